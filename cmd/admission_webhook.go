@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/admission"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	admissionListen      string
+	admissionTLSCertFile string
+	admissionTLSKeyFile  string
+	admissionFailOn      string
+	admissionKEVCatalog  string
+	admissionCacheTTL    time.Duration
+	admissionDenyOnError bool
+)
+
+var admissionWebhookCmd = &cobra.Command{
+	Use:   "admission-webhook",
+	Short: "run a Kubernetes validating admission webhook that denies pods with vulnerable images",
+	Long: `Runs a Kubernetes validating admission webhook, which scans the images of every reviewed pod and denies pods with
+images that have a vulnerability at or above the --fail-on severity or a known exploited vulnerability (see --kev-catalog).
+Pods are reviewed at /validate, and /healthz and /readyz are the health endpoints.`,
+	Args: cobra.ExactArgs(0),
+	RunE: runAdmissionWebhookCmd,
+}
+
+func init() {
+	flags := admissionWebhookCmd.Flags()
+	flags.StringVar(&admissionListen, "listen", ":8443", "the address to listen on")
+	flags.StringVar(&admissionTLSCertFile, "tls-cert-file", "", "the TLS certificate to serve with (the API server requires TLS)")
+	flags.StringVar(&admissionTLSKeyFile, "tls-key-file", "", "the private key of the TLS certificate")
+	flags.StringVarP(&admissionFailOn, "fail-on", "f", "", fmt.Sprintf("deny pods with an image that has a vulnerability with a severity >= the given severity, options=%v", vulnerability.AllSeverities))
+	flags.StringVar(&admissionKEVCatalog, "kev-catalog", "", "deny pods with an image that has a vulnerability within the given known exploited vulnerabilities catalog (the CISA JSON format)")
+	flags.DurationVar(&admissionCacheTTL, "cache-ttl", time.Hour, "how long the scan results of an image that is referenced by digest are reused (0 disables caching)")
+	flags.BoolVar(&admissionDenyOnError, "deny-on-error", false, "deny pods with an image that cannot be scanned (by default these are allowed with a warning)")
+
+	rootCmd.AddCommand(admissionWebhookCmd)
+}
+
+func admissionPolicy() (admission.Policy, error) {
+	var policy admission.Policy
+	if admissionFailOn != "" {
+		severity := vulnerability.ParseSeverity(admissionFailOn)
+		if severity == vulnerability.UnknownSeverity {
+			return policy, fmt.Errorf("bad --fail-on severity value '%s'", admissionFailOn)
+		}
+		policy.FailOnSeverity = &severity
+	}
+
+	if admissionKEVCatalog != "" {
+		f, err := os.Open(admissionKEVCatalog)
+		if err != nil {
+			return policy, fmt.Errorf("unable to open known exploited vulnerabilities catalog: %w", err)
+		}
+		defer f.Close()
+		if policy.KEV, err = admission.ReadKEV(f); err != nil {
+			return policy, err
+		}
+	}
+
+	if policy.FailOnSeverity == nil && len(policy.KEV) == 0 {
+		return policy, fmt.Errorf("no policy given (see --fail-on and --kev-catalog)")
+	}
+	return policy, nil
+}
+
+func runAdmissionWebhookCmd(_ *cobra.Command, _ []string) error {
+	if (admissionTLSCertFile == "") != (admissionTLSKeyFile == "") {
+		return fmt.Errorf("both --tls-cert-file and --tls-key-file are required to serve with TLS")
+	}
+	policy, err := admissionPolicy()
+	if err != nil {
+		return err
+	}
+
+	log.Debug("loading DB")
+	provider, metadataProvider, dbStatus, err := grype.LoadVulnerabilityDB(appConfig.DB.ToCuratorConfig(), appConfig.DB.AutoUpdate)
+	if err = validateDBLoad(err, dbStatus); err != nil {
+		return err
+	}
+	if err = validateDBAge(dbStatus, appConfig.DB.MaxAllowedAgeDuration, appConfig.DB.StalePolicy, time.Now()); err != nil {
+		return err
+	}
+
+	webhook := admission.NewWebhook(imageScanner(provider, metadataProvider), admission.Config{
+		Policy:      policy,
+		CacheTTL:    admissionCacheTTL,
+		DenyOnError: admissionDenyOnError,
+	})
+	httpServer := &http.Server{
+		Addr:    admissionListen,
+		Handler: webhook.Handler(),
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		if admissionTLSCertFile == "" {
+			log.Warnf("serving without TLS (the API server requires TLS, e.g. from a proxy in front of the webhook)")
+			log.Infof("listening on %s", admissionListen)
+			errs <- httpServer.ListenAndServe()
+			return
+		}
+		log.Infof("listening on %s (TLS)", admissionListen)
+		errs <- httpServer.ListenAndServeTLS(admissionTLSCertFile, admissionTLSKeyFile)
+	}()
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("unable to serve: %w", err)
+	case <-setupSignals():
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+// imageScanner returns a function that scans the given image (which is always pulled from the registry) for matches,
+// which can be called many times with the vulnerability database loaded once.
+func imageScanner(provider vulnerability.Provider, metadataProvider vulnerability.MetadataProvider) func(pkg.ImageReference) ([]models.Match, error) {
+	ignoreRules := append([]match.IgnoreRule{}, appConfig.Ignore...)
+	if appConfig.OnlyFixed {
+		ignoreRules = append(ignoreRules, ignoreNonFixedMatches...)
+	}
+	providerConfig := newProviderConfig()
+
+	return func(image pkg.ImageReference) ([]models.Match, error) {
+		packages, context, err := pkg.Provide(image.Input(), providerConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to catalog: %w", err)
+		}
+		allMatches := grype.FindVulnerabilitiesForPackageWithConfig(provider, context.Distro, appConfig.Matcher, packages...)
+		remainingMatches, _ := match.ApplyIgnoreRules(allMatches, ignoreRules)
+
+		var matches []models.Match
+		err = models.EnumerateMatches(packages, remainingMatches, metadataProvider, func(m models.Match) error {
+			matches = append(matches, m)
+			return nil
+		})
+		return matches, err
+	}
+}
@@ -1,15 +1,20 @@
 package cmd
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 
 	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/grype/internal/config"
 	"github.com/anchore/grype/internal/log"
 	"github.com/anchore/grype/internal/logger"
+	"github.com/anchore/grype/internal/proxy"
+	"github.com/anchore/grype/internal/tracing"
 	"github.com/anchore/grype/internal/version"
 	"github.com/anchore/stereoscope"
 	"github.com/anchore/syft/syft"
@@ -23,6 +28,8 @@ var (
 	appConfig         *config.Application
 	eventBus          *partybus.Bus
 	eventSubscription *partybus.Subscription
+	// tracingShutdown exports the remaining spans before exiting
+	tracingShutdown func(context.Context) error
 )
 
 func init() {
@@ -30,6 +37,9 @@ func init() {
 		initRootCmdConfigOptions,
 		initAppConfig,
 		initLogging,
+		initProxy,
+		initTracing,
+		initRegistryAccess,
 		logAppConfig,
 		logAppVersion,
 		initEventBus,
@@ -37,7 +47,10 @@ func init() {
 }
 
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	err := rootCmd.Execute()
+	closeMatcherPlugins()
+	shutdownTracing()
+	if err != nil {
 		_ = stderrPrintLnf(err.Error())
 		os.Exit(1)
 	}
@@ -81,6 +94,15 @@ func initLogging() {
 }
 
 func logAppConfig() {
+	for _, w := range appConfig.ImportWarnings {
+		log.Warnf("ignore rules import: %s", w)
+	}
+
+	if appConfig.Log.Format == config.JSONLogFormat {
+		// note: color codes are noise in the messages of JSON log entries
+		log.Debugf("application config:\n%+v", appConfig.String())
+		return
+	}
 	log.Debugf("application config:\n%+v", color.Magenta.Sprint(appConfig.String()))
 }
 
@@ -114,6 +136,50 @@ func logAppVersion() {
 	}
 }
 
+func initProxy() {
+	if err := proxy.Configure(appConfig.Proxy.ToConfig()); err != nil {
+		fmt.Printf("failed to configure the proxy: \n\t%+v\n", err)
+		os.Exit(1)
+	}
+}
+
+func initTracing() {
+	shutdown, err := tracing.Setup(appConfig.Tracing.ToConfig())
+	if err != nil {
+		fmt.Printf("failed to configure tracing: \n\t%+v\n", err)
+		os.Exit(1)
+	}
+	tracingShutdown = shutdown
+}
+
+// closeMatcherPlugins stops the matcher plugins that were started by scans.
+func closeMatcherPlugins() {
+	if appConfig == nil {
+		return
+	}
+	for _, m := range appConfig.Matcher.Plugins {
+		if closer, ok := m.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				log.Warnf("unable to stop matcher plugin: %+v", err)
+			}
+		}
+	}
+}
+
+// shutdownTracing exports the remaining spans (if tracing is configured).
+func shutdownTracing() {
+	if tracingShutdown == nil {
+		return
+	}
+	if err := tracingShutdown(context.Background()); err != nil {
+		log.Warnf("unable to export traces: %+v", err)
+	}
+}
+
+func initRegistryAccess() {
+	pkg.ConfigureRegistryAccess(appConfig.Registry.ToAccessConfig())
+}
+
 func initEventBus() {
 	eventBus = partybus.NewBus()
 	eventSubscription = eventBus.Subscribe()
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/anchore/grype/grype/db"
+	"github.com/anchore/grype/grype/db/v3/private"
+	"github.com/spf13/cobra"
+)
+
+var dbAddCmd = &cobra.Command{
+	Use:   "add FILE...",
+	Short: "add private vulnerabilities to the vulnerability database",
+	Long: `add private vulnerabilities (e.g. internal advisories for first-party libraries) described in a YAML or JSON FILE
+to the current vulnerability database, where they are matched alongside public vulnerabilities. Added
+vulnerabilities are not retained when the database is updated, so they must be added again after each update.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDBAddCmd,
+}
+
+func init() {
+	dbCmd.AddCommand(dbAddCmd)
+}
+
+func runDBAddCmd(_ *cobra.Command, args []string) error {
+	dbCurator, err := db.NewCurator(appConfig.DB.ToCuratorConfig())
+	if err != nil {
+		return err
+	}
+
+	var added int
+	for _, path := range args {
+		count, err := addPrivateVulnerabilities(&dbCurator, path)
+		if err != nil {
+			return err
+		}
+		added += count
+	}
+
+	return stderrPrintLnf("Added %d vulnerabilities", added)
+}
+
+func addPrivateVulnerabilities(dbCurator *db.Curator, path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("unable to open private vulnerabilities (%s): %w", path, err)
+	}
+	defer f.Close()
+
+	vulnerabilities, metadata, err := private.Transform(f)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read private vulnerabilities (%s): %w", path, err)
+	}
+
+	added, err := dbCurator.AddRecords(vulnerabilities, metadata)
+	if err != nil {
+		return 0, fmt.Errorf("unable to add private vulnerabilities (%s): %+v", path, err)
+	}
+	return added, nil
+}
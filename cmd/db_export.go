@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/db"
+	"github.com/spf13/cobra"
+	"github.com/wagoodman/go-progress"
+)
+
+var dbExportCmd = &cobra.Command{
+	Use:   "export FILE",
+	Short: "export the latest vulnerability database as a bundle for offline hosts",
+	Long:  "download the latest vulnerability database and write it to a bundle FILE (the database archive, its signature and a listing snapshot), which can be imported on hosts without network access with \"db import FILE\".",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runDBExportCmd,
+}
+
+func init() {
+	dbCmd.AddCommand(dbExportCmd)
+}
+
+func runDBExportCmd(_ *cobra.Command, args []string) error {
+	dbCurator, err := db.NewCurator(appConfig.DB.ToCuratorConfig())
+	if err != nil {
+		return err
+	}
+
+	entry, err := dbCurator.ExportBundle(args[0], &progress.Manual{})
+	if err != nil {
+		return fmt.Errorf("unable to export vulnerability database: %+v", err)
+	}
+
+	return stderrPrintLnf("Vulnerability database exported (version=%d built=%q)", entry.Version, entry.Built.String())
+}
@@ -6,13 +6,14 @@ import (
 	"github.com/anchore/grype/internal"
 
 	"github.com/anchore/grype/grype/db"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
 
 var dbImportCmd = &cobra.Command{
 	Use:   "import FILE",
-	Short: "import a vulnerability database archive",
-	Long:  fmt.Sprintf("import a vulnerability database archive from a local FILE.\nDB archives can be obtained from %q.", internal.DBUpdateURL),
+	Short: "import a vulnerability database archive or bundle",
+	Long:  fmt.Sprintf("import a vulnerability database archive or a bundle (created by \"db export\") from a local FILE.\nDB archives can be obtained from %q.", internal.DBUpdateURL),
 	Args:  cobra.ExactArgs(1),
 	RunE:  runDBImportCmd,
 }
@@ -27,6 +28,18 @@ func runDBImportCmd(_ *cobra.Command, args []string) error {
 		return err
 	}
 
+	isBundle, err := db.IsBundle(afero.NewOsFs(), args[0])
+	if err != nil {
+		return fmt.Errorf("unable to import vulnerability database: %+v", err)
+	}
+	if isBundle {
+		entry, err := dbCurator.ImportBundle(args[0])
+		if err != nil {
+			return fmt.Errorf("unable to import vulnerability database bundle: %+v", err)
+		}
+		return stderrPrintLnf("Vulnerability database imported (version=%d built=%q)", entry.Version, entry.Built.String())
+	}
+
 	if err := dbCurator.ImportFrom(args[0]); err != nil {
 		return fmt.Errorf("unable to import vulnerability database: %+v", err)
 	}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/anchore/grype/grype/db"
+	"github.com/anchore/grype/grype/db/v3/csaf"
+	"github.com/spf13/cobra"
+)
+
+var dbImportCSAFNamespace string
+
+var dbImportCSAFCmd = &cobra.Command{
+	Use:   "import-csaf FILE...",
+	Short: "add the vulnerabilities from CSAF advisories to the vulnerability database",
+	Long: `add the vulnerabilities from CSAF 2.0 advisory or VEX documents (e.g. supplied by a distro vendor) to the current
+vulnerability database, within the given namespace (e.g. "rhel:8"). Packages that are stated to not be affected by a
+vulnerability are removed from the namespace. Only packages identified by package URL are considered, so each
+document is expected to describe the packages of the distro release that the namespace refers to. Imported
+vulnerabilities are not retained when the database is updated, so the import must be repeated after each update.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runDBImportCSAFCmd,
+}
+
+func init() {
+	dbImportCSAFCmd.Flags().StringVarP(&dbImportCSAFNamespace, "namespace", "n", "", "the namespace to add the vulnerabilities to (e.g. \"rhel:8\")")
+	if err := dbImportCSAFCmd.MarkFlagRequired("namespace"); err != nil {
+		panic(err)
+	}
+
+	dbCmd.AddCommand(dbImportCSAFCmd)
+}
+
+func runDBImportCSAFCmd(_ *cobra.Command, args []string) error {
+	dbCurator, err := db.NewCurator(appConfig.DB.ToCuratorConfig())
+	if err != nil {
+		return err
+	}
+
+	var records csaf.Records
+	for _, path := range args {
+		documentRecords, err := readCSAFRecords(path)
+		if err != nil {
+			return err
+		}
+		records.Vulnerabilities = append(records.Vulnerabilities, documentRecords.Vulnerabilities...)
+		records.Metadata = append(records.Metadata, documentRecords.Metadata...)
+		records.NotAffected = append(records.NotAffected, documentRecords.NotAffected...)
+	}
+
+	removed, err := dbCurator.RemoveRecords(records.NotAffected)
+	if err != nil {
+		return fmt.Errorf("unable to import CSAF documents: %+v", err)
+	}
+
+	added, err := dbCurator.AddRecords(records.Vulnerabilities, records.Metadata)
+	if err != nil {
+		return fmt.Errorf("unable to import CSAF documents: %+v", err)
+	}
+
+	return stderrPrintLnf("Imported %d vulnerabilities into namespace %q (removed %d not affected)", added, dbImportCSAFNamespace, removed)
+}
+
+func readCSAFRecords(path string) (*csaf.Records, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open CSAF document (%s): %w", path, err)
+	}
+	defer f.Close()
+
+	records, err := csaf.Transform(f, dbImportCSAFNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CSAF document (%s): %w", path, err)
+	}
+	return records, nil
+}
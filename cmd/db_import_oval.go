@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/anchore/grype/grype/db"
+	"github.com/anchore/grype/grype/db/v3/oval"
+	"github.com/spf13/cobra"
+)
+
+var dbImportOVALNamespace string
+
+var dbImportOVALCmd = &cobra.Command{
+	Use:   "import-oval FILE",
+	Short: "add the vulnerabilities from an OVAL definitions file to the vulnerability database",
+	Long: `add the vulnerabilities from an OVAL definitions FILE (e.g. supplied by a distro vendor) to the current
+vulnerability database, within the given namespace (e.g. "mydistro:1"). Imported vulnerabilities are not retained
+when the database is updated, so the import must be repeated after each update.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDBImportOVALCmd,
+}
+
+func init() {
+	dbImportOVALCmd.Flags().StringVarP(&dbImportOVALNamespace, "namespace", "n", "", "the namespace to add the vulnerabilities to (e.g. \"mydistro:1\")")
+	if err := dbImportOVALCmd.MarkFlagRequired("namespace"); err != nil {
+		panic(err)
+	}
+
+	dbCmd.AddCommand(dbImportOVALCmd)
+}
+
+func runDBImportOVALCmd(_ *cobra.Command, args []string) error {
+	dbCurator, err := db.NewCurator(appConfig.DB.ToCuratorConfig())
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("unable to open OVAL file (%s): %w", args[0], err)
+	}
+	defer f.Close()
+
+	vulnerabilities, metadata, err := oval.Transform(f, dbImportOVALNamespace)
+	if err != nil {
+		return err
+	}
+
+	added, err := dbCurator.AddRecords(vulnerabilities, metadata)
+	if err != nil {
+		return fmt.Errorf("unable to import OVAL definitions: %+v", err)
+	}
+
+	return stderrPrintLnf("Imported %d vulnerabilities into namespace %q", added, dbImportOVALNamespace)
+}
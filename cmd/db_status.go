@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/anchore/grype/grype/db"
 
 	"github.com/spf13/cobra"
 )
 
+var verifyDB bool
+
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "display database status",
@@ -16,6 +19,8 @@ var statusCmd = &cobra.Command{
 }
 
 func init() {
+	statusCmd.Flags().BoolVar(&verifyDB, "verify", false, "verify the integrity of the database file against the checksum within the database metadata")
+
 	dbCmd.AddCommand(statusCmd)
 }
 
@@ -26,16 +31,28 @@ func runDBStatusCmd(_ *cobra.Command, _ []string) error {
 	}
 
 	status := dbCurator.Status()
+	if status.Err == nil && verifyDB {
+		status.Err = dbCurator.Verify()
+	}
 
 	statusStr := "valid"
-	if status.Err != nil {
+	switch {
+	case status.Err != nil:
 		statusStr = "invalid"
+	case status.IsStale(appConfig.DB.MaxAllowedAgeDuration, time.Now()):
+		statusStr = fmt.Sprintf("valid (older than the max allowed age of %s)", appConfig.DB.MaxAllowedAge)
 	}
 
 	fmt.Println("Location: ", status.Location)
 	fmt.Println("Built:    ", status.Built.String())
 	fmt.Println("Schema:   ", status.SchemaVersion)
 	fmt.Println("Checksum: ", status.Checksum)
+	if len(status.Providers) > 0 {
+		fmt.Println("Providers:")
+		for _, p := range status.Providers {
+			fmt.Printf("  %-20s built %s\n", p.Name, p.Built.String())
+		}
+	}
 	fmt.Println("Status:   ", statusStr)
 
 	return status.Err
@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/diff"
+	"github.com/anchore/grype/grype/grypeerr"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffCompareTo    string
+	diffOutputFormat string
+	diffFailOnNew    bool
+	diffFailOn       string
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff OLD-REPORT [NEW-REPORT]",
+	Short: "report vulnerabilities newly introduced, fixed, and persisting between two scans",
+	Long: `Compares two JSON reports (see "-o json") and reports the vulnerabilities that were newly introduced, fixed, and
+persisting. Instead of a new report, an image can be scanned with --compare-to.`,
+	Example: `  grype diff old-report.json new-report.json
+  grype diff old-report.json --compare-to alpine:latest
+  grype diff old-report.json new-report.json --fail-on-new --fail-on high`,
+	Args: validateDiffArgs,
+	RunE: runDiffCmd,
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffCompareTo, "compare-to", "", "scan the given image (instead of reading a new report) to compare against the old report")
+	diffCmd.Flags().StringVarP(&diffOutputFormat, "output", "o", "text", "format to display results (available=[text, json])")
+	diffCmd.Flags().BoolVar(&diffFailOnNew, "fail-on-new", false, "set the return code to 1 if there are newly introduced vulnerabilities")
+	diffCmd.Flags().StringVarP(&diffFailOn, "fail-on", "f", "", fmt.Sprintf("only consider newly introduced vulnerabilities with a severity >= the given severity for --fail-on-new, options=%v", vulnerability.AllSeverities))
+
+	rootCmd.AddCommand(diffCmd)
+}
+
+func validateDiffArgs(cmd *cobra.Command, args []string) error {
+	if err := cobra.RangeArgs(1, 2)(cmd, args); err != nil {
+		return err
+	}
+	if len(args) == 2 && diffCompareTo != "" {
+		return fmt.Errorf("cannot compare to both a new report and an image (--compare-to)")
+	}
+	if len(args) == 1 && diffCompareTo == "" {
+		return fmt.Errorf("a new report or an image to compare to (--compare-to) is required")
+	}
+	if diffFailOn != "" && vulnerability.ParseSeverity(diffFailOn) == vulnerability.UnknownSeverity {
+		return fmt.Errorf("bad --fail-on severity value '%s'", diffFailOn)
+	}
+	return nil
+}
+
+func runDiffCmd(_ *cobra.Command, args []string) error {
+	oldMatches, err := readReportFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	var newMatches []models.Match
+	if diffCompareTo != "" {
+		newMatches, err = scanMatches(diffCompareTo)
+	} else {
+		newMatches, err = readReportFile(args[1])
+	}
+	if err != nil {
+		return err
+	}
+
+	d := diff.Compare(oldMatches, newMatches)
+
+	switch diffOutputFormat {
+	case "text":
+		if err := writeDiffTable(os.Stdout, d); err != nil {
+			return err
+		}
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", " ")
+		if err := enc.Encode(&d); err != nil {
+			return fmt.Errorf("failed to write diff: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %s", diffOutputFormat)
+	}
+
+	return diffFailure(d, diffFailOnNew, diffFailOn)
+}
+
+// diffFailure returns an expected error when there are newly introduced vulnerabilities (at or above the given
+// severity, when given) and failing on new vulnerabilities is requested.
+func diffFailure(d diff.Diff, failOnNew bool, failOn string) error {
+	if !failOnNew {
+		return nil
+	}
+	threshold := vulnerability.UnknownSeverity
+	if failOn != "" {
+		threshold = vulnerability.ParseSeverity(failOn)
+	}
+	if len(d.NewAtOrAbove(threshold)) > 0 {
+		return grypeerr.ErrNewVulnerabilities
+	}
+	return nil
+}
+
+func readReportFile(path string) ([]models.Match, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open report: %w", err)
+	}
+	defer f.Close()
+
+	matches, err := diff.ReadReport(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return matches, nil
+}
+
+// scanMatches scans the given input as the root command does (without the UI), returning the remaining matches as
+// they are reported in a JSON report.
+func scanMatches(userInput string) ([]models.Match, error) {
+	log.Debug("loading DB")
+	provider, metadataProvider, dbStatus, err := grype.LoadVulnerabilityDB(appConfig.DB.ToCuratorConfig(), appConfig.DB.AutoUpdate)
+	if err = validateDBLoad(err, dbStatus); err != nil {
+		return nil, err
+	}
+	if err = validateDBAge(dbStatus, appConfig.DB.MaxAllowedAgeDuration, appConfig.DB.StalePolicy, time.Now()); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("gathering packages")
+	packages, context, err := pkg.Provide(userInput, newProviderConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to catalog: %w", err)
+	}
+
+	ignoreRules := append([]match.IgnoreRule{}, appConfig.Ignore...)
+	if appConfig.OnlyFixed {
+		ignoreRules = append(ignoreRules, ignoreNonFixedMatches...)
+	}
+
+	allMatches := grype.FindVulnerabilitiesForPackageWithConfig(provider, context.Distro, appConfig.Matcher, packages...)
+	remainingMatches, ignoredMatches := match.ApplyIgnoreRules(allMatches, ignoreRules)
+
+	doc, err := models.NewDocument(packages, context, remainingMatches, ignoredMatches, metadataProvider, nil, dbStatus)
+	if err != nil {
+		return nil, err
+	}
+	return doc.Matches, nil
+}
+
+func writeDiffTable(output io.Writer, d diff.Diff) error {
+	var rows [][]string
+	for _, section := range []struct {
+		status   string
+		findings []diff.Finding
+	}{
+		{status: "new", findings: d.New},
+		{status: "fixed", findings: d.Fixed},
+		{status: "persisting", findings: d.Persisting},
+	} {
+		for _, f := range section.findings {
+			rows = append(rows, []string{section.status, f.Package, f.Version, strings.Join(f.FixedIn, ", "), f.Vulnerability, f.Severity})
+		}
+	}
+
+	if len(rows) == 0 {
+		_, err := io.WriteString(output, "No vulnerabilities found in either report\n")
+		return err
+	}
+
+	table := tablewriter.NewWriter(output)
+
+	table.SetHeader([]string{"Status", "Name", "Installed", "Fixed-In", "Vulnerability", "Severity"})
+	table.SetAutoWrapText(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetAutoFormatHeaders(true)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetTablePadding("  ")
+	table.SetNoWhiteSpace(true)
+
+	table.AppendBulk(rows)
+	table.Render()
+
+	_, err := fmt.Fprintf(output, "\n%d new, %d fixed, %d persisting\n", len(d.New), len(d.Fixed), len(d.Persisting))
+	return err
+}
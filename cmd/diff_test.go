@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/anchore/grype/grype/diff"
+	"github.com/anchore/grype/grype/grypeerr"
+)
+
+func TestDiffFailure(t *testing.T) {
+	d := diff.Diff{
+		New: []diff.Finding{{Vulnerability: "CVE-1", Severity: "Medium", Package: "musl"}},
+	}
+
+	tests := []struct {
+		name      string
+		diff      diff.Diff
+		failOnNew bool
+		failOn    string
+		expected  error
+	}{
+		{name: "no fail on new", diff: d, failOnNew: false, expected: nil},
+		{name: "fail on new", diff: d, failOnNew: true, expected: grypeerr.ErrNewVulnerabilities},
+		{name: "no new findings", diff: diff.Diff{}, failOnNew: true, expected: nil},
+		{name: "new finding at severity", diff: d, failOnNew: true, failOn: "medium", expected: grypeerr.ErrNewVulnerabilities},
+		{name: "new finding below severity", diff: d, failOnNew: true, failOn: "high", expected: nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := diffFailure(test.diff, test.failOnNew, test.failOn)
+			if actual != test.expected {
+				t.Errorf("diffFailure: expected %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/internal/explore"
+	"github.com/spf13/cobra"
+)
+
+// defaultIgnoreConfig is the config file that ignore rules are written to when no config file was used.
+const defaultIgnoreConfig = ".grype.yaml"
+
+// exploreResults is the --tui option that explores the scan result in the terminal instead of writing a report.
+var exploreResults bool
+
+var exploreCmd = &cobra.Command{
+	Use:   "explore REPORT",
+	Short: "explore the matches of a JSON report interactively in the terminal",
+	Long: `Explores the matches of a JSON report (see "-o json") interactively in the terminal: filter the matches by severity,
+fix state, and package, view the details and references of a match, and mark matches to ignore, which are written as
+ignore rules to the config file (or to .grype.yaml when no config file is used). To explore the result of a scan
+directly, use "--tui" when scanning.`,
+	Example: `  grype explore report.json
+  grype explore report.json -c ci/grype.yaml
+  grype alpine:latest --tui`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExploreCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(exploreCmd)
+}
+
+func runExploreCmd(_ *cobra.Command, args []string) error {
+	if !explore.IsTerminal(os.Stdin, os.Stdout) {
+		return fmt.Errorf("the result explorer requires an interactive terminal")
+	}
+
+	matches, err := readReportFile(args[0])
+	if err != nil {
+		return err
+	}
+	return explore.Run(explore.New(matches, writeIgnoreRules), os.Stdin, os.Stdout)
+}
+
+// writeIgnoreRules adds the ignore rules marked in the explorer to the config file that was used (or the default
+// config file of the working directory when no config file was used).
+func writeIgnoreRules(rules []match.IgnoreRule) error {
+	path := appConfig.ConfigPath
+	if path == "" {
+		path = defaultIgnoreConfig
+	}
+	return explore.AppendIgnoreRules(path, rules)
+}
+
+// explorePresenter is the presenter of --tui, which explores the scan result in the terminal (instead of writing a
+// report to the output).
+type explorePresenter struct {
+	matches []models.Match
+}
+
+func (p explorePresenter) Present(io.Writer) error {
+	return explore.Run(explore.New(p.matches, writeIgnoreRules), os.Stdin, os.Stdout)
+}
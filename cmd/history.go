@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/anchore/grype/grype/db"
+	"github.com/anchore/grype/grype/history"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/source"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var historyOutputFormat string
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "scan history operations (see the history.enabled configuration)",
+}
+
+var historyListCmd = &cobra.Command{
+	Use:   "list [TARGET]",
+	Short: "list the recorded scans (of the given target) and how their vulnerabilities changed over time",
+	Args:  cobra.MaximumNArgs(1),
+	RunE:  runHistoryListCmd,
+}
+
+var historyShowCmd = &cobra.Command{
+	Use:   "show ID",
+	Short: "show a recorded scan compared to the previous scan of the same target",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runHistoryShowCmd,
+}
+
+func init() {
+	for _, c := range []*cobra.Command{historyListCmd, historyShowCmd} {
+		c.Flags().StringVarP(&historyOutputFormat, "output", "o", "text", "format to display results (available=[text, json])")
+		historyCmd.AddCommand(c)
+	}
+
+	rootCmd.AddCommand(historyCmd)
+}
+
+func runHistoryListCmd(_ *cobra.Command, args []string) error {
+	var target string
+	if len(args) > 0 {
+		target = args[0]
+	}
+
+	store, err := history.Open(appConfig.History.Path)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	entries, err := store.List(target)
+	if err != nil {
+		return err
+	}
+	trends := history.Trends(entries)
+
+	switch historyOutputFormat {
+	case "text":
+		if len(entries) == 0 {
+			return stderrPrintLnf("No scans recorded in the history (%s)", appConfig.History.Path)
+		}
+		writeHistoryTable(os.Stdout, entries)
+		for _, trend := range trends {
+			fmt.Println()
+			writeHistoryTrend(os.Stdout, trend)
+		}
+	case "json":
+		return encodeHistoryJSON(struct {
+			Scans  []history.Entry `json:"scans"`
+			Trends []history.Trend `json:"trends"`
+		}{Scans: entries, Trends: trends})
+	default:
+		return fmt.Errorf("unsupported output format: %s", historyOutputFormat)
+	}
+	return nil
+}
+
+func runHistoryShowCmd(_ *cobra.Command, args []string) error {
+	id, err := strconv.ParseUint(args[0], 10, 0)
+	if err != nil {
+		return fmt.Errorf("bad scan ID %q: %w", args[0], err)
+	}
+
+	store, err := history.Open(appConfig.History.Path)
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+
+	entry, err := store.Get(uint(id))
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return fmt.Errorf("no scan with ID %d in the history", id)
+	}
+
+	entries, err := store.List(entry.Target)
+	if err != nil {
+		return err
+	}
+	previous := history.Previous(entries, *entry)
+
+	switch historyOutputFormat {
+	case "text":
+		fmt.Printf("ID:        %d\n", entry.ID)
+		fmt.Printf("Time:      %s\n", entry.Time.Format(time.RFC3339))
+		fmt.Printf("Target:    %s\n", entry.Target)
+		fmt.Printf("Digest:    %s\n", entry.Digest)
+		fmt.Printf("DB Built:  %s (schema %d)\n\n", entry.DBBuilt.Format(time.RFC3339), entry.DBSchemaVersion)
+		if previous == nil {
+			writeHistoryTrend(os.Stdout, history.Trend{Target: entry.Target, Scans: 1, First: *entry, Latest: *entry})
+		} else {
+			writeHistoryTrend(os.Stdout, history.Trends([]history.Entry{*previous, *entry})[0])
+		}
+	case "json":
+		return encodeHistoryJSON(struct {
+			Scan     history.Entry  `json:"scan"`
+			Previous *history.Entry `json:"previous,omitempty"`
+		}{Scan: *entry, Previous: previous})
+	default:
+		return fmt.Errorf("unsupported output format: %s", historyOutputFormat)
+	}
+	return nil
+}
+
+func encodeHistoryJSON(value interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", " ")
+	if err := enc.Encode(value); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+func writeHistoryTable(output io.Writer, entries []history.Entry) {
+	table := tablewriter.NewWriter(output)
+
+	table.SetHeader([]string{"ID", "Time", "Target", "Digest", "Critical", "High", "Medium", "Low", "Negligible", "Unknown"})
+	table.SetAutoWrapText(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetAutoFormatHeaders(true)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetTablePadding("  ")
+	table.SetNoWhiteSpace(true)
+
+	for _, e := range entries {
+		digest := e.Digest
+		if len(digest) > 19 {
+			// e.g. "sha256:" and the first 12 characters of the hash
+			digest = digest[:19]
+		}
+		c := e.Counts
+		table.Append([]string{
+			strconv.FormatUint(uint64(e.ID), 10), e.Time.Format(time.RFC3339), e.Target, digest,
+			strconv.Itoa(c.Critical), strconv.Itoa(c.High), strconv.Itoa(c.Medium), strconv.Itoa(c.Low), strconv.Itoa(c.Negligible), strconv.Itoa(c.Unknown),
+		})
+	}
+	table.Render()
+}
+
+// writeHistoryTrend writes a summary of how the vulnerabilities of a target changed from its first to its latest scan.
+func writeHistoryTrend(output io.Writer, trend history.Trend) {
+	first, latest := trend.First.Counts, trend.Latest.Counts
+	fmt.Fprintf(output, "Trend of %s (%d scans, %s to %s):\n", trend.Target, trend.Scans,
+		trend.First.Time.Format("2006-01-02"), trend.Latest.Time.Format("2006-01-02"))
+	for _, row := range []struct {
+		name          string
+		first, latest int
+	}{
+		{"critical", first.Critical, latest.Critical},
+		{"high", first.High, latest.High},
+		{"medium", first.Medium, latest.Medium},
+		{"low", first.Low, latest.Low},
+		{"negligible", first.Negligible, latest.Negligible},
+		{"unknown", first.Unknown, latest.Unknown},
+		{"total", first.Total(), latest.Total()},
+	} {
+		fmt.Fprintf(output, "  %-10s  %5d -> %-5d  (%+d)\n", row.name, row.first, row.latest, row.latest-row.first)
+	}
+
+	switch change := trend.Change.Total(); {
+	case change < 0:
+		fmt.Fprintln(output, "  improving")
+	case change > 0:
+		fmt.Fprintln(output, "  worsening")
+	default:
+		fmt.Fprintln(output, "  unchanged")
+	}
+}
+
+// recordHistory stores a summary of the scan into the history. Failing to do so does not fail the scan.
+func recordHistory(userInput string, context pkg.Context, matches match.Matches, metadataProvider vulnerability.MetadataProvider, dbStatus *db.Status) {
+	entry := history.Entry{
+		Time:   time.Now(),
+		Target: historyTarget(userInput, context.Source),
+		Digest: historyDigest(context.Source),
+	}
+	if dbStatus != nil {
+		entry.DBBuilt = dbStatus.Built
+		entry.DBSchemaVersion = dbStatus.SchemaVersion
+	}
+	for m := range matches.Enumerate() {
+		var severity vulnerability.Severity
+		if metadata, err := metadataProvider.GetMetadata(m.Vulnerability.ID, m.Vulnerability.Namespace); err == nil && metadata != nil {
+			severity = vulnerability.ParseSeverity(metadata.Severity)
+		}
+		entry.Counts.Add(severity)
+	}
+
+	store, err := history.Open(appConfig.History.Path)
+	if err != nil {
+		log.Warnf("unable to record scan history: %+v", err)
+		return
+	}
+	defer store.Close()
+
+	if entry, err = store.Add(entry); err != nil {
+		log.Warnf("unable to record scan history: %+v", err)
+		return
+	}
+	log.Debugf("recorded scan history entry=%d", entry.ID)
+}
+
+// historyTarget returns the scanned target, which is not given as user input when an SBOM is piped in.
+func historyTarget(userInput string, src *source.Metadata) string {
+	switch {
+	case userInput != "":
+		return userInput
+	case src == nil:
+		return ""
+	case src.Scheme == source.ImageScheme:
+		return src.ImageMetadata.UserInput
+	default:
+		return src.Path
+	}
+}
+
+// historyDigest returns the manifest digest of the scanned image (or its ID when the manifest digest is unknown).
+func historyDigest(src *source.Metadata) string {
+	if src == nil || src.Scheme != source.ImageScheme {
+		return ""
+	}
+	if src.ImageMetadata.ManifestDigest != "" {
+		return src.ImageMetadata.ManifestDigest
+	}
+	return src.ImageMetadata.ID
+}
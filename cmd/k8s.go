@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/grypeerr"
+	"github.com/anchore/grype/grype/k8s"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	k8sKubeconfig    string
+	k8sContext       string
+	k8sNamespace     string
+	k8sAllNamespaces bool
+	k8sOutputFormat  string
+	k8sFailOn        string
+)
+
+var k8sCmd = &cobra.Command{
+	Use:   "k8s",
+	Short: "scan the images of the running pods of a Kubernetes cluster, reporting vulnerabilities by workload",
+	Long: `Scans the images of the running pods of a Kubernetes cluster, where each distinct image (by digest) is scanned once.
+The cluster is found with the given kubeconfig (by default $KUBECONFIG or ~/.kube/config), or else with the service
+account of the pod grype is running within. The report is aggregated by workload (e.g. Deployment, StatefulSet, or
+CronJob), or by pod for pods that are not controlled by a workload.`,
+	Args: cobra.ExactArgs(0),
+	RunE: runK8sCmd,
+}
+
+func init() {
+	flags := k8sCmd.Flags()
+	flags.StringVar(&k8sKubeconfig, "kubeconfig", "", "the kubeconfig of the cluster (default $KUBECONFIG or ~/.kube/config)")
+	flags.StringVar(&k8sContext, "context", "", "the kubeconfig context of the cluster (default the current context)")
+	flags.StringVarP(&k8sNamespace, "namespace", "n", "", "the namespace to scan (default the namespace of the context)")
+	flags.BoolVarP(&k8sAllNamespaces, "all-namespaces", "A", false, "scan all namespaces")
+	flags.StringVarP(&k8sOutputFormat, "output", "o", "text", "format to display results (available=[text, json])")
+	flags.StringVarP(&k8sFailOn, "fail-on", "f", "", fmt.Sprintf("set the return code to 1 if a vulnerability is found with a severity >= the given severity, options=%v", vulnerability.AllSeverities))
+
+	rootCmd.AddCommand(k8sCmd)
+}
+
+func runK8sCmd(_ *cobra.Command, _ []string) error {
+	var failOn *vulnerability.Severity
+	if k8sFailOn != "" {
+		severity := vulnerability.ParseSeverity(k8sFailOn)
+		if severity == vulnerability.UnknownSeverity {
+			return fmt.Errorf("bad --fail-on severity value '%s'", k8sFailOn)
+		}
+		failOn = &severity
+	}
+	if k8sOutputFormat != "text" && k8sOutputFormat != "json" {
+		return fmt.Errorf("unsupported output format: %s", k8sOutputFormat)
+	}
+	if k8sAllNamespaces && k8sNamespace != "" {
+		return fmt.Errorf("--namespace and --all-namespaces cannot be given together")
+	}
+
+	config, err := k8s.LoadConfig(k8sKubeconfig, k8sContext)
+	if err != nil {
+		return err
+	}
+	client, err := k8s.NewClient(config)
+	if err != nil {
+		return err
+	}
+
+	namespace := k8sNamespace
+	if namespace == "" && !k8sAllNamespaces {
+		namespace = config.Namespace
+	}
+	images, err := client.Images(namespace)
+	if err != nil {
+		return err
+	}
+	log.Infof("found %d distinct images within the cluster", len(images))
+
+	log.Debug("loading DB")
+	provider, metadataProvider, dbStatus, err := grype.LoadVulnerabilityDB(appConfig.DB.ToCuratorConfig(), appConfig.DB.AutoUpdate)
+	if err = validateDBLoad(err, dbStatus); err != nil {
+		return err
+	}
+	if err = validateDBAge(dbStatus, appConfig.DB.MaxAllowedAgeDuration, appConfig.DB.StalePolicy, time.Now()); err != nil {
+		return err
+	}
+
+	scan := imageScanner(provider, metadataProvider)
+	results := make([]k8s.ImageResult, 0, len(images))
+	for i, image := range images {
+		log.Infof("scanning image %d/%d: %s", i+1, len(images), image.Reference)
+		result := k8s.ImageResult{Image: image}
+		ref, err := pkg.RegistryImageReference(image.Reference)
+		if err == nil {
+			result.Matches, err = scan(ref)
+		}
+		if err != nil {
+			log.Warnf("unable to scan image=%q: %+v", image.Reference, err)
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	report := k8s.NewReport(results)
+
+	switch k8sOutputFormat {
+	case "text":
+		writeK8sReport(os.Stdout, report)
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", " ")
+		if err := enc.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+	}
+
+	if failOn != nil && report.HasSeverity(*failOn) {
+		return grypeerr.ErrAboveSeverityThreshold
+	}
+	return nil
+}
+
+func writeK8sReport(output io.Writer, report k8s.Report) {
+	if len(report.Workloads) == 0 {
+		fmt.Fprintln(output, "No running pods found")
+		return
+	}
+
+	var rows [][]string
+	for _, w := range report.Workloads {
+		images := strconv.Itoa(len(w.Images))
+		if w.Failed > 0 {
+			images += fmt.Sprintf(" (%d failed)", w.Failed)
+		}
+		rows = append(rows, []string{
+			w.Namespace,
+			w.Kind,
+			w.Name,
+			images,
+			strconv.Itoa(w.Counts.Critical),
+			strconv.Itoa(w.Counts.High),
+			strconv.Itoa(w.Counts.Medium),
+			strconv.Itoa(w.Counts.Low),
+			strconv.Itoa(w.Counts.Negligible),
+			strconv.Itoa(w.Counts.Unknown),
+		})
+	}
+	writeK8sTable(output, []string{"Namespace", "Kind", "Name", "Images", "Critical", "High", "Medium", "Low", "Negligible", "Unknown"}, rows)
+
+	rows = nil
+	for _, image := range report.Images {
+		vulnerabilities := strconv.Itoa(len(image.Matches))
+		if image.Error != "" {
+			vulnerabilities = "scan failed: " + image.Error
+		}
+		var workloads []string
+		for _, w := range image.Workloads {
+			workloads = append(workloads, w.String())
+		}
+		rows = append(rows, []string{image.Image.Image, image.Digest, strings.Join(workloads, ", "), vulnerabilities})
+	}
+	fmt.Fprintln(output)
+	writeK8sTable(output, []string{"Image", "Digest", "Workloads", "Vulnerabilities"}, rows)
+
+	fmt.Fprintf(output, "\n%d vulnerabilities within %d images of %d workloads\n", report.Counts.Total(), len(report.Images), len(report.Workloads))
+}
+
+func writeK8sTable(output io.Writer, header []string, rows [][]string) {
+	table := tablewriter.NewWriter(output)
+
+	table.SetHeader(header)
+	table.SetAutoWrapText(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	table.SetHeaderLine(false)
+	table.SetBorder(false)
+	table.SetAutoFormatHeaders(true)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetTablePadding("  ")
+	table.SetNoWhiteSpace(true)
+
+	table.AppendBulk(rows)
+	table.Render()
+}
@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/event"
+	"github.com/anchore/grype/grype/grypeerr"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/policy"
+	"github.com/anchore/grype/grype/presenter"
+	"github.com/anchore/grype/grype/presenter/multi"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/bus"
+	"github.com/anchore/grype/internal/config"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/grype/internal/tracing"
+	"github.com/wagoodman/go-partybus"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// target is an input to scan, where the reference is what is scanned (e.g. the image of a single platform of the input).
+type target struct {
+	input     string
+	reference string
+	// platform is the selected platform of a multi-platform image (if any)
+	platform string
+}
+
+func (t target) String() string {
+	if t.platform == "" {
+		return t.input
+	}
+	return fmt.Sprintf("%s (%s)", t.input, t.platform)
+}
+
+// resolveTargets returns the targets of the given inputs, where each image is resolved to the image of the selected
+// platform (--platform), or to the images of all of its platforms (--all-platforms).
+func resolveTargets(inputs []string) ([]target, error) {
+	var targets []target
+	for _, input := range inputs {
+		if appConfig.Platform == "" && !appConfig.AllPlatforms {
+			targets = append(targets, target{input: input, reference: input})
+			continue
+		}
+
+		images, err := pkg.ImagePlatforms(input, appConfig.Registry.ToOptions())
+		if err != nil {
+			return nil, fmt.Errorf("unable to determine the platforms of %q: %w", input, err)
+		}
+		if appConfig.Platform != "" {
+			selected, err := pkg.SelectPlatform(images, appConfig.Platform)
+			if err != nil {
+				return nil, fmt.Errorf("unable to select the platform of %q: %w", input, err)
+			}
+			images = []pkg.PlatformImage{selected}
+		}
+		for _, img := range images {
+			log.Infof("scanning platform=%s of %q (%s)", img.Platform, input, img.Input)
+			targets = append(targets, target{input: input, reference: img.Input, platform: img.Platform})
+		}
+	}
+	return targets, nil
+}
+
+// readTargetsFile reads the targets of a --targets-file, with one target per line (where empty lines and lines
+// starting with "#" are skipped).
+func readTargetsFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open targets file: %w", err)
+	}
+	defer f.Close()
+
+	var targets []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		targets = append(targets, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("unable to read targets file: %w", err)
+	}
+	return targets, nil
+}
+
+// startMultiTargetWorker scans several targets with the vulnerability database loaded once, where up to the configured
+// parallelism targets are scanned concurrently, and reports the results of all targets together.
+func startMultiTargetWorker(targets []target, failOnSeverity *vulnerability.Severity) <-chan error {
+	errs := make(chan error)
+	go func() {
+		defer close(errs)
+
+		presenterConfig, err := presenter.ValidatedConfig(appConfig.Output, appConfig.OutputTemplateFile)
+		if err != nil {
+			errs <- err
+			return
+		}
+		presenterConfig = presenterConfig.WithTable(appConfig.Table.Options)
+		if err := presenter.ValidateMultiTarget(presenterConfig); err != nil {
+			errs <- err
+			return
+		}
+		if appConfig.ResultAttestation.Push {
+			errs <- fmt.Errorf("the attestation of the scan result can only be pushed when scanning a single target")
+			return
+		}
+		if appConfig.Publishes(config.DependencyTrackSink) && appConfig.DependencyTrack.HasProject() {
+			errs <- fmt.Errorf("the scan results of several targets can only be published to Dependency-Track projects named after the images (without a configured project)")
+			return
+		}
+		publishers, err := resultPublishers()
+		if err != nil {
+			errs <- err
+			return
+		}
+
+		checkForAppUpdate()
+
+		spanCtx, span := startScanSpan("scan", attribute.Int("grype.targets", len(targets)))
+		defer span.End()
+
+		provider, metadataProvider, dbStatus, err := loadScanDB(spanCtx)
+		if err != nil {
+			tracing.Fail(span, err)
+			errs <- err
+			return
+		}
+
+		if appConfig.OnlyFixed {
+			appConfig.Ignore = append(appConfig.Ignore, ignoreNonFixedMatches...)
+		}
+
+		results := make([]multi.Target, len(targets))
+		limit := make(chan struct{}, appConfig.Parallelism)
+		wg := &sync.WaitGroup{}
+		for i, t := range targets {
+			wg.Add(1)
+			go func(i int, t target) {
+				defer wg.Done()
+				limit <- struct{}{}
+				defer func() { <-limit }()
+
+				results[i] = scanTarget(spanCtx, t, provider, metadataProvider)
+				if results[i].Err != nil {
+					log.Errorf("failed to scan target=%q: %+v", t, results[i].Err)
+				}
+			}(i, t)
+		}
+		wg.Wait()
+
+		var failed int
+		var hitThreshold, hasFixable, reachedEOL bool
+		var denials []string
+		for i, result := range results {
+			if result.Err != nil {
+				failed++
+				continue
+			}
+			// note: the database cannot be accessed concurrently with the presenter, so the severities are checked first
+			hitThreshold = hitThreshold || hitSeverityThreshold(failOnSeverity, result.Matches, metadataProvider)
+			hasFixable = hasFixable || hasFixableVulnerability(result.Matches)
+			reachedEOL = reachedEOL || distroEOLReached(result.Context)
+			if appConfig.Policy.PolicyOpt != nil {
+				decision, err := evaluatePolicy(appConfig.Policy.PolicyOpt, result.String(), result.Matches, result.IgnoredMatches, result.Packages, result.Context, metadataProvider, dbStatus)
+				if err != nil {
+					errs <- err
+				} else {
+					results[i].Context.Policy = decision
+					for _, denial := range decision.Denials {
+						denials = append(denials, fmt.Sprintf("%s: %s", result, denial))
+					}
+				}
+			}
+			if appConfig.History.Enabled {
+				recordHistory(result.String(), result.Context, result.Matches, metadataProvider, dbStatus)
+			}
+			notifyScanFinished(result.String(), result.Matches, result.IgnoredMatches, result.Packages, results[i].Context, metadataProvider, dbStatus)
+			if err := publishResult(publishers, result.String(), result.Matches, result.IgnoredMatches, result.Packages, results[i].Context, metadataProvider, dbStatus); err != nil {
+				errs <- err
+			}
+		}
+
+		// note: the report is published before any error, so the results of the other targets are still reported
+		bus.Publish(partybus.Event{
+			Type:  event.VulnerabilityScanningFinished,
+			Value: newTracedPresenter(spanCtx, presenter.GetMultiTargetPresenter(presenterConfig, results, metadataProvider, appConfig, dbStatus)),
+		})
+
+		if failed > 0 {
+			errs <- fmt.Errorf("failed to scan %d of %d targets", failed, len(targets))
+		}
+		if reachedEOL && appConfig.FailOnEOL {
+			errs <- grypeerr.ErrDistroEOL
+		}
+		if hitThreshold {
+			errs <- grypeerr.ErrAboveSeverityThreshold
+		}
+		if hasFixable && appConfig.FailOnFixable {
+			errs <- grypeerr.ErrFixableVulnerabilities
+		}
+		if len(denials) > 0 {
+			errs <- policyDenied(&policy.Decision{Denials: denials})
+		}
+	}()
+	return errs
+}
+
+// scanTarget catalogs and matches a single target of a multi-target scan.
+func scanTarget(spanCtx context.Context, t target, provider vulnerability.Provider, metadataProvider vulnerability.MetadataProvider) (result multi.Target) {
+	spanCtx, span := tracing.Start(spanCtx, "scan target", attribute.String("grype.target", t.String()))
+	defer func() { tracing.End(span, result.Err) }()
+
+	result = multi.Target{Input: t.input, Platform: t.platform}
+
+	log.Debugf("gathering packages of target=%q", t)
+	providerConfig := newProviderConfig()
+	packages, context, err := pkg.ProvideContext(spanCtx, t.reference, providerConfig)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to catalog: %w", err)
+		return result
+	}
+
+	var baseImageRules []match.IgnoreRule
+	if appConfig.ExcludeBaseImage != "" {
+		baseImageRules, err = baseImageIgnoreRules(appConfig.ExcludeBaseImage, context.Source, providerConfig)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+	}
+
+	context.DistroEOL = distroEOL(provider, context.Distro)
+
+	allMatches := grype.FindVulnerabilitiesForPackageContext(spanCtx, provider, context.Distro, appConfig.Matcher, packages...)
+	ignoreRules := append(append([]match.IgnoreRule{}, appConfig.Ignore...), baseImageRules...)
+	remainingMatches, ignoredMatches := match.ApplyIgnoreRules(allMatches, ignoreRules)
+	if count := len(ignoredMatches); count > 0 {
+		log.Infof("ignoring %d matches of target=%q due to user-provided ignore rules", count, t)
+	}
+
+	remainingMatches, err = match.FilterByDate(remainingMatches, metadataProvider, appConfig.DateFilter)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	result.Matches = remainingMatches
+	result.IgnoredMatches = ignoredMatches
+	result.Packages = packages
+	result.Context = context
+	return result
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadTargetsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "targets.txt")
+	contents := "# production images\nalpine:3.14\n\n  registry:ghcr.io/org/app:1.0  \ndir:./src\n"
+	if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatalf("unable to write targets file: %+v", err)
+	}
+
+	targets, err := readTargetsFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %+v", err)
+	}
+
+	expected := []string{"alpine:3.14", "registry:ghcr.io/org/app:1.0", "dir:./src"}
+	if !reflect.DeepEqual(targets, expected) {
+		t.Errorf("unexpected targets: %v (expected %v)", targets, expected)
+	}
+
+	if _, err := readTargetsFile(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Errorf("expected an error for a missing targets file")
+	}
+}
+
+func TestTargetString(t *testing.T) {
+	tests := []struct {
+		target   target
+		expected string
+	}{
+		{target: target{input: "alpine:3.14", reference: "alpine:3.14"}, expected: "alpine:3.14"},
+		{
+			target:   target{input: "python:3.10", reference: "registry:index.docker.io/library/python@sha256:abc", platform: "linux/arm64/v8"},
+			expected: "python:3.10 (linux/arm64/v8)",
+		},
+	}
+
+	for _, test := range tests {
+		if actual := test.target.String(); actual != test.expected {
+			t.Errorf("unexpected target string: %q (expected %q)", actual, test.expected)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/anchore/grype/grype/db"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/metrics"
+	"github.com/anchore/grype/grype/notify"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/grype/watch"
+	"github.com/anchore/grype/internal/log"
+)
+
+// notifyScanFinished notifies the configured webhooks of the scan result of the target (if there are webhooks).
+func notifyScanFinished(target string, matches match.Matches, ignoredMatches []match.IgnoredMatch, packages []pkg.Package, context pkg.Context, metadataProvider vulnerability.MetadataProvider, dbStatus *db.Status) {
+	if len(appConfig.Webhooks) == 0 {
+		return
+	}
+	target = historyTarget(target, context.Source)
+	doc, err := models.NewDocument(packages, context, matches, ignoredMatches, metadataProvider, appConfig, dbStatus)
+	if err != nil {
+		log.Warnf("unable to notify webhooks of the scan of target=%q: %+v", target, err)
+		return
+	}
+	sendNotification(notify.NewScanFinished(target, doc))
+}
+
+// notifyNewVulnerabilities notifies the configured webhooks of the newly appearing matches of watched targets (if
+// there are webhooks and new matches).
+func notifyNewVulnerabilities(events []watch.Event) {
+	if len(appConfig.Webhooks) == 0 || len(events) == 0 {
+		return
+	}
+	sendNotification(notify.NewNewVulnerabilities(events))
+}
+
+// sendNotification posts the notification to the configured webhooks, where failing to notify a webhook does not fail
+// the scan.
+func sendNotification(notification notify.Notification) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	if err := notify.NewNotifier(client, appConfig.Webhooks.ToWebhooks()).Notify(notification); err != nil {
+		log.Warnf("unable to notify webhooks of event=%q: %+v", notification.Event, err)
+		metrics.Errors.With("notify").Inc()
+		return
+	}
+	log.Debugf("notified webhooks of event=%q", notification.Event)
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/anchore/grype/grype/db"
+	"github.com/anchore/grype/grype/dependencytrack"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/publish"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/config"
+	"github.com/anchore/grype/internal/log"
+)
+
+// resultPublishers returns the publishers of the sinks given by --publish.
+func resultPublishers() ([]publish.Publisher, error) {
+	var publishers []publish.Publisher
+	for _, sink := range appConfig.Publish {
+		switch sink {
+		case config.DependencyTrackSink:
+			client := dependencytrack.NewClient(appConfig.DependencyTrack.URL, appConfig.DependencyTrack.APIKey, &http.Client{Timeout: time.Minute})
+			publishers = append(publishers, dependencytrack.NewPublisher(client, appConfig.DependencyTrack.ToProject()))
+		case config.FileSink:
+			publishers = append(publishers, publish.NewFilePublisher(appConfig.PublishFile.Path))
+		case config.S3Sink:
+			publisher, err := publish.NewS3Publisher(appConfig.PublishS3.ToConfig())
+			if err != nil {
+				return nil, err
+			}
+			publishers = append(publishers, publisher)
+		case config.HTTPSink:
+			client := &http.Client{Timeout: time.Minute}
+			publishers = append(publishers, publish.NewHTTPPublisher(client, appConfig.PublishHTTP.URL, appConfig.PublishHTTP.Headers))
+		}
+	}
+	return publishers, nil
+}
+
+// publishResult publishes the scan result of the target with the given publishers.
+func publishResult(publishers []publish.Publisher, target string, matches match.Matches, ignoredMatches []match.IgnoredMatch, packages []pkg.Package, context pkg.Context, metadataProvider vulnerability.MetadataProvider, dbStatus *db.Status) error {
+	if len(publishers) == 0 {
+		return nil
+	}
+	target = historyTarget(target, context.Source)
+
+	result, err := publish.NewResult(target, matches, ignoredMatches, packages, context, metadataProvider, appConfig, dbStatus)
+	if err != nil {
+		return fmt.Errorf("unable to describe the scan result of target=%q for publishing: %w", target, err)
+	}
+	if err := publish.PublishAll(publishers, result); err != nil {
+		return fmt.Errorf("unable to publish the scan result of target=%q: %w", target, err)
+	}
+	log.Infof("published the scan result of target=%q to %v", target, appConfig.Publish)
+	return nil
+}
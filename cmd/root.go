@@ -1,38 +1,60 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/anchore/grype/grype"
 	"github.com/anchore/grype/grype/db"
+	"github.com/anchore/grype/grype/db/ghsa"
+	"github.com/anchore/grype/grype/distro"
 	"github.com/anchore/grype/grype/event"
 	"github.com/anchore/grype/grype/grypeerr"
 	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
 	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/policy"
 	"github.com/anchore/grype/grype/presenter"
+	"github.com/anchore/grype/grype/presenter/attestation"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/presenter/table"
 	"github.com/anchore/grype/grype/vulnerability"
 	"github.com/anchore/grype/internal"
 	"github.com/anchore/grype/internal/bus"
 	"github.com/anchore/grype/internal/config"
+	"github.com/anchore/grype/internal/explore"
 	"github.com/anchore/grype/internal/format"
 	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/grype/internal/tracing"
 	"github.com/anchore/grype/internal/ui"
 	"github.com/anchore/grype/internal/version"
 	"github.com/anchore/stereoscope"
+	"github.com/anchore/syft/syft/linux"
 	"github.com/anchore/syft/syft/source"
 	"github.com/pkg/profile"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 	"github.com/wagoodman/go-partybus"
+	"go.opentelemetry.io/otel/attribute"
 
 	grypeDb "github.com/anchore/grype/grype/db/v3"
 )
 
 var persistentOpts = config.CliOnlyOptions{}
 
+// targetsFile is the --targets-file with targets to scan in addition to the arguments.
+var targetsFile string
+
+// autoBaseImage is the --exclude-base-image value that excludes the base image annotated on the scanned image.
+const autoBaseImage = "auto"
+
 var ignoreNonFixedMatches = []match.IgnoreRule{
 	{FixState: string(grypeDb.NotFixedState)},
 	{FixState: string(grypeDb.WontFixState)},
@@ -41,7 +63,7 @@ var ignoreNonFixedMatches = []match.IgnoreRule{
 
 var (
 	rootCmd = &cobra.Command{
-		Use:   fmt.Sprintf("%s [IMAGE]", internal.ApplicationName),
+		Use:   fmt.Sprintf("%s [IMAGE...]", internal.ApplicationName),
 		Short: "A vulnerability scanner for container images, filesystems, and SBOMs",
 		Long: format.Tprintf(`A vulnerability scanner for container images, filesystems, and SBOMs.
 
@@ -61,6 +83,9 @@ You can also explicitly specify the scheme to use:
 You can also pipe in Syft JSON directly:
 	syft yourimage:tag -o json | {{.appName}}
 
+Several targets can be scanned at once (given as arguments or with --targets-file), with a combined report:
+    {{.appName}} yourrepo/yourimage:tag dir:path/to/yourproject
+
 `, map[string]interface{}{
 			"appName": internal.ApplicationName,
 		}),
@@ -88,6 +113,7 @@ func init() {
 func setGlobalCliOptions() {
 	// setup global CLI options (available on all CLI commands)
 	rootCmd.PersistentFlags().StringVarP(&persistentOpts.ConfigPath, "config", "c", "", "application config file")
+	rootCmd.PersistentFlags().StringVarP(&persistentOpts.Profile, "profile", "", "", "the profile of the config to apply (one of the profiles of the config, e.g. ci)")
 
 	flag := "quiet"
 	rootCmd.PersistentFlags().BoolP(
@@ -99,7 +125,86 @@ func setGlobalCliOptions() {
 		os.Exit(1)
 	}
 
+	flag = "require-signed-db"
+	rootCmd.PersistentFlags().BoolP(
+		flag, "", false,
+		"only use vulnerability database archives with a valid signature (requires db.signing-key)",
+	)
+	if err := viper.BindPFlag("db.require-signed", rootCmd.PersistentFlags().Lookup(flag)); err != nil {
+		fmt.Printf("unable to bind flag '%s': %+v", flag, err)
+		os.Exit(1)
+	}
+
+	flag = "log-format"
+	rootCmd.PersistentFlags().StringP(
+		flag, "", "",
+		fmt.Sprintf("the format of log entries, options=[%s %s]", config.TextLogFormat, config.JSONLogFormat),
+	)
+	if err := viper.BindPFlag("log.format", rootCmd.PersistentFlags().Lookup(flag)); err != nil {
+		fmt.Printf("unable to bind flag '%s': %+v", flag, err)
+		os.Exit(1)
+	}
+
+	flag = "otlp-endpoint"
+	rootCmd.PersistentFlags().StringP(
+		flag, "", "",
+		"export traces of scans to an OpenTelemetry collector at the given OTLP/HTTP URL (e.g. http://localhost:4318/v1/traces)",
+	)
+	if err := viper.BindPFlag("tracing.endpoint", rootCmd.PersistentFlags().Lookup(flag)); err != nil {
+		fmt.Printf("unable to bind flag '%s': %+v", flag, err)
+		os.Exit(1)
+	}
+
 	rootCmd.PersistentFlags().CountVarP(&persistentOpts.Verbosity, "verbose", "v", "increase verbosity (-v = info, -vv = debug)")
+
+	setRegistryCliOptions(rootCmd.PersistentFlags())
+}
+
+func setRegistryCliOptions(flags *pflag.FlagSet) {
+	flags.StringVarP(&persistentOpts.RegistryAuth.Authority, "registry-auth-authority", "", "", "the registry of the registry credentials (e.g. \"ghcr.io\"), where the credentials are used for every registry when not given")
+	flags.StringVarP(&persistentOpts.RegistryAuth.Username, "registry-auth-username", "", "", "the username of the registry credentials")
+	flags.StringVarP(&persistentOpts.RegistryAuth.Password, "registry-auth-password", "", "", "the password of the registry credentials (prefer the GRYPE_REGISTRY_AUTH_PASSWORD env var)")
+	flags.StringVarP(&persistentOpts.RegistryAuth.Token, "registry-auth-token", "", "", "the bearer token of the registry credentials (prefer the GRYPE_REGISTRY_AUTH_TOKEN env var)")
+
+	flag := "insecure-skip-tls-verify"
+	flags.BoolP(
+		flag, "", false,
+		"skip TLS verification when communicating with the registry",
+	)
+	if err := viper.BindPFlag("registry.insecure-skip-tls-verify", flags.Lookup(flag)); err != nil {
+		fmt.Printf("unable to bind flag '%s': %+v", flag, err)
+		os.Exit(1)
+	}
+
+	flag = "insecure-use-http"
+	flags.BoolP(
+		flag, "", false,
+		"use http instead of https when connecting to the registry",
+	)
+	if err := viper.BindPFlag("registry.insecure-use-http", flags.Lookup(flag)); err != nil {
+		fmt.Printf("unable to bind flag '%s': %+v", flag, err)
+		os.Exit(1)
+	}
+
+	flag = "registry-ca-cert"
+	flags.StringP(
+		flag, "", "",
+		"the PEM encoded certificate authorities of registries (in addition to the system roots)",
+	)
+	if err := viper.BindPFlag("registry.ca-cert", flags.Lookup(flag)); err != nil {
+		fmt.Printf("unable to bind flag '%s': %+v", flag, err)
+		os.Exit(1)
+	}
+
+	flag = "registry-cloud-credentials"
+	flags.BoolP(
+		flag, "", false,
+		"use the credentials of the cloud environment for AWS ECR, Google (GCR, Artifact Registry), and Azure ACR registries",
+	)
+	if err := viper.BindPFlag("registry.cloud-credentials", flags.Lookup(flag)); err != nil {
+		fmt.Printf("unable to bind flag '%s': %+v", flag, err)
+		os.Exit(1)
+	}
 }
 
 func setRootFlags(flags *pflag.FlagSet) {
@@ -121,6 +226,21 @@ func setRootFlags(flags *pflag.FlagSet) {
 	flags.StringP("template", "t", "", "specify the path to a Go template file ("+
 		"requires 'template' output to be selected)")
 
+	flags.StringP(
+		"sort-by", "", string(table.SortByPackage),
+		fmt.Sprintf("the order of the rows of the table output (then by package and vulnerability), options=%v", table.SortOptions),
+	)
+
+	flags.StringSliceP(
+		"columns", "", nil,
+		fmt.Sprintf("comma-separated columns of the table output, in order (default is name, installed, fixed-in, vulnerability, severity, and layer for images), options=%v", table.AvailableColumns),
+	)
+
+	flags.BoolP(
+		"show-refs", "", false,
+		"add the related vulnerabilities (aliases) and the reference URLs of each vulnerability to the table output",
+	)
+
 	flags.StringP(
 		"fail-on", "f", "",
 		fmt.Sprintf("set the return code to 1 if a vulnerability is found with a severity >= the given severity, options=%v", vulnerability.AllSeverities),
@@ -131,10 +251,165 @@ func setRootFlags(flags *pflag.FlagSet) {
 		"ignore matches for vulnerabilities that are not fixed",
 	)
 
+	flags.StringP(
+		"unknown-version-policy", "", string(matcher.DefaultConfig().UnknownVersionPolicy),
+		fmt.Sprintf("how to handle packages with versions that cannot be parsed, options=%v", matcher.AllUnknownVersionPolicies),
+	)
+
+	flags.StringP(
+		"cpe-strictness", "", string(matcher.DefaultConfig().CPEStrictness),
+		fmt.Sprintf("how strictly packages are matched against the CPEs of vulnerabilities (by the target software and vendor of the CPEs), options=%v", matcher.AllCPEStrictnesses),
+	)
+
 	flags.StringArrayP(
 		"exclude", "", nil,
 		"exclude paths from being scanned using a glob expression",
 	)
+
+	flags.StringArrayP(
+		"import-ignores", "", nil,
+		fmt.Sprintf("import ignore rules from the suppression file of another scanner, optionally prefixed by the format (e.g. 'trivy:ignores.txt'), formats=%v", config.IgnoreImportFormats),
+	)
+
+	flags.StringArrayP(
+		"exclude-package", "", nil,
+		"exclude packages from matching by name using a glob expression, optionally prefixed by the package type (e.g. 'npm:*-test-utils')",
+	)
+
+	flags.BoolP(
+		"exclude-dev-dependencies", "", false,
+		"exclude packages that are only needed to develop or test a project (e.g. npm dev dependencies) from matching",
+	)
+
+	flags.BoolP(
+		"fail-on-eol", "", false,
+		"set the return code to 1 if the distro has reached the end of life",
+	)
+
+	flags.BoolP(
+		"fail-on-fixable", "", false,
+		"set the return code to 1 if any vulnerability has a fix (see the suggested fix of each package)",
+	)
+
+	flags.StringP(
+		"published-after", "", "",
+		"only report vulnerabilities published after the given date (e.g. 2024-01-01) or RFC3339 timestamp",
+	)
+
+	flags.StringP(
+		"modified-after", "", "",
+		"only report vulnerabilities last modified after the given date (e.g. 2024-01-01) or RFC3339 timestamp",
+	)
+
+	flags.StringP(
+		"policy", "", "",
+		"a Rego policy (package grype, with deny and warn rules over the JSON document of the scan result) that sets the return code to 1 when it denies the scan result (requires the opa executable)",
+	)
+
+	flags.StringP(
+		"distro", "", "",
+		"distro to match against in the format: <distro>:<version> (overrides the detected distro)",
+	)
+
+	flags.StringP(
+		"exclude-base-image", "", "",
+		fmt.Sprintf("ignore matches of packages inherited from the given base image (or %q to use the base image annotated on the image)", autoBaseImage),
+	)
+
+	flags.BoolP(
+		"deduplicate-packages", "", false,
+		"merge packages with the same package URL and metadata that were found at several locations (instead of matching each location on its own)",
+	)
+
+	flags.BoolP(
+		"deduplicate-vulnerabilities", "", false,
+		"merge the matches of a package that refer to the same vulnerability by different IDs (e.g. a GHSA and its CVE) into one match with related vulnerabilities",
+	)
+
+	flags.BoolP(
+		"cache-sbom", "", false,
+		"reuse the packages cataloged for an image digest that was scanned before (skipping cataloging)",
+	)
+
+	flags.BoolP(
+		"unpack-archives", "", false,
+		"catalog the contents of the archives (e.g. tarballs, zips, and wheels) within directory and file targets, recursively",
+	)
+
+	flags.BoolP(
+		"sbom-attestation", "", false,
+		"scan the signed SBOM attestation of an image from the registry (when there is one) instead of cataloging the image",
+	)
+
+	flags.StringP(
+		"key", "", "",
+		"the public key (PEM) that signs SBOM attestations",
+	)
+
+	flags.StringP(
+		"certificate-identity", "", "",
+		"the identity (email or URI) of the certificate that signs SBOM attestations (for keyless signing)",
+	)
+
+	flags.StringP(
+		"certificate-oidc-issuer", "", "",
+		"the OIDC issuer of the identity of the certificate that signs SBOM attestations (for keyless signing)",
+	)
+
+	flags.BoolP(
+		"insecure-skip-attestation-verify", "", false,
+		"scan the SBOM attestations of att: targets without verifying their signatures",
+	)
+
+	flags.StringP(
+		"attestation-signing-key", "", "",
+		"the private key (PEM, or an encrypted cosign key with the password from COSIGN_PASSWORD) that signs the attestation of the scan result (for '-o attestation' and --push-attestation)",
+	)
+
+	flags.BoolP(
+		"push-attestation", "", false,
+		"push the signed attestation of the scan result to the registry of the scanned image (requires --attestation-signing-key)",
+	)
+
+	flags.StringArrayP(
+		"publish", "", nil,
+		fmt.Sprintf("publish the scan result to the given sink (can be given several times; see the config of each sink), options=%v", config.PublishSinks),
+	)
+
+	flags.StringArrayVarP(
+		&persistentOpts.Webhooks, "notify-webhook", "", nil,
+		"post a JSON summary of the scan result to the given URL when the scan finishes (can be given several times; see the webhooks config for headers, signing, and full reports)",
+	)
+
+	flags.StringVarP(
+		&targetsFile, "targets-file", "", "",
+		"file with the targets to scan (one per line), in addition to the targets given as arguments",
+	)
+
+	flags.BoolVarP(
+		&exploreResults, "tui", "", false,
+		"explore the scan result interactively in the terminal (instead of writing a report), where ignored matches are written to the config",
+	)
+
+	flags.IntP(
+		"parallelism", "", 2,
+		"the number of targets that are scanned concurrently (when scanning several targets)",
+	)
+
+	flags.StringP(
+		"platform", "", "",
+		"the platform of a multi-platform image to scan, in the format: <os>/<arch>[/<variant>] (e.g. linux/arm64)",
+	)
+
+	flags.BoolP(
+		"all-platforms", "", false,
+		"scan every platform of a multi-platform image (reporting the results of each platform)",
+	)
+
+	flags.StringP(
+		"progress", "", config.AutoProgress,
+		fmt.Sprintf("how to show the progress of the scan on stderr, options=[%s %s] (json emits newline-delimited progress events)", config.AutoProgress, config.JSONProgress),
+	)
 }
 
 func bindRootConfigOptions(flags *pflag.FlagSet) error {
@@ -154,6 +429,18 @@ func bindRootConfigOptions(flags *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := viper.BindPFlag("table.sort-by", flags.Lookup("sort-by")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("table.columns", flags.Lookup("columns")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("table.show-refs", flags.Lookup("show-refs")); err != nil {
+		return err
+	}
+
 	if err := viper.BindPFlag("fail-on-severity", flags.Lookup("fail-on")); err != nil {
 		return err
 	}
@@ -162,18 +449,147 @@ func bindRootConfigOptions(flags *pflag.FlagSet) error {
 		return err
 	}
 
+	if err := viper.BindPFlag("unknown-version-policy", flags.Lookup("unknown-version-policy")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("cpe-strictness", flags.Lookup("cpe-strictness")); err != nil {
+		return err
+	}
+
 	if err := viper.BindPFlag("exclude", flags.Lookup("exclude")); err != nil {
 		return err
 	}
 
+	if err := viper.BindPFlag("exclude-package", flags.Lookup("exclude-package")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("import-ignores", flags.Lookup("import-ignores")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("exclude-dev-dependencies", flags.Lookup("exclude-dev-dependencies")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("fail-on-eol", flags.Lookup("fail-on-eol")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("fail-on-fixable", flags.Lookup("fail-on-fixable")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("published-after", flags.Lookup("published-after")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("modified-after", flags.Lookup("modified-after")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("publish", flags.Lookup("publish")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("policy.file", flags.Lookup("policy")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("distro", flags.Lookup("distro")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("deduplicate-packages", flags.Lookup("deduplicate-packages")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("deduplicate-vulnerabilities", flags.Lookup("deduplicate-vulnerabilities")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("exclude-base-image", flags.Lookup("exclude-base-image")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("sbom-cache.enabled", flags.Lookup("cache-sbom")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("archives.enabled", flags.Lookup("unpack-archives")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("attestations.enabled", flags.Lookup("sbom-attestation")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("attestations.key", flags.Lookup("key")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("attestations.certificate-identity", flags.Lookup("certificate-identity")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("attestations.certificate-oidc-issuer", flags.Lookup("certificate-oidc-issuer")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("attestations.insecure-skip-verify", flags.Lookup("insecure-skip-attestation-verify")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("result-attestation.signing-key", flags.Lookup("attestation-signing-key")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("result-attestation.push", flags.Lookup("push-attestation")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("parallelism", flags.Lookup("parallelism")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("platform", flags.Lookup("platform")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("all-platforms", flags.Lookup("all-platforms")); err != nil {
+		return err
+	}
+
+	if err := viper.BindPFlag("progress", flags.Lookup("progress")); err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func rootExec(_ *cobra.Command, args []string) error {
-	// we may not be provided an image if the user is piping in SBOM input
-	var userInput string
-	if len(args) > 0 {
-		userInput = args[0]
+	inputs := args
+	if targetsFile != "" {
+		fileTargets, err := readTargetsFile(targetsFile)
+		if err != nil {
+			return err
+		}
+		inputs = append(inputs, fileTargets...)
+	}
+
+	targets, err := resolveTargets(inputs)
+	if err != nil {
+		return err
+	}
+
+	if exploreResults {
+		if len(targets) > 1 {
+			return fmt.Errorf("--tui explores the result of a single target (not %d targets)", len(targets))
+		}
+		if !explore.IsTerminal(os.Stdin, os.Stdout) {
+			return fmt.Errorf("--tui requires an interactive terminal")
+		}
 	}
 
 	reporter, closer, err := reportWriter()
@@ -187,15 +603,35 @@ func rootExec(_ *cobra.Command, args []string) error {
 		return err
 	}
 
+	var worker <-chan error
+	switch len(targets) {
+	case 0:
+		// we may not be provided an image if the user is piping in SBOM input
+		worker = startWorker(target{}, appConfig.FailOnSeverity)
+	case 1:
+		worker = startWorker(targets[0], appConfig.FailOnSeverity)
+	default:
+		worker = startMultiTargetWorker(targets, appConfig.FailOnSeverity)
+	}
+
 	return eventLoop(
-		startWorker(userInput, appConfig.FailOnSeverity),
+		worker,
 		setupSignals(),
 		eventSubscription,
 		stereoscope.Cleanup,
-		ui.Select(isVerbose(), appConfig.Quiet, reporter)...,
+		selectUI(reporter)...,
 	)
 }
 
+// selectUI returns the UIs of the scan, where JSON progress events (which are requested explicitly) replace the TTY
+// UI and the logs of progress.
+func selectUI(reporter io.Writer) []ui.UI {
+	if appConfig.Progress == config.JSONProgress {
+		return []ui.UI{ui.NewJSONProgressUI(os.Stderr, reporter)}
+	}
+	return ui.Select(isVerbose(), appConfig.Quiet, reporter)
+}
+
 func isVerbose() (result bool) {
 	isPipedInput, err := internal.IsPipedInput()
 	if err != nil {
@@ -208,39 +644,43 @@ func isVerbose() (result bool) {
 }
 
 // nolint:funlen
-func startWorker(userInput string, failOnSeverity *vulnerability.Severity) <-chan error {
+func startWorker(t target, failOnSeverity *vulnerability.Severity) <-chan error {
 	errs := make(chan error)
 	go func() {
 		defer close(errs)
 
+		spanCtx, span := startScanSpan("scan", attribute.String("grype.target", t.String()))
+		defer span.End()
+
 		presenterConfig, err := presenter.ValidatedConfig(appConfig.Output, appConfig.OutputTemplateFile)
 		if err != nil {
 			errs <- err
 			return
 		}
+		if presenterConfig.AttestsResult() && appConfig.ResultAttestation.SigningKeyOpt == nil {
+			errs <- fmt.Errorf("the %q output format requires an attestation signing key", appConfig.Output)
+			return
+		}
+		attestationOptions := attestation.Options{
+			Signer:        appConfig.ResultAttestation.SigningKeyOpt,
+			ScanStartedOn: time.Now(),
+		}
+		presenterConfig = presenterConfig.WithAttestation(attestationOptions).WithTable(appConfig.Table.Options)
 
-		if appConfig.CheckForAppUpdate {
-			isAvailable, newVersion, err := version.IsUpdateAvailable()
-			if err != nil {
-				log.Errorf(err.Error())
-			}
-			if isAvailable {
-				log.Infof("New version of %s is available: %s", internal.ApplicationName, newVersion)
-
-				bus.Publish(partybus.Event{
-					Type:  event.AppUpdateAvailable,
-					Value: newVersion,
-				})
-			} else {
-				log.Debugf("No new %s update available", internal.ApplicationName)
-			}
+		publishers, err := resultPublishers()
+		if err != nil {
+			errs <- err
+			return
 		}
 
+		checkForAppUpdate()
+
 		var provider vulnerability.Provider
 		var metadataProvider vulnerability.MetadataProvider
 		var dbStatus *db.Status
 		var packages []pkg.Package
 		var context pkg.Context
+		var baseImageRules []match.IgnoreRule
 		var wg = &sync.WaitGroup{}
 		var loadedDB, gatheredPackages bool
 
@@ -248,9 +688,9 @@ func startWorker(userInput string, failOnSeverity *vulnerability.Severity) <-cha
 
 		go func() {
 			defer wg.Done()
-			log.Debug("loading DB")
-			provider, metadataProvider, dbStatus, err = grype.LoadVulnerabilityDB(appConfig.DB.ToCuratorConfig(), appConfig.DB.AutoUpdate)
-			if err = validateDBLoad(err, dbStatus); err != nil {
+			provider, metadataProvider, dbStatus, err = loadScanDB(spanCtx)
+			if err != nil {
+				tracing.Fail(span, err)
 				errs <- err
 				return
 			}
@@ -260,16 +700,21 @@ func startWorker(userInput string, failOnSeverity *vulnerability.Severity) <-cha
 		go func() {
 			defer wg.Done()
 			log.Debugf("gathering packages")
-			providerConfig := pkg.ProviderConfig{
-				RegistryOptions:   appConfig.Registry.ToOptions(),
-				Exclusions:        appConfig.Exclusions,
-				CatalogingOptions: appConfig.Search.ToConfig(),
-			}
-			packages, context, err = pkg.Provide(userInput, providerConfig)
+			providerConfig := newProviderConfig()
+			packages, context, err = pkg.ProvideContext(spanCtx, t.reference, providerConfig)
 			if err != nil {
+				tracing.Fail(span, err)
 				errs <- fmt.Errorf("failed to catalog: %w", err)
 				return
 			}
+			log.WithFields(sourceLogFields(context)).Infof("cataloged %d packages", len(packages))
+			if appConfig.ExcludeBaseImage != "" {
+				baseImageRules, err = baseImageIgnoreRules(appConfig.ExcludeBaseImage, context.Source, providerConfig)
+				if err != nil {
+					errs <- err
+					return
+				}
+			}
 			gatheredPackages = true
 		}()
 
@@ -282,28 +727,247 @@ func startWorker(userInput string, failOnSeverity *vulnerability.Severity) <-cha
 			appConfig.Ignore = append(appConfig.Ignore, ignoreNonFixedMatches...)
 		}
 
-		allMatches := grype.FindVulnerabilitiesForPackage(provider, context.Distro, packages...)
-		remainingMatches, ignoredMatches := match.ApplyIgnoreRules(allMatches, appConfig.Ignore)
+		context.DistroEOL = distroEOL(provider, context.Distro)
+		if distroEOLReached(context) && appConfig.FailOnEOL {
+			errs <- grypeerr.ErrDistroEOL
+		}
+
+		allMatches := grype.FindVulnerabilitiesForPackageContext(spanCtx, provider, context.Distro, appConfig.Matcher, packages...)
+		// note: the base image rules are not part of the configuration (which is reported), since there is a rule for
+		// every package of the base image
+		ignoreRules := append(append([]match.IgnoreRule{}, appConfig.Ignore...), baseImageRules...)
+		remainingMatches, ignoredMatches := match.ApplyIgnoreRules(allMatches, ignoreRules)
 
 		if count := len(ignoredMatches); count > 0 {
 			log.Infof("ignoring %d matches due to user-provided ignore rules", count)
 		}
 
+		remainingMatches, err = match.FilterByDate(remainingMatches, metadataProvider, appConfig.DateFilter)
+		if err != nil {
+			errs <- err
+			return
+		}
+
 		// determine if there are any severities >= to the max allowable severity (which is optional).
 		// note: until the shared file lock in sqlittle is fixed the sqlite DB cannot be access concurrently,
 		// implying that the fail-on-severity check must be done before sending the presenter object.
 		if hitSeverityThreshold(failOnSeverity, remainingMatches, metadataProvider) {
 			errs <- grypeerr.ErrAboveSeverityThreshold
 		}
+		if appConfig.FailOnFixable && hasFixableVulnerability(remainingMatches) {
+			errs <- grypeerr.ErrFixableVulnerabilities
+		}
+
+		if appConfig.Policy.PolicyOpt != nil {
+			context.Policy, err = evaluatePolicy(appConfig.Policy.PolicyOpt, t.String(), remainingMatches, ignoredMatches, packages, context, metadataProvider, dbStatus)
+			if err != nil {
+				errs <- err
+			} else if !context.Policy.Passed() {
+				errs <- policyDenied(context.Policy)
+			}
+		}
+
+		if appConfig.History.Enabled {
+			recordHistory(t.String(), context, remainingMatches, metadataProvider, dbStatus)
+		}
+
+		notifyScanFinished(t.String(), remainingMatches, ignoredMatches, packages, context, metadataProvider, dbStatus)
+		if err := publishResult(publishers, t.String(), remainingMatches, ignoredMatches, packages, context, metadataProvider, dbStatus); err != nil {
+			errs <- err
+		}
+
+		report := presenter.GetPresenter(presenterConfig, remainingMatches, ignoredMatches, packages, context, metadataProvider, appConfig, dbStatus)
+		if exploreResults {
+			doc, err := models.NewDocument(packages, context, remainingMatches, ignoredMatches, metadataProvider, nil, dbStatus)
+			if err != nil {
+				errs <- err
+				return
+			}
+			report = explorePresenter{matches: doc.Matches}
+		}
+		if appConfig.ResultAttestation.Push {
+			// note: when the report is the attestation, the same (signed) attestation is pushed
+			signed, ok := report.(*attestation.Presenter)
+			if !ok {
+				signed = attestation.NewPresenter(remainingMatches, ignoredMatches, packages, context, metadataProvider, appConfig, dbStatus, attestationOptions)
+			}
+			if err := pushResultAttestation(signed, context); err != nil {
+				errs <- err
+			}
+		}
 
 		bus.Publish(partybus.Event{
 			Type:  event.VulnerabilityScanningFinished,
-			Value: presenter.GetPresenter(presenterConfig, remainingMatches, ignoredMatches, packages, context, metadataProvider, appConfig, dbStatus),
+			Value: newTracedPresenter(spanCtx, report),
 		})
 	}()
 	return errs
 }
 
+// pushResultAttestation pushes the signed attestation of the scan result to the registry of the scanned image (next
+// to the image, the way cosign attaches attestations).
+func pushResultAttestation(signed *attestation.Presenter, context pkg.Context) error {
+	if context.Source == nil || context.Source.Scheme != source.ImageScheme {
+		return fmt.Errorf("the attestation of the scan result can only be pushed for images")
+	}
+	envelope, err := signed.Envelope()
+	if err != nil {
+		return fmt.Errorf("unable to attest the scan result: %w", err)
+	}
+	reference := context.Source.ImageMetadata.UserInput
+	pushed, err := pkg.PushAttestation(reference, envelope, appConfig.Registry.ToOptions())
+	if err != nil {
+		return fmt.Errorf("unable to push the attestation of the scan result of image=%q: %w", reference, err)
+	}
+	log.Infof("pushed the attestation of the scan result to %q", pushed)
+	return nil
+}
+
+func checkForAppUpdate() {
+	if !appConfig.CheckForAppUpdate {
+		return
+	}
+	isAvailable, newVersion, err := version.IsUpdateAvailable()
+	if err != nil {
+		log.Errorf(err.Error())
+	}
+	if isAvailable {
+		log.Infof("New version of %s is available: %s", internal.ApplicationName, newVersion)
+
+		bus.Publish(partybus.Event{
+			Type:  event.AppUpdateAvailable,
+			Value: newVersion,
+		})
+	} else {
+		log.Debugf("No new %s update available", internal.ApplicationName)
+	}
+}
+
+// loadScanDB loads the vulnerability database for scanning, which is combined with the online sources that are enabled.
+func loadScanDB(spanCtx context.Context) (_ vulnerability.Provider, _ vulnerability.MetadataProvider, _ *db.Status, err error) {
+	_, span := tracing.Start(spanCtx, "load db", attribute.Bool("grype.db.auto_update", appConfig.DB.AutoUpdate))
+	defer func() { tracing.End(span, err) }()
+
+	log.Debug("loading DB")
+	provider, metadataProvider, dbStatus, err := grype.LoadVulnerabilityDB(appConfig.DB.ToCuratorConfig(), appConfig.DB.AutoUpdate)
+	if err = validateDBLoad(err, dbStatus); err != nil {
+		return nil, nil, nil, err
+	}
+	if err = validateDBAge(dbStatus, appConfig.DB.MaxAllowedAgeDuration, appConfig.DB.StalePolicy, time.Now()); err != nil {
+		return nil, nil, nil, err
+	}
+	span.SetAttributes(
+		attribute.Int("grype.db.schema_version", dbStatus.SchemaVersion),
+		attribute.String("grype.db.built", dbStatus.Built.UTC().Format(time.RFC3339)),
+	)
+	log.WithFields(log.Fields{
+		"db-schema-version": dbStatus.SchemaVersion,
+		"db-built":          dbStatus.Built.UTC().Format(time.RFC3339),
+		"db-checksum":       dbStatus.Checksum,
+	}).Info("loaded vulnerability database")
+	if cfg := appConfig.ExternalSources.ToGitHubAdvisoriesConfig(); cfg.Enabled {
+		online := ghsa.NewProvider(provider, metadataProvider, cfg)
+		provider, metadataProvider = online, online
+	}
+	return provider, metadataProvider, dbStatus, nil
+}
+
+// sourceLogFields returns the log fields that identify the scanned source (e.g. the digest of an image).
+func sourceLogFields(context pkg.Context) log.Fields {
+	fields := log.Fields{}
+	if src := context.Source; src != nil {
+		fields["source-type"] = string(src.Scheme)
+		switch src.Scheme {
+		case source.ImageScheme:
+			fields["image"] = src.ImageMetadata.UserInput
+			fields["image-id"] = src.ImageMetadata.ID
+			fields["image-digest"] = src.ImageMetadata.ManifestDigest
+		case source.DirectoryScheme, source.FileScheme:
+			fields["path"] = src.Path
+		}
+	}
+	if context.Distro != nil {
+		fields["distro"] = context.Distro.String()
+	}
+	return fields
+}
+
+// newProviderConfig returns the configuration for gathering packages from the application configuration.
+func newProviderConfig() pkg.ProviderConfig {
+	return pkg.ProviderConfig{
+		RegistryOptions:        appConfig.Registry.ToOptions(),
+		Exclusions:             appConfig.Exclusions,
+		PackageExclusions:      appConfig.PackageExclusions,
+		ExcludeDevDependencies: appConfig.ExcludeDevDeps,
+		CPEOverrides:           appConfig.CPEOverrideRules,
+		CatalogingOptions:      appConfig.Search.ToConfig(),
+		Distro:                 appConfig.DistroRelease,
+		MavenSearch:            appConfig.ExternalSources.ToMavenSearchConfig(),
+		Catalog:                pkg.CatalogConfig{Deduplicate: appConfig.DedupPackages},
+		SBOMCache:              appConfig.SBOMCache.ToConfig(),
+		Archives:               appConfig.Archives.ToConfig(),
+		Attestations:           appConfig.Attestations.ToConfig(),
+	}
+}
+
+// baseImageIgnoreRules catalogs the given base image (or the base image annotated on the scanned image when the
+// reference is "auto") and returns rules that ignore the matches of the packages inherited from the base image.
+func baseImageIgnoreRules(reference string, src *source.Metadata, config pkg.ProviderConfig) ([]match.IgnoreRule, error) {
+	if reference == autoBaseImage {
+		reference = pkg.BaseImageReference(src)
+		if reference == "" {
+			log.Warnf("unable to exclude the base image: the image manifest has no %q annotation", pkg.BaseImageNameAnnotation)
+			return nil, nil
+		}
+	}
+
+	log.Debugf("gathering packages of base image=%q", reference)
+	basePackages, _, err := pkg.Provide(reference, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to catalog base image %q: %w", reference, err)
+	}
+
+	rules := match.BaseImageIgnoreRules(reference, basePackages)
+	log.Infof("excluding %d packages inherited from base image=%q", len(rules), reference)
+	return rules, nil
+}
+
+// distroEOL returns the end of life of the given distro release, when known by the provider.
+func distroEOL(provider vulnerability.Provider, release *linux.Release) *time.Time {
+	eolProvider, ok := provider.(vulnerability.DistroEOLProvider)
+	if !ok || release == nil {
+		return nil
+	}
+
+	d, err := distro.NewFromRelease(*release)
+	if err != nil {
+		return nil
+	}
+
+	eol, err := eolProvider.GetDistroEOL(d)
+	if err != nil {
+		log.Warnf("unable to determine the end of life of distro=%s: %+v", d, err)
+		return nil
+	}
+	if eol == nil || time.Now().Before(*eol) {
+		return eol
+	}
+
+	log.Warnf("%s reached the end of life on %s, vulnerability data is no longer published for it", d, eol.Format("2006-01-02"))
+	bus.Publish(partybus.Event{
+		Type:   event.DistroEOLReached,
+		Source: d,
+		Value:  *eol,
+	})
+
+	return eol
+}
+
+// distroEOLReached indicates if the distro of the given context has reached the end of life.
+func distroEOLReached(context pkg.Context) bool {
+	return context.DistroEOL != nil && !time.Now().Before(*context.DistroEOL)
+}
+
 func validateDBLoad(loadErr error, status *db.Status) error {
 	if loadErr != nil {
 		return fmt.Errorf("failed to load vulnerability db: %w", loadErr)
@@ -317,6 +981,20 @@ func validateDBLoad(loadErr error, status *db.Status) error {
 	return nil
 }
 
+// validateDBAge checks that the database is not older than the max allowed age (if any), where a stale database either
+// fails the scan or only raises a warning (according to the given policy).
+func validateDBAge(status *db.Status, maxAge time.Duration, policy string, now time.Time) error {
+	if !status.IsStale(maxAge, now) {
+		return nil
+	}
+	message := fmt.Sprintf("vulnerability database was built %s ago, which is older than the max allowed age of %s (run 'grype db update' to correct)", now.Sub(status.Built).Round(time.Hour), maxAge)
+	if policy == config.WarnStalePolicy {
+		log.Warn(message)
+		return nil
+	}
+	return errors.New(message)
+}
+
 func validateRootArgs(cmd *cobra.Command, args []string) error {
 	isPipedInput, err := internal.IsPipedInput()
 	if err != nil {
@@ -324,7 +1002,7 @@ func validateRootArgs(cmd *cobra.Command, args []string) error {
 		isPipedInput = false
 	}
 
-	if len(args) == 0 && !isPipedInput {
+	if len(args) == 0 && !isPipedInput && targetsFile == "" {
 		// in the case that no arguments are given and there is no piped input we want to show the help text and return with a non-0 return code.
 		if err := cmd.Help(); err != nil {
 			return fmt.Errorf("unable to display help: %w", err)
@@ -332,7 +1010,45 @@ func validateRootArgs(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("an image/directory argument is required")
 	}
 
-	return cobra.MaximumNArgs(1)(cmd, args)
+	return nil
+}
+
+// evaluatePolicy returns the decision of the given policy over the scan result of the target, where the input of the
+// policy is the JSON document of the scan result.
+func evaluatePolicy(p *policy.Policy, target string, matches match.Matches, ignoredMatches []match.IgnoredMatch, packages []pkg.Package, context pkg.Context, metadataProvider vulnerability.MetadataProvider, dbStatus *db.Status) (*policy.Decision, error) {
+	doc, err := models.NewDocument(packages, context, matches, ignoredMatches, metadataProvider, appConfig, dbStatus)
+	if err != nil {
+		return nil, fmt.Errorf("unable to describe the scan result of target=%q for the policy: %w", target, err)
+	}
+	decision, err := p.Evaluate(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, warning := range decision.Warnings {
+		log.Warnf("policy=%q warns about target=%q: %s", decision.Policy, target, warning)
+	}
+	for _, denial := range decision.Denials {
+		log.Errorf("policy=%q denies target=%q: %s", decision.Policy, target, denial)
+	}
+	return decision, nil
+}
+
+// policyDenied returns the error of a scan result that the policy denied (which describes the denials).
+func policyDenied(decision *policy.Decision) error {
+	return fmt.Errorf("%w: %s", grypeerr.ErrPolicyDenied, strings.Join(decision.Denials, "; "))
+}
+
+// hasFixableVulnerability indicates if any of the matches has a fix (by the fix state or the fix versions).
+func hasFixableVulnerability(matches match.Matches) bool {
+	fixable := false
+	// note: the enumeration is never stopped early, which would leak the enumerating goroutine
+	for m := range matches.Enumerate() {
+		if match.Fixable(m) {
+			fixable = true
+		}
+	}
+	return fixable
 }
 
 // hitSeverityThreshold indicates if there are any severities >= to the max allowable severity (which is optional)
@@ -1,15 +1,20 @@
 package cmd
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/anchore/grype/grype/db"
 	grypeDB "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/grypeerr"
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/policy"
 	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/config"
 	syftPkg "github.com/anchore/syft/syft/pkg"
 )
 
@@ -112,3 +117,152 @@ func TestAboveAllowableSeverity(t *testing.T) {
 		})
 	}
 }
+
+func TestDistroEOLReached(t *testing.T) {
+	past := time.Now().AddDate(-1, 0, 0)
+	future := time.Now().AddDate(1, 0, 0)
+
+	tests := []struct {
+		name           string
+		eol            *time.Time
+		expectedResult bool
+	}{
+		{
+			name:           "unknown end of life",
+			expectedResult: false,
+		},
+		{
+			name:           "reached end of life",
+			eol:            &past,
+			expectedResult: true,
+		},
+		{
+			name:           "upcoming end of life",
+			eol:            &future,
+			expectedResult: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := distroEOLReached(pkg.Context{DistroEOL: test.eol})
+
+			if test.expectedResult != actual {
+				t.Errorf("expected: %v got : %v", test.expectedResult, actual)
+			}
+		})
+	}
+}
+
+func TestHasFixableVulnerability(t *testing.T) {
+	newMatches := func(fix vulnerability.Fix) match.Matches {
+		return match.NewMatches(match.Match{
+			Vulnerability: vulnerability.Vulnerability{ID: "CVE-2014-fake-1", Namespace: "source-1", Fix: fix},
+			Package:       pkg.Package{ID: pkg.ID(uuid.NewString()), Name: "the-package", Version: "v0.1", Type: syftPkg.RpmPkg},
+		})
+	}
+
+	tests := []struct {
+		name           string
+		matches        match.Matches
+		expectedResult bool
+	}{
+		{
+			name:           "no matches",
+			matches:        match.NewMatches(),
+			expectedResult: false,
+		},
+		{
+			name:           "fixed",
+			matches:        newMatches(vulnerability.Fix{State: grypeDB.FixedState, Versions: []string{"v0.2"}}),
+			expectedResult: true,
+		},
+		{
+			name:           "fixed without a known version",
+			matches:        newMatches(vulnerability.Fix{State: grypeDB.FixedState}),
+			expectedResult: true,
+		},
+		{
+			name:           "only fixed below the installed version",
+			matches:        newMatches(vulnerability.Fix{State: grypeDB.FixedState, Versions: []string{"v0.0.9"}}),
+			expectedResult: false,
+		},
+		{
+			name:           "not fixed",
+			matches:        newMatches(vulnerability.Fix{State: grypeDB.NotFixedState}),
+			expectedResult: false,
+		},
+		{
+			name:           "won't fix",
+			matches:        newMatches(vulnerability.Fix{State: grypeDB.WontFixState}),
+			expectedResult: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := hasFixableVulnerability(test.matches)
+
+			if test.expectedResult != actual {
+				t.Errorf("expected: %v got : %v", test.expectedResult, actual)
+			}
+		})
+	}
+}
+
+func TestValidateDBAge(t *testing.T) {
+	now := time.Date(2021, 11, 20, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		built  time.Time
+		maxAge time.Duration
+		policy string
+		err    bool
+	}{
+		{
+			name:   "no max allowed age",
+			built:  now.AddDate(-1, 0, 0),
+			policy: config.FailStalePolicy,
+		},
+		{
+			name:   "recent database",
+			built:  now.AddDate(0, 0, -1),
+			maxAge: 5 * 24 * time.Hour,
+			policy: config.FailStalePolicy,
+		},
+		{
+			name:   "stale database fails",
+			built:  now.AddDate(0, 0, -6),
+			maxAge: 5 * 24 * time.Hour,
+			policy: config.FailStalePolicy,
+			err:    true,
+		},
+		{
+			name:   "stale database warns",
+			built:  now.AddDate(0, 0, -6),
+			maxAge: 5 * 24 * time.Hour,
+			policy: config.WarnStalePolicy,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := validateDBAge(&db.Status{Built: test.built}, test.maxAge, test.policy, now)
+			if test.err != (err != nil) {
+				t.Errorf("expected error: %v got : %+v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestPolicyDenied(t *testing.T) {
+	err := policyDenied(&policy.Decision{Denials: []string{"CVE-2021-1 is known exploited", "CVE-2021-2 is critical"}})
+	if !errors.Is(err, grypeerr.ErrPolicyDenied) {
+		t.Errorf("expected a policy denied error, got: %+v", err)
+	}
+	expected := "the policy denied the scan result: CVE-2021-1 is known exploited; CVE-2021-2 is critical"
+	if err.Error() != expected {
+		t.Errorf("expected error %q, got %q", expected, err.Error())
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "show the schemas of the output formats",
+}
+
+var schemaJSONCmd = &cobra.Command{
+	Use:     "json",
+	Short:   `show the JSON schema of the JSON output (see "-o json")`,
+	Example: "  grype schema json > schema.json",
+	Args:    cobra.ExactArgs(0),
+	RunE:    runSchemaJSONCmd,
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaJSONCmd)
+	rootCmd.AddCommand(schemaCmd)
+}
+
+func runSchemaJSONCmd(_ *cobra.Command, _ []string) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", " ")
+	if err := enc.Encode(models.JSONSchema()); err != nil {
+		return fmt.Errorf("failed to show the JSON schema: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/metrics"
+	"github.com/anchore/grype/grype/server"
+	"github.com/anchore/grype/internal/log"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+)
+
+var (
+	serveListen             string
+	serveGRPCListen         string
+	serveMaxScans           int
+	serveMaxConcurrentScans int
+	serveTokenFile          string
+	serveGRPCConfig         server.GRPCConfig
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "run a scanning service exposing a REST API (with the vulnerability database loaded once)",
+	Long: `Runs a long-running scanning service with a REST API:
+  POST /v1/scans          submit an image ({"image": "alpine:latest"}) or an SBOM (as the request body) to scan
+  GET  /v1/scans          list the submitted scans
+  GET  /v1/scans/{id}     retrieve a scan and (once complete) its result
+  GET  /v1/db/status      the status of the loaded vulnerability database
+  GET  /v1/ignore-rules   the ignore rules applied to new scans (PUT replaces and POST adds ignore rules, which
+                          requires the token of --token-file as a bearer token)
+  GET  /metrics           the metrics of the service, in the Prometheus text exposition format
+
+With --grpc-listen the scanning API is also served over gRPC (see grype/server/rpc/grype.proto).`,
+	Args: cobra.ExactArgs(0),
+	RunE: runServeCmd,
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveListen, "listen", "localhost:8080", "the address to listen on")
+	serveCmd.Flags().StringVar(&serveGRPCListen, "grpc-listen", "", "the address to serve the gRPC scanning API on (not served by default)")
+	serveCmd.Flags().StringVar(&serveGRPCConfig.TLSCertFile, "grpc-tls-cert-file", "", "the certificate to serve the gRPC scanning API over TLS (served without TLS by default)")
+	serveCmd.Flags().StringVar(&serveGRPCConfig.TLSKeyFile, "grpc-tls-key-file", "", "the key of the certificate to serve the gRPC scanning API over TLS")
+	serveCmd.Flags().IntVar(&serveGRPCConfig.MaxMessageSize, "grpc-max-message-size", server.DefaultGRPCMaxMessageSize, "the largest gRPC message (e.g. a request with an SBOM) that is received or sent, in bytes")
+	serveCmd.Flags().IntVar(&serveMaxScans, "max-scans", server.DefaultMaxScans, "the number of scans to keep, where the oldest scans are discarded first")
+	serveCmd.Flags().IntVar(&serveMaxConcurrentScans, "max-concurrent-scans", server.DefaultMaxConcurrentScans, "the number of scans that run at the same time, where further scans are rejected (with HTTP 429) until a scan completes")
+	serveCmd.Flags().StringVar(&serveTokenFile, "token-file", "", "a file with the bearer token that is required to change the ignore rules (which cannot be changed without a token)")
+
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServeCmd(_ *cobra.Command, _ []string) error {
+	log.Debug("loading DB")
+	provider, metadataProvider, dbStatus, err := grype.LoadVulnerabilityDB(appConfig.DB.ToCuratorConfig(), appConfig.DB.AutoUpdate)
+	if err = validateDBLoad(err, dbStatus); err != nil {
+		return err
+	}
+	if err = validateDBAge(dbStatus, appConfig.DB.MaxAllowedAgeDuration, appConfig.DB.StalePolicy, time.Now()); err != nil {
+		return err
+	}
+	metrics.SetDBBuilt(dbStatus.Built)
+
+	var token string
+	if serveTokenFile != "" {
+		contents, err := os.ReadFile(serveTokenFile)
+		if err != nil {
+			return fmt.Errorf("unable to read token: %w", err)
+		}
+		token = strings.TrimSpace(string(contents))
+	}
+
+	ignoreRules := appConfig.Ignore
+	if appConfig.OnlyFixed {
+		ignoreRules = append(ignoreRules, ignoreNonFixedMatches...)
+	}
+	s := server.New(provider, metadataProvider, dbStatus, server.Config{
+		Provider:           newProviderConfig(),
+		Matcher:            appConfig.Matcher,
+		IgnoreRules:        ignoreRules,
+		MaxScans:           serveMaxScans,
+		MaxConcurrentScans: serveMaxConcurrentScans,
+		Token:              token,
+	})
+
+	httpServer := &http.Server{
+		Addr:    serveListen,
+		Handler: s.Handler(),
+	}
+
+	errs := make(chan error, 2)
+	go func() {
+		log.Infof("listening on %s", serveListen)
+		errs <- httpServer.ListenAndServe()
+	}()
+
+	var grpcServer *grpc.Server
+	if serveGRPCListen != "" {
+		listener, err := net.Listen("tcp", serveGRPCListen)
+		if err != nil {
+			return fmt.Errorf("unable to serve gRPC: %w", err)
+		}
+		options, err := serveGRPCConfig.ServerOptions()
+		if err != nil {
+			return fmt.Errorf("unable to serve gRPC: %w", err)
+		}
+		grpcServer = grpc.NewServer(options...)
+		s.RegisterGRPC(grpcServer)
+		go func() {
+			log.Infof("serving gRPC on %s", serveGRPCListen)
+			errs <- grpcServer.Serve(listener)
+		}()
+	}
+
+	select {
+	case err := <-errs:
+		return fmt.Errorf("unable to serve: %w", err)
+	case <-setupSignals():
+		if grpcServer != nil {
+			grpcServer.GracefulStop()
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
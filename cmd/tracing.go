@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"context"
+	"io"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/anchore/grype/grype/presenter"
+	"github.com/anchore/grype/internal/tracing"
+)
+
+// startScanSpan starts the root span of a scan (of which catalogs, DB loads, matching, and presentation are children).
+func startScanSpan(name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Start(context.Background(), name, attributes...)
+}
+
+// tracedPresenter is a presenter whose presentation is a span of the scan (since the report is presented by the UI,
+// after the scan itself is done).
+type tracedPresenter struct {
+	presenter.Presenter
+	spanCtx context.Context
+}
+
+func newTracedPresenter(spanCtx context.Context, pres presenter.Presenter) presenter.Presenter {
+	if !trace.SpanFromContext(spanCtx).IsRecording() {
+		return pres
+	}
+	return tracedPresenter{Presenter: pres, spanCtx: spanCtx}
+}
+
+func (p tracedPresenter) Present(output io.Writer) (err error) {
+	_, span := tracing.Start(p.spanCtx, "present")
+	defer func() { tracing.End(span, err) }()
+	return p.Presenter.Present(output)
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/internal"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{
+	Use:   "validate REPORT",
+	Short: `validate a JSON report (see "-o json") against the JSON schema`,
+	Long: fmt.Sprintf(`Validates a JSON report (see "-o json") against the JSON schema of its schema version (see "grype schema json"),
+reporting each violation. Only reports of the schema version of this version of grype (%s) can be validated.`, internal.JSONSchemaVersion),
+	Example: "  grype validate report.json",
+	Args:    cobra.ExactArgs(1),
+	RunE:    runValidateCmd,
+}
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+}
+
+func runValidateCmd(_ *cobra.Command, args []string) error {
+	path := args[0]
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read report: %w", err)
+	}
+
+	violations, err := models.ValidateDocument(contents)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	for _, v := range violations {
+		fmt.Fprintln(os.Stdout, v)
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%s is not a valid report for schema version %s (%d violations)", path, internal.JSONSchemaVersion, len(violations))
+	}
+	return stderrPrintLnf("%s is a valid report for schema version %s", path, internal.JSONSchemaVersion)
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/grype"
+	"github.com/anchore/grype/grype/db"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/metrics"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/watch"
+	"github.com/anchore/grype/internal/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	watchInterval      time.Duration
+	watchWebhook       string
+	watchOutputFormat  string
+	watchReportInitial bool
+	watchMetricsListen string
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch TARGET...",
+	Short: "re-evaluate targets whenever a new vulnerability database is published, reporting newly appearing matches",
+	Long: `Catalogs the given targets (images, directories, or SBOMs) once, and matches their packages against every new
+vulnerability database that is published, reporting only the matches that newly appear (to stdout or a webhook).`,
+	Example: `  grype watch alpine:3.15 sbom:./app-sbom.json
+  grype watch alpine:3.15 --interval 6h --webhook https://example.com/grype`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runWatchCmd,
+}
+
+func init() {
+	watchCmd.Flags().DurationVar(&watchInterval, "interval", time.Hour, "how often to check for a new vulnerability database")
+	watchCmd.Flags().StringVar(&watchWebhook, "webhook", "", "post newly appearing matches to the given URL (instead of writing them to stdout)")
+	watchCmd.Flags().StringVarP(&watchOutputFormat, "output", "o", "text", "format to write newly appearing matches to stdout (available=[text, json])")
+	watchCmd.Flags().BoolVar(&watchReportInitial, "report-initial", false, "also report the matches of the first evaluation (by default these are the baseline)")
+	watchCmd.Flags().StringVar(&watchMetricsListen, "metrics-listen", "", "the address to serve Prometheus metrics on at /metrics (not served by default)")
+
+	rootCmd.AddCommand(watchCmd)
+}
+
+func runWatchCmd(_ *cobra.Command, args []string) error {
+	if watchInterval <= 0 {
+		return fmt.Errorf("bad --interval value: %s (must be positive)", watchInterval)
+	}
+	report, err := watchReporter()
+	if err != nil {
+		return err
+	}
+	if watchMetricsListen != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Default.Handler())
+		go func() {
+			log.Infof("serving metrics on %s", watchMetricsListen)
+			if err := http.ListenAndServe(watchMetricsListen, mux); err != nil {
+				log.Errorf("unable to serve metrics: %+v", err)
+			}
+		}()
+	}
+
+	var targets []watch.Target
+	for _, input := range args {
+		log.Infof("gathering packages of target=%q", input)
+		packages, context, err := pkg.Provide(input, newProviderConfig())
+		if err != nil {
+			return fmt.Errorf("failed to catalog %q: %w", input, err)
+		}
+		targets = append(targets, watch.Target{Input: input, Packages: packages, Context: context})
+	}
+
+	curator, err := db.NewCurator(appConfig.DB.ToCuratorConfig())
+	if err != nil {
+		return err
+	}
+	if appConfig.DB.AutoUpdate {
+		if _, err := curator.Update(); err != nil {
+			return err
+		}
+	}
+
+	ignoreRules := append([]match.IgnoreRule{}, appConfig.Ignore...)
+	if appConfig.OnlyFixed {
+		ignoreRules = append(ignoreRules, ignoreNonFixedMatches...)
+	}
+	watcher := watch.NewWatcher(targets, appConfig.Matcher, ignoreRules)
+
+	events, err := evaluateWatchTargets(watcher)
+	if err != nil {
+		return err
+	}
+	log.Infof("watching %d targets with %d matches", len(targets), len(events))
+	if watchReportInitial {
+		report(events)
+		notifyNewVulnerabilities(events)
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	signals := setupSignals()
+	for {
+		select {
+		case <-signals:
+			return nil
+		case <-ticker.C:
+			updated, err := curator.Update()
+			if err != nil {
+				log.Warnf("unable to update the vulnerability database: %+v", err)
+				metrics.Errors.With("db-update").Inc()
+				continue
+			}
+			if !updated {
+				continue
+			}
+
+			events, err := evaluateWatchTargets(watcher)
+			if err != nil {
+				log.Warnf("unable to evaluate targets: %+v", err)
+				continue
+			}
+			log.Infof("found %d new matches", len(events))
+			report(events)
+			notifyNewVulnerabilities(events)
+		}
+	}
+}
+
+// evaluateWatchTargets evaluates the targets against the current vulnerability database.
+func evaluateWatchTargets(watcher *watch.Watcher) ([]watch.Event, error) {
+	provider, metadataProvider, dbStatus, err := grype.LoadVulnerabilityDB(appConfig.DB.ToCuratorConfig(), false)
+	if err = validateDBLoad(err, dbStatus); err != nil {
+		metrics.Errors.With("db-load").Inc()
+		return nil, err
+	}
+	metrics.SetDBBuilt(dbStatus.Built)
+	return watcher.Evaluate(provider, metadataProvider, dbStatus.Built)
+}
+
+// watchReporter returns how newly appearing matches are reported.
+func watchReporter() (func([]watch.Event), error) {
+	if watchWebhook != "" {
+		client := &http.Client{Timeout: 30 * time.Second}
+		return func(events []watch.Event) {
+			if len(events) == 0 {
+				return
+			}
+			if err := watch.PostEvents(client, watchWebhook, events); err != nil {
+				log.Warnf("%+v", err)
+				metrics.Errors.With("notify").Inc()
+			}
+		}, nil
+	}
+
+	switch watchOutputFormat {
+	case "text", "json":
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", watchOutputFormat)
+	}
+	return func(events []watch.Event) {
+		if err := writeWatchEvents(os.Stdout, watchOutputFormat, events); err != nil {
+			log.Warnf("unable to write matches: %+v", err)
+		}
+	}, nil
+}
+
+func writeWatchEvents(output io.Writer, format string, events []watch.Event) error {
+	enc := json.NewEncoder(output)
+	enc.SetEscapeHTML(false)
+	for _, e := range events {
+		if format == "json" {
+			if err := enc.Encode(e); err != nil {
+				return err
+			}
+			continue
+		}
+
+		line := fmt.Sprintf("%s: %s (%s) %s %s", e.Target, e.Match.Vulnerability.ID, e.Match.Vulnerability.Severity,
+			e.Match.Artifact.Name, e.Match.Artifact.Version)
+		if fixes := e.Match.Vulnerability.Fix.Versions; len(fixes) > 0 {
+			line += fmt.Sprintf(" (fixed in %s)", strings.Join(fixes, ", "))
+		}
+		if _, err := fmt.Fprintln(output, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// kevCatalog is the subset of the CISA Known Exploited Vulnerabilities catalog that is needed (see
+// https://www.cisa.gov/known-exploited-vulnerabilities-catalog).
+type kevCatalog struct {
+	Vulnerabilities []struct {
+		CVEID string `json:"cveID"`
+	} `json:"vulnerabilities"`
+}
+
+// KEV is a set of known exploited vulnerabilities (by CVE ID).
+type KEV map[string]struct{}
+
+// ReadKEV reads a known exploited vulnerabilities catalog (in the CISA JSON format).
+func ReadKEV(reader io.Reader) (KEV, error) {
+	var catalog kevCatalog
+	if err := json.NewDecoder(reader).Decode(&catalog); err != nil {
+		return nil, fmt.Errorf("unable to read known exploited vulnerabilities catalog: %w", err)
+	}
+
+	kev := make(KEV, len(catalog.Vulnerabilities))
+	for _, v := range catalog.Vulnerabilities {
+		kev[strings.ToUpper(v.CVEID)] = struct{}{}
+	}
+	return kev, nil
+}
+
+// Contains indicates if the given vulnerability is known to be exploited.
+func (k KEV) Contains(id string) bool {
+	_, ok := k[strings.ToUpper(id)]
+	return ok
+}
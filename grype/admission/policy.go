@@ -0,0 +1,46 @@
+package admission
+
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// Policy determines which matches deny an image.
+type Policy struct {
+	// FailOnSeverity denies images with a match at or above the severity (no image is denied by severity when nil)
+	FailOnSeverity *vulnerability.Severity
+	// KEV denies images with a match of a known exploited vulnerability (no image is denied as such when empty)
+	KEV KEV
+}
+
+// Violations returns a description of every match that violates the policy.
+func (p Policy) Violations(matches []models.Match) []string {
+	var violations []string
+	for _, m := range matches {
+		id := m.Vulnerability.ID
+		if kevID := p.knownExploited(m); kevID != "" {
+			violations = append(violations, fmt.Sprintf("%s (known exploited %s) in %s %s", id, kevID, m.Artifact.Name, m.Artifact.Version))
+			continue
+		}
+		if p.FailOnSeverity != nil && vulnerability.ParseSeverity(m.Vulnerability.Severity) >= *p.FailOnSeverity {
+			violations = append(violations, fmt.Sprintf("%s (%s) in %s %s", id, m.Vulnerability.Severity, m.Artifact.Name, m.Artifact.Version))
+		}
+	}
+	return violations
+}
+
+// knownExploited returns the vulnerability of the match that is known to be exploited, which is either the matched
+// vulnerability or a related vulnerability (e.g. the CVE of a GitHub advisory), or "" when there is none.
+func (p Policy) knownExploited(m models.Match) string {
+	if p.KEV.Contains(m.Vulnerability.ID) {
+		return m.Vulnerability.ID
+	}
+	for _, related := range m.RelatedVulnerabilities {
+		if p.KEV.Contains(related.ID) {
+			return related.ID
+		}
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package admission
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMatch(id, severity string, related ...string) models.Match {
+	m := models.Match{
+		Vulnerability: models.Vulnerability{
+			VulnerabilityMetadata: models.VulnerabilityMetadata{ID: id, Severity: severity},
+		},
+		Artifact: models.Package{Name: "openssl", Version: "1.1.1k"},
+	}
+	for _, r := range related {
+		m.RelatedVulnerabilities = append(m.RelatedVulnerabilities, models.VulnerabilityMetadata{ID: r})
+	}
+	return m
+}
+
+func TestReadKEV(t *testing.T) {
+	kev, err := ReadKEV(strings.NewReader(`{"title": "catalog", "vulnerabilities": [{"cveID": "CVE-2021-44228"}, {"cveID": "cve-2014-0160"}]}`))
+	require.NoError(t, err)
+
+	assert.True(t, kev.Contains("CVE-2021-44228"))
+	assert.True(t, kev.Contains("cve-2021-44228"))
+	assert.True(t, kev.Contains("CVE-2014-0160"))
+	assert.False(t, kev.Contains("CVE-2020-0001"))
+
+	_, err = ReadKEV(strings.NewReader(`not json`))
+	assert.Error(t, err)
+}
+
+func TestPolicy_Violations(t *testing.T) {
+	high := vulnerability.HighSeverity
+	kev := KEV{"CVE-2021-44228": {}}
+
+	tests := []struct {
+		name     string
+		policy   Policy
+		matches  []models.Match
+		expected []string
+	}{
+		{
+			name:    "empty policy",
+			policy:  Policy{},
+			matches: []models.Match{newMatch("CVE-2021-44228", "Critical")},
+		},
+		{
+			name:   "severity",
+			policy: Policy{FailOnSeverity: &high},
+			matches: []models.Match{
+				newMatch("CVE-2021-0001", "Medium"),
+				newMatch("CVE-2021-0002", "High"),
+				newMatch("CVE-2021-0003", "Critical"),
+			},
+			expected: []string{
+				"CVE-2021-0002 (High) in openssl 1.1.1k",
+				"CVE-2021-0003 (Critical) in openssl 1.1.1k",
+			},
+		},
+		{
+			name:   "known exploited",
+			policy: Policy{KEV: kev},
+			matches: []models.Match{
+				newMatch("CVE-2021-44228", "Low"),
+				newMatch("CVE-2021-0002", "Critical"),
+			},
+			expected: []string{"CVE-2021-44228 (known exploited CVE-2021-44228) in openssl 1.1.1k"},
+		},
+		{
+			name:     "known exploited related vulnerability",
+			policy:   Policy{KEV: kev},
+			matches:  []models.Match{newMatch("GHSA-jfh8-c2jp-5v3q", "Critical", "CVE-2021-44228")},
+			expected: []string{"GHSA-jfh8-c2jp-5v3q (known exploited CVE-2021-44228) in openssl 1.1.1k"},
+		},
+		{
+			name:   "known exploited takes precedence over severity",
+			policy: Policy{FailOnSeverity: &high, KEV: kev},
+			matches: []models.Match{
+				newMatch("CVE-2021-44228", "Critical"),
+			},
+			expected: []string{"CVE-2021-44228 (known exploited CVE-2021-44228) in openssl 1.1.1k"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.policy.Violations(test.matches))
+		})
+	}
+}
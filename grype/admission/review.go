@@ -0,0 +1,79 @@
+package admission
+
+import "encoding/json"
+
+// the subset of the admission.k8s.io/v1 API that is needed to review pods (see
+// https://kubernetes.io/docs/reference/access-authn-authz/extensible-admission-controllers/#webhook-request-and-response)
+
+const (
+	reviewAPIVersion = "admission.k8s.io/v1"
+	reviewKind       = "AdmissionReview"
+)
+
+// Review is an AdmissionReview, which carries the request from the API server and the response of the webhook.
+type Review struct {
+	APIVersion string    `json:"apiVersion"`
+	Kind       string    `json:"kind"`
+	Request    *Request  `json:"request,omitempty"`
+	Response   *Response `json:"response,omitempty"`
+}
+
+// Request describes the object that is being admitted.
+type Request struct {
+	UID       string          `json:"uid"`
+	Kind      GroupKind       `json:"kind"`
+	Namespace string          `json:"namespace,omitempty"`
+	Name      string          `json:"name,omitempty"`
+	Operation string          `json:"operation,omitempty"`
+	Object    json.RawMessage `json:"object,omitempty"`
+}
+
+// GroupKind identifies the kind of the object that is being admitted.
+type GroupKind struct {
+	Group   string `json:"group"`
+	Version string `json:"version"`
+	Kind    string `json:"kind"`
+}
+
+// Response is the verdict of the webhook.
+type Response struct {
+	UID      string   `json:"uid"`
+	Allowed  bool     `json:"allowed"`
+	Result   *Status  `json:"status,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Status describes why an object is denied.
+type Status struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// pod is the subset of a pod that is needed to determine the images it runs.
+type pod struct {
+	Spec struct {
+		InitContainers      []container `json:"initContainers"`
+		Containers          []container `json:"containers"`
+		EphemeralContainers []container `json:"ephemeralContainers"`
+	} `json:"spec"`
+}
+
+type container struct {
+	Image string `json:"image"`
+}
+
+// images returns the distinct images of all containers of the pod.
+func (p pod) images() []string {
+	var images []string
+	seen := make(map[string]struct{})
+	for _, containers := range [][]container{p.Spec.InitContainers, p.Spec.Containers, p.Spec.EphemeralContainers} {
+		for _, c := range containers {
+			if _, ok := seen[c.Image]; ok || c.Image == "" {
+				continue
+			}
+			seen[c.Image] = struct{}{}
+			images = append(images, c.Image)
+		}
+	}
+	return images
+}
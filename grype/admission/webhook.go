@@ -0,0 +1,187 @@
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/internal/log"
+)
+
+// maxViolations is the number of violations that are described for each denied image.
+const maxViolations = 10
+
+// Scanner scans an image within a registry, returning its matches (after applying ignore rules).
+type Scanner func(image pkg.ImageReference) ([]models.Match, error)
+
+// Config controls the verdicts of the webhook.
+type Config struct {
+	Policy Policy
+	// CacheTTL is how long the matches of an image that is referenced by digest are reused (nothing is cached when
+	// zero), where images that are referenced by tag are always scanned since the tag may refer to another image
+	CacheTTL time.Duration
+	// DenyOnError denies pods with an image that cannot be scanned (instead of allowing them with a warning)
+	DenyOnError bool
+}
+
+type cachedMatches struct {
+	matches []models.Match
+	expires time.Time
+}
+
+// Webhook is a validating admission webhook that denies pods with images that violate the policy.
+type Webhook struct {
+	scan   Scanner
+	config Config
+	now    func() time.Time
+
+	lock  sync.Mutex
+	cache map[string]cachedMatches
+}
+
+// NewWebhook returns a webhook that scans images with the given scanner.
+func NewWebhook(scan Scanner, config Config) *Webhook {
+	return &Webhook{
+		scan:   scan,
+		config: config,
+		now:    time.Now,
+		cache:  make(map[string]cachedMatches),
+	}
+}
+
+// Handler returns the HTTP handler of the webhook, which reviews pods at /validate.
+func (w *Webhook) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/validate", w.handleValidate)
+	healthy := func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = rw.Write([]byte("ok"))
+	}
+	mux.HandleFunc("/healthz", healthy)
+	mux.HandleFunc("/readyz", healthy)
+	return mux
+}
+
+func (w *Webhook) handleValidate(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(rw, fmt.Sprintf("unsupported method %s", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	var review Review
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil || review.Request == nil {
+		http.Error(rw, "expected an AdmissionReview request", http.StatusBadRequest)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(rw).Encode(Review{
+		APIVersion: reviewAPIVersion,
+		Kind:       reviewKind,
+		Response:   w.Review(review.Request),
+	})
+	if err != nil {
+		log.Warnf("unable to write admission review: %+v", err)
+	}
+}
+
+// Review returns the verdict for the given request, where objects other than pods are always allowed.
+func (w *Webhook) Review(request *Request) *Response {
+	response := &Response{UID: request.UID, Allowed: true}
+	if request.Kind.Kind != "Pod" {
+		return response
+	}
+
+	var p pod
+	if err := json.Unmarshal(request.Object, &p); err != nil {
+		return deny(response, http.StatusBadRequest, fmt.Sprintf("unable to decode pod: %v", err))
+	}
+
+	var denials []string
+	for _, image := range p.images() {
+		matches, err := w.matches(image)
+		if err != nil {
+			log.Warnf("unable to scan image=%q: %+v", image, err)
+			message := fmt.Sprintf("unable to scan image %s: %v", image, err)
+			if w.config.DenyOnError {
+				denials = append(denials, message)
+			} else {
+				response.Warnings = append(response.Warnings, message)
+			}
+			continue
+		}
+
+		if violations := w.config.Policy.Violations(matches); len(violations) > 0 {
+			denials = append(denials, describeViolations(image, violations))
+		}
+	}
+
+	if len(denials) > 0 {
+		log.Infof("denying pod=%s/%s: %s", request.Namespace, request.Name, strings.Join(denials, "; "))
+		return deny(response, http.StatusForbidden, strings.Join(denials, "; "))
+	}
+	return response
+}
+
+// matches returns the matches of the given image (which is always pulled from the registry), where the matches of
+// images that are referenced by digest are reused for the configured TTL.
+func (w *Webhook) matches(image string) ([]models.Match, error) {
+	ref, err := pkg.RegistryImageReference(image)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheable := w.config.CacheTTL > 0 && ref.HasDigest()
+	key := ref.Input()
+	if cacheable {
+		w.lock.Lock()
+		cached, ok := w.cache[key]
+		w.lock.Unlock()
+		if ok && w.now().Before(cached.expires) {
+			return cached.matches, nil
+		}
+	}
+
+	matches, err := w.scan(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheable {
+		now := w.now()
+		w.lock.Lock()
+		for cachedImage, cached := range w.cache {
+			if !now.Before(cached.expires) {
+				delete(w.cache, cachedImage)
+			}
+		}
+		w.cache[key] = cachedMatches{matches: matches, expires: now.Add(w.config.CacheTTL)}
+		w.lock.Unlock()
+	}
+	return matches, nil
+}
+
+func deny(response *Response, code int, message string) *Response {
+	response.Allowed = false
+	response.Result = &Status{Code: code, Message: message}
+	return response
+}
+
+func describeViolations(image string, violations []string) string {
+	description := fmt.Sprintf("image %s violates the vulnerability policy: %s", image, strings.Join(truncate(violations, maxViolations), ", "))
+	if len(violations) > maxViolations {
+		description += fmt.Sprintf(" (and %d more)", len(violations)-maxViolations)
+	}
+	return description
+}
+
+func truncate(values []string, max int) []string {
+	if len(values) > max {
+		return values[:max]
+	}
+	return values
+}
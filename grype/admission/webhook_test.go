@@ -0,0 +1,219 @@
+package admission
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const vulnerableDigest = "vulnerable@sha256:6845222dafaba46394bded167974eaca737ad7988b9a77decbfc2c686ba49480"
+
+type fakeScanner struct {
+	matches map[string][]models.Match
+	scanned map[string]int
+}
+
+func newFakeScanner() *fakeScanner {
+	return &fakeScanner{
+		matches: map[string][]models.Match{
+			"vulnerable:1.0": {newMatch("CVE-2021-0001", "Critical")},
+			vulnerableDigest: {newMatch("CVE-2021-0001", "Critical")},
+			"clean:1.0":      nil,
+		},
+		scanned: make(map[string]int),
+	}
+}
+
+func (s *fakeScanner) scan(image pkg.ImageReference) ([]models.Match, error) {
+	if !strings.HasPrefix(image.Input(), "registry:") {
+		return nil, fmt.Errorf("not a registry image: %s", image.Input())
+	}
+	s.scanned[image.String()]++
+	matches, ok := s.matches[image.String()]
+	if !ok {
+		return nil, errors.New("image not found")
+	}
+	return matches, nil
+}
+
+func podRequest(images ...string) *Request {
+	var containers []map[string]string
+	for i, image := range images {
+		containers = append(containers, map[string]string{"name": fmt.Sprintf("c%d", i), "image": image})
+	}
+	object, _ := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"containers": containers},
+	})
+	return &Request{
+		UID:       "705ab4f5-6393-11e8-b7cc-42010a800002",
+		Kind:      GroupKind{Version: "v1", Kind: "Pod"},
+		Namespace: "default",
+		Name:      "app",
+		Operation: "CREATE",
+		Object:    object,
+	}
+}
+
+func TestWebhook_Review(t *testing.T) {
+	high := vulnerability.HighSeverity
+
+	tests := []struct {
+		name            string
+		config          Config
+		request         *Request
+		allowed         bool
+		code            int
+		expectedWarning bool
+	}{
+		{
+			name:    "clean image",
+			config:  Config{Policy: Policy{FailOnSeverity: &high}},
+			request: podRequest("clean:1.0"),
+			allowed: true,
+		},
+		{
+			name:    "vulnerable image",
+			config:  Config{Policy: Policy{FailOnSeverity: &high}},
+			request: podRequest("clean:1.0", "vulnerable:1.0"),
+			code:    http.StatusForbidden,
+		},
+		{
+			name:    "other kinds are allowed",
+			config:  Config{Policy: Policy{FailOnSeverity: &high}},
+			request: &Request{UID: "1", Kind: GroupKind{Version: "v1", Kind: "ConfigMap"}, Object: json.RawMessage(`{}`)},
+			allowed: true,
+		},
+		{
+			name:    "bad pod",
+			config:  Config{Policy: Policy{FailOnSeverity: &high}},
+			request: &Request{UID: "1", Kind: GroupKind{Version: "v1", Kind: "Pod"}, Object: json.RawMessage(`[]`)},
+			code:    http.StatusBadRequest,
+		},
+		{
+			name:            "scan error allowed with warning",
+			config:          Config{Policy: Policy{FailOnSeverity: &high}},
+			request:         podRequest("missing:1.0"),
+			allowed:         true,
+			expectedWarning: true,
+		},
+		{
+			name:    "images are only pulled from a registry",
+			config:  Config{Policy: Policy{FailOnSeverity: &high}, DenyOnError: true},
+			request: podRequest("dir:/"),
+			code:    http.StatusForbidden,
+		},
+		{
+			name:    "scan error denied",
+			config:  Config{Policy: Policy{FailOnSeverity: &high}, DenyOnError: true},
+			request: podRequest("missing:1.0"),
+			code:    http.StatusForbidden,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			webhook := NewWebhook(newFakeScanner().scan, test.config)
+			response := webhook.Review(test.request)
+
+			assert.Equal(t, test.request.UID, response.UID)
+			assert.Equal(t, test.allowed, response.Allowed)
+			if test.allowed {
+				assert.Nil(t, response.Result)
+			} else {
+				require.NotNil(t, response.Result)
+				assert.Equal(t, test.code, response.Result.Code)
+			}
+			assert.Equal(t, test.expectedWarning, len(response.Warnings) > 0)
+		})
+	}
+}
+
+func TestWebhook_Cache(t *testing.T) {
+	high := vulnerability.HighSeverity
+	scanner := newFakeScanner()
+	webhook := NewWebhook(scanner.scan, Config{Policy: Policy{FailOnSeverity: &high}, CacheTTL: time.Hour})
+	now := time.Date(2021, 11, 1, 0, 0, 0, 0, time.UTC)
+	webhook.now = func() time.Time { return now }
+
+	webhook.Review(podRequest(vulnerableDigest, "clean:1.0"))
+	webhook.Review(podRequest(vulnerableDigest, "clean:1.0"))
+	assert.Equal(t, 1, scanner.scanned[vulnerableDigest])
+	// images referenced by tag may refer to another image at any time, so these are not cached
+	assert.Equal(t, 2, scanner.scanned["clean:1.0"])
+	assert.Len(t, webhook.cache, 1)
+
+	now = now.Add(2 * time.Hour)
+	webhook.Review(podRequest(vulnerableDigest))
+	assert.Equal(t, 2, scanner.scanned[vulnerableDigest])
+	assert.Len(t, webhook.cache, 1)
+
+	// errors are not cached
+	webhook.Review(podRequest("missing:1.0"))
+	webhook.Review(podRequest("missing:1.0"))
+	assert.Equal(t, 2, scanner.scanned["missing:1.0"])
+}
+
+func TestDescribeViolations(t *testing.T) {
+	var violations []string
+	for i := 0; i < maxViolations+2; i++ {
+		violations = append(violations, fmt.Sprintf("CVE-2021-%04d", i))
+	}
+	description := describeViolations("vulnerable:1.0", violations)
+	assert.Contains(t, description, "CVE-2021-0009")
+	assert.NotContains(t, description, "CVE-2021-0010")
+	assert.Contains(t, description, "(and 2 more)")
+}
+
+func TestWebhook_Handler(t *testing.T) {
+	high := vulnerability.HighSeverity
+	server := httptest.NewServer(NewWebhook(newFakeScanner().scan, Config{Policy: Policy{FailOnSeverity: &high}}).Handler())
+	defer server.Close()
+
+	for _, path := range []string{"/healthz", "/readyz"} {
+		resp, err := http.Get(server.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+	}
+
+	resp, err := http.Get(server.URL + "/validate")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	resp, err = http.Post(server.URL+"/validate", "application/json", bytes.NewBufferString(`{"kind": "AdmissionReview"}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	body, err := json.Marshal(Review{
+		APIVersion: reviewAPIVersion,
+		Kind:       reviewKind,
+		Request:    podRequest("vulnerable:1.0"),
+	})
+	require.NoError(t, err)
+	resp, err = http.Post(server.URL+"/validate", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var review Review
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&review))
+	assert.Equal(t, reviewAPIVersion, review.APIVersion)
+	assert.Equal(t, reviewKind, review.Kind)
+	require.NotNil(t, review.Response)
+	assert.False(t, review.Response.Allowed)
+	assert.Contains(t, review.Response.Result.Message, "CVE-2021-0001 (Critical)")
+}
@@ -0,0 +1,137 @@
+package db
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+
+	"github.com/anchore/grype/internal/file"
+	"github.com/anchore/grype/internal/log"
+	"github.com/spf13/afero"
+	"github.com/wagoodman/go-progress"
+)
+
+// gzipMagic are the leading bytes of gzip files (e.g. database archives), which distinguish database archives from
+// bundles (uncompressed tar files).
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ExportBundle downloads the latest database for the supported schema and writes it to a bundle at the given path,
+// which can be imported on hosts without network access (see ImportBundle). A bundle is a tar file that contains:
+//   - the database archive, as published (e.g. "vulnerability-db_v3_2021-11-20T08:14:27Z.tar.gz")
+//   - the signature of the archive (if the archive is signed)
+//   - a snapshot of the listing (listing.json) with only the bundled database, where the URL is the archive file name
+func (c *Curator) ExportBundle(bundlePath string, downloadProgress *progress.Manual) (*ListingEntry, error) {
+	listing, err := c.ListingFromURL()
+	if err != nil {
+		return nil, err
+	}
+	entry := listing.BestUpdate(c.targetSchema)
+	if entry == nil {
+		return nil, fmt.Errorf("no db candidates with correct version available (maybe there is an application update available?)")
+	}
+
+	// note: the temp directory is persisted upon failure to allow for investigation
+	tempDir, err := os.MkdirTemp("", "grype-export")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create db temp dir: %w", err)
+	}
+
+	archivePath, signaturePath, err := c.downloadArchive(entry, tempDir, downloadProgress)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := NewListing(ListingEntry{
+		Built:    entry.Built,
+		Version:  entry.Version,
+		URL:      &url.URL{Path: path.Base(archivePath)},
+		Checksum: entry.Checksum,
+	})
+	listingPath := path.Join(tempDir, ListingFileName)
+	if err := snapshot.Write(listingPath); err != nil {
+		return nil, err
+	}
+
+	files := []string{listingPath, archivePath}
+	if exists, _ := afero.Exists(c.fs, signaturePath); exists {
+		files = append(files, signaturePath)
+	}
+
+	f, err := c.fs.Create(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create bundle (%s): %w", bundlePath, err)
+	}
+	defer f.Close()
+
+	if err := file.Tar(f, files...); err != nil {
+		return nil, fmt.Errorf("unable to write bundle (%s): %w", bundlePath, err)
+	}
+
+	return entry, c.fs.RemoveAll(tempDir)
+}
+
+// ImportBundle imports the database within a bundle created by ExportBundle, verifying the checksum (and the signature,
+// when a signing key is configured) of the bundled archive.
+func (c *Curator) ImportBundle(bundlePath string) (*ListingEntry, error) {
+	// note: the temp directory is persisted upon failure to allow for investigation
+	tempDir, err := os.MkdirTemp("", "grype-bundle")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create db temp dir: %w", err)
+	}
+
+	f, err := c.fs.Open(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open bundle (%s): %w", bundlePath, err)
+	}
+	defer f.Close()
+
+	if err := file.UnTar(tempDir, f); err != nil {
+		return nil, fmt.Errorf("unable to extract bundle (%s): %w", bundlePath, err)
+	}
+
+	listing, err := NewListingFromFile(c.fs, path.Join(tempDir, ListingFileName))
+	if err != nil {
+		return nil, err
+	}
+	entry := listing.BestUpdate(c.targetSchema)
+	if entry == nil {
+		return nil, fmt.Errorf("bundle has no database for schema version %d (maybe there is an application update available?)", c.targetSchema)
+	}
+
+	archivePath := path.Join(tempDir, path.Base(entry.URL.Path))
+	valid, actualHash, err := file.ValidateByHash(c.fs, archivePath, entry.Checksum)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, fmt.Errorf("bad db archive checksum (%s): %q vs %q", archivePath, entry.Checksum, actualHash)
+	}
+
+	current, err := NewMetadataFromDir(c.fs, c.dbDir)
+	if err == nil && !current.IsSupersededBy(entry) {
+		log.Warnf("importing a database that is not newer than the current database (built=%q)", current.Built.String())
+	}
+
+	if err := c.ImportFrom(archivePath); err != nil {
+		return nil, err
+	}
+	return entry, c.fs.RemoveAll(tempDir)
+}
+
+// IsBundle indicates if the given file is a bundle created by ExportBundle (as opposed to a database archive).
+func IsBundle(fs afero.Fs, filePath string) (bool, error) {
+	f, err := fs.Open(filePath)
+	if err != nil {
+		return false, fmt.Errorf("unable to open file (%s): %w", filePath, err)
+	}
+	defer f.Close()
+
+	header := make([]byte, len(gzipMagic))
+	if _, err := io.ReadFull(f, header); err != nil {
+		return false, fmt.Errorf("unable to read file (%s): %w", filePath, err)
+	}
+	return !bytes.Equal(header, gzipMagic), nil
+}
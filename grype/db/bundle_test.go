@@ -0,0 +1,83 @@
+package db
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wagoodman/go-progress"
+)
+
+// pathGetter provides the file contents for each URL path.
+type pathGetter map[string][]byte
+
+func (g pathGetter) GetFile(dst, src string, _ ...*progress.Manual) error {
+	u, err := url.Parse(src)
+	if err != nil {
+		return err
+	}
+	contents, ok := g[u.Path]
+	if !ok {
+		return fmt.Errorf("not found: %s", src)
+	}
+	return os.WriteFile(dst, contents, 0600)
+}
+
+func (g pathGetter) GetToDir(_, src string, _ ...*progress.Manual) error {
+	return fmt.Errorf("unexpected directory download: %s", src)
+}
+
+func TestCuratorBundle(t *testing.T) {
+	key := newTestSigningKey(t)
+	fs := afero.NewOsFs()
+	online := newTestCurator(t, fs, nil, t.TempDir(), "http://localhost/listing.json", false)
+
+	built := time.Date(2021, 10, 21, 8, 0, 0, 0, time.UTC)
+	archive := testArchive(t, Metadata{Built: built, Version: online.targetSchema})
+	archivePath := filepath.Join(t.TempDir(), "vulnerability-db.tar.gz")
+	require.NoError(t, os.WriteFile(archivePath, archive, 0600))
+	entry, err := NewListingEntryFromArchive(fs, Metadata{Built: built, Version: online.targetSchema}, archivePath, mustUrl(url.Parse("http://localhost/databases")))
+	require.NoError(t, err)
+	listingPath := filepath.Join(t.TempDir(), ListingFileName)
+	require.NoError(t, NewListing(entry).Write(listingPath))
+	listing, err := os.ReadFile(listingPath)
+	require.NoError(t, err)
+
+	online.downloader = pathGetter{
+		"/listing.json":                              listing,
+		"/databases/vulnerability-db.tar.gz":         archive,
+		"/databases/vulnerability-db.tar.gz.minisig": key.sign(archive, false, "timestamp:1634804021"),
+	}
+
+	bundlePath := filepath.Join(t.TempDir(), "grype-db.tar")
+	exported, err := online.ExportBundle(bundlePath, &progress.Manual{})
+	require.NoError(t, err)
+	assert.True(t, built.Equal(exported.Built))
+
+	isBundle, err := IsBundle(fs, bundlePath)
+	require.NoError(t, err)
+	assert.True(t, isBundle)
+	isBundle, err = IsBundle(fs, archivePath)
+	require.NoError(t, err)
+	assert.False(t, isBundle)
+
+	// the bundled archive is verified with the signature within the bundle
+	offline := newTestCurator(t, fs, nil, t.TempDir(), "http://localhost/listing.json", false)
+	offline.signingKey, err = ParsePublicKey(key.encodedPublicKey())
+	require.NoError(t, err)
+	offline.requireSignature = true
+
+	imported, err := offline.ImportBundle(bundlePath)
+	require.NoError(t, err)
+	assert.Equal(t, entry.Checksum, imported.Checksum)
+
+	status := offline.Status()
+	require.NoError(t, status.Err)
+	assert.True(t, built.Equal(status.Built))
+}
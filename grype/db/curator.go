@@ -1,21 +1,27 @@
 package db
 
 import (
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
 	"strconv"
+	"time"
 
 	grypeDB "github.com/anchore/grype/grype/db/v3"
 	"github.com/anchore/grype/grype/db/v3/reader"
+	"github.com/anchore/grype/grype/db/v3/writer"
+	"github.com/anchore/grype/grype/distro"
 	"github.com/anchore/grype/grype/event"
 	"github.com/anchore/grype/grype/vulnerability"
 	"github.com/anchore/grype/internal/bus"
 	"github.com/anchore/grype/internal/file"
 	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/grype/internal/proxy"
 	"github.com/hashicorp/go-cleanhttp"
 	"github.com/spf13/afero"
 	"github.com/wagoodman/go-partybus"
@@ -27,10 +33,32 @@ const (
 )
 
 type Config struct {
-	DBRootDir           string
-	ListingURL          string
-	CACert              string
+	DBRootDir  string
+	ListingURL string
+	CACert     string
+	// ClientCert and ClientKey are the paths of the PEM encoded client certificate and key for servers that require
+	// TLS client authentication (e.g. an internal artifact store serving the listing and databases)
+	ClientCert string
+	ClientKey  string
+	// Proxy is the URL of the proxy used for database downloads (when empty the standard proxy environment variables
+	// are used)
+	Proxy               string
 	ValidateByHashOnGet bool
+	// DistroAliases are the rules used to match distros against the vulnerability data of another distro (when nil
+	// the default rules are used)
+	DistroAliases []distro.AliasRule
+	// Overlays are the paths of additional databases to merge with the application database, in order of precedence
+	// (all of which take precedence over the application database)
+	Overlays []string
+	// SigningKey is the minisign public key (or the path to the public key file) that database archives must be signed
+	// with, where signatures are only verified when a key is configured
+	SigningKey string
+	// RequireSignature rejects database archives without a valid signature (instead of only warning about unsigned
+	// archives), which requires a signing key
+	RequireSignature bool
+	// CacheSize is the number of package lookups whose vulnerabilities are kept in memory during a scan (where zero
+	// disables caching)
+	CacheSize int
 }
 
 type Curator struct {
@@ -41,17 +69,27 @@ type Curator struct {
 	dbPath              string
 	listingURL          string
 	validateByHashOnGet bool
+	signingKey          *PublicKey
+	requireSignature    bool
 }
 
 func NewCurator(cfg Config) (Curator, error) {
 	dbDir := path.Join(cfg.DBRootDir, strconv.Itoa(vulnerability.SchemaVersion))
 
 	fs := afero.NewOsFs()
-	httpClient, err := defaultHTTPClient(fs, cfg.CACert)
+	httpClient, err := defaultHTTPClient(fs, cfg)
 	if err != nil {
 		return Curator{}, err
 	}
 
+	signingKey, err := readSigningKey(fs, cfg.SigningKey)
+	if err != nil {
+		return Curator{}, err
+	}
+	if cfg.RequireSignature && signingKey == nil {
+		return Curator{}, fmt.Errorf("a signing key is required to require signed databases")
+	}
+
 	return Curator{
 		fs:                  fs,
 		targetSchema:        vulnerability.SchemaVersion,
@@ -60,9 +98,30 @@ func NewCurator(cfg Config) (Curator, error) {
 		dbPath:              path.Join(dbDir, FileName),
 		listingURL:          cfg.ListingURL,
 		validateByHashOnGet: cfg.ValidateByHashOnGet,
+		signingKey:          signingKey,
+		requireSignature:    cfg.RequireSignature,
 	}, nil
 }
 
+// readSigningKey parses the configured signing key, given either the key itself or the path to a public key file.
+func readSigningKey(fs afero.Fs, value string) (*PublicKey, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if exists, _ := afero.Exists(fs, value); exists {
+		contents, err := afero.ReadFile(fs, value)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read signing key (%s): %w", value, err)
+		}
+		value = string(contents)
+	}
+	key, err := ParsePublicKey(value)
+	if err != nil {
+		return nil, fmt.Errorf("bad signing key: %w", err)
+	}
+	return key, nil
+}
+
 func (c Curator) SupportedSchema() int {
 	return c.targetSchema
 }
@@ -91,13 +150,48 @@ func (c *Curator) Status() Status {
 		}
 	}
 
-	return Status{
+	status := Status{
 		Built:         metadata.Built,
 		SchemaVersion: metadata.Version,
 		Location:      c.dbDir,
 		Checksum:      metadata.Checksum,
 		Err:           c.Validate(),
 	}
+	if status.Err == nil {
+		// providers are informational only, so an unreadable provider table does not invalidate the database
+		providers, err := c.providers()
+		if err != nil {
+			log.Debugf("unable to determine vulnerability database providers: %+v", err)
+		}
+		status.Providers = providers
+	}
+	return status
+}
+
+// Verify checks the integrity of the current database (the checksum of the database file against the metadata), even
+// when the checksum is not validated on each use.
+func (c *Curator) Verify() error {
+	verifier := *c
+	verifier.validateByHashOnGet = true
+	return verifier.Validate()
+}
+
+func (c *Curator) providers() ([]grypeDB.Provider, error) {
+	store, cleanup, err := reader.New(c.dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := cleanup(); err != nil {
+			log.Errorf("unable to close vulnerability database: %+v", err)
+		}
+	}()
+
+	providers, err := store.GetProviders()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read providers: %w", err)
+	}
+	return providers, nil
 }
 
 // Delete removes the DB and metadata file for this specific schema.
@@ -185,6 +279,18 @@ func (c *Curator) IsUpdateAvailable() (bool, *ListingEntry, error) {
 
 // UpdateTo updates the existing DB with the specific other version provided from a listing entry.
 func (c *Curator) UpdateTo(listing *ListingEntry, downloadProgress, importProgress *progress.Manual, stage *progress.Stage) error {
+	if delta := c.deltaFor(listing); delta != nil {
+		err := c.updateByDelta(listing, delta, downloadProgress, stage)
+		if err == nil {
+			stage.Current = "updated"
+			importProgress.N = importProgress.Total
+			importProgress.SetCompleted()
+			return nil
+		}
+		log.Warnf("unable to update vulnerability database with delta, downloading the full database")
+		log.Debugf("delta update failed: %+v", err)
+	}
+
 	stage.Current = "downloading"
 	// note: the temp directory is persisted upon download/validation/activation failure to allow for investigation
 	tempDir, err := c.download(listing, downloadProgress)
@@ -210,6 +316,99 @@ func (c *Curator) UpdateTo(listing *ListingEntry, downloadProgress, importProgre
 	return c.fs.RemoveAll(tempDir)
 }
 
+// deltaFor returns the delta that updates the current database to the given listing entry (if any).
+func (c *Curator) deltaFor(listing *ListingEntry) *DeltaEntry {
+	current, err := NewMetadataFromDir(c.fs, c.dbDir)
+	if err != nil || current == nil || current.Version != listing.Version {
+		return nil
+	}
+	return listing.DeltaFrom(current.Built)
+}
+
+// updateByDelta updates a copy of the current database with the changed namespaces of a delta archive, and then
+// activates the copy.
+func (c *Curator) updateByDelta(listing *ListingEntry, delta *DeltaEntry, downloadProgress *progress.Manual, stage *progress.Stage) error {
+	// the current database must be intact, since it is the base of the update
+	current, err := NewMetadataFromDir(c.fs, c.dbDir)
+	if err != nil {
+		return fmt.Errorf("failed to parse database metadata (%s): %w", c.dbDir, err)
+	}
+	valid, actualHash, err := file.ValidateByHash(c.fs, c.dbPath, current.Checksum)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return fmt.Errorf("bad db checksum (%s): %q vs %q", c.dbPath, current.Checksum, actualHash)
+	}
+
+	stage.Current = "downloading delta"
+	// note: the temp directories are persisted upon failure to allow for investigation
+	deltaDir, err := c.download(&ListingEntry{URL: delta.URL, Checksum: delta.Checksum}, downloadProgress)
+	if err != nil {
+		return err
+	}
+
+	stage.Current = "merging delta"
+	workDir, err := os.MkdirTemp("", "grype-delta")
+	if err != nil {
+		return fmt.Errorf("unable to create db temp dir: %w", err)
+	}
+	if err := file.CopyDir(c.fs, c.dbDir, workDir); err != nil {
+		return fmt.Errorf("unable to copy database: %w", err)
+	}
+
+	workDBPath := path.Join(workDir, FileName)
+	if err := mergeDelta(workDBPath, path.Join(deltaDir, FileName), delta.Namespaces, listing.Built); err != nil {
+		return err
+	}
+
+	hash, err := file.HashFile(c.fs, workDBPath, sha256.New())
+	if err != nil {
+		return err
+	}
+	metadata := Metadata{
+		Built:    listing.Built,
+		Version:  listing.Version,
+		Checksum: "sha256:" + hash,
+	}
+	if err := metadata.Write(metadataPath(workDir)); err != nil {
+		return err
+	}
+
+	stage.Current = "validating"
+	if err := c.validate(workDir); err != nil {
+		return err
+	}
+
+	stage.Current = "importing"
+	if err := c.activate(workDir); err != nil {
+		return err
+	}
+
+	if err := c.fs.RemoveAll(deltaDir); err != nil {
+		return err
+	}
+	return c.fs.RemoveAll(workDir)
+}
+
+// mergeDelta replaces the given namespaces of a database with the records from a delta database.
+func mergeDelta(dbPath, deltaDBPath string, namespaces []string, built time.Time) error {
+	w, cleanup, err := writer.New(dbPath, false)
+	if err != nil {
+		return fmt.Errorf("unable to open vulnerability database: %w", err)
+	}
+	defer func() {
+		if err := cleanup(); err != nil {
+			log.Errorf("unable to close vulnerability database: %+v", err)
+		}
+	}()
+
+	if err := w.ReplaceNamespaces(deltaDBPath, namespaces); err != nil {
+		return fmt.Errorf("unable to merge delta: %w", err)
+	}
+	return w.SetID(grypeDB.NewID(built))
+}
+
 // Validate checks the current database to ensure file integrity and if it can be used by this version of the application.
 func (c *Curator) Validate() error {
 	return c.validate(c.dbDir)
@@ -223,6 +422,14 @@ func (c *Curator) ImportFrom(dbArchivePath string) error {
 		return fmt.Errorf("unable to create db temp dir: %w", err)
 	}
 
+	signature, err := afero.ReadFile(c.fs, dbArchivePath+SignatureExtension)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("unable to read db signature: %w", err)
+	}
+	if err := c.verifySignature(dbArchivePath, signature); err != nil {
+		return err
+	}
+
 	f, err := os.Open(dbArchivePath)
 	if err != nil {
 		return fmt.Errorf("unable to open archive (%s): %w", dbArchivePath, err)
@@ -252,30 +459,222 @@ func (c *Curator) ImportFrom(dbArchivePath string) error {
 	return c.fs.RemoveAll(tempDir)
 }
 
+// AddRecords adds the given vulnerability records to the current database (e.g. records converted from vendor OVAL
+// definitions), returning the number of vulnerabilities that were added. Records that are already present are skipped,
+// so the same records can be added more than once. The checksum within the database metadata is updated to match.
+// Note: added records are not retained when the database is updated.
+func (c *Curator) AddRecords(vulnerabilities []grypeDB.Vulnerability, metadata []grypeDB.VulnerabilityMetadata) (int, error) {
+	if err := c.Validate(); err != nil {
+		return 0, err
+	}
+
+	w, cleanup, err := writer.New(c.dbPath, false)
+	if err != nil {
+		return 0, fmt.Errorf("unable to open vulnerability database: %w", err)
+	}
+	defer func() {
+		if err := cleanup(); err != nil {
+			log.Errorf("unable to close vulnerability database: %+v", err)
+		}
+	}()
+
+	var added int
+	for _, v := range vulnerabilities {
+		existing, err := w.GetVulnerability(v.Namespace, v.PackageName)
+		if err != nil {
+			return added, fmt.Errorf("unable to read existing vulnerabilities: %w", err)
+		}
+		if containsRecord(existing, v) {
+			continue
+		}
+		if err := w.AddVulnerability(v); err != nil {
+			return added, fmt.Errorf("unable to add vulnerability=%q: %w", v.ID, err)
+		}
+		added++
+	}
+
+	for _, m := range metadata {
+		existing, err := w.GetVulnerabilityMetadata(m.ID, m.Namespace)
+		if err != nil {
+			return added, fmt.Errorf("unable to read existing vulnerability metadata: %w", err)
+		}
+		if existing != nil {
+			continue
+		}
+		if err := w.AddVulnerabilityMetadata(m); err != nil {
+			return added, fmt.Errorf("unable to add vulnerability=%q metadata: %w", m.ID, err)
+		}
+	}
+
+	return added, c.updateChecksum()
+}
+
+// RemoveRecords removes the vulnerabilities with the same ID, package and namespace as the given records from the
+// current database (e.g. packages that an advisory states are not affected), returning the number of vulnerabilities
+// that were removed. The checksum within the database metadata is updated to match.
+// Note: removed records are restored when the database is updated.
+func (c *Curator) RemoveRecords(vulnerabilities []grypeDB.Vulnerability) (int, error) {
+	if err := c.Validate(); err != nil {
+		return 0, err
+	}
+
+	w, cleanup, err := writer.New(c.dbPath, false)
+	if err != nil {
+		return 0, fmt.Errorf("unable to open vulnerability database: %w", err)
+	}
+	defer func() {
+		if err := cleanup(); err != nil {
+			log.Errorf("unable to close vulnerability database: %+v", err)
+		}
+	}()
+
+	var removed int
+	for _, v := range vulnerabilities {
+		count, err := w.DeleteVulnerability(v.Namespace, v.PackageName, v.ID)
+		if err != nil {
+			return removed, fmt.Errorf("unable to remove vulnerability=%q: %w", v.ID, err)
+		}
+		removed += int(count)
+	}
+
+	return removed, c.updateChecksum()
+}
+
+func containsRecord(existing []grypeDB.Vulnerability, v grypeDB.Vulnerability) bool {
+	for _, e := range existing {
+		if e.ID == v.ID && e.VersionConstraint == v.VersionConstraint {
+			return true
+		}
+	}
+	return false
+}
+
+// updateChecksum records the checksum of the current database file within the database metadata.
+func (c *Curator) updateChecksum() error {
+	metadata, err := NewMetadataFromDir(c.fs, c.dbDir)
+	if err != nil {
+		return fmt.Errorf("failed to parse database metadata (%s): %w", c.dbDir, err)
+	}
+	if metadata == nil {
+		return fmt.Errorf("database metadata not found: %s", c.dbDir)
+	}
+
+	hash, err := file.HashFile(c.fs, c.dbPath, sha256.New())
+	if err != nil {
+		return err
+	}
+	metadata.Checksum = "sha256:" + hash
+
+	return metadata.Write(metadataPath(c.dbDir))
+}
+
 func (c *Curator) download(listing *ListingEntry, downloadProgress *progress.Manual) (string, error) {
 	tempDir, err := os.MkdirTemp("", "grype-scratch")
 	if err != nil {
 		return "", fmt.Errorf("unable to create db temp dir: %w", err)
 	}
 
-	// download the db to the temp dir
-	url := listing.URL
+	if c.signingKey == nil {
+		// download the db to the temp dir
+		url := listing.URL
 
-	// from go-getter, adding a checksum as a query string will validate the payload after download
-	// note: the checksum query parameter is not sent to the server
-	query := url.Query()
-	query.Add("checksum", listing.Checksum)
-	url.RawQuery = query.Encode()
+		// from go-getter, adding a checksum as a query string will validate the payload after download
+		// note: the checksum query parameter is not sent to the server
+		query := url.Query()
+		query.Add("checksum", listing.Checksum)
+		url.RawQuery = query.Encode()
 
-	// go-getter will automatically extract all files within the archive to the temp dir
-	err = c.downloader.GetToDir(tempDir, listing.URL.String(), downloadProgress)
+		// go-getter will automatically extract all files within the archive to the temp dir
+		err = c.downloader.GetToDir(tempDir, listing.URL.String(), downloadProgress)
+		if err != nil {
+			return "", fmt.Errorf("unable to download db: %w", err)
+		}
+		return tempDir, nil
+	}
+
+	// the archive must be verified before it is extracted
+	archivePath, signaturePath, err := c.downloadArchive(listing, tempDir, downloadProgress)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := c.fs.Open(archivePath)
 	if err != nil {
-		return "", fmt.Errorf("unable to download db: %w", err)
+		return "", fmt.Errorf("unable to open archive (%s): %w", archivePath, err)
 	}
+	defer f.Close()
 
+	if err := file.UnTarGz(tempDir, f); err != nil {
+		return "", err
+	}
+
+	for _, p := range []string{archivePath, signaturePath} {
+		if err := c.fs.RemoveAll(p); err != nil {
+			return "", err
+		}
+	}
 	return tempDir, nil
 }
 
+// downloadArchive downloads a database archive (without extracting it) and its signature (if any) into the given
+// directory, verifying the checksum and the signature of the archive. The path of the signature is returned even
+// when the archive is not signed.
+func (c *Curator) downloadArchive(listing *ListingEntry, dir string, downloadProgress *progress.Manual) (string, string, error) {
+	// the detached signature is published next to the archive
+	signatureURL := *listing.URL
+	signatureURL.Path += SignatureExtension
+
+	// from go-getter, adding a checksum as a query string will validate the payload after download, and disabling
+	// archives prevents extracting the payload
+	// note: these query parameters are not sent to the server
+	archiveURL := *listing.URL
+	query := archiveURL.Query()
+	query.Add("archive", "false")
+	query.Add("checksum", listing.Checksum)
+	archiveURL.RawQuery = query.Encode()
+
+	archivePath := path.Join(dir, path.Base(listing.URL.Path))
+	if err := c.downloader.GetFile(archivePath, archiveURL.String(), downloadProgress); err != nil {
+		return "", "", fmt.Errorf("unable to download db: %w", err)
+	}
+
+	signaturePath := archivePath + SignatureExtension
+	var signature []byte
+	if err := c.downloader.GetFile(signaturePath, signatureURL.String()); err != nil {
+		log.Debugf("unable to download db signature: %+v", err)
+	} else {
+		signature, err = afero.ReadFile(c.fs, signaturePath)
+		if err != nil {
+			return "", "", fmt.Errorf("unable to read db signature: %w", err)
+		}
+	}
+
+	if err := c.verifySignature(archivePath, signature); err != nil {
+		return "", "", err
+	}
+	return archivePath, signaturePath, nil
+}
+
+// verifySignature checks the signature of a database archive (when a signing key is configured), where archives
+// without a signature are only rejected when signatures are required.
+func (c *Curator) verifySignature(archivePath string, signature []byte) error {
+	if c.signingKey == nil {
+		return nil
+	}
+	if len(signature) == 0 {
+		if c.requireSignature {
+			return fmt.Errorf("db archive is not signed (%s)", archivePath)
+		}
+		log.Warnf("db archive is not signed, skipping signature verification")
+		return nil
+	}
+	if err := c.signingKey.Verify(c.fs, archivePath, signature); err != nil {
+		return fmt.Errorf("unable to verify db signature: %w", err)
+	}
+	log.Debugf("verified db signature with key=%s", c.signingKey.ID())
+	return nil
+}
+
 func (c *Curator) validate(dbDirPath string) error {
 	// check that the disk checksum still matches the db payload
 	metadata, err := NewMetadataFromDir(c.fs, dbDirPath)
@@ -354,21 +753,59 @@ func (c Curator) ListingFromURL() (Listing, error) {
 	return listing, nil
 }
 
-func defaultHTTPClient(fs afero.Fs, caCertPath string) (*http.Client, error) {
+func defaultHTTPClient(fs afero.Fs, cfg Config) (*http.Client, error) {
 	httpClient := cleanhttp.DefaultClient()
-	if caCertPath != "" {
-		rootCAs := x509.NewCertPool()
+	transport := httpClient.Transport.(*http.Transport)
+	transport.Proxy = proxy.ForRequest
 
-		pemBytes, err := afero.ReadFile(fs, caCertPath)
-		if err != nil {
-			return nil, fmt.Errorf("unable to configure root CAs for curator: %w", err)
+	if cfg.CACert != "" || cfg.ClientCert != "" || cfg.ClientKey != "" {
+		tlsConfig := &tls.Config{
+			MinVersion: tls.VersionTLS12,
 		}
-		rootCAs.AppendCertsFromPEM(pemBytes)
 
-		httpClient.Transport.(*http.Transport).TLSClientConfig = &tls.Config{
-			MinVersion: tls.VersionTLS12,
-			RootCAs:    rootCAs,
+		if cfg.CACert != "" {
+			rootCAs := x509.NewCertPool()
+
+			pemBytes, err := afero.ReadFile(fs, cfg.CACert)
+			if err != nil {
+				return nil, fmt.Errorf("unable to configure root CAs for curator: %w", err)
+			}
+			rootCAs.AppendCertsFromPEM(pemBytes)
+			tlsConfig.RootCAs = rootCAs
 		}
+
+		if cfg.ClientCert != "" || cfg.ClientKey != "" {
+			cert, err := clientCertificate(fs, cfg.ClientCert, cfg.ClientKey)
+			if err != nil {
+				return nil, fmt.Errorf("unable to configure client certificate for curator: %w", err)
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	if cfg.Proxy != "" {
+		proxyURL, err := url.Parse(cfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("bad proxy URL for curator (%s): %w", cfg.Proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
 	}
 	return httpClient, nil
 }
+
+func clientCertificate(fs afero.Fs, certPath, keyPath string) (tls.Certificate, error) {
+	if certPath == "" || keyPath == "" {
+		return tls.Certificate{}, fmt.Errorf("both a client certificate and key are required")
+	}
+	certPEM, err := afero.ReadFile(fs, certPath)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	keyPEM, err := afero.ReadFile(fs, keyPath)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
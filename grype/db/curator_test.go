@@ -1,7 +1,13 @@
 package db
 
 import (
+	"archive/tar"
 	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,10 +17,14 @@ import (
 	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"syscall"
 	"testing"
 	"time"
 
+	grypeDB "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/db/v3/model"
+	"github.com/anchore/grype/grype/db/v3/writer"
 	"github.com/anchore/grype/internal"
 	"github.com/anchore/grype/internal/file"
 	"github.com/gookit/color"
@@ -75,8 +85,12 @@ func newTestCurator(tb testing.TB, fs afero.Fs, getter file.Getter, dbDir, metad
 
 func Test_defaultHTTPClient(t *testing.T) {
 	tests := []struct {
-		name    string
-		hasCert bool
+		name          string
+		hasCert       bool
+		hasClientCert bool
+		hasClientKey  bool
+		proxy         string
+		err           bool
 	}{
 		{
 			name:    "no custom cert should use default system root certs",
@@ -86,25 +100,66 @@ func Test_defaultHTTPClient(t *testing.T) {
 			name:    "should use single custom cert",
 			hasCert: true,
 		},
+		{
+			name:          "should use client cert",
+			hasCert:       true,
+			hasClientCert: true,
+			hasClientKey:  true,
+		},
+		{
+			name:          "client cert requires a key",
+			hasClientCert: true,
+			err:           true,
+		},
+		{
+			name:  "should use proxy",
+			proxy: "http://proxy.internal:3128",
+		},
 	}
 
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
-			var certPath string
-			if test.hasCert {
-				certPath = generateCertFixture(t)
+			var cfg Config
+			if test.hasCert || test.hasClientCert || test.hasClientKey {
+				certPath := generateCertFixture(t)
+				if test.hasCert {
+					cfg.CACert = certPath
+				}
+				if test.hasClientCert {
+					cfg.ClientCert = certPath
+				}
+				if test.hasClientKey {
+					cfg.ClientKey = strings.TrimSuffix(certPath, ".crt") + ".key"
+				}
 			}
+			cfg.Proxy = test.proxy
 
-			httpClient, err := defaultHTTPClient(afero.NewOsFs(), certPath)
+			httpClient, err := defaultHTTPClient(afero.NewOsFs(), cfg)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
 			require.NoError(t, err)
+			transport := httpClient.Transport.(*http.Transport)
 
 			if test.hasCert {
-				require.NotNil(t, httpClient.Transport.(*http.Transport).TLSClientConfig)
-				assert.Len(t, httpClient.Transport.(*http.Transport).TLSClientConfig.RootCAs.Subjects(), 1)
+				require.NotNil(t, transport.TLSClientConfig)
+				assert.Len(t, transport.TLSClientConfig.RootCAs.Subjects(), 1)
 			} else {
-				assert.Nil(t, httpClient.Transport.(*http.Transport).TLSClientConfig)
+				assert.Nil(t, transport.TLSClientConfig)
 			}
 
+			if test.hasClientCert {
+				assert.Len(t, transport.TLSClientConfig.Certificates, 1)
+			}
+
+			if test.proxy != "" {
+				req, err := http.NewRequest(http.MethodGet, "https://toolbox-data.anchore.io/grype/databases/listing.json", nil)
+				require.NoError(t, err)
+				proxyURL, err := transport.Proxy(req)
+				require.NoError(t, err)
+				assert.Equal(t, test.proxy, proxyURL.String())
+			}
 		})
 	}
 }
@@ -297,3 +352,409 @@ func TestCuratorDBPathHasSchemaVersion(t *testing.T) {
 	assert.Equal(t, path.Join(dbRootPath, strconv.Itoa(cur.targetSchema)), cur.dbDir, "unexpected dir")
 	assert.Contains(t, cur.dbPath, path.Join(dbRootPath, strconv.Itoa(cur.targetSchema)), "unexpected path")
 }
+
+func TestCuratorAddRecords(t *testing.T) {
+	fs := afero.NewOsFs()
+	cur := newTestCurator(t, fs, nil, t.TempDir(), "http://metadata.io", true)
+	require.NoError(t, fs.MkdirAll(cur.dbDir, 0755))
+
+	// an empty database of the supported schema
+	w, cleanup, err := writer.New(cur.dbPath, true)
+	require.NoError(t, err)
+	require.NoError(t, w.AddVulnerability(grypeDB.Vulnerability{ID: "CVE-2020-0001", PackageName: "bash", Namespace: "example:8"}))
+	require.NoError(t, cleanup())
+	require.NoError(t, Metadata{Built: time.Now(), Version: cur.targetSchema}.Write(metadataPath(cur.dbDir)))
+	require.NoError(t, cur.updateChecksum())
+
+	vulnerabilities := []grypeDB.Vulnerability{
+		{ID: "CVE-2020-0001", PackageName: "bash", Namespace: "example:8"},
+		{ID: "EXSA-2021:0001", PackageName: "openssl", Namespace: "example:8", VersionConstraint: "< 1.1.1g", VersionFormat: "rpm"},
+	}
+	metadata := []grypeDB.VulnerabilityMetadata{
+		{ID: "EXSA-2021:0001", Namespace: "example:8", RecordSource: "oval:example:8", Severity: "High"},
+	}
+
+	added, err := cur.AddRecords(vulnerabilities, metadata)
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+
+	// the checksum must match the updated database
+	require.NoError(t, cur.Validate())
+
+	// adding the same records again is a no-op
+	added, err = cur.AddRecords(vulnerabilities, metadata)
+	require.NoError(t, err)
+	assert.Equal(t, 0, added)
+
+	store, err := cur.GetStore()
+	require.NoError(t, err)
+	actual, err := store.GetVulnerability("example:8", "openssl")
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	assert.Equal(t, "EXSA-2021:0001", actual[0].ID)
+
+	actualMetadata, err := store.GetVulnerabilityMetadata("EXSA-2021:0001", "example:8")
+	require.NoError(t, err)
+	require.NotNil(t, actualMetadata)
+	assert.Equal(t, "High", actualMetadata.Severity)
+}
+
+func TestCuratorRemoveRecords(t *testing.T) {
+	fs := afero.NewOsFs()
+	cur := newTestCurator(t, fs, nil, t.TempDir(), "http://metadata.io", true)
+	require.NoError(t, fs.MkdirAll(cur.dbDir, 0755))
+
+	w, cleanup, err := writer.New(cur.dbPath, true)
+	require.NoError(t, err)
+	require.NoError(t, w.AddVulnerability(
+		grypeDB.Vulnerability{ID: "CVE-2020-0001", PackageName: "bash", Namespace: "example:8"},
+		grypeDB.Vulnerability{ID: "CVE-2020-0002", PackageName: "bash", Namespace: "example:8"},
+	))
+	require.NoError(t, cleanup())
+	require.NoError(t, Metadata{Built: time.Now(), Version: cur.targetSchema}.Write(metadataPath(cur.dbDir)))
+	require.NoError(t, cur.updateChecksum())
+
+	removed, err := cur.RemoveRecords([]grypeDB.Vulnerability{
+		{ID: "CVE-2020-0001", PackageName: "bash", Namespace: "example:8"},
+		{ID: "CVE-2020-0001", PackageName: "bash", Namespace: "example:9"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	// the checksum must match the updated database
+	require.NoError(t, cur.Validate())
+
+	store, err := cur.GetStore()
+	require.NoError(t, err)
+	actual, err := store.GetVulnerability("example:8", "bash")
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	assert.Equal(t, "CVE-2020-0002", actual[0].ID)
+}
+
+// deltaGetter provides a delta database for any URL.
+type deltaGetter struct {
+	deltaDBPath string
+	calls       []string
+}
+
+func (g *deltaGetter) GetFile(_, src string, _ ...*progress.Manual) error {
+	g.calls = append(g.calls, src)
+	return fmt.Errorf("unexpected file download: %s", src)
+}
+
+func (g *deltaGetter) GetToDir(dst, src string, _ ...*progress.Manual) error {
+	g.calls = append(g.calls, src)
+	contents, err := os.ReadFile(g.deltaDBPath)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dst, FileName), contents, 0600)
+}
+
+func TestCuratorUpdateToDelta(t *testing.T) {
+	fs := afero.NewOsFs()
+	deltaDBPath := filepath.Join(t.TempDir(), FileName)
+	getter := &deltaGetter{deltaDBPath: deltaDBPath}
+	cur := newTestCurator(t, fs, getter, t.TempDir(), "http://metadata.io", true)
+	require.NoError(t, fs.MkdirAll(cur.dbDir, 0755))
+
+	previous := time.Date(2021, 10, 20, 8, 0, 0, 0, time.UTC)
+	built := time.Date(2021, 10, 21, 8, 0, 0, 0, time.UTC)
+
+	w, cleanup, err := writer.New(cur.dbPath, true)
+	require.NoError(t, err)
+	require.NoError(t, w.SetID(grypeDB.NewID(previous)))
+	require.NoError(t, w.AddVulnerability(
+		grypeDB.Vulnerability{ID: "CVE-2021-0001", PackageName: "bash", Namespace: "debian:10"},
+		grypeDB.Vulnerability{ID: "CVE-2021-0002", PackageName: "bash", Namespace: "alpine:3.14"},
+	))
+	require.NoError(t, cleanup())
+	require.NoError(t, Metadata{Built: previous, Version: cur.targetSchema}.Write(metadataPath(cur.dbDir)))
+	require.NoError(t, cur.updateChecksum())
+
+	w, cleanup, err = writer.New(deltaDBPath, true)
+	require.NoError(t, err)
+	require.NoError(t, w.AddVulnerability(grypeDB.Vulnerability{ID: "CVE-2021-0003", PackageName: "bash", Namespace: "debian:10"}))
+	require.NoError(t, cleanup())
+
+	deltaURL, err := url.Parse("http://localhost/delta.tar.gz")
+	require.NoError(t, err)
+	fullURL, err := url.Parse("http://localhost/full.tar.gz")
+	require.NoError(t, err)
+	listing := &ListingEntry{
+		Built:   built,
+		Version: cur.targetSchema,
+		URL:     fullURL,
+		Deltas: []DeltaEntry{
+			{From: previous.Add(-24 * time.Hour), URL: fullURL},
+			{From: previous, URL: deltaURL, Checksum: "sha256:abc", Namespaces: []string{"debian:10"}},
+		},
+	}
+
+	stage := &progress.Stage{}
+	require.NoError(t, cur.UpdateTo(listing, &progress.Manual{}, &progress.Manual{}, stage))
+	require.Len(t, getter.calls, 1)
+	assert.Contains(t, getter.calls[0], "http://localhost/delta.tar.gz")
+
+	status := cur.Status()
+	require.NoError(t, status.Err)
+	assert.True(t, built.Equal(status.Built))
+
+	store, err := cur.GetStore()
+	require.NoError(t, err)
+	for namespace, expected := range map[string]string{"debian:10": "CVE-2021-0003", "alpine:3.14": "CVE-2021-0002"} {
+		vulns, err := store.GetVulnerability(namespace, "bash")
+		require.NoError(t, err)
+		require.Len(t, vulns, 1)
+		assert.Equal(t, expected, vulns[0].ID)
+	}
+	id, err := store.GetID()
+	require.NoError(t, err)
+	assert.True(t, built.Equal(id.BuildTimestamp))
+
+	// the index of the published database is kept up to date by the delta
+	indexed, err := store.HasPackageIndex()
+	require.NoError(t, err)
+	assert.True(t, indexed)
+	assert.NoError(t, cur.Verify())
+}
+
+func TestCuratorActivateKeepsChecksum(t *testing.T) {
+	fs := afero.NewOsFs()
+	cur := newTestCurator(t, fs, nil, t.TempDir(), "http://metadata.io", true)
+
+	// a published database that is not indexed by package
+	workDir := t.TempDir()
+	w, cleanup, err := writer.New(filepath.Join(workDir, FileName), true)
+	require.NoError(t, err)
+	require.NoError(t, w.AddVulnerability(grypeDB.Vulnerability{ID: "CVE-2021-0001", PackageName: "bash", Namespace: "debian:10"}))
+	require.NoError(t, cleanup())
+	dropPackageIndex(t, filepath.Join(workDir, FileName))
+	hash, err := file.HashFile(fs, filepath.Join(workDir, FileName), sha256.New())
+	require.NoError(t, err)
+	require.NoError(t, Metadata{Built: time.Now(), Version: cur.targetSchema, Checksum: "sha256:" + hash}.Write(metadataPath(workDir)))
+
+	require.NoError(t, cur.activate(workDir))
+
+	// the verified database is activated as is
+	metadata, err := NewMetadataFromDir(fs, cur.dbDir)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:"+hash, metadata.Checksum)
+	assert.NoError(t, cur.Verify())
+
+	store, err := cur.GetStore()
+	require.NoError(t, err)
+	indexed, err := store.HasPackageIndex()
+	require.NoError(t, err)
+	assert.False(t, indexed)
+}
+
+func TestCuratorDeltaFor(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	cur := newTestCurator(t, fs, nil, "/tmp/dbdir", "http://metadata.io", false)
+	built := time.Date(2021, 10, 20, 8, 0, 0, 0, time.UTC)
+
+	listing := &ListingEntry{
+		Version: cur.targetSchema,
+		Deltas:  []DeltaEntry{{From: built}},
+	}
+
+	// no current database
+	assert.Nil(t, cur.deltaFor(listing))
+
+	require.NoError(t, fs.MkdirAll(cur.dbDir, 0755))
+	require.NoError(t, afero.WriteFile(fs, metadataPath(cur.dbDir), []byte(fmt.Sprintf(`{"built": %q, "version": %d, "checksum": "sha256:abc"}`, built.Format(time.RFC3339), cur.targetSchema)), 0600))
+	assert.NotNil(t, cur.deltaFor(listing))
+
+	listing.Deltas[0].From = built.Add(time.Hour)
+	assert.Nil(t, cur.deltaFor(listing))
+
+	listing.Deltas[0].From = built
+	listing.Version = cur.targetSchema + 1
+	assert.Nil(t, cur.deltaFor(listing))
+}
+
+// archiveGetter provides a database archive (and its signature, if any) for any URL.
+type archiveGetter struct {
+	archive   []byte
+	signature []byte
+	calls     []string
+}
+
+func (g *archiveGetter) GetFile(dst, src string, _ ...*progress.Manual) error {
+	g.calls = append(g.calls, src)
+	u, err := url.Parse(src)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(u.Path, SignatureExtension) {
+		if g.signature == nil {
+			return fmt.Errorf("no signature: %s", src)
+		}
+		return os.WriteFile(dst, g.signature, 0600)
+	}
+	return os.WriteFile(dst, g.archive, 0600)
+}
+
+func (g *archiveGetter) GetToDir(_, src string, _ ...*progress.Manual) error {
+	g.calls = append(g.calls, src)
+	return fmt.Errorf("unexpected directory download: %s", src)
+}
+
+// testArchive returns a database archive with the given metadata and a placeholder database file.
+func testArchive(t *testing.T, metadata Metadata) []byte {
+	t.Helper()
+	metadataContents, err := json.Marshal(metadata)
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for name, contents := range map[string][]byte{
+		MetadataFileName: metadataContents,
+		FileName:         []byte("the database"),
+	} {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents))}))
+		_, err := tw.Write(contents)
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestCuratorDownloadSigned(t *testing.T) {
+	key := newTestSigningKey(t)
+	otherKey := newTestSigningKey(t)
+
+	tests := []struct {
+		name             string
+		signingKey       string
+		requireSignature bool
+		signature        func(archive []byte) []byte
+		err              bool
+	}{
+		{
+			name:       "valid signature",
+			signingKey: key.encodedPublicKey(),
+			signature: func(archive []byte) []byte {
+				return key.sign(archive, false, "timestamp:1634804021")
+			},
+		},
+		{
+			name:             "valid signature is required",
+			signingKey:       key.encodedPublicKey(),
+			requireSignature: true,
+			signature: func(archive []byte) []byte {
+				return key.sign(archive, false, "timestamp:1634804021")
+			},
+		},
+		{
+			name:       "signed by another key",
+			signingKey: key.encodedPublicKey(),
+			signature: func(archive []byte) []byte {
+				return otherKey.sign(archive, false, "timestamp:1634804021")
+			},
+			err: true,
+		},
+		{
+			name:       "signature of another archive",
+			signingKey: key.encodedPublicKey(),
+			signature: func([]byte) []byte {
+				return key.sign([]byte("another archive"), false, "timestamp:1634804021")
+			},
+			err: true,
+		},
+		{
+			name:       "unsigned archive",
+			signingKey: key.encodedPublicKey(),
+		},
+		{
+			name:             "unsigned archive when signatures are required",
+			signingKey:       key.encodedPublicKey(),
+			requireSignature: true,
+			err:              true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := afero.NewOsFs()
+			cur := newTestCurator(t, fs, nil, t.TempDir(), "http://metadata.io", false)
+			signingKey, err := ParsePublicKey(test.signingKey)
+			require.NoError(t, err)
+			cur.signingKey = signingKey
+			cur.requireSignature = test.requireSignature
+
+			built := time.Date(2021, 10, 21, 8, 0, 0, 0, time.UTC)
+			getter := &archiveGetter{archive: testArchive(t, Metadata{Built: built, Version: cur.targetSchema})}
+			if test.signature != nil {
+				getter.signature = test.signature(getter.archive)
+			}
+			cur.downloader = getter
+
+			entry := &ListingEntry{
+				Built:    built,
+				Version:  cur.targetSchema,
+				URL:      mustUrl(url.Parse("http://a-url/payload.tar.gz")),
+				Checksum: "sha256:deadbeefcafe",
+			}
+			dir, err := cur.download(entry, &progress.Manual{})
+			require.Len(t, getter.calls, 2)
+			assert.Equal(t, "http://a-url/payload.tar.gz?archive=false&checksum=sha256%3Adeadbeefcafe", getter.calls[0])
+			assert.Equal(t, "http://a-url/payload.tar.gz.minisig", getter.calls[1])
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.NoError(t, cur.validate(dir))
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			var names []string
+			for _, e := range entries {
+				names = append(names, e.Name())
+			}
+			assert.ElementsMatch(t, []string{MetadataFileName, FileName}, names)
+		})
+	}
+}
+
+func TestCuratorImportFromSigned(t *testing.T) {
+	key := newTestSigningKey(t)
+	fs := afero.NewOsFs()
+	cur := newTestCurator(t, fs, nil, t.TempDir(), "http://metadata.io", false)
+	signingKey, err := ParsePublicKey(key.encodedPublicKey())
+	require.NoError(t, err)
+	cur.signingKey = signingKey
+	cur.requireSignature = true
+
+	built := time.Date(2021, 10, 21, 8, 0, 0, 0, time.UTC)
+	archive := testArchive(t, Metadata{Built: built, Version: cur.targetSchema})
+	archivePath := filepath.Join(t.TempDir(), "db.tar.gz")
+	require.NoError(t, os.WriteFile(archivePath, archive, 0600))
+
+	// unsigned archives are rejected
+	assert.Error(t, cur.ImportFrom(archivePath))
+
+	require.NoError(t, os.WriteFile(archivePath+SignatureExtension, key.sign(archive, false, "timestamp:1634804021"), 0600))
+	require.NoError(t, cur.ImportFrom(archivePath))
+
+	status := cur.Status()
+	require.NoError(t, status.Err)
+	assert.True(t, built.Equal(status.Built))
+}
+
+// dropPackageIndex removes the package index from a database (as if it was built before the index was introduced).
+func dropPackageIndex(t *testing.T, dbPath string) {
+	t.Helper()
+	db, err := sql.Open("sqlite3", dbPath)
+	require.NoError(t, err)
+	defer db.Close()
+	for _, table := range []string{model.PackageIndexTableName, model.VulnerabilityNamespaceTableName} {
+		_, err = db.Exec("DROP TABLE " + table)
+		require.NoError(t, err)
+	}
+}
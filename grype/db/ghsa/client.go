@@ -0,0 +1,246 @@
+package ghsa
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/grype/metrics"
+	"github.com/anchore/grype/internal/log"
+)
+
+const DefaultURL = "https://api.github.com/graphql"
+
+// Config controls the (opt-in) lookup of advisories for language packages against the GitHub Advisory Database API at
+// scan time, which covers advisories that were published after the vulnerability database was built.
+type Config struct {
+	Enabled  bool
+	BaseURL  string
+	Token    string // the API requires authentication (any token without scopes is sufficient)
+	Timeout  time.Duration
+	CacheDir string        // where lookup results are kept between runs (no results are kept when empty)
+	CacheTTL time.Duration // how long lookup results are kept before the API is queried again
+}
+
+// the page size is the maximum allowed by the API
+const securityVulnerabilitiesQuery = `query($ecosystem: SecurityAdvisoryEcosystem!, $package: String!, $after: String) {
+  securityVulnerabilities(first: 100, ecosystem: $ecosystem, package: $package, after: $after) {
+    nodes {
+      advisory {
+        ghsaId
+        summary
+        severity
+        permalink
+        withdrawnAt
+        publishedAt
+        updatedAt
+        identifiers { type value }
+        references { url }
+        cwes(first: 10) { nodes { cweId } }
+        cvss { score vectorString }
+      }
+      package { ecosystem name }
+      vulnerableVersionRange
+      firstPatchedVersion { identifier }
+    }
+    pageInfo { hasNextPage endCursor }
+  }
+}`
+
+// securityVulnerability is a vulnerable version range of a package, as described by a GitHub security advisory.
+type securityVulnerability struct {
+	Advisory struct {
+		GhsaID      string     `json:"ghsaId"`
+		Summary     string     `json:"summary"`
+		Severity    string     `json:"severity"`
+		Permalink   string     `json:"permalink"`
+		WithdrawnAt *string    `json:"withdrawnAt"`
+		PublishedAt *time.Time `json:"publishedAt"`
+		UpdatedAt   *time.Time `json:"updatedAt"`
+		Identifiers []struct {
+			Type  string `json:"type"`
+			Value string `json:"value"`
+		} `json:"identifiers"`
+		References []struct {
+			URL string `json:"url"`
+		} `json:"references"`
+		Cwes struct {
+			Nodes []struct {
+				CweID string `json:"cweId"`
+			} `json:"nodes"`
+		} `json:"cwes"`
+		Cvss struct {
+			Score        float64 `json:"score"`
+			VectorString string  `json:"vectorString"`
+		} `json:"cvss"`
+	} `json:"advisory"`
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	VulnerableVersionRange string `json:"vulnerableVersionRange"`
+	FirstPatchedVersion    *struct {
+		Identifier string `json:"identifier"`
+	} `json:"firstPatchedVersion"`
+}
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+type graphQLResponse struct {
+	Data struct {
+		SecurityVulnerabilities struct {
+			Nodes    []securityVulnerability `json:"nodes"`
+			PageInfo struct {
+				HasNextPage bool   `json:"hasNextPage"`
+				EndCursor   string `json:"endCursor"`
+			} `json:"pageInfo"`
+		} `json:"securityVulnerabilities"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+type client struct {
+	config Config
+	http   *http.Client
+}
+
+func newClient(config Config) *client {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultURL
+	}
+	return &client{
+		config: config,
+		http:   &http.Client{Timeout: config.Timeout},
+	}
+}
+
+// securityVulnerabilities returns all vulnerable version ranges of the given package within the given ecosystem (e.g.
+// "NPM"), preferring results that were cached within the TTL.
+func (c *client) securityVulnerabilities(ecosystem, name string) ([]securityVulnerability, error) {
+	result, ok := c.readCache(ecosystem, name)
+	if c.config.CacheDir != "" {
+		metrics.ObserveCache(metrics.GitHubCache, ok)
+	}
+	if ok {
+		return result, nil
+	}
+
+	var after *string
+	for {
+		response, err := c.query(ecosystem, name, after)
+		if err != nil {
+			return nil, err
+		}
+		page := response.Data.SecurityVulnerabilities
+		result = append(result, page.Nodes...)
+		if !page.PageInfo.HasNextPage {
+			break
+		}
+		cursor := page.PageInfo.EndCursor
+		after = &cursor
+	}
+
+	c.writeCache(ecosystem, name, result)
+	return result, nil
+}
+
+func (c *client) query(ecosystem, name string, after *string) (*graphQLResponse, error) {
+	body, err := json.Marshal(graphQLRequest{
+		Query: securityVulnerabilitiesQuery,
+		Variables: map[string]interface{}{
+			"ecosystem": ecosystem,
+			"package":   name,
+			"after":     after,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode GitHub advisory query: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.config.BaseURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create GitHub advisory request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+c.config.Token)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query GitHub advisories: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to query GitHub advisories: HTTP %d", resp.StatusCode)
+	}
+
+	var response graphQLResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("unable to parse GitHub advisory response: %w", err)
+	}
+	if len(response.Errors) > 0 {
+		var messages []string
+		for _, e := range response.Errors {
+			messages = append(messages, e.Message)
+		}
+		return nil, fmt.Errorf("unable to query GitHub advisories: %s", strings.Join(messages, "; "))
+	}
+	return &response, nil
+}
+
+func (c *client) cachePath(ecosystem, name string) string {
+	// package names may contain path separators (e.g. go modules and composer packages)
+	return filepath.Join(c.config.CacheDir, strings.ToLower(ecosystem), url.PathEscape(name)+".json")
+}
+
+func (c *client) readCache(ecosystem, name string) ([]securityVulnerability, bool) {
+	if c.config.CacheDir == "" {
+		return nil, false
+	}
+	path := c.cachePath(ecosystem, name)
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > c.config.CacheTTL {
+		return nil, false
+	}
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var result []securityVulnerability
+	if err := json.Unmarshal(contents, &result); err != nil {
+		log.Debugf("ignoring invalid GitHub advisory cache entry for %s package=%q: %+v", ecosystem, name, err)
+		return nil, false
+	}
+	return result, true
+}
+
+func (c *client) writeCache(ecosystem, name string, result []securityVulnerability) {
+	if c.config.CacheDir == "" {
+		return
+	}
+	contents, err := json.Marshal(result)
+	if err != nil {
+		log.Debugf("unable to encode GitHub advisory cache entry for %s package=%q: %+v", ecosystem, name, err)
+		return
+	}
+	path := c.cachePath(ecosystem, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Debugf("unable to create GitHub advisory cache dir: %+v", err)
+		return
+	}
+	if err := ioutil.WriteFile(path, contents, 0600); err != nil {
+		log.Debugf("unable to write GitHub advisory cache entry for %s package=%q: %+v", ecosystem, name, err)
+	}
+}
@@ -0,0 +1,123 @@
+package ghsa
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer returns a server that responds with the given fixture for each page (by the "after" cursor), and the
+// number of requests made so far.
+func newTestServer(t *testing.T, pages map[string]string) (*httptest.Server, *int) {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "bearer a-token", r.Header.Get("Authorization"))
+
+		var request graphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "NPM", request.Variables["ecosystem"])
+		assert.Equal(t, "lodash", request.Variables["package"])
+
+		after, _ := request.Variables["after"].(string)
+		fixture, ok := pages[after]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		contents, err := ioutil.ReadFile(fixture)
+		require.NoError(t, err)
+		_, _ = w.Write(contents)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+var testPages = map[string]string{
+	"":             "test-fixtures/page-1.json",
+	"Y3Vyc29yOjE=": "test-fixtures/page-2.json",
+}
+
+func TestClient_SecurityVulnerabilities(t *testing.T) {
+	server, requests := newTestServer(t, testPages)
+	c := newClient(Config{BaseURL: server.URL, Token: "a-token", Timeout: time.Second})
+
+	nodes, err := c.securityVulnerabilities("NPM", "lodash")
+	require.NoError(t, err)
+	assert.Equal(t, 2, *requests)
+
+	var ids []string
+	for _, n := range nodes {
+		ids = append(ids, n.Advisory.GhsaID)
+	}
+	assert.Equal(t, []string{"GHSA-p6mc-m468-83gw", "GHSA-withdrawn", "GHSA-new-unfixed"}, ids)
+	assert.Equal(t, ">= 3.7.0, < 4.17.19", nodes[0].VulnerableVersionRange)
+	assert.Equal(t, "4.17.19", nodes[0].FirstPatchedVersion.Identifier)
+	assert.Nil(t, nodes[2].FirstPatchedVersion)
+}
+
+func TestClient_SecurityVulnerabilities_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		status   int
+	}{
+		{
+			name:   "HTTP error",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:     "GraphQL error",
+			status:   http.StatusOK,
+			response: `{"errors": [{"message": "Argument 'ecosystem' has an invalid value"}]}`,
+		},
+		{
+			name:     "invalid response",
+			status:   http.StatusOK,
+			response: `not json`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				_, _ = w.Write([]byte(test.response))
+			}))
+			defer server.Close()
+
+			c := newClient(Config{BaseURL: server.URL, Token: "a-token", Timeout: time.Second})
+			_, err := c.securityVulnerabilities("NPM", "lodash")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestClient_SecurityVulnerabilities_Cache(t *testing.T) {
+	server, requests := newTestServer(t, testPages)
+	config := Config{BaseURL: server.URL, Token: "a-token", Timeout: time.Second, CacheDir: t.TempDir(), CacheTTL: time.Hour}
+
+	first, err := newClient(config).securityVulnerabilities("NPM", "lodash")
+	require.NoError(t, err)
+	assert.Equal(t, 2, *requests)
+
+	// a new client (i.e. a later run) is served from the cache
+	second, err := newClient(config).securityVulnerabilities("NPM", "lodash")
+	require.NoError(t, err)
+	assert.Equal(t, 2, *requests)
+	assert.Equal(t, first, second)
+
+	// expired entries are ignored
+	config.CacheTTL = 0
+	_, err = newClient(config).securityVulnerabilities("NPM", "lodash")
+	require.NoError(t, err)
+	assert.Equal(t, 4, *requests)
+}
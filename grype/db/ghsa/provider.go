@@ -0,0 +1,251 @@
+package ghsa
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	grypeDB "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+var _ vulnerability.Provider = (*Provider)(nil)
+var _ vulnerability.DistroEOLProvider = (*Provider)(nil)
+var _ vulnerability.MetadataProvider = (*Provider)(nil)
+
+// ecosystem is how the packages of a GitHub namespace are identified by the API.
+type ecosystem struct {
+	name   string
+	format version.Format
+}
+
+// ecosystems are the API ecosystems for each GitHub namespace within the vulnerability database.
+var ecosystems = map[string]ecosystem{
+	"github:composer": {name: "COMPOSER", format: version.ComposerFormat},
+	"github:erlang":   {name: "ERLANG", format: version.HexFormat},
+	"github:gem":      {name: "RUBYGEMS", format: version.GemFormat},
+	"github:go":       {name: "GO", format: version.GolangFormat},
+	"github:java":     {name: "MAVEN", format: version.MavenFormat},
+	"github:npm":      {name: "NPM", format: version.NpmFormat},
+	"github:nuget":    {name: "NUGET", format: version.NugetFormat},
+	"github:pub":      {name: "PUB", format: version.SemverRangeFormat},
+	"github:python":   {name: "PIP", format: version.PythonFormat},
+	"github:rust":     {name: "RUST", format: version.SemverRangeFormat},
+	"github:swift":    {name: "SWIFT", format: version.SemanticFormat},
+}
+
+// Provider adds the advisories from the GitHub Advisory Database API to the language vulnerabilities of another
+// provider (typically the vulnerability database). Advisories from the API take precedence over advisories with the
+// same ID from the other provider, since the API is always up-to-date (e.g. for withdrawn advisories or corrected
+// version ranges). When the API cannot be queried, only the vulnerabilities from the other provider are returned.
+type Provider struct {
+	vulnerability.Provider
+	metadataProvider vulnerability.MetadataProvider
+	client           *client
+	lock             sync.Mutex
+	// metadata of the advisories found by the API, by namespace and ID
+	metadata map[string]*vulnerability.Metadata
+	failed   bool
+}
+
+func NewProvider(provider vulnerability.Provider, metadataProvider vulnerability.MetadataProvider, config Config) *Provider {
+	if config.Token == "" {
+		log.Warnf("the GitHub advisory API requires a token, advisories will most likely not be found")
+	}
+	return &Provider{
+		Provider:         provider,
+		metadataProvider: metadataProvider,
+		client:           newClient(config),
+		metadata:         make(map[string]*vulnerability.Metadata),
+	}
+}
+
+func (pr *Provider) GetByLanguage(l syftPkg.Language, p pkg.Package) ([]vulnerability.Vulnerability, error) {
+	vulns, err := pr.Provider.GetByLanguage(l, p)
+	if err != nil {
+		return nil, err
+	}
+
+	pr.lock.Lock()
+	defer pr.lock.Unlock()
+	if pr.failed {
+		return vulns, nil
+	}
+
+	for namespace, namer := range grypeDB.NamespacePackageNamersForLanguage(l) {
+		eco, ok := ecosystems[namespace]
+		if !ok {
+			continue
+		}
+		for _, name := range namer(p) {
+			nodes, err := pr.client.securityVulnerabilities(eco.name, name)
+			if err != nil {
+				// the remaining lookups would most likely fail the same way (e.g. timeouts without network access)
+				log.Warnf("skipping GitHub advisory lookups for the remaining packages: %+v", err)
+				pr.failed = true
+				return vulns, nil
+			}
+
+			vulns, err = pr.merge(vulns, namespace, name, eco, nodes)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return vulns, nil
+}
+
+// merge replaces the vulnerabilities in the given namespace that were found by the API with the API results (and
+// removes withdrawn advisories).
+func (pr *Provider) merge(vulns []vulnerability.Vulnerability, namespace, name string, eco ecosystem, nodes []securityVulnerability) ([]vulnerability.Vulnerability, error) {
+	if len(nodes) == 0 {
+		return vulns, nil
+	}
+
+	found := make(map[string]struct{})
+	for _, node := range nodes {
+		found[node.Advisory.GhsaID] = struct{}{}
+	}
+
+	var result []vulnerability.Vulnerability
+	for _, vuln := range vulns {
+		if _, exists := found[vuln.ID]; exists && vuln.Namespace == namespace {
+			continue
+		}
+		result = append(result, vuln)
+	}
+
+	for _, node := range nodes {
+		if node.Advisory.WithdrawnAt != nil {
+			continue
+		}
+		record, metadata := newRecords(node, namespace, name, eco)
+		vuln, err := vulnerability.NewVulnerability(record)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse GitHub advisory=%q: %w", record.ID, err)
+		}
+		result = append(result, *vuln)
+
+		m, err := vulnerability.NewMetadata(&metadata)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse GitHub advisory=%q metadata: %w", record.ID, err)
+		}
+		pr.metadata[metadataKey(record.ID, namespace)] = m
+	}
+	return result, nil
+}
+
+// GetMetadata returns the metadata of advisories found by the API, or the metadata from the other provider otherwise.
+func (pr *Provider) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	pr.lock.Lock()
+	m, ok := pr.metadata[metadataKey(id, namespace)]
+	pr.lock.Unlock()
+	if ok {
+		return m, nil
+	}
+	return pr.metadataProvider.GetMetadata(id, namespace)
+}
+
+func (pr *Provider) GetDistroEOL(d *distro.Distro) (*time.Time, error) {
+	if eolProvider, ok := pr.Provider.(vulnerability.DistroEOLProvider); ok {
+		return eolProvider.GetDistroEOL(d)
+	}
+	return nil, nil
+}
+
+func metadataKey(id, namespace string) string {
+	return namespace + "/" + id
+}
+
+func newRecords(node securityVulnerability, namespace, name string, eco ecosystem) (grypeDB.Vulnerability, grypeDB.VulnerabilityMetadata) {
+	advisory := node.Advisory
+
+	fix := grypeDB.Fix{State: grypeDB.NotFixedState}
+	if node.FirstPatchedVersion != nil && node.FirstPatchedVersion.Identifier != "" {
+		fix = grypeDB.Fix{
+			Versions: []string{node.FirstPatchedVersion.Identifier},
+			State:    grypeDB.FixedState,
+		}
+	}
+
+	var related []grypeDB.VulnerabilityReference
+	for _, identifier := range advisory.Identifiers {
+		if identifier.Type == "CVE" {
+			related = append(related, grypeDB.VulnerabilityReference{ID: identifier.Value, Namespace: grypeDB.NVDNamespace})
+		}
+	}
+
+	var urls []string
+	for _, ref := range advisory.References {
+		urls = append(urls, ref.URL)
+	}
+
+	var cwes []string
+	for _, cwe := range advisory.Cwes.Nodes {
+		if cwe.CweID != "" {
+			cwes = append(cwes, cwe.CweID)
+		}
+	}
+
+	var cvss []grypeDB.Cvss
+	if advisory.Cvss.VectorString != "" {
+		cvss = append(cvss, grypeDB.Cvss{
+			Metrics: grypeDB.CvssMetrics{BaseScore: advisory.Cvss.Score},
+			Vector:  advisory.Cvss.VectorString,
+			Version: cvssVersion(advisory.Cvss.VectorString),
+		})
+	}
+
+	record := grypeDB.Vulnerability{
+		ID:                     advisory.GhsaID,
+		PackageName:            name,
+		Namespace:              namespace,
+		VersionConstraint:      node.VulnerableVersionRange,
+		VersionFormat:          eco.format.String(),
+		RelatedVulnerabilities: related,
+		Fix:                    fix,
+	}
+	metadata := grypeDB.VulnerabilityMetadata{
+		ID:           advisory.GhsaID,
+		Namespace:    namespace,
+		DataSource:   advisory.Permalink,
+		RecordSource: grypeDB.RecordSource("github", namespace),
+		Severity:     severity(advisory.Severity),
+		URLs:         urls,
+		Description:  advisory.Summary,
+		Cvss:         cvss,
+		Published:    advisory.PublishedAt,
+		Modified:     advisory.UpdatedAt,
+		CWEs:         cwes,
+	}
+	return record, metadata
+}
+
+// cvssVersion returns the CVSS version of the given vector (e.g. "3.1" for "CVSS:3.1/AV:N/..."), where vectors without
+// a version prefix are CVSS 2.0 vectors.
+func cvssVersion(vector string) string {
+	if strings.HasPrefix(vector, "CVSS:") {
+		return strings.SplitN(strings.TrimPrefix(vector, "CVSS:"), "/", 2)[0]
+	}
+	return "2.0"
+}
+
+func severity(value string) string {
+	switch strings.ToUpper(value) {
+	case "CRITICAL":
+		return "Critical"
+	case "HIGH":
+		return "High"
+	case "MODERATE":
+		return "Medium"
+	case "LOW":
+		return "Low"
+	}
+	return "Unknown"
+}
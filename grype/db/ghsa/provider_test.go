@@ -0,0 +1,138 @@
+package ghsa
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type mockProvider struct {
+	vulns    []vulnerability.Vulnerability
+	metadata map[string]*vulnerability.Metadata
+}
+
+func (m *mockProvider) GetByDistro(*distro.Distro, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (m *mockProvider) GetByLanguage(syftPkg.Language, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return m.vulns, nil
+}
+
+func (m *mockProvider) GetByPackageType(syftPkg.Type, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (m *mockProvider) GetByCPE(syftPkg.CPE) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (m *mockProvider) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	return m.metadata[namespace+"/"+id], nil
+}
+
+func mustConstraint(t *testing.T, value string) version.Constraint {
+	t.Helper()
+	c, err := version.GetConstraint(value, version.NpmFormat)
+	require.NoError(t, err)
+	return c
+}
+
+func TestProvider_GetByLanguage(t *testing.T) {
+	server, requests := newTestServer(t, testPages)
+	inner := &mockProvider{
+		vulns: []vulnerability.Vulnerability{
+			// replaced by the API result
+			{ID: "GHSA-p6mc-m468-83gw", Namespace: "github:npm", Constraint: mustConstraint(t, "< 4.17.12")},
+			// removed since the advisory was withdrawn
+			{ID: "GHSA-withdrawn", Namespace: "github:npm", Constraint: mustConstraint(t, "< 5.0.0")},
+			// not known to the API
+			{ID: "GHSA-from-the-db", Namespace: "github:npm", Constraint: mustConstraint(t, "< 1.0.0")},
+			// another namespace
+			{ID: "GHSA-withdrawn", Namespace: "osv:npm", Constraint: mustConstraint(t, "< 5.0.0")},
+		},
+		metadata: map[string]*vulnerability.Metadata{
+			"github:npm/GHSA-from-the-db": {ID: "GHSA-from-the-db", Severity: "Low"},
+		},
+	}
+	pr := NewProvider(inner, inner, Config{BaseURL: server.URL, Token: "a-token", Timeout: time.Second})
+
+	p := pkg.Package{Name: "lodash", Version: "4.17.15", Language: syftPkg.JavaScript, Type: syftPkg.NpmPkg}
+	vulns, err := pr.GetByLanguage(syftPkg.JavaScript, p)
+	require.NoError(t, err)
+	assert.Equal(t, 2, *requests)
+
+	actual := make(map[string]string)
+	for _, v := range vulns {
+		actual[v.Namespace+"/"+v.ID] = v.Constraint.String()
+	}
+	assert.Equal(t, map[string]string{
+		"github:npm/GHSA-from-the-db":    "< 1.0.0 (npm)",
+		"osv:npm/GHSA-withdrawn":         "< 5.0.0 (npm)",
+		"github:npm/GHSA-p6mc-m468-83gw": ">= 3.7.0, < 4.17.19 (npm)",
+		"github:npm/GHSA-new-unfixed":    "<= 4.17.21 (npm)",
+	}, actual)
+
+	for _, v := range vulns {
+		if v.ID == "GHSA-p6mc-m468-83gw" {
+			assert.Equal(t, vulnerability.Fix{Versions: []string{"4.17.19"}, State: "fixed"}, v.Fix)
+			assert.Equal(t, []vulnerability.Reference{{ID: "CVE-2020-8203", Namespace: "nvd"}}, v.RelatedVulnerabilities)
+		}
+	}
+
+	metadata, err := pr.GetMetadata("GHSA-p6mc-m468-83gw", "github:npm")
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	assert.Equal(t, "High", metadata.Severity)
+	assert.Equal(t, "https://github.com/advisories/GHSA-p6mc-m468-83gw", metadata.DataSource)
+	require.Len(t, metadata.Cvss, 1)
+	assert.Equal(t, "3.1", metadata.Cvss[0].Version)
+	assert.Equal(t, 7.4, metadata.Cvss[0].Metrics.BaseScore)
+	require.NotNil(t, metadata.Published)
+	assert.Equal(t, time.Date(2020, 7, 15, 19, 15, 48, 0, time.UTC), *metadata.Published)
+	require.NotNil(t, metadata.Modified)
+	assert.Equal(t, time.Date(2021, 9, 16, 16, 28, 52, 0, time.UTC), *metadata.Modified)
+	assert.Equal(t, []string{"CWE-1321"}, metadata.CWEs)
+
+	metadata, err = pr.GetMetadata("GHSA-new-unfixed", "github:npm")
+	require.NoError(t, err)
+	assert.Equal(t, "Medium", metadata.Severity)
+
+	// metadata for advisories that were not found by the API is from the other provider
+	metadata, err = pr.GetMetadata("GHSA-from-the-db", "github:npm")
+	require.NoError(t, err)
+	assert.Equal(t, "Low", metadata.Severity)
+}
+
+func TestProvider_GetByLanguage_APIFailure(t *testing.T) {
+	server, requests := newTestServer(t, map[string]string{})
+	inner := &mockProvider{
+		vulns: []vulnerability.Vulnerability{
+			{ID: "GHSA-from-the-db", Namespace: "github:npm", Constraint: mustConstraint(t, "< 1.0.0")},
+		},
+	}
+	pr := NewProvider(inner, inner, Config{BaseURL: server.URL, Token: "a-token", Timeout: time.Second})
+
+	p := pkg.Package{Name: "lodash", Version: "4.17.15", Language: syftPkg.JavaScript, Type: syftPkg.NpmPkg}
+	for i := 0; i < 2; i++ {
+		vulns, err := pr.GetByLanguage(syftPkg.JavaScript, p)
+		require.NoError(t, err)
+		assert.Equal(t, inner.vulns, vulns)
+	}
+	// no further lookups are made after the first failure
+	assert.Equal(t, 1, *requests)
+}
+
+func TestCvssVersion(t *testing.T) {
+	assert.Equal(t, "3.1", cvssVersion("CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:N/I:H/A:H"))
+	assert.Equal(t, "3.0", cvssVersion("CVSS:3.0/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"))
+	assert.Equal(t, "2.0", cvssVersion("AV:N/AC:L/Au:N/C:P/I:P/A:P"))
+}
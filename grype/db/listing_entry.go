@@ -20,14 +20,35 @@ type ListingEntry struct {
 	Version  int
 	URL      *url.URL
 	Checksum string
+	// Deltas are smaller archives that update an existing database to this database (optional)
+	Deltas []DeltaEntry
+}
+
+// DeltaEntry describes an archive that updates a database built at a previous time to the database of a listing
+// entry. The archive contains a database file with the complete records of each namespace that changed since the
+// previous database was built (where namespaces without records in the archive were removed).
+type DeltaEntry struct {
+	From       time.Time // RFC 3339
+	URL        *url.URL
+	Checksum   string
+	Namespaces []string
 }
 
 // ListingEntryJSON is a helper struct for converting a ListingEntry into JSON (or parsing from JSON)
 type ListingEntryJSON struct {
-	Built    string `json:"built"`
-	Version  int    `json:"version"`
-	URL      string `json:"url"`
-	Checksum string `json:"checksum"`
+	Built    string           `json:"built"`
+	Version  int              `json:"version"`
+	URL      string           `json:"url"`
+	Checksum string           `json:"checksum"`
+	Deltas   []DeltaEntryJSON `json:"deltas,omitempty"`
+}
+
+// DeltaEntryJSON is a helper struct for converting a DeltaEntry into JSON (or parsing from JSON)
+type DeltaEntryJSON struct {
+	From       string   `json:"from"`
+	URL        string   `json:"url"`
+	Checksum   string   `json:"checksum"`
+	Namespaces []string `json:"namespaces"`
 }
 
 // NewListingEntryFromArchive creates a new ListingEntry based on the metadata from a database flat file.
@@ -61,11 +82,41 @@ func (l ListingEntryJSON) ToListingEntry() (ListingEntry, error) {
 		return ListingEntry{}, fmt.Errorf("cannot parse url (%s): %+v", l.URL, err)
 	}
 
+	var deltas []DeltaEntry
+	for _, d := range l.Deltas {
+		delta, err := d.ToDeltaEntry()
+		if err != nil {
+			return ListingEntry{}, err
+		}
+		deltas = append(deltas, delta)
+	}
+
 	return ListingEntry{
 		Built:    build.UTC(),
 		Version:  l.Version,
 		URL:      u,
 		Checksum: l.Checksum,
+		Deltas:   deltas,
+	}, nil
+}
+
+// ToDeltaEntry converts a DeltaEntryJSON to a DeltaEntry.
+func (d DeltaEntryJSON) ToDeltaEntry() (DeltaEntry, error) {
+	from, err := time.Parse(time.RFC3339, d.From)
+	if err != nil {
+		return DeltaEntry{}, fmt.Errorf("cannot convert delta from time (%s): %+v", d.From, err)
+	}
+
+	u, err := url.Parse(d.URL)
+	if err != nil {
+		return DeltaEntry{}, fmt.Errorf("cannot parse delta url (%s): %+v", d.URL, err)
+	}
+
+	return DeltaEntry{
+		From:       from.UTC(),
+		URL:        u,
+		Checksum:   d.Checksum,
+		Namespaces: d.Namespaces,
 	}, nil
 }
 
@@ -83,14 +134,35 @@ func (l *ListingEntry) UnmarshalJSON(data []byte) error {
 }
 
 func (l *ListingEntry) MarshalJSON() ([]byte, error) {
+	var deltas []DeltaEntryJSON
+	for _, d := range l.Deltas {
+		deltas = append(deltas, DeltaEntryJSON{
+			From:       d.From.Format(time.RFC3339),
+			URL:        d.URL.String(),
+			Checksum:   d.Checksum,
+			Namespaces: d.Namespaces,
+		})
+	}
+
 	return json.Marshal(&ListingEntryJSON{
 		Built:    l.Built.Format(time.RFC3339),
 		Version:  l.Version,
 		Checksum: l.Checksum,
 		URL:      l.URL.String(),
+		Deltas:   deltas,
 	})
 }
 
+// DeltaFrom returns the delta that updates a database built at the given time to this database (if any).
+func (l ListingEntry) DeltaFrom(built time.Time) *DeltaEntry {
+	for idx := range l.Deltas {
+		if l.Deltas[idx].From.Equal(built) {
+			return &l.Deltas[idx]
+		}
+	}
+	return nil
+}
+
 func (l ListingEntry) String() string {
 	return fmt.Sprintf("Listing(url=%s)", l.URL)
 }
@@ -95,6 +95,29 @@ func TestNewListingFromPath(t *testing.T) {
 				},
 			},
 		},
+		{
+			fixture: "test-fixtures/listing-deltas.json",
+			expected: Listing{
+				Available: map[int][]ListingEntry{
+					3: {
+						{
+							Built:    time.Date(2021, 10, 21, 8, 13, 41, 0, time.UTC),
+							URL:      mustUrl(url.Parse("http://localhost:5000/vulnerability-db_v3_2021-10-21T08:13:41Z.tar.gz")),
+							Version:  3,
+							Checksum: "sha256:8c99fb4e516f10b304f026267c2a73a474e2df878a59bf688cfb0f094bfe7a91",
+							Deltas: []DeltaEntry{
+								{
+									From:       time.Date(2021, 10, 20, 8, 13, 41, 0, time.UTC),
+									URL:        mustUrl(url.Parse("http://localhost:5000/vulnerability-db_v3_2021-10-20T08:13:41Z_2021-10-21T08:13:41Z.tar.gz")),
+									Checksum:   "sha256:e20c251202948df7f853ddc812f64826bdcd6a285c839a7c65939e68609dfc6e",
+									Namespaces: []string{"debian:10", "github:npm"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
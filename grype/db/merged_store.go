@@ -0,0 +1,214 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	grypeDB "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/db/v3/reader"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal"
+	"github.com/spf13/afero"
+)
+
+// Store is a read-only vulnerability database.
+type Store interface {
+	grypeDB.VulnerabilityStoreReader
+	grypeDB.VulnerabilityBatchReader
+	grypeDB.VulnerabilityNamespaceReader
+	grypeDB.VulnerabilityPackageIndexReader
+	grypeDB.VulnerabilityMetadataStoreReader
+	grypeDB.DistroEOLStoreReader
+}
+
+var _ Store = (*reader.Reader)(nil)
+var _ Store = (*MergedStore)(nil)
+
+// MergedStore combines several vulnerability databases (e.g. a corporate overlay and the upstream database), where
+// the data of each store takes precedence over the data of the stores that follow it:
+//   - vulnerabilities with the same ID for the same package and namespace are only taken from the first store that
+//     has them (so that an overlay can correct the affected versions or fix of an upstream vulnerability)
+//   - metadata (e.g. the severity) of a vulnerability is taken from the first store that has it
+//   - the end of life of a distro release is taken from the first store that has it
+type MergedStore struct {
+	stores []Store
+}
+
+// NewMergedStore returns a store that combines the given stores, in order of precedence.
+func NewMergedStore(stores ...Store) *MergedStore {
+	return &MergedStore{
+		stores: stores,
+	}
+}
+
+func (s *MergedStore) GetVulnerability(namespace, name string) ([]grypeDB.Vulnerability, error) {
+	var vulnsByStore [][]grypeDB.Vulnerability
+	for _, store := range s.stores {
+		vulns, err := store.GetVulnerability(namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		vulnsByStore = append(vulnsByStore, vulns)
+	}
+	return mergeVulnerabilities(vulnsByStore), nil
+}
+
+func (s *MergedStore) GetVulnerabilitiesByName(namespace string, names []string) (map[string][]grypeDB.Vulnerability, error) {
+	vulnsByName := make(map[string][][]grypeDB.Vulnerability)
+	for _, store := range s.stores {
+		storeVulns, err := store.GetVulnerabilitiesByName(namespace, names)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			vulnsByName[name] = append(vulnsByName[name], storeVulns[name])
+		}
+	}
+
+	result := make(map[string][]grypeDB.Vulnerability)
+	for name, vulnsByStore := range vulnsByName {
+		result[name] = mergeVulnerabilities(vulnsByStore)
+	}
+	return result, nil
+}
+
+// mergeVulnerabilities combines the vulnerabilities of a package from each store (in order of precedence), where
+// vulnerabilities with the same ID are only taken from the first store that has them.
+func mergeVulnerabilities(vulnsByStore [][]grypeDB.Vulnerability) []grypeDB.Vulnerability {
+	var result []grypeDB.Vulnerability
+	ids := internal.NewStringSet()
+	for _, vulns := range vulnsByStore {
+		found := internal.NewStringSet()
+		for _, v := range vulns {
+			if ids.Contains(v.ID) {
+				continue
+			}
+			found.Add(v.ID)
+			result = append(result, v)
+		}
+		for id := range found {
+			ids.Add(id)
+		}
+	}
+	return result
+}
+
+// HasPackageIndex indicates if the vulnerabilities within every store are indexed by package.
+func (s *MergedStore) HasPackageIndex() (bool, error) {
+	for _, store := range s.stores {
+		indexed, err := store.HasPackageIndex()
+		if err != nil || !indexed {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (s *MergedStore) GetVulnerabilityByPackageKey(key string, namespaces []string) ([]grypeDB.Vulnerability, error) {
+	var result []grypeDB.Vulnerability
+	ids := internal.NewStringSet()
+	for _, store := range s.stores {
+		vulns, err := store.GetVulnerabilityByPackageKey(key, namespaces)
+		if err != nil {
+			return nil, err
+		}
+
+		// note: a key may describe several package names (e.g. python package names are normalized), so records are
+		// shadowed by records of the same package within the same namespace
+		found := internal.NewStringSet()
+		for _, v := range vulns {
+			id := v.Namespace + "/" + v.PackageName + "/" + v.ID
+			if ids.Contains(id) {
+				continue
+			}
+			found.Add(id)
+			result = append(result, v)
+		}
+		for id := range found {
+			ids.Add(id)
+		}
+	}
+	return result, nil
+}
+
+func (s *MergedStore) GetVulnerabilityNamespaces() ([]string, error) {
+	var result []string
+	namespaces := internal.NewStringSet()
+	for _, store := range s.stores {
+		storeNamespaces, err := store.GetVulnerabilityNamespaces()
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range storeNamespaces {
+			if namespaces.Contains(n) {
+				continue
+			}
+			namespaces.Add(n)
+			result = append(result, n)
+		}
+	}
+	return result, nil
+}
+
+func (s *MergedStore) GetVulnerabilityMetadata(id, namespace string) (*grypeDB.VulnerabilityMetadata, error) {
+	for _, store := range s.stores {
+		metadata, err := store.GetVulnerabilityMetadata(id, namespace)
+		if err != nil {
+			return nil, err
+		}
+		if metadata != nil {
+			return metadata, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *MergedStore) GetDistroEOL(namespace string) (*grypeDB.DistroEOL, error) {
+	for _, store := range s.stores {
+		eol, err := store.GetDistroEOL(namespace)
+		if err != nil {
+			return nil, err
+		}
+		if eol != nil {
+			return eol, nil
+		}
+	}
+	return nil, nil
+}
+
+// OpenOverlay opens an additional vulnerability database to merge with the application database, given the path to a
+// database directory (containing the database file) or the database file itself. Since overlay databases are managed
+// outside of the application, they are neither updated nor validated by checksum.
+func OpenOverlay(overlayPath string) (*reader.Reader, error) {
+	fs := afero.NewOsFs()
+	info, err := fs.Stat(overlayPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open overlay database (%s): %w", overlayPath, err)
+	}
+
+	dbPath := overlayPath
+	if info.IsDir() {
+		dbPath = path.Join(overlayPath, FileName)
+	}
+	if _, err := fs.Stat(dbPath); os.IsNotExist(err) {
+		return nil, fmt.Errorf("overlay database not found: %s", dbPath)
+	}
+
+	store, _, err := reader.New(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open overlay database (%s): %w", overlayPath, err)
+	}
+
+	id, err := store.GetID()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read overlay database ID (%s): %w", overlayPath, err)
+	}
+	if id == nil {
+		return nil, fmt.Errorf("overlay database has no ID (%s)", overlayPath)
+	}
+	if id.SchemaVersion != vulnerability.SchemaVersion {
+		return nil, fmt.Errorf("unsupported overlay database version (%s): have=%d want=%d", overlayPath, id.SchemaVersion, vulnerability.SchemaVersion)
+	}
+	return store, nil
+}
@@ -0,0 +1,191 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	grypeDB "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/db/v3/writer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticStore is a store that holds the given records.
+type staticStore struct {
+	vulnerabilities []grypeDB.Vulnerability
+	metadata        []grypeDB.VulnerabilityMetadata
+	eols            []grypeDB.DistroEOL
+}
+
+func (s staticStore) GetVulnerability(namespace, name string) ([]grypeDB.Vulnerability, error) {
+	var result []grypeDB.Vulnerability
+	for _, v := range s.vulnerabilities {
+		if v.Namespace == namespace && v.PackageName == name {
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+func (s staticStore) GetVulnerabilitiesByName(namespace string, names []string) (map[string][]grypeDB.Vulnerability, error) {
+	result := make(map[string][]grypeDB.Vulnerability)
+	for _, name := range names {
+		result[name], _ = s.GetVulnerability(namespace, name)
+	}
+	return result, nil
+}
+
+func (s staticStore) HasPackageIndex() (bool, error) {
+	return true, nil
+}
+
+func (s staticStore) GetVulnerabilityByPackageKey(key string, namespaces []string) ([]grypeDB.Vulnerability, error) {
+	var result []grypeDB.Vulnerability
+	for _, v := range s.vulnerabilities {
+		for _, namespace := range namespaces {
+			if v.Namespace == namespace && grypeDB.PackageKey(v.Namespace, v.PackageName) == key {
+				result = append(result, v)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s staticStore) GetVulnerabilityNamespaces() ([]string, error) {
+	var result []string
+	for _, v := range s.vulnerabilities {
+		result = append(result, v.Namespace)
+	}
+	return result, nil
+}
+
+func (s staticStore) GetVulnerabilityMetadata(id, namespace string) (*grypeDB.VulnerabilityMetadata, error) {
+	for _, m := range s.metadata {
+		if m.ID == id && m.Namespace == namespace {
+			return &m, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s staticStore) GetDistroEOL(namespace string) (*grypeDB.DistroEOL, error) {
+	for _, eol := range s.eols {
+		if eol.Namespace == namespace {
+			return &eol, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestMergedStore(t *testing.T) {
+	overlay := staticStore{
+		vulnerabilities: []grypeDB.Vulnerability{
+			{ID: "CVE-2021-0001", Namespace: "debian:10", PackageName: "openssl", VersionConstraint: "< 1.1.1k-1"},
+			{ID: "ACME-2021-0001", Namespace: "debian:10", PackageName: "openssl", VersionConstraint: "< 1.1.1k-2"},
+			{ID: "ACME-2021-0002", Namespace: "acme:1", PackageName: "acme-agent"},
+			{ID: "GHSA-0001", Namespace: "github:python", PackageName: "requests", VersionConstraint: "< 2.20.0"},
+			{ID: "PYSEC-0001", Namespace: "osv:pypi", PackageName: "requests", VersionConstraint: "< 2.20.0"},
+		},
+		metadata: []grypeDB.VulnerabilityMetadata{
+			{ID: "CVE-2021-0001", Namespace: "debian:10", Severity: "Critical"},
+		},
+	}
+	upstream := staticStore{
+		vulnerabilities: []grypeDB.Vulnerability{
+			{ID: "CVE-2021-0001", Namespace: "debian:10", PackageName: "openssl", VersionConstraint: "< 1.1.1d-1"},
+			{ID: "CVE-2021-0001", Namespace: "debian:10", PackageName: "openssl", VersionConstraint: "< 1.1.1e-1"},
+			{ID: "CVE-2021-0002", Namespace: "debian:10", PackageName: "openssl", VersionConstraint: "< 1.1.1f-1"},
+			{ID: "GHSA-0001", Namespace: "github:python", PackageName: "requests", VersionConstraint: "< 2.19.0"},
+			{ID: "GHSA-0002", Namespace: "github:python", PackageName: "requests", VersionConstraint: "< 2.25.0"},
+		},
+		metadata: []grypeDB.VulnerabilityMetadata{
+			{ID: "CVE-2021-0001", Namespace: "debian:10", Severity: "Medium"},
+			{ID: "CVE-2021-0002", Namespace: "debian:10", Severity: "Low"},
+		},
+		eols: []grypeDB.DistroEOL{
+			{Namespace: "debian:10", Date: time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	store := NewMergedStore(overlay, upstream)
+
+	vulns, err := store.GetVulnerability("debian:10", "openssl")
+	require.NoError(t, err)
+	var constraints []string
+	for _, v := range vulns {
+		constraints = append(constraints, v.ID+" "+v.VersionConstraint)
+	}
+	assert.Equal(t, []string{
+		"CVE-2021-0001 < 1.1.1k-1",
+		"ACME-2021-0001 < 1.1.1k-2",
+		"CVE-2021-0002 < 1.1.1f-1",
+	}, constraints)
+
+	vulnsByName, err := store.GetVulnerabilitiesByName("debian:10", []string{"openssl", "bash"})
+	require.NoError(t, err)
+	assert.Equal(t, vulns, vulnsByName["openssl"])
+	assert.Empty(t, vulnsByName["bash"])
+
+	indexed, err := store.HasPackageIndex()
+	require.NoError(t, err)
+	assert.True(t, indexed)
+
+	vulns, err = store.GetVulnerabilityByPackageKey("pypi/requests", []string{"github:python", "osv:pypi"})
+	require.NoError(t, err)
+	constraints = nil
+	for _, v := range vulns {
+		constraints = append(constraints, v.Namespace+" "+v.ID+" "+v.VersionConstraint)
+	}
+	assert.Equal(t, []string{
+		"github:python GHSA-0001 < 2.20.0",
+		"osv:pypi PYSEC-0001 < 2.20.0",
+		"github:python GHSA-0002 < 2.25.0",
+	}, constraints)
+
+	metadata, err := store.GetVulnerabilityMetadata("CVE-2021-0001", "debian:10")
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	assert.Equal(t, "Critical", metadata.Severity)
+
+	metadata, err = store.GetVulnerabilityMetadata("CVE-2021-0002", "debian:10")
+	require.NoError(t, err)
+	require.NotNil(t, metadata)
+	assert.Equal(t, "Low", metadata.Severity)
+
+	metadata, err = store.GetVulnerabilityMetadata("CVE-2021-0003", "debian:10")
+	require.NoError(t, err)
+	assert.Nil(t, metadata)
+
+	namespaces, err := store.GetVulnerabilityNamespaces()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"debian:10", "acme:1", "github:python", "osv:pypi"}, namespaces)
+
+	eol, err := store.GetDistroEOL("debian:10")
+	require.NoError(t, err)
+	require.NotNil(t, eol)
+	assert.Equal(t, 2024, eol.Date.Year())
+}
+
+func TestOpenOverlay(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, FileName)
+	w, cleanup, err := writer.New(dbPath, true)
+	require.NoError(t, err)
+	require.NoError(t, w.SetID(grypeDB.NewID(time.Now())))
+	require.NoError(t, w.AddVulnerability(grypeDB.Vulnerability{ID: "ACME-2021-0001", Namespace: "acme:1", PackageName: "acme-agent"}))
+	require.NoError(t, cleanup())
+
+	for _, overlayPath := range []string{dir, dbPath} {
+		store, err := OpenOverlay(overlayPath)
+		require.NoError(t, err)
+		vulns, err := store.GetVulnerability("acme:1", "acme-agent")
+		require.NoError(t, err)
+		assert.Len(t, vulns, 1)
+	}
+
+	_, err = OpenOverlay(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+
+	_, err = OpenOverlay(t.TempDir())
+	assert.Error(t, err)
+}
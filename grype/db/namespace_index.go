@@ -0,0 +1,45 @@
+package db
+
+import (
+	"sync"
+
+	grypeDB "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/internal"
+)
+
+// namespaceIndex lazily determines (once) which namespaces a store contains.
+type namespaceIndex struct {
+	reader     grypeDB.VulnerabilityStoreReader
+	once       sync.Once
+	namespaces internal.StringSet
+	err        error
+}
+
+func newNamespaceIndex(reader grypeDB.VulnerabilityStoreReader) *namespaceIndex {
+	return &namespaceIndex{
+		reader: reader,
+	}
+}
+
+// contains indicates if the store has vulnerabilities within the given namespace. Stores that are unable to describe
+// their namespaces are considered to contain no namespaces.
+func (i *namespaceIndex) contains(namespace string) (bool, error) {
+	i.once.Do(func() {
+		i.namespaces = internal.NewStringSet()
+
+		namespaceReader, ok := i.reader.(grypeDB.VulnerabilityNamespaceReader)
+		if !ok {
+			return
+		}
+
+		var namespaces []string
+		namespaces, i.err = namespaceReader.GetVulnerabilityNamespaces()
+		for _, n := range namespaces {
+			i.namespaces.Add(n)
+		}
+	})
+	if i.err != nil {
+		return false, i.err
+	}
+	return i.namespaces.Contains(namespace), nil
+}
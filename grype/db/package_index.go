@@ -0,0 +1,38 @@
+package db
+
+import (
+	"sync"
+
+	grypeDB "github.com/anchore/grype/grype/db/v3"
+)
+
+// packageIndex lazily determines (once) if a store indexes vulnerabilities by package.
+type packageIndex struct {
+	reader      grypeDB.VulnerabilityStoreReader
+	once        sync.Once
+	indexReader grypeDB.VulnerabilityPackageIndexReader
+	err         error
+}
+
+func newPackageIndex(reader grypeDB.VulnerabilityStoreReader) *packageIndex {
+	return &packageIndex{
+		reader: reader,
+	}
+}
+
+// get returns the package index of the store, or nil when the store does not index vulnerabilities by package.
+func (i *packageIndex) get() (grypeDB.VulnerabilityPackageIndexReader, error) {
+	i.once.Do(func() {
+		indexReader, ok := i.reader.(grypeDB.VulnerabilityPackageIndexReader)
+		if !ok {
+			return
+		}
+
+		var indexed bool
+		indexed, i.err = indexReader.HasPackageIndex()
+		if indexed {
+			i.indexReader = indexReader
+		}
+	})
+	return i.indexReader, i.err
+}
@@ -0,0 +1,136 @@
+package db
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/afero"
+	"golang.org/x/crypto/blake2b"
+)
+
+const (
+	// SignatureExtension is the suffix of the detached signature of a database archive (e.g.
+	// "vulnerability-db_v3_2021-11-20T08:14:27Z.tar.gz.minisig" for "vulnerability-db_v3_2021-11-20T08:14:27Z.tar.gz").
+	SignatureExtension = ".minisig"
+
+	untrustedCommentPrefix = "untrusted comment:"
+	trustedCommentPrefix   = "trusted comment: "
+)
+
+// minisign signature algorithms: the signature of the file itself (legacy) or of the BLAKE2b-512 hash of the file
+var (
+	legacyAlgorithm = []byte("Ed")
+	hashedAlgorithm = []byte("ED")
+)
+
+// PublicKey is a minisign public key (see https://jedisct1.github.io/minisign/), used to verify the detached signatures
+// of database archives.
+type PublicKey struct {
+	keyID []byte
+	key   ed25519.PublicKey
+}
+
+// ParsePublicKey parses a minisign public key, given either the encoded key (e.g.
+// "RWQf6LRCGA9i53mlYecO4IzT51TGPpvWucNSCh1CBM0QTaLn73Y7GFO3") or the contents of a public key file.
+func ParsePublicKey(value string) (*PublicKey, error) {
+	var encoded string
+	for _, line := range strings.Split(strings.TrimSpace(value), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, untrustedCommentPrefix) {
+			continue
+		}
+		encoded = line
+		break
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode public key: %w", err)
+	}
+	if len(decoded) != 2+8+ed25519.PublicKeySize || !bytes.Equal(decoded[:2], legacyAlgorithm) {
+		return nil, fmt.Errorf("invalid public key: not an ed25519 minisign key")
+	}
+
+	return &PublicKey{
+		keyID: decoded[2:10],
+		key:   decoded[10:],
+	}, nil
+}
+
+// ID returns the ID of the key, as shown by minisign.
+func (k PublicKey) ID() string {
+	return fmt.Sprintf("%016X", binary.LittleEndian.Uint64(k.keyID))
+}
+
+// Verify checks that the given minisign signature was created by this key for the given file, including the trusted
+// comment of the signature.
+func (k PublicKey) Verify(fs afero.Fs, filePath string, signature []byte) error {
+	lines := strings.Split(strings.TrimSpace(string(signature)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], untrustedCommentPrefix) || !strings.HasPrefix(lines[2], trustedCommentPrefix) {
+		return fmt.Errorf("invalid signature: unexpected format")
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil {
+		return fmt.Errorf("unable to decode signature: %w", err)
+	}
+	if len(sig) != 2+8+ed25519.SignatureSize {
+		return fmt.Errorf("invalid signature: unexpected length")
+	}
+	algorithm, keyID, fileSig := sig[:2], sig[2:10], sig[10:]
+	if !bytes.Equal(keyID, k.keyID) {
+		return fmt.Errorf("signature was not created by key=%s (key=%016X)", k.ID(), binary.LittleEndian.Uint64(keyID))
+	}
+
+	message, err := signedMessage(fs, filePath, algorithm)
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(k.key, message, fileSig) {
+		return fmt.Errorf("invalid signature for file=%q", filePath)
+	}
+
+	// the trusted comment (e.g. the timestamp of the signature) is signed together with the file signature
+	globalSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil {
+		return fmt.Errorf("unable to decode trusted comment signature: %w", err)
+	}
+	trustedComment := strings.TrimSuffix(strings.TrimPrefix(lines[2], trustedCommentPrefix), "\r")
+	if !ed25519.Verify(k.key, append(append([]byte{}, fileSig...), trustedComment...), globalSig) {
+		return fmt.Errorf("invalid trusted comment signature for file=%q", filePath)
+	}
+	return nil
+}
+
+// signedMessage returns the message that is signed by the given algorithm for a file.
+func signedMessage(fs afero.Fs, filePath string, algorithm []byte) ([]byte, error) {
+	f, err := fs.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open file (%s): %w", filePath, err)
+	}
+	defer f.Close()
+
+	switch {
+	case bytes.Equal(algorithm, legacyAlgorithm):
+		message, err := io.ReadAll(f)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read file (%s): %w", filePath, err)
+		}
+		return message, nil
+	case bytes.Equal(algorithm, hashedAlgorithm):
+		hasher, err := blake2b.New512(nil)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(hasher, f); err != nil {
+			return nil, fmt.Errorf("unable to hash file (%s): %w", filePath, err)
+		}
+		return hasher.Sum(nil), nil
+	}
+	return nil, fmt.Errorf("unsupported signature algorithm %q", algorithm)
+}
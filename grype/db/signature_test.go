@@ -0,0 +1,169 @@
+package db
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+// testSigningKey is a minisign key pair for signing test files.
+type testSigningKey struct {
+	keyID   []byte
+	private ed25519.PrivateKey
+	public  ed25519.PublicKey
+}
+
+func newTestSigningKey(t *testing.T) testSigningKey {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keyID := make([]byte, 8)
+	_, err = rand.Read(keyID)
+	require.NoError(t, err)
+	return testSigningKey{keyID: keyID, private: private, public: public}
+}
+
+// encodedPublicKey returns the contents of the minisign public key file.
+func (k testSigningKey) encodedPublicKey() string {
+	key := append(append([]byte("Ed"), k.keyID...), k.public...)
+	return fmt.Sprintf("untrusted comment: minisign public key\n%s\n", base64.StdEncoding.EncodeToString(key))
+}
+
+// sign returns the minisign signature of the given contents, prehashed unless the legacy algorithm is requested.
+func (k testSigningKey) sign(contents []byte, legacy bool, trustedComment string) []byte {
+	algorithm := "ED"
+	message := contents
+	if legacy {
+		algorithm = "Ed"
+	} else {
+		hash := blake2b.Sum512(contents)
+		message = hash[:]
+	}
+	sig := ed25519.Sign(k.private, message)
+	globalSig := ed25519.Sign(k.private, append(append([]byte{}, sig...), trustedComment...))
+	encoded := append(append([]byte(algorithm), k.keyID...), sig...)
+	return []byte(fmt.Sprintf("untrusted comment: signature from minisign secret key\n%s\ntrusted comment: %s\n%s\n",
+		base64.StdEncoding.EncodeToString(encoded), trustedComment, base64.StdEncoding.EncodeToString(globalSig)))
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key := newTestSigningKey(t)
+
+	tests := []struct {
+		name  string
+		value string
+		err   bool
+	}{
+		{
+			name:  "public key file",
+			value: key.encodedPublicKey(),
+		},
+		{
+			name:  "encoded key",
+			value: base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), key.keyID...), key.public...)),
+		},
+		{
+			name:  "not base64",
+			value: "not a key!",
+			err:   true,
+		},
+		{
+			name:  "unsupported algorithm",
+			value: base64.StdEncoding.EncodeToString(append(append([]byte("Xx"), key.keyID...), key.public...)),
+			err:   true,
+		},
+		{
+			name:  "truncated key",
+			value: base64.StdEncoding.EncodeToString(append([]byte("Ed"), key.keyID...)),
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ParsePublicKey(test.value)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, key.keyID, actual.keyID)
+			assert.Equal(t, key.public, actual.key)
+		})
+	}
+}
+
+func TestPublicKeyVerify(t *testing.T) {
+	key := newTestSigningKey(t)
+	otherKey := newTestSigningKey(t)
+	contents := []byte("the database archive")
+
+	publicKey, err := ParsePublicKey(key.encodedPublicKey())
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		contents  []byte
+		signature []byte
+		err       bool
+	}{
+		{
+			name:      "prehashed signature",
+			contents:  contents,
+			signature: key.sign(contents, false, "timestamp:1634804021"),
+		},
+		{
+			name:      "legacy signature",
+			contents:  contents,
+			signature: key.sign(contents, true, "timestamp:1634804021"),
+		},
+		{
+			name:      "modified file",
+			contents:  []byte("a malicious database archive"),
+			signature: key.sign(contents, false, "timestamp:1634804021"),
+			err:       true,
+		},
+		{
+			name:      "signed by another key",
+			contents:  contents,
+			signature: otherKey.sign(contents, false, "timestamp:1634804021"),
+			err:       true,
+		},
+		{
+			name:      "modified trusted comment",
+			contents:  contents,
+			signature: bytes.Replace(key.sign(contents, false, "timestamp:1634804021"), []byte("1634804021"), []byte("1734804021"), 1),
+			err:       true,
+		},
+		{
+			name:      "not a signature",
+			contents:  contents,
+			signature: []byte("not a signature"),
+			err:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fs := afero.NewMemMapFs()
+			filePath := filepath.Join("/archives", "db.tar.gz")
+			require.NoError(t, afero.WriteFile(fs, filePath, test.contents, 0644))
+
+			err := publicKey.Verify(fs, filePath, test.signature)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -1,11 +1,23 @@
 package db
 
-import "time"
+import (
+	"time"
+
+	grypeDB "github.com/anchore/grype/grype/db/v3"
+)
 
 type Status struct {
 	Built         time.Time `json:"built"`
 	SchemaVersion int       `json:"schemaVersion"`
 	Location      string    `json:"location"`
 	Checksum      string    `json:"checksum"`
-	Err           error     `json:"error"`
+	// Providers are the upstream sources of the vulnerability data and when each was last retrieved (when tracked by
+	// the database)
+	Providers []grypeDB.Provider `json:"providers,omitempty"`
+	Err       error              `json:"error"`
+}
+
+// IsStale indicates if the database was built longer than the given max age ago (where zero allows any age).
+func (s Status) IsStale(maxAge time.Duration, now time.Time) bool {
+	return maxAge > 0 && now.Sub(s.Built) > maxAge
 }
@@ -0,0 +1,409 @@
+package csaf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	v3 "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/pkg"
+)
+
+// document is the subset of a CSAF 2.0 document (see https://docs.oasis-open.org/csaf/csaf/v2.0/csaf-v2.0.html) that
+// describes the affectedness of packages by each vulnerability.
+type document struct {
+	Document struct {
+		Title             string `json:"title"`
+		AggregateSeverity struct {
+			Text string `json:"text"`
+		} `json:"aggregate_severity"`
+		References []reference `json:"references"`
+		Tracking   struct {
+			InitialReleaseDate string `json:"initial_release_date"`
+			CurrentReleaseDate string `json:"current_release_date"`
+		} `json:"tracking"`
+	} `json:"document"`
+	ProductTree struct {
+		Branches         []branch      `json:"branches"`
+		FullProductNames []productName `json:"full_product_names"`
+		Relationships    []struct {
+			ProductReference string      `json:"product_reference"`
+			FullProductName  productName `json:"full_product_name"`
+		} `json:"relationships"`
+	} `json:"product_tree"`
+	Vulnerabilities []vulnerability `json:"vulnerabilities"`
+}
+
+type reference struct {
+	URL      string `json:"url"`
+	Category string `json:"category"`
+}
+
+type branch struct {
+	Branches []branch    `json:"branches"`
+	Product  productName `json:"product"`
+}
+
+type productName struct {
+	ProductID                   string `json:"product_id"`
+	Name                        string `json:"name"`
+	ProductIdentificationHelper struct {
+		PURL string `json:"purl"`
+	} `json:"product_identification_helper"`
+}
+
+type vulnerability struct {
+	CVE         string `json:"cve"`
+	ReleaseDate string `json:"release_date"`
+	CWE         *struct {
+		ID string `json:"id"`
+	} `json:"cwe"`
+	IDs []struct {
+		Text string `json:"text"`
+	} `json:"ids"`
+	Notes []struct {
+		Category string `json:"category"`
+		Text     string `json:"text"`
+	} `json:"notes"`
+	ProductStatus struct {
+		FirstFixed       []string `json:"first_fixed"`
+		Fixed            []string `json:"fixed"`
+		KnownAffected    []string `json:"known_affected"`
+		KnownNotAffected []string `json:"known_not_affected"`
+	} `json:"product_status"`
+	Remediations []struct {
+		Category   string   `json:"category"`
+		ProductIDs []string `json:"product_ids"`
+	} `json:"remediations"`
+	Threats []struct {
+		Category string `json:"category"`
+		Details  string `json:"details"`
+	} `json:"threats"`
+	Scores []struct {
+		CvssV2 *cvss `json:"cvss_v2"`
+		CvssV3 *cvss `json:"cvss_v3"`
+	} `json:"scores"`
+	References []reference `json:"references"`
+}
+
+type cvss struct {
+	Version      string  `json:"version"`
+	VectorString string  `json:"vectorString"`
+	BaseScore    float64 `json:"baseScore"`
+}
+
+// Records are the vulnerability records described by CSAF documents.
+type Records struct {
+	Vulnerabilities []v3.Vulnerability
+	Metadata        []v3.VulnerabilityMetadata
+	// NotAffected are the packages that are stated to not be affected by a vulnerability, which supersede any other
+	// records for the same vulnerability and package
+	NotAffected []v3.Vulnerability
+}
+
+// versionFormats are the version formats of the packages for each package URL type.
+var versionFormats = map[string]string{
+	"apk": "apk",
+	"deb": "dpkg",
+	"rpm": "rpm",
+}
+
+// component is a package identified by a product within the product tree.
+type component struct {
+	name    string
+	version string
+	format  string
+}
+
+// Transform converts the vulnerabilities within the given CSAF document into vulnerability records within the given
+// namespace. Products are only considered when they identify an OS package by package URL (e.g.
+// "pkg:rpm/redhat/openssl@1.1.1g-15.el8_3?epoch=1"), so the document is expected to only describe the products of the
+// distro release that the namespace refers to.
+func Transform(reader io.Reader, namespace string) (*Records, error) {
+	var doc document
+	if err := json.NewDecoder(reader).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("unable to parse CSAF document: %w", err)
+	}
+
+	components := doc.components()
+
+	records := &Records{}
+	for _, vuln := range doc.Vulnerabilities {
+		id := vuln.id()
+		if id == "" {
+			continue
+		}
+
+		vulnerabilities, notAffected := vuln.records(id, namespace, components)
+		records.NotAffected = append(records.NotAffected, notAffected...)
+		if len(vulnerabilities) == 0 {
+			continue
+		}
+		records.Vulnerabilities = append(records.Vulnerabilities, vulnerabilities...)
+		records.Metadata = append(records.Metadata, vuln.metadata(id, namespace, doc))
+	}
+	return records, nil
+}
+
+// components returns the OS packages identified by each product within the product tree, including products that are
+// the combination of a package and another product (e.g. "openssl as a component of Red Hat Enterprise Linux 8").
+func (doc document) components() map[string]component {
+	products := make(map[string]component)
+	add := func(p productName) {
+		purl := p.ProductIdentificationHelper.PURL
+		format, ok := versionFormats[pkg.PURLType(purl)]
+		if !ok {
+			return
+		}
+		name, version, qualifiers := pkg.PURLPackage(purl)
+		if name == "" {
+			return
+		}
+		if epoch := qualifiers["epoch"]; epoch != "" && version != "" {
+			version = epoch + ":" + version
+		}
+		products[p.ProductID] = component{name: name, version: version, format: format}
+	}
+
+	var walk func([]branch)
+	walk = func(branches []branch) {
+		for _, b := range branches {
+			add(b.Product)
+			walk(b.Branches)
+		}
+	}
+	walk(doc.ProductTree.Branches)
+	for _, p := range doc.ProductTree.FullProductNames {
+		add(p)
+	}
+
+	for _, r := range doc.ProductTree.Relationships {
+		if c, ok := products[r.ProductReference]; ok {
+			products[r.FullProductName.ProductID] = c
+		}
+	}
+	return products
+}
+
+func (v vulnerability) id() string {
+	if v.CVE != "" {
+		return v.CVE
+	}
+	if len(v.IDs) > 0 {
+		return v.IDs[0].Text
+	}
+	return ""
+}
+
+// records returns the affected and unaffected packages of the vulnerability. Since the document may describe several
+// builds of the same package (e.g. for each architecture), a fixed package takes precedence over an affected package,
+// which takes precedence over an unaffected package.
+func (v vulnerability) records(id, namespace string, components map[string]component) ([]v3.Vulnerability, []v3.Vulnerability) {
+	var vulnerabilities []v3.Vulnerability
+	fixed := make(map[string]struct{})
+	affected := make(map[string]struct{})
+	seen := make(map[string]struct{})
+	add := func(c component, constraint string, fix v3.Fix) {
+		affected[c.name] = struct{}{}
+		key := c.name + constraint
+		if _, exists := seen[key]; exists {
+			return
+		}
+		seen[key] = struct{}{}
+		vulnerabilities = append(vulnerabilities, v3.Vulnerability{
+			ID:                id,
+			PackageName:       c.name,
+			Namespace:         namespace,
+			VersionConstraint: constraint,
+			VersionFormat:     c.format,
+			CPEs:              []string{},
+			Fix:               fix,
+			Advisories:        []v3.Advisory{},
+		})
+	}
+
+	for _, productID := range append(v.ProductStatus.FirstFixed, v.ProductStatus.Fixed...) {
+		c, ok := components[productID]
+		if !ok || c.version == "" {
+			continue
+		}
+		fixed[c.name] = struct{}{}
+		add(c, fmt.Sprintf("< %s", c.version), v3.Fix{Versions: []string{c.version}, State: v3.FixedState})
+	}
+
+	for _, productID := range v.ProductStatus.KnownAffected {
+		c, ok := components[productID]
+		if !ok {
+			continue
+		}
+		if _, exists := fixed[c.name]; exists {
+			continue
+		}
+		add(c, "", v3.Fix{State: v.fixState(productID)})
+	}
+
+	var notAffected []v3.Vulnerability
+	for _, productID := range v.ProductStatus.KnownNotAffected {
+		c, ok := components[productID]
+		if !ok {
+			continue
+		}
+		if _, exists := affected[c.name]; exists {
+			continue
+		}
+		affected[c.name] = struct{}{}
+		notAffected = append(notAffected, v3.Vulnerability{
+			ID:          id,
+			PackageName: c.name,
+			Namespace:   namespace,
+		})
+	}
+
+	return vulnerabilities, notAffected
+}
+
+// fixState returns the fix state of an affected product according to the remediations of the vulnerability.
+func (v vulnerability) fixState(productID string) v3.FixState {
+	for _, r := range v.Remediations {
+		if r.Category != "no_fix_planned" {
+			continue
+		}
+		for _, id := range r.ProductIDs {
+			if id == productID {
+				return v3.WontFixState
+			}
+		}
+	}
+	return v3.NotFixedState
+}
+
+func (v vulnerability) metadata(id, namespace string, doc document) v3.VulnerabilityMetadata {
+	var urls []string
+	var dataSource string
+	for _, ref := range v.References {
+		if ref.URL == "" {
+			continue
+		}
+		urls = append(urls, ref.URL)
+		if ref.Category == "self" && dataSource == "" {
+			dataSource = ref.URL
+		}
+	}
+	if dataSource == "" {
+		for _, ref := range doc.Document.References {
+			if ref.Category == "self" {
+				dataSource = ref.URL
+				break
+			}
+		}
+	}
+	if dataSource == "" && len(urls) > 0 {
+		dataSource = urls[0]
+	}
+
+	value := doc.Document.AggregateSeverity.Text
+	for _, threat := range v.Threats {
+		if threat.Category == "impact" && threat.Details != "" {
+			value = threat.Details
+			break
+		}
+	}
+
+	var exploitation []string
+	for _, threat := range v.Threats {
+		if threat.Category == "exploit_status" && strings.TrimSpace(threat.Details) != "" {
+			exploitation = append(exploitation, strings.TrimSpace(threat.Details))
+		}
+	}
+
+	var cwes []string
+	if v.CWE != nil && v.CWE.ID != "" {
+		cwes = []string{v.CWE.ID}
+	}
+
+	var scores []v3.Cvss
+	seen := make(map[string]struct{})
+	for _, score := range v.Scores {
+		for _, s := range []*cvss{score.CvssV3, score.CvssV2} {
+			if s == nil || s.VectorString == "" {
+				continue
+			}
+			// the same score is often repeated for each group of products
+			if _, exists := seen[s.VectorString]; exists {
+				continue
+			}
+			seen[s.VectorString] = struct{}{}
+			scores = append(scores, v3.Cvss{
+				Metrics: v3.CvssMetrics{BaseScore: s.BaseScore},
+				Vector:  s.VectorString,
+				Version: s.Version,
+			})
+		}
+	}
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Version > scores[j].Version
+	})
+	if scores == nil {
+		scores = []v3.Cvss{}
+	}
+
+	return v3.VulnerabilityMetadata{
+		ID:           id,
+		Namespace:    namespace,
+		DataSource:   dataSource,
+		RecordSource: v3.RecordSource("csaf", namespace),
+		Severity:     severity(value),
+		URLs:         urls,
+		Description:  v.description(doc),
+		Cvss:         scores,
+		Published:    firstTimestamp(v.ReleaseDate, doc.Document.Tracking.InitialReleaseDate),
+		Modified:     firstTimestamp(doc.Document.Tracking.CurrentReleaseDate),
+		CWEs:         cwes,
+		Exploitation: strings.Join(exploitation, "; "),
+	}
+}
+
+// firstTimestamp returns the first of the given timestamps that is a valid RFC3339 timestamp (or nil when there is
+// none).
+func firstTimestamp(values ...string) *time.Time {
+	for _, value := range values {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
+// description returns the description of the vulnerability, falling back to the summary of the vulnerability or the
+// title of the document.
+func (v vulnerability) description(doc document) string {
+	for _, category := range []string{"description", "summary"} {
+		for _, note := range v.Notes {
+			if note.Category == category && strings.TrimSpace(note.Text) != "" {
+				return strings.TrimSpace(note.Text)
+			}
+		}
+	}
+	return strings.TrimSpace(doc.Document.Title)
+}
+
+// severity normalizes the severity of a vulnerability, where vendors use different scales (e.g. "Important" for Red
+// Hat).
+func severity(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "critical":
+		return "Critical"
+	case "important", "high":
+		return "High"
+	case "moderate", "medium":
+		return "Medium"
+	case "low":
+		return "Low"
+	case "negligible":
+		return "Negligible"
+	}
+	return "Unknown"
+}
@@ -0,0 +1,127 @@
+package csaf
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	v3 "github.com/anchore/grype/grype/db/v3"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransform(t *testing.T) {
+	f, err := os.Open("test-fixtures/rhsa-2021_1024.json")
+	require.NoError(t, err)
+	defer f.Close()
+
+	records, err := Transform(f, "rhel:8")
+	require.NoError(t, err)
+
+	fixed := v3.Fix{
+		Versions: []string{"1:1.1.1g-15.el8_3"},
+		State:    v3.FixedState,
+	}
+	expectedVulnerabilities := []v3.Vulnerability{
+		{
+			ID:                "CVE-2021-3449",
+			PackageName:       "openssl",
+			Namespace:         "rhel:8",
+			VersionConstraint: "< 1:1.1.1g-15.el8_3",
+			VersionFormat:     "rpm",
+			CPEs:              []string{},
+			Fix:               fixed,
+			Advisories:        []v3.Advisory{},
+		},
+		{
+			ID:                "CVE-2021-3449",
+			PackageName:       "openssl-libs",
+			Namespace:         "rhel:8",
+			VersionConstraint: "< 1:1.1.1g-15.el8_3",
+			VersionFormat:     "rpm",
+			CPEs:              []string{},
+			Fix:               fixed,
+			Advisories:        []v3.Advisory{},
+		},
+		{
+			ID:                "CVE-2021-3449",
+			PackageName:       "compat-openssl10",
+			Namespace:         "rhel:8",
+			VersionConstraint: "",
+			VersionFormat:     "rpm",
+			CPEs:              []string{},
+			Fix:               v3.Fix{State: v3.WontFixState},
+			Advisories:        []v3.Advisory{},
+		},
+	}
+	for _, d := range deep.Equal(expectedVulnerabilities, records.Vulnerabilities) {
+		t.Errorf("vulnerability diff: %+v", d)
+	}
+
+	expectedMetadata := []v3.VulnerabilityMetadata{
+		{
+			ID:           "CVE-2021-3449",
+			Namespace:    "rhel:8",
+			DataSource:   "https://access.redhat.com/security/cve/CVE-2021-3449",
+			RecordSource: "csaf:rhel:8",
+			Severity:     "Medium",
+			URLs: []string{
+				"https://access.redhat.com/security/cve/CVE-2021-3449",
+				"https://bugzilla.redhat.com/show_bug.cgi?id=1941554",
+			},
+			Description: "An OpenSSL TLS server may crash if sent a maliciously crafted renegotiation ClientHello message from a client.",
+			Cvss: []v3.Cvss{
+				{
+					Metrics: v3.CvssMetrics{BaseScore: 5.9},
+					Vector:  "CVSS:3.1/AV:N/AC:H/PR:N/UI:N/S:U/C:N/I:N/A:H",
+					Version: "3.1",
+				},
+			},
+			Published:    timeRef(time.Date(2021, 3, 25, 14, 0, 0, 0, time.UTC)),
+			Modified:     timeRef(time.Date(2021, 4, 6, 10, 30, 0, 0, time.UTC)),
+			CWEs:         []string{"CWE-476"},
+			Exploitation: "No known exploitation in the wild",
+		},
+	}
+	for _, d := range deep.Equal(expectedMetadata, records.Metadata) {
+		t.Errorf("metadata diff: %+v", d)
+	}
+
+	expectedNotAffected := []v3.Vulnerability{
+		{ID: "CVE-2021-3449", PackageName: "openssl-ibmca", Namespace: "rhel:8"},
+		{ID: "CVE-2021-3450", PackageName: "openssl", Namespace: "rhel:8"},
+	}
+	for _, d := range deep.Equal(expectedNotAffected, records.NotAffected) {
+		t.Errorf("not affected diff: %+v", d)
+	}
+}
+
+func TestTransform_InvalidDocument(t *testing.T) {
+	_, err := Transform(strings.NewReader("{"), "rhel:8")
+	assert.Error(t, err)
+}
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{value: "Critical", expected: "Critical"},
+		{value: "Important", expected: "High"},
+		{value: "Moderate", expected: "Medium"},
+		{value: "low", expected: "Low"},
+		{value: "", expected: "Unknown"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			assert.Equal(t, test.expected, severity(test.value))
+		})
+	}
+}
+
+func timeRef(t time.Time) *time.Time {
+	return &t
+}
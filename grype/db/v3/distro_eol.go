@@ -0,0 +1,10 @@
+package v3
+
+import "time"
+
+// DistroEOL represents the end of life of a distro release, after which vulnerability data is no longer published for
+// the release.
+type DistroEOL struct {
+	Namespace string    // The namespace of the distro release (e.g. "rhel:7")
+	Date      time.Time // When the distro release reached (or will reach) the end of life
+}
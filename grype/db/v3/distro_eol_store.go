@@ -0,0 +1,17 @@
+package v3
+
+type DistroEOLStore interface {
+	DistroEOLStoreReader
+	DistroEOLStoreWriter
+}
+
+// DistroEOLStoreReader is implemented by stores that track the end of life of distro releases.
+type DistroEOLStoreReader interface {
+	// GetDistroEOL retrieves the end of life of the distro release with the given namespace (or nil when unknown)
+	GetDistroEOL(namespace string) (*DistroEOL, error)
+}
+
+type DistroEOLStoreWriter interface {
+	// AddDistroEOL inserts (or replaces) the end of life of one or more distro releases into the store
+	AddDistroEOL(eols ...DistroEOL) error
+}
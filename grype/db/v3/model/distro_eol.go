@@ -0,0 +1,44 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	v3 "github.com/anchore/grype/grype/db/v3"
+)
+
+const (
+	DistroEOLTableName = "distro_eol"
+)
+
+// DistroEOLModel is a struct used to serialize db.DistroEOL information into a sqlite3 DB.
+type DistroEOLModel struct {
+	Namespace string `gorm:"primary_key; column:namespace;"`
+	Date      string `gorm:"column:date"`
+}
+
+// NewDistroEOLModel generates a new model from a db.DistroEOL struct.
+func NewDistroEOLModel(eol v3.DistroEOL) DistroEOLModel {
+	return DistroEOLModel{
+		Namespace: eol.Namespace,
+		Date:      eol.Date.UTC().Format(time.RFC3339),
+	}
+}
+
+// TableName returns the table which all db.DistroEOL model instances are stored into.
+func (DistroEOLModel) TableName() string {
+	return DistroEOLTableName
+}
+
+// Inflate generates a db.DistroEOL object from the serialized model instance.
+func (m *DistroEOLModel) Inflate() (v3.DistroEOL, error) {
+	date, err := time.Parse(time.RFC3339, m.Date)
+	if err != nil {
+		return v3.DistroEOL{}, fmt.Errorf("unable to parse end of life date (%+v): %w", m.Date, err)
+	}
+
+	return v3.DistroEOL{
+		Namespace: m.Namespace,
+		Date:      date,
+	}, nil
+}
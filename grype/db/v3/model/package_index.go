@@ -0,0 +1,36 @@
+package model
+
+const (
+	PackageIndexTableName           = "package_index"
+	VulnerabilityNamespaceTableName = "vulnerability_namespace"
+)
+
+// PackageIndexSchema describes the package index, which maps the key of each package (see v3.PackageKey) to the
+// vulnerabilities of the package within each namespace. Since the table has no rowid, rows are stored within the
+// primary key, which makes it a covering index: the vulnerabilities of a package within the namespaces of interest are
+// found without reading any vulnerability that is not of interest.
+// Note: tables without a rowid cannot be described by gorm, which is why the schema is not migrated from the model.
+const PackageIndexSchema = `CREATE TABLE IF NOT EXISTS package_index (
+	package_key TEXT NOT NULL,
+	namespace TEXT NOT NULL,
+	vulnerability_pk INTEGER NOT NULL,
+	PRIMARY KEY (package_key, namespace, vulnerability_pk)
+) WITHOUT ROWID`
+
+// VulnerabilityNamespaceSchema describes the namespaces that have vulnerabilities, which spares reading every
+// vulnerability to determine the namespaces within the DB.
+const VulnerabilityNamespaceSchema = `CREATE TABLE IF NOT EXISTS vulnerability_namespace (
+	namespace TEXT NOT NULL PRIMARY KEY
+) WITHOUT ROWID`
+
+// PackageIndexModel is a struct used to read the package index from a sqlite3 DB.
+type PackageIndexModel struct {
+	PackageKey      string `gorm:"primary_key; column:package_key"`
+	Namespace       string `gorm:"primary_key; column:namespace"`
+	VulnerabilityPK int64  `gorm:"primary_key; column:vulnerability_pk"`
+}
+
+// TableName returns the table which all package index model instances are stored into.
+func (PackageIndexModel) TableName() string {
+	return PackageIndexTableName
+}
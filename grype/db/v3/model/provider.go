@@ -0,0 +1,44 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	v3 "github.com/anchore/grype/grype/db/v3"
+)
+
+const (
+	ProviderTableName = "provider"
+)
+
+// ProviderModel is a struct used to serialize db.Provider information into a sqlite3 DB.
+type ProviderModel struct {
+	Name           string `gorm:"primary_key; column:name;"`
+	BuildTimestamp string `gorm:"column:build_timestamp"`
+}
+
+// NewProviderModel generates a new model from a db.Provider struct.
+func NewProviderModel(provider v3.Provider) ProviderModel {
+	return ProviderModel{
+		Name:           provider.Name,
+		BuildTimestamp: provider.Built.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+// TableName returns the table which all db.Provider model instances are stored into.
+func (ProviderModel) TableName() string {
+	return ProviderTableName
+}
+
+// Inflate generates a db.Provider object from the serialized model instance.
+func (m *ProviderModel) Inflate() (v3.Provider, error) {
+	built, err := time.Parse(time.RFC3339Nano, m.BuildTimestamp)
+	if err != nil {
+		return v3.Provider{}, fmt.Errorf("unable to parse provider build timestamp (%+v): %w", m.BuildTimestamp, err)
+	}
+
+	return v3.Provider{
+		Name:  m.Name,
+		Built: built,
+	}, nil
+}
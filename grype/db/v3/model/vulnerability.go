@@ -25,6 +25,9 @@ type VulnerabilityModel struct {
 	FixedInVersions        string `gorm:"column:fixed_in_versions"`
 	FixState               string `gorm:"column:fix_state"`
 	Advisories             string `gorm:"column:advisories"`
+	RpmModularity          string `gorm:"column:rpm_modularity"`
+	Arches                 string `gorm:"column:arches"`
+	PlatformCPEs           string `gorm:"column:platform_cpes"`
 }
 
 // NewVulnerabilityModel generates a new model from a db.Vulnerability struct.
@@ -53,6 +56,24 @@ func NewVulnerabilityModel(vulnerability v3.Vulnerability) VulnerabilityModel {
 		panic(err)
 	}
 
+	var arches []byte
+	if len(vulnerability.Arches) > 0 {
+		arches, err = json.Marshal(vulnerability.Arches)
+		if err != nil {
+			// TODO: just no
+			panic(err)
+		}
+	}
+
+	var platformCPEs []byte
+	if len(vulnerability.PlatformCPEs) > 0 {
+		platformCPEs, err = json.Marshal(vulnerability.PlatformCPEs)
+		if err != nil {
+			// TODO: just no
+			panic(err)
+		}
+	}
+
 	return VulnerabilityModel{
 		ID:                     vulnerability.ID,
 		PackageName:            vulnerability.PackageName,
@@ -64,6 +85,9 @@ func NewVulnerabilityModel(vulnerability v3.Vulnerability) VulnerabilityModel {
 		Advisories:             string(advisories),
 		CPEs:                   string(cpes),
 		RelatedVulnerabilities: string(related),
+		RpmModularity:          vulnerability.RpmModularity,
+		Arches:                 string(arches),
+		PlatformCPEs:           string(platformCPEs),
 	}
 }
 
@@ -98,6 +122,22 @@ func (m *VulnerabilityModel) Inflate() (v3.Vulnerability, error) {
 		return v3.Vulnerability{}, fmt.Errorf("unable to unmarshal versions (%+v): %w", m.FixedInVersions, err)
 	}
 
+	var arches []string
+	if m.Arches != "" {
+		err = json.Unmarshal([]byte(m.Arches), &arches)
+		if err != nil {
+			return v3.Vulnerability{}, fmt.Errorf("unable to unmarshal arches (%+v): %w", m.Arches, err)
+		}
+	}
+
+	var platformCPEs []string
+	if m.PlatformCPEs != "" {
+		err = json.Unmarshal([]byte(m.PlatformCPEs), &platformCPEs)
+		if err != nil {
+			return v3.Vulnerability{}, fmt.Errorf("unable to unmarshal platform CPEs (%+v): %w", m.PlatformCPEs, err)
+		}
+	}
+
 	return v3.Vulnerability{
 		ID:                     m.ID,
 		PackageName:            m.PackageName,
@@ -110,6 +150,9 @@ func (m *VulnerabilityModel) Inflate() (v3.Vulnerability, error) {
 			Versions: versions,
 			State:    v3.FixState(m.FixState),
 		},
-		Advisories: advisories,
+		Advisories:    advisories,
+		RpmModularity: m.RpmModularity,
+		Arches:        arches,
+		PlatformCPEs:  platformCPEs,
 	}, nil
 }
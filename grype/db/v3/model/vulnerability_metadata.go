@@ -3,6 +3,7 @@ package model
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	v3 "github.com/anchore/grype/grype/db/v3"
 )
@@ -21,6 +22,10 @@ type VulnerabilityMetadataModel struct {
 	URLs         string `gorm:"column:urls"`
 	Description  string `gorm:"column:description"`
 	Cvss         string `gorm:"column:cvss"`
+	Published    string `gorm:"column:published"`
+	Modified     string `gorm:"column:modified"`
+	CWEs         string `gorm:"column:cwes"`
+	Exploitation string `gorm:"column:exploitation"`
 }
 
 // NewVulnerabilityMetadataModel generates a new model from a db.VulnerabilityMetadata struct.
@@ -43,6 +48,15 @@ func NewVulnerabilityMetadataModel(metadata v3.VulnerabilityMetadata) Vulnerabil
 
 	cvssStr = string(cvss)
 
+	var cwes []byte
+	if len(metadata.CWEs) > 0 {
+		cwes, err = json.Marshal(metadata.CWEs)
+		if err != nil {
+			// TODO: just no
+			panic(err)
+		}
+	}
+
 	return VulnerabilityMetadataModel{
 		ID:           metadata.ID,
 		Namespace:    metadata.Namespace,
@@ -52,6 +66,10 @@ func NewVulnerabilityMetadataModel(metadata v3.VulnerabilityMetadata) Vulnerabil
 		URLs:         string(links),
 		Description:  metadata.Description,
 		Cvss:         cvssStr,
+		Published:    formatTimestamp(metadata.Published),
+		Modified:     formatTimestamp(metadata.Modified),
+		CWEs:         string(cwes),
+		Exploitation: metadata.Exploitation,
 	}
 }
 
@@ -74,6 +92,23 @@ func (m *VulnerabilityMetadataModel) Inflate() (v3.VulnerabilityMetadata, error)
 		return v3.VulnerabilityMetadata{}, fmt.Errorf("unable to unmarshal cvss data (%+v): %w", m.Cvss, err)
 	}
 
+	published, err := parseTimestamp(m.Published)
+	if err != nil {
+		return v3.VulnerabilityMetadata{}, fmt.Errorf("unable to parse published date (%+v): %w", m.Published, err)
+	}
+
+	modified, err := parseTimestamp(m.Modified)
+	if err != nil {
+		return v3.VulnerabilityMetadata{}, fmt.Errorf("unable to parse modified date (%+v): %w", m.Modified, err)
+	}
+
+	var cwes []string
+	if m.CWEs != "" {
+		if err := json.Unmarshal([]byte(m.CWEs), &cwes); err != nil {
+			return v3.VulnerabilityMetadata{}, fmt.Errorf("unable to unmarshal CWEs (%+v): %w", m.CWEs, err)
+		}
+	}
+
 	return v3.VulnerabilityMetadata{
 		ID:           m.ID,
 		Namespace:    m.Namespace,
@@ -83,5 +118,29 @@ func (m *VulnerabilityMetadataModel) Inflate() (v3.VulnerabilityMetadata, error)
 		URLs:         links,
 		Description:  m.Description,
 		Cvss:         cvss,
+		Published:    published,
+		Modified:     modified,
+		CWEs:         cwes,
+		Exploitation: m.Exploitation,
 	}, nil
 }
+
+// formatTimestamp serializes an optional timestamp, where an unknown timestamp is empty.
+func formatTimestamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// parseTimestamp parses an optional timestamp, where an empty value is an unknown timestamp.
+func parseTimestamp(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
@@ -2,6 +2,7 @@ package v3
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/anchore/grype/grype/distro"
@@ -11,9 +12,11 @@ import (
 )
 
 const (
-	NVDNamespace        = "nvd"
-	MSRCNamespacePrefix = "msrc"
-	VulnDBNamespace     = "vulndb"
+	NVDNamespace               = "nvd"
+	MSRCNamespacePrefix        = "msrc"
+	OSVNamespacePrefix         = "osv"
+	VulnDBNamespace            = "vulndb"
+	AmazonLinuxNamespacePrefix = "amzn"
 )
 
 func RecordSource(feed, group string) string {
@@ -32,6 +35,10 @@ func NamespaceForFeedGroup(feed, group string) (string, error) {
 		return VulnDBNamespace, nil
 	case feed == "microsoft" && strings.HasPrefix(group, MSRCNamespacePrefix+":"):
 		return group, nil
+	case feed == "osv" && strings.HasPrefix(group, OSVNamespacePrefix+":"):
+		return group, nil
+	case feed == "mariner" && (strings.HasPrefix(group, "mariner:") || strings.HasPrefix(group, "azurelinux:")):
+		return group, nil
 	}
 	return "", fmt.Errorf("feed=%q group=%q has no namespace mappings", feed, group)
 }
@@ -52,21 +59,50 @@ func NamespaceForDistro(d *distro.Distro) string {
 		versionSegments = d.Version.Segments()
 	}
 
+	switch d.Type {
+	case distro.OpenSuseTumbleweed:
+		// tumbleweed is a rolling release (the version is the snapshot date), so there is a single namespace
+		return "opensuse-tumbleweed:rolling"
+	case distro.Wolfi, distro.Chainguard:
+		// wolfi and chainguard are rolling releases (the version, if any, is the build date), so there is a single
+		// namespace for each
+		return fmt.Sprintf("%s:rolling", d.Type)
+	}
+
 	if len(versionSegments) > 0 {
 		switch d.Type {
 		// derived from https://github.com/anchore/anchore-engine/blob/5bbbe6b9744f2fb806198ae5d6f0cfe3b367fd9d/anchore_engine/services/policy_engine/__init__.py#L149-L159
-		case distro.CentOS, distro.RedHat, distro.Fedora, distro.RockyLinux, distro.AlmaLinux:
+		case distro.CentOS, distro.RedHat, distro.Fedora:
 			// TODO: there is no mapping of fedora version to RHEL latest version (only the name)
 			return fmt.Sprintf("rhel:%d", versionSegments[0])
+		case distro.RockyLinux:
+			// note: when there is no rocky linux data the RHEL namespace is used instead (see distro.DefaultAliasRules)
+			return fmt.Sprintf("rocky:%d", versionSegments[0])
+		case distro.AlmaLinux:
+			// note: when there is no almalinux data the RHEL namespace is used instead (see distro.DefaultAliasRules)
+			return fmt.Sprintf("almalinux:%d", versionSegments[0])
 		case distro.AmazonLinux:
-			return fmt.Sprintf("amzn:%d", versionSegments[0])
+			return fmt.Sprintf("%s:%s", AmazonLinuxNamespacePrefix, amazonLinuxRelease(versionSegments[0]))
 		case distro.OracleLinux:
 			return fmt.Sprintf("ol:%d", versionSegments[0])
 		case distro.Alpine:
 			// XXX this assumes that a major and minor versions will always exist in Segments
 			return fmt.Sprintf("alpine:%d.%d", versionSegments[0], versionSegments[1])
 		case distro.SLES:
+			// service packs are minor versions (e.g. "15.3" for SLES 15 SP3), and the initial release has none
+			if len(versionSegments) < 2 || versionSegments[1] == 0 {
+				return fmt.Sprintf("sles:%d", versionSegments[0])
+			}
 			return fmt.Sprintf("sles:%d.%d", versionSegments[0], versionSegments[1])
+		case distro.OpenSuseLeap:
+			if len(versionSegments) < 2 {
+				return fmt.Sprintf("opensuse-leap:%d", versionSegments[0])
+			}
+			return fmt.Sprintf("opensuse-leap:%d.%d", versionSegments[0], versionSegments[1])
+		case distro.Mariner, distro.AzureLinux:
+			// releases are only versioned by major version (e.g. "2.0"), while the version may include the build
+			// date (e.g. "2.0.20220713")
+			return fmt.Sprintf("%s:%d.0", d.Type, versionSegments[0])
 		case distro.Windows:
 			return fmt.Sprintf("%s:%d", MSRCNamespacePrefix, versionSegments[0])
 		}
@@ -74,27 +110,169 @@ func NamespaceForDistro(d *distro.Distro) string {
 	return fmt.Sprintf("%s:%s", strings.ToLower(d.Type.String()), d.FullVersion())
 }
 
+// amazonLinuxRelease returns the release of amazon linux for the given major version. Amazon Linux 1 was versioned by
+// the year and month of each release (e.g. "2018.03"), while later releases are versioned either by number (e.g. "2")
+// or by the year of the release (e.g. "2023").
+func amazonLinuxRelease(major int) string {
+	if major >= 2011 && major <= 2018 {
+		return "1"
+	}
+	return fmt.Sprintf("%d", major)
+}
+
 func NamespacesIndexedByCPE() []string {
 	return []string{NVDNamespace, VulnDBNamespace}
 }
 
+// packageTypesByNamespace are the package URL types (see https://github.com/package-url/purl-spec) of the ecosystems
+// described by language and package type namespaces.
+var packageTypesByNamespace = map[string]string{
+	"github:gem":              "gem",
+	OSVNamespace("RubyGems"):  "gem",
+	"github:java":             "maven",
+	OSVNamespace("Maven"):     "maven",
+	"github:npm":              "npm",
+	OSVNamespace("npm"):       "npm",
+	"github:python":           "pypi",
+	OSVNamespace("PyPI"):      "pypi",
+	"github:go":               "golang",
+	OSVNamespace("Go"):        "golang",
+	"github:composer":         "composer",
+	OSVNamespace("Packagist"): "composer",
+	"github:rust":             "cargo",
+	OSVNamespace("crates.io"): "cargo",
+	"github:pub":              "pub",
+	OSVNamespace("Pub"):       "pub",
+	"github:swift":            "swift",
+	OSVNamespace("SwiftURL"):  "swift",
+	"github:erlang":           "hex",
+	OSVNamespace("Hex"):       "hex",
+	OSVNamespace("CRAN"):      "cran",
+	OSVNamespace("Hackage"):   "hackage",
+	"github:nuget":            "nuget",
+	OSVNamespace("NuGet"):     "nuget",
+	OSVNamespace("Bitnami"):   "bitnami",
+	OSVNamespace("Homebrew"):  "brew",
+}
+
+// PackageKey returns the key of a package within the package index, which is the package URL type of the ecosystem
+// that the namespace describes followed by the package name (e.g. "pypi/requests" within "github:python" or
+// "osv:pypi"), so that the vulnerabilities of a package within all namespaces of an ecosystem share a key. Names are
+// normalized the way the ecosystem compares them (see normalizePackageName), so that a package is found regardless of
+// how each namespace spells its name. Packages within namespaces that are indexed by CPE are keyed by product (e.g.
+// "cpe/openssl"). Packages within distro namespaces are not indexed, in which case the key is empty.
+func PackageKey(namespace, name string) string {
+	for _, n := range NamespacesIndexedByCPE() {
+		if namespace == n {
+			return "cpe/" + name
+		}
+	}
+
+	packageType, ok := packageTypesByNamespace[namespace]
+	if !ok {
+		return ""
+	}
+	return packageType + "/" + normalizePackageName(packageType, name)
+}
+
+var pythonNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// normalizePackageName returns the name of a package the way the ecosystem of the given package URL type compares
+// names, where names within ecosystems that compare them exactly (e.g. maven and golang) are kept as they are.
+func normalizePackageName(packageType, name string) string {
+	switch packageType {
+	case "pypi":
+		// see https://peps.python.org/pep-0503/#normalized-names
+		return pythonNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+	case "cargo":
+		// crates.io does not allow names that only differ by case or by "-" and "_"
+		return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+	case "npm", "composer", "nuget", "hex":
+		return strings.ToLower(name)
+	}
+	return name
+}
+
 func NamespacePackageNamersForLanguage(l syftPkg.Language) map[string]NamerByPackage {
 	namespaces := make(map[string]NamerByPackage)
 	switch l {
 	case syftPkg.Ruby:
 		namespaces["github:gem"] = defaultPackageNamer
+		namespaces[OSVNamespace("RubyGems")] = defaultPackageNamer
 	case syftPkg.Java:
 		namespaces["github:java"] = githubJavaPackageNamer
+		namespaces[OSVNamespace("Maven")] = githubJavaPackageNamer
 	case syftPkg.JavaScript:
 		namespaces["github:npm"] = defaultPackageNamer
+		namespaces[OSVNamespace("npm")] = defaultPackageNamer
 	case syftPkg.Python:
 		namespaces["github:python"] = defaultPackageNamer
+		namespaces[OSVNamespace("PyPI")] = defaultPackageNamer
+	case syftPkg.Go:
+		namespaces["github:go"] = githubGoPackageNamer
+		namespaces[OSVNamespace("Go")] = githubGoPackageNamer
+	case syftPkg.PHP:
+		namespaces["github:composer"] = githubComposerPackageNamer
+		namespaces[OSVNamespace("Packagist")] = githubComposerPackageNamer
+	case syftPkg.Rust:
+		namespaces["github:rust"] = defaultPackageNamer
+		namespaces[OSVNamespace("crates.io")] = defaultPackageNamer
+	case pkg.Dart:
+		namespaces["github:pub"] = defaultPackageNamer
+		namespaces[OSVNamespace("Pub")] = defaultPackageNamer
+	case pkg.Swift:
+		namespaces["github:swift"] = swiftPackageNamer
+		namespaces[OSVNamespace("SwiftURL")] = swiftPackageNamer
+	case pkg.Elixir, pkg.Erlang:
+		// elixir (mix) and erlang (rebar3) packages are both published to hex
+		namespaces["github:erlang"] = hexPackageNamer
+		namespaces[OSVNamespace("Hex")] = hexPackageNamer
+	case pkg.R:
+		namespaces[OSVNamespace("CRAN")] = defaultPackageNamer
+	case pkg.Haskell:
+		namespaces[OSVNamespace("Hackage")] = defaultPackageNamer
+	case pkg.Dotnet:
+		namespaces["github:nuget"] = defaultPackageNamer
+		namespaces[OSVNamespace("NuGet")] = defaultPackageNamer
 	default:
 		namespaces[fmt.Sprintf("github:%s", l)] = defaultPackageNamer
 	}
 	return namespaces
 }
 
+// NamespacePackageNamersForPackageType returns the namespaces (and how to name packages within them) for ecosystems
+// that are identified by package type instead of language (e.g. packages that are distributed by a vendor).
+func NamespacePackageNamersForPackageType(t syftPkg.Type) map[string]NamerByPackage {
+	namespaces := make(map[string]NamerByPackage)
+	switch t {
+	case pkg.BitnamiPkg:
+		namespaces[OSVNamespace("Bitnami")] = defaultPackageNamer
+	case pkg.HomebrewPkg:
+		namespaces[OSVNamespace("Homebrew")] = defaultPackageNamer
+	}
+	return namespaces
+}
+
+// swiftPackageNamer returns the repository URL of the swift package, both in the normalized form
+// (e.g. "github.com/apple/swift-nio") and as a git URL (e.g. "https://github.com/apple/swift-nio.git"), which is how
+// swift packages are identified within advisory data.
+func swiftPackageNamer(p pkg.Package) []string {
+	if metadata, ok := p.Metadata.(pkg.SwiftMetadata); ok && metadata.RepositoryURL != "" {
+		return []string{metadata.RepositoryURL, fmt.Sprintf("https://%s.git", metadata.RepositoryURL)}
+	}
+	return []string{p.Name}
+}
+
+// hexPackageNamer returns the package name as published to hex, which is case-sensitive but always lowercase.
+func hexPackageNamer(p pkg.Package) []string {
+	return []string{strings.ToLower(p.Name)}
+}
+
+// OSVNamespace returns the namespace for advisories from the given OSV ecosystem (e.g. "Pub" becomes "osv:pub").
+func OSVNamespace(ecosystem string) string {
+	return fmt.Sprintf("%s:%s", OSVNamespacePrefix, strings.ToLower(ecosystem))
+}
+
 type NamerByPackage func(p pkg.Package) []string
 
 func defaultPackageNamer(p pkg.Package) []string {
@@ -118,3 +296,24 @@ func githubJavaPackageNamer(p pkg.Package) []string {
 
 	return names.ToSlice()
 }
+
+func githubGoPackageNamer(p pkg.Package) []string {
+	names := internal.NewStringSetFromSlice([]string{p.Name})
+
+	// the golang.org/x modules are mirrored on github (e.g. golang.org/x/crypto -> github.com/golang/crypto) and
+	// advisories may be recorded against either module path
+	if strings.HasPrefix(p.Name, "golang.org/x/") {
+		names.Add("github.com/golang/" + strings.TrimPrefix(p.Name, "golang.org/x/"))
+	}
+
+	return names.ToSlice()
+}
+
+// githubComposerPackageNamer returns the composer package name in the form of "vendor/name", which is how composer
+// packages are identified within the GitHub advisory data. Note: composer package names are case-insensitive.
+func githubComposerPackageNamer(p pkg.Package) []string {
+	if metadata, ok := p.Metadata.(pkg.PhpComposerMetadata); ok && metadata.Vendor != "" {
+		return []string{strings.ToLower(fmt.Sprintf("%s/%s", metadata.Vendor, metadata.Name))}
+	}
+	return []string{strings.ToLower(p.Name)}
+}
@@ -47,6 +47,21 @@ func Test_NamespaceFromRecordSource(t *testing.T) {
 			Group:     "msrc:11769",
 			Namespace: "msrc:11769",
 		},
+		{
+			Feed:      "osv",
+			Group:     "osv:pub",
+			Namespace: "osv:pub",
+		},
+		{
+			Feed:      "mariner",
+			Group:     "mariner:2.0",
+			Namespace: "mariner:2.0",
+		},
+		{
+			Feed:      "mariner",
+			Group:     "azurelinux:3.0",
+			Namespace: "azurelinux:3.0",
+		},
 	}
 
 	for _, test := range tests {
@@ -102,6 +117,21 @@ func Test_NamespaceForDistro(t *testing.T) {
 			version:  "2",
 			expected: "amzn:2",
 		},
+		{
+			dist:     distro.AmazonLinux,
+			version:  "2018.03",
+			expected: "amzn:1",
+		},
+		{
+			dist:     distro.AmazonLinux,
+			version:  "2022",
+			expected: "amzn:2022",
+		},
+		{
+			dist:     distro.AmazonLinux,
+			version:  "2023",
+			expected: "amzn:2023",
+		},
 		{
 			dist:     distro.OracleLinux,
 			version:  "6",
@@ -144,10 +174,34 @@ func Test_NamespaceForDistro(t *testing.T) {
 			expected: "archlinux:",
 		},
 		{
-			// TODO: this is not correct. This should be mapped to a feed source.
 			dist:     distro.OpenSuseLeap,
 			version:  "15.2",
-			expected: "opensuseleap:15.2",
+			expected: "opensuse-leap:15.2",
+		},
+		{
+			dist:     distro.OpenSuseLeap,
+			version:  "42.3",
+			expected: "opensuse-leap:42.3",
+		},
+		{
+			dist:     distro.OpenSuseTumbleweed,
+			version:  "20211210",
+			expected: "opensuse-tumbleweed:rolling",
+		},
+		{
+			dist:     distro.Wolfi,
+			version:  "20230201",
+			expected: "wolfi:rolling",
+		},
+		{
+			dist:     distro.Wolfi,
+			version:  "", // not all wolfi images expose a version
+			expected: "wolfi:rolling",
+		},
+		{
+			dist:     distro.Chainguard,
+			version:  "20230214",
+			expected: "chainguard:rolling",
 		},
 		{
 			// TODO: this is not correct. This should be mapped to a feed source.
@@ -160,6 +214,16 @@ func Test_NamespaceForDistro(t *testing.T) {
 			version:  "12.5",
 			expected: "sles:12.5",
 		},
+		{
+			dist:     distro.SLES,
+			version:  "15",
+			expected: "sles:15",
+		},
+		{
+			dist:     distro.SLES,
+			version:  "15.0",
+			expected: "sles:15",
+		},
 		{
 			dist:     distro.Windows,
 			version:  "471816",
@@ -168,12 +232,27 @@ func Test_NamespaceForDistro(t *testing.T) {
 		{
 			dist:     distro.RockyLinux,
 			version:  "8.5",
-			expected: "rhel:8",
+			expected: "rocky:8",
 		},
 		{
 			dist:     distro.AlmaLinux,
 			version:  "8.5",
-			expected: "rhel:8",
+			expected: "almalinux:8",
+		},
+		{
+			dist:     distro.Mariner,
+			version:  "1.0",
+			expected: "mariner:1.0",
+		},
+		{
+			dist:     distro.Mariner,
+			version:  "2.0.20220713",
+			expected: "mariner:2.0",
+		},
+		{
+			dist:     distro.AzureLinux,
+			version:  "3.0",
+			expected: "azurelinux:3.0",
 		},
 	}
 
@@ -184,9 +263,6 @@ func Test_NamespaceForDistro(t *testing.T) {
 		allDistros.Add(d.String())
 	}
 
-	// TOOD: what do we do with mariner
-	allDistros.Remove(distro.Mariner.String())
-
 	for _, test := range tests {
 		name := fmt.Sprintf("%s:%s", test.dist, test.version)
 		t.Run(name, func(t *testing.T) {
@@ -204,6 +280,86 @@ func Test_NamespacesIndexedByCPE(t *testing.T) {
 	assert.ElementsMatch(t, NamespacesIndexedByCPE(), []string{"nvd", "vulndb"})
 }
 
+func Test_PackageKey(t *testing.T) {
+	tests := []struct {
+		namespace string
+		name      string
+		expected  string
+	}{
+		{
+			namespace: "github:python",
+			name:      "Zope.Interface",
+			expected:  "pypi/zope-interface",
+		},
+		{
+			namespace: "osv:pypi",
+			name:      "zope_interface",
+			expected:  "pypi/zope-interface",
+		},
+		{
+			namespace: "osv:pypi",
+			name:      "Flask--SQLAlchemy",
+			expected:  "pypi/flask-sqlalchemy",
+		},
+		{
+			namespace: "github:npm",
+			name:      "JSONStream",
+			expected:  "npm/jsonstream",
+		},
+		{
+			namespace: "github:composer",
+			name:      "Symfony/HTTP-Kernel",
+			expected:  "composer/symfony/http-kernel",
+		},
+		{
+			namespace: "github:nuget",
+			name:      "Newtonsoft.Json",
+			expected:  "nuget/newtonsoft.json",
+		},
+		{
+			namespace: "github:go",
+			name:      "github.com/BurntSushi/toml",
+			expected:  "golang/github.com/BurntSushi/toml",
+		},
+		{
+			namespace: "github:java",
+			name:      "org.apache.logging.log4j:log4j-core",
+			expected:  "maven/org.apache.logging.log4j:log4j-core",
+		},
+		{
+			namespace: "osv:crates.io",
+			name:      "Serde_JSON",
+			expected:  "cargo/serde-json",
+		},
+		{
+			namespace: "nvd",
+			name:      "openssl",
+			expected:  "cpe/openssl",
+		},
+		{
+			namespace: "vulndb",
+			name:      "openssl",
+			expected:  "cpe/openssl",
+		},
+		{
+			namespace: "debian:10",
+			name:      "openssl",
+			expected:  "",
+		},
+		{
+			namespace: "github:unknown",
+			name:      "a-name",
+			expected:  "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%s/%s", test.namespace, test.name), func(t *testing.T) {
+			assert.Equal(t, test.expected, PackageKey(test.namespace, test.name))
+		})
+	}
+}
+
 func Test_NamespacesForLanguage(t *testing.T) {
 	tests := []struct {
 		language           syftPkg.Language
@@ -220,24 +376,155 @@ func Test_NamespacesForLanguage(t *testing.T) {
 			},
 			expectedNamespaces: []string{
 				"github:rust",
+				"osv:crates.io",
 			},
 			expectedNames: []string{
 				"a-name",
+				"a-name",
 			},
 		},
 		{
 			language: syftPkg.Go,
 			namerInput: &pkg.Package{
 				ID:   pkg.ID(uuid.NewString()),
-				Name: "a-name",
+				Name: "golang.org/x/crypto",
 			},
 			expectedNamespaces: []string{
 				"github:go",
+				"osv:go",
 			},
 			expectedNames: []string{
+				"golang.org/x/crypto",
+				"github.com/golang/crypto",
+				"golang.org/x/crypto",
+				"github.com/golang/crypto",
+			},
+		},
+		{
+			language: syftPkg.PHP,
+			namerInput: &pkg.Package{
+				ID:   pkg.ID(uuid.NewString()),
+				Name: "http-kernel",
+				Metadata: pkg.PhpComposerMetadata{
+					Vendor: "Symfony",
+					Name:   "http-kernel",
+				},
+			},
+			expectedNamespaces: []string{
+				"github:composer",
+				"osv:packagist",
+			},
+			expectedNames: []string{
+				"symfony/http-kernel",
+				"symfony/http-kernel",
+			},
+		},
+		{
+			language: pkg.Dart,
+			namerInput: &pkg.Package{
+				ID:   pkg.ID(uuid.NewString()),
+				Name: "a-name",
+			},
+			expectedNamespaces: []string{
+				"github:pub",
+				"osv:pub",
+			},
+			expectedNames: []string{
+				"a-name",
 				"a-name",
 			},
 		},
+		{
+			language: pkg.Swift,
+			namerInput: &pkg.Package{
+				ID:   pkg.ID(uuid.NewString()),
+				Name: "swift-nio",
+				Metadata: pkg.SwiftMetadata{
+					RepositoryURL: "github.com/apple/swift-nio",
+				},
+			},
+			expectedNamespaces: []string{
+				"github:swift",
+				"osv:swifturl",
+			},
+			expectedNames: []string{
+				"github.com/apple/swift-nio",
+				"https://github.com/apple/swift-nio.git",
+				"github.com/apple/swift-nio",
+				"https://github.com/apple/swift-nio.git",
+			},
+		},
+		{
+			language: pkg.Elixir,
+			namerInput: &pkg.Package{
+				ID:   pkg.ID(uuid.NewString()),
+				Name: "Plug",
+			},
+			expectedNamespaces: []string{
+				"github:erlang",
+				"osv:hex",
+			},
+			expectedNames: []string{
+				"plug",
+				"plug",
+			},
+		},
+		{
+			language: pkg.Erlang,
+			namerInput: &pkg.Package{
+				ID:   pkg.ID(uuid.NewString()),
+				Name: "cowboy",
+			},
+			expectedNamespaces: []string{
+				"github:erlang",
+				"osv:hex",
+			},
+			expectedNames: []string{
+				"cowboy",
+				"cowboy",
+			},
+		},
+		{
+			language: pkg.R,
+			namerInput: &pkg.Package{
+				ID:   pkg.ID(uuid.NewString()),
+				Name: "Rcpp",
+			},
+			expectedNamespaces: []string{
+				"osv:cran",
+			},
+			expectedNames: []string{
+				"Rcpp",
+			},
+		},
+		{
+			language: pkg.Haskell,
+			namerInput: &pkg.Package{
+				ID:   pkg.ID(uuid.NewString()),
+				Name: "aeson",
+			},
+			expectedNamespaces: []string{
+				"osv:hackage",
+			},
+			expectedNames: []string{
+				"aeson",
+			},
+		},
+		{
+			language: pkg.Dotnet,
+			namerInput: &pkg.Package{
+				ID:   pkg.ID(uuid.NewString()),
+				Name: "Newtonsoft.Json",
+			},
+			expectedNamespaces: []string{
+				"github:nuget",
+				"osv:nuget",
+			},
+			expectedNames: []string{
+				"Newtonsoft.Json",
+				"Newtonsoft.Json",
+			},
+		},
 		// supported languages
 		{
 			language: syftPkg.Ruby,
@@ -247,9 +534,11 @@ func Test_NamespacesForLanguage(t *testing.T) {
 			},
 			expectedNamespaces: []string{
 				"github:gem",
+				"osv:rubygems",
 			},
 			expectedNames: []string{
 				"a-name",
+				"a-name",
 			},
 		},
 		{
@@ -260,9 +549,11 @@ func Test_NamespacesForLanguage(t *testing.T) {
 			},
 			expectedNamespaces: []string{
 				"github:npm",
+				"osv:npm",
 			},
 			expectedNames: []string{
 				"a-name",
+				"a-name",
 			},
 		},
 		{
@@ -273,9 +564,11 @@ func Test_NamespacesForLanguage(t *testing.T) {
 			},
 			expectedNamespaces: []string{
 				"github:python",
+				"osv:pypi",
 			},
 			expectedNames: []string{
 				"a-name",
+				"a-name",
 			},
 		},
 		{
@@ -292,10 +585,13 @@ func Test_NamespacesForLanguage(t *testing.T) {
 			},
 			expectedNamespaces: []string{
 				"github:java",
+				"osv:maven",
 			},
 			expectedNames: []string{
 				"g-id:art-id",
 				"g-id:man-name",
+				"g-id:art-id",
+				"g-id:man-name",
 			},
 		},
 	}
@@ -307,8 +603,9 @@ func Test_NamespacesForLanguage(t *testing.T) {
 		allLanguages.Add(string(l))
 	}
 
-	// remove PHP for coverage as feed has not been updated
-	allLanguages.Remove(string(syftPkg.PHP))
+	for _, l := range pkg.AdditionalLanguages {
+		allLanguages.Add(string(l))
+	}
 
 	for _, test := range tests {
 		t.Run(string(test.language), func(t *testing.T) {
@@ -327,6 +624,62 @@ func Test_NamespacesForLanguage(t *testing.T) {
 	assert.ElementsMatch(t, allLanguages.List(), observedLanguages.List(), "at least one language doesn't have a corresponding test")
 }
 
+func Test_NamespacesForPackageType(t *testing.T) {
+	tests := []struct {
+		pkgType            syftPkg.Type
+		namerInput         *pkg.Package
+		expectedNamespaces []string
+		expectedNames      []string
+	}{
+		{
+			pkgType: pkg.BitnamiPkg,
+			namerInput: &pkg.Package{
+				ID:   pkg.ID(uuid.NewString()),
+				Name: "redis",
+			},
+			expectedNamespaces: []string{
+				"osv:bitnami",
+			},
+			expectedNames: []string{
+				"redis",
+			},
+		},
+		{
+			pkgType: pkg.HomebrewPkg,
+			namerInput: &pkg.Package{
+				ID:   pkg.ID(uuid.NewString()),
+				Name: "jq",
+			},
+			expectedNamespaces: []string{
+				"osv:homebrew",
+			},
+			expectedNames: []string{
+				"jq",
+			},
+		},
+		{
+			pkgType: syftPkg.GemPkg,
+			namerInput: &pkg.Package{
+				ID:   pkg.ID(uuid.NewString()),
+				Name: "a-name",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.pkgType), func(t *testing.T) {
+			var actualNamespaces, actualNames []string
+			namers := NamespacePackageNamersForPackageType(test.pkgType)
+			for namespace, namerFn := range namers {
+				actualNamespaces = append(actualNamespaces, namespace)
+				actualNames = append(actualNames, namerFn(*test.namerInput)...)
+			}
+			assert.ElementsMatch(t, actualNamespaces, test.expectedNamespaces)
+			assert.ElementsMatch(t, actualNames, test.expectedNames)
+		})
+	}
+}
+
 func Test_githubJavaPackageNamer(t *testing.T) {
 	tests := []struct {
 		name       string
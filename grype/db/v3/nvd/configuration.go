@@ -0,0 +1,91 @@
+package nvd
+
+import (
+	"sort"
+
+	"github.com/scylladb/go-set/strset"
+)
+
+// Configurations are the applicability statements of a CVE within the NVD JSON 1.1 feeds
+// (see https://csrc.nist.gov/schema/nvd/feed/1.1/nvd_cve_feed_json_1.1.schema).
+type Configurations struct {
+	Nodes []Node `json:"nodes"`
+}
+
+// Node is a set of CPEs (and child nodes) that are combined by the operator ("OR" or "AND").
+type Node struct {
+	Operator string     `json:"operator"`
+	Negate   bool       `json:"negate"`
+	Children []Node     `json:"children"`
+	CPEMatch []CPEMatch `json:"cpe_match"`
+}
+
+// CPEMatch is a CPE that is either vulnerable or describes the platform that a vulnerable CPE must be running on.
+type CPEMatch struct {
+	Vulnerable bool   `json:"vulnerable"`
+	CPE23URI   string `json:"cpe23Uri"`
+}
+
+// CPEs returns the vulnerable CPEs and the platform CPEs of the given configurations. A top-level "AND" node describes
+// vulnerable CPEs that only apply when running on one of the non-vulnerable CPEs of the node (e.g. an application that
+// is only vulnerable on windows). No platform CPEs are returned when any of the vulnerable CPEs applies regardless of
+// the platform, since the vulnerable CPEs and platforms are not related to each other when stored.
+func (c Configurations) CPEs() ([]string, []string) {
+	vulnerable := strset.New()
+	platforms := strset.New()
+	unconstrained := false
+
+	for _, node := range c.Nodes {
+		nodeVulnerable, nodePlatforms, negated := node.cpes()
+		if nodeVulnerable.IsEmpty() {
+			continue
+		}
+		vulnerable.Merge(nodeVulnerable)
+
+		// note: a negated node (e.g. "not running on X") cannot be expressed as a platform, so is not constrained
+		if node.Operator != "AND" || negated || nodePlatforms.IsEmpty() {
+			unconstrained = true
+			continue
+		}
+		platforms.Merge(nodePlatforms)
+	}
+
+	if unconstrained {
+		return sorted(vulnerable), nil
+	}
+	return sorted(vulnerable), sorted(platforms)
+}
+
+// cpes returns the vulnerable and non-vulnerable CPEs of the node and all child nodes, and if any of the nodes is
+// negated.
+func (n Node) cpes() (*strset.Set, *strset.Set, bool) {
+	vulnerable := strset.New()
+	other := strset.New()
+	for _, m := range n.CPEMatch {
+		if m.CPE23URI == "" {
+			continue
+		}
+		if m.Vulnerable {
+			vulnerable.Add(m.CPE23URI)
+		} else {
+			other.Add(m.CPE23URI)
+		}
+	}
+	negated := n.Negate
+	for _, child := range n.Children {
+		childVulnerable, childOther, childNegated := child.cpes()
+		vulnerable.Merge(childVulnerable)
+		other.Merge(childOther)
+		negated = negated || childNegated
+	}
+	return vulnerable, other, negated
+}
+
+func sorted(s *strset.Set) []string {
+	if s.IsEmpty() {
+		return nil
+	}
+	values := s.List()
+	sort.Strings(values)
+	return values
+}
@@ -0,0 +1,111 @@
+package nvd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigurations_CPEs(t *testing.T) {
+	tests := []struct {
+		name               string
+		configurations     string
+		expectedVulnerable []string
+		expectedPlatforms  []string
+	}{
+		{
+			name: "flat list of vulnerable CPEs",
+			configurations: `{"nodes": [{"operator": "OR", "children": [], "cpe_match": [
+				{"vulnerable": true, "cpe23Uri": "cpe:2.3:a:apache:log4j:*:*:*:*:*:*:*:*"},
+				{"vulnerable": true, "cpe23Uri": "cpe:2.3:a:apache:log4j:2.0:-:*:*:*:*:*:*"}
+			]}]}`,
+			expectedVulnerable: []string{
+				"cpe:2.3:a:apache:log4j:*:*:*:*:*:*:*:*",
+				"cpe:2.3:a:apache:log4j:2.0:-:*:*:*:*:*:*",
+			},
+		},
+		{
+			name: "running on windows",
+			configurations: `{"nodes": [{"operator": "AND", "children": [
+				{"operator": "OR", "cpe_match": [{"vulnerable": true, "cpe23Uri": "cpe:2.3:a:zoom:zoom:*:*:*:*:*:*:*:*"}]},
+				{"operator": "OR", "cpe_match": [
+					{"vulnerable": false, "cpe23Uri": "cpe:2.3:o:microsoft:windows:-:*:*:*:*:*:*:*"},
+					{"vulnerable": false, "cpe23Uri": "cpe:2.3:o:microsoft:windows_server:-:*:*:*:*:*:*:*"}
+				]}
+			], "cpe_match": []}]}`,
+			expectedVulnerable: []string{"cpe:2.3:a:zoom:zoom:*:*:*:*:*:*:*:*"},
+			expectedPlatforms: []string{
+				"cpe:2.3:o:microsoft:windows:-:*:*:*:*:*:*:*",
+				"cpe:2.3:o:microsoft:windows_server:-:*:*:*:*:*:*:*",
+			},
+		},
+		{
+			name: "running on different platforms",
+			configurations: `{"nodes": [
+				{"operator": "AND", "children": [
+					{"operator": "OR", "cpe_match": [{"vulnerable": true, "cpe23Uri": "cpe:2.3:a:vendor:app:*:*:*:*:*:*:*:*"}]},
+					{"operator": "OR", "cpe_match": [{"vulnerable": false, "cpe23Uri": "cpe:2.3:o:apple:macos:-:*:*:*:*:*:*:*"}]}
+				]},
+				{"operator": "AND", "children": [
+					{"operator": "OR", "cpe_match": [{"vulnerable": true, "cpe23Uri": "cpe:2.3:a:vendor:app_server:*:*:*:*:*:*:*:*"}]},
+					{"operator": "OR", "cpe_match": [{"vulnerable": false, "cpe23Uri": "cpe:2.3:o:microsoft:windows:-:*:*:*:*:*:*:*"}]}
+				]}
+			]}`,
+			expectedVulnerable: []string{
+				"cpe:2.3:a:vendor:app:*:*:*:*:*:*:*:*",
+				"cpe:2.3:a:vendor:app_server:*:*:*:*:*:*:*:*",
+			},
+			expectedPlatforms: []string{
+				"cpe:2.3:o:apple:macos:-:*:*:*:*:*:*:*",
+				"cpe:2.3:o:microsoft:windows:-:*:*:*:*:*:*:*",
+			},
+		},
+		{
+			name: "any unconstrained node drops all platforms",
+			configurations: `{"nodes": [
+				{"operator": "AND", "children": [
+					{"operator": "OR", "cpe_match": [{"vulnerable": true, "cpe23Uri": "cpe:2.3:a:vendor:app:*:*:*:*:*:*:*:*"}]},
+					{"operator": "OR", "cpe_match": [{"vulnerable": false, "cpe23Uri": "cpe:2.3:o:apple:macos:-:*:*:*:*:*:*:*"}]}
+				]},
+				{"operator": "OR", "cpe_match": [{"vulnerable": true, "cpe23Uri": "cpe:2.3:a:vendor:other:*:*:*:*:*:*:*:*"}]}
+			]}`,
+			expectedVulnerable: []string{
+				"cpe:2.3:a:vendor:app:*:*:*:*:*:*:*:*",
+				"cpe:2.3:a:vendor:other:*:*:*:*:*:*:*:*",
+			},
+		},
+		{
+			name: "negated platforms are not constraints",
+			configurations: `{"nodes": [{"operator": "AND", "children": [
+				{"operator": "OR", "cpe_match": [{"vulnerable": true, "cpe23Uri": "cpe:2.3:a:vendor:app:*:*:*:*:*:*:*:*"}]},
+				{"operator": "OR", "negate": true, "cpe_match": [{"vulnerable": false, "cpe23Uri": "cpe:2.3:o:apple:macos:-:*:*:*:*:*:*:*"}]}
+			]}]}`,
+			expectedVulnerable: []string{"cpe:2.3:a:vendor:app:*:*:*:*:*:*:*:*"},
+		},
+		{
+			name: "nodes without vulnerable CPEs are ignored",
+			configurations: `{"nodes": [
+				{"operator": "OR", "cpe_match": [{"vulnerable": false, "cpe23Uri": "cpe:2.3:o:apple:macos:-:*:*:*:*:*:*:*"}]},
+				{"operator": "AND", "children": [
+					{"operator": "OR", "cpe_match": [{"vulnerable": true, "cpe23Uri": "cpe:2.3:a:vendor:app:*:*:*:*:*:*:*:*"}]},
+					{"operator": "OR", "cpe_match": [{"vulnerable": false, "cpe23Uri": "cpe:2.3:o:linux:linux_kernel:-:*:*:*:*:*:*:*"}]}
+				]}
+			]}`,
+			expectedVulnerable: []string{"cpe:2.3:a:vendor:app:*:*:*:*:*:*:*:*"},
+			expectedPlatforms:  []string{"cpe:2.3:o:linux:linux_kernel:-:*:*:*:*:*:*:*"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var configurations Configurations
+			require.NoError(t, json.Unmarshal([]byte(test.configurations), &configurations))
+
+			vulnerable, platforms := configurations.CPEs()
+			assert.Equal(t, test.expectedVulnerable, vulnerable)
+			assert.Equal(t, test.expectedPlatforms, platforms)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package osv
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Record is an advisory in the OSV format (see https://ossf.github.io/osv-schema), as returned by the OSV API
+// (e.g. https://api.osv.dev/v1/vulns/RUSTSEC-2021-0001) or found within an ecosystem export
+// (e.g. https://osv-vulnerabilities.storage.googleapis.com/crates.io/all.zip).
+type Record struct {
+	ID               string                 `json:"id"`
+	Modified         string                 `json:"modified"`
+	Published        string                 `json:"published"`
+	Withdrawn        string                 `json:"withdrawn"`
+	Aliases          []string               `json:"aliases"`
+	Related          []string               `json:"related"`
+	Summary          string                 `json:"summary"`
+	Details          string                 `json:"details"`
+	Severity         []Severity             `json:"severity"`
+	Affected         []Affected             `json:"affected"`
+	References       []Reference            `json:"references"`
+	DatabaseSpecific map[string]interface{} `json:"database_specific"`
+}
+
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type Affected struct {
+	Package          Package                `json:"package"`
+	Ranges           []Range                `json:"ranges"`
+	Versions         []string               `json:"versions"`
+	DatabaseSpecific map[string]interface{} `json:"database_specific"`
+}
+
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	PURL      string `json:"purl"`
+}
+
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+type Reference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Parse reads one or more OSV records from the given JSON, which is either a single record or a list of records.
+func Parse(reader io.Reader) ([]Record, error) {
+	contents, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read OSV records: %w", err)
+	}
+
+	contents = bytes.TrimSpace(contents)
+	if bytes.HasPrefix(contents, []byte("[")) {
+		var records []Record
+		if err := json.Unmarshal(contents, &records); err != nil {
+			return nil, fmt.Errorf("unable to parse OSV records: %w", err)
+		}
+		return records, nil
+	}
+
+	var record Record
+	if err := json.Unmarshal(contents, &record); err != nil {
+		return nil, fmt.Errorf("unable to parse OSV record: %w", err)
+	}
+	return []Record{record}, nil
+}
+
+// ParseArchive reads all OSV records from an ecosystem export (a zip with one JSON file per record), ordered by ID.
+func ParseArchive(archivePath string) ([]Record, error) {
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open OSV archive: %w", err)
+	}
+	defer archive.Close()
+
+	var records []Record
+	for _, f := range archive.File {
+		if f.FileInfo().IsDir() || !strings.EqualFold(path.Ext(f.Name), ".json") {
+			continue
+		}
+
+		parsed, err := parseArchiveFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %q from OSV archive: %w", f.Name, err)
+		}
+		records = append(records, parsed...)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].ID < records[j].ID
+	})
+	return records, nil
+}
+
+func parseArchiveFile(f *zip.File) ([]Record, error) {
+	reader, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return Parse(reader)
+}
@@ -0,0 +1,81 @@
+package osv
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		fixture     string
+		expectedIDs []string
+	}{
+		{
+			fixture:     "test-fixtures/RUSTSEC-2021-0078.json",
+			expectedIDs: []string{"RUSTSEC-2021-0078"},
+		},
+		{
+			fixture:     "test-fixtures/multiple.json",
+			expectedIDs: []string{"GO-2022-0493", "GHSA-xxxx-yyyy-zzzz"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fixture, func(t *testing.T) {
+			f, err := os.Open(test.fixture)
+			require.NoError(t, err)
+			defer f.Close()
+
+			records, err := Parse(f)
+			require.NoError(t, err)
+
+			var actual []string
+			for _, r := range records {
+				actual = append(actual, r.ID)
+			}
+			assert.Equal(t, test.expectedIDs, actual)
+		})
+	}
+}
+
+func TestParse_Record(t *testing.T) {
+	f, err := os.Open("test-fixtures/RUSTSEC-2021-0078.json")
+	require.NoError(t, err)
+	defer f.Close()
+
+	records, err := Parse(f)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	record := records[0]
+	assert.Equal(t, []string{"CVE-2021-32715", "GHSA-5h46-h7hh-c6x9"}, record.Aliases)
+	require.Len(t, record.Affected, 1)
+	assert.Equal(t, Package{Ecosystem: "crates.io", Name: "hyper", PURL: "pkg:cargo/hyper"}, record.Affected[0].Package)
+	assert.Equal(t, []Range{
+		{
+			Type:   "SEMVER",
+			Events: []Event{{Introduced: "0.0.0-0"}, {Fixed: "0.14.10"}},
+		},
+	}, record.Affected[0].Ranges)
+	assert.Len(t, record.References, 2)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse(strings.NewReader(`{"id": 1}`))
+	assert.Error(t, err)
+}
+
+func TestParseArchive(t *testing.T) {
+	records, err := ParseArchive("test-fixtures/crates.io.zip")
+	require.NoError(t, err)
+
+	var actual []string
+	for _, r := range records {
+		actual = append(actual, r.ID)
+	}
+	assert.Equal(t, []string{"RUSTSEC-2020-0001", "RUSTSEC-2021-0078"}, actual)
+}
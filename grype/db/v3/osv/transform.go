@@ -0,0 +1,292 @@
+package osv
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	v3 "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/version"
+)
+
+// ecosystem describes how advisories from an OSV ecosystem are stored within the database.
+type ecosystem struct {
+	format version.Format
+	// githubNamespace is where the same advisories are stored when sourced from the GitHub advisory database (if any)
+	githubNamespace string
+	// normalizeName returns the package name as it is searched for at match time (nil when the name is used as-is)
+	normalizeName func(string) string
+}
+
+// ecosystems are the OSV ecosystems that are supported, by the ecosystem name used within OSV records.
+var ecosystems = map[string]ecosystem{
+	"crates.io": {format: version.SemanticFormat, githubNamespace: "github:rust"},
+	"Go":        {format: version.GolangFormat, githubNamespace: "github:go"},
+	"Hex":       {format: version.HexFormat, githubNamespace: "github:erlang", normalizeName: strings.ToLower},
+	"Pub":       {format: version.SemanticFormat, githubNamespace: "github:pub"},
+	"SwiftURL":  {format: version.SemanticFormat, githubNamespace: "github:swift"},
+	"CRAN":      {format: version.RFormat},
+	"Hackage":   {format: version.HaskellFormat},
+	"Bitnami":   {format: version.BitnamiFormat},
+	"Homebrew":  {format: version.UnknownFormat},
+	"RubyGems":  {format: version.GemFormat, githubNamespace: "github:gem"},
+	"npm":       {format: version.NpmFormat, githubNamespace: "github:npm"},
+	"PyPI":      {format: version.PythonFormat, githubNamespace: "github:python"},
+	"Maven":     {format: version.MavenFormat, githubNamespace: "github:java"},
+	"NuGet":     {format: version.NugetFormat, githubNamespace: "github:nuget"},
+	// composer package names are case-insensitive (see githubComposerPackageNamer)
+	"Packagist": {format: version.ComposerFormat, githubNamespace: "github:composer", normalizeName: strings.ToLower},
+}
+
+// Transformer converts OSV records into vulnerability records. When a metadata reader is given (typically the database
+// being built), advisories that are already present within the GitHub namespace of the same ecosystem (by ID or by a
+// GHSA alias) are skipped, since OSV mirrors the GitHub advisory database for most ecosystems.
+type Transformer struct {
+	existing v3.VulnerabilityMetadataStoreReader
+}
+
+func NewTransformer(existing v3.VulnerabilityMetadataStoreReader) *Transformer {
+	return &Transformer{
+		existing: existing,
+	}
+}
+
+// Transform returns the vulnerability records (one for each affected package) and the metadata records (one for each
+// namespace) for the given OSV record. Withdrawn records, affected packages from unsupported ecosystems, and affected
+// packages that are already covered by a GitHub advisory result in no records.
+func (t *Transformer) Transform(record Record) ([]v3.Vulnerability, []v3.VulnerabilityMetadata, error) {
+	if record.Withdrawn != "" {
+		return nil, nil, nil
+	}
+
+	var vulnerabilities []v3.Vulnerability
+	metadataByNamespace := make(map[string]v3.VulnerabilityMetadata)
+	for _, affected := range record.Affected {
+		eco, ok := ecosystems[affected.Package.Ecosystem]
+		if !ok || affected.Package.Name == "" {
+			continue
+		}
+
+		covered, err := t.coveredByGitHub(record, eco)
+		if err != nil {
+			return nil, nil, err
+		}
+		if covered {
+			continue
+		}
+
+		namespace := v3.OSVNamespace(affected.Package.Ecosystem)
+		vulnerabilities = append(vulnerabilities, newVulnerability(record, affected, eco, namespace))
+		if _, exists := metadataByNamespace[namespace]; !exists {
+			metadataByNamespace[namespace] = newVulnerabilityMetadata(record, namespace)
+		}
+	}
+
+	metadata := make([]v3.VulnerabilityMetadata, 0, len(metadataByNamespace))
+	for _, m := range metadataByNamespace {
+		metadata = append(metadata, m)
+	}
+	sort.Slice(metadata, func(i, j int) bool {
+		return metadata[i].Namespace < metadata[j].Namespace
+	})
+
+	return vulnerabilities, metadata, nil
+}
+
+// coveredByGitHub indicates if the given record (or any of its GHSA aliases) is already present within the GitHub
+// namespace of the ecosystem.
+func (t *Transformer) coveredByGitHub(record Record, eco ecosystem) (bool, error) {
+	if t.existing == nil || eco.githubNamespace == "" {
+		return false, nil
+	}
+	for _, id := range append([]string{record.ID}, record.Aliases...) {
+		if !isGHSA(id) {
+			continue
+		}
+		metadata, err := t.existing.GetVulnerabilityMetadata(id, eco.githubNamespace)
+		if err != nil {
+			return false, fmt.Errorf("unable to check for existing advisory=%q: %w", id, err)
+		}
+		if metadata != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newVulnerability(record Record, affected Affected, eco ecosystem, namespace string) v3.Vulnerability {
+	name := affected.Package.Name
+	if eco.normalizeName != nil {
+		name = eco.normalizeName(name)
+	}
+
+	constraint, fixedVersions := constraintAndFixes(affected)
+	fix := v3.Fix{
+		Versions: fixedVersions,
+		State:    v3.NotFixedState,
+	}
+	if len(fixedVersions) > 0 {
+		fix.State = v3.FixedState
+	}
+
+	var format string
+	if eco.format != version.UnknownFormat {
+		format = eco.format.String()
+	}
+
+	return v3.Vulnerability{
+		ID:                     record.ID,
+		PackageName:            name,
+		Namespace:              namespace,
+		VersionConstraint:      constraint,
+		VersionFormat:          format,
+		CPEs:                   []string{},
+		RelatedVulnerabilities: relatedVulnerabilities(record, eco, namespace),
+		Fix:                    fix,
+		Advisories:             []v3.Advisory{},
+	}
+}
+
+func newVulnerabilityMetadata(record Record, namespace string) v3.VulnerabilityMetadata {
+	var urls []string
+	for _, ref := range record.References {
+		if ref.URL != "" {
+			urls = append(urls, ref.URL)
+		}
+	}
+
+	description := record.Summary
+	if description == "" {
+		description = record.Details
+	}
+
+	return v3.VulnerabilityMetadata{
+		ID:           record.ID,
+		Namespace:    namespace,
+		DataSource:   fmt.Sprintf("https://osv.dev/vulnerability/%s", record.ID),
+		RecordSource: v3.RecordSource(v3.OSVNamespacePrefix, namespace),
+		Severity:     severity(record),
+		URLs:         urls,
+		Description:  description,
+		Cvss:         []v3.Cvss{},
+		Published:    timestamp(record.Published),
+		Modified:     timestamp(record.Modified),
+		CWEs:         cweIDs(record),
+	}
+}
+
+// timestamp parses an RFC3339 timestamp of a record, where a missing or malformed timestamp is unknown (nil).
+func timestamp(value string) *time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// constraintAndFixes returns the version constraint for all affected versions (where each range or version is OR'd
+// together) and the versions that fix the package. Note that git commit ranges are not supported.
+func constraintAndFixes(affected Affected) (string, []string) {
+	var terms, fixes []string
+	allAffected := false
+	for _, r := range affected.Ranges {
+		if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+			continue
+		}
+
+		var introduced string
+		open := false
+		for _, event := range r.Events {
+			switch {
+			case event.Introduced != "":
+				introduced, open = event.Introduced, true
+			case event.Fixed != "":
+				terms = append(terms, rangeTerm(introduced, "<", event.Fixed))
+				fixes = append(fixes, event.Fixed)
+				open = false
+			case event.LastAffected != "":
+				terms = append(terms, rangeTerm(introduced, "<=", event.LastAffected))
+				open = false
+			case event.Limit != "":
+				terms = append(terms, rangeTerm(introduced, "<", event.Limit))
+				open = false
+			}
+		}
+		if open {
+			if introduced == "0" {
+				allAffected = true
+				continue
+			}
+			terms = append(terms, fmt.Sprintf(">= %s", introduced))
+		}
+	}
+
+	for _, v := range affected.Versions {
+		terms = append(terms, fmt.Sprintf("= %s", v))
+	}
+
+	if allAffected {
+		// an empty constraint is satisfied by any version
+		return "", fixes
+	}
+	return strings.Join(terms, " || "), fixes
+}
+
+func rangeTerm(introduced, operator, upper string) string {
+	if introduced == "" || introduced == "0" {
+		return fmt.Sprintf("%s %s", operator, upper)
+	}
+	return fmt.Sprintf(">= %s, %s %s", introduced, operator, upper)
+}
+
+// relatedVulnerabilities returns references to the aliases of the record: CVEs are related to the NVD record, GHSAs
+// to the GitHub advisory of the same ecosystem, and any other alias (e.g. "PYSEC-2021-1") to the same OSV namespace.
+func relatedVulnerabilities(record Record, eco ecosystem, namespace string) []v3.VulnerabilityReference {
+	var related []v3.VulnerabilityReference
+	for _, alias := range record.Aliases {
+		switch {
+		case strings.HasPrefix(alias, "CVE-"):
+			related = append(related, v3.VulnerabilityReference{ID: alias, Namespace: v3.NVDNamespace})
+		case isGHSA(alias) && eco.githubNamespace != "":
+			related = append(related, v3.VulnerabilityReference{ID: alias, Namespace: eco.githubNamespace})
+		default:
+			related = append(related, v3.VulnerabilityReference{ID: alias, Namespace: namespace})
+		}
+	}
+	return related
+}
+
+// severity returns the severity of the record as given by the original advisory database (e.g. "MODERATE" from a
+// GitHub advisory), since OSV itself only provides CVSS vectors.
+func severity(record Record) string {
+	value, _ := record.DatabaseSpecific["severity"].(string)
+	switch strings.ToUpper(value) {
+	case "CRITICAL":
+		return "Critical"
+	case "HIGH":
+		return "High"
+	case "MODERATE", "MEDIUM":
+		return "Medium"
+	case "LOW":
+		return "Low"
+	}
+	return "Unknown"
+}
+
+// cweIDs returns the CWE identifiers of the weaknesses of the record (e.g. "CWE-79"), as listed within the database
+// specific data of GitHub advisories.
+func cweIDs(record Record) []string {
+	values, _ := record.DatabaseSpecific["cwe_ids"].([]interface{})
+	var ids []string
+	for _, v := range values {
+		if id, ok := v.(string); ok && id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func isGHSA(id string) bool {
+	return strings.HasPrefix(id, "GHSA-")
+}
@@ -0,0 +1,237 @@
+package osv
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v3 "github.com/anchore/grype/grype/db/v3"
+)
+
+type mockMetadataStore struct {
+	data map[string]map[string]*v3.VulnerabilityMetadata
+}
+
+func (s *mockMetadataStore) GetVulnerabilityMetadata(id, namespace string) (*v3.VulnerabilityMetadata, error) {
+	return s.data[id][namespace], nil
+}
+
+func loadRecords(t *testing.T, fixture string) []Record {
+	t.Helper()
+	f, err := os.Open(fixture)
+	require.NoError(t, err)
+	defer f.Close()
+
+	records, err := Parse(f)
+	require.NoError(t, err)
+	return records
+}
+
+func TestTransformer_Transform(t *testing.T) {
+	records := loadRecords(t, "test-fixtures/RUSTSEC-2021-0078.json")
+	vulns, metadata, err := NewTransformer(nil).Transform(records[0])
+	require.NoError(t, err)
+
+	assert.Equal(t, []v3.Vulnerability{
+		{
+			ID:                "RUSTSEC-2021-0078",
+			PackageName:       "hyper",
+			Namespace:         "osv:crates.io",
+			VersionConstraint: ">= 0.0.0-0, < 0.14.10",
+			VersionFormat:     "Semantic",
+			CPEs:              []string{},
+			RelatedVulnerabilities: []v3.VulnerabilityReference{
+				{ID: "CVE-2021-32715", Namespace: "nvd"},
+				{ID: "GHSA-5h46-h7hh-c6x9", Namespace: "github:rust"},
+			},
+			Fix: v3.Fix{
+				Versions: []string{"0.14.10"},
+				State:    v3.FixedState,
+			},
+			Advisories: []v3.Advisory{},
+		},
+	}, vulns)
+
+	assert.Equal(t, []v3.VulnerabilityMetadata{
+		{
+			ID:           "RUSTSEC-2021-0078",
+			Namespace:    "osv:crates.io",
+			DataSource:   "https://osv.dev/vulnerability/RUSTSEC-2021-0078",
+			RecordSource: "osv:osv:crates.io",
+			Severity:     "Unknown",
+			URLs: []string{
+				"https://crates.io/crates/hyper",
+				"https://rustsec.org/advisories/RUSTSEC-2021-0078.html",
+			},
+			Description: "Lenient `hyper` header parsing of `Content-Length` could allow request smuggling",
+			Cvss:        []v3.Cvss{},
+			Published:   timeRef(time.Date(2021, 7, 7, 12, 0, 0, 0, time.UTC)),
+			Modified:    timeRef(time.Date(2021, 7, 14, 12, 0, 0, 0, time.UTC)),
+		},
+	}, metadata)
+}
+
+func TestTransformer_Transform_Multiple(t *testing.T) {
+	records := loadRecords(t, "test-fixtures/multiple.json")
+	require.Len(t, records, 2)
+
+	vulns, metadata, err := NewTransformer(nil).Transform(records[0])
+	require.NoError(t, err)
+	require.Len(t, vulns, 1)
+	assert.Equal(t, "golang.org/x/sys", vulns[0].PackageName)
+	assert.Equal(t, "osv:go", vulns[0].Namespace)
+	assert.Equal(t, "Go", vulns[0].VersionFormat)
+	assert.Equal(t, "< 0.0.0-20220412211240-33da011f77ad", vulns[0].VersionConstraint)
+	require.Len(t, metadata, 1)
+	// the details are used when there is no summary
+	assert.Equal(t, records[0].Details, metadata[0].Description)
+
+	// withdrawn records result in no vulnerabilities
+	vulns, metadata, err = NewTransformer(nil).Transform(records[1])
+	require.NoError(t, err)
+	assert.Empty(t, vulns)
+	assert.Empty(t, metadata)
+}
+
+func TestTransformer_Transform_CoveredByGitHub(t *testing.T) {
+	records := loadRecords(t, "test-fixtures/RUSTSEC-2021-0078.json")
+
+	tests := []struct {
+		name          string
+		existing      map[string]map[string]*v3.VulnerabilityMetadata
+		expectedVulns int
+	}{
+		{
+			name:          "no existing advisories",
+			expectedVulns: 1,
+		},
+		{
+			name: "GHSA alias exists in the github namespace",
+			existing: map[string]map[string]*v3.VulnerabilityMetadata{
+				"GHSA-5h46-h7hh-c6x9": {"github:rust": {ID: "GHSA-5h46-h7hh-c6x9", Namespace: "github:rust"}},
+			},
+			expectedVulns: 0,
+		},
+		{
+			name: "GHSA alias exists in another github namespace",
+			existing: map[string]map[string]*v3.VulnerabilityMetadata{
+				"GHSA-5h46-h7hh-c6x9": {"github:npm": {ID: "GHSA-5h46-h7hh-c6x9", Namespace: "github:npm"}},
+			},
+			expectedVulns: 1,
+		},
+		{
+			name: "CVE alias exists",
+			existing: map[string]map[string]*v3.VulnerabilityMetadata{
+				"CVE-2021-32715": {"nvd": {ID: "CVE-2021-32715", Namespace: "nvd"}},
+			},
+			expectedVulns: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			transformer := NewTransformer(&mockMetadataStore{data: test.existing})
+			vulns, metadata, err := transformer.Transform(records[0])
+			require.NoError(t, err)
+			assert.Len(t, vulns, test.expectedVulns)
+			assert.Len(t, metadata, test.expectedVulns)
+		})
+	}
+}
+
+func TestConstraintAndFixes(t *testing.T) {
+	tests := []struct {
+		name               string
+		affected           Affected
+		expectedConstraint string
+		expectedFixes      []string
+	}{
+		{
+			name: "fixed from the first version",
+			affected: Affected{Ranges: []Range{
+				{Type: "ECOSYSTEM", Events: []Event{{Introduced: "0"}, {Fixed: "1.2.3"}}},
+			}},
+			expectedConstraint: "< 1.2.3",
+			expectedFixes:      []string{"1.2.3"},
+		},
+		{
+			name: "multiple introduced and fixed pairs",
+			affected: Affected{Ranges: []Range{
+				{Type: "SEMVER", Events: []Event{{Introduced: "1.0.0"}, {Fixed: "1.0.5"}, {Introduced: "2.0.0"}, {Fixed: "2.1.0"}}},
+			}},
+			expectedConstraint: ">= 1.0.0, < 1.0.5 || >= 2.0.0, < 2.1.0",
+			expectedFixes:      []string{"1.0.5", "2.1.0"},
+		},
+		{
+			name: "last affected and limit",
+			affected: Affected{Ranges: []Range{
+				{Type: "ECOSYSTEM", Events: []Event{{Introduced: "1.0"}, {LastAffected: "1.4"}}},
+				{Type: "ECOSYSTEM", Events: []Event{{Introduced: "0"}, {Limit: "0.9"}}},
+			}},
+			expectedConstraint: ">= 1.0, <= 1.4 || < 0.9",
+		},
+		{
+			name: "not fixed",
+			affected: Affected{Ranges: []Range{
+				{Type: "ECOSYSTEM", Events: []Event{{Introduced: "3.0"}}},
+			}},
+			expectedConstraint: ">= 3.0",
+		},
+		{
+			name: "all versions",
+			affected: Affected{Ranges: []Range{
+				{Type: "ECOSYSTEM", Events: []Event{{Introduced: "1.0"}, {Fixed: "1.1"}}},
+				{Type: "ECOSYSTEM", Events: []Event{{Introduced: "0"}}},
+			}},
+			expectedConstraint: "",
+			expectedFixes:      []string{"1.1"},
+		},
+		{
+			name: "explicit versions and git ranges",
+			affected: Affected{
+				Ranges: []Range{
+					{Type: "GIT", Events: []Event{{Introduced: "0"}, {Fixed: "a1b2c3d"}}},
+				},
+				Versions: []string{"1.0.0", "1.0.1"},
+			},
+			expectedConstraint: "= 1.0.0 || = 1.0.1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			constraint, fixes := constraintAndFixes(test.affected)
+			assert.Equal(t, test.expectedConstraint, constraint)
+			assert.Equal(t, test.expectedFixes, fixes)
+		})
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	tests := map[string]string{
+		"CRITICAL": "Critical",
+		"HIGH":     "High",
+		"MODERATE": "Medium",
+		"low":      "Low",
+		"":         "Unknown",
+	}
+	for value, expected := range tests {
+		t.Run(value, func(t *testing.T) {
+			record := Record{DatabaseSpecific: map[string]interface{}{"severity": value}}
+			assert.Equal(t, expected, severity(record))
+		})
+	}
+}
+
+func TestCWEIDs(t *testing.T) {
+	record := Record{DatabaseSpecific: map[string]interface{}{"cwe_ids": []interface{}{"CWE-1321", "", 20}}}
+	assert.Equal(t, []string{"CWE-1321"}, cweIDs(record))
+	assert.Nil(t, cweIDs(Record{}))
+}
+
+func timeRef(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,260 @@
+package oval
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	v3 "github.com/anchore/grype/grype/db/v3"
+)
+
+// document is the subset of an OVAL definitions document (see https://oval.mitre.org/language/) that describes which
+// package versions are affected by each definition.
+type document struct {
+	Definitions []definition `xml:"definitions>definition"`
+	Tests       struct {
+		Items []test `xml:",any"`
+	} `xml:"tests"`
+	Objects struct {
+		Items []object `xml:",any"`
+	} `xml:"objects"`
+	States struct {
+		Items []state `xml:",any"`
+	} `xml:"states"`
+}
+
+type definition struct {
+	ID       string `xml:"id,attr"`
+	Class    string `xml:"class,attr"`
+	Metadata struct {
+		Title       string      `xml:"title"`
+		Description string      `xml:"description"`
+		References  []reference `xml:"reference"`
+		Advisory    struct {
+			Severity string `xml:"severity"`
+		} `xml:"advisory"`
+	} `xml:"metadata"`
+	Criteria criteria `xml:"criteria"`
+}
+
+type reference struct {
+	ID     string `xml:"ref_id,attr"`
+	URL    string `xml:"ref_url,attr"`
+	Source string `xml:"source,attr"`
+}
+
+type criteria struct {
+	Criteria  []criteria `xml:"criteria"`
+	Criterion []struct {
+		TestRef string `xml:"test_ref,attr"`
+	} `xml:"criterion"`
+}
+
+// test is a package test (e.g. "rpminfo_test" or "dpkginfo_test") that checks the state of an object.
+type test struct {
+	XMLName xml.Name
+	ID      string `xml:"id,attr"`
+	Object  struct {
+		Ref string `xml:"object_ref,attr"`
+	} `xml:"object"`
+	State struct {
+		Ref string `xml:"state_ref,attr"`
+	} `xml:"state"`
+}
+
+type object struct {
+	ID   string `xml:"id,attr"`
+	Name string `xml:"name"`
+}
+
+type state struct {
+	ID  string `xml:"id,attr"`
+	EVR struct {
+		Value     string `xml:",chardata"`
+		Operation string `xml:"operation,attr"`
+	} `xml:"evr"`
+}
+
+// versionFormats are the version formats of the packages checked by each kind of test.
+var versionFormats = map[string]string{
+	"rpminfo_test":  "rpm",
+	"dpkginfo_test": "dpkg",
+}
+
+// Transform converts the definitions within the given OVAL document into vulnerability records within the given
+// namespace. Only the package version checks of each definition are considered (e.g. "openssl is earlier than
+// 1:1.1.1g-12.el8_3"), any other checks (e.g. the release or the signing key of the packages) are ignored.
+func Transform(reader io.Reader, namespace string) ([]v3.Vulnerability, []v3.VulnerabilityMetadata, error) {
+	var doc document
+	if err := xml.NewDecoder(reader).Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse OVAL definitions: %w", err)
+	}
+
+	tests := make(map[string]test)
+	for _, t := range doc.Tests.Items {
+		tests[t.ID] = t
+	}
+	objects := make(map[string]object)
+	for _, o := range doc.Objects.Items {
+		objects[o.ID] = o
+	}
+	states := make(map[string]state)
+	for _, s := range doc.States.Items {
+		states[s.ID] = s
+	}
+
+	var vulnerabilities []v3.Vulnerability
+	var metadata []v3.VulnerabilityMetadata
+	for _, def := range doc.Definitions {
+		id, related := identifiers(def)
+
+		var defVulnerabilities []v3.Vulnerability
+		seen := make(map[string]struct{})
+		for _, testRef := range def.Criteria.testRefs() {
+			t, ok := tests[testRef]
+			if !ok {
+				continue
+			}
+			format, ok := versionFormats[t.XMLName.Local]
+			if !ok {
+				continue
+			}
+			name := strings.TrimSpace(objects[t.Object.Ref].Name)
+			s, ok := states[t.State.Ref]
+			if name == "" || !ok {
+				continue
+			}
+
+			constraint, fix, ok := constraintAndFix(s)
+			if !ok {
+				continue
+			}
+
+			// the same package is often checked for each architecture
+			key := name + constraint
+			if _, exists := seen[key]; exists {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			defVulnerabilities = append(defVulnerabilities, v3.Vulnerability{
+				ID:                     id,
+				PackageName:            name,
+				Namespace:              namespace,
+				VersionConstraint:      constraint,
+				VersionFormat:          format,
+				CPEs:                   []string{},
+				RelatedVulnerabilities: related,
+				Fix:                    fix,
+				Advisories:             []v3.Advisory{},
+			})
+		}
+
+		if len(defVulnerabilities) == 0 {
+			continue
+		}
+		vulnerabilities = append(vulnerabilities, defVulnerabilities...)
+		metadata = append(metadata, newMetadata(def, id, namespace))
+	}
+
+	return vulnerabilities, metadata, nil
+}
+
+// testRefs returns the references to all tests within the criteria (and nested criteria), in document order.
+func (c criteria) testRefs() []string {
+	var refs []string
+	for _, criterion := range c.Criterion {
+		refs = append(refs, criterion.TestRef)
+	}
+	for _, nested := range c.Criteria {
+		refs = append(refs, nested.testRefs()...)
+	}
+	return refs
+}
+
+// identifiers returns the ID of the definition (the first reference, e.g. "RHSA-2021:0001" or "CVE-2021-3449", or the
+// definition ID when there are no references) and the CVEs that are related to it.
+func identifiers(def definition) (string, []v3.VulnerabilityReference) {
+	id := def.ID
+	if len(def.Metadata.References) > 0 && def.Metadata.References[0].ID != "" {
+		id = def.Metadata.References[0].ID
+	}
+
+	var related []v3.VulnerabilityReference
+	for _, ref := range def.Metadata.References {
+		if ref.ID == id || !strings.HasPrefix(ref.ID, "CVE-") {
+			continue
+		}
+		related = append(related, v3.VulnerabilityReference{ID: ref.ID, Namespace: v3.NVDNamespace})
+	}
+	sort.Slice(related, func(i, j int) bool {
+		return related[i].ID < related[j].ID
+	})
+	return id, related
+}
+
+// constraintAndFix returns the version constraint of a package state (e.g. "less than 1:1.1.1g-12.el8_3"), where
+// only states that describe affected versions are supported.
+func constraintAndFix(s state) (string, v3.Fix, bool) {
+	value := strings.TrimSpace(s.EVR.Value)
+	if value == "" {
+		return "", v3.Fix{}, false
+	}
+	switch s.EVR.Operation {
+	case "less than":
+		return fmt.Sprintf("< %s", value), v3.Fix{Versions: []string{value}, State: v3.FixedState}, true
+	case "less than or equal":
+		return fmt.Sprintf("<= %s", value), v3.Fix{State: v3.NotFixedState}, true
+	}
+	return "", v3.Fix{}, false
+}
+
+func newMetadata(def definition, id, namespace string) v3.VulnerabilityMetadata {
+	var urls []string
+	for _, ref := range def.Metadata.References {
+		if ref.URL != "" {
+			urls = append(urls, ref.URL)
+		}
+	}
+
+	var dataSource string
+	if len(urls) > 0 {
+		dataSource = urls[0]
+	}
+
+	description := strings.TrimSpace(def.Metadata.Description)
+	if description == "" {
+		description = strings.TrimSpace(def.Metadata.Title)
+	}
+
+	return v3.VulnerabilityMetadata{
+		ID:           id,
+		Namespace:    namespace,
+		DataSource:   dataSource,
+		RecordSource: v3.RecordSource("oval", namespace),
+		Severity:     severity(def.Metadata.Advisory.Severity),
+		URLs:         urls,
+		Description:  description,
+		Cvss:         []v3.Cvss{},
+	}
+}
+
+// severity normalizes the severity of a definition, where vendors use different scales (e.g. "Important" for Red Hat
+// and "Moderate" for Red Hat and SUSE).
+func severity(value string) string {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "critical":
+		return "Critical"
+	case "important", "high":
+		return "High"
+	case "moderate", "medium":
+		return "Medium"
+	case "low":
+		return "Low"
+	case "negligible":
+		return "Negligible"
+	}
+	return "Unknown"
+}
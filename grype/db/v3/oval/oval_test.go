@@ -0,0 +1,128 @@
+package oval
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	v3 "github.com/anchore/grype/grype/db/v3"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransform(t *testing.T) {
+	f, err := os.Open("test-fixtures/definitions.xml")
+	require.NoError(t, err)
+	defer f.Close()
+
+	vulnerabilities, metadata, err := Transform(f, "example:8")
+	require.NoError(t, err)
+
+	related := []v3.VulnerabilityReference{
+		{ID: "CVE-2021-3449", Namespace: "nvd"},
+		{ID: "CVE-2021-3450", Namespace: "nvd"},
+	}
+	expectedVulnerabilities := []v3.Vulnerability{
+		{
+			ID:                     "EXSA-2021:0001",
+			PackageName:            "openssl",
+			Namespace:              "example:8",
+			VersionConstraint:      "< 1:1.1.1g-15.el8_3",
+			VersionFormat:          "rpm",
+			CPEs:                   []string{},
+			RelatedVulnerabilities: related,
+			Fix: v3.Fix{
+				Versions: []string{"1:1.1.1g-15.el8_3"},
+				State:    v3.FixedState,
+			},
+			Advisories: []v3.Advisory{},
+		},
+		{
+			ID:                     "EXSA-2021:0001",
+			PackageName:            "openssl-libs",
+			Namespace:              "example:8",
+			VersionConstraint:      "< 1:1.1.1g-15.el8_3",
+			VersionFormat:          "rpm",
+			CPEs:                   []string{},
+			RelatedVulnerabilities: related,
+			Fix: v3.Fix{
+				Versions: []string{"1:1.1.1g-15.el8_3"},
+				State:    v3.FixedState,
+			},
+			Advisories: []v3.Advisory{},
+		},
+		{
+			ID:                "CVE-2021-44228",
+			PackageName:       "liblog4j2-java",
+			Namespace:         "example:8",
+			VersionConstraint: "<= 0:2.14.1-1",
+			VersionFormat:     "dpkg",
+			CPEs:              []string{},
+			Fix: v3.Fix{
+				State: v3.NotFixedState,
+			},
+			Advisories: []v3.Advisory{},
+		},
+	}
+	for _, d := range deep.Equal(expectedVulnerabilities, vulnerabilities) {
+		t.Errorf("vulnerability diff: %+v", d)
+	}
+
+	expectedMetadata := []v3.VulnerabilityMetadata{
+		{
+			ID:           "EXSA-2021:0001",
+			Namespace:    "example:8",
+			DataSource:   "https://security.example.com/EXSA-2021:0001",
+			RecordSource: "oval:example:8",
+			Severity:     "High",
+			URLs: []string{
+				"https://security.example.com/EXSA-2021:0001",
+				"https://security.example.com/cve/CVE-2021-3450",
+				"https://security.example.com/cve/CVE-2021-3449",
+			},
+			Description: "OpenSSL is a toolkit that implements the Secure Sockets Layer protocols.",
+			Cvss:        []v3.Cvss{},
+		},
+		{
+			ID:           "CVE-2021-44228",
+			Namespace:    "example:8",
+			DataSource:   "https://security.example.com/cve/CVE-2021-44228",
+			RecordSource: "oval:example:8",
+			Severity:     "Critical",
+			URLs:         []string{"https://security.example.com/cve/CVE-2021-44228"},
+			Description:  "CVE-2021-44228 on Example Linux 8 (log4j)",
+			Cvss:         []v3.Cvss{},
+		},
+	}
+	for _, d := range deep.Equal(expectedMetadata, metadata) {
+		t.Errorf("metadata diff: %+v", d)
+	}
+}
+
+func TestTransform_InvalidDocument(t *testing.T) {
+	_, _, err := Transform(strings.NewReader("<oval_definitions>"), "example:8")
+	assert.Error(t, err)
+}
+
+func TestSeverity(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{value: "Critical", expected: "Critical"},
+		{value: "Important", expected: "High"},
+		{value: "high", expected: "High"},
+		{value: "Moderate", expected: "Medium"},
+		{value: " Low ", expected: "Low"},
+		{value: "Negligible", expected: "Negligible"},
+		{value: "", expected: "Unknown"},
+		{value: "n/a", expected: "Unknown"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			assert.Equal(t, test.expected, severity(test.value))
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package private
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	v3 "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"gopkg.in/yaml.v2"
+)
+
+// Document is a set of private vulnerability records (e.g. internal advisories for first-party libraries), in YAML or
+// JSON:
+//
+//	vulnerabilities:
+//	  - id: ACME-2021-0001
+//	    namespace: github:java
+//	    package: com.acme:acme-core
+//	    constraint: ">= 1.0.0, < 1.4.2"
+//	    severity: high
+//	    fix: ["1.4.2"]
+//	    references: ["https://wiki.acme.com/security/ACME-2021-0001"]
+//	    published: 2021-12-10
+type Document struct {
+	Vulnerabilities []Record `yaml:"vulnerabilities"`
+}
+
+// Record describes a vulnerable package within a namespace, where the same vulnerability may be described by several
+// records (e.g. for several packages).
+type Record struct {
+	// ID is the ID of the vulnerability (e.g. "ACME-2021-0001")
+	ID string `yaml:"id"`
+	// Namespace is where packages are matched against the vulnerability (e.g. "github:java" for java packages, or
+	// "debian:10" for debian 10 packages)
+	Namespace string `yaml:"namespace"`
+	// Package is the name of the vulnerable package, as named within the namespace (e.g. "group:artifact" for java)
+	Package string `yaml:"package"`
+	// Constraint describes the vulnerable versions of the package (e.g. "< 1.4.2"), where all versions are vulnerable
+	// when empty
+	Constraint string `yaml:"constraint"`
+	// VersionFormat is how versions are compared (e.g. "maven" or "semver"), which is implied by the namespace when
+	// empty
+	VersionFormat string `yaml:"version-format"`
+	// Severity is one of negligible, low, medium, high or critical (unknown when empty)
+	Severity string `yaml:"severity"`
+	// Fix are the versions that the vulnerability was fixed in
+	Fix []string `yaml:"fix"`
+	// Description describes the vulnerability
+	Description string `yaml:"description"`
+	// References are URLs with more information about the vulnerability (where the first is shown as the data source)
+	References []string `yaml:"references"`
+	// Related are the CVEs that describe the same vulnerability (e.g. "CVE-2021-44228")
+	Related []string `yaml:"related"`
+	// Published is when the vulnerability was disclosed, as a date (e.g. "2021-12-10") or an RFC3339 timestamp
+	Published string `yaml:"published"`
+	// Modified is when the vulnerability was last changed, as a date or an RFC3339 timestamp
+	Modified string `yaml:"modified"`
+	// CWEs are the weaknesses of the vulnerability (e.g. "CWE-502")
+	CWEs []string `yaml:"cwes"`
+	// Exploitation notes whether (and how) the vulnerability is known to be exploited
+	Exploitation string `yaml:"exploitation"`
+}
+
+// Transform converts the records within the given YAML or JSON document into vulnerability records, returning an error
+// when any record is invalid.
+func Transform(reader io.Reader) ([]v3.Vulnerability, []v3.VulnerabilityMetadata, error) {
+	contents, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read private vulnerabilities: %w", err)
+	}
+
+	var doc Document
+	if err := yaml.UnmarshalStrict(contents, &doc); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse private vulnerabilities: %w", err)
+	}
+
+	var vulnerabilities []v3.Vulnerability
+	var metadata []v3.VulnerabilityMetadata
+	metadataIndex := make(map[string]int)
+	for idx, record := range doc.Vulnerabilities {
+		vuln, m, err := record.transform()
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid private vulnerability (#%d): %w", idx+1, err)
+		}
+		vulnerabilities = append(vulnerabilities, vuln)
+
+		key := m.Namespace + "/" + m.ID
+		if existingIdx, exists := metadataIndex[key]; exists {
+			existing := metadata[existingIdx]
+			if existing.Severity != m.Severity || existing.Description != m.Description {
+				return nil, nil, fmt.Errorf("invalid private vulnerability (#%d): mismatched severity or description for vulnerability=%q", idx+1, m.ID)
+			}
+			continue
+		}
+		metadataIndex[key] = len(metadata)
+		metadata = append(metadata, m)
+	}
+
+	return vulnerabilities, metadata, nil
+}
+
+func (r Record) transform() (v3.Vulnerability, v3.VulnerabilityMetadata, error) {
+	switch {
+	case r.ID == "":
+		return v3.Vulnerability{}, v3.VulnerabilityMetadata{}, fmt.Errorf("missing id")
+	case r.Namespace == "":
+		return v3.Vulnerability{}, v3.VulnerabilityMetadata{}, fmt.Errorf("missing namespace for vulnerability=%q", r.ID)
+	case r.Package == "":
+		return v3.Vulnerability{}, v3.VulnerabilityMetadata{}, fmt.Errorf("missing package for vulnerability=%q", r.ID)
+	}
+
+	if r.VersionFormat != "" && version.ParseFormat(r.VersionFormat) == version.UnknownFormat {
+		return v3.Vulnerability{}, v3.VulnerabilityMetadata{}, fmt.Errorf("vulnerability=%q: unsupported version format %q", r.ID, r.VersionFormat)
+	}
+
+	sev, err := severity(r.Severity)
+	if err != nil {
+		return v3.Vulnerability{}, v3.VulnerabilityMetadata{}, fmt.Errorf("vulnerability=%q: %w", r.ID, err)
+	}
+
+	fix := v3.Fix{Versions: []string{}, State: v3.NotFixedState}
+	if len(r.Fix) > 0 {
+		fix = v3.Fix{Versions: r.Fix, State: v3.FixedState}
+	}
+
+	related := []v3.VulnerabilityReference{}
+	for _, id := range r.Related {
+		if !strings.HasPrefix(id, "CVE-") {
+			return v3.Vulnerability{}, v3.VulnerabilityMetadata{}, fmt.Errorf("vulnerability=%q: related vulnerability %q is not a CVE", r.ID, id)
+		}
+		related = append(related, v3.VulnerabilityReference{ID: id, Namespace: v3.NVDNamespace})
+	}
+
+	vuln := v3.Vulnerability{
+		ID:                     r.ID,
+		PackageName:            r.Package,
+		Namespace:              r.Namespace,
+		VersionConstraint:      r.Constraint,
+		VersionFormat:          r.VersionFormat,
+		CPEs:                   []string{},
+		RelatedVulnerabilities: related,
+		Fix:                    fix,
+		Advisories:             []v3.Advisory{},
+	}
+
+	// ensure that the record can be matched against packages (e.g. the constraint is valid for the version format)
+	if _, err := vulnerability.NewVulnerability(vuln); err != nil {
+		return v3.Vulnerability{}, v3.VulnerabilityMetadata{}, fmt.Errorf("vulnerability=%q: %w", r.ID, err)
+	}
+
+	published, err := timestamp(r.Published)
+	if err != nil {
+		return v3.Vulnerability{}, v3.VulnerabilityMetadata{}, fmt.Errorf("vulnerability=%q: bad published date: %w", r.ID, err)
+	}
+	modified, err := timestamp(r.Modified)
+	if err != nil {
+		return v3.Vulnerability{}, v3.VulnerabilityMetadata{}, fmt.Errorf("vulnerability=%q: bad modified date: %w", r.ID, err)
+	}
+
+	var dataSource string
+	if len(r.References) > 0 {
+		dataSource = r.References[0]
+	}
+	urls := r.References
+	if urls == nil {
+		urls = []string{}
+	}
+
+	m := v3.VulnerabilityMetadata{
+		ID:           r.ID,
+		Namespace:    r.Namespace,
+		DataSource:   dataSource,
+		RecordSource: v3.RecordSource("private", r.Namespace),
+		Severity:     sev,
+		URLs:         urls,
+		Description:  r.Description,
+		Cvss:         []v3.Cvss{},
+		Published:    published,
+		Modified:     modified,
+		CWEs:         r.CWEs,
+		Exploitation: r.Exploitation,
+	}
+	return vuln, m, nil
+}
+
+// timestamp parses a date (e.g. "2021-12-10") or an RFC3339 timestamp, where an empty value is unknown (nil).
+func timestamp(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		if t, err = time.Parse("2006-01-02", value); err != nil {
+			return nil, fmt.Errorf("expected a date or an RFC3339 timestamp, got %q", value)
+		}
+	}
+	return &t, nil
+}
+
+func severity(value string) (string, error) {
+	if value == "" || strings.EqualFold(value, "unknown") {
+		return "Unknown", nil
+	}
+	sev := vulnerability.ParseSeverity(value)
+	if sev == vulnerability.UnknownSeverity {
+		return "", fmt.Errorf("unsupported severity %q", value)
+	}
+	name := sev.String()
+	return strings.ToUpper(name[:1]) + name[1:], nil
+}
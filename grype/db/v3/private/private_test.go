@@ -0,0 +1,182 @@
+package private
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	v3 "github.com/anchore/grype/grype/db/v3"
+	"github.com/go-test/deep"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransform(t *testing.T) {
+	agent := v3.Vulnerability{
+		ID:                     "ACME-2021-0002",
+		PackageName:            "acme-agent",
+		Namespace:              "debian:10",
+		VersionFormat:          "dpkg",
+		CPEs:                   []string{},
+		RelatedVulnerabilities: []v3.VulnerabilityReference{},
+		Fix:                    v3.Fix{Versions: []string{}, State: v3.NotFixedState},
+		Advisories:             []v3.Advisory{},
+	}
+	agentMetadata := v3.VulnerabilityMetadata{
+		ID:           "ACME-2021-0002",
+		Namespace:    "debian:10",
+		RecordSource: "private:debian:10",
+		Severity:     "Unknown",
+		URLs:         []string{},
+		Cvss:         []v3.Cvss{},
+	}
+
+	tests := []struct {
+		fixture                 string
+		expectedVulnerabilities []v3.Vulnerability
+		expectedMetadata        []v3.VulnerabilityMetadata
+	}{
+		{
+			fixture: "test-fixtures/vulnerabilities.yaml",
+			expectedVulnerabilities: []v3.Vulnerability{
+				{
+					ID:                     "ACME-2021-0001",
+					PackageName:            "com.acme:acme-core",
+					Namespace:              "github:java",
+					VersionConstraint:      ">= 1.0.0, < 1.4.2",
+					CPEs:                   []string{},
+					RelatedVulnerabilities: []v3.VulnerabilityReference{{ID: "CVE-2021-44228", Namespace: "nvd"}},
+					Fix:                    v3.Fix{Versions: []string{"1.4.2"}, State: v3.FixedState},
+					Advisories:             []v3.Advisory{},
+				},
+				{
+					ID:                     "ACME-2021-0001",
+					PackageName:            "com.acme:acme-web",
+					Namespace:              "github:java",
+					VersionConstraint:      "< 2.0.1",
+					CPEs:                   []string{},
+					RelatedVulnerabilities: []v3.VulnerabilityReference{},
+					Fix:                    v3.Fix{Versions: []string{"2.0.1"}, State: v3.FixedState},
+					Advisories:             []v3.Advisory{},
+				},
+				agent,
+			},
+			expectedMetadata: []v3.VulnerabilityMetadata{
+				{
+					ID:           "ACME-2021-0001",
+					Namespace:    "github:java",
+					DataSource:   "https://wiki.acme.com/security/ACME-2021-0001",
+					RecordSource: "private:github:java",
+					Severity:     "High",
+					URLs: []string{
+						"https://wiki.acme.com/security/ACME-2021-0001",
+						"https://jira.acme.com/browse/SEC-12",
+					},
+					Description:  "Remote code execution via crafted messages.",
+					Cvss:         []v3.Cvss{},
+					Published:    timeRef(time.Date(2021, 12, 10, 0, 0, 0, 0, time.UTC)),
+					CWEs:         []string{"CWE-502"},
+					Exploitation: "Exploited in the wild against internet-facing deployments.",
+				},
+				agentMetadata,
+			},
+		},
+		{
+			fixture:                 "test-fixtures/vulnerabilities.json",
+			expectedVulnerabilities: []v3.Vulnerability{agent},
+			expectedMetadata:        []v3.VulnerabilityMetadata{agentMetadata},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fixture, func(t *testing.T) {
+			f, err := os.Open(test.fixture)
+			require.NoError(t, err)
+			defer f.Close()
+
+			vulnerabilities, metadata, err := Transform(f)
+			require.NoError(t, err)
+
+			for _, d := range deep.Equal(test.expectedVulnerabilities, vulnerabilities) {
+				t.Errorf("vulnerability diff: %+v", d)
+			}
+			for _, d := range deep.Equal(test.expectedMetadata, metadata) {
+				t.Errorf("metadata diff: %+v", d)
+			}
+		})
+	}
+}
+
+func TestTransform_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		err      string
+	}{
+		{
+			name:     "missing id",
+			document: "vulnerabilities: [{namespace: github:npm, package: acme}]",
+			err:      "missing id",
+		},
+		{
+			name:     "missing namespace",
+			document: "vulnerabilities: [{id: ACME-1, package: acme}]",
+			err:      "missing namespace",
+		},
+		{
+			name:     "missing package",
+			document: "vulnerabilities: [{id: ACME-1, namespace: github:npm}]",
+			err:      "missing package",
+		},
+		{
+			name:     "unknown field",
+			document: "vulnerabilities: [{id: ACME-1, namespace: github:npm, package: acme, fixed: [1.0.0]}]",
+			err:      "field fixed not found",
+		},
+		{
+			name:     "unsupported severity",
+			document: "vulnerabilities: [{id: ACME-1, namespace: github:npm, package: acme, severity: important}]",
+			err:      "unsupported severity",
+		},
+		{
+			name:     "unsupported version format",
+			document: "vulnerabilities: [{id: ACME-1, namespace: github:npm, package: acme, version-format: nope}]",
+			err:      "unsupported version format",
+		},
+		{
+			name:     "invalid constraint",
+			document: "vulnerabilities: [{id: ACME-1, namespace: debian:10, package: acme, version-format: dpkg, constraint: '<<< 1'}]",
+			err:      "failed to parse constraint",
+		},
+		{
+			name:     "related vulnerability is not a CVE",
+			document: "vulnerabilities: [{id: ACME-1, namespace: github:npm, package: acme, related: [GHSA-1234]}]",
+			err:      "is not a CVE",
+		},
+		{
+			name:     "bad published date",
+			document: "vulnerabilities: [{id: ACME-1, namespace: github:npm, package: acme, published: yesterday}]",
+			err:      "bad published date",
+		},
+		{
+			name: "mismatched severity",
+			document: `vulnerabilities:
+  - {id: ACME-1, namespace: github:npm, package: acme, severity: low}
+  - {id: ACME-1, namespace: github:npm, package: acme-cli, severity: high}`,
+			err: "mismatched severity",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := Transform(strings.NewReader(test.document))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.err)
+		})
+	}
+}
+
+func timeRef(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,9 @@
+package v3
+
+import "time"
+
+// Provider represents an upstream source of vulnerability data within the database (e.g. "nvd" or "alpine").
+type Provider struct {
+	Name  string    // The name of the provider (e.g. "nvd")
+	Built time.Time // When the vulnerability data of the provider was last retrieved
+}
@@ -0,0 +1,17 @@
+package v3
+
+type ProviderStore interface {
+	ProviderStoreReader
+	ProviderStoreWriter
+}
+
+// ProviderStoreReader is implemented by stores that track when the data of each provider was retrieved.
+type ProviderStoreReader interface {
+	// GetProviders retrieves all providers within the store (or nothing when providers are not tracked)
+	GetProviders() ([]Provider, error)
+}
+
+type ProviderStoreWriter interface {
+	// AddProvider inserts (or replaces) one or more providers into the store
+	AddProvider(providers ...Provider) error
+}
@@ -0,0 +1,123 @@
+package reader
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	sdb "github.com/alicebob/sqlittle/db"
+)
+
+var errClosed = errors.New("the DB is closed")
+
+// connections is a pool of connections to the DB, where each read uses a connection of its own, since a connection
+// cannot be read concurrently (a second read lock of a connection fails while the first is held, and the cached
+// header and schema of a connection are not synchronized). Note that the read lock of the DB file is held by the
+// process rather than by a connection, so unlocking any connection would unlock the file for every read in progress:
+// connections are kept locked until there are no reads in progress.
+type connections struct {
+	dbPath string
+	lock   sync.Mutex
+	all    []*sdb.Database
+	idle   []*sdb.Database
+	locked []*sdb.Database
+	reads  int
+	closed bool
+}
+
+func newConnections(dbPath string) (*connections, error) {
+	c := &connections{
+		dbPath: dbPath,
+	}
+
+	// the first connection is opened upfront, so that a DB that cannot be read is rejected right away
+	db, err := c.open()
+	if err != nil {
+		return nil, err
+	}
+	c.idle = append(c.idle, db)
+	return c, nil
+}
+
+func (c *connections) open() (*sdb.Database, error) {
+	db, err := Open(&config{
+		dbPath:    c.dbPath,
+		overwrite: false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.all = append(c.all, db)
+	return db, nil
+}
+
+// get returns a read locked connection that is not used by any other read, which is returned to the pool with put.
+func (c *connections) get() (*sdb.Database, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.closed {
+		return nil, errClosed
+	}
+
+	var db *sdb.Database
+	if n := len(c.idle); n > 0 {
+		db, c.idle = c.idle[n-1], c.idle[:n-1]
+	} else {
+		var err error
+		if db, err = c.open(); err != nil {
+			return nil, err
+		}
+	}
+
+	if !c.isLocked(db) {
+		if err := db.RLock(); err != nil {
+			c.idle = append(c.idle, db)
+			return nil, fmt.Errorf("unable to lock: %w", err)
+		}
+		c.locked = append(c.locked, db)
+	}
+	c.reads++
+	return db, nil
+}
+
+// put returns a connection to the pool, where every connection is unlocked once there are no reads in progress.
+func (c *connections) put(db *sdb.Database) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.idle = append(c.idle, db)
+	c.reads--
+	if c.reads > 0 {
+		return
+	}
+	for _, l := range c.locked {
+		_ = l.RUnlock()
+	}
+	c.locked = nil
+}
+
+func (c *connections) isLocked(db *sdb.Database) bool {
+	for _, l := range c.locked {
+		if l == db {
+			return true
+		}
+	}
+	return false
+}
+
+// close closes every connection, where reads that are in progress must be done.
+func (c *connections) close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.closed = true
+	var err error
+	for _, db := range c.all {
+		if closeErr := db.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	c.all, c.idle, c.locked = nil, nil, nil
+	return err
+}
@@ -3,7 +3,7 @@ package reader
 import (
 	"os"
 
-	"github.com/alicebob/sqlittle"
+	sdb "github.com/alicebob/sqlittle/db"
 )
 
 // Options defines the information needed to connect and create a sqlite3 database
@@ -13,13 +13,13 @@ type config struct {
 }
 
 // Open a new connection to the sqlite3 database file
-func Open(cfg *config) (*sqlittle.DB, error) {
+func Open(cfg *config) (*sdb.Database, error) {
 	if cfg.overwrite {
 		// the file may or may not exist, so we ignore the error explicitly
 		_ = os.Remove(cfg.dbPath)
 	}
 
-	db, err := sqlittle.Open(cfg.dbPath)
+	db, err := sdb.OpenFile(cfg.dbPath)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,307 @@
+package reader
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alicebob/sqlittle"
+	sdb "github.com/alicebob/sqlittle/db"
+	"github.com/alicebob/sqlittle/sql"
+)
+
+// indexKey is the value of each (leading) column of an index or primary key to look up.
+type indexKey []interface{}
+
+// column describes where the value of a selected column is found within a record.
+type column struct {
+	position int
+	rowid    bool
+	def      interface{}
+}
+
+// read reads from a connection of its own (see connections), which holds the (file) read lock of the DB while reading,
+// so that the DB is not changed by another process meanwhile.
+func (b *Reader) read(fn func(db *sdb.Database) error) error {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	db, err := b.connections.get()
+	if err != nil {
+		return err
+	}
+	defer b.connections.put(db)
+	return fn(db)
+}
+
+// hasTable indicates if the DB has the given table.
+func (b *Reader) hasTable(name string) (bool, error) {
+	var found bool
+	err := b.read(func(db *sdb.Database) error {
+		tables, err := db.Tables()
+		if err != nil {
+			return err
+		}
+		for _, table := range tables {
+			if strings.EqualFold(table, name) {
+				found = true
+				break
+			}
+		}
+		return nil
+	})
+	return found, err
+}
+
+// selectAll reads the given columns of every row of a table.
+func (b *Reader) selectAll(table string, cb func(sqlittle.Row), columns ...string) error {
+	return b.read(func(db *sdb.Database) error {
+		schema, err := db.Schema(table)
+		if err != nil {
+			return err
+		}
+		cols, err := resolveColumns(schema, columns)
+		if err != nil {
+			return err
+		}
+
+		if schema.WithoutRowid {
+			t, err := db.NonRowidTable(schema.Table)
+			if err != nil {
+				return err
+			}
+			return t.Scan(func(r sdb.Record) bool {
+				cb(toRow(0, cols, r))
+				return false
+			})
+		}
+
+		t, err := db.Table(schema.Table)
+		if err != nil {
+			return err
+		}
+		return t.Scan(func(rowid int64, r sdb.Record) bool {
+			cb(toRow(rowid, cols, r))
+			return false
+		})
+	})
+}
+
+// selectRowid reads the given columns of the row with the given rowid, which is nil when there is no such row.
+func (b *Reader) selectRowid(table string, rowid int64, columns ...string) (sqlittle.Row, error) {
+	var row sqlittle.Row
+	err := b.read(func(db *sdb.Database) error {
+		schema, err := db.Schema(table)
+		if err != nil {
+			return err
+		}
+		if schema.WithoutRowid {
+			return fmt.Errorf("table %q has no rowid", table)
+		}
+		cols, err := resolveColumns(schema, columns)
+		if err != nil {
+			return err
+		}
+
+		t, err := db.Table(schema.Table)
+		if err != nil {
+			return err
+		}
+		r, err := t.Rowid(rowid)
+		if err != nil || r == nil {
+			return err
+		}
+		row = toRow(rowid, cols, r)
+		return nil
+	})
+	return row, err
+}
+
+// selectEq reads the given columns of the rows that match any of the given keys (like an "IN (...)" condition) with
+// the named index of a table, or with the primary key when no index is named. Every key is looked up while holding a
+// single read lock, and the callback is given the position of the key that a row matches.
+// nolint:funlen
+func (b *Reader) selectEq(table, index string, keys []indexKey, cb func(int, sqlittle.Row), columns ...string) error {
+	return b.read(func(db *sdb.Database) error {
+		schema, err := db.Schema(table)
+		if err != nil {
+			return err
+		}
+		cols, err := resolveColumns(schema, columns)
+		if err != nil {
+			return err
+		}
+
+		if schema.WithoutRowid {
+			if index != "" {
+				return fmt.Errorf("indexed lookups of table %q without rowid are not supported", table)
+			}
+			t, err := db.NonRowidTable(schema.Table)
+			if err != nil {
+				return err
+			}
+			for i, k := range keys {
+				dbKey, err := asKey(k, schema.PK)
+				if err != nil {
+					return err
+				}
+				err = t.ScanEq(dbKey, func(r sdb.Record) bool {
+					cb(i, toRow(0, cols, r))
+					return false
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		t, err := db.Table(schema.Table)
+		if err != nil {
+			return err
+		}
+
+		if index == "" && schema.RowidPK {
+			// the primary key is the rowid itself
+			for i, k := range keys {
+				if len(k) != 1 {
+					return fmt.Errorf("invalid key: %+v", k)
+				}
+				rowid, ok := k[0].(int64)
+				if !ok {
+					return fmt.Errorf("invalid key: %+v", k)
+				}
+				r, err := t.Rowid(rowid)
+				if err != nil {
+					return err
+				}
+				if r != nil {
+					cb(i, toRow(rowid, cols, r))
+				}
+			}
+			return nil
+		}
+
+		if index == "" {
+			index = schema.PrimaryKey
+		}
+		indexSchema := schema.NamedIndex(index)
+		if indexSchema == nil {
+			return fmt.Errorf("no such index: %q", index)
+		}
+		ind, err := db.Index(indexSchema.Index)
+		if err != nil {
+			return err
+		}
+
+		for i, k := range keys {
+			dbKey, err := asKey(k, indexSchema.Columns)
+			if err != nil {
+				return err
+			}
+			var rowErr error
+			err = ind.ScanEq(dbKey, func(r sdb.Record) bool {
+				rowid, _, err := sdb.ChompRowid(r)
+				if err != nil {
+					rowErr = fmt.Errorf("unable to scan over index: %w", err)
+					return true
+				}
+				record, err := t.Rowid(rowid)
+				if err != nil || record == nil {
+					rowErr = fmt.Errorf("unable to read row (rowid=%d): %w", rowid, err)
+					return true
+				}
+				cb(i, toRow(rowid, cols, record))
+				return false
+			})
+			if err != nil {
+				return err
+			}
+			if rowErr != nil {
+				return rowErr
+			}
+		}
+		return nil
+	})
+}
+
+// resolveColumns returns where the given columns are found within the records of a table.
+func resolveColumns(schema *sdb.Schema, columns []string) ([]column, error) {
+	var stored []int
+	if schema.WithoutRowid {
+		stored = storedPositions(schema)
+	}
+
+	cols := make([]column, 0, len(columns))
+	for _, name := range columns {
+		n := schema.Column(name)
+		if n < 0 {
+			return nil, fmt.Errorf("no such column: %q", name)
+		}
+		c := column{position: n, rowid: schema.Columns[n].Rowid, def: schema.Columns[n].Default}
+		if schema.WithoutRowid {
+			c.position = stored[n]
+		}
+		cols = append(cols, c)
+	}
+	return cols, nil
+}
+
+// storedPositions returns the position of each column of a table without rowid within its records, which start with
+// the primary key columns.
+func storedPositions(schema *sdb.Schema) []int {
+	var order []int
+	seen := make(map[int]bool)
+	for _, c := range schema.PK {
+		n := schema.Column(c.Column)
+		order = append(order, n)
+		seen[n] = true
+	}
+	for n := range schema.Columns {
+		if !seen[n] {
+			order = append(order, n)
+		}
+	}
+
+	positions := make([]int, len(schema.Columns))
+	for position, n := range order {
+		positions[n] = position
+	}
+	return positions
+}
+
+// toRow returns the values of the given columns of a record, where records written before a column was added are
+// shorter (in which case the default of the column is used).
+func toRow(rowid int64, cols []column, record sdb.Record) sqlittle.Row {
+	row := make(sqlittle.Row, len(cols))
+	for i, c := range cols {
+		switch {
+		case c.rowid:
+			row[i] = rowid
+		case c.position < len(record):
+			row[i] = record[c.position]
+		default:
+			row[i] = c.def
+		}
+	}
+	return row
+}
+
+// asKey returns the key to look up with the given (leading) columns of an index or primary key.
+func asKey(k indexKey, columns []sdb.IndexColumn) (sdb.Key, error) {
+	if len(k) > len(columns) {
+		return nil, fmt.Errorf("too many columns in key: %+v", k)
+	}
+	dbKey := make(sdb.Key, len(k))
+	for i, v := range k {
+		collate := strings.ToLower(columns[i].Collate)
+		if _, ok := sdb.CollateFuncs[collate]; collate != "" && !ok {
+			return nil, fmt.Errorf("unknown collate function: %q", collate)
+		}
+		dbKey[i] = sdb.KeyCol{
+			V:       v,
+			Collate: collate,
+			Desc:    columns[i].SortOrder == sql.Desc,
+		}
+	}
+	return dbKey, nil
+}
@@ -2,34 +2,64 @@ package reader
 
 import (
 	"fmt"
+	"sort"
+	"sync"
 
 	v3 "github.com/anchore/grype/grype/db/v3"
 
 	"github.com/alicebob/sqlittle"
 	"github.com/anchore/grype/grype/db/v3/model"
+	"github.com/anchore/grype/internal"
 )
 
 // Reader holds an instance of the database connection.
 type Reader struct {
-	db *sqlittle.DB
+	// note: reads are done concurrently, where the lock is only held exclusively to close the DB
+	lock sync.RWMutex
+	// note: the lower level API of the DB is used (see query.go), which allows for reading many records with one lock
+	connections *connections
+	// note: DBs built before RPM module streams, architectures and platforms were tracked do not have all vulnerability
+	// columns
+	optionalColumnsOnce sync.Once
+	optionalColumns     []string
+	// note: DBs built before publication dates, CWEs and exploitation notes were tracked do not have all vulnerability
+	// metadata columns
+	optionalMetadataColumnsOnce sync.Once
+	optionalMetadataColumns     []string
+	// note: DBs built before vulnerabilities were indexed by package do not have a package index
+	packageIndexOnce sync.Once
+	packageIndexed   bool
+	packageIndexErr  error
 }
 
+// optionalVulnerabilityColumns are the vulnerability columns that were added without a schema version bump.
+var optionalVulnerabilityColumns = []string{"rpm_modularity", "arches", "platform_cpes"}
+
+// optionalVulnerabilityMetadataColumns are the vulnerability metadata columns that were added without a schema version
+// bump.
+var optionalVulnerabilityMetadataColumns = []string{"published", "modified", "cwes", "exploitation"}
+
 // CleanupFn is a callback for closing a DB connection.
 type CleanupFn func() error
 
 // New creates a new instance of the store.
 func New(dbFilePath string) (*Reader, CleanupFn, error) {
-	d, err := Open(&config{
-		dbPath:    dbFilePath,
-		overwrite: false,
-	})
+	c, err := newConnections(dbFilePath)
 	if err != nil {
 		return nil, nil, fmt.Errorf("unable to create a new connection to sqlite3 db: %s", err)
 	}
 
-	return &Reader{
-		db: d,
-	}, d.Close, nil
+	r := &Reader{
+		connections: c,
+	}
+	return r, r.close, nil
+}
+
+func (b *Reader) close() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.connections.close()
 }
 
 // GetID fetches the metadata about the databases schema version and build time.
@@ -37,7 +67,7 @@ func (b *Reader) GetID() (*v3.ID, error) {
 	var scanErr error
 	total := 0
 	var m model.IDModel
-	err := b.db.Select(model.IDTableName, func(row sqlittle.Row) {
+	err := b.selectAll(model.IDTableName, func(row sqlittle.Row) {
 		total++
 
 		if scanErr = row.Scan(&m.BuildTimestamp, &m.SchemaVersion); scanErr != nil {
@@ -72,16 +102,94 @@ func (b *Reader) GetVulnerability(namespace, name string) ([]v3.Vulnerability, e
 	var scanErr error
 	var vulnerabilityModels []model.VulnerabilityModel
 
-	err := b.db.IndexedSelectEq(model.VulnerabilityTableName, model.GetVulnerabilityIndexName, sqlittle.Key{name, namespace}, func(row sqlittle.Row) {
-		var m model.VulnerabilityModel
+	columns := b.vulnerabilityColumns()
+	err := b.selectEq(model.VulnerabilityTableName, model.GetVulnerabilityIndexName, []indexKey{{name, namespace}}, func(_ int, row sqlittle.Row) {
+		m, err := scanVulnerability(row, columns)
+		if err != nil {
+			scanErr = err
+			return
+		}
+		vulnerabilityModels = append(vulnerabilityModels, m)
+	}, columns...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query: %w", err)
+	}
+	if scanErr != nil {
+		return nil, scanErr
+	}
 
-		if err := row.Scan(&m.Namespace, &m.PackageName, &m.ID, &m.VersionConstraint, &m.VersionFormat, &m.CPEs, &m.RelatedVulnerabilities, &m.FixedInVersions, &m.FixState, &m.Advisories); err != nil {
-			scanErr = fmt.Errorf("unable to scan over row: %w", err)
+	return inflateVulnerabilities(vulnerabilityModels)
+}
+
+// GetVulnerabilitiesByName retrieves the vulnerabilities of several packages within a namespace (keyed by package
+// name) with a single query, which spares the locking and schema lookup of a query for each package.
+func (b *Reader) GetVulnerabilitiesByName(namespace string, names []string) (map[string][]v3.Vulnerability, error) {
+	names = internal.NewStringSetFromSlice(names).ToSlice()
+	keys := make([]indexKey, len(names))
+	for i, name := range names {
+		keys[i] = indexKey{name, namespace}
+	}
+
+	var scanErr error
+	vulnerabilityModels := make([][]model.VulnerabilityModel, len(names))
+
+	columns := b.vulnerabilityColumns()
+	err := b.selectEq(model.VulnerabilityTableName, model.GetVulnerabilityIndexName, keys, func(i int, row sqlittle.Row) {
+		m, err := scanVulnerability(row, columns)
+		if err != nil {
+			scanErr = err
 			return
 		}
+		vulnerabilityModels[i] = append(vulnerabilityModels[i], m)
+	}, columns...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to query: %w", err)
+	}
+	if scanErr != nil {
+		return nil, scanErr
+	}
 
-		vulnerabilityModels = append(vulnerabilityModels, m)
-	}, "namespace", "package_name", "id", "version_constraint", "version_format", "cpes", "related_vulnerabilities", "fixed_in_versions", "fix_state", "advisories")
+	result := make(map[string][]v3.Vulnerability, len(names))
+	for i, name := range names {
+		vulnerabilities, err := inflateVulnerabilities(vulnerabilityModels[i])
+		if err != nil {
+			return nil, err
+		}
+		result[name] = vulnerabilities
+	}
+	return result, nil
+}
+
+// HasPackageIndex indicates if the vulnerabilities within the DB are indexed by package (determined once).
+func (b *Reader) HasPackageIndex() (bool, error) {
+	b.packageIndexOnce.Do(func() {
+		indexed, err := b.hasTable(model.PackageIndexTableName)
+		if err != nil {
+			b.packageIndexErr = fmt.Errorf("unable to read schema: %w", err)
+			return
+		}
+		b.packageIndexed = indexed
+	})
+	return b.packageIndexed, b.packageIndexErr
+}
+
+// GetVulnerabilityByPackageKey retrieves the vulnerabilities within the given namespaces of the package with the given
+// key (see v3.PackageKey), where only the vulnerabilities within the given namespaces are read.
+func (b *Reader) GetVulnerabilityByPackageKey(key string, namespaces []string) ([]v3.Vulnerability, error) {
+	var scanErr error
+	var pks []int64
+
+	namespaceSet := internal.NewStringSetFromSlice(namespaces)
+	err := b.selectEq(model.PackageIndexTableName, "", []indexKey{{key}}, func(_ int, row sqlittle.Row) {
+		var m model.PackageIndexModel
+		if err := row.Scan(&m.Namespace, &m.VulnerabilityPK); err != nil {
+			scanErr = fmt.Errorf("unable to scan over row: %w", err)
+			return
+		}
+		if namespaceSet.Contains(m.Namespace) {
+			pks = append(pks, m.VulnerabilityPK)
+		}
+	}, "namespace", "vulnerability_pk")
 	if err != nil {
 		return nil, fmt.Errorf("unable to query: %w", err)
 	}
@@ -89,6 +197,55 @@ func (b *Reader) GetVulnerability(namespace, name string) ([]v3.Vulnerability, e
 		return nil, scanErr
 	}
 
+	columns := b.vulnerabilityColumns()
+	vulnerabilityModels := make([]model.VulnerabilityModel, 0, len(pks))
+	for _, pk := range pks {
+		row, err := b.selectRowid(model.VulnerabilityTableName, pk, columns...)
+		if err != nil {
+			return nil, fmt.Errorf("unable to query: %w", err)
+		}
+		if row == nil {
+			return nil, fmt.Errorf("package index is inconsistent: vulnerability pk=%d not found", pk)
+		}
+		m, err := scanVulnerability(row, columns)
+		if err != nil {
+			return nil, err
+		}
+		vulnerabilityModels = append(vulnerabilityModels, m)
+	}
+
+	return inflateVulnerabilities(vulnerabilityModels)
+}
+
+// vulnerabilityColumns returns the vulnerability columns to read, including the optional columns that the vulnerability
+// table has.
+func (b *Reader) vulnerabilityColumns() []string {
+	columns := []string{"namespace", "package_name", "id", "version_constraint", "version_format", "cpes", "related_vulnerabilities", "fixed_in_versions", "fix_state", "advisories"}
+	return append(columns, b.optionalVulnerabilityColumns()...)
+}
+
+func scanVulnerability(row sqlittle.Row, columns []string) (model.VulnerabilityModel, error) {
+	var m model.VulnerabilityModel
+
+	fields := []interface{}{&m.Namespace, &m.PackageName, &m.ID, &m.VersionConstraint, &m.VersionFormat, &m.CPEs, &m.RelatedVulnerabilities, &m.FixedInVersions, &m.FixState, &m.Advisories}
+	for _, column := range columns[len(fields):] {
+		switch column {
+		case "rpm_modularity":
+			fields = append(fields, &m.RpmModularity)
+		case "arches":
+			fields = append(fields, &m.Arches)
+		case "platform_cpes":
+			fields = append(fields, &m.PlatformCPEs)
+		}
+	}
+
+	if err := row.Scan(fields...); err != nil {
+		return model.VulnerabilityModel{}, fmt.Errorf("unable to scan over row: %w", err)
+	}
+	return m, nil
+}
+
+func inflateVulnerabilities(vulnerabilityModels []model.VulnerabilityModel) ([]v3.Vulnerability, error) {
 	vulnerabilities := make([]v3.Vulnerability, 0, len(vulnerabilityModels))
 
 	for _, m := range vulnerabilityModels {
@@ -102,20 +259,97 @@ func (b *Reader) GetVulnerability(namespace, name string) ([]v3.Vulnerability, e
 	return vulnerabilities, nil
 }
 
+// optionalVulnerabilityColumns returns the optional columns that the vulnerability table has (determined once).
+func (b *Reader) optionalVulnerabilityColumns() []string {
+	b.optionalColumnsOnce.Do(func() {
+		for _, column := range optionalVulnerabilityColumns {
+			// columns are validated before any row is read, so selecting a row that does not exist is enough
+			if _, err := b.selectRowid(model.VulnerabilityTableName, 0, column); err == nil {
+				b.optionalColumns = append(b.optionalColumns, column)
+			}
+		}
+	})
+	return b.optionalColumns
+}
+
+// optionalVulnerabilityMetadataColumns returns the optional columns that the vulnerability metadata table has
+// (determined once).
+func (b *Reader) optionalVulnerabilityMetadataColumns() []string {
+	b.optionalMetadataColumnsOnce.Do(func() {
+		for _, column := range optionalVulnerabilityMetadataColumns {
+			// columns are validated before any row is read, so selecting a row that does not exist is enough
+			if _, err := b.selectRowid(model.VulnerabilityMetadataTableName, 0, column); err == nil {
+				b.optionalMetadataColumns = append(b.optionalMetadataColumns, column)
+			}
+		}
+	})
+	return b.optionalMetadataColumns
+}
+
+// GetVulnerabilityNamespaces retrieves all namespaces that have at least one vulnerability.
+func (b *Reader) GetVulnerabilityNamespaces() ([]string, error) {
+	var scanErr error
+	namespaces := internal.NewStringSet()
+
+	tracked, err := b.hasTable(model.VulnerabilityNamespaceTableName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read schema: %w", err)
+	}
+
+	table := model.VulnerabilityNamespaceTableName
+	if !tracked {
+		// DBs built before vulnerabilities were indexed by package have no namespace table, in which case every
+		// vulnerability record is read (so callers should not do this more than once)
+		table = model.VulnerabilityTableName
+	}
+
+	err = b.selectAll(table, func(row sqlittle.Row) {
+		var namespace string
+		if err := row.Scan(&namespace); err != nil {
+			scanErr = fmt.Errorf("unable to scan over row: %w", err)
+			return
+		}
+		namespaces.Add(namespace)
+	}, "namespace")
+	if err != nil {
+		return nil, fmt.Errorf("unable to query: %w", err)
+	}
+	if scanErr != nil {
+		return nil, scanErr
+	}
+
+	return namespaces.ToSlice(), nil
+}
+
 // GetVulnerabilityMetadata retrieves metadata for the given vulnerability ID relative to a specific record source.
 func (b *Reader) GetVulnerabilityMetadata(id, namespace string) (*v3.VulnerabilityMetadata, error) {
 	total := 0
 	var m model.VulnerabilityMetadataModel
 	var scanErr error
 
-	err := b.db.PKSelect(model.VulnerabilityMetadataTableName, sqlittle.Key{id, namespace}, func(row sqlittle.Row) {
+	columns := append([]string{"id", "namespace", "data_source", "record_source", "severity", "urls", "description", "cvss"}, b.optionalVulnerabilityMetadataColumns()...)
+	err := b.selectEq(model.VulnerabilityMetadataTableName, "", []indexKey{{id, namespace}}, func(_ int, row sqlittle.Row) {
 		total++
 
-		if err := row.Scan(&m.ID, &m.Namespace, &m.DataSource, &m.RecordSource, &m.Severity, &m.URLs, &m.Description, &m.Cvss); err != nil {
+		fields := []interface{}{&m.ID, &m.Namespace, &m.DataSource, &m.RecordSource, &m.Severity, &m.URLs, &m.Description, &m.Cvss}
+		for _, column := range columns[len(fields):] {
+			switch column {
+			case "published":
+				fields = append(fields, &m.Published)
+			case "modified":
+				fields = append(fields, &m.Modified)
+			case "cwes":
+				fields = append(fields, &m.CWEs)
+			case "exploitation":
+				fields = append(fields, &m.Exploitation)
+			}
+		}
+
+		if err := row.Scan(fields...); err != nil {
 			scanErr = fmt.Errorf("unable to scan over row: %w", err)
 			return
 		}
-	}, "id", "namespace", "data_source", "record_source", "severity", "urls", "description", "cvss")
+	}, columns...)
 	if err != nil {
 		return nil, fmt.Errorf("unable to query: %w", err)
 	}
@@ -137,3 +371,89 @@ func (b *Reader) GetVulnerabilityMetadata(id, namespace string) (*v3.Vulnerabili
 
 	return &metadata, nil
 }
+
+// GetDistroEOL retrieves the end of life of the distro release with the given namespace (or nil when unknown).
+func (b *Reader) GetDistroEOL(namespace string) (*v3.DistroEOL, error) {
+	// DBs built before end of life dates were tracked have no such table, in which case nothing is known
+	tracked, err := b.hasTable(model.DistroEOLTableName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read schema: %w", err)
+	}
+	if !tracked {
+		return nil, nil
+	}
+
+	total := 0
+	var m model.DistroEOLModel
+	var scanErr error
+
+	err = b.selectEq(model.DistroEOLTableName, "", []indexKey{{namespace}}, func(_ int, row sqlittle.Row) {
+		total++
+
+		if err := row.Scan(&m.Namespace, &m.Date); err != nil {
+			scanErr = fmt.Errorf("unable to scan over row: %w", err)
+			return
+		}
+	}, "namespace", "date")
+	if err != nil {
+		return nil, fmt.Errorf("unable to query: %w", err)
+	}
+	if scanErr != nil {
+		return nil, scanErr
+	}
+
+	if total == 0 {
+		return nil, nil
+	}
+
+	eol, err := m.Inflate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &eol, nil
+}
+
+// GetProviders retrieves all providers within the database, ordered by name (or nothing when providers are not
+// tracked).
+func (b *Reader) GetProviders() ([]v3.Provider, error) {
+	// DBs built before providers were tracked have no such table, in which case nothing is known
+	tracked, err := b.hasTable(model.ProviderTableName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read schema: %w", err)
+	}
+	if !tracked {
+		return nil, nil
+	}
+
+	var models []model.ProviderModel
+	var scanErr error
+
+	err = b.selectAll(model.ProviderTableName, func(row sqlittle.Row) {
+		var m model.ProviderModel
+		if err := row.Scan(&m.Name, &m.BuildTimestamp); err != nil {
+			scanErr = fmt.Errorf("unable to scan over row: %w", err)
+			return
+		}
+		models = append(models, m)
+	}, "name", "build_timestamp")
+	if err != nil {
+		return nil, fmt.Errorf("unable to query: %w", err)
+	}
+	if scanErr != nil {
+		return nil, scanErr
+	}
+
+	providers := make([]v3.Provider, 0, len(models))
+	for _, m := range models {
+		provider, err := m.Inflate()
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].Name < providers[j].Name
+	})
+	return providers, nil
+}
@@ -11,6 +11,9 @@ type Vulnerability struct {
 	RelatedVulnerabilities []VulnerabilityReference // Other Vulnerabilities that are related to this one (e.g. GHSA relate to CVEs, or how distro CVE relates to NVD record)
 	Fix                    Fix                      // All information about fixed versions
 	Advisories             []Advisory               // Any vendor advisories about fixes or other notifications about this vulnerability
+	RpmModularity          string                   // The RPM module stream (e.g. "nodejs:12") of the vulnerable package, for packages that are only vulnerable within a module stream
+	Arches                 []string                 // The architectures (e.g. x86_64, aarch64) the vulnerable package was built for, for advisories that are scoped to specific architectures
+	PlatformCPEs           []string                 // The CPEs of the platforms (one of which must be present) for the vulnerability to apply (e.g. an application CVE that only applies when running on windows), empty when the vulnerability applies on any platform
 }
 
 type VulnerabilityReference struct {
@@ -1,15 +1,21 @@
 package v3
 
+import "time"
+
 // VulnerabilityMetadata represents all vulnerability data that is not necessary to perform package-to-vulnerability matching.
 type VulnerabilityMetadata struct {
-	ID           string   // The identifier of the vulnerability or advisory
-	Namespace    string   // Where this entry is valid within
-	DataSource   string   // A URL where the data was sourced from
-	RecordSource string   // The source of the vulnerability information (relative to the immediate upstream in the enterprise feedgroup)
-	Severity     string   // How severe the vulnerability is (valid values are defined by upstream sources currently)
-	URLs         []string // URLs to get more information about the vulnerability or advisory
-	Description  string   // Description of the vulnerability
-	Cvss         []Cvss   // Common Vulnerability Scoring System values
+	ID           string     // The identifier of the vulnerability or advisory
+	Namespace    string     // Where this entry is valid within
+	DataSource   string     // A URL where the data was sourced from
+	RecordSource string     // The source of the vulnerability information (relative to the immediate upstream in the enterprise feedgroup)
+	Severity     string     // How severe the vulnerability is (valid values are defined by upstream sources currently)
+	URLs         []string   // URLs to get more information about the vulnerability or advisory
+	Description  string     // Description of the vulnerability
+	Cvss         []Cvss     // Common Vulnerability Scoring System values
+	Published    *time.Time // When the vulnerability or advisory was first published (nil when unknown)
+	Modified     *time.Time // When the vulnerability or advisory was last modified (nil when unknown)
+	CWEs         []string   // The weaknesses that the vulnerability is an instance of (e.g. "CWE-79")
+	Exploitation string     // Notes on the exploitation of the vulnerability (e.g. that it is exploited in the wild)
 }
 
 // Cvss contains select Common Vulnerability Scoring System fields for a vulnerability.
@@ -12,6 +12,30 @@ type VulnerabilityStoreReader interface {
 	GetVulnerability(namespace, name string) ([]Vulnerability, error)
 }
 
+// VulnerabilityBatchReader is implemented by stores that are able to retrieve the vulnerabilities of many packages at
+// once, which is much faster than retrieving the vulnerabilities of each package on its own.
+type VulnerabilityBatchReader interface {
+	// GetVulnerabilitiesByName retrieves the vulnerabilities associated with a namespace for each of the given package
+	// names (keyed by package name)
+	GetVulnerabilitiesByName(namespace string, names []string) (map[string][]Vulnerability, error)
+}
+
+// VulnerabilityNamespaceReader is implemented by stores that are able to describe which namespaces they contain.
+type VulnerabilityNamespaceReader interface {
+	// GetVulnerabilityNamespaces retrieves all namespaces that have at least one vulnerability
+	GetVulnerabilityNamespaces() ([]string, error)
+}
+
+// VulnerabilityPackageIndexReader is implemented by stores that index vulnerabilities by package (see PackageKey), so
+// that the vulnerabilities of a package within all namespaces of an ecosystem are found with a single lookup.
+type VulnerabilityPackageIndexReader interface {
+	// HasPackageIndex indicates if the vulnerabilities within the store are indexed by package
+	HasPackageIndex() (bool, error)
+	// GetVulnerabilityByPackageKey retrieves the vulnerabilities within the given namespaces of the package with the
+	// given key
+	GetVulnerabilityByPackageKey(key string, namespaces []string) ([]Vulnerability, error)
+}
+
 type VulnerabilityStoreWriter interface {
 	// AddVulnerability inserts a new record of a vulnerability into the store
 	AddVulnerability(vulnerabilities ...Vulnerability) error
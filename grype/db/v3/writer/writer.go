@@ -3,6 +3,7 @@ package writer
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	v3 "github.com/anchore/grype/grype/db/v3"
 
@@ -18,6 +19,9 @@ import (
 // Writer holds an instance of the database connection
 type Writer struct {
 	db *gorm.DB
+	// packagesIndexed indicates if the vulnerabilities are indexed by package (see IndexPackages), in which case the
+	// index is kept up to date by every change to the vulnerabilities
+	packagesIndexed bool
 }
 
 // CleanupFn is a callback for closing a DB connection.
@@ -40,9 +44,22 @@ func New(dbFilePath string, overwrite bool) (*Writer, CleanupFn, error) {
 	db.AutoMigrate(&model.VulnerabilityModel{})
 	db.AutoMigrate(&model.VulnerabilityMetadataModel{})
 
-	return &Writer{
-		db: db,
-	}, db.Close, nil
+	w := &Writer{
+		db:              db,
+		packagesIndexed: db.HasTable(model.PackageIndexTableName),
+	}
+
+	// the tables that are not part of every v3 DB are only created within new DBs (an existing DB, e.g. a published DB
+	// that is updated, keeps the tables it was built with), where the package index is kept up to date as it is written
+	if overwrite {
+		db.AutoMigrate(&model.DistroEOLModel{})
+		db.AutoMigrate(&model.ProviderModel{})
+		if err := w.IndexPackages(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return w, db.Close, nil
 }
 
 // GetID fetches the metadata about the databases schema version and build time.
@@ -115,6 +132,214 @@ func (s *Writer) AddVulnerability(vulnerabilities ...v3.Vulnerability) error {
 		if result.RowsAffected != 1 {
 			return fmt.Errorf("unable to add vulnerability (%d rows affected)", result.RowsAffected)
 		}
+
+		if s.packagesIndexed {
+			if err := indexNamespace(s.db, m.Namespace); err != nil {
+				return err
+			}
+			if err := indexVulnerability(s.db, m.PK, m.Namespace, m.PackageName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// DeleteVulnerability removes all vulnerabilities with the given ID for a package within a namespace, returning the
+// number of vulnerabilities that were removed.
+func (s *Writer) DeleteVulnerability(namespace, packageName, id string) (int64, error) {
+	if key := v3.PackageKey(namespace, packageName); s.packagesIndexed && key != "" {
+		statement := fmt.Sprintf("DELETE FROM %s WHERE package_key = ? AND namespace = ? AND vulnerability_pk IN (SELECT pk FROM %s WHERE namespace = ? AND package_name = ? AND id = ?)", model.PackageIndexTableName, model.VulnerabilityTableName)
+		if err := s.db.Exec(statement, key, namespace, namespace, packageName, id).Error; err != nil {
+			return 0, fmt.Errorf("unable to remove vulnerability from package index: %w", err)
+		}
+	}
+	result := s.db.Where("namespace = ? AND package_name = ? AND id = ?", namespace, packageName, id).Delete(&model.VulnerabilityModel{})
+	return result.RowsAffected, result.Error
+}
+
+// ReplaceNamespaces replaces all records (vulnerabilities, vulnerability metadata and distro end of life) within the
+// given namespaces with the records of the same namespaces from another database file (e.g. a delta update that
+// contains the complete records of the namespaces that changed since this database was built). Namespaces without
+// records in the other database are removed.
+func (s *Writer) ReplaceNamespaces(fromDBPath string, namespaces []string) error {
+	if len(namespaces) == 0 {
+		return nil
+	}
+
+	// attached databases are only visible to the connection that attached them
+	s.db.DB().SetMaxOpenConns(1)
+
+	if err := s.db.Exec("ATTACH DATABASE ? AS source", fromDBPath).Error; err != nil {
+		return fmt.Errorf("unable to attach database (%s): %w", fromDBPath, err)
+	}
+	defer s.db.Exec("DETACH DATABASE source")
+
+	tx := s.db.Begin()
+	for _, table := range []string{model.VulnerabilityTableName, model.VulnerabilityMetadataTableName, model.DistroEOLTableName} {
+		if !tx.HasTable(table) {
+			// this database was built without the table
+			continue
+		}
+		if err := replaceNamespaces(tx, table, namespaces); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if s.packagesIndexed {
+		for _, table := range []string{model.PackageIndexTableName, model.VulnerabilityNamespaceTableName} {
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE namespace IN (?)", table), namespaces).Error; err != nil {
+				tx.Rollback()
+				return fmt.Errorf("unable to remove %s records: %w", table, err)
+			}
+		}
+		if err := indexVulnerabilities(tx, "namespace IN (?)", namespaces); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit().Error
+}
+
+func replaceNamespaces(tx *gorm.DB, table string, namespaces []string) error {
+	if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE namespace IN (?)", table), namespaces).Error; err != nil {
+		return fmt.Errorf("unable to remove %s records: %w", table, err)
+	}
+
+	columns, err := sourceColumns(tx, table)
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		// the source database does not have this table
+		return nil
+	}
+
+	list := strings.Join(columns, ", ")
+	statement := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM source.%s WHERE namespace IN (?)", table, list, list, table)
+	if err := tx.Exec(statement, namespaces).Error; err != nil {
+		return fmt.Errorf("unable to copy %s records: %w", table, err)
+	}
+	return nil
+}
+
+// sourceColumns returns the columns of the given table within the attached source database that can be copied, where
+// generated primary keys are excluded (e.g. the vulnerability table "pk" column).
+func sourceColumns(tx *gorm.DB, table string) ([]string, error) {
+	rows, err := tx.Raw(fmt.Sprintf("PRAGMA source.table_info(%s)", table)).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("unable to describe %s table: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, columnType string
+		var defaultValue interface{}
+		if err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &pk); err != nil {
+			return nil, fmt.Errorf("unable to describe %s table: %w", table, err)
+		}
+		if name == "pk" {
+			continue
+		}
+		columns = append(columns, name)
+	}
+	return columns, rows.Err()
+}
+
+// IndexPackages (re)builds the package index (see v3.PackageKey) and the namespaces of the vulnerabilities within the
+// sqlite DB, after which both are kept up to date by every change to the vulnerabilities.
+func (s *Writer) IndexPackages() error {
+	tx := s.db.Begin()
+	for _, statement := range []string{
+		fmt.Sprintf("DROP TABLE IF EXISTS %s", model.PackageIndexTableName),
+		fmt.Sprintf("DROP TABLE IF EXISTS %s", model.VulnerabilityNamespaceTableName),
+		model.PackageIndexSchema,
+		model.VulnerabilityNamespaceSchema,
+	} {
+		if err := tx.Exec(statement).Error; err != nil {
+			tx.Rollback()
+			return fmt.Errorf("unable to create package index: %w", err)
+		}
+	}
+
+	if err := indexVulnerabilities(tx, "1 = 1"); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	s.packagesIndexed = true
+	return nil
+}
+
+// HasPackageIndex indicates if the vulnerabilities within the sqlite DB are indexed by package.
+func (s *Writer) HasPackageIndex() (bool, error) {
+	return s.packagesIndexed, nil
+}
+
+// indexVulnerabilities adds the vulnerabilities that match the given condition (and their namespaces) to the package
+// index.
+func indexVulnerabilities(tx *gorm.DB, condition string, args ...interface{}) error {
+	rows, err := tx.Table(model.VulnerabilityTableName).Select("pk, namespace, package_name").Where(condition, args...).Rows()
+	if err != nil {
+		return fmt.Errorf("unable to read vulnerabilities to index: %w", err)
+	}
+
+	// note: rows are read completely before indexing, since the connection is busy until the rows are closed
+	var models []model.VulnerabilityModel
+	for rows.Next() {
+		var m model.VulnerabilityModel
+		if err := rows.Scan(&m.PK, &m.Namespace, &m.PackageName); err != nil {
+			rows.Close()
+			return fmt.Errorf("unable to read vulnerabilities to index: %w", err)
+		}
+		models = append(models, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("unable to read vulnerabilities to index: %w", err)
+	}
+
+	namespaces := internal.NewStringSet()
+	for _, m := range models {
+		if !namespaces.Contains(m.Namespace) {
+			if err := indexNamespace(tx, m.Namespace); err != nil {
+				return err
+			}
+			namespaces.Add(m.Namespace)
+		}
+		if err := indexVulnerability(tx, m.PK, m.Namespace, m.PackageName); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexNamespace records that there are vulnerabilities within the given namespace.
+func indexNamespace(tx *gorm.DB, namespace string) error {
+	statement := fmt.Sprintf("INSERT OR IGNORE INTO %s (namespace) VALUES (?)", model.VulnerabilityNamespaceTableName)
+	if err := tx.Exec(statement, namespace).Error; err != nil {
+		return fmt.Errorf("unable to index namespace=%q: %w", namespace, err)
+	}
+	return nil
+}
+
+// indexVulnerability adds a vulnerability to the package index.
+func indexVulnerability(tx *gorm.DB, pk uint64, namespace, packageName string) error {
+	key := v3.PackageKey(namespace, packageName)
+	if key == "" {
+		// vulnerabilities within distro namespaces are only found by namespace and package name
+		return nil
+	}
+
+	statement := fmt.Sprintf("INSERT OR IGNORE INTO %s (package_key, namespace, vulnerability_pk) VALUES (?, ?, ?)", model.PackageIndexTableName)
+	if err := tx.Exec(statement, key, namespace, pk).Error; err != nil {
+		return fmt.Errorf("unable to index package=%q: %w", packageName, err)
 	}
 	return nil
 }
@@ -143,6 +368,73 @@ func (s *Writer) GetVulnerabilityMetadata(id, namespace string) (*v3.Vulnerabili
 	return nil, nil
 }
 
+// GetDistroEOL retrieves the end of life of the distro release with the given namespace (or nil when unknown).
+func (s *Writer) GetDistroEOL(namespace string) (*v3.DistroEOL, error) {
+	var models []model.DistroEOLModel
+
+	result := s.db.Where(&model.DistroEOLModel{Namespace: namespace}).Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	eol, err := models[0].Inflate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &eol, nil
+}
+
+// AddDistroEOL stores (or replaces) the end of life of one or more distro releases into the sqlite DB.
+func (s *Writer) AddDistroEOL(eols ...v3.DistroEOL) error {
+	for _, eol := range eols {
+		m := model.NewDistroEOLModel(eol)
+
+		result := s.db.Save(&m)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+	return nil
+}
+
+// GetProviders retrieves all providers within the sqlite DB, ordered by name.
+func (s *Writer) GetProviders() ([]v3.Provider, error) {
+	var models []model.ProviderModel
+
+	result := s.db.Order("name").Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	providers := make([]v3.Provider, 0, len(models))
+	for _, m := range models {
+		provider, err := m.Inflate()
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+// AddProvider stores (or replaces) one or more providers into the sqlite DB.
+func (s *Writer) AddProvider(providers ...v3.Provider) error {
+	for _, provider := range providers {
+		m := model.NewProviderModel(provider)
+
+		result := s.db.Save(&m)
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+	return nil
+}
+
 // nolint:gocognit
 // AddVulnerabilityMetadata stores one or more vulnerability metadata models into the sqlite DB.
 func (s *Writer) AddVulnerabilityMetadata(metadata ...v3.VulnerabilityMetadata) error {
@@ -185,6 +477,26 @@ func (s *Writer) AddVulnerabilityMetadata(metadata ...v3.VulnerabilityMetadata)
 			existing.URLs = links.ToSlice()
 			sort.Strings(existing.URLs)
 
+			// the earliest publication and the latest modification of the merged entries are kept
+			if m.Published != nil && (existing.Published == nil || m.Published.Before(*existing.Published)) {
+				existing.Published = m.Published
+			}
+			if m.Modified != nil && (existing.Modified == nil || m.Modified.After(*existing.Modified)) {
+				existing.Modified = m.Modified
+			}
+
+			if len(m.CWEs) > 0 {
+				cwes := internal.NewStringSetFromSlice(existing.CWEs)
+				for _, c := range m.CWEs {
+					cwes.Add(c)
+				}
+				existing.CWEs = cwes.ToSlice()
+				sort.Strings(existing.CWEs)
+			}
+			if existing.Exploitation == "" {
+				existing.Exploitation = m.Exploitation
+			}
+
 			newModel := model.NewVulnerabilityMetadataModel(*existing)
 			result := s.db.Save(&newModel)
 
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
@@ -185,6 +186,9 @@ func TestStore_GetVulnerability_SetVulnerability(t *testing.T) {
 				Versions: []string{"4.0.5"},
 				State:    v3.FixedState,
 			},
+			RpmModularity: "nodejs:12",
+			Arches:        []string{"x86_64", "aarch64"},
+			PlatformCPEs:  []string{"cpe:2.3:o:microsoft:windows:-:*:*:*:*:*:*:*"},
 		},
 	}
 
@@ -209,7 +213,525 @@ func TestStore_GetVulnerability_SetVulnerability(t *testing.T) {
 		t.Fatalf("could not open db reader: %+v", err)
 	}
 	assertVulnerabilityReader(t, storeReader, expected[0].Namespace, expected[0].PackageName, expected)
+	assertBatchReader(t, storeReader, expected[0].Namespace, map[string][]v3.Vulnerability{
+		expected[0].PackageName: expected,
+		"missing-package":       nil,
+	})
+
+	// the reader is safe to use concurrently, where reads are not serialized
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				actual, err := storeReader.GetVulnerability(expected[0].Namespace, expected[0].PackageName)
+				assert.NoError(t, err)
+				assert.Len(t, actual, len(expected))
+
+				namespaces, err := storeReader.GetVulnerabilityNamespaces()
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"my-namespace"}, namespaces)
+			}
+		}()
+	}
+	wg.Wait()
+
+	namespaces, err := storeReader.GetVulnerabilityNamespaces()
+	if err != nil {
+		t.Fatalf("could not get namespaces: %+v", err)
+	}
+	assert.Equal(t, []string{"my-namespace"}, namespaces)
+}
+
+func TestStore_GetVulnerability_WithoutOptionalColumns(t *testing.T) {
+	dbTempFile, err := ioutil.TempFile("", "grype-db-test-store")
+	if err != nil {
+		t.Fatalf("could not create temp file: %+v", err)
+	}
+	defer os.Remove(dbTempFile.Name())
+
+	store, cleanupFn, err := New(dbTempFile.Name(), true)
+	defer cleanupFn()
+	if err != nil {
+		t.Fatalf("could not create store: %+v", err)
+	}
+
+	expected := []v3.Vulnerability{
+		{
+			ID:                     "my-cve",
+			PackageName:            "package-name",
+			Namespace:              "my-namespace",
+			VersionConstraint:      "< 1.0",
+			VersionFormat:          "semver",
+			CPEs:                   []string{},
+			RelatedVulnerabilities: []v3.VulnerabilityReference{},
+			Fix: v3.Fix{
+				Versions: []string{"1.0.1"},
+				State:    v3.FixedState,
+			},
+			Advisories: []v3.Advisory{},
+		},
+	}
+
+	if err = store.AddVulnerability(expected...); err != nil {
+		t.Fatalf("failed to set Vulnerability: %+v", err)
+	}
+
+	// emulate a DB that was built before RPM module streams, architectures and platforms were tracked
+	for _, statement := range []string{
+		"CREATE TABLE vulnerability_old AS SELECT pk, id, package_name, namespace, version_constraint, version_format, cpes, related_vulnerabilities, fixed_in_versions, fix_state, advisories FROM vulnerability",
+		"DROP TABLE vulnerability",
+		"ALTER TABLE vulnerability_old RENAME TO vulnerability",
+		"CREATE INDEX get_vulnerability_index ON vulnerability(package_name, namespace)",
+	} {
+		if result := store.db.Exec(statement); result.Error != nil {
+			t.Fatalf("could not drop column: %+v", result.Error)
+		}
+	}
+
+	storeReader, othercleanfn, err := reader.New(dbTempFile.Name())
+	defer othercleanfn()
+	if err != nil {
+		t.Fatalf("could not open db reader: %+v", err)
+	}
+	assertVulnerabilityReader(t, storeReader, "my-namespace", "package-name", expected)
+	assertBatchReader(t, storeReader, "my-namespace", map[string][]v3.Vulnerability{"package-name": expected})
+}
+
+func TestStore_GetVulnerabilityMetadata_WithoutOptionalColumns(t *testing.T) {
+	dbTempFile, err := ioutil.TempFile("", "grype-db-test-store")
+	if err != nil {
+		t.Fatalf("could not create temp file: %+v", err)
+	}
+	defer os.Remove(dbTempFile.Name())
+
+	store, cleanupFn, err := New(dbTempFile.Name(), true)
+	defer cleanupFn()
+	if err != nil {
+		t.Fatalf("could not create store: %+v", err)
+	}
+
+	expected := v3.VulnerabilityMetadata{
+		ID:           "my-cve",
+		Namespace:    "my-namespace",
+		RecordSource: "record-source",
+		Severity:     "High",
+		URLs:         []string{"https://ancho.re"},
+		Cvss:         []v3.Cvss{},
+	}
+	if err = store.AddVulnerabilityMetadata(expected); err != nil {
+		t.Fatalf("failed to set metadata: %+v", err)
+	}
+
+	// emulate a DB that was built before publication dates, weaknesses and exploitation notes were tracked
+	for _, statement := range []string{
+		"CREATE TABLE vulnerability_metadata_old (id varchar(255), namespace varchar(255), data_source varchar(255), record_source varchar(255), severity varchar(255), urls varchar(255), description varchar(255), cvss varchar(255), PRIMARY KEY (id, namespace))",
+		"INSERT INTO vulnerability_metadata_old SELECT id, namespace, data_source, record_source, severity, urls, description, cvss FROM vulnerability_metadata",
+		"DROP TABLE vulnerability_metadata",
+		"ALTER TABLE vulnerability_metadata_old RENAME TO vulnerability_metadata",
+	} {
+		if result := store.db.Exec(statement); result.Error != nil {
+			t.Fatalf("could not drop column: %+v", result.Error)
+		}
+	}
 
+	storeReader, othercleanfn, err := reader.New(dbTempFile.Name())
+	defer othercleanfn()
+	if err != nil {
+		t.Fatalf("could not open db reader: %+v", err)
+	}
+	assertVulnerabilityMetadataReader(t, storeReader, expected.ID, expected.Namespace, expected)
+}
+
+func assertBatchReader(t *testing.T, reader v3.VulnerabilityBatchReader, namespace string, expected map[string][]v3.Vulnerability) {
+	t.Helper()
+	var names []string
+	for name := range expected {
+		names = append(names, name)
+	}
+	actual, err := reader.GetVulnerabilitiesByName(namespace, names)
+	if err != nil {
+		t.Fatalf("failed to get vulnerabilities by name: %+v", err)
+	}
+	assert.Len(t, actual, len(expected))
+	for name, vulnerabilities := range expected {
+		assert.ElementsMatch(t, vulnerabilities, actual[name], name)
+	}
+}
+
+func TestStore_DeleteVulnerability(t *testing.T) {
+	dbTempFile, err := ioutil.TempFile("", "grype-db-test-store")
+	if err != nil {
+		t.Fatalf("could not create temp file: %+v", err)
+	}
+	defer os.Remove(dbTempFile.Name())
+
+	store, cleanupFn, err := New(dbTempFile.Name(), true)
+	defer cleanupFn()
+	if err != nil {
+		t.Fatalf("could not create store: %+v", err)
+	}
+
+	vulnerability := func(id, name, namespace string) v3.Vulnerability {
+		return v3.Vulnerability{
+			ID:                     id,
+			PackageName:            name,
+			Namespace:              namespace,
+			VersionConstraint:      "< 1.0",
+			VersionFormat:          "semver",
+			CPEs:                   []string{},
+			RelatedVulnerabilities: []v3.VulnerabilityReference{},
+			Fix:                    v3.Fix{Versions: []string{}, State: v3.NotFixedState},
+			Advisories:             []v3.Advisory{},
+		}
+	}
+
+	if err = store.AddVulnerability(
+		vulnerability("CVE-2021-0001", "package-name", "my-namespace"),
+		vulnerability("CVE-2021-0001", "package-name", "my-namespace"),
+		vulnerability("CVE-2021-0002", "package-name", "my-namespace"),
+		vulnerability("CVE-2021-0001", "other-package-name", "my-namespace"),
+		vulnerability("CVE-2021-0001", "package-name", "other-namespace"),
+	); err != nil {
+		t.Fatalf("failed to set Vulnerability: %+v", err)
+	}
+
+	removed, err := store.DeleteVulnerability("my-namespace", "package-name", "CVE-2021-0001")
+	if err != nil {
+		t.Fatalf("failed to delete Vulnerability: %+v", err)
+	}
+	assert.Equal(t, int64(2), removed)
+
+	assertVulnerabilityReader(t, store, "my-namespace", "package-name", []v3.Vulnerability{vulnerability("CVE-2021-0002", "package-name", "my-namespace")})
+	assertVulnerabilityReader(t, store, "my-namespace", "other-package-name", []v3.Vulnerability{vulnerability("CVE-2021-0001", "other-package-name", "my-namespace")})
+	assertVulnerabilityReader(t, store, "other-namespace", "package-name", []v3.Vulnerability{vulnerability("CVE-2021-0001", "package-name", "other-namespace")})
+}
+
+func TestStore_ReplaceNamespaces(t *testing.T) {
+	dir := t.TempDir()
+
+	vulnerability := func(id, name, namespace string) v3.Vulnerability {
+		return v3.Vulnerability{
+			ID:                     id,
+			PackageName:            name,
+			Namespace:              namespace,
+			VersionConstraint:      "< 1.0",
+			VersionFormat:          "semver",
+			CPEs:                   []string{},
+			RelatedVulnerabilities: []v3.VulnerabilityReference{},
+			Fix:                    v3.Fix{Versions: []string{}, State: v3.NotFixedState},
+			Advisories:             []v3.Advisory{},
+		}
+	}
+	metadata := func(id, namespace, severity string) v3.VulnerabilityMetadata {
+		return v3.VulnerabilityMetadata{
+			ID:         id,
+			Namespace:  namespace,
+			DataSource: "https://example.com/" + id,
+			Severity:   severity,
+			URLs:       []string{},
+			Cvss:       []v3.Cvss{},
+		}
+	}
+
+	source, sourceCleanup, err := New(dir+"/source.db", true)
+	if err != nil {
+		t.Fatalf("could not create store: %+v", err)
+	}
+	if err = source.AddVulnerability(
+		vulnerability("CVE-2021-0003", "package-name", "changed-namespace"),
+		vulnerability("CVE-2021-0004", "package-name", "other-namespace"),
+	); err != nil {
+		t.Fatalf("failed to set Vulnerability: %+v", err)
+	}
+	if err = source.AddVulnerabilityMetadata(metadata("CVE-2021-0003", "changed-namespace", "High")); err != nil {
+		t.Fatalf("failed to set Vulnerability metadata: %+v", err)
+	}
+	if err = sourceCleanup(); err != nil {
+		t.Fatalf("could not close store: %+v", err)
+	}
+
+	store, cleanupFn, err := New(dir+"/target.db", true)
+	defer cleanupFn()
+	if err != nil {
+		t.Fatalf("could not create store: %+v", err)
+	}
+	if err = store.AddVulnerability(
+		vulnerability("CVE-2021-0001", "package-name", "changed-namespace"),
+		vulnerability("CVE-2021-0002", "package-name", "removed-namespace"),
+		vulnerability("CVE-2021-0005", "package-name", "unchanged-namespace"),
+	); err != nil {
+		t.Fatalf("failed to set Vulnerability: %+v", err)
+	}
+	if err = store.AddVulnerabilityMetadata(
+		metadata("CVE-2021-0001", "changed-namespace", "Low"),
+		metadata("CVE-2021-0005", "unchanged-namespace", "Low"),
+	); err != nil {
+		t.Fatalf("failed to set Vulnerability metadata: %+v", err)
+	}
+
+	if err = store.IndexPackages(); err != nil {
+		t.Fatalf("failed to index packages: %+v", err)
+	}
+
+	if err = store.ReplaceNamespaces(dir+"/source.db", []string{"changed-namespace", "removed-namespace"}); err != nil {
+		t.Fatalf("failed to replace namespaces: %+v", err)
+	}
+
+	storeReader, othercleanfn, err := reader.New(dir + "/target.db")
+	defer othercleanfn()
+	if err != nil {
+		t.Fatalf("could not open db reader: %+v", err)
+	}
+	assertNamespaces(t, storeReader, []string{"changed-namespace", "unchanged-namespace"})
+
+	assertVulnerabilityReader(t, store, "changed-namespace", "package-name", []v3.Vulnerability{vulnerability("CVE-2021-0003", "package-name", "changed-namespace")})
+	assertVulnerabilityReader(t, store, "removed-namespace", "package-name", []v3.Vulnerability{})
+	assertVulnerabilityReader(t, store, "unchanged-namespace", "package-name", []v3.Vulnerability{vulnerability("CVE-2021-0005", "package-name", "unchanged-namespace")})
+	assertVulnerabilityReader(t, store, "other-namespace", "package-name", []v3.Vulnerability{})
+
+	for _, test := range []struct {
+		id        string
+		namespace string
+		expected  *v3.VulnerabilityMetadata
+	}{
+		{id: "CVE-2021-0001", namespace: "changed-namespace"},
+		{id: "CVE-2021-0003", namespace: "changed-namespace", expected: &v3.VulnerabilityMetadata{ID: "CVE-2021-0003", Namespace: "changed-namespace", DataSource: "https://example.com/CVE-2021-0003", Severity: "High", URLs: []string{}, Cvss: []v3.Cvss{}}},
+		{id: "CVE-2021-0005", namespace: "unchanged-namespace", expected: &v3.VulnerabilityMetadata{ID: "CVE-2021-0005", Namespace: "unchanged-namespace", DataSource: "https://example.com/CVE-2021-0005", Severity: "Low", URLs: []string{}, Cvss: []v3.Cvss{}}},
+	} {
+		actual, err := store.GetVulnerabilityMetadata(test.id, test.namespace)
+		if err != nil {
+			t.Fatalf("failed to get metadata: %+v", err)
+		}
+		for _, d := range deep.Equal(test.expected, actual) {
+			t.Errorf("metadata diff (%s): %+v", test.id, d)
+		}
+	}
+}
+
+func TestStore_GetDistroEOL_AddDistroEOL(t *testing.T) {
+	dbTempFile, err := ioutil.TempFile("", "grype-db-test-store")
+	if err != nil {
+		t.Fatalf("could not create temp file: %+v", err)
+	}
+	defer os.Remove(dbTempFile.Name())
+
+	store, cleanupFn, err := New(dbTempFile.Name(), true)
+	defer cleanupFn()
+	if err != nil {
+		t.Fatalf("could not create store: %+v", err)
+	}
+
+	expected := v3.DistroEOL{
+		Namespace: "rhel:7",
+		Date:      time.Date(2024, time.June, 30, 0, 0, 0, 0, time.UTC),
+	}
+
+	// replace an existing entry
+	if err = store.AddDistroEOL(v3.DistroEOL{Namespace: "rhel:7", Date: time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)}); err != nil {
+		t.Fatalf("failed to add distro EOL: %+v", err)
+	}
+	if err = store.AddDistroEOL(expected); err != nil {
+		t.Fatalf("failed to add distro EOL: %+v", err)
+	}
+
+	assertDistroEOLReader(t, store, expected)
+
+	// gut check on reader
+	storeReader, othercleanfn, err := reader.New(dbTempFile.Name())
+	defer othercleanfn()
+	if err != nil {
+		t.Fatalf("could not open db reader: %+v", err)
+	}
+	assertDistroEOLReader(t, storeReader, expected)
+}
+
+func assertDistroEOLReader(t *testing.T, reader v3.DistroEOLStoreReader, expected v3.DistroEOL) {
+	t.Helper()
+	actual, err := reader.GetDistroEOL(expected.Namespace)
+	assert.NoError(t, err)
+	assert.Equal(t, &expected, actual)
+
+	actual, err = reader.GetDistroEOL("unknown:1")
+	assert.NoError(t, err)
+	assert.Nil(t, actual)
+}
+
+func TestStore_GetProviders_AddProvider(t *testing.T) {
+	dbTempFile, err := ioutil.TempFile("", "grype-db-test-store")
+	if err != nil {
+		t.Fatalf("could not create temp file: %+v", err)
+	}
+	defer os.Remove(dbTempFile.Name())
+
+	store, cleanupFn, err := New(dbTempFile.Name(), true)
+	defer cleanupFn()
+	if err != nil {
+		t.Fatalf("could not create store: %+v", err)
+	}
+
+	expected := []v3.Provider{
+		{Name: "alpine", Built: time.Date(2021, time.November, 20, 8, 14, 27, 0, time.UTC)},
+		{Name: "nvd", Built: time.Date(2021, time.November, 20, 8, 3, 12, 500, time.UTC)},
+	}
+
+	// replace an existing entry
+	if err = store.AddProvider(v3.Provider{Name: "nvd", Built: time.Date(2021, time.November, 19, 8, 0, 0, 0, time.UTC)}); err != nil {
+		t.Fatalf("failed to add provider: %+v", err)
+	}
+	if err = store.AddProvider(expected[1], expected[0]); err != nil {
+		t.Fatalf("failed to add provider: %+v", err)
+	}
+
+	assertProviderReader(t, store, expected)
+
+	// gut check on reader
+	storeReader, othercleanfn, err := reader.New(dbTempFile.Name())
+	defer othercleanfn()
+	if err != nil {
+		t.Fatalf("could not open db reader: %+v", err)
+	}
+	assertProviderReader(t, storeReader, expected)
+}
+
+func TestStore_IndexPackages(t *testing.T) {
+	dir := t.TempDir()
+
+	vulnerability := func(id, name, namespace string) v3.Vulnerability {
+		return v3.Vulnerability{
+			ID:                     id,
+			PackageName:            name,
+			Namespace:              namespace,
+			VersionConstraint:      "< 1.0",
+			VersionFormat:          "semver",
+			CPEs:                   []string{},
+			RelatedVulnerabilities: []v3.VulnerabilityReference{},
+			Fix:                    v3.Fix{Versions: []string{}, State: v3.NotFixedState},
+			Advisories:             []v3.Advisory{},
+		}
+	}
+
+	store, cleanupFn, err := New(dir+"/vulnerability.db", true)
+	defer cleanupFn()
+	if err != nil {
+		t.Fatalf("could not create store: %+v", err)
+	}
+
+	// the index of a new DB is kept up to date as it is written
+	if err = store.AddVulnerability(
+		vulnerability("GHSA-0001", "zope.interface", "github:python"),
+		vulnerability("GHSA-0003", "Zope_Interface", "github:python"),
+		vulnerability("PYSEC-0001", "zope.interface", "osv:pypi"),
+		vulnerability("PYSEC-0002", "Requests", "osv:pypi"),
+		vulnerability("CVE-2021-0001", "openssl", "nvd"),
+		vulnerability("CVE-2021-0001", "openssl", "debian:10"),
+	); err != nil {
+		t.Fatalf("failed to set Vulnerability: %+v", err)
+	}
+	if _, err = store.DeleteVulnerability("osv:pypi", "zope.interface", "PYSEC-0001"); err != nil {
+		t.Fatalf("failed to delete Vulnerability: %+v", err)
+	}
+
+	assertIndex := func(t *testing.T) {
+		t.Helper()
+		storeReader, othercleanfn, err := reader.New(dir + "/vulnerability.db")
+		defer othercleanfn()
+		if err != nil {
+			t.Fatalf("could not open db reader: %+v", err)
+		}
+		indexed, err := storeReader.HasPackageIndex()
+		assert.NoError(t, err)
+		assert.True(t, indexed)
+		assertNamespaces(t, storeReader, []string{"github:python", "osv:pypi", "nvd", "debian:10"})
+
+		for _, test := range []struct {
+			key        string
+			namespaces []string
+			expected   []string
+		}{
+			// package names are normalized (see v3.PackageKey)
+			{key: "pypi/zope-interface", namespaces: []string{"github:python", "osv:pypi"}, expected: []string{"GHSA-0001", "GHSA-0003"}},
+			{key: "pypi/zope-interface", namespaces: []string{"osv:pypi"}},
+			{key: "pypi/requests", namespaces: []string{"github:python", "osv:pypi"}, expected: []string{"PYSEC-0002"}},
+			{key: "cpe/openssl", namespaces: []string{"nvd", "vulndb"}, expected: []string{"CVE-2021-0001"}},
+			{key: "cpe/openssl", namespaces: []string{"debian:10"}},
+			{key: "pypi/unknown", namespaces: []string{"github:python", "osv:pypi"}},
+		} {
+			actual, err := storeReader.GetVulnerabilityByPackageKey(test.key, test.namespaces)
+			if err != nil {
+				t.Fatalf("failed to get vulnerabilities by package key: %+v", err)
+			}
+			var ids []string
+			for _, v := range actual {
+				ids = append(ids, v.ID)
+			}
+			assert.ElementsMatch(t, test.expected, ids, "key=%q namespaces=%+v", test.key, test.namespaces)
+		}
+	}
+	assertIndex(t)
+
+	// the index is rebuilt as it is
+	if err = store.IndexPackages(); err != nil {
+		t.Fatalf("failed to index packages: %+v", err)
+	}
+	assertIndex(t)
+}
+
+func TestStore_ExistingDBIsNotMigrated(t *testing.T) {
+	dbPath := t.TempDir() + "/vulnerability.db"
+
+	// a DB that was built without the package index and the newer tables
+	store, cleanupFn, err := New(dbPath, true)
+	if err != nil {
+		t.Fatalf("could not create store: %+v", err)
+	}
+	for _, table := range []string{model.PackageIndexTableName, model.VulnerabilityNamespaceTableName, model.DistroEOLTableName, model.ProviderTableName} {
+		if err = store.db.DropTable(table).Error; err != nil {
+			t.Fatalf("could not drop table=%q: %+v", table, err)
+		}
+	}
+	if err = cleanupFn(); err != nil {
+		t.Fatalf("could not close store: %+v", err)
+	}
+
+	store, cleanupFn, err = New(dbPath, false)
+	if err != nil {
+		t.Fatalf("could not open store: %+v", err)
+	}
+	if err = store.AddVulnerability(v3.Vulnerability{ID: "GHSA-0001", PackageName: "requests", Namespace: "github:python"}); err != nil {
+		t.Fatalf("failed to set Vulnerability: %+v", err)
+	}
+	if err = cleanupFn(); err != nil {
+		t.Fatalf("could not close store: %+v", err)
+	}
+
+	storeReader, othercleanfn, err := reader.New(dbPath)
+	defer othercleanfn()
+	if err != nil {
+		t.Fatalf("could not open db reader: %+v", err)
+	}
+	indexed, err := storeReader.HasPackageIndex()
+	assert.NoError(t, err)
+	assert.False(t, indexed)
+	assertNamespaces(t, storeReader, []string{"github:python"})
+	providers, err := storeReader.GetProviders()
+	assert.NoError(t, err)
+	assert.Empty(t, providers)
+}
+
+func assertNamespaces(t *testing.T, reader v3.VulnerabilityNamespaceReader, expected []string) {
+	t.Helper()
+	actual, err := reader.GetVulnerabilityNamespaces()
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, expected, actual)
+}
+
+func assertProviderReader(t *testing.T, reader v3.ProviderStoreReader, expected []v3.Provider) {
+	t.Helper()
+	actual, err := reader.GetProviders()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
 }
 
 func assertVulnerabilityMetadataReader(t *testing.T, reader v3.VulnerabilityMetadataStoreReader, id, namespace string, expected v3.VulnerabilityMetadata) {
@@ -292,6 +814,10 @@ func TestStore_GetVulnerabilityMetadata_SetVulnerabilityMetadata(t *testing.T) {
 			Severity:     "pretty bad",
 			URLs:         []string{"https://ancho.re"},
 			Description:  "best description ever",
+			Published:    timeRef(time.Date(2021, 12, 10, 10, 15, 0, 0, time.UTC)),
+			Modified:     timeRef(time.Date(2022, 1, 4, 8, 0, 0, 0, time.UTC)),
+			CWEs:         []string{"CWE-79"},
+			Exploitation: "exploited in the wild",
 			Cvss: []v3.Cvss{
 				{
 					VendorMetadata: CustomMetadata{
@@ -471,6 +997,80 @@ func TestStore_MergeVulnerabilityMetadata(t *testing.T) {
 				Cvss:         []v3.Cvss{},
 			},
 		},
+		{
+			name: "merge-dates",
+			add: []v3.VulnerabilityMetadata{
+				{
+					ID:           "my-cve",
+					RecordSource: "record-source",
+					Namespace:    "namespace",
+					Severity:     "pretty bad",
+					Published:    timeRef(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)),
+					Modified:     timeRef(time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)),
+				},
+				{
+					ID:           "my-cve",
+					RecordSource: "record-source",
+					Namespace:    "namespace",
+					Severity:     "pretty bad",
+					Published:    timeRef(time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
+					Modified:     timeRef(time.Date(2021, 3, 15, 0, 0, 0, 0, time.UTC)),
+				},
+				{
+					ID:           "my-cve",
+					RecordSource: "record-source",
+					Namespace:    "namespace",
+					Severity:     "pretty bad",
+				},
+			},
+			expected: v3.VulnerabilityMetadata{
+				ID:           "my-cve",
+				RecordSource: "record-source",
+				Namespace:    "namespace",
+				Severity:     "pretty bad",
+				URLs:         []string{},
+				Cvss:         []v3.Cvss{},
+				Published:    timeRef(time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)),
+				Modified:     timeRef(time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)),
+			},
+		},
+		{
+			name: "merge-weaknesses",
+			add: []v3.VulnerabilityMetadata{
+				{
+					ID:           "my-cve",
+					RecordSource: "record-source",
+					Namespace:    "namespace",
+					Severity:     "pretty bad",
+					CWEs:         []string{"CWE-79"},
+				},
+				{
+					ID:           "my-cve",
+					RecordSource: "record-source",
+					Namespace:    "namespace",
+					Severity:     "pretty bad",
+					CWEs:         []string{"CWE-20", "CWE-79"},
+					Exploitation: "exploited in the wild",
+				},
+				{
+					ID:           "my-cve",
+					RecordSource: "record-source",
+					Namespace:    "namespace",
+					Severity:     "pretty bad",
+					Exploitation: "proof of concept available",
+				},
+			},
+			expected: v3.VulnerabilityMetadata{
+				ID:           "my-cve",
+				RecordSource: "record-source",
+				Namespace:    "namespace",
+				Severity:     "pretty bad",
+				URLs:         []string{},
+				Cvss:         []v3.Cvss{},
+				CWEs:         []string{"CWE-20", "CWE-79"},
+				Exploitation: "exploited in the wild",
+			},
+		},
 		{
 			name: "bad-severity",
 			add: []v3.VulnerabilityMetadata{
@@ -1061,3 +1661,7 @@ func TestCvssScoresInMetadata(t *testing.T) {
 		})
 	}
 }
+
+func timeRef(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"container/list"
+	"sync"
+
+	grypeDB "github.com/anchore/grype/grype/db/v3"
+)
+
+// DefaultCacheSize is the number of package lookups that are kept in memory by default.
+const DefaultCacheSize = 10000
+
+// cacheKey identifies the vulnerabilities of a package within a namespace (or of a package key within a set of
+// namespaces).
+type cacheKey struct {
+	namespace string
+	name      string
+}
+
+type cacheEntry struct {
+	key             cacheKey
+	vulnerabilities []grypeDB.Vulnerability
+}
+
+// vulnerabilityCache is a least recently used cache of the vulnerabilities of packages. The same package is commonly
+// looked up many times during a scan (e.g. the upstream source package of many binary packages), which are then
+// served from memory instead of the store.
+type vulnerabilityCache struct {
+	size    int
+	lock    sync.Mutex
+	entries map[cacheKey]*list.Element
+	order   *list.List
+}
+
+func newVulnerabilityCache(size int) *vulnerabilityCache {
+	return &vulnerabilityCache{
+		size:    size,
+		entries: make(map[cacheKey]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the cached vulnerabilities of a package within a namespace, and if the package is within the cache.
+func (c *vulnerabilityCache) get(namespace, name string) ([]grypeDB.Vulnerability, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[cacheKey{namespace: namespace, name: name}]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*cacheEntry).vulnerabilities, true
+}
+
+// add caches the vulnerabilities of a package within a namespace, evicting the least recently used package when the
+// cache is full.
+func (c *vulnerabilityCache) add(namespace, name string, vulnerabilities []grypeDB.Vulnerability) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := cacheKey{namespace: namespace, name: name}
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*cacheEntry).vulnerabilities = vulnerabilities
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, vulnerabilities: vulnerabilities})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
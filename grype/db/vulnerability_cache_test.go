@@ -0,0 +1,40 @@
+package db
+
+import (
+	"testing"
+
+	grypeDB "github.com/anchore/grype/grype/db/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVulnerabilityCache(t *testing.T) {
+	cache := newVulnerabilityCache(2)
+	neutron := []grypeDB.Vulnerability{{ID: "CVE-2014-fake-1"}}
+	bash := []grypeDB.Vulnerability{{ID: "CVE-2014-fake-2"}}
+
+	cache.add("debian:8", "neutron", neutron)
+	cache.add("debian:8", "bash", bash)
+
+	// a lookup makes neutron the most recently used package...
+	actual, ok := cache.get("debian:8", "neutron")
+	assert.True(t, ok)
+	assert.Equal(t, neutron, actual)
+
+	// ...so bash is evicted to make room for another package
+	cache.add("debian:8", "openssl", nil)
+	_, ok = cache.get("debian:8", "bash")
+	assert.False(t, ok)
+	_, ok = cache.get("debian:8", "neutron")
+	assert.True(t, ok)
+
+	// packages without vulnerabilities are cached too
+	actual, ok = cache.get("debian:8", "openssl")
+	assert.True(t, ok)
+	assert.Empty(t, actual)
+
+	// the namespace is part of the key
+	_, ok = cache.get("debian:9", "neutron")
+	assert.False(t, ok)
+	assert.Equal(t, 2, cache.order.Len())
+	assert.Len(t, cache.entries, 2)
+}
@@ -2,36 +2,93 @@ package db
 
 import (
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/anchore/grype/grype/cpe"
 
 	grypeDB "github.com/anchore/grype/grype/db/v3"
 	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/metrics"
 	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal"
+	"github.com/anchore/grype/internal/log"
 	syftPkg "github.com/anchore/syft/syft/pkg"
 	"github.com/facebookincubator/nvdtools/wfn"
 )
 
 var _ vulnerability.Provider = (*VulnerabilityProvider)(nil)
+var _ vulnerability.DistroEOLProvider = (*VulnerabilityProvider)(nil)
+var _ vulnerability.BatchProvider = (*VulnerabilityProvider)(nil)
+
+// DefaultPrefetchLimit is the number of package names whose vulnerabilities are retrieved at once by default (see
+// WithPackages).
+const DefaultPrefetchLimit = 10000
 
 type VulnerabilityProvider struct {
-	reader grypeDB.VulnerabilityStoreReader
+	reader     grypeDB.VulnerabilityStoreReader
+	aliasRules []distro.AliasRule
+	namespaces *namespaceIndex
+	packages   *packageIndex
+	// prefetched are the vulnerabilities of each package name within each namespace that were retrieved at once (see
+	// WithPackages)
+	prefetched map[string]map[string][]grypeDB.Vulnerability
+	// prefetchLimit is the number of package names whose vulnerabilities are retrieved at once at most (see
+	// WithPackages)
+	prefetchLimit int
+	// cache keeps the most recently retrieved vulnerabilities of packages in memory (see WithCache)
+	cache *vulnerabilityCache
 }
 
 func NewVulnerabilityProvider(reader grypeDB.VulnerabilityStoreReader) *VulnerabilityProvider {
+	return NewVulnerabilityProviderWithAliases(reader, distro.DefaultAliasRules)
+}
+
+// NewVulnerabilityProviderWithAliases returns a provider that matches distros which are aliases of another distro
+// (according to the given rules) against the vulnerability data of the target distro when the store has no data for
+// the aliased distro itself.
+func NewVulnerabilityProviderWithAliases(reader grypeDB.VulnerabilityStoreReader, rules []distro.AliasRule) *VulnerabilityProvider {
 	return &VulnerabilityProvider{
-		reader: reader,
+		reader:        reader,
+		aliasRules:    rules,
+		namespaces:    newNamespaceIndex(reader),
+		packages:      newPackageIndex(reader),
+		prefetchLimit: DefaultPrefetchLimit,
 	}
 }
 
+// WithCache keeps the vulnerabilities of (up to) the given number of the most recently retrieved packages in memory,
+// so that repeated lookups of the same package (e.g. of a source package shared by many binary packages) do not query
+// the store again. A size of zero or less disables caching.
+func (pr *VulnerabilityProvider) WithCache(size int) *VulnerabilityProvider {
+	pr.cache = nil
+	if size > 0 {
+		pr.cache = newVulnerabilityCache(size)
+	}
+	return pr
+}
+
+// WithPrefetchLimit limits the number of package names whose vulnerabilities are retrieved at once (see WithPackages),
+// which bounds the memory held by the provider of a batch. The vulnerabilities of any other package are retrieved on
+// demand. A limit of zero or less disables retrieving vulnerabilities at once.
+func (pr *VulnerabilityProvider) WithPrefetchLimit(limit int) *VulnerabilityProvider {
+	pr.prefetchLimit = limit
+	return pr
+}
+
 func (pr *VulnerabilityProvider) GetByDistro(d *distro.Distro, p pkg.Package) ([]vulnerability.Vulnerability, error) {
 	if d == nil {
 		return nil, nil
 	}
 
-	namespace := grypeDB.NamespaceForDistro(d)
-	allPkgVulns, err := pr.reader.GetVulnerability(namespace, p.Name)
+	namespace, err := pr.namespaceForDistro(d)
+	if err != nil {
+		return nil, err
+	}
+
+	allPkgVulns, err := pr.getVulnerability(namespace, p.Name)
 
 	if err != nil {
 		return nil, fmt.Errorf("provider failed to fetch namespace='%s' pkg='%s': %w", namespace, p.Name, err)
@@ -51,6 +108,153 @@ func (pr *VulnerabilityProvider) GetByDistro(d *distro.Distro, p pkg.Package) ([
 	return vulnerabilities, nil
 }
 
+// WithPackages returns a provider that has retrieved the vulnerabilities of the given packages (and their upstream
+// packages) with a single query for each namespace, which falls back to querying the store for any other package.
+// At most the prefetch limit of package names are retrieved (see WithPrefetchLimit), where the vulnerabilities of the
+// remaining packages are retrieved on demand.
+// Note: packages that are found by package key (see grypeDB.PackageKey) are already found with a single lookup.
+func (pr *VulnerabilityProvider) WithPackages(d *distro.Distro, packages []pkg.Package) (vulnerability.Provider, error) {
+	batchReader, ok := pr.reader.(grypeDB.VulnerabilityBatchReader)
+	if !ok || pr.prefetchLimit <= 0 {
+		return pr, nil
+	}
+
+	index, err := pr.packages.get()
+	if err != nil {
+		return nil, fmt.Errorf("provider failed to determine if packages are indexed: %w", err)
+	}
+
+	var distroNamespace string
+	if d != nil {
+		if distroNamespace, err = pr.namespaceForDistro(d); err != nil {
+			return nil, err
+		}
+	}
+
+	var total int
+	batches := make(map[string]internal.StringSet)
+	add := func(namespace string, names ...string) {
+		for _, name := range names {
+			if index != nil && grypeDB.PackageKey(namespace, name) != "" {
+				continue
+			}
+			if total >= pr.prefetchLimit || batches[namespace].Contains(name) {
+				continue
+			}
+			if _, ok := batches[namespace]; !ok {
+				batches[namespace] = internal.NewStringSet()
+			}
+			batches[namespace].Add(name)
+			total++
+		}
+	}
+	for _, p := range packages {
+		for _, candidate := range append([]pkg.Package{p}, pkg.UpstreamPackages(p)...) {
+			if distroNamespace != "" {
+				add(distroNamespace, candidate.Name)
+			}
+			if candidate.Language != "" {
+				for namespace, names := range namesByNamespace(grypeDB.NamespacePackageNamersForLanguage(candidate.Language), candidate) {
+					add(namespace, names...)
+				}
+			}
+			for namespace, names := range namesByNamespace(grypeDB.NamespacePackageNamersForPackageType(candidate.Type), candidate) {
+				add(namespace, names...)
+			}
+		}
+	}
+
+	prefetched := make(map[string]map[string][]grypeDB.Vulnerability)
+	for namespace, names := range batches {
+		vulns, err := batchReader.GetVulnerabilitiesByName(namespace, names.ToSlice())
+		if err != nil {
+			return nil, fmt.Errorf("provider failed to fetch namespace='%s' pkgs=%d: %w", namespace, len(names), err)
+		}
+		prefetched[namespace] = vulns
+	}
+
+	batched := *pr
+	batched.prefetched = prefetched
+	return &batched, nil
+}
+
+// getVulnerability returns the vulnerabilities of a package within a namespace, which are possibly prefetched (see
+// WithPackages) or cached (see WithCache).
+func (pr *VulnerabilityProvider) getVulnerability(namespace, name string) ([]grypeDB.Vulnerability, error) {
+	if vulns, ok := pr.prefetched[namespace][name]; ok {
+		return vulns, nil
+	}
+	return pr.cached(namespace, name, func() ([]grypeDB.Vulnerability, error) {
+		return pr.reader.GetVulnerability(namespace, name)
+	})
+}
+
+// cached returns the cached vulnerabilities of a package within a namespace, retrieving (and caching) them when they
+// are not cached yet.
+func (pr *VulnerabilityProvider) cached(namespace, name string, retrieve func() ([]grypeDB.Vulnerability, error)) ([]grypeDB.Vulnerability, error) {
+	if pr.cache == nil {
+		return retrieve()
+	}
+	vulns, ok := pr.cache.get(namespace, name)
+	metrics.ObserveCache(metrics.VulnerabilityCache, ok)
+	if ok {
+		return vulns, nil
+	}
+
+	vulns, err := retrieve()
+	if err != nil {
+		return nil, err
+	}
+	pr.cache.add(namespace, name, vulns)
+	return vulns, nil
+}
+
+// GetDistroEOL returns when the given distro release reached (or will reach) the end of life, or nil when the store
+// does not track the end of life of the release.
+func (pr *VulnerabilityProvider) GetDistroEOL(d *distro.Distro) (*time.Time, error) {
+	if d == nil {
+		return nil, nil
+	}
+
+	eolReader, ok := pr.reader.(grypeDB.DistroEOLStoreReader)
+	if !ok {
+		return nil, nil
+	}
+
+	namespace := grypeDB.NamespaceForDistro(d)
+	eol, err := eolReader.GetDistroEOL(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("provider failed to fetch end of life for namespace='%s': %w", namespace, err)
+	}
+	if eol == nil {
+		return nil, nil
+	}
+
+	return &eol.Date, nil
+}
+
+// namespaceForDistro returns the namespace to search for vulnerabilities of the given distro, which is the namespace
+// of the alias target when the store has no vulnerabilities for the distro itself.
+func (pr *VulnerabilityProvider) namespaceForDistro(d *distro.Distro) (string, error) {
+	namespace := grypeDB.NamespaceForDistro(d)
+
+	target := d.AliasTarget(pr.aliasRules)
+	if target == nil {
+		return namespace, nil
+	}
+
+	exists, err := pr.namespaces.contains(namespace)
+	if err != nil {
+		return "", fmt.Errorf("provider failed to determine available namespaces: %w", err)
+	}
+	if exists {
+		return namespace, nil
+	}
+
+	log.Debugf("no vulnerability data for distro=%q, using the data for distro=%q instead", d, target)
+	return grypeDB.NamespaceForDistro(target), nil
+}
+
 func (pr *VulnerabilityProvider) GetByLanguage(l syftPkg.Language, p pkg.Package) ([]vulnerability.Vulnerability, error) {
 	vulns := make([]vulnerability.Vulnerability, 0)
 
@@ -59,22 +263,43 @@ func (pr *VulnerabilityProvider) GetByLanguage(l syftPkg.Language, p pkg.Package
 		return nil, fmt.Errorf("no store namespaces found for language '%s'", l)
 	}
 
-	for namespace, namer := range namersByNamespace {
-		for _, name := range namer(p) {
-			allPkgVulns, err := pr.reader.GetVulnerability(namespace, name)
-			if err != nil {
-				return nil, fmt.Errorf("provider failed to fetch namespace='%s' pkg='%s': %w", namespace, name, err)
-			}
+	allPkgVulns, err := pr.getByNamespaces(namesByNamespace(namersByNamespace, p))
+	if err != nil {
+		return nil, err
+	}
 
-			for _, vuln := range allPkgVulns {
-				vulnObj, err := vulnerability.NewVulnerability(vuln)
-				if err != nil {
-					return nil, fmt.Errorf("provider failed to parse language='%s': %w", l, err)
-				}
+	for _, vuln := range allPkgVulns {
+		vulnObj, err := vulnerability.NewVulnerability(vuln)
+		if err != nil {
+			return nil, fmt.Errorf("provider failed to parse language='%s': %w", l, err)
+		}
 
-				vulns = append(vulns, *vulnObj)
-			}
+		vulns = append(vulns, *vulnObj)
+	}
+
+	return vulns, nil
+}
+
+func (pr *VulnerabilityProvider) GetByPackageType(t syftPkg.Type, p pkg.Package) ([]vulnerability.Vulnerability, error) {
+	vulns := make([]vulnerability.Vulnerability, 0)
+
+	namersByNamespace := grypeDB.NamespacePackageNamersForPackageType(t)
+	if namersByNamespace == nil {
+		return nil, fmt.Errorf("no store namespaces found for package type '%s'", t)
+	}
+
+	allPkgVulns, err := pr.getByNamespaces(namesByNamespace(namersByNamespace, p))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vuln := range allPkgVulns {
+		vulnObj, err := vulnerability.NewVulnerability(vuln)
+		if err != nil {
+			return nil, fmt.Errorf("provider failed to parse package type='%s': %w", t, err)
 		}
+
+		vulns = append(vulns, *vulnObj)
 	}
 
 	return vulns, nil
@@ -92,33 +317,85 @@ func (pr *VulnerabilityProvider) GetByCPE(requestCPE syftPkg.CPE) ([]vulnerabili
 		return nil, fmt.Errorf("product name is required")
 	}
 
+	names := make(map[string][]string)
 	for _, namespace := range namespaces {
-		allPkgVulns, err := pr.reader.GetVulnerability(namespace, requestCPE.Product)
+		names[namespace] = []string{requestCPE.Product}
+	}
+
+	allPkgVulns, err := pr.getByNamespaces(names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, vuln := range allPkgVulns {
+		vulnCPEs, err := cpe.NewSlice(vuln.CPEs...)
 		if err != nil {
-			return nil, fmt.Errorf("provider failed to fetch namespace='%s' product='%s': %w", namespace, requestCPE.Product, err)
+			return nil, err
 		}
 
-		for _, vuln := range allPkgVulns {
-			vulnCPEs, err := cpe.NewSlice(vuln.CPEs...)
+		// compare the request CPE to the potential matches (excluding version, which is handled downstream)
+		candidateMatchCpes := cpe.MatchWithoutVersion(requestCPE, vulnCPEs)
+
+		if len(candidateMatchCpes) > 0 {
+			vulnObj, err := vulnerability.NewVulnerability(vuln)
 			if err != nil {
-				return nil, err
+				return nil, fmt.Errorf("provider failed to parse cpe='%s': %w", requestCPE.BindToFmtString(), err)
 			}
 
-			// compare the request CPE to the potential matches (excluding version, which is handled downstream)
-			candidateMatchCpes := cpe.MatchWithoutVersion(requestCPE, vulnCPEs)
+			vulnObj.CPEs = candidateMatchCpes
 
-			if len(candidateMatchCpes) > 0 {
-				vulnObj, err := vulnerability.NewVulnerability(vuln)
-				if err != nil {
-					return nil, fmt.Errorf("provider failed to parse cpe='%s': %w", requestCPE.BindToFmtString(), err)
-				}
+			vulns = append(vulns, *vulnObj)
+		}
+	}
+
+	return vulns, nil
+}
+
+// getByNamespaces returns the vulnerabilities of the given package names within each namespace. When the store indexes
+// vulnerabilities by package, each package key is looked up once for all namespaces that share it (e.g. "github:python"
+// and "osv:pypi") instead of searching every namespace for every name.
+func (pr *VulnerabilityProvider) getByNamespaces(names map[string][]string) ([]grypeDB.Vulnerability, error) {
+	index, err := pr.packages.get()
+	if err != nil {
+		return nil, fmt.Errorf("provider failed to determine if packages are indexed: %w", err)
+	}
 
-				vulnObj.CPEs = candidateMatchCpes
+	var vulns []grypeDB.Vulnerability
+	namespacesByKey := make(map[string][]string)
+	for namespace, namespaceNames := range names {
+		for _, name := range namespaceNames {
+			if key := grypeDB.PackageKey(namespace, name); index != nil && key != "" {
+				namespacesByKey[key] = append(namespacesByKey[key], namespace)
+				continue
+			}
 
-				vulns = append(vulns, *vulnObj)
+			allPkgVulns, err := pr.getVulnerability(namespace, name)
+			if err != nil {
+				return nil, fmt.Errorf("provider failed to fetch namespace='%s' pkg='%s': %w", namespace, name, err)
 			}
+			vulns = append(vulns, allPkgVulns...)
 		}
 	}
 
+	for key, namespaces := range namespacesByKey {
+		sort.Strings(namespaces)
+		allPkgVulns, err := pr.cached(strings.Join(namespaces, ","), key, func() ([]grypeDB.Vulnerability, error) {
+			return index.GetVulnerabilityByPackageKey(key, namespaces)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("provider failed to fetch key='%s': %w", key, err)
+		}
+		vulns = append(vulns, allPkgVulns...)
+	}
+
 	return vulns, nil
 }
+
+// namesByNamespace returns the names of the given package within each namespace.
+func namesByNamespace(namersByNamespace map[string]grypeDB.NamerByPackage, p pkg.Package) map[string][]string {
+	names := make(map[string][]string)
+	for namespace, namer := range namersByNamespace {
+		names[namespace] = namer(p)
+	}
+	return names
+}
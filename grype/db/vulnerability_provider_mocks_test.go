@@ -1,6 +1,10 @@
 package db
 
-import grypeDB "github.com/anchore/grype/grype/db/v3"
+import (
+	"time"
+
+	grypeDB "github.com/anchore/grype/grype/db/v3"
+)
 
 type mockStore struct {
 	data map[string]map[string][]grypeDB.Vulnerability
@@ -82,3 +86,82 @@ func (d *mockStore) stub() {
 func (d *mockStore) GetVulnerability(namespace, name string) ([]grypeDB.Vulnerability, error) {
 	return d.data[namespace][name], nil
 }
+
+// mockNamespacedStore is a store that is able to describe the namespaces it contains.
+type mockNamespacedStore struct {
+	mockStore
+}
+
+func (d *mockNamespacedStore) GetVulnerabilityNamespaces() ([]string, error) {
+	var namespaces []string
+	for namespace := range d.data {
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces, nil
+}
+
+// mockEOLStore is a store that tracks the end of life of distro releases.
+type mockEOLStore struct {
+	mockStore
+	eols map[string]time.Time
+}
+
+func (d *mockEOLStore) GetDistroEOL(namespace string) (*grypeDB.DistroEOL, error) {
+	date, ok := d.eols[namespace]
+	if !ok {
+		return nil, nil
+	}
+	return &grypeDB.DistroEOL{
+		Namespace: namespace,
+		Date:      date,
+	}, nil
+}
+
+// mockIndexedStore is a store that indexes vulnerabilities by package, recording every lookup.
+type mockIndexedStore struct {
+	mockStore
+	lookups []string
+}
+
+func (d *mockIndexedStore) GetVulnerability(namespace, name string) ([]grypeDB.Vulnerability, error) {
+	d.lookups = append(d.lookups, namespace+" "+name)
+	return d.mockStore.GetVulnerability(namespace, name)
+}
+
+func (d *mockIndexedStore) HasPackageIndex() (bool, error) {
+	return true, nil
+}
+
+func (d *mockIndexedStore) GetVulnerabilityByPackageKey(key string, namespaces []string) ([]grypeDB.Vulnerability, error) {
+	d.lookups = append(d.lookups, key)
+
+	var vulns []grypeDB.Vulnerability
+	for _, namespace := range namespaces {
+		for name, namespaceVulns := range d.data[namespace] {
+			if grypeDB.PackageKey(namespace, name) == key {
+				vulns = append(vulns, namespaceVulns...)
+			}
+		}
+	}
+	return vulns, nil
+}
+
+// mockBatchStore is a store that retrieves the vulnerabilities of many packages at once, recording every query.
+type mockBatchStore struct {
+	mockStore
+	queries []string
+}
+
+func (d *mockBatchStore) GetVulnerability(namespace, name string) ([]grypeDB.Vulnerability, error) {
+	d.queries = append(d.queries, namespace+" "+name)
+	return d.mockStore.GetVulnerability(namespace, name)
+}
+
+func (d *mockBatchStore) GetVulnerabilitiesByName(namespace string, names []string) (map[string][]grypeDB.Vulnerability, error) {
+	d.queries = append(d.queries, namespace+" (batch)")
+	result := make(map[string][]grypeDB.Vulnerability)
+	for _, name := range names {
+		result[name] = d.data[namespace][name]
+	}
+	return result, nil
+}
@@ -2,14 +2,17 @@ package db
 
 import (
 	"testing"
+	"time"
 
 	"github.com/anchore/grype/grype/vulnerability"
 	"github.com/google/uuid"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/go-test/deep"
 
+	grypeDB "github.com/anchore/grype/grype/db/v3"
 	"github.com/anchore/grype/grype/distro"
 	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/grype/grype/version"
@@ -160,3 +163,264 @@ func TestGetByCPE(t *testing.T) {
 	}
 
 }
+
+func TestGetByLanguage_PackageIndex(t *testing.T) {
+	vuln := func(id, name, namespace string) grypeDB.Vulnerability {
+		return grypeDB.Vulnerability{
+			ID:                id,
+			PackageName:       name,
+			Namespace:         namespace,
+			VersionConstraint: "< 2.20.0",
+			VersionFormat:     "python",
+		}
+	}
+	data := map[string]map[string][]grypeDB.Vulnerability{
+		"github:python": {"requests": {vuln("GHSA-0001", "requests", "github:python")}},
+		"osv:pypi": {
+			"requests": {vuln("PYSEC-0001", "requests", "osv:pypi")},
+			"Requests": {vuln("PYSEC-0002", "Requests", "osv:pypi")},
+		},
+		"github:npm": {"requests": {vuln("GHSA-0002", "requests", "github:npm")}},
+	}
+	p := pkg.Package{
+		ID:   pkg.ID(uuid.NewString()),
+		Name: "requests",
+	}
+
+	indexed := &mockIndexedStore{mockStore: mockStore{data: data}}
+	actual, err := NewVulnerabilityProvider(indexed).GetByLanguage(syftPkg.Python, p)
+	require.NoError(t, err)
+	var ids []string
+	for _, v := range actual {
+		ids = append(ids, v.ID)
+	}
+	// the python namespaces share a single lookup, which finds every spelling of the (normalized) name
+	assert.ElementsMatch(t, []string{"GHSA-0001", "PYSEC-0001", "PYSEC-0002"}, ids)
+	assert.Equal(t, []string{"pypi/requests"}, indexed.lookups)
+
+	actual, err = NewVulnerabilityProvider(&mockStore{data: data}).GetByLanguage(syftPkg.Python, p)
+	require.NoError(t, err)
+	ids = nil
+	for _, v := range actual {
+		ids = append(ids, v.ID)
+	}
+	// searching each namespace only finds the exact name
+	assert.ElementsMatch(t, []string{"GHSA-0001", "PYSEC-0001"}, ids)
+}
+
+func TestWithPackages(t *testing.T) {
+	store := &mockBatchStore{mockStore: *newMockStore()}
+	d, err := distro.New(distro.Debian, "8", "")
+	require.NoError(t, err)
+
+	packages := []pkg.Package{
+		{ID: pkg.ID(uuid.NewString()), Name: "neutron-common", Upstreams: []pkg.UpstreamPackage{{Name: "neutron"}}},
+		{ID: pkg.ID(uuid.NewString()), Name: "bash"},
+	}
+	provider, err := NewVulnerabilityProvider(store).WithPackages(d, packages)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"debian:8 (batch)"}, store.queries)
+
+	for _, p := range append(packages, pkg.UpstreamPackages(packages[0])...) {
+		_, err := provider.GetByDistro(d, p)
+		require.NoError(t, err)
+	}
+	actual, err := provider.GetByDistro(d, pkg.UpstreamPackages(packages[0])[0])
+	require.NoError(t, err)
+	assert.Len(t, actual, 2)
+	// every lookup was answered by the batch
+	assert.Equal(t, []string{"debian:8 (batch)"}, store.queries)
+
+	// packages that were not part of the batch are still found
+	_, err = provider.GetByDistro(d, pkg.Package{ID: pkg.ID(uuid.NewString()), Name: "openssl"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"debian:8 (batch)", "debian:8 openssl"}, store.queries)
+}
+
+func TestWithPackages_PrefetchLimit(t *testing.T) {
+	store := &mockBatchStore{mockStore: *newMockStore()}
+	d, err := distro.New(distro.Debian, "8", "")
+	require.NoError(t, err)
+
+	packages := []pkg.Package{
+		{ID: pkg.ID(uuid.NewString()), Name: "neutron"},
+		{ID: pkg.ID(uuid.NewString()), Name: "bash"},
+	}
+	provider, err := NewVulnerabilityProvider(store).WithPrefetchLimit(1).WithPackages(d, packages)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"debian:8 (batch)"}, store.queries)
+
+	// only the first package was retrieved at once, the other is retrieved on demand
+	for _, p := range packages {
+		_, err := provider.GetByDistro(d, p)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"debian:8 (batch)", "debian:8 bash"}, store.queries)
+
+	// no vulnerabilities are retrieved at once without a limit
+	store.queries = nil
+	_, err = NewVulnerabilityProvider(store).WithPrefetchLimit(0).WithPackages(d, packages)
+	require.NoError(t, err)
+	assert.Empty(t, store.queries)
+}
+
+func TestWithCache(t *testing.T) {
+	store := &mockBatchStore{mockStore: *newMockStore()}
+	d, err := distro.New(distro.Debian, "8", "")
+	require.NoError(t, err)
+
+	provider := NewVulnerabilityProvider(store).WithCache(DefaultCacheSize)
+	p := pkg.Package{ID: pkg.ID(uuid.NewString()), Name: "neutron"}
+	for i := 0; i < 3; i++ {
+		actual, err := provider.GetByDistro(d, p)
+		require.NoError(t, err)
+		assert.Len(t, actual, 2)
+	}
+	assert.Equal(t, []string{"debian:8 neutron"}, store.queries)
+
+	// the cache is shared with the provider of a batch
+	batched, err := provider.WithPackages(d, nil)
+	require.NoError(t, err)
+	_, err = batched.GetByDistro(d, p)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"debian:8 neutron"}, store.queries)
+
+	// the cache can be disabled
+	provider = NewVulnerabilityProvider(store).WithCache(0)
+	for i := 0; i < 2; i++ {
+		_, err := provider.GetByDistro(d, p)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"debian:8 neutron", "debian:8 neutron", "debian:8 neutron"}, store.queries)
+}
+
+func TestGetByDistro_Aliases(t *testing.T) {
+	rhelData := map[string][]grypeDB.Vulnerability{
+		"openssl": {
+			{
+				PackageName:       "openssl",
+				Namespace:         "rhel:8",
+				VersionConstraint: "< 1:1.1.1k-5.el8_5",
+				ID:                "CVE-2021-3712",
+				VersionFormat:     "rpm",
+			},
+		},
+	}
+	rockyData := map[string][]grypeDB.Vulnerability{
+		"openssl": {
+			{
+				PackageName:       "openssl",
+				Namespace:         "rocky:8",
+				VersionConstraint: "< 1:1.1.1k-5.el8_5",
+				ID:                "RLSA-2021:4614",
+				VersionFormat:     "rpm",
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		store      grypeDB.VulnerabilityStoreReader
+		rules      []distro.AliasRule
+		expectedID string
+	}{
+		{
+			name: "aliased distro with its own data",
+			store: &mockNamespacedStore{mockStore{data: map[string]map[string][]grypeDB.Vulnerability{
+				"rhel:8":  rhelData,
+				"rocky:8": rockyData,
+			}}},
+			rules:      distro.DefaultAliasRules,
+			expectedID: "RLSA-2021:4614",
+		},
+		{
+			name: "aliased distro without its own data",
+			store: &mockNamespacedStore{mockStore{data: map[string]map[string][]grypeDB.Vulnerability{
+				"rhel:8": rhelData,
+			}}},
+			rules:      distro.DefaultAliasRules,
+			expectedID: "CVE-2021-3712",
+		},
+		{
+			name: "store without namespace information",
+			store: &mockStore{data: map[string]map[string][]grypeDB.Vulnerability{
+				"rhel:8": rhelData,
+			}},
+			rules:      distro.DefaultAliasRules,
+			expectedID: "CVE-2021-3712",
+		},
+		{
+			name: "no alias rules",
+			store: &mockNamespacedStore{mockStore{data: map[string]map[string][]grypeDB.Vulnerability{
+				"rhel:8": rhelData,
+			}}},
+		},
+	}
+
+	d, err := distro.New(distro.RockyLinux, "8.5")
+	require.NoError(t, err)
+
+	p := pkg.Package{
+		ID:   pkg.ID(uuid.NewString()),
+		Name: "openssl",
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			provider := NewVulnerabilityProviderWithAliases(test.store, test.rules)
+
+			actual, err := provider.GetByDistro(d, p)
+			require.NoError(t, err)
+
+			if test.expectedID == "" {
+				assert.Empty(t, actual)
+				return
+			}
+			require.Len(t, actual, 1)
+			assert.Equal(t, test.expectedID, actual[0].ID)
+		})
+	}
+}
+
+func TestGetDistroEOL(t *testing.T) {
+	eol := time.Date(2024, time.June, 30, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		store    grypeDB.VulnerabilityStoreReader
+		distro   distro.Type
+		version  string
+		expected *time.Time
+	}{
+		{
+			name:     "distro release with an end of life",
+			store:    &mockEOLStore{eols: map[string]time.Time{"rhel:7": eol}},
+			distro:   distro.CentOS,
+			version:  "7.9.2009",
+			expected: &eol,
+		},
+		{
+			name:    "distro release without an end of life",
+			store:   &mockEOLStore{eols: map[string]time.Time{"rhel:7": eol}},
+			distro:  distro.RedHat,
+			version: "8.5",
+		},
+		{
+			name:    "store without end of life information",
+			store:   newMockStore(),
+			distro:  distro.RedHat,
+			version: "7.9",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d, err := distro.New(test.distro, test.version)
+			require.NoError(t, err)
+
+			actual, err := NewVulnerabilityProvider(test.store).GetDistroEOL(d)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package dependencytrack
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal"
+	"github.com/anchore/grype/internal/version"
+	"github.com/anchore/syft/syft/source"
+	"github.com/google/uuid"
+)
+
+// source: https://cyclonedx.org/docs/1.4/json/
+
+// BOM is a CycloneDX 1.4 JSON document of the packages and the vulnerabilities of a scan result, where the analysis
+// of the vulnerabilities (VEX) describes the matches that are ignored.
+type BOM struct {
+	BOMFormat       string          `json:"bomFormat"`
+	SpecVersion     string          `json:"specVersion"`
+	SerialNumber    string          `json:"serialNumber"`
+	Version         int             `json:"version"`
+	Metadata        Metadata        `json:"metadata"`
+	Components      []Component     `json:"components"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"`
+}
+
+// Metadata describes the scan of the BOM.
+type Metadata struct {
+	Timestamp string     `json:"timestamp"`
+	Tools     []Tool     `json:"tools"`
+	Component *Component `json:"component,omitempty"`
+}
+
+// Tool is the tool that made the BOM.
+type Tool struct {
+	Vendor  string `json:"vendor"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Component is a package (or the scanned source, in the metadata).
+type Component struct {
+	BOMRef  string `json:"bom-ref,omitempty"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+	PURL    string `json:"purl,omitempty"`
+	CPE     string `json:"cpe,omitempty"`
+}
+
+// Vulnerability is a match of a vulnerability to a package.
+type Vulnerability struct {
+	BOMRef         string     `json:"bom-ref"`
+	ID             string     `json:"id"`
+	Source         *Source    `json:"source,omitempty"`
+	Ratings        []Rating   `json:"ratings,omitempty"`
+	Description    string     `json:"description,omitempty"`
+	Recommendation string     `json:"recommendation,omitempty"`
+	Advisories     []Advisory `json:"advisories,omitempty"`
+	Affects        []Affect   `json:"affects"`
+	Analysis       *Analysis  `json:"analysis,omitempty"`
+}
+
+// Source is where the vulnerability is published.
+type Source struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// Rating is the severity (and the CVSS score) of a vulnerability.
+type Rating struct {
+	Score    *float64 `json:"score,omitempty"`
+	Severity string   `json:"severity,omitempty"`
+	Method   string   `json:"method,omitempty"`
+	Vector   string   `json:"vector,omitempty"`
+}
+
+// Advisory is a link to an advisory of a vulnerability.
+type Advisory struct {
+	URL string `json:"url"`
+}
+
+// Affect is the reference of the component that is affected by a vulnerability.
+type Affect struct {
+	Ref string `json:"ref"`
+}
+
+// Analysis is the VEX analysis of a vulnerability of a component.
+type Analysis struct {
+	State  string `json:"state"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// NewBOM returns the BOM of the given scan result, where the ignored matches are analyzed as "not_affected" (with the
+// reasons of the applied ignore rules as the detail).
+func NewBOM(packages []pkg.Package, matches match.Matches, ignoredMatches []match.IgnoredMatch, src *source.Metadata, metadataProvider vulnerability.MetadataProvider) (BOM, error) {
+	bom := BOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.4",
+		SerialNumber: uuid.New().URN(),
+		Version:      1,
+		Metadata: Metadata{
+			Timestamp: time.Now().UTC().Format(time.RFC3339),
+			Tools: []Tool{{
+				Vendor:  "anchore",
+				Name:    internal.ApplicationName,
+				Version: version.FromBuild().Version,
+			}},
+			Component: sourceComponent(src),
+		},
+		Components: make([]Component, 0, len(packages)),
+	}
+
+	for _, p := range packages {
+		component := Component{
+			BOMRef:  string(p.ID),
+			Type:    "library",
+			Name:    p.Name,
+			Version: p.Version,
+			PURL:    p.PURL,
+		}
+		if len(p.CPEs) > 0 {
+			component.CPE = p.CPEs[0].BindToFmtString()
+		}
+		bom.Components = append(bom.Components, component)
+	}
+
+	for m := range matches.Enumerate() {
+		v, err := newVulnerability(m, metadataProvider)
+		if err != nil {
+			return BOM{}, err
+		}
+		bom.Vulnerabilities = append(bom.Vulnerabilities, v)
+	}
+
+	for _, m := range ignoredMatches {
+		v, err := newVulnerability(m.Match, metadataProvider)
+		if err != nil {
+			return BOM{}, err
+		}
+		var reasons []string
+		for _, rule := range m.AppliedIgnoreRules {
+			if rule.Reason != "" {
+				reasons = append(reasons, rule.Reason)
+			}
+		}
+		detail := "ignored by a grype ignore rule"
+		if len(reasons) > 0 {
+			detail = strings.Join(reasons, "; ")
+		}
+		v.Analysis = &Analysis{State: "not_affected", Detail: detail}
+		bom.Vulnerabilities = append(bom.Vulnerabilities, v)
+	}
+
+	return bom, nil
+}
+
+// HasAnalysis indicates whether any vulnerability of the BOM has a VEX analysis.
+func (b BOM) HasAnalysis() bool {
+	for _, v := range b.Vulnerabilities {
+		if v.Analysis != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func newVulnerability(m match.Match, metadataProvider vulnerability.MetadataProvider) (Vulnerability, error) {
+	metadata, err := metadataProvider.GetMetadata(m.Vulnerability.ID, m.Vulnerability.Namespace)
+	if err != nil {
+		return Vulnerability{}, fmt.Errorf("unable to fetch vuln=%q metadata: %+v", m.Vulnerability.ID, err)
+	}
+
+	v := Vulnerability{
+		BOMRef:  fmt.Sprintf("%s:%s", m.Vulnerability.ID, m.Package.ID),
+		ID:      m.Vulnerability.ID,
+		Affects: []Affect{{Ref: string(m.Package.ID)}},
+	}
+	if fixes := m.Vulnerability.Fix.Versions; len(fixes) > 0 {
+		v.Recommendation = fmt.Sprintf("Upgrade %s to %s", m.Package.Name, strings.Join(fixes, ", "))
+	}
+	if metadata == nil {
+		return v, nil
+	}
+
+	v.Source = &Source{Name: metadata.Namespace}
+	if len(metadata.URLs) > 0 {
+		v.Source.URL = metadata.URLs[0]
+	}
+	v.Description = metadata.Description
+	for _, u := range metadata.URLs {
+		v.Advisories = append(v.Advisories, Advisory{URL: u})
+	}
+
+	severity := ratingSeverity(metadata.Severity)
+	for _, cvss := range metadata.Cvss {
+		score := cvss.Metrics.BaseScore
+		v.Ratings = append(v.Ratings, Rating{
+			Score:    &score,
+			Severity: severity,
+			Method:   cvssMethod(cvss.Version),
+			Vector:   cvss.Vector,
+		})
+	}
+	if len(v.Ratings) == 0 {
+		v.Ratings = append(v.Ratings, Rating{Severity: severity})
+	}
+	return v, nil
+}
+
+// ratingSeverity returns the CycloneDX severity of the given grype severity.
+func ratingSeverity(severity string) string {
+	switch s := vulnerability.ParseSeverity(severity); s {
+	case vulnerability.NegligibleSeverity:
+		return "info"
+	case vulnerability.UnknownSeverity:
+		return "unknown"
+	default:
+		return strings.ToLower(s.String())
+	}
+}
+
+// cvssMethod returns the CycloneDX rating method of the given CVSS version (e.g. "CVSSv31" of "3.1").
+func cvssMethod(cvssVersion string) string {
+	v, err := strconv.ParseFloat(cvssVersion, 64)
+	switch {
+	case err != nil:
+		return "other"
+	case v >= 3.1:
+		return "CVSSv31"
+	case v >= 3:
+		return "CVSSv3"
+	case v >= 2:
+		return "CVSSv2"
+	default:
+		return "other"
+	}
+}
+
+// sourceComponent returns the component of the scanned source (if known).
+func sourceComponent(src *source.Metadata) *Component {
+	if src == nil {
+		return nil
+	}
+	switch src.Scheme {
+	case source.ImageScheme:
+		return &Component{
+			Type:    "container",
+			Name:    src.ImageMetadata.UserInput,
+			Version: src.ImageMetadata.ManifestDigest,
+		}
+	case source.DirectoryScheme, source.FileScheme:
+		return &Component{Type: "file", Name: src.Path}
+	default:
+		return nil
+	}
+}
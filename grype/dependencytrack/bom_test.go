@@ -0,0 +1,89 @@
+package dependencytrack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+func testResult() ([]pkg.Package, match.Matches, []match.IgnoredMatch) {
+	pkg1 := pkg.Package{ID: "package-1-id", Name: "package-1", Version: "1.0.1", Type: syftPkg.DebPkg, PURL: "pkg:deb/debian/package-1@1.0.1"}
+	pkg2 := pkg.Package{ID: "package-2-id", Name: "package-2", Version: "2.0.1", Type: syftPkg.DebPkg}
+
+	matches := match.NewMatches(match.Match{
+		Vulnerability: vulnerability.Vulnerability{
+			ID:        "CVE-1999-0001",
+			Namespace: "source-1",
+			Fix:       vulnerability.Fix{Versions: []string{"1.0.2"}},
+		},
+		Package: pkg1,
+	})
+	ignored := []match.IgnoredMatch{{
+		Match: match.Match{
+			Vulnerability: vulnerability.Vulnerability{ID: "CVE-1999-0002", Namespace: "source-2"},
+			Package:       pkg2,
+		},
+		AppliedIgnoreRules: []match.IgnoreRule{{Vulnerability: "CVE-1999-0002", Reason: "the vulnerable code is not reachable"}},
+	}}
+	return []pkg.Package{pkg1, pkg2}, matches, ignored
+}
+
+func TestNewBOM(t *testing.T) {
+	packages, matches, ignored := testResult()
+	src := &source.Metadata{Scheme: source.ImageScheme, ImageMetadata: source.ImageMetadata{UserInput: "alpine:3.14", ManifestDigest: "sha256:abc"}}
+
+	bom, err := NewBOM(packages, matches, ignored, src, models.NewMetadataMock())
+	require.NoError(t, err)
+
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Equal(t, "1.4", bom.SpecVersion)
+	assert.Equal(t, &Component{Type: "container", Name: "alpine:3.14", Version: "sha256:abc"}, bom.Metadata.Component)
+	assert.Equal(t, []Component{
+		{BOMRef: "package-1-id", Type: "library", Name: "package-1", Version: "1.0.1", PURL: "pkg:deb/debian/package-1@1.0.1"},
+		{BOMRef: "package-2-id", Type: "library", Name: "package-2", Version: "2.0.1"},
+	}, bom.Components)
+
+	require.Len(t, bom.Vulnerabilities, 2)
+	score := 4.0
+	assert.Equal(t, Vulnerability{
+		BOMRef:         "CVE-1999-0001:package-1-id",
+		ID:             "CVE-1999-0001",
+		Source:         &Source{},
+		Ratings:        []Rating{{Score: &score, Severity: "low", Method: "CVSSv3", Vector: "another vector"}},
+		Description:    "1999-01 description",
+		Recommendation: "Upgrade package-1 to 1.0.2",
+		Affects:        []Affect{{Ref: "package-1-id"}},
+	}, bom.Vulnerabilities[0])
+
+	analyzed := bom.Vulnerabilities[1]
+	assert.Equal(t, "CVE-1999-0002", analyzed.ID)
+	assert.Equal(t, "critical", analyzed.Ratings[0].Severity)
+	assert.Equal(t, "CVSSv2", analyzed.Ratings[0].Method)
+	assert.Equal(t, &Analysis{State: "not_affected", Detail: "the vulnerable code is not reachable"}, analyzed.Analysis)
+	assert.True(t, bom.HasAnalysis())
+}
+
+func TestRatingSeverity(t *testing.T) {
+	tests := map[string]string{
+		"Critical":   "critical",
+		"High":       "high",
+		"Medium":     "medium",
+		"Low":        "low",
+		"Negligible": "info",
+		"":           "unknown",
+		"whatever":   "unknown",
+	}
+	for severity, expected := range tests {
+		t.Run(severity, func(t *testing.T) {
+			assert.Equal(t, expected, ratingSeverity(severity))
+		})
+	}
+}
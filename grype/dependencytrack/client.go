@@ -0,0 +1,172 @@
+package dependencytrack
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Project identifies the Dependency-Track project of a BOM, by its UUID or else by its name and version.
+type Project struct {
+	UUID    string
+	Name    string
+	Version string
+	// AutoCreate creates the project (by name and version) when it does not exist
+	AutoCreate bool
+}
+
+func (p Project) String() string {
+	if p.UUID != "" {
+		return p.UUID
+	}
+	return fmt.Sprintf("%s@%s", p.Name, p.Version)
+}
+
+// Client uploads BOMs to the API of a Dependency-Track instance.
+type Client struct {
+	url    string
+	apiKey string
+	client *http.Client
+	// pollInterval is how often the processing of an uploaded BOM is checked (before the VEX is uploaded)
+	pollInterval time.Duration
+	// processingTimeout is how long the processing of an uploaded BOM is waited for
+	processingTimeout time.Duration
+}
+
+// NewClient is a *Client constructor, where the URL is of the API server (e.g. "https://dtrack.example.com").
+func NewClient(url, apiKey string, client *http.Client) *Client {
+	return &Client{
+		url:               strings.TrimSuffix(url, "/"),
+		apiKey:            apiKey,
+		client:            client,
+		pollInterval:      2 * time.Second,
+		processingTimeout: 5 * time.Minute,
+	}
+}
+
+type uploadRequest struct {
+	Project        string `json:"project,omitempty"`
+	ProjectName    string `json:"projectName,omitempty"`
+	ProjectVersion string `json:"projectVersion,omitempty"`
+	AutoCreate     bool   `json:"autoCreate,omitempty"`
+	BOM            string `json:"bom,omitempty"`
+	VEX            string `json:"vex,omitempty"`
+}
+
+type uploadResponse struct {
+	Token string `json:"token"`
+}
+
+type processingResponse struct {
+	Processing bool `json:"processing"`
+}
+
+// Publish uploads the BOM to the project, and then (once the BOM is processed) the analysis of its vulnerabilities as
+// a VEX, which is only uploaded when there is an analysis (i.e. when matches are ignored).
+func (c *Client) Publish(project Project, bom BOM) error {
+	document, err := json.Marshal(bom)
+	if err != nil {
+		return fmt.Errorf("unable to encode BOM: %w", err)
+	}
+
+	request := projectRequest(project)
+	request.AutoCreate = project.AutoCreate && project.UUID == ""
+	request.BOM = base64.StdEncoding.EncodeToString(document)
+	var uploaded uploadResponse
+	if err := c.put("/api/v1/bom", request, &uploaded); err != nil {
+		return fmt.Errorf("unable to upload BOM to project=%q: %w", project, err)
+	}
+
+	if !bom.HasAnalysis() {
+		return nil
+	}
+	// note: the analysis applies to the findings of the project, which exist once the BOM is processed
+	if err := c.waitForProcessing(uploaded.Token); err != nil {
+		return err
+	}
+	request = projectRequest(project)
+	request.VEX = base64.StdEncoding.EncodeToString(document)
+	if err := c.put("/api/v1/vex", request, nil); err != nil {
+		return fmt.Errorf("unable to upload VEX to project=%q: %w", project, err)
+	}
+	return nil
+}
+
+func projectRequest(project Project) uploadRequest {
+	if project.UUID != "" {
+		return uploadRequest{Project: project.UUID}
+	}
+	return uploadRequest{ProjectName: project.Name, ProjectVersion: project.Version}
+}
+
+// waitForProcessing waits until the BOM of the given upload token is processed.
+func (c *Client) waitForProcessing(token string) error {
+	if token == "" {
+		return nil
+	}
+	deadline := time.Now().Add(c.processingTimeout)
+	for {
+		var status processingResponse
+		if err := c.get("/api/v1/bom/token/"+token, &status); err != nil {
+			return fmt.Errorf("unable to check the processing of the BOM: %w", err)
+		}
+		if !status.Processing {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("the BOM was not processed within %s", c.processingTimeout)
+		}
+		time.Sleep(c.pollInterval)
+	}
+}
+
+func (c *Client) put(path string, body interface{}, result interface{}) error {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPut, c.url+path, bytes.NewReader(encoded))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	return c.do(request, result)
+}
+
+func (c *Client) get(path string, result interface{}) error {
+	request, err := http.NewRequest(http.MethodGet, c.url+path, nil)
+	if err != nil {
+		return err
+	}
+	return c.do(request, result)
+}
+
+func (c *Client) do(request *http.Request, result interface{}) error {
+	request.Header.Set("X-Api-Key", c.apiKey)
+	request.Header.Set("Accept", "application/json")
+	response, err := c.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %q: %s", response.Status, strings.TrimSpace(string(body)))
+	}
+	if result == nil || len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("unable to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package dependencytrack
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer is a Dependency-Track API server that processes a BOM after the given number of polls.
+type fakeServer struct {
+	lock     sync.Mutex
+	polls    int
+	requests []string
+	uploads  map[string]uploadRequest
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if r.Header.Get("X-Api-Key") != "key" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	switch r.URL.Path {
+	case "/api/v1/bom", "/api/v1/vex":
+		var upload uploadRequest
+		if err := json.NewDecoder(r.Body).Decode(&upload); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.uploads[r.URL.Path] = upload
+		_ = json.NewEncoder(w).Encode(uploadResponse{Token: "token"})
+	case "/api/v1/bom/token/token":
+		s.polls++
+		_ = json.NewEncoder(w).Encode(processingResponse{Processing: s.polls < 2})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newTestClient(t *testing.T, apiKey string) (*Client, *fakeServer) {
+	fake := &fakeServer{uploads: make(map[string]uploadRequest)}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client := NewClient(server.URL+"/", apiKey, server.Client())
+	client.pollInterval = time.Millisecond
+	return client, fake
+}
+
+func TestClient_Publish(t *testing.T) {
+	bom := BOM{BOMFormat: "CycloneDX", Vulnerabilities: []Vulnerability{{ID: "CVE-1999-0002", Analysis: &Analysis{State: "not_affected"}}}}
+
+	client, fake := newTestClient(t, "key")
+	require.NoError(t, client.Publish(Project{Name: "app", Version: "1.0", AutoCreate: true}, bom))
+
+	assert.Equal(t, []string{"PUT /api/v1/bom", "GET /api/v1/bom/token/token", "GET /api/v1/bom/token/token", "PUT /api/v1/vex"}, fake.requests)
+
+	upload := fake.uploads["/api/v1/bom"]
+	assert.Equal(t, "app", upload.ProjectName)
+	assert.Equal(t, "1.0", upload.ProjectVersion)
+	assert.True(t, upload.AutoCreate)
+	decoded, err := base64.StdEncoding.DecodeString(upload.BOM)
+	require.NoError(t, err)
+	var uploaded BOM
+	require.NoError(t, json.Unmarshal(decoded, &uploaded))
+	assert.Equal(t, bom, uploaded)
+
+	vex := fake.uploads["/api/v1/vex"]
+	assert.Equal(t, "app", vex.ProjectName)
+	assert.False(t, vex.AutoCreate)
+	assert.NotEmpty(t, vex.VEX)
+}
+
+func TestClient_Publish_WithoutAnalysis(t *testing.T) {
+	client, fake := newTestClient(t, "key")
+	require.NoError(t, client.Publish(Project{UUID: "b3f7c5c2-5d0e-4c32-9a51-4d0b6f5b0a71", AutoCreate: true}, BOM{BOMFormat: "CycloneDX"}))
+
+	assert.Equal(t, []string{"PUT /api/v1/bom"}, fake.requests)
+	upload := fake.uploads["/api/v1/bom"]
+	assert.Equal(t, "b3f7c5c2-5d0e-4c32-9a51-4d0b6f5b0a71", upload.Project)
+	// note: projects are only created by name and version
+	assert.False(t, upload.AutoCreate)
+}
+
+func TestClient_Publish_Unauthorized(t *testing.T) {
+	client, _ := newTestClient(t, "wrong")
+	err := client.Publish(Project{Name: "app", Version: "1.0"}, BOM{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+}
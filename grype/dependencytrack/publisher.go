@@ -0,0 +1,61 @@
+package dependencytrack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchore/grype/grype/publish"
+	"github.com/anchore/syft/syft/source"
+)
+
+var _ publish.Publisher = (*Publisher)(nil)
+
+// Publisher publishes results to a Dependency-Track project.
+type Publisher struct {
+	client  *Client
+	project Project
+}
+
+// NewPublisher is a *Publisher constructor, where the results of images are published to the projects named after the
+// images when the project has neither a UUID nor a name (see ImageProject).
+func NewPublisher(client *Client, project Project) *Publisher {
+	return &Publisher{
+		client:  client,
+		project: project,
+	}
+}
+
+func (p *Publisher) Publish(result publish.Result) error {
+	project := p.project
+	if project.UUID == "" && project.Name == "" {
+		var ok bool
+		if project.Name, project.Version, ok = ImageProject(result.Context.Source); !ok {
+			return fmt.Errorf("publishing the scan result of target=%q to Dependency-Track requires a project (a UUID, or a name and version)", result.Target)
+		}
+	}
+
+	bom, err := NewBOM(result.Packages, result.Matches, result.IgnoredMatches, result.Context.Source, result.MetadataProvider)
+	if err != nil {
+		return fmt.Errorf("unable to describe the scan result of target=%q for Dependency-Track: %w", result.Target, err)
+	}
+	if err := p.client.Publish(project, bom); err != nil {
+		return fmt.Errorf("unable to publish the scan result of target=%q to Dependency-Track: %w", result.Target, err)
+	}
+	return nil
+}
+
+// ImageProject returns the Dependency-Track project of the scanned image, which is named after the repository of the
+// image and versioned by its tag (or digest), e.g. "alpine" and "3.14" of "alpine:3.14".
+func ImageProject(src *source.Metadata) (name, version string, ok bool) {
+	if src == nil || src.Scheme != source.ImageScheme || src.ImageMetadata.UserInput == "" {
+		return "", "", false
+	}
+	ref := src.ImageMetadata.UserInput
+	if i := strings.LastIndex(ref, "@"); i >= 0 {
+		return ref[:i], ref[i+1:], true
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i], ref[i+1:], true
+	}
+	return ref, "latest", true
+}
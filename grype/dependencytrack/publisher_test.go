@@ -0,0 +1,91 @@
+package dependencytrack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/publish"
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestPublisher_Publish(t *testing.T) {
+	packages, matches, ignored := testResult()
+	result := publish.Result{
+		Target:         "ghcr.io/org/app:1.2.0",
+		Matches:        matches,
+		IgnoredMatches: ignored,
+		Packages:       packages,
+		Context: pkg.Context{
+			Source: &source.Metadata{Scheme: source.ImageScheme, ImageMetadata: source.ImageMetadata{UserInput: "ghcr.io/org/app:1.2.0"}},
+		},
+		MetadataProvider: models.NewMetadataMock(),
+	}
+
+	client, fake := newTestClient(t, "key")
+	require.NoError(t, NewPublisher(client, Project{AutoCreate: true}).Publish(result))
+	upload := fake.uploads["/api/v1/bom"]
+	assert.Equal(t, "ghcr.io/org/app", upload.ProjectName)
+	assert.Equal(t, "1.2.0", upload.ProjectVersion)
+	assert.True(t, upload.AutoCreate)
+	// the ignored match is analyzed
+	assert.Contains(t, fake.uploads, "/api/v1/vex")
+
+	client, fake = newTestClient(t, "key")
+	require.NoError(t, NewPublisher(client, Project{Name: "app", Version: "main"}).Publish(result))
+	assert.Equal(t, "app", fake.uploads["/api/v1/bom"].ProjectName)
+
+	result.Context.Source = &source.Metadata{Scheme: source.DirectoryScheme, Path: "./src"}
+	assert.Error(t, NewPublisher(client, Project{}).Publish(result))
+}
+
+func TestImageProject(t *testing.T) {
+	tests := []struct {
+		name            string
+		src             *source.Metadata
+		expectedName    string
+		expectedVersion string
+		expectedOK      bool
+	}{
+		{
+			name:            "tag",
+			src:             &source.Metadata{Scheme: source.ImageScheme, ImageMetadata: source.ImageMetadata{UserInput: "alpine:3.14"}},
+			expectedName:    "alpine",
+			expectedVersion: "3.14",
+			expectedOK:      true,
+		},
+		{
+			name:            "registry with port",
+			src:             &source.Metadata{Scheme: source.ImageScheme, ImageMetadata: source.ImageMetadata{UserInput: "localhost:5000/org/app"}},
+			expectedName:    "localhost:5000/org/app",
+			expectedVersion: "latest",
+			expectedOK:      true,
+		},
+		{
+			name:            "digest",
+			src:             &source.Metadata{Scheme: source.ImageScheme, ImageMetadata: source.ImageMetadata{UserInput: "ghcr.io/org/app@sha256:abc"}},
+			expectedName:    "ghcr.io/org/app",
+			expectedVersion: "sha256:abc",
+			expectedOK:      true,
+		},
+		{
+			name: "directory",
+			src:  &source.Metadata{Scheme: source.DirectoryScheme, Path: "./src"},
+		},
+		{
+			name: "unknown source",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			name, version, ok := ImageProject(test.src)
+			assert.Equal(t, test.expectedName, name)
+			assert.Equal(t, test.expectedVersion, version)
+			assert.Equal(t, test.expectedOK, ok)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// Finding is a vulnerability of a package within a report.
+type Finding struct {
+	Vulnerability string   `json:"vulnerability"`
+	Severity      string   `json:"severity"`
+	Package       string   `json:"package"`
+	Version       string   `json:"version"`
+	Type          string   `json:"type"`
+	FixedIn       []string `json:"fixedIn"`
+}
+
+// Diff describes how the findings of a report changed compared to an earlier report.
+type Diff struct {
+	// New are the findings that are only within the new report
+	New []Finding `json:"new"`
+	// Fixed are the findings that are only within the old report
+	Fixed []Finding `json:"fixed"`
+	// Persisting are the findings that are within both reports (as described by the new report)
+	Persisting []Finding `json:"persisting"`
+}
+
+// findingKey identifies a finding across reports. The version of the package is not part of the key, since a package
+// that was updated to a version that is still vulnerable is the same finding (rather than a fixed and a new finding).
+type findingKey struct {
+	vulnerability string
+	pkg           string
+	pkgType       string
+}
+
+// ReadReport reads the matches of a JSON report (see the json output format).
+func ReadReport(reader io.Reader) ([]models.Match, error) {
+	var doc models.Document
+	if err := json.NewDecoder(reader).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("unable to read report: %w", err)
+	}
+	return doc.Matches, nil
+}
+
+// Compare returns how the findings of the new matches changed compared to the old matches.
+func Compare(oldMatches, newMatches []models.Match) Diff {
+	oldFindings := findingsByKey(oldMatches)
+	newFindings := findingsByKey(newMatches)
+
+	diff := Diff{
+		New:        make([]Finding, 0),
+		Fixed:      make([]Finding, 0),
+		Persisting: make([]Finding, 0),
+	}
+	for key, f := range newFindings {
+		if _, ok := oldFindings[key]; ok {
+			diff.Persisting = append(diff.Persisting, f)
+		} else {
+			diff.New = append(diff.New, f)
+		}
+	}
+	for key, f := range oldFindings {
+		if _, ok := newFindings[key]; !ok {
+			diff.Fixed = append(diff.Fixed, f)
+		}
+	}
+
+	sortFindings(diff.New)
+	sortFindings(diff.Fixed)
+	sortFindings(diff.Persisting)
+	return diff
+}
+
+// NewAtOrAbove returns the new findings with a severity at or above the given severity.
+func (d Diff) NewAtOrAbove(severity vulnerability.Severity) []Finding {
+	var findings []Finding
+	for _, f := range d.New {
+		if vulnerability.ParseSeverity(f.Severity) >= severity {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+func findingsByKey(matches []models.Match) map[findingKey]Finding {
+	findings := make(map[findingKey]Finding)
+	for _, m := range matches {
+		key := findingKey{
+			vulnerability: m.Vulnerability.ID,
+			pkg:           m.Artifact.Name,
+			pkgType:       string(m.Artifact.Type),
+		}
+		// note: the same finding may be reported for several locations of a package, which are the same finding here
+		if _, ok := findings[key]; ok {
+			continue
+		}
+		findings[key] = Finding{
+			Vulnerability: m.Vulnerability.ID,
+			Severity:      m.Vulnerability.Severity,
+			Package:       m.Artifact.Name,
+			Version:       m.Artifact.Version,
+			Type:          string(m.Artifact.Type),
+			FixedIn:       m.Vulnerability.Fix.Versions,
+		}
+	}
+	return findings
+}
+
+func sortFindings(findings []Finding) {
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Package != findings[j].Package {
+			return findings[i].Package < findings[j].Package
+		}
+		if findings[i].Type != findings[j].Type {
+			return findings[i].Type < findings[j].Type
+		}
+		return findings[i].Vulnerability < findings[j].Vulnerability
+	})
+}
@@ -0,0 +1,119 @@
+package diff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMatch(id, severity, name, version string, fixedIn ...string) models.Match {
+	return models.Match{
+		Vulnerability: models.Vulnerability{
+			VulnerabilityMetadata: models.VulnerabilityMetadata{
+				ID:       id,
+				Severity: severity,
+			},
+			Fix: models.Fix{Versions: fixedIn},
+		},
+		Artifact: models.Package{
+			Name:    name,
+			Version: version,
+			Type:    syftPkg.ApkPkg,
+		},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name       string
+		old        []models.Match
+		new        []models.Match
+		introduced []string
+		fixed      []string
+		persisting []string
+	}{
+		{
+			name: "no reports",
+		},
+		{
+			name:       "new and fixed",
+			old:        []models.Match{newMatch("CVE-1", "High", "musl", "1.0", "1.1")},
+			new:        []models.Match{newMatch("CVE-2", "Low", "zlib", "1.2")},
+			introduced: []string{"CVE-2 zlib 1.2"},
+			fixed:      []string{"CVE-1 musl 1.0"},
+		},
+		{
+			name: "updated package that is still vulnerable persists (as the new version)",
+			old: []models.Match{
+				newMatch("CVE-1", "High", "musl", "1.0", "1.3"),
+				newMatch("CVE-2", "High", "musl", "1.0", "1.1"),
+			},
+			new:        []models.Match{newMatch("CVE-1", "High", "musl", "1.2", "1.3")},
+			fixed:      []string{"CVE-2 musl 1.0"},
+			persisting: []string{"CVE-1 musl 1.2"},
+		},
+		{
+			name: "same finding for several locations",
+			old:  []models.Match{newMatch("CVE-1", "High", "musl", "1.0")},
+			new: []models.Match{
+				newMatch("CVE-1", "High", "musl", "1.0"),
+				newMatch("CVE-1", "High", "musl", "1.0"),
+			},
+			persisting: []string{"CVE-1 musl 1.0"},
+		},
+		{
+			name: "sorted by package and vulnerability",
+			new: []models.Match{
+				newMatch("CVE-2", "High", "zlib", "1.0"),
+				newMatch("CVE-3", "High", "musl", "1.0"),
+				newMatch("CVE-1", "High", "zlib", "1.0"),
+			},
+			introduced: []string{"CVE-3 musl 1.0", "CVE-1 zlib 1.0", "CVE-2 zlib 1.0"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := Compare(test.old, test.new)
+			assert.Equal(t, test.introduced, describe(d.New), "new")
+			assert.Equal(t, test.fixed, describe(d.Fixed), "fixed")
+			assert.Equal(t, test.persisting, describe(d.Persisting), "persisting")
+		})
+	}
+}
+
+func TestDiff_NewAtOrAbove(t *testing.T) {
+	d := Compare(nil, []models.Match{
+		newMatch("CVE-1", "Low", "musl", "1.0"),
+		newMatch("CVE-2", "Critical", "musl", "1.0"),
+		newMatch("CVE-3", "High", "musl", "1.0"),
+	})
+
+	assert.Len(t, d.NewAtOrAbove(vulnerability.UnknownSeverity), 3)
+	assert.Equal(t, []string{"CVE-2 musl 1.0", "CVE-3 musl 1.0"}, describe(d.NewAtOrAbove(vulnerability.HighSeverity)))
+	assert.Empty(t, d.NewAtOrAbove(vulnerability.CriticalSeverity+1))
+}
+
+func TestReadReport(t *testing.T) {
+	matches, err := ReadReport(strings.NewReader(`{"matches": [{"vulnerability": {"id": "CVE-1", "severity": "High"}, "artifact": {"name": "musl", "version": "1.0", "type": "apk"}}]}`))
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "CVE-1", matches[0].Vulnerability.ID)
+	assert.Equal(t, "musl", matches[0].Artifact.Name)
+
+	_, err = ReadReport(strings.NewReader("not a report"))
+	assert.Error(t, err)
+}
+
+func describe(findings []Finding) []string {
+	var result []string
+	for _, f := range findings {
+		result = append(result, f.Vulnerability+" "+f.Package+" "+f.Version)
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package distro
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AliasRule declares that the vulnerability data for one distro (the target) also describes another distro (the
+// alias), which is the case for distros that are rebuilt from the sources of another distro (e.g. Rocky Linux and
+// AlmaLinux are rebuilt from RHEL sources, and Chainguard images are built from Wolfi packages). The target is only
+// used when there is no vulnerability data for the aliased distro itself.
+type AliasRule struct {
+	Alias  Type
+	Target Type
+}
+
+// DefaultAliasRules are the alias rules used unless otherwise configured.
+var DefaultAliasRules = []AliasRule{
+	{Alias: RockyLinux, Target: RedHat},
+	{Alias: AlmaLinux, Target: RedHat},
+	{Alias: Chainguard, Target: Wolfi},
+}
+
+// ParseAliasRule parses an alias rule of the form "<alias>=<target>", where each side is either a distro type or an
+// os-release ID (e.g. "rocky=rhel").
+func ParseAliasRule(value string) (AliasRule, error) {
+	fields := strings.SplitN(value, "=", 2)
+	if len(fields) != 2 {
+		return AliasRule{}, fmt.Errorf("distro alias rule %q must be of the form <alias>=<target>", value)
+	}
+
+	alias, err := parseType(fields[0])
+	if err != nil {
+		return AliasRule{}, err
+	}
+
+	target, err := parseType(fields[1])
+	if err != nil {
+		return AliasRule{}, err
+	}
+
+	return AliasRule{Alias: alias, Target: target}, nil
+}
+
+func (r AliasRule) String() string {
+	return fmt.Sprintf("%s=%s", r.Alias, r.Target)
+}
+
+func parseType(value string) (Type, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if t, ok := IDMapping[value]; ok {
+		return t, nil
+	}
+	for _, t := range All {
+		if string(t) == value {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown distro: %q", value)
+}
+
+// AliasTarget returns the distro (of the same version) whose vulnerability data describes the given distro according
+// to the given alias rules, or nil if the distro is not an alias.
+func (d Distro) AliasTarget(rules []AliasRule) *Distro {
+	for _, rule := range rules {
+		if rule.Alias != d.Type {
+			continue
+		}
+		return &Distro{
+			Type:       rule.Target,
+			Version:    d.Version,
+			RawVersion: d.RawVersion,
+			IDLike:     d.IDLike,
+		}
+	}
+	return nil
+}
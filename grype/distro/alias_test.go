@@ -0,0 +1,65 @@
+package distro
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAliasRule(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected AliasRule
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			value:    "rocky=rhel",
+			expected: AliasRule{Alias: RockyLinux, Target: RedHat},
+		},
+		{
+			value:    "almalinux = redhat",
+			expected: AliasRule{Alias: AlmaLinux, Target: RedHat},
+		},
+		{
+			value:   "rocky",
+			wantErr: require.Error,
+		},
+		{
+			value:   "rocky=unknown",
+			wantErr: require.Error,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if test.wantErr == nil {
+				test.wantErr = require.NoError
+			}
+			actual, err := ParseAliasRule(test.value)
+			test.wantErr(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestDistro_AliasTarget(t *testing.T) {
+	rocky, err := New(RockyLinux, "8.5")
+	require.NoError(t, err)
+
+	target := rocky.AliasTarget(DefaultAliasRules)
+	require.NotNil(t, target)
+	assert.Equal(t, RedHat, target.Type)
+	assert.Equal(t, "8.5", target.RawVersion)
+
+	chainguard, err := New(Chainguard, "")
+	require.NoError(t, err)
+
+	target = chainguard.AliasTarget(DefaultAliasRules)
+	require.NotNil(t, target)
+	assert.Equal(t, Wolfi, target.Type)
+
+	debian, err := New(Debian, "11")
+	require.NoError(t, err)
+	assert.Nil(t, debian.AliasTarget(DefaultAliasRules))
+}
@@ -106,6 +106,11 @@ func Test_NewDistroFromRelease_Coverage(t *testing.T) {
 			Type:    AmazonLinux,
 			Version: "2.0.0",
 		},
+		{
+			fixture: "test-fixtures/os/amazon2023",
+			Type:    AmazonLinux,
+			Version: "2023.0.0",
+		},
 		{
 			fixture: "test-fixtures/os/busybox",
 			Type:    Busybox,
@@ -156,6 +161,26 @@ func Test_NewDistroFromRelease_Coverage(t *testing.T) {
 			Type:    SLES,
 			Version: "15.2.0",
 		},
+		{
+			fixture: "test-fixtures/os/sles-sap",
+			Type:    SLES,
+			Version: "15.3.0",
+		},
+		{
+			fixture: "test-fixtures/os/opensuse-tumbleweed",
+			Type:    OpenSuseTumbleweed,
+			Version: "20211210.0.0",
+		},
+		{
+			fixture: "test-fixtures/os/wolfi",
+			Type:    Wolfi,
+			Version: "20230201.0.0",
+		},
+		{
+			fixture: "test-fixtures/os/chainguard",
+			Type:    Chainguard,
+			Version: "20230214.0.0",
+		},
 		{
 			fixture: "test-fixtures/os/photon",
 			Type:    Photon,
@@ -190,6 +215,16 @@ func Test_NewDistroFromRelease_Coverage(t *testing.T) {
 			Type:    Mariner,
 			Version: "1.0.0",
 		},
+		{
+			fixture: "test-fixtures/os/mariner2",
+			Type:    Mariner,
+			Version: "2.0.0",
+		},
+		{
+			fixture: "test-fixtures/os/azurelinux",
+			Type:    AzureLinux,
+			Version: "3.0.0",
+		},
 		{
 			fixture: "test-fixtures/os/rockylinux",
 			Type:    RockyLinux,
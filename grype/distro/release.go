@@ -0,0 +1,61 @@
+package distro
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anchore/syft/syft/linux"
+)
+
+// ParseRelease parses a distro of the form "<name>:<version>" (e.g. "rhel:8" or "alpine:3.15"), where the name is
+// either an os-release ID or a distro type, into the linux release that would have been detected for the distro.
+func ParseRelease(value string) (*linux.Release, error) {
+	fields := strings.SplitN(value, ":", 2)
+	name := strings.ToLower(strings.TrimSpace(fields[0]))
+	if name == "" {
+		return nil, fmt.Errorf("distro %q must be of the form <name>:<version>", value)
+	}
+
+	t, err := parseType(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var version string
+	if len(fields) == 2 {
+		version = strings.TrimSpace(fields[1])
+	}
+
+	// validate the version in the same way as a detected distro
+	if _, err := New(t, version); err != nil {
+		return nil, fmt.Errorf("bad distro %q: %w", value, err)
+	}
+
+	id := name
+	if _, ok := IDMapping[id]; !ok {
+		id = releaseID(t)
+	}
+
+	return &linux.Release{
+		Name:      id,
+		ID:        id,
+		VersionID: version,
+		Version:   version,
+	}, nil
+}
+
+// releaseID returns the os-release ID for the given distro type (the first in lexical order when there are several).
+func releaseID(t Type) string {
+	var ids []string
+	for id, mapped := range IDMapping {
+		if mapped == t {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return string(t)
+	}
+	sort.Strings(ids)
+	return ids[0]
+}
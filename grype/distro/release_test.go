@@ -0,0 +1,71 @@
+package distro
+
+import (
+	"testing"
+
+	"github.com/anchore/syft/syft/linux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRelease(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected *linux.Release
+		distro   Type
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			value:    "rhel:8",
+			expected: &linux.Release{Name: "rhel", ID: "rhel", VersionID: "8", Version: "8"},
+			distro:   RedHat,
+		},
+		{
+			value:    "Alpine:3.15",
+			expected: &linux.Release{Name: "alpine", ID: "alpine", VersionID: "3.15", Version: "3.15"},
+			distro:   Alpine,
+		},
+		{
+			// distro types are mapped to an os-release ID
+			value:    "amazonlinux:2",
+			expected: &linux.Release{Name: "amzn", ID: "amzn", VersionID: "2", Version: "2"},
+			distro:   AmazonLinux,
+		},
+		{
+			value:    "wolfi",
+			expected: &linux.Release{Name: "wolfi", ID: "wolfi"},
+			distro:   Wolfi,
+		},
+		{
+			value:   "unknown:1",
+			wantErr: require.Error,
+		},
+		{
+			value:   "rhel:eight",
+			wantErr: require.Error,
+		},
+		{
+			value:   ":8",
+			wantErr: require.Error,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if test.wantErr == nil {
+				test.wantErr = require.NoError
+			}
+
+			actual, err := ParseRelease(test.value)
+			test.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, test.expected, actual)
+
+			d, err := NewFromRelease(*actual)
+			require.NoError(t, err)
+			assert.Equal(t, test.distro, d.Type)
+		})
+	}
+}
@@ -21,12 +21,17 @@ const (
 	OracleLinux  Type = "oraclelinux"
 	ArchLinux    Type = "archlinux"
 	OpenSuseLeap Type = "opensuseleap"
-	SLES         Type = "sles"
-	Photon       Type = "photon"
-	Windows      Type = "windows"
-	Mariner      Type = "mariner"
-	RockyLinux   Type = "rockylinux"
-	AlmaLinux    Type = "almalinux"
+	// OpenSuseTumbleweed is the rolling release of openSUSE
+	OpenSuseTumbleweed Type = "opensusetumbleweed"
+	SLES               Type = "sles"
+	Photon             Type = "photon"
+	Windows            Type = "windows"
+	Mariner            Type = "mariner"
+	AzureLinux         Type = "azurelinux" // the successor of CBL-Mariner (from release 3.0)
+	RockyLinux         Type = "rockylinux"
+	AlmaLinux          Type = "almalinux"
+	Wolfi              Type = "wolfi"
+	Chainguard         Type = "chainguard"
 )
 
 // All contains all Linux distribution options
@@ -42,33 +47,45 @@ var All = []Type{
 	OracleLinux,
 	ArchLinux,
 	OpenSuseLeap,
+	OpenSuseTumbleweed,
 	SLES,
 	Photon,
 	Windows,
 	Mariner,
+	AzureLinux,
 	RockyLinux,
 	AlmaLinux,
+	Wolfi,
+	Chainguard,
 }
 
 // IDMapping connects a distro ID like "ubuntu" to a Distro type
 var IDMapping = map[string]Type{
-	"debian":        Debian,
-	"ubuntu":        Ubuntu,
-	"rhel":          RedHat,
-	"centos":        CentOS,
-	"fedora":        Fedora,
-	"alpine":        Alpine,
-	"busybox":       Busybox,
-	"amzn":          AmazonLinux,
-	"ol":            OracleLinux,
-	"arch":          ArchLinux,
-	"opensuse-leap": OpenSuseLeap,
-	"sles":          SLES,
-	"photon":        Photon,
-	"windows":       Windows,
-	"mariner":       Mariner,
-	"rocky":         RockyLinux,
-	"almalinux":     AlmaLinux,
+	"debian":              Debian,
+	"ubuntu":              Ubuntu,
+	"rhel":                RedHat,
+	"centos":              CentOS,
+	"fedora":              Fedora,
+	"alpine":              Alpine,
+	"busybox":             Busybox,
+	"amzn":                AmazonLinux,
+	"ol":                  OracleLinux,
+	"arch":                ArchLinux,
+	"opensuse-leap":       OpenSuseLeap,
+	"opensuse":            OpenSuseLeap, // openSUSE releases before leap 15 (e.g. 42.3)
+	"opensuse-tumbleweed": OpenSuseTumbleweed,
+	"sles":                SLES,
+	"sles_sap":            SLES, // SLES for SAP applications (which shares the packages and advisories of SLES)
+	"sled":                SLES, // SUSE Linux Enterprise Desktop (which shares the packages and advisories of SLES)
+	"suse":                SLES, // other SUSE derivatives only identify as SUSE (by ID_LIKE)
+	"photon":              Photon,
+	"windows":             Windows,
+	"mariner":             Mariner,
+	"azurelinux":          AzureLinux,
+	"rocky":               RockyLinux,
+	"almalinux":           AlmaLinux,
+	"wolfi":               Wolfi,
+	"chainguard":          Chainguard,
 }
 
 func TypeFromRelease(release linux.Release) Type {
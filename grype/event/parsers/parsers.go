@@ -2,7 +2,9 @@ package parsers
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/anchore/grype/grype/distro"
 	"github.com/anchore/grype/grype/matcher"
 	"github.com/anchore/grype/grype/presenter"
 	"github.com/wagoodman/go-progress"
@@ -49,6 +51,24 @@ func ParseAppUpdateAvailable(e partybus.Event) (string, error) {
 	return newVersion, nil
 }
 
+func ParseDistroEOLReached(e partybus.Event) (*distro.Distro, time.Time, error) {
+	if err := checkEventType(e.Type, event.DistroEOLReached); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	d, ok := e.Source.(*distro.Distro)
+	if !ok {
+		return nil, time.Time{}, newPayloadErr(e.Type, "Source", e.Source)
+	}
+
+	eol, ok := e.Value.(time.Time)
+	if !ok {
+		return nil, time.Time{}, newPayloadErr(e.Type, "Value", e.Value)
+	}
+
+	return d, eol, nil
+}
+
 func ParseUpdateVulnerabilityDatabase(e partybus.Event) (progress.StagedProgressable, error) {
 	if err := checkEventType(e.Type, event.UpdateVulnerabilityDatabase); err != nil {
 		return nil, err
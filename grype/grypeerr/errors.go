@@ -3,4 +3,16 @@ package grypeerr
 var (
 	// ErrAboveSeverityThreshold indicates when a vulnerability severity is discovered that is above the given --fail-on severity value
 	ErrAboveSeverityThreshold = NewExpectedErr("discovered vulnerabilities at or above the severity threshold")
+
+	// ErrFixableVulnerabilities indicates when a vulnerability with a fix is discovered (and --fail-on-fixable is given)
+	ErrFixableVulnerabilities = NewExpectedErr("discovered vulnerabilities with a fix")
+
+	// ErrDistroEOL indicates when the distro of the scanned source has reached the end of life (and --fail-on-eol is given)
+	ErrDistroEOL = NewExpectedErr("the distro has reached the end of life and vulnerability data is no longer published for it")
+
+	// ErrNewVulnerabilities indicates when a diff of two scans discovered newly introduced vulnerabilities (and --fail-on-new is given)
+	ErrNewVulnerabilities = NewExpectedErr("discovered newly introduced vulnerabilities")
+
+	// ErrPolicyDenied indicates when the policy given by --policy denied the scan result
+	ErrPolicyDenied = NewExpectedErr("the policy denied the scan result")
 )
@@ -0,0 +1,79 @@
+package history
+
+import (
+	"time"
+
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// Entry is the summary of a scan within the history.
+type Entry struct {
+	ID uint `json:"id"`
+	// Time is when the scan was done
+	Time time.Time `json:"time"`
+	// Target is the scanned input (as given by the user, e.g. "alpine:latest")
+	Target string `json:"target"`
+	// Digest identifies the content of the target at the time of the scan (the manifest digest of an image, when known)
+	Digest string `json:"digest,omitempty"`
+	// DBBuilt and DBSchemaVersion describe the vulnerability database the target was scanned with
+	DBBuilt         time.Time `json:"dbBuilt"`
+	DBSchemaVersion int       `json:"dbSchemaVersion"`
+	Counts          Counts    `json:"counts"`
+}
+
+// Counts are the number of vulnerabilities found by severity.
+type Counts struct {
+	Critical   int `json:"critical"`
+	High       int `json:"high"`
+	Medium     int `json:"medium"`
+	Low        int `json:"low"`
+	Negligible int `json:"negligible"`
+	Unknown    int `json:"unknown"`
+}
+
+// Add counts a vulnerability of the given severity.
+func (c *Counts) Add(severity vulnerability.Severity) {
+	switch severity {
+	case vulnerability.CriticalSeverity:
+		c.Critical++
+	case vulnerability.HighSeverity:
+		c.High++
+	case vulnerability.MediumSeverity:
+		c.Medium++
+	case vulnerability.LowSeverity:
+		c.Low++
+	case vulnerability.NegligibleSeverity:
+		c.Negligible++
+	default:
+		c.Unknown++
+	}
+}
+
+// Total is the number of vulnerabilities of any severity.
+func (c Counts) Total() int {
+	return c.Critical + c.High + c.Medium + c.Low + c.Negligible + c.Unknown
+}
+
+// Sub returns the difference of the counts to the given counts.
+func (c Counts) Sub(other Counts) Counts {
+	return Counts{
+		Critical:   c.Critical - other.Critical,
+		High:       c.High - other.High,
+		Medium:     c.Medium - other.Medium,
+		Low:        c.Low - other.Low,
+		Negligible: c.Negligible - other.Negligible,
+		Unknown:    c.Unknown - other.Unknown,
+	}
+}
+
+// Plus returns the sum of the counts and the given counts.
+func (c Counts) Plus(other Counts) Counts {
+	return Counts{
+		Critical:   c.Critical + other.Critical,
+		High:       c.High + other.High,
+		Medium:     c.Medium + other.Medium,
+		Low:        c.Low + other.Low,
+		Negligible: c.Negligible + other.Negligible,
+		Unknown:    c.Unknown + other.Unknown,
+	}
+}
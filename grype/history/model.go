@@ -0,0 +1,75 @@
+package history
+
+import (
+	"fmt"
+	"time"
+)
+
+const entryTableName = "scans"
+
+// entryModel is a struct used to serialize history entries into a sqlite3 DB.
+type entryModel struct {
+	ID              uint   `gorm:"primary_key; column:id"`
+	Time            string `gorm:"column:time; index:scans_target_time_idx"`
+	Target          string `gorm:"column:target; index:scans_target_time_idx"`
+	Digest          string `gorm:"column:digest"`
+	DBBuilt         string `gorm:"column:db_built"`
+	DBSchemaVersion int    `gorm:"column:db_schema_version"`
+	Critical        int    `gorm:"column:critical"`
+	High            int    `gorm:"column:high"`
+	Medium          int    `gorm:"column:medium"`
+	Low             int    `gorm:"column:low"`
+	Negligible      int    `gorm:"column:negligible"`
+	Unknown         int    `gorm:"column:unknown"`
+}
+
+func newEntryModel(entry Entry) entryModel {
+	return entryModel{
+		ID:              entry.ID,
+		Time:            entry.Time.UTC().Format(time.RFC3339Nano),
+		Target:          entry.Target,
+		Digest:          entry.Digest,
+		DBBuilt:         entry.DBBuilt.UTC().Format(time.RFC3339),
+		DBSchemaVersion: entry.DBSchemaVersion,
+		Critical:        entry.Counts.Critical,
+		High:            entry.Counts.High,
+		Medium:          entry.Counts.Medium,
+		Low:             entry.Counts.Low,
+		Negligible:      entry.Counts.Negligible,
+		Unknown:         entry.Counts.Unknown,
+	}
+}
+
+// TableName returns the table which all history entries are stored into.
+func (entryModel) TableName() string {
+	return entryTableName
+}
+
+// Inflate generates a history entry from the serialized model instance.
+func (m entryModel) Inflate() (Entry, error) {
+	scanned, err := time.Parse(time.RFC3339Nano, m.Time)
+	if err != nil {
+		return Entry{}, fmt.Errorf("unable to parse scan time (%+v): %w", m.Time, err)
+	}
+	built, err := time.Parse(time.RFC3339, m.DBBuilt)
+	if err != nil {
+		return Entry{}, fmt.Errorf("unable to parse DB build time (%+v): %w", m.DBBuilt, err)
+	}
+
+	return Entry{
+		ID:              m.ID,
+		Time:            scanned,
+		Target:          m.Target,
+		Digest:          m.Digest,
+		DBBuilt:         built,
+		DBSchemaVersion: m.DBSchemaVersion,
+		Counts: Counts{
+			Critical:   m.Critical,
+			High:       m.High,
+			Medium:     m.Medium,
+			Low:        m.Low,
+			Negligible: m.Negligible,
+			Unknown:    m.Unknown,
+		},
+	}, nil
+}
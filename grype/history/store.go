@@ -0,0 +1,95 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jinzhu/gorm"
+
+	// provide the sqlite dialect to gorm via import
+	_ "github.com/jinzhu/gorm/dialects/sqlite"
+)
+
+// Store persists the summaries of scans within a local sqlite3 DB.
+type Store struct {
+	db *gorm.DB
+}
+
+// Open opens (or creates) the history DB at the given path.
+func Open(path string) (*Store, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no history filepath given")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("unable to create history directory: %w", err)
+	}
+
+	db, err := gorm.Open("sqlite3", fmt.Sprintf("file:%s", path))
+	if err != nil {
+		return nil, fmt.Errorf("unable to open history DB: %w", err)
+	}
+
+	if err := db.AutoMigrate(&entryModel{}).Error; err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("unable to migrate history DB: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the history DB.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Add stores the given scan summary, returning the entry with its assigned ID.
+func (s *Store) Add(entry Entry) (Entry, error) {
+	entry.ID = 0
+	model := newEntryModel(entry)
+	if err := s.db.Create(&model).Error; err != nil {
+		return Entry{}, fmt.Errorf("unable to add history entry: %w", err)
+	}
+	entry.ID = model.ID
+	return entry, nil
+}
+
+// List returns the scan summaries (of the given target, when given) from oldest to newest.
+func (s *Store) List(target string) ([]Entry, error) {
+	query := s.db.Order("time asc, id asc")
+	if target != "" {
+		query = query.Where("target = ?", target)
+	}
+
+	var models []entryModel
+	if err := query.Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("unable to list history: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(models))
+	for _, m := range models {
+		entry, err := m.Inflate()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Get returns the scan summary with the given ID (or nil when there is no such entry).
+func (s *Store) Get(id uint) (*Entry, error) {
+	var models []entryModel
+	if err := s.db.Where("id = ?", id).Find(&models).Error; err != nil {
+		return nil, fmt.Errorf("unable to get history entry: %w", err)
+	}
+	if len(models) == 0 {
+		return nil, nil
+	}
+
+	entry, err := models[0].Inflate()
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
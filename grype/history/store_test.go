@@ -0,0 +1,63 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	store, err := Open(filepath.Join(t.TempDir(), "nested", "history.db"))
+	require.NoError(t, err)
+	defer store.Close()
+
+	start := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	built := time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC)
+	for i, e := range []Entry{
+		{Time: start, Target: "alpine:latest", Digest: "sha256:a", Counts: Counts{Critical: 2, High: 3}},
+		{Time: start.Add(time.Hour), Target: "debian:latest", Counts: Counts{Low: 1}},
+		{Time: start.Add(2 * time.Hour), Target: "alpine:latest", Digest: "sha256:b", Counts: Counts{High: 1}},
+	} {
+		e.DBBuilt = built
+		e.DBSchemaVersion = 3
+		added, err := store.Add(e)
+		require.NoError(t, err)
+		assert.Equal(t, uint(i+1), added.ID)
+	}
+
+	all, err := store.List("")
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "debian:latest", all[1].Target)
+
+	alpine, err := store.List("alpine:latest")
+	require.NoError(t, err)
+	require.Len(t, alpine, 2)
+	assert.Equal(t, Entry{
+		ID:              3,
+		Time:            start.Add(2 * time.Hour),
+		Target:          "alpine:latest",
+		Digest:          "sha256:b",
+		DBBuilt:         built,
+		DBSchemaVersion: 3,
+		Counts:          Counts{High: 1},
+	}, alpine[1])
+
+	entry, err := store.Get(1)
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, "sha256:a", entry.Digest)
+	assert.Equal(t, Counts{Critical: 2, High: 3}, entry.Counts)
+
+	missing, err := store.Get(42)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestOpen_NoPath(t *testing.T) {
+	_, err := Open("")
+	assert.Error(t, err)
+}
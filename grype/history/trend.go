@@ -0,0 +1,57 @@
+package history
+
+import "sort"
+
+// Trend summarizes how the vulnerabilities of a target changed over its scans.
+type Trend struct {
+	Target string `json:"target"`
+	Scans  int    `json:"scans"`
+	First  Entry  `json:"first"`
+	Latest Entry  `json:"latest"`
+	// Change is the difference of the latest counts to the first counts (where negative is fewer vulnerabilities)
+	Change Counts `json:"change"`
+}
+
+// Improving indicates if the latest scan found fewer vulnerabilities than the first scan.
+func (t Trend) Improving() bool {
+	return t.Change.Total() < 0
+}
+
+// Trends returns the trend of every target within the given entries (ordered from oldest to newest), sorted by target.
+func Trends(entries []Entry) []Trend {
+	byTarget := make(map[string]*Trend)
+	for _, entry := range entries {
+		trend, ok := byTarget[entry.Target]
+		if !ok {
+			trend = &Trend{Target: entry.Target, First: entry}
+			byTarget[entry.Target] = trend
+		}
+		trend.Scans++
+		trend.Latest = entry
+	}
+
+	trends := make([]Trend, 0, len(byTarget))
+	for _, trend := range byTarget {
+		trend.Change = trend.Latest.Counts.Sub(trend.First.Counts)
+		trends = append(trends, *trend)
+	}
+	sort.Slice(trends, func(i, j int) bool {
+		return trends[i].Target < trends[j].Target
+	})
+	return trends
+}
+
+// Previous returns the scan of the same target preceding the given entry (or nil when it is the first scan), where
+// the entries are ordered from oldest to newest.
+func Previous(entries []Entry, entry Entry) *Entry {
+	var previous *Entry
+	for i := range entries {
+		if entries[i].ID == entry.ID {
+			return previous
+		}
+		if entries[i].Target == entry.Target {
+			previous = &entries[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package history
+
+import (
+	"testing"
+
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrends(t *testing.T) {
+	entries := []Entry{
+		{ID: 1, Target: "alpine", Counts: Counts{Critical: 2, High: 3}},
+		{ID: 2, Target: "debian", Counts: Counts{Low: 1}},
+		{ID: 3, Target: "alpine", Counts: Counts{Critical: 1, High: 1}},
+		{ID: 4, Target: "alpine", Counts: Counts{High: 2, Medium: 1}},
+		{ID: 5, Target: "debian", Counts: Counts{Low: 2}},
+	}
+
+	trends := Trends(entries)
+	if assert.Len(t, trends, 2) {
+		assert.Equal(t, "alpine", trends[0].Target)
+		assert.Equal(t, 3, trends[0].Scans)
+		assert.Equal(t, uint(1), trends[0].First.ID)
+		assert.Equal(t, uint(4), trends[0].Latest.ID)
+		assert.Equal(t, Counts{Critical: -2, High: -1, Medium: 1}, trends[0].Change)
+		assert.True(t, trends[0].Improving())
+
+		assert.Equal(t, "debian", trends[1].Target)
+		assert.Equal(t, Counts{Low: 1}, trends[1].Change)
+		assert.False(t, trends[1].Improving())
+	}
+
+	assert.Empty(t, Trends(nil))
+}
+
+func TestPrevious(t *testing.T) {
+	entries := []Entry{
+		{ID: 1, Target: "alpine"},
+		{ID: 2, Target: "debian"},
+		{ID: 3, Target: "alpine"},
+	}
+
+	assert.Nil(t, Previous(entries, entries[0]))
+	assert.Nil(t, Previous(entries, entries[1]))
+	if previous := Previous(entries, entries[2]); assert.NotNil(t, previous) {
+		assert.Equal(t, uint(1), previous.ID)
+	}
+}
+
+func TestCounts(t *testing.T) {
+	var counts Counts
+	for _, severity := range []vulnerability.Severity{
+		vulnerability.CriticalSeverity,
+		vulnerability.HighSeverity,
+		vulnerability.HighSeverity,
+		vulnerability.LowSeverity,
+		vulnerability.UnknownSeverity,
+	} {
+		counts.Add(severity)
+	}
+
+	assert.Equal(t, Counts{Critical: 1, High: 2, Low: 1, Unknown: 1}, counts)
+	assert.Equal(t, 5, counts.Total())
+	assert.Equal(t, Counts{High: 1, Unknown: 1}, counts.Sub(Counts{Critical: 1, High: 1, Low: 1}))
+	assert.Equal(t, Counts{Critical: 1, High: 3, Low: 1, Unknown: 1}, counts.Plus(Counts{High: 1}))
+}
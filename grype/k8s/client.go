@@ -0,0 +1,105 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/internal/proxy"
+	"k8s.io/client-go/rest"
+)
+
+// listLimit is the number of objects requested from the API server at once.
+const listLimit = 500
+
+// Client lists the objects of a cluster that are needed to find its images.
+type Client struct {
+	server string
+	http   *http.Client
+}
+
+// NewClient returns a client of the API server of the given config, which authenticates every request as configured
+// (e.g. with the credentials of a credential plugin, which are renewed when these expire).
+func NewClient(config *Config) (*Client, error) {
+	restConfig := rest.CopyConfig(config.REST)
+	if restConfig.Proxy == nil {
+		restConfig.Proxy = proxy.ForRequest
+	}
+	transport, err := rest.TransportFor(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config of the API server: %w", err)
+	}
+	return &Client{
+		server: strings.TrimSuffix(restConfig.Host, "/"),
+		http:   &http.Client{Transport: transport, Timeout: time.Minute},
+	}, nil
+}
+
+// list calls the given function with every object of the given collection, following the pages of the collection.
+func (c *Client) list(path string, fn func(json.RawMessage) error) error {
+	query := url.Values{"limit": []string{fmt.Sprint(listLimit)}}
+	for {
+		var page struct {
+			Metadata struct {
+				Continue string `json:"continue"`
+			} `json:"metadata"`
+			Items []json.RawMessage `json:"items"`
+		}
+		if err := c.get(path, query, &page); err != nil {
+			return err
+		}
+		for _, item := range page.Items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		if page.Metadata.Continue == "" {
+			return nil
+		}
+		query.Set("continue", page.Metadata.Continue)
+	}
+}
+
+func (c *Client) get(path string, query url.Values, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.server+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to list %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		// the API server describes failures with a status object
+		var status struct {
+			Message string `json:"message"`
+		}
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		if json.Unmarshal(body, &status) != nil || status.Message == "" {
+			status.Message = strings.TrimSpace(string(body))
+		}
+		return fmt.Errorf("unable to list %s: %s (%s)", path, resp.Status, status.Message)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("unable to decode %s: %w", path, err)
+	}
+	return nil
+}
+
+// collectionPath returns the path of the given resource within the namespace (or all namespaces when empty).
+func collectionPath(group, namespace, resource string) string {
+	if namespace == "" {
+		return fmt.Sprintf("%s/%s", group, resource)
+	}
+	return fmt.Sprintf("%s/namespaces/%s/%s", group, url.PathEscape(namespace), resource)
+}
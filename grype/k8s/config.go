@@ -0,0 +1,44 @@
+package k8s
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	// the auth-provider plugins of kubeconfig users (exec credential plugins are built into client-go)
+	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
+	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
+)
+
+// Config describes how to connect to the API server of a cluster.
+type Config struct {
+	// REST is the config of the API server, including how to authenticate (e.g. with a token, a client certificate,
+	// or a credential plugin of the kubeconfig)
+	REST *rest.Config
+	// Namespace is the default namespace of the context
+	Namespace string
+}
+
+// LoadConfig returns the config of the given kubeconfig file and context (the current context when empty). Without a
+// kubeconfig file the files of $KUBECONFIG or ~/.kube/config are used, falling back to the in-cluster config (of the
+// service account of the pod) when none exists. Kubeconfig files are loaded the same way as by kubectl, so users that
+// authenticate with a credential plugin (exec or auth-provider) are supported.
+func LoadConfig(path, context string) (*Config, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = path
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: context})
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		if clientcmd.IsEmptyConfig(err) {
+			return nil, fmt.Errorf("no kubeconfig found and not running within a cluster (see --kubeconfig)")
+		}
+		return nil, fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine namespace: %w", err)
+	}
+	return &Config{REST: restConfig, Namespace: namespace}, nil
+}
@@ -0,0 +1,139 @@
+package k8s
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		name              string
+		context           string
+		expectedHost      string
+		expectedToken     string
+		expectedCA        []byte
+		expectedInsecure  bool
+		expectedNamespace string
+		expectedExec      string
+		wantErr           require.ErrorAssertionFunc
+	}{
+		{
+			name:              "current context",
+			context:           "",
+			expectedHost:      "https://dev.example.com:6443",
+			expectedToken:     "dev-token",
+			expectedInsecure:  true,
+			expectedNamespace: "default",
+		},
+		{
+			name:              "token file and certificate authority",
+			context:           "prod",
+			expectedHost:      "https://prod.example.com:6443",
+			expectedToken:     "prod-token",
+			expectedCA:        []byte("ca-cert"),
+			expectedNamespace: "prod",
+		},
+		{
+			name:              "credential plugin",
+			context:           "sso",
+			expectedHost:      "https://prod.example.com:6443",
+			expectedCA:        []byte("ca-cert"),
+			expectedNamespace: "default",
+			expectedExec:      "kubelogin",
+		},
+		{
+			name:    "missing cluster",
+			context: "missing",
+			wantErr: require.Error,
+		},
+		{
+			name:    "missing context",
+			context: "staging",
+			wantErr: require.Error,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.wantErr == nil {
+				test.wantErr = require.NoError
+			}
+			config, err := LoadConfig("test-fixtures/kubeconfig", test.context)
+			test.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, test.expectedHost, config.REST.Host)
+			assert.Equal(t, test.expectedToken, strings.TrimSpace(config.REST.BearerToken))
+			assert.Equal(t, test.expectedCA, config.REST.TLSClientConfig.CAData)
+			assert.Equal(t, test.expectedInsecure, config.REST.TLSClientConfig.Insecure)
+			assert.Equal(t, test.expectedNamespace, config.Namespace)
+			if test.expectedExec == "" {
+				assert.Nil(t, config.REST.ExecProvider)
+			} else {
+				require.NotNil(t, config.REST.ExecProvider)
+				assert.Equal(t, test.expectedExec, config.REST.ExecProvider.Command)
+			}
+		})
+	}
+}
+
+func TestClient_ExecCredentialPlugin(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the credential plugin is a shell script")
+	}
+
+	var authorization string
+	// credentials are only sent to API servers over TLS
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"items": []}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	plugin := filepath.Join(dir, "credential-plugin")
+	require.NoError(t, os.WriteFile(plugin, []byte(`#!/bin/sh
+echo '{"apiVersion": "client.authentication.k8s.io/v1beta1", "kind": "ExecCredential", "status": {"token": "plugin-token"}}'
+`), 0700))
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+current-context: test
+clusters:
+  - name: test
+    cluster:
+      server: %s
+      insecure-skip-tls-verify: true
+contexts:
+  - name: test
+    context:
+      cluster: test
+      user: test
+users:
+  - name: test
+    user:
+      exec:
+        apiVersion: client.authentication.k8s.io/v1beta1
+        command: %s
+`, server.URL, plugin)), 0600))
+
+	config, err := LoadConfig(kubeconfig, "")
+	require.NoError(t, err)
+	client, err := NewClient(config)
+	require.NoError(t, err)
+
+	_, err = client.Images("default")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer plugin-token", authorization)
+}
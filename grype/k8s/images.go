@@ -0,0 +1,226 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/internal/log"
+)
+
+// Workload is the object that controls pods (e.g. a Deployment), or a pod that is not controlled by another object.
+type Workload struct {
+	Namespace string `json:"namespace"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+}
+
+func (w Workload) String() string {
+	return fmt.Sprintf("%s/%s/%s", w.Namespace, strings.ToLower(w.Kind), w.Name)
+}
+
+// Image is a distinct image (by digest) running within the cluster.
+type Image struct {
+	// Image is the image as given within the pod spec (e.g. "nginx:1.21")
+	Image string `json:"image"`
+	// Reference is what is scanned, which is pinned to the digest of the running image when known
+	Reference string `json:"reference"`
+	// Digest is the digest of the running image as reported by the container runtime (empty when not yet known)
+	Digest    string     `json:"digest,omitempty"`
+	Workloads []Workload `json:"workloads"`
+}
+
+type objectMeta struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace"`
+	OwnerReferences []struct {
+		Kind       string `json:"kind"`
+		Name       string `json:"name"`
+		Controller bool   `json:"controller"`
+	} `json:"ownerReferences"`
+}
+
+// controller returns the object that controls the given object, if any.
+func (m objectMeta) controller() (Workload, bool) {
+	for _, ref := range m.OwnerReferences {
+		if ref.Controller {
+			return Workload{Namespace: m.Namespace, Kind: ref.Kind, Name: ref.Name}, true
+		}
+	}
+	return Workload{}, false
+}
+
+type container struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+}
+
+type containerStatus struct {
+	Name    string `json:"name"`
+	ImageID string `json:"imageID"`
+}
+
+type pod struct {
+	Metadata objectMeta `json:"metadata"`
+	Spec     struct {
+		InitContainers []container `json:"initContainers"`
+		Containers     []container `json:"containers"`
+	} `json:"spec"`
+	Status struct {
+		Phase                 string            `json:"phase"`
+		InitContainerStatuses []containerStatus `json:"initContainerStatuses"`
+		ContainerStatuses     []containerStatus `json:"containerStatuses"`
+	} `json:"status"`
+}
+
+// Images returns the distinct images of the running pods within the namespace (or all namespaces when empty), with the
+// workloads they belong to.
+func (c *Client) Images(namespace string) ([]Image, error) {
+	var pods []pod
+	err := c.list(collectionPath("/api/v1", namespace, "pods"), func(item json.RawMessage) error {
+		var p pod
+		if err := json.Unmarshal(item, &p); err != nil {
+			return fmt.Errorf("unable to decode pod: %w", err)
+		}
+		if p.Status.Phase == "Running" {
+			pods = append(pods, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	workloads := newWorkloadResolver(c, namespace)
+	images := make(map[string]*Image)
+	for _, p := range pods {
+		workload := workloads.resolve(p.Metadata)
+
+		statuses := make(map[string]string)
+		for _, s := range append(p.Status.InitContainerStatuses, p.Status.ContainerStatuses...) {
+			statuses[s.Name] = s.ImageID
+		}
+		for _, ctr := range append(p.Spec.InitContainers, p.Spec.Containers...) {
+			reference, digest := parseImageID(statuses[ctr.Name])
+			if reference == "" {
+				reference = ctr.Image
+			}
+			key := digest
+			if key == "" {
+				key = ctr.Image
+			}
+
+			image, ok := images[key]
+			if !ok {
+				image = &Image{Image: ctr.Image, Reference: reference, Digest: digest}
+				images[key] = image
+			}
+			image.Workloads = appendWorkload(image.Workloads, workload)
+		}
+	}
+
+	result := make([]Image, 0, len(images))
+	for _, image := range images {
+		sortWorkloads(image.Workloads)
+		result = append(result, *image)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Image != result[j].Image {
+			return result[i].Image < result[j].Image
+		}
+		return result[i].Digest < result[j].Digest
+	})
+	return result, nil
+}
+
+// parseImageID returns the reference (pinned by digest) and the digest of the image ID of a container status, where
+// either may be empty when the container runtime does not report it (e.g. "docker-pullable://nginx@sha256:..." has
+// both, while "sha256:..." is only the digest of the local image).
+func parseImageID(imageID string) (string, string) {
+	for _, prefix := range []string{"docker-pullable://", "docker://"} {
+		imageID = strings.TrimPrefix(imageID, prefix)
+	}
+	if i := strings.Index(imageID, "@sha256:"); i >= 0 {
+		return imageID, imageID[i+1:]
+	}
+	if strings.HasPrefix(imageID, "sha256:") {
+		return "", imageID
+	}
+	return "", ""
+}
+
+func appendWorkload(workloads []Workload, workload Workload) []Workload {
+	for _, w := range workloads {
+		if w == workload {
+			return workloads
+		}
+	}
+	return append(workloads, workload)
+}
+
+func sortWorkloads(workloads []Workload) {
+	sort.Slice(workloads, func(i, j int) bool {
+		return workloads[i].String() < workloads[j].String()
+	})
+}
+
+// workloadResolver finds the workload of a pod, following the owners of ReplicaSets (i.e. Deployments) and Jobs (i.e.
+// CronJobs), which are listed when first needed.
+type workloadResolver struct {
+	client    *Client
+	namespace string
+	owners    map[string]map[Workload]Workload
+}
+
+func newWorkloadResolver(client *Client, namespace string) *workloadResolver {
+	return &workloadResolver{
+		client:    client,
+		namespace: namespace,
+		owners:    make(map[string]map[Workload]Workload),
+	}
+}
+
+func (r *workloadResolver) resolve(metadata objectMeta) Workload {
+	workload, ok := metadata.controller()
+	if !ok {
+		return Workload{Namespace: metadata.Namespace, Kind: "Pod", Name: metadata.Name}
+	}
+
+	var path string
+	switch workload.Kind {
+	case "ReplicaSet":
+		path = collectionPath("/apis/apps/v1", r.namespace, "replicasets")
+	case "Job":
+		path = collectionPath("/apis/batch/v1", r.namespace, "jobs")
+	default:
+		return workload
+	}
+
+	owners, ok := r.owners[workload.Kind]
+	if !ok {
+		owners = make(map[Workload]Workload)
+		err := r.client.list(path, func(item json.RawMessage) error {
+			var object struct {
+				Metadata objectMeta `json:"metadata"`
+			}
+			if err := json.Unmarshal(item, &object); err != nil {
+				return fmt.Errorf("unable to decode %s: %w", workload.Kind, err)
+			}
+			if owner, ok := object.Metadata.controller(); ok {
+				owners[Workload{Namespace: object.Metadata.Namespace, Kind: workload.Kind, Name: object.Metadata.Name}] = owner
+			}
+			return nil
+		})
+		if err != nil {
+			// the pods are still grouped by their direct controller (e.g. without permission to list ReplicaSets)
+			log.Warnf("unable to find the owners of %s objects: %+v", workload.Kind, err)
+		}
+		r.owners[workload.Kind] = owners
+	}
+
+	if owner, ok := owners[workload]; ok {
+		return owner
+	}
+	return workload
+}
@@ -0,0 +1,169 @@
+package k8s
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func newPod(namespace, name, phase string, owner map[string]interface{}, images map[string]string) map[string]interface{} {
+	metadata := map[string]interface{}{"namespace": namespace, "name": name}
+	if owner != nil {
+		metadata["ownerReferences"] = []interface{}{owner}
+	}
+	var containers, statuses []interface{}
+	for container, imageID := range images {
+		containers = append(containers, map[string]string{"name": container, "image": container + ":latest"})
+		statuses = append(statuses, map[string]string{"name": container, "imageID": imageID})
+	}
+	return map[string]interface{}{
+		"metadata": metadata,
+		"spec":     map[string]interface{}{"containers": containers},
+		"status":   map[string]interface{}{"phase": phase, "containerStatuses": statuses},
+	}
+}
+
+func controller(kind, name string) map[string]interface{} {
+	return map[string]interface{}{"kind": kind, "name": name, "controller": true}
+}
+
+func newAPIServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	collections := map[string][][]interface{}{
+		// the pods are split across two pages
+		"/api/v1/namespaces/prod/pods": {
+			{
+				newPod("prod", "web-7d4b9c-abcde", "Running", controller("ReplicaSet", "web-7d4b9c"), map[string]string{
+					"nginx": "docker-pullable://nginx@sha256:aaaa",
+				}),
+				newPod("prod", "web-7d4b9c-fghij", "Running", controller("ReplicaSet", "web-7d4b9c"), map[string]string{
+					"nginx": "docker-pullable://nginx@sha256:aaaa",
+				}),
+			},
+			{
+				newPod("prod", "db-0", "Running", controller("StatefulSet", "db"), map[string]string{
+					"postgres": "docker.io/library/postgres@sha256:bbbb",
+					"nginx":    "docker-pullable://nginx@sha256:aaaa",
+				}),
+				newPod("prod", "backup-27312-xyz", "Running", controller("Job", "backup-27312"), map[string]string{
+					"busybox": "sha256:cccc",
+				}),
+				newPod("prod", "debug", "Running", nil, map[string]string{
+					"alpine": "",
+				}),
+				newPod("prod", "done", "Succeeded", nil, map[string]string{
+					"ubuntu": "docker-pullable://ubuntu@sha256:dddd",
+				}),
+			},
+		},
+		"/apis/apps/v1/namespaces/prod/replicasets": {{
+			map[string]interface{}{"metadata": map[string]interface{}{
+				"namespace": "prod", "name": "web-7d4b9c", "ownerReferences": []interface{}{controller("Deployment", "web")},
+			}},
+		}},
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"kind": "Status", "message": "Unauthorized"}`))
+			return
+		}
+		pages, ok := collections[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"kind": "Status", "message": "forbidden"}`))
+			return
+		}
+		page := 0
+		if r.URL.Query().Get("continue") == "next" {
+			page = 1
+		}
+		list := map[string]interface{}{"items": pages[page], "metadata": map[string]string{}}
+		if page+1 < len(pages) {
+			list["metadata"] = map[string]string{"continue": "next"}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(list))
+	}))
+}
+
+func TestClient_Images(t *testing.T) {
+	server := newAPIServer(t)
+	defer server.Close()
+
+	client, err := NewClient(&Config{REST: &rest.Config{Host: server.URL, BearerToken: "token"}})
+	require.NoError(t, err)
+
+	images, err := client.Images("prod")
+	require.NoError(t, err)
+
+	expected := []Image{
+		{
+			Image:     "alpine:latest",
+			Reference: "alpine:latest",
+			Workloads: []Workload{{Namespace: "prod", Kind: "Pod", Name: "debug"}},
+		},
+		{
+			Image:     "busybox:latest",
+			Reference: "busybox:latest",
+			Digest:    "sha256:cccc",
+			// without permission to list the jobs, the pod is grouped by its job
+			Workloads: []Workload{{Namespace: "prod", Kind: "Job", Name: "backup-27312"}},
+		},
+		{
+			Image:     "nginx:latest",
+			Reference: "nginx@sha256:aaaa",
+			Digest:    "sha256:aaaa",
+			Workloads: []Workload{
+				{Namespace: "prod", Kind: "Deployment", Name: "web"},
+				{Namespace: "prod", Kind: "StatefulSet", Name: "db"},
+			},
+		},
+		{
+			Image:     "postgres:latest",
+			Reference: "docker.io/library/postgres@sha256:bbbb",
+			Digest:    "sha256:bbbb",
+			Workloads: []Workload{{Namespace: "prod", Kind: "StatefulSet", Name: "db"}},
+		},
+	}
+	assert.Equal(t, expected, images)
+}
+
+func TestClient_Images_Unauthorized(t *testing.T) {
+	server := newAPIServer(t)
+	defer server.Close()
+
+	client, err := NewClient(&Config{REST: &rest.Config{Host: server.URL, BearerToken: "wrong"}})
+	require.NoError(t, err)
+
+	_, err = client.Images("prod")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unauthorized")
+}
+
+func TestParseImageID(t *testing.T) {
+	tests := []struct {
+		imageID   string
+		reference string
+		digest    string
+	}{
+		{imageID: "docker-pullable://nginx@sha256:aaaa", reference: "nginx@sha256:aaaa", digest: "sha256:aaaa"},
+		{imageID: "docker.io/library/nginx@sha256:aaaa", reference: "docker.io/library/nginx@sha256:aaaa", digest: "sha256:aaaa"},
+		{imageID: "docker://sha256:aaaa", digest: "sha256:aaaa"},
+		{imageID: "sha256:aaaa", digest: "sha256:aaaa"},
+		{imageID: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.imageID, func(t *testing.T) {
+			reference, digest := parseImageID(test.imageID)
+			assert.Equal(t, test.reference, reference)
+			assert.Equal(t, test.digest, digest)
+		})
+	}
+}
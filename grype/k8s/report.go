@@ -0,0 +1,87 @@
+package k8s
+
+import (
+	"sort"
+
+	"github.com/anchore/grype/grype/history"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// ImageResult is the result of scanning an image of the cluster.
+type ImageResult struct {
+	Image
+	Matches []models.Match `json:"matches"`
+	// Error describes why the image could not be scanned
+	Error string `json:"error,omitempty"`
+}
+
+// Counts returns the number of vulnerabilities of the image by severity.
+func (r ImageResult) Counts() history.Counts {
+	var counts history.Counts
+	for _, m := range r.Matches {
+		counts.Add(vulnerability.ParseSeverity(m.Vulnerability.Severity))
+	}
+	return counts
+}
+
+// WorkloadSummary is the vulnerabilities of all images of a workload.
+type WorkloadSummary struct {
+	Workload
+	Images []string `json:"images"`
+	// Failed is the number of images of the workload that could not be scanned
+	Failed int            `json:"failed,omitempty"`
+	Counts history.Counts `json:"counts"`
+}
+
+// Report is the cluster-level result of scanning all images, grouped by workload.
+type Report struct {
+	Workloads []WorkloadSummary `json:"workloads"`
+	Images    []ImageResult     `json:"images"`
+	// Counts is the number of vulnerabilities of all distinct images by severity
+	Counts history.Counts `json:"counts"`
+}
+
+// NewReport aggregates the results of the images of a cluster by workload.
+func NewReport(results []ImageResult) Report {
+	report := Report{Images: results}
+	summaries := make(map[Workload]*WorkloadSummary)
+	for _, result := range results {
+		counts := result.Counts()
+		report.Counts = report.Counts.Plus(counts)
+
+		for _, workload := range result.Workloads {
+			summary, ok := summaries[workload]
+			if !ok {
+				summary = &WorkloadSummary{Workload: workload}
+				summaries[workload] = summary
+			}
+			summary.Images = append(summary.Images, result.Image.Image)
+			if result.Error != "" {
+				summary.Failed++
+			}
+			summary.Counts = summary.Counts.Plus(counts)
+		}
+	}
+
+	for _, summary := range summaries {
+		sort.Strings(summary.Images)
+		report.Workloads = append(report.Workloads, *summary)
+	}
+	sort.Slice(report.Workloads, func(i, j int) bool {
+		return report.Workloads[i].Workload.String() < report.Workloads[j].Workload.String()
+	})
+	return report
+}
+
+// HasSeverity indicates if any image has a vulnerability at or above the given severity.
+func (r Report) HasSeverity(severity vulnerability.Severity) bool {
+	for _, result := range r.Images {
+		for _, m := range result.Matches {
+			if vulnerability.ParseSeverity(m.Vulnerability.Severity) >= severity {
+				return true
+			}
+		}
+	}
+	return false
+}
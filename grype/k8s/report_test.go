@@ -0,0 +1,58 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/anchore/grype/grype/history"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMatch(severity string) models.Match {
+	return models.Match{
+		Vulnerability: models.Vulnerability{
+			VulnerabilityMetadata: models.VulnerabilityMetadata{ID: "CVE-2021-0001", Severity: severity},
+		},
+	}
+}
+
+func TestNewReport(t *testing.T) {
+	web := Workload{Namespace: "prod", Kind: "Deployment", Name: "web"}
+	db := Workload{Namespace: "prod", Kind: "StatefulSet", Name: "db"}
+
+	results := []ImageResult{
+		{
+			Image:   Image{Image: "nginx:latest", Workloads: []Workload{web, db}},
+			Matches: []models.Match{newMatch("Critical"), newMatch("Low")},
+		},
+		{
+			Image:   Image{Image: "postgres:latest", Workloads: []Workload{db}},
+			Matches: []models.Match{newMatch("High")},
+		},
+		{
+			Image: Image{Image: "private:latest", Workloads: []Workload{web}},
+			Error: "unauthorized",
+		},
+	}
+
+	report := NewReport(results)
+
+	assert.Equal(t, []WorkloadSummary{
+		{
+			Workload: web,
+			Images:   []string{"nginx:latest", "private:latest"},
+			Failed:   1,
+			Counts:   history.Counts{Critical: 1, Low: 1},
+		},
+		{
+			Workload: db,
+			Images:   []string{"nginx:latest", "postgres:latest"},
+			Counts:   history.Counts{Critical: 1, High: 1, Low: 1},
+		},
+	}, report.Workloads)
+	assert.Equal(t, history.Counts{Critical: 1, High: 1, Low: 1}, report.Counts)
+
+	assert.True(t, report.HasSeverity(vulnerability.CriticalSeverity))
+	assert.False(t, NewReport(results[1:]).HasSeverity(vulnerability.CriticalSeverity))
+}
@@ -1,7 +1,10 @@
 package grype
 
 import (
+	"context"
+
 	"github.com/anchore/grype/grype/db"
+	"github.com/anchore/grype/grype/distro"
 	"github.com/anchore/grype/grype/logger"
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/matcher"
@@ -35,6 +38,16 @@ func FindVulnerabilitiesForPackage(provider vulnerability.Provider, d *linux.Rel
 	return matcher.FindMatches(provider, d, packages...)
 }
 
+func FindVulnerabilitiesForPackageWithConfig(provider vulnerability.Provider, d *linux.Release, cfg matcher.Config, packages ...pkg.Package) match.Matches {
+	return matcher.FindMatchesWithConfig(provider, d, cfg, packages...)
+}
+
+// FindVulnerabilitiesForPackageContext is FindVulnerabilitiesForPackageWithConfig, where the spans of matching are
+// children of the span of the given context.
+func FindVulnerabilitiesForPackageContext(ctx context.Context, provider vulnerability.Provider, d *linux.Release, cfg matcher.Config, packages ...pkg.Package) match.Matches {
+	return matcher.FindMatchesContext(ctx, provider, d, cfg, packages...)
+}
+
 func LoadVulnerabilityDB(cfg db.Config, update bool) (vulnerability.Provider, vulnerability.MetadataProvider, *db.Status, error) {
 	dbCurator, err := db.NewCurator(cfg)
 	if err != nil {
@@ -54,9 +67,30 @@ func LoadVulnerabilityDB(cfg db.Config, update bool) (vulnerability.Provider, vu
 		return nil, nil, nil, err
 	}
 
+	var mergedStore db.Store = store
+	if len(cfg.Overlays) > 0 {
+		var stores []db.Store
+		for _, overlayPath := range cfg.Overlays {
+			overlay, err := db.OpenOverlay(overlayPath)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			log.Debugf("merging overlay vulnerability database: %s", overlayPath)
+			stores = append(stores, overlay)
+		}
+		mergedStore = db.NewMergedStore(append(stores, store)...)
+	}
+
 	status := dbCurator.Status()
 
-	return db.NewVulnerabilityProvider(store), db.NewVulnerabilityMetadataProvider(store), &status, status.Err
+	aliases := cfg.DistroAliases
+	if aliases == nil {
+		aliases = distro.DefaultAliasRules
+	}
+
+	provider := db.NewVulnerabilityProviderWithAliases(mergedStore, aliases).WithCache(cfg.CacheSize)
+
+	return provider, db.NewVulnerabilityMetadataProvider(mergedStore), &status, status.Err
 }
 
 func SetLogger(logger logger.Logger) {
@@ -0,0 +1,206 @@
+package match
+
+import (
+	"sort"
+	"strings"
+
+	grypeDb "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// MergeAliases merges the matches of each package that refer to the same flaw by different IDs (e.g. a GHSA, the CVE
+// that it is an alias of, and the distro advisory of that CVE) into a single match. Two matches refer to the same flaw
+// when they have the same ID, when the vulnerability of one is an alias of the other, or when both are aliases of the
+// same vulnerability, where a vulnerability is only an alias of its related vulnerability when it has exactly one (an
+// advisory that covers several CVEs is not the same flaw as any of them, so it keeps these apart). The merged match
+// has the vulnerability of the most specific source (a distro, then a language ecosystem, then the NVD), reports the
+// vulnerabilities of the other matches as related vulnerabilities, and has the fix versions of all of the matches.
+func MergeAliases(matches Matches) Matches {
+	byPackage := make(map[pkg.ID][]Match)
+	var packageIDs []pkg.ID
+	for _, m := range matches.Sorted() {
+		if _, ok := byPackage[m.Package.ID]; !ok {
+			packageIDs = append(packageIDs, m.Package.ID)
+		}
+		byPackage[m.Package.ID] = append(byPackage[m.Package.ID], m)
+	}
+
+	result := NewMatches()
+	for _, id := range packageIDs {
+		for _, group := range aliasGroups(byPackage[id]) {
+			result.Add(mergeGroup(group))
+		}
+	}
+	return result
+}
+
+// aliasGroups returns the given matches (of a single package) grouped by the flaw that they refer to.
+func aliasGroups(matches []Match) [][]Match {
+	// note: the groups are found with a union-find over the matches, where matches are joined by any shared alias ID
+	parent := make([]int, len(matches))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	// vulnerabilities of the matches that are related to several other vulnerabilities are not an alias of any of them,
+	// so other vulnerabilities that are aliases of these do not join the matches of the vulnerabilities they cover
+	ambiguous := make(map[string]bool)
+	for _, m := range matches {
+		if _, ok := aliasOf(m.Vulnerability); !ok && len(relatedIDs(m.Vulnerability)) > 0 {
+			ambiguous[m.Vulnerability.ID] = true
+		}
+	}
+
+	matchByID := make(map[string]int)
+	join := func(i int, id string) {
+		if other, ok := matchByID[id]; ok {
+			parent[find(i)] = find(other)
+			return
+		}
+		matchByID[id] = i
+	}
+	for i, m := range matches {
+		join(i, m.Vulnerability.ID)
+		if alias, ok := aliasOf(m.Vulnerability); ok && !ambiguous[alias] {
+			join(i, alias)
+		}
+	}
+
+	var groups [][]Match
+	groupIndex := make(map[int]int)
+	for i, m := range matches {
+		root := find(i)
+		idx, ok := groupIndex[root]
+		if !ok {
+			idx = len(groups)
+			groupIndex[root] = idx
+			groups = append(groups, nil)
+		}
+		groups[idx] = append(groups[idx], m)
+	}
+	return groups
+}
+
+// aliasOf returns the ID of the vulnerability that the given vulnerability is an alias of, which is its only related
+// vulnerability (other than records of the same ID within other namespaces).
+func aliasOf(v vulnerability.Vulnerability) (string, bool) {
+	ids := relatedIDs(v)
+	if len(ids) != 1 {
+		return "", false
+	}
+	return ids[0], true
+}
+
+// relatedIDs returns the distinct IDs of the related vulnerabilities of the vulnerability, except for its own ID.
+func relatedIDs(v vulnerability.Vulnerability) []string {
+	seen := map[string]bool{v.ID: true}
+	var ids []string
+	for _, r := range v.RelatedVulnerabilities {
+		if !seen[r.ID] {
+			seen[r.ID] = true
+			ids = append(ids, r.ID)
+		}
+	}
+	return ids
+}
+
+// vulnerabilityIDs returns the ID of the vulnerability and the IDs of its related vulnerabilities.
+func vulnerabilityIDs(v vulnerability.Vulnerability) []string {
+	ids := []string{v.ID}
+	for _, r := range v.RelatedVulnerabilities {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+// mergeGroup merges the matches of a single flaw into the match of the preferred vulnerability.
+func mergeGroup(group []Match) Match {
+	if len(group) == 1 {
+		return group[0]
+	}
+
+	sort.SliceStable(group, func(i, j int) bool {
+		return preferredVulnerability(group[i].Vulnerability, group[j].Vulnerability)
+	})
+	merged := group[0]
+	primary := vulnerability.Reference{ID: merged.Vulnerability.ID, Namespace: merged.Vulnerability.Namespace}
+
+	seen := map[vulnerability.Reference]bool{primary: true}
+	var related []vulnerability.Reference
+	addRelated := func(refs ...vulnerability.Reference) {
+		for _, r := range refs {
+			if !seen[r] {
+				seen[r] = true
+				related = append(related, r)
+			}
+		}
+	}
+	addRelated(merged.Vulnerability.RelatedVulnerabilities...)
+
+	merged.Vulnerability.Fix.Versions = append([]string(nil), merged.Vulnerability.Fix.Versions...)
+	fixVersions := make(map[string]bool)
+	for _, v := range merged.Vulnerability.Fix.Versions {
+		fixVersions[v] = true
+	}
+
+	detailIDs := make(map[string]bool)
+	var details Details
+	for _, m := range group {
+		addRelated(vulnerability.Reference{ID: m.Vulnerability.ID, Namespace: m.Vulnerability.Namespace})
+		addRelated(m.Vulnerability.RelatedVulnerabilities...)
+		// the preferred source may not know of the fix (yet), so the fix of any alias is a fix of the merged match
+		for _, v := range m.Vulnerability.Fix.Versions {
+			if !fixVersions[v] {
+				fixVersions[v] = true
+				merged.Vulnerability.Fix.Versions = append(merged.Vulnerability.Fix.Versions, v)
+				merged.Vulnerability.Fix.State = grypeDb.FixedState
+			}
+		}
+		for _, d := range m.Details {
+			if id := d.ID(); !detailIDs[id] {
+				detailIDs[id] = true
+				details = append(details, d)
+			}
+		}
+	}
+
+	merged.Vulnerability.RelatedVulnerabilities = related
+	merged.Details = details
+	return merged
+}
+
+// preferredVulnerability indicates whether the vulnerability a is preferred over b as the vulnerability of a merged
+// match: the most specific source first, then CVEs (which are referenced the most), then by ID and namespace.
+func preferredVulnerability(a, b vulnerability.Vulnerability) bool {
+	if ra, rb := namespaceRank(a.Namespace), namespaceRank(b.Namespace); ra != rb {
+		return ra < rb
+	}
+	if ca, cb := strings.HasPrefix(a.ID, "CVE-"), strings.HasPrefix(b.ID, "CVE-"); ca != cb {
+		return ca
+	}
+	if a.ID != b.ID {
+		return a.ID < b.ID
+	}
+	return a.Namespace < b.Namespace
+}
+
+// namespaceRank orders namespaces from the most specific source of vulnerability data (a distro, which describes the
+// packages of the distro) to the least specific source (the NVD, which describes products by CPE).
+func namespaceRank(namespace string) int {
+	switch {
+	case namespace == "nvd":
+		return 2
+	case strings.HasPrefix(namespace, "github:"), strings.HasPrefix(namespace, "osv:"):
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,174 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	grypeDb "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestMergeAliases(t *testing.T) {
+	lodash := pkg.Package{ID: "lodash", Name: "lodash", Version: "4.17.15", Type: syftPkg.NpmPkg}
+	openssl := pkg.Package{ID: "openssl", Name: "openssl", Version: "1.1.1k", Type: syftPkg.ApkPkg}
+
+	vuln := func(id, namespace string, related ...vulnerability.Reference) vulnerability.Vulnerability {
+		return vulnerability.Vulnerability{ID: id, Namespace: namespace, RelatedVulnerabilities: related}
+	}
+	ref := func(id, namespace string) vulnerability.Reference {
+		return vulnerability.Reference{ID: id, Namespace: namespace}
+	}
+	withFix := func(v vulnerability.Vulnerability, state grypeDb.FixState, versions ...string) vulnerability.Vulnerability {
+		v.Fix = vulnerability.Fix{State: state, Versions: versions}
+		return v
+	}
+	detail := func(matcher MatcherType) Details {
+		return Details{{Type: ExactDirectMatch, Matcher: matcher}}
+	}
+
+	tests := []struct {
+		name     string
+		matches  []Match
+		expected []Match
+	}{
+		{
+			name: "GHSA and the CVE it is an alias of are merged into the GHSA",
+			matches: []Match{
+				{Package: lodash, Vulnerability: vuln("GHSA-p6mc-m468-83gw", "github:language:javascript", ref("CVE-2020-8203", "nvd")), Details: detail(JavascriptMatcher)},
+				{Package: lodash, Vulnerability: vuln("CVE-2020-8203", "nvd"), Details: detail(StockMatcher)},
+			},
+			expected: []Match{
+				{
+					Package:       lodash,
+					Vulnerability: vuln("GHSA-p6mc-m468-83gw", "github:language:javascript", ref("CVE-2020-8203", "nvd")),
+					Details:       append(detail(JavascriptMatcher), detail(StockMatcher)...),
+				},
+			},
+		},
+		{
+			name: "distro advisory is preferred and lists the other IDs as related",
+			matches: []Match{
+				{Package: openssl, Vulnerability: vuln("CVE-2021-3711", "nvd"), Details: detail(ApkMatcher)},
+				{Package: openssl, Vulnerability: vuln("CVE-2021-3711", "alpine:distro:alpine:3.14", ref("CVE-2021-3711", "nvd")), Details: detail(ApkMatcher)},
+				{Package: openssl, Vulnerability: vuln("GHSA-xxxx-xxxx-xxxx", "github:language:rust", ref("CVE-2021-3711", "nvd")), Details: detail(RustMatcher)},
+			},
+			expected: []Match{
+				{
+					Package: openssl,
+					Vulnerability: vuln("CVE-2021-3711", "alpine:distro:alpine:3.14",
+						ref("CVE-2021-3711", "nvd"),
+						ref("GHSA-xxxx-xxxx-xxxx", "github:language:rust"),
+					),
+					Details: append(detail(ApkMatcher), detail(RustMatcher)...),
+				},
+			},
+		},
+		{
+			name: "advisories of several CVEs are not aliases of these CVEs",
+			matches: []Match{
+				{Package: lodash, Vulnerability: vuln("GHSA-aaaa-aaaa-aaaa", "github:language:javascript", ref("CVE-2021-0001", "nvd"))},
+				{Package: lodash, Vulnerability: vuln("OSV-2021-1", "osv:npm", ref("CVE-2021-0001", "nvd"), ref("CVE-2021-0002", "nvd"))},
+				{Package: lodash, Vulnerability: vuln("CVE-2021-0001", "nvd")},
+				{Package: lodash, Vulnerability: vuln("CVE-2021-0002", "nvd")},
+			},
+			expected: []Match{
+				{
+					Package:       lodash,
+					Vulnerability: vuln("GHSA-aaaa-aaaa-aaaa", "github:language:javascript", ref("CVE-2021-0001", "nvd")),
+				},
+				{
+					Package:       lodash,
+					Vulnerability: vuln("OSV-2021-1", "osv:npm", ref("CVE-2021-0001", "nvd"), ref("CVE-2021-0002", "nvd")),
+				},
+				{Package: lodash, Vulnerability: vuln("CVE-2021-0002", "nvd")},
+			},
+		},
+		{
+			name: "CVEs that are related to the same advisory of several CVEs are kept apart",
+			matches: []Match{
+				{Package: lodash, Vulnerability: vuln("OSV-2021-1", "osv:npm", ref("CVE-2021-0001", "nvd"), ref("CVE-2021-0002", "nvd"))},
+				{Package: lodash, Vulnerability: vuln("CVE-2021-0001", "github:language:javascript", ref("OSV-2021-1", "osv:npm"))},
+				{Package: lodash, Vulnerability: vuln("CVE-2021-0002", "github:language:javascript", ref("OSV-2021-1", "osv:npm"))},
+			},
+			expected: []Match{
+				{Package: lodash, Vulnerability: vuln("OSV-2021-1", "osv:npm", ref("CVE-2021-0001", "nvd"), ref("CVE-2021-0002", "nvd"))},
+				{Package: lodash, Vulnerability: vuln("CVE-2021-0001", "github:language:javascript", ref("OSV-2021-1", "osv:npm"))},
+				{Package: lodash, Vulnerability: vuln("CVE-2021-0002", "github:language:javascript", ref("OSV-2021-1", "osv:npm"))},
+			},
+		},
+		{
+			name: "the fix versions of all aliases are kept",
+			matches: []Match{
+				{Package: openssl, Vulnerability: withFix(vuln("CVE-2021-3711", "alpine:distro:alpine:3.14"), grypeDb.NotFixedState)},
+				{Package: openssl, Vulnerability: withFix(vuln("GHSA-xxxx-xxxx-xxxx", "github:language:rust", ref("CVE-2021-3711", "nvd")), grypeDb.FixedState, "1.1.1l")},
+				{Package: openssl, Vulnerability: withFix(vuln("CVE-2021-3711", "nvd"), grypeDb.FixedState, "1.1.1l", "3.0.0")},
+			},
+			expected: []Match{
+				{
+					Package: openssl,
+					Vulnerability: withFix(vuln("CVE-2021-3711", "alpine:distro:alpine:3.14",
+						ref("GHSA-xxxx-xxxx-xxxx", "github:language:rust"),
+						ref("CVE-2021-3711", "nvd"),
+					), grypeDb.FixedState, "1.1.1l", "3.0.0"),
+				},
+			},
+		},
+		{
+			name: "unrelated vulnerabilities and other packages are kept apart",
+			matches: []Match{
+				{Package: lodash, Vulnerability: vuln("CVE-2020-8203", "nvd")},
+				{Package: lodash, Vulnerability: vuln("CVE-2021-23337", "nvd")},
+				{Package: openssl, Vulnerability: vuln("CVE-2020-8203", "nvd")},
+			},
+			expected: []Match{
+				{Package: lodash, Vulnerability: vuln("CVE-2020-8203", "nvd")},
+				{Package: lodash, Vulnerability: vuln("CVE-2021-23337", "nvd")},
+				{Package: openssl, Vulnerability: vuln("CVE-2020-8203", "nvd")},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := MergeAliases(NewMatches(test.matches...))
+			assert.ElementsMatch(t, test.expected, actual.Sorted())
+		})
+	}
+}
+
+func TestIgnoreRuleAppliesToRelatedVulnerabilities(t *testing.T) {
+	m := Match{
+		Package: pkg.Package{ID: "lodash", Name: "lodash", Version: "4.17.15", Type: syftPkg.NpmPkg},
+		Vulnerability: vulnerability.Vulnerability{
+			ID:                     "GHSA-p6mc-m468-83gw",
+			Namespace:              "github:language:javascript",
+			RelatedVulnerabilities: []vulnerability.Reference{{ID: "CVE-2020-8203", Namespace: "nvd"}},
+		},
+	}
+
+	tests := []struct {
+		rule    IgnoreRule
+		ignored bool
+	}{
+		{rule: IgnoreRule{Vulnerability: "GHSA-p6mc-m468-83gw"}, ignored: true},
+		{rule: IgnoreRule{Vulnerability: "GHSA-p6mc-m468-83gw", IncludeAliases: true}, ignored: true},
+		// the rule of a related vulnerability only applies when asked to
+		{rule: IgnoreRule{Vulnerability: "CVE-2020-8203"}, ignored: false},
+		{rule: IgnoreRule{Vulnerability: "CVE-2020-8203", IncludeAliases: true}, ignored: true},
+		{rule: IgnoreRule{Vulnerability: "CVE-2021-23337", IncludeAliases: true}, ignored: false},
+	}
+
+	for _, test := range tests {
+		remaining, ignored := ApplyIgnoreRules(NewMatches(m), []IgnoreRule{test.rule})
+		if test.ignored {
+			assert.Equal(t, 0, remaining.Count(), "%+v", test.rule)
+			assert.Len(t, ignored, 1, "%+v", test.rule)
+		} else {
+			assert.Equal(t, 1, remaining.Count(), "%+v", test.rule)
+			assert.Empty(t, ignored, "%+v", test.rule)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package match
+
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/pkg"
+)
+
+// BaseImageIgnoreRules returns rules that ignore the matches of the packages of the given base image (identified by
+// the given reference). Packages inherited from a base image are fixed by updating the base image (not within the image
+// built upon it), so these rules leave only the matches that the authors of the image itself are able to fix.
+func BaseImageIgnoreRules(reference string, basePackages []pkg.Package) []IgnoreRule {
+	type key struct {
+		name, version, pkgType string
+	}
+
+	seen := make(map[key]struct{})
+	var rules []IgnoreRule
+	for _, p := range basePackages {
+		k := key{name: p.Name, version: p.Version, pkgType: string(p.Type)}
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+
+		rules = append(rules, IgnoreRule{
+			Reason: fmt.Sprintf("inherited from base image %s", reference),
+			Package: IgnoreRulePackage{
+				Name:    p.Name,
+				Version: p.Version,
+				Type:    string(p.Type),
+			},
+		})
+	}
+	return rules
+}
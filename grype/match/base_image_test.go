@@ -0,0 +1,47 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestBaseImageIgnoreRules(t *testing.T) {
+	musl := pkg.Package{ID: pkg.ID(uuid.NewString()), Name: "musl", Version: "1.2.2-r0", Type: syftPkg.ApkPkg}
+	curl := pkg.Package{ID: pkg.ID(uuid.NewString()), Name: "curl", Version: "7.79.1-r0", Type: syftPkg.ApkPkg}
+	app := pkg.Package{ID: pkg.ID(uuid.NewString()), Name: "log4j-core", Version: "2.14.1", Type: syftPkg.JavaPkg}
+	// the same package as within the base image, at another location
+	baseMusl := musl
+	baseMusl.ID = pkg.ID(uuid.NewString())
+
+	rules := BaseImageIgnoreRules("alpine:3.14", []pkg.Package{baseMusl, baseMusl, curl})
+	assert.Len(t, rules, 2)
+	assert.Equal(t, "inherited from base image alpine:3.14", rules[0].Reason)
+
+	// the application installed a newer curl than the base image had
+	newerCurl := curl
+	newerCurl.ID = pkg.ID(uuid.NewString())
+	newerCurl.Version = "7.80.0-r0"
+
+	matches := NewMatches(
+		Match{Vulnerability: vulnerability.Vulnerability{ID: "CVE-2021-1"}, Package: musl},
+		Match{Vulnerability: vulnerability.Vulnerability{ID: "CVE-2021-2"}, Package: newerCurl},
+		Match{Vulnerability: vulnerability.Vulnerability{ID: "CVE-2021-3"}, Package: app},
+	)
+	remaining, ignored := ApplyIgnoreRules(matches, rules)
+
+	var remainingIDs []string
+	for _, m := range remaining.Sorted() {
+		remainingIDs = append(remainingIDs, m.Vulnerability.ID)
+	}
+	assert.ElementsMatch(t, []string{"CVE-2021-2", "CVE-2021-3"}, remainingIDs)
+	if assert.Len(t, ignored, 1) {
+		assert.Equal(t, "CVE-2021-1", ignored[0].Vulnerability.ID)
+		assert.Equal(t, "inherited from base image alpine:3.14", ignored[0].AppliedIgnoreRules[0].Reason)
+	}
+}
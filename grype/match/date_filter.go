@@ -0,0 +1,71 @@
+package match
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// DateFilter selects matches by when their vulnerability was published or last modified (e.g. to focus on newly
+// disclosed vulnerabilities), where a nil bound does not filter.
+type DateFilter struct {
+	PublishedAfter *time.Time
+	ModifiedAfter  *time.Time
+}
+
+// IsEmpty indicates whether the filter selects every match.
+func (f DateFilter) IsEmpty() bool {
+	return f.PublishedAfter == nil && f.ModifiedAfter == nil
+}
+
+// FilterByDate returns the matches that the given filter selects. The dates of a vulnerability are those of its
+// metadata, falling back to the metadata of its related vulnerabilities (e.g. the GHSA of a CVE), and matches of
+// vulnerabilities without a known date are not selected by a bound on that date.
+func FilterByDate(matches Matches, metadataProvider vulnerability.MetadataProvider, filter DateFilter) (Matches, error) {
+	if filter.IsEmpty() {
+		return matches, nil
+	}
+
+	result := NewMatches()
+	for _, m := range matches.Sorted() {
+		published, modified, err := vulnerabilityDates(m.Vulnerability, metadataProvider)
+		if err != nil {
+			return NewMatches(), err
+		}
+		if filter.PublishedAfter != nil && (published == nil || !published.After(*filter.PublishedAfter)) {
+			continue
+		}
+		if filter.ModifiedAfter != nil && (modified == nil || !modified.After(*filter.ModifiedAfter)) {
+			continue
+		}
+		result.Add(m)
+	}
+	return result, nil
+}
+
+// vulnerabilityDates returns when the vulnerability was published and last modified (nil when unknown).
+func vulnerabilityDates(v vulnerability.Vulnerability, metadataProvider vulnerability.MetadataProvider) (*time.Time, *time.Time, error) {
+	refs := append([]vulnerability.Reference{{ID: v.ID, Namespace: v.Namespace}}, v.RelatedVulnerabilities...)
+
+	var published, modified *time.Time
+	for _, ref := range refs {
+		if published != nil && modified != nil {
+			break
+		}
+		metadata, err := metadataProvider.GetMetadata(ref.ID, ref.Namespace)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to fetch vuln=%q metadata: %w", ref.ID, err)
+		}
+		if metadata == nil {
+			continue
+		}
+		if published == nil {
+			published = metadata.Published
+		}
+		if modified == nil {
+			modified = metadata.Modified
+		}
+	}
+	return published, modified, nil
+}
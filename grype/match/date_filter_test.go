@@ -0,0 +1,93 @@
+package match
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// datedMetadata provides metadata with the given publication and modification dates of vulnerabilities (by ID).
+type datedMetadata map[string][2]*time.Time
+
+func (m datedMetadata) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	dates, ok := m[id]
+	if !ok {
+		return nil, nil
+	}
+	return &vulnerability.Metadata{ID: id, Namespace: namespace, Published: dates[0], Modified: dates[1]}, nil
+}
+
+func TestFilterByDate(t *testing.T) {
+	date := func(value string) *time.Time {
+		d, err := time.Parse("2006-01-02", value)
+		require.NoError(t, err)
+		return &d
+	}
+
+	p := pkg.Package{ID: "lodash", Name: "lodash", Version: "4.17.15", Type: syftPkg.NpmPkg}
+	newMatch := func(id string, related ...string) Match {
+		v := vulnerability.Vulnerability{ID: id, Namespace: "github:language:javascript"}
+		for _, r := range related {
+			v.RelatedVulnerabilities = append(v.RelatedVulnerabilities, vulnerability.Reference{ID: r, Namespace: "nvd"})
+		}
+		return Match{Package: p, Vulnerability: v}
+	}
+
+	metadata := datedMetadata{
+		"GHSA-old":     {date("2020-07-15"), date("2021-09-16")},
+		"GHSA-new":     {date("2024-02-01"), date("2024-02-03")},
+		"GHSA-undated": {nil, nil},
+		"CVE-2024-1":   {date("2024-01-20"), date("2024-01-22")},
+	}
+	matches := NewMatches(
+		newMatch("GHSA-old"),
+		newMatch("GHSA-new"),
+		newMatch("GHSA-undated"),
+		newMatch("GHSA-aliased", "CVE-2024-1"),
+	)
+
+	tests := []struct {
+		name     string
+		filter   DateFilter
+		expected []string
+	}{
+		{
+			name:     "no filter",
+			expected: []string{"GHSA-aliased", "GHSA-new", "GHSA-old", "GHSA-undated"},
+		},
+		{
+			name:     "published after",
+			filter:   DateFilter{PublishedAfter: date("2024-01-01")},
+			expected: []string{"GHSA-aliased", "GHSA-new"},
+		},
+		{
+			name:     "modified after",
+			filter:   DateFilter{ModifiedAfter: date("2024-02-01")},
+			expected: []string{"GHSA-new"},
+		},
+		{
+			name:     "published and modified after",
+			filter:   DateFilter{PublishedAfter: date("2020-01-01"), ModifiedAfter: date("2021-01-01")},
+			expected: []string{"GHSA-aliased", "GHSA-new", "GHSA-old"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := FilterByDate(matches, metadata, test.filter)
+			require.NoError(t, err)
+
+			var ids []string
+			for _, m := range actual.Sorted() {
+				ids = append(ids, m.Vulnerability.ID)
+			}
+			assert.ElementsMatch(t, test.expected, ids)
+		})
+	}
+}
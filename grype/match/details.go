@@ -3,6 +3,7 @@ package match
 import (
 	"fmt"
 
+	"github.com/anchore/grype/grype/pkg"
 	"github.com/mitchellh/hashstructure/v2"
 )
 
@@ -31,6 +32,45 @@ func (m Details) Matchers() (tys []MatcherType) {
 	return tys
 }
 
+// Upstreams returns the upstream packages (e.g. the source package of a dpkg binary package) that were searched to make
+// the indirect matches. Nothing is returned if any of the details indicate that a direct match was made.
+func (m Details) Upstreams() (upstreams []pkg.UpstreamPackage) {
+	seen := make(map[pkg.UpstreamPackage]struct{})
+	for _, d := range m {
+		switch d.Type {
+		case ExactDirectMatch:
+			return nil
+		case ExactIndirectMatch:
+			upstream, ok := searchedUpstream(d.SearchedBy)
+			if !ok {
+				continue
+			}
+			if _, exists := seen[upstream]; exists {
+				continue
+			}
+			seen[upstream] = struct{}{}
+			upstreams = append(upstreams, upstream)
+		}
+	}
+	return upstreams
+}
+
+// searchedUpstream extracts the package name and version that was searched with (as recorded by the distro search).
+func searchedUpstream(searchedBy interface{}) (pkg.UpstreamPackage, bool) {
+	fields, ok := searchedBy.(map[string]interface{})
+	if !ok {
+		return pkg.UpstreamPackage{}, false
+	}
+	p, ok := fields["package"].(map[string]string)
+	if !ok || p["name"] == "" {
+		return pkg.UpstreamPackage{}, false
+	}
+	return pkg.UpstreamPackage{
+		Name:    p["name"],
+		Version: p["version"],
+	}, true
+}
+
 func (m Details) Types() (tys []Type) {
 	if len(m) == 0 {
 		return nil
@@ -0,0 +1,81 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/pkg"
+)
+
+func searchedByPackage(name, version string) map[string]interface{} {
+	return map[string]interface{}{
+		"distro": map[string]string{
+			"type":    "debian",
+			"version": "10",
+		},
+		"package": map[string]string{
+			"name":    name,
+			"version": version,
+		},
+		"namespace": "debian:10",
+	}
+}
+
+func TestDetails_Upstreams(t *testing.T) {
+	tests := []struct {
+		name     string
+		details  Details
+		expected []pkg.UpstreamPackage
+	}{
+		{
+			name: "direct match only",
+			details: Details{
+				{Type: ExactDirectMatch, SearchedBy: searchedByPackage("libssl1.1", "1.1.1d-0+deb10u7")},
+			},
+		},
+		{
+			name: "indirect match",
+			details: Details{
+				{Type: ExactIndirectMatch, SearchedBy: searchedByPackage("openssl", "1.1.1d-0+deb10u7")},
+			},
+			expected: []pkg.UpstreamPackage{
+				{
+					Name:    "openssl",
+					Version: "1.1.1d-0+deb10u7",
+				},
+			},
+		},
+		{
+			name: "duplicate indirect matches",
+			details: Details{
+				{Type: ExactIndirectMatch, SearchedBy: searchedByPackage("openssl", "1.1.1d-0+deb10u7")},
+				{Type: ExactIndirectMatch, SearchedBy: searchedByPackage("openssl", "1.1.1d-0+deb10u7")},
+			},
+			expected: []pkg.UpstreamPackage{
+				{
+					Name:    "openssl",
+					Version: "1.1.1d-0+deb10u7",
+				},
+			},
+		},
+		{
+			name: "indirect and direct match",
+			details: Details{
+				{Type: ExactIndirectMatch, SearchedBy: searchedByPackage("openssl", "1.1.1d-0+deb10u7")},
+				{Type: ExactDirectMatch, SearchedBy: searchedByPackage("libssl1.1", "1.1.1d-0+deb10u7")},
+			},
+		},
+		{
+			name: "indirect match without package information",
+			details: Details{
+				{Type: ExactIndirectMatch, SearchedBy: map[string]interface{}{"language": "java"}},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.details.Upstreams())
+		})
+	}
+}
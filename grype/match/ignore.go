@@ -1,9 +1,17 @@
 package match
 
 import (
+	"strings"
+
 	"github.com/bmatcuk/doublestar/v2"
+
+	grypeDb "github.com/anchore/grype/grype/db/v3"
 )
 
+// UntilFixedReason is the reason of an ignore rule that only applies to matches without a fix, so that the matches of
+// the rule resurface once a fix is published (instead of being ignored for good).
+const UntilFixedReason = "until-fixed"
+
 // An IgnoredMatch is a vulnerability Match that has been ignored because one or more IgnoreRules applied to the match.
 type IgnoredMatch struct {
 	Match
@@ -17,9 +25,14 @@ type IgnoredMatch struct {
 // specified criteria must be met by the vulnerability match in order for the
 // rule to apply.
 type IgnoreRule struct {
-	Vulnerability string            `yaml:"vulnerability" json:"vulnerability" mapstructure:"vulnerability"`
-	FixState      string            `yaml:"fix-state" json:"fix-state" mapstructure:"fix-state"`
-	Package       IgnoreRulePackage `yaml:"package" json:"package" mapstructure:"package"`
+	Vulnerability string `yaml:"vulnerability" json:"vulnerability" mapstructure:"vulnerability"`
+	// IncludeAliases applies the rule of the vulnerability to matches of which it is a related vulnerability (e.g. the
+	// match of a GHSA, for a rule of the CVE it is an alias of), instead of only to matches of the vulnerability itself
+	IncludeAliases bool              `yaml:"include-aliases" json:"include-aliases" mapstructure:"include-aliases"`
+	FixState       string            `yaml:"fix-state" json:"fix-state" mapstructure:"fix-state"`
+	Package        IgnoreRulePackage `yaml:"package" json:"package" mapstructure:"package"`
+	// Reason describes why matches are ignored (which is not a criterion of the rule, except for the UntilFixedReason)
+	Reason string `yaml:"reason" json:"reason" mapstructure:"reason"`
 }
 
 // IgnoreRulePackage describes the Package-specific fields that comprise the IgnoreRule.
@@ -28,6 +41,7 @@ type IgnoreRulePackage struct {
 	Version  string `yaml:"version" json:"version" mapstructure:"version"`
 	Type     string `yaml:"type" json:"type" mapstructure:"type"`
 	Location string `yaml:"location" json:"location" mapstructure:"location"`
+	PURL     string `yaml:"purl" json:"purl" mapstructure:"purl"`
 }
 
 // ApplyIgnoreRules iterates through the provided matches and, for each match,
@@ -90,7 +104,7 @@ func getIgnoreConditionsForRule(rule IgnoreRule) []ignoreCondition {
 	var ignoreConditions []ignoreCondition
 
 	if v := rule.Vulnerability; v != "" {
-		ignoreConditions = append(ignoreConditions, ifVulnerabilityApplies(v))
+		ignoreConditions = append(ignoreConditions, ifVulnerabilityApplies(v, rule.IncludeAliases))
 	}
 
 	if n := rule.Package.Name; n != "" {
@@ -109,10 +123,20 @@ func getIgnoreConditionsForRule(rule IgnoreRule) []ignoreCondition {
 		ignoreConditions = append(ignoreConditions, ifPackageLocationApplies(l))
 	}
 
+	if p := rule.Package.PURL; p != "" {
+		ignoreConditions = append(ignoreConditions, ifPackagePURLApplies(p))
+	}
+
 	if fs := rule.FixState; fs != "" {
 		ignoreConditions = append(ignoreConditions, ifFixStateApplies(fs))
 	}
 
+	// note: the reason only narrows down the matches of the other criteria, so a rule without criteria still does not
+	// apply to any match
+	if len(ignoreConditions) > 0 && strings.EqualFold(strings.TrimSpace(rule.Reason), UntilFixedReason) {
+		ignoreConditions = append(ignoreConditions, ifNotFixedApplies())
+	}
+
 	return ignoreConditions
 }
 
@@ -122,9 +146,27 @@ func ifFixStateApplies(fs string) ignoreCondition {
 	}
 }
 
-func ifVulnerabilityApplies(vulnerability string) ignoreCondition {
+// ifNotFixedApplies matches the matches of which the vulnerability has no fix (by the fix state or the fix versions).
+func ifNotFixedApplies() ignoreCondition {
 	return func(match Match) bool {
-		return vulnerability == match.Vulnerability.ID
+		return match.Vulnerability.Fix.State != grypeDb.FixedState && len(match.Vulnerability.Fix.Versions) == 0
+	}
+}
+
+// ifVulnerabilityApplies matches the vulnerability of the match by its ID, or, when aliases are included, by the ID of
+// any of its related vulnerabilities (e.g. so that a rule of a CVE applies when the match was merged into the match of
+// an alias of it).
+func ifVulnerabilityApplies(vulnerability string, includeAliases bool) ignoreCondition {
+	return func(match Match) bool {
+		if !includeAliases {
+			return vulnerability == match.Vulnerability.ID
+		}
+		for _, id := range vulnerabilityIDs(match.Vulnerability) {
+			if vulnerability == id {
+				return true
+			}
+		}
+		return false
 	}
 }
 
@@ -152,6 +194,14 @@ func ifPackageLocationApplies(location string) ignoreCondition {
 	}
 }
 
+// ifPackagePURLApplies matches the package URL of the package by a glob expression (e.g. "pkg:maven/org.acme/*").
+func ifPackagePURLApplies(purl string) ignoreCondition {
+	return func(match Match) bool {
+		doesMatch, err := doublestar.Match(purl, match.Package.PURL)
+		return err == nil && doesMatch
+	}
+}
+
 func ruleLocationAppliesToMatch(location string, match Match) bool {
 	for _, packageLocation := range match.Package.Locations {
 		if ruleLocationAppliesToPath(location, packageLocation.RealPath) {
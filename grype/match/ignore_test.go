@@ -260,6 +260,7 @@ var (
 				source.NewVirtualLocation("/some/path", "/some/virtual/path"),
 			},
 			Type: "rpm",
+			PURL: "pkg:rpm/a-pkg@1.0",
 		},
 	}
 )
@@ -345,6 +346,26 @@ func TestShouldIgnore(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name:  "rule applies via package URL glob",
+			match: exampleMatch,
+			rule: IgnoreRule{
+				Package: IgnoreRulePackage{
+					PURL: "pkg:rpm/a-pkg@*",
+				},
+			},
+			expected: true,
+		},
+		{
+			name:  "rule doesn't apply via another package URL",
+			match: exampleMatch,
+			rule: IgnoreRule{
+				Package: IgnoreRulePackage{
+					PURL: "pkg:rpm/b-pkg@*",
+				},
+			},
+			expected: false,
+		},
 		{
 			name:  "rule applies via multiple fields",
 			match: exampleMatch,
@@ -368,6 +389,49 @@ func TestShouldIgnore(t *testing.T) {
 			},
 			expected: false,
 		},
+		{
+			name:  "until fixed rule applies to a match without a fix",
+			match: exampleMatch,
+			rule: IgnoreRule{
+				Vulnerability: exampleMatch.Vulnerability.ID,
+				Reason:        UntilFixedReason,
+			},
+			expected: true,
+		},
+		{
+			name: "until fixed rule doesn't apply once there is a fix",
+			match: Match{
+				Vulnerability: vulnerability.Vulnerability{
+					ID: exampleMatch.Vulnerability.ID,
+					Fix: vulnerability.Fix{
+						State:    grypeDb.FixedState,
+						Versions: []string{"1.1"},
+					},
+				},
+				Package: exampleMatch.Package,
+			},
+			rule: IgnoreRule{
+				Vulnerability: exampleMatch.Vulnerability.ID,
+				Reason:        UntilFixedReason,
+			},
+			expected: false,
+		},
+		{
+			name:  "until fixed rule without criteria",
+			match: exampleMatch,
+			rule: IgnoreRule{
+				Reason: UntilFixedReason,
+			},
+			expected: false,
+		},
+		{
+			name:  "other reasons are not a criterion",
+			match: exampleMatch,
+			rule: IgnoreRule{
+				Reason: "not exploitable",
+			},
+			expected: false,
+		},
 	}
 
 	for _, testCase := range cases {
@@ -11,6 +11,21 @@ const (
 	PythonMatcher      MatcherType = "python-matcher"
 	JavascriptMatcher  MatcherType = "javascript-matcher"
 	MsrcMatcher        MatcherType = "msrc-matcher"
+	GoModuleMatcher    MatcherType = "go-module-matcher"
+	RustMatcher        MatcherType = "rust-matcher"
+	DotnetMatcher      MatcherType = "dotnet-matcher"
+	PhpComposerMatcher MatcherType = "php-composer-matcher"
+	DartPubMatcher     MatcherType = "dart-pub-matcher"
+	SwiftMatcher       MatcherType = "swift-matcher"
+	HexMatcher         MatcherType = "hex-matcher"
+	CondaMatcher       MatcherType = "conda-matcher"
+	RPackageMatcher    MatcherType = "r-package-matcher"
+	HaskellMatcher     MatcherType = "haskell-matcher"
+	BitnamiMatcher     MatcherType = "bitnami-matcher"
+	LinuxKernelMatcher MatcherType = "linux-kernel-matcher"
+	BinaryMatcher      MatcherType = "binary-matcher"
+	HomebrewMatcher    MatcherType = "homebrew-matcher"
+	ChocolateyMatcher  MatcherType = "chocolatey-matcher"
 )
 
 var AllMatcherTypes = []MatcherType{
@@ -22,6 +37,21 @@ var AllMatcherTypes = []MatcherType{
 	PythonMatcher,
 	JavascriptMatcher,
 	MsrcMatcher,
+	GoModuleMatcher,
+	RustMatcher,
+	DotnetMatcher,
+	PhpComposerMatcher,
+	DartPubMatcher,
+	SwiftMatcher,
+	HexMatcher,
+	CondaMatcher,
+	RPackageMatcher,
+	HaskellMatcher,
+	BitnamiMatcher,
+	LinuxKernelMatcher,
+	BinaryMatcher,
+	HomebrewMatcher,
+	ChocolateyMatcher,
 }
 
 type MatcherType string
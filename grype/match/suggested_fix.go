@@ -0,0 +1,107 @@
+package match
+
+import (
+	grypeDb "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/internal/log"
+)
+
+// SuggestedFixes returns the suggested fix of each package of the given matches that has one, keyed by the ID of the
+// package (see SuggestedFix).
+func SuggestedFixes(matches Matches) map[pkg.ID]string {
+	byPackage := make(map[pkg.ID][]Match)
+	packages := make(map[pkg.ID]pkg.Package)
+	for m := range matches.Enumerate() {
+		byPackage[m.Package.ID] = append(byPackage[m.Package.ID], m)
+		packages[m.Package.ID] = m.Package
+	}
+
+	suggestions := make(map[pkg.ID]string)
+	for id, packageMatches := range byPackage {
+		if suggestion := SuggestedFix(packages[id], packageMatches); suggestion != "" {
+			suggestions[id] = suggestion
+		}
+	}
+	return suggestions
+}
+
+// SuggestedFix returns the lowest version that the package can be upgraded to that fixes every one of the given matches
+// (of the package) that has a fix: for each vulnerability this is the lowest fix version above the installed version,
+// and the suggestion is the highest of these versions. Vulnerabilities without a fix above the installed version cannot
+// be fixed by an upgrade, so these are left out (where no suggestion is made when none of the vulnerabilities have one).
+// Matches of an upstream package with another version (e.g. the source package of a distro package) are left out as
+// well, since their fixes are versions of the upstream package instead of versions of the package.
+func SuggestedFix(p pkg.Package, matches []Match) string {
+	format := version.FormatFromPkg(p)
+
+	var suggestion string
+	for _, m := range matches {
+		if fixedVersionOf(p, m) != p.Version {
+			continue
+		}
+		lowest := lowestFix(m, p.Version, format)
+		if lowest != "" && (suggestion == "" || isHigherVersion(lowest, suggestion, format)) {
+			suggestion = lowest
+		}
+	}
+	return suggestion
+}
+
+// Fixable indicates whether the vulnerability of the match is fixed in a version above the version that was matched,
+// which is the version of the upstream package for matches of an upstream package. Vulnerabilities that are fixed
+// without a known fix version are considered to be fixable.
+func Fixable(m Match) bool {
+	if len(m.Vulnerability.Fix.Versions) == 0 {
+		return m.Vulnerability.Fix.State == grypeDb.FixedState
+	}
+	return lowestFix(m, fixedVersionOf(m.Package, m), version.FormatFromPkg(m.Package)) != ""
+}
+
+// fixedVersionOf returns the version that the fixes of the match are versions of: the version of the package, or the
+// version of the upstream package for matches of an upstream package (e.g. the source package of a distro package).
+func fixedVersionOf(p pkg.Package, m Match) string {
+	for _, u := range m.Details.Upstreams() {
+		if u.Version != "" {
+			return u.Version
+		}
+	}
+	return p.Version
+}
+
+// lowestFix returns the lowest fix version of the vulnerability of the match above the given version.
+func lowestFix(m Match, installed string, format version.Format) string {
+	var lowest string
+	for _, fix := range m.Vulnerability.Fix.Versions {
+		if !isHigherVersion(fix, installed, format) {
+			// note: a fix of another release line (e.g. 1.2.5 for 2.0.1) does not fix the installed version
+			continue
+		}
+		if lowest == "" || isHigherVersion(lowest, fix, format) {
+			lowest = fix
+		}
+	}
+	return lowest
+}
+
+// isHigherVersion indicates whether version a is higher than version b, where versions that cannot be compared (in the
+// version format of the package, or otherwise as loosely formatted versions) are not higher.
+func isHigherVersion(a, b string, format version.Format) bool {
+	for _, f := range []version.Format{format, version.UnknownFormat} {
+		constraint, err := version.GetConstraint("> "+b, f)
+		if err != nil {
+			continue
+		}
+		v, err := version.NewVersion(a, f)
+		if err != nil {
+			continue
+		}
+		higher, err := constraint.Satisfied(v)
+		if err != nil {
+			continue
+		}
+		return higher
+	}
+	log.Debugf("unable to compare versions %q and %q (format=%s)", a, b, format)
+	return false
+}
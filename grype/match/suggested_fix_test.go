@@ -0,0 +1,148 @@
+package match
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestSuggestedFix(t *testing.T) {
+	newMatch := func(id string, fixes ...string) Match {
+		return Match{Vulnerability: vulnerability.Vulnerability{ID: id, Fix: vulnerability.Fix{Versions: fixes}}}
+	}
+
+	tests := []struct {
+		name     string
+		version  string
+		matches  []Match
+		expected string
+	}{
+		{
+			name:     "highest of the fixes of each vulnerability",
+			version:  "4.17.15",
+			matches:  []Match{newMatch("CVE-1", "4.17.19"), newMatch("CVE-2", "4.17.21"), newMatch("CVE-3", "4.17.16")},
+			expected: "4.17.21",
+		},
+		{
+			name:     "lowest fix above the installed version of a vulnerability",
+			version:  "2.0.1",
+			matches:  []Match{newMatch("CVE-1", "1.2.5", "2.0.5", "3.0.0"), newMatch("CVE-2", "2.0.3")},
+			expected: "2.0.5",
+		},
+		{
+			name:     "versions are compared by the version format of the package",
+			version:  "1.9.0",
+			matches:  []Match{newMatch("CVE-1", "1.10.0"), newMatch("CVE-2", "1.9.1")},
+			expected: "1.10.0",
+		},
+		{
+			name:     "vulnerabilities without a fix are left out",
+			version:  "1.0.0",
+			matches:  []Match{newMatch("CVE-1"), newMatch("CVE-2", "1.0.1")},
+			expected: "1.0.1",
+		},
+		{
+			name:     "no fix above the installed version",
+			version:  "1.0.0",
+			matches:  []Match{newMatch("CVE-1"), newMatch("CVE-2", "0.9.0")},
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := pkg.Package{ID: "lodash", Name: "lodash", Version: test.version, Type: syftPkg.NpmPkg}
+			assert.Equal(t, test.expected, SuggestedFix(p, test.matches))
+		})
+	}
+}
+
+func TestSuggestedFix_UpstreamMatches(t *testing.T) {
+	// a binary package of a rebuild of the source package, which has another version than the source package
+	p := pkg.Package{
+		ID:        "libssl",
+		Name:      "libssl1.1",
+		Version:   "1.1.1k-1+b1",
+		Type:      syftPkg.DebPkg,
+		Upstreams: []pkg.UpstreamPackage{{Name: "openssl", Version: "1.1.1k-1"}},
+	}
+	upstreamMatch := func(id, upstreamVersion string, fixes ...string) Match {
+		return Match{
+			Package:       p,
+			Vulnerability: vulnerability.Vulnerability{ID: id, Fix: vulnerability.Fix{Versions: fixes}},
+			Details: Details{
+				{
+					Type: ExactIndirectMatch,
+					SearchedBy: map[string]interface{}{
+						"package": map[string]string{"name": "openssl", "version": upstreamVersion},
+					},
+				},
+			},
+		}
+	}
+	directMatch := func(id string, fixes ...string) Match {
+		return Match{
+			Package:       p,
+			Vulnerability: vulnerability.Vulnerability{ID: id, Fix: vulnerability.Fix{Versions: fixes}},
+			Details:       Details{{Type: ExactDirectMatch}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		matches  []Match
+		expected string
+		fixable  []bool
+	}{
+		{
+			name:     "fixes of the source package are not versions of the binary package",
+			matches:  []Match{upstreamMatch("CVE-1", "1.1.1k-1", "1.1.1k-2")},
+			expected: "",
+			fixable:  []bool{true},
+		},
+		{
+			name:     "fixes of the source package are compared with the version of the source package",
+			matches:  []Match{upstreamMatch("CVE-1", "1.1.1k-1", "1.1.1k-1")},
+			expected: "",
+			fixable:  []bool{false},
+		},
+		{
+			name:     "only direct matches are suggested",
+			matches:  []Match{upstreamMatch("CVE-1", "1.1.1k-1", "1.1.1l-1"), directMatch("CVE-2", "1.1.1k-1+b2")},
+			expected: "1.1.1k-1+b2",
+			fixable:  []bool{true, true},
+		},
+		{
+			name:     "upstreams of the same version are compared with the version of the package",
+			matches:  []Match{upstreamMatch("CVE-1", "1.1.1k-1+b1", "1.1.1k-2")},
+			expected: "1.1.1k-2",
+			fixable:  []bool{true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, SuggestedFix(p, test.matches))
+			for i, m := range test.matches {
+				assert.Equal(t, test.fixable[i], Fixable(m), m.Vulnerability.ID)
+			}
+		})
+	}
+}
+
+func TestSuggestedFixes(t *testing.T) {
+	lodash := pkg.Package{ID: "lodash", Name: "lodash", Version: "4.17.15", Type: syftPkg.NpmPkg}
+	minimist := pkg.Package{ID: "minimist", Name: "minimist", Version: "1.2.0", Type: syftPkg.NpmPkg}
+
+	matches := NewMatches(
+		Match{Package: lodash, Vulnerability: vulnerability.Vulnerability{ID: "CVE-2020-8203", Fix: vulnerability.Fix{Versions: []string{"4.17.19"}}}},
+		Match{Package: lodash, Vulnerability: vulnerability.Vulnerability{ID: "CVE-2021-23337", Fix: vulnerability.Fix{Versions: []string{"4.17.21"}}}},
+		Match{Package: minimist, Vulnerability: vulnerability.Vulnerability{ID: "CVE-2021-44906"}},
+	)
+
+	assert.Equal(t, map[pkg.ID]string{"lodash": "4.17.21"}, SuggestedFixes(matches))
+}
@@ -462,6 +462,114 @@ func TestNvdMatchesNoConstraintWithSecDBFix(t *testing.T) {
 	}
 }
 
+func TestWolfiSecDBFixOnlyMatch(t *testing.T) {
+	// the wolfi secdb only describes the version that fixes a package
+	secDbVuln := grypeDB.Vulnerability{
+		ID:        "CVE-2023-0286",
+		Namespace: "wolfi:rolling",
+		Fix: grypeDB.Fix{
+			Versions: []string{"3.0.8-r0"},
+			State:    grypeDB.FixedState,
+		},
+	}
+
+	store := mockStore{
+		backend: map[string]map[string][]grypeDB.Vulnerability{
+			"wolfi:rolling": {
+				"openssl": []grypeDB.Vulnerability{secDbVuln},
+			},
+		},
+	}
+
+	provider := db.NewVulnerabilityProvider(&store)
+
+	m := Matcher{}
+	// chainguard images are matched against the wolfi secdb when there is no chainguard data
+	d, err := distro.New(distro.Chainguard, "20230214", "")
+	if err != nil {
+		t.Fatalf("failed to create a new distro: %+v", err)
+	}
+
+	tests := []struct {
+		version  string
+		expected int
+	}{
+		{version: "3.0.7-r1", expected: 1},
+		{version: "3.0.8-r0", expected: 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			p := pkg.Package{
+				ID:      pkg.ID(uuid.NewString()),
+				Name:    "openssl",
+				Version: test.version,
+				Type:    syftPkg.ApkPkg,
+			}
+
+			actual, err := m.Match(provider, d, p)
+			assert.NoError(t, err)
+			assert.Len(t, actual, test.expected)
+			for _, a := range actual {
+				assert.Equal(t, "< 3.0.8-r0 (apk)", a.Vulnerability.Constraint.String())
+				assert.Equal(t, []string{"3.0.8-r0"}, a.Vulnerability.Fix.Versions)
+			}
+		})
+	}
+}
+
+func TestNvdMatchesWithWolfiSecDBNotAffected(t *testing.T) {
+	nvdVuln := grypeDB.Vulnerability{
+		ID:                "CVE-2023-0464",
+		VersionConstraint: "< 3.1.1",
+		VersionFormat:     "unknown",
+		CPEs:              []string{"cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*"},
+		Namespace:         "nvd",
+	}
+
+	// a fixed version of "0" indicates that the package was never affected
+	secDbVuln := grypeDB.Vulnerability{
+		ID:        "CVE-2023-0464",
+		Namespace: "wolfi:rolling",
+		Fix: grypeDB.Fix{
+			Versions: []string{"0"},
+			State:    grypeDB.FixedState,
+		},
+	}
+
+	store := mockStore{
+		backend: map[string]map[string][]grypeDB.Vulnerability{
+			"nvd": {
+				"openssl": []grypeDB.Vulnerability{nvdVuln},
+			},
+			"wolfi:rolling": {
+				"openssl": []grypeDB.Vulnerability{secDbVuln},
+			},
+		},
+	}
+
+	provider := db.NewVulnerabilityProvider(&store)
+
+	m := Matcher{}
+	d, err := distro.New(distro.Wolfi, "", "")
+	if err != nil {
+		t.Fatalf("failed to create a new distro: %+v", err)
+	}
+	p := pkg.Package{
+		ID:      pkg.ID(uuid.NewString()),
+		Name:    "openssl",
+		Version: "3.1.0-r0",
+		Type:    syftPkg.ApkPkg,
+		CPEs: []syftPkg.CPE{
+			must(syftPkg.NewCPE("cpe:2.3:a:openssl:openssl:3.1.0-r0:*:*:*:*:*:*:*")),
+		},
+	}
+
+	actual, err := m.Match(provider, d, p)
+	assert.NoError(t, err)
+	assert.Empty(t, actual)
+}
+
 func TestDistroMatchBySourceIndirection(t *testing.T) {
 
 	secDbVuln := grypeDB.Vulnerability{
@@ -487,10 +595,15 @@ func TestDistroMatchBySourceIndirection(t *testing.T) {
 		t.Fatalf("failed to create a new distro: %+v", err)
 	}
 	p := pkg.Package{
-		ID:       pkg.ID(uuid.NewString()),
-		Name:     "musl-utils",
-		Version:  "1.3.2-r0",
-		Type:     syftPkg.ApkPkg,
+		ID:      pkg.ID(uuid.NewString()),
+		Name:    "musl-utils",
+		Version: "1.3.2-r0",
+		Type:    syftPkg.ApkPkg,
+		Upstreams: []pkg.UpstreamPackage{
+			{
+				Name: "musl",
+			},
+		},
 		Metadata: pkg.ApkMetadata{OriginPackage: "musl"},
 	}
 
@@ -567,6 +680,11 @@ func TestNVDMatchBySourceIndirection(t *testing.T) {
 			must(syftPkg.NewCPE("cpe:2.3:a:musl-utils:musl-utils:*:*:*:*:*:*:*:*")),
 			must(syftPkg.NewCPE("cpe:2.3:a:musl-utils:musl-utils:*:*:*:*:*:*:*:*")),
 		},
+		Upstreams: []pkg.UpstreamPackage{
+			{
+				Name: "musl",
+			},
+		},
 		Metadata: pkg.ApkMetadata{OriginPackage: "musl"},
 	}
 
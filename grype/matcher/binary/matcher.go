@@ -0,0 +1,45 @@
+package binary
+
+import (
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher/stock"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/search"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type Matcher struct {
+}
+
+func (m *Matcher) PackageTypes() []syftPkg.Type {
+	return []syftPkg.Type{pkg.BinaryPkg}
+}
+
+func (m *Matcher) Type() match.MatcherType {
+	return match.BinaryMatcher
+}
+
+// Match searches for vulnerabilities by CPE, but only for the CPEs that are allowed for classified binaries (see
+// pkg.AllowedBinaryCPE). Binary packages that were not classified by grype (e.g. from a provided SBOM) are matched
+// the same way as any other package by the stock matcher.
+func (m *Matcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	if _, ok := p.Metadata.(pkg.BinaryMetadata); !ok {
+		stockMatcher := stock.Matcher{}
+		return stockMatcher.Match(store, d, p)
+	}
+
+	var allowed []syftPkg.CPE
+	for _, c := range p.CPEs {
+		if pkg.AllowedBinaryCPE(c) {
+			allowed = append(allowed, c)
+		}
+	}
+	if len(allowed) == 0 {
+		return nil, nil
+	}
+	p.CPEs = allowed
+
+	return search.ByCriteria(store, d, p, m.Type(), search.ByCPE)
+}
@@ -0,0 +1,115 @@
+package binary
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/db"
+	grypeDB "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/pkg"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type mockStore struct {
+	backend map[string]map[string][]grypeDB.Vulnerability
+}
+
+func (s *mockStore) GetVulnerability(namespace, name string) ([]grypeDB.Vulnerability, error) {
+	namespaceMap := s.backend[namespace]
+	if namespaceMap == nil {
+		return nil, nil
+	}
+	return namespaceMap[name], nil
+}
+
+func TestMatches(t *testing.T) {
+	store := mockStore{
+		backend: map[string]map[string][]grypeDB.Vulnerability{
+			"nvd": {
+				"openssl": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-2021-3711",
+						VersionConstraint: "< 1.1.1l",
+						VersionFormat:     "unknown",
+						CPEs:              []string{"cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*"},
+					},
+					{
+						// a different component with the same product name
+						ID:                "CVE-2009-made-up",
+						VersionConstraint: "< 2.0",
+						VersionFormat:     "unknown",
+						CPEs:              []string{"cpe:2.3:a:openssl_project:openssl:*:*:*:*:*:*:*:*"},
+					},
+				},
+			},
+		},
+	}
+
+	provider := db.NewVulnerabilityProvider(&store)
+
+	tests := []struct {
+		name            string
+		cpes            []string
+		unclassified    bool
+		expectedVulnIDs []string
+	}{
+		{
+			name:            "allowed CPE",
+			cpes:            []string{"cpe:2.3:a:openssl:openssl:1.1.1k:*:*:*:*:*:*:*"},
+			expectedVulnIDs: []string{"CVE-2021-3711"},
+		},
+		{
+			name: "CPEs that are not allowed are ignored",
+			cpes: []string{
+				"cpe:2.3:a:openssl:openssl:1.1.1k:*:*:*:*:*:*:*",
+				"cpe:2.3:a:openssl_project:openssl:1.1.1k:*:*:*:*:*:*:*",
+			},
+			expectedVulnIDs: []string{"CVE-2021-3711"},
+		},
+		{
+			name: "no allowed CPEs",
+			cpes: []string{"cpe:2.3:a:openssl_project:openssl:1.1.1k:*:*:*:*:*:*:*"},
+		},
+		{
+			name:            "all CPEs of binaries that were not classified are searched",
+			cpes:            []string{"cpe:2.3:a:openssl_project:openssl:1.1.1k:*:*:*:*:*:*:*"},
+			unclassified:    true,
+			expectedVulnIDs: []string{"CVE-2009-made-up"},
+		},
+	}
+
+	m := Matcher{}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var cpes []syftPkg.CPE
+			for _, c := range test.cpes {
+				cpes = append(cpes, syftPkg.MustCPE(c))
+			}
+			p := pkg.Package{
+				ID:      pkg.ID(uuid.NewString()),
+				Name:    "openssl",
+				Version: "1.1.1k",
+				Type:    pkg.BinaryPkg,
+				CPEs:    cpes,
+			}
+			if !test.unclassified {
+				p.Metadata = pkg.BinaryMetadata{Classifier: "openssl"}
+			}
+
+			matches, err := m.Match(provider, nil, p)
+			require.NoError(t, err)
+
+			var actualVulnIDs []string
+			for _, a := range matches {
+				actualVulnIDs = append(actualVulnIDs, a.Vulnerability.ID)
+			}
+			sort.Strings(actualVulnIDs)
+
+			assert.Equal(t, test.expectedVulnIDs, actualVulnIDs)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package conda
+
+import (
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/search"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type Matcher struct {
+}
+
+func (m *Matcher) PackageTypes() []syftPkg.Type {
+	return []syftPkg.Type{pkg.CondaPkg}
+}
+
+func (m *Matcher) Type() match.MatcherType {
+	return match.CondaMatcher
+}
+
+// Match searches by language for conda packages of a known language ecosystem (e.g. python packages), all other
+// (native) packages are only searched by CPE.
+func (m *Matcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	if p.Language == "" || p.Language == syftPkg.UnknownLanguage {
+		return search.ByCriteria(store, d, p, m.Type(), search.ByCPE)
+	}
+	return search.ByCriteria(store, d, p, m.Type(), search.CommonCriteria...)
+}
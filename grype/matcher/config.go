@@ -0,0 +1,86 @@
+package matcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+const (
+	// MatchUnknownVersions matches packages with versions that cannot be parsed by CPE, comparing versions generically
+	// (this is the default, so that packages are not silently missed because of a non-conforming version).
+	MatchUnknownVersions UnknownVersionPolicy = "match"
+	// SkipUnknownVersions does not match packages with versions that cannot be parsed.
+	SkipUnknownVersions UnknownVersionPolicy = "skip"
+	// WarnUnknownVersions does not match packages with versions that cannot be parsed, logging a warning for each.
+	WarnUnknownVersions UnknownVersionPolicy = "warn"
+)
+
+var AllUnknownVersionPolicies = []UnknownVersionPolicy{
+	MatchUnknownVersions,
+	SkipUnknownVersions,
+	WarnUnknownVersions,
+}
+
+// UnknownVersionPolicy determines how packages are handled when their version cannot be parsed by the versioning
+// scheme of the package type.
+type UnknownVersionPolicy string
+
+// ParseUnknownVersionPolicy returns the policy for the given (case-insensitive) name.
+func ParseUnknownVersionPolicy(s string) (UnknownVersionPolicy, error) {
+	for _, p := range AllUnknownVersionPolicies {
+		if strings.EqualFold(s, string(p)) {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("unknown version policy %q (options: %v)", s, AllUnknownVersionPolicies)
+}
+
+// Config controls how packages are matched against vulnerabilities.
+type Config struct {
+	// UnknownVersionPolicy determines how packages with versions that cannot be parsed are handled (where the empty
+	// policy matches them)
+	UnknownVersionPolicy UnknownVersionPolicy
+	// CPEStrictness determines how strictly packages are matched against the CPEs of vulnerabilities (where the empty
+	// strictness is loose)
+	CPEStrictness CPEStrictness
+	// Workers is the number of packages that are matched concurrently (where zero or less uses the number of CPUs)
+	Workers int
+	// Matchers configures individual matchers by type (where matchers that are not configured have the defaults)
+	Matchers map[match.MatcherType]MatcherConfig
+	// Plugins are the matchers of external plugins, which match packages of their types in addition to the built-in
+	// matchers (see the plugin package)
+	Plugins []Matcher
+	// MergeAliases merges the matches of a package that refer to the same vulnerability by different IDs (e.g. a GHSA
+	// and the CVE it is an alias of) into a single match, instead of keeping these apart (see match.MergeAliases)
+	MergeAliases bool
+}
+
+// MatcherConfig configures a single matcher, where the zero value is the default configuration.
+type MatcherConfig struct {
+	// Disabled turns the matcher off, so that it does not match any package (for the stock matcher, this includes
+	// packages of types without a matcher and packages with unknown versions)
+	Disabled bool
+	// SkipCPEs does not search for vulnerabilities by the CPEs of packages (e.g. only by the ecosystem of the package)
+	SkipCPEs bool
+}
+
+func DefaultConfig() Config {
+	return Config{
+		UnknownVersionPolicy: MatchUnknownVersions,
+		CPEStrictness:        TargetSoftwareCPEMatching,
+	}
+}
+
+// withoutCPEs is a provider of a matcher that does not search by CPE, which has no vulnerabilities for any CPE (so that
+// the matcher does not query the vulnerabilities of CPEs at all).
+type withoutCPEs struct {
+	vulnerability.Provider
+}
+
+func (withoutCPEs) GetByCPE(syftPkg.CPE) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
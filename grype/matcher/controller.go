@@ -1,22 +1,49 @@
 package matcher
 
 import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/anchore/grype/grype/distro"
 	"github.com/anchore/grype/grype/event"
+	"github.com/anchore/grype/grype/logger"
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/matcher/apk"
+	"github.com/anchore/grype/grype/matcher/binary"
+	"github.com/anchore/grype/grype/matcher/bitnami"
+	"github.com/anchore/grype/grype/matcher/chocolatey"
+	"github.com/anchore/grype/grype/matcher/conda"
+	"github.com/anchore/grype/grype/matcher/dart"
+	"github.com/anchore/grype/grype/matcher/dotnet"
 	"github.com/anchore/grype/grype/matcher/dpkg"
+	"github.com/anchore/grype/grype/matcher/golang"
+	"github.com/anchore/grype/grype/matcher/haskell"
+	"github.com/anchore/grype/grype/matcher/hex"
+	"github.com/anchore/grype/grype/matcher/homebrew"
 	"github.com/anchore/grype/grype/matcher/java"
 	"github.com/anchore/grype/grype/matcher/javascript"
+	"github.com/anchore/grype/grype/matcher/kernel"
 	"github.com/anchore/grype/grype/matcher/msrc"
+	"github.com/anchore/grype/grype/matcher/php"
 	"github.com/anchore/grype/grype/matcher/python"
+	"github.com/anchore/grype/grype/matcher/r"
 	"github.com/anchore/grype/grype/matcher/rpmdb"
 	"github.com/anchore/grype/grype/matcher/ruby"
+	"github.com/anchore/grype/grype/matcher/rust"
 	"github.com/anchore/grype/grype/matcher/stock"
+	"github.com/anchore/grype/grype/matcher/swift"
 	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/search"
+	"github.com/anchore/grype/grype/version"
 	"github.com/anchore/grype/grype/vulnerability"
 	"github.com/anchore/grype/internal/bus"
 	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/grype/internal/tracing"
 	"github.com/anchore/syft/syft/linux"
 	syftPkg "github.com/anchore/syft/syft/pkg"
 	"github.com/wagoodman/go-partybus"
@@ -26,7 +53,7 @@ import (
 var controllerInstance controller
 
 type Monitor struct {
-	PackagesProcessed         progress.Monitorable
+	PackagesProcessed         progress.Monitorable // also a progress.Sizable of the number of packages to match
 	VulnerabilitiesDiscovered progress.Monitorable
 }
 
@@ -50,6 +77,21 @@ func newController() controller {
 	ctrlr.add(&javascript.Matcher{})
 	ctrlr.add(&apk.Matcher{})
 	ctrlr.add(&msrc.Matcher{})
+	ctrlr.add(&golang.Matcher{})
+	ctrlr.add(&rust.Matcher{})
+	ctrlr.add(&dotnet.Matcher{})
+	ctrlr.add(&php.Matcher{})
+	ctrlr.add(&dart.Matcher{})
+	ctrlr.add(&swift.Matcher{})
+	ctrlr.add(&hex.Matcher{})
+	ctrlr.add(&conda.Matcher{})
+	ctrlr.add(&r.Matcher{})
+	ctrlr.add(&haskell.Matcher{})
+	ctrlr.add(&bitnami.Matcher{})
+	ctrlr.add(&kernel.Matcher{})
+	ctrlr.add(&binary.Matcher{})
+	ctrlr.add(&homebrew.Matcher{})
+	ctrlr.add(&chocolatey.Matcher{})
 	return ctrlr
 }
 
@@ -66,8 +108,8 @@ func (c *controller) add(matchers ...Matcher) {
 	}
 }
 
-func (c *controller) trackMatcher() (*progress.Manual, *progress.Manual) {
-	packagesProcessed := progress.Manual{}
+func (c *controller) trackMatcher(packageCount int) (*progress.Manual, *progress.Manual) {
+	packagesProcessed := progress.Manual{Total: int64(packageCount)}
 	vulnerabilitiesDiscovered := progress.Manual{}
 
 	bus.Publish(partybus.Event{
@@ -80,7 +122,10 @@ func (c *controller) trackMatcher() (*progress.Manual, *progress.Manual) {
 	return &packagesProcessed, &vulnerabilitiesDiscovered
 }
 
-func (c *controller) findMatches(provider vulnerability.Provider, release *linux.Release, packages ...pkg.Package) match.Matches {
+func (c *controller) findMatches(spanCtx context.Context, provider vulnerability.Provider, release *linux.Release, cfg Config, packages ...pkg.Package) match.Matches {
+	spanCtx, span := tracing.Start(spanCtx, "match", attribute.Int("grype.packages", len(packages)))
+	defer span.End()
+
 	var err error
 	res := match.NewMatches()
 
@@ -92,50 +137,204 @@ func (c *controller) findMatches(provider vulnerability.Provider, release *linux
 		}
 	}
 
-	packagesProcessed, vulnerabilitiesDiscovered := c.trackMatcher()
+	if batchProvider, ok := provider.(vulnerability.BatchProvider); ok {
+		// retrieve the vulnerabilities of all packages up front, instead of with a query for each package
+		batched, err := batchProvider.WithPackages(d, packages)
+		if err != nil {
+			log.Warnf("unable to retrieve vulnerabilities of all packages at once: %+v", err)
+		} else {
+			provider = batched
+		}
+	}
 
-	defaultMatcher := &stock.Matcher{}
-	for _, p := range packages {
-		packagesProcessed.N++
-		log.Debugf("searching for vulnerability matches for pkg=%s", p)
+	packagesProcessed, vulnerabilitiesDiscovered := c.trackMatcher(len(packages))
+	platforms := newPlatformIndex(d, packages)
+	var spans *matcherSpans
+	if trace.SpanFromContext(spanCtx).IsRecording() {
+		spans = newMatcherSpans()
+	}
 
-		matchers, ok := c.matchers[p.Type]
-		if !ok {
-			matchers = []Matcher{defaultMatcher}
-		}
-		for _, m := range matchers {
-			matches, err := m.Match(provider, d, p)
-			if err != nil {
-				log.Warnf("matcher failed for pkg=%s: %+v", p, err)
-			} else {
-				logMatches(p, matches)
-				res.Add(matches...)
-				vulnerabilitiesDiscovered.N += int64(len(matches))
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(packages) {
+		workers = len(packages)
+	}
+
+	indexes := make(chan int)
+	results := make(chan packageMatches)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				results <- packageMatches{
+					index:   idx,
+					matches: c.matchPackage(provider, d, cfg, platforms, spans, packages[idx]),
+				}
 			}
+		}()
+	}
+	go func() {
+		for idx := range packages {
+			indexes <- idx
 		}
+		close(indexes)
+		wg.Wait()
+		close(results)
+	}()
+
+	// matches are added in the order of the packages (regardless of the order the packages were matched in), since
+	// the result of merging matches depends on the order the matches are added in
+	matchesByPackage := make([][]match.Match, len(packages))
+	for result := range results {
+		matchesByPackage[result.index] = result.matches
+		packagesProcessed.N++
+		vulnerabilitiesDiscovered.N += int64(len(result.matches))
+	}
+	for _, matches := range matchesByPackage {
+		res.Add(matches...)
 	}
 
 	packagesProcessed.SetCompleted()
 	vulnerabilitiesDiscovered.SetCompleted()
 
 	res = match.ApplyExplicitIgnoreRules(res)
+	if cfg.MergeAliases {
+		res = match.MergeAliases(res)
+	}
 
+	spans.record(spanCtx)
+	span.SetAttributes(attribute.Int("grype.matches", res.Count()))
 	return res
 }
 
+// packageMatches are the matches of the package at the given index.
+type packageMatches struct {
+	index   int
+	matches []match.Match
+}
+
+// matchPackage returns the matches of all matchers of the given package, which is safe to call concurrently.
+func (c *controller) matchPackage(provider vulnerability.Provider, d *distro.Distro, cfg Config, platforms platformIndex, spans *matcherSpans, p pkg.Package) []match.Match {
+	log.Debugf("searching for vulnerability matches for pkg=%s", p)
+
+	provider = newCPEGuard(provider, cfg.CPEStrictness, p)
+
+	if _, err := version.NewVersionFromPkg(p); err != nil {
+		matches, err := matchUnknownVersion(provider, d, cfg, p, err)
+		if err != nil {
+			log.Warnf("matcher failed for pkg=%s: %+v", p, err)
+			return nil
+		}
+		matches = platforms.onlyMatchingPlatforms(matches)
+		logMatches(log.Log, p, matches)
+		return matches
+	}
+
+	matchers := append([]Matcher{}, c.matchers[p.Type]...)
+	for _, plugin := range cfg.Plugins {
+		if handlesType(plugin, p.Type) {
+			matchers = append(matchers, plugin)
+		}
+	}
+	// note: the stock matcher only matches packages of types that neither a built-in matcher nor a plugin matches
+	if len(matchers) == 0 {
+		matchers = []Matcher{&stock.Matcher{}}
+	}
+
+	var allMatches []match.Match
+	for _, m := range matchers {
+		matcherConfig := cfg.Matchers[m.Type()]
+		if matcherConfig.Disabled {
+			continue
+		}
+		matcherProvider := provider
+		if matcherConfig.SkipCPEs {
+			matcherProvider = withoutCPEs{Provider: provider}
+		}
+
+		matcherLog := log.WithFields(log.Fields{"matcher": m.Type()})
+		start := time.Now()
+		matches, err := m.Match(matcherProvider, d, p)
+		spans.add(m.Type(), start, len(matches))
+		if err != nil {
+			matcherLog.Warnf("matcher failed for pkg=%s: %+v", p, err)
+			continue
+		}
+		matches = platforms.onlyMatchingPlatforms(matches)
+		logMatches(matcherLog, p, matches)
+		allMatches = append(allMatches, matches...)
+	}
+	return allMatches
+}
+
+func handlesType(m Matcher, t syftPkg.Type) bool {
+	for _, mt := range m.PackageTypes() {
+		if mt == t {
+			return true
+		}
+	}
+	return false
+}
+
+// matchUnknownVersion handles a package with a version that cannot be parsed by the versioning scheme of the package
+// type according to the unknown version policy (where the package is matched by the stock matcher).
+func matchUnknownVersion(provider vulnerability.Provider, d *distro.Distro, cfg Config, p pkg.Package, parseErr error) ([]match.Match, error) {
+	switch cfg.UnknownVersionPolicy {
+	case SkipUnknownVersions:
+		log.Debugf("skipping pkg=%s with an unknown version: %+v", p, parseErr)
+		return nil, nil
+	case WarnUnknownVersions:
+		log.Warnf("skipping pkg=%s with an unknown version: %+v", p, parseErr)
+		return nil, nil
+	default:
+		if stockConfig := cfg.Matchers[match.StockMatcher]; stockConfig.Disabled || stockConfig.SkipCPEs {
+			log.Debugf("skipping pkg=%s with an unknown version (the stock matcher does not match by CPE): %+v", p, parseErr)
+			return nil, nil
+		}
+		log.Debugf("matching pkg=%s with an unknown version by CPE: %+v", p, parseErr)
+		// the versioning scheme of the package type cannot be used, however, the package can still be matched by CPE
+		// with a generic version comparison (which any version can be parsed by)
+		generic := p
+		generic.Type = syftPkg.UnknownPkg
+		matches, err := search.ByCriteria(provider, d, generic, match.StockMatcher, search.ByCPE)
+		if err != nil {
+			return nil, err
+		}
+		for i := range matches {
+			matches[i].Package = p
+		}
+		return matches, nil
+	}
+}
+
+// FindMatches returns the vulnerability matches for the given packages using the default configuration.
 func FindMatches(provider vulnerability.Provider, d *linux.Release, packages ...pkg.Package) match.Matches {
-	return controllerInstance.findMatches(provider, d, packages...)
+	return FindMatchesWithConfig(provider, d, DefaultConfig(), packages...)
+}
+
+func FindMatchesWithConfig(provider vulnerability.Provider, d *linux.Release, cfg Config, packages ...pkg.Package) match.Matches {
+	return FindMatchesContext(context.Background(), provider, d, cfg, packages...)
+}
+
+// FindMatchesContext is FindMatchesWithConfig, where the spans of matching (and of every matcher) are children of the
+// span of the given context.
+func FindMatchesContext(spanCtx context.Context, provider vulnerability.Provider, d *linux.Release, cfg Config, packages ...pkg.Package) match.Matches {
+	return controllerInstance.findMatches(spanCtx, provider, d, cfg, packages...)
 }
 
-func logMatches(p pkg.Package, matches []match.Match) {
+func logMatches(logger logger.Logger, p pkg.Package, matches []match.Match) {
 	if len(matches) > 0 {
-		log.Debugf("found %d vulnerabilities for pkg=%s", len(matches), p)
+		logger.Debugf("found %d vulnerabilities for pkg=%s", len(matches), p)
 		for idx, m := range matches {
 			var branch = "├──"
 			if idx == len(matches)-1 {
 				branch = "└──"
 			}
-			log.Debugf("  %s %s", branch, m.Summary())
+			logger.Debugf("  %s %s", branch, m.Summary())
 		}
 	}
 }
@@ -0,0 +1,322 @@
+package matcher
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type mockCPEProvider struct {
+	vulns []vulnerability.Vulnerability
+}
+
+func (pr *mockCPEProvider) GetByDistro(*distro.Distro, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (pr *mockCPEProvider) GetByLanguage(syftPkg.Language, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (pr *mockCPEProvider) GetByPackageType(syftPkg.Type, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (pr *mockCPEProvider) GetByCPE(c syftPkg.CPE) ([]vulnerability.Vulnerability, error) {
+	var vulns []vulnerability.Vulnerability
+	for _, v := range pr.vulns {
+		for _, vc := range v.CPEs {
+			if vc.Product == c.Product {
+				vulns = append(vulns, v)
+				break
+			}
+		}
+	}
+	return vulns, nil
+}
+
+func TestFindMatches_UnknownVersionPolicy(t *testing.T) {
+	cpe, err := syftPkg.NewCPE("cpe:2.3:a:libfoo:libfoo:*:*:*:*:*:*:*:*")
+	require.NoError(t, err)
+
+	// a version that is not valid semver, which rust packages are versioned by
+	p := pkg.Package{
+		ID:       pkg.ID(uuid.NewString()),
+		Name:     "libfoo",
+		Version:  "nightly-20211012",
+		Type:     syftPkg.RustPkg,
+		Language: syftPkg.Rust,
+		CPEs:     []syftPkg.CPE{cpe},
+	}
+
+	provider := &mockCPEProvider{
+		vulns: []vulnerability.Vulnerability{
+			{
+				Constraint: version.MustGetConstraint("< nightly-20211101", version.UnknownFormat),
+				ID:         "CVE-2021-fake",
+				Namespace:  "nvd",
+				CPEs:       []syftPkg.CPE{cpe},
+			},
+		},
+	}
+
+	tests := []struct {
+		policy   UnknownVersionPolicy
+		expected []string
+	}{
+		{
+			policy:   MatchUnknownVersions,
+			expected: []string{"CVE-2021-fake"},
+		},
+		{
+			policy: SkipUnknownVersions,
+		},
+		{
+			policy: WarnUnknownVersions,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.policy), func(t *testing.T) {
+			cfg := Config{UnknownVersionPolicy: test.policy}
+			matches := FindMatchesWithConfig(provider, nil, cfg, p)
+
+			var actual []string
+			for m := range matches.Enumerate() {
+				actual = append(actual, m.Vulnerability.ID)
+				// the original package (and package type) is always reported
+				assert.Equal(t, p.Type, m.Package.Type)
+				assert.Equal(t, match.StockMatcher, m.Details[0].Matcher)
+			}
+			assert.ElementsMatch(t, test.expected, actual)
+		})
+	}
+}
+
+func TestFindMatches_UnknownVersionByDefault(t *testing.T) {
+	cpe, err := syftPkg.NewCPE("cpe:2.3:a:libfoo:libfoo:*:*:*:*:*:*:*:*")
+	require.NoError(t, err)
+
+	// a version that does not conform to semver (which npm packages are versioned by)
+	p := pkg.Package{
+		ID:       pkg.ID(uuid.NewString()),
+		Name:     "libfoo",
+		Version:  "1.2",
+		Type:     syftPkg.NpmPkg,
+		Language: syftPkg.JavaScript,
+		CPEs:     []syftPkg.CPE{cpe},
+	}
+	_, err = version.NewVersionFromPkg(p)
+	require.Error(t, err)
+
+	provider := &mockCPEProvider{
+		vulns: []vulnerability.Vulnerability{
+			{
+				Constraint: version.MustGetConstraint("< 1.3.0", version.UnknownFormat),
+				ID:         "CVE-2021-fake",
+				Namespace:  "nvd",
+				CPEs:       []syftPkg.CPE{cpe},
+			},
+		},
+	}
+
+	for name, cfg := range map[string]Config{"default": DefaultConfig(), "empty": {}} {
+		t.Run(name, func(t *testing.T) {
+			matches := FindMatchesWithConfig(provider, nil, cfg, p)
+
+			var actual []string
+			for m := range matches.Enumerate() {
+				actual = append(actual, m.Vulnerability.ID)
+			}
+			assert.Equal(t, []string{"CVE-2021-fake"}, actual)
+		})
+	}
+}
+
+func TestFindMatches_Workers(t *testing.T) {
+	cpe, err := syftPkg.NewCPE("cpe:2.3:a:libfoo:libfoo:*:*:*:*:*:*:*:*")
+	require.NoError(t, err)
+
+	provider := &mockCPEProvider{
+		vulns: []vulnerability.Vulnerability{
+			{
+				Constraint: version.MustGetConstraint("< 2.0.0", version.SemanticFormat),
+				ID:         "CVE-2021-fake",
+				Namespace:  "nvd",
+				CPEs:       []syftPkg.CPE{cpe},
+			},
+		},
+	}
+
+	var packages []pkg.Package
+	for i := 0; i < 100; i++ {
+		packages = append(packages, pkg.Package{
+			ID:       pkg.ID(uuid.NewString()),
+			Name:     "libfoo",
+			Version:  "1.0.0",
+			Type:     syftPkg.RustPkg,
+			Language: syftPkg.Rust,
+			CPEs:     []syftPkg.CPE{cpe},
+		})
+	}
+
+	expected := FindMatchesWithConfig(provider, nil, Config{Workers: 1}, packages...)
+	assert.Equal(t, len(packages), expected.Count())
+
+	for _, workers := range []int{0, 4, 1000} {
+		actual := FindMatchesWithConfig(provider, nil, Config{Workers: workers}, packages...)
+		assert.ElementsMatch(t, expected.Sorted(), actual.Sorted(), "workers=%d", workers)
+	}
+}
+
+func TestParseUnknownVersionPolicy(t *testing.T) {
+	policy, err := ParseUnknownVersionPolicy("Skip")
+	require.NoError(t, err)
+	assert.Equal(t, SkipUnknownVersions, policy)
+
+	_, err = ParseUnknownVersionPolicy("ignore")
+	assert.Error(t, err)
+}
+
+func TestFindMatches_MatcherConfig(t *testing.T) {
+	cpe, err := syftPkg.NewCPE("cpe:2.3:a:libfoo:libfoo:*:*:*:*:*:*:*:*")
+	require.NoError(t, err)
+	otherCPE, err := syftPkg.NewCPE("cpe:2.3:a:libbar:libbar:*:*:*:*:*:*:*:*")
+	require.NoError(t, err)
+
+	provider := &mockCPEProvider{
+		vulns: []vulnerability.Vulnerability{
+			{
+				Constraint: version.MustGetConstraint("< 2.0.0", version.SemanticFormat),
+				ID:         "CVE-2021-fake",
+				Namespace:  "nvd",
+				CPEs:       []syftPkg.CPE{cpe},
+			},
+			{
+				Constraint: version.MustGetConstraint("< 2.0.0", version.UnknownFormat),
+				ID:         "CVE-2021-other",
+				Namespace:  "nvd",
+				CPEs:       []syftPkg.CPE{otherCPE},
+			},
+		},
+	}
+
+	rustPkg := pkg.Package{
+		ID:       pkg.ID(uuid.NewString()),
+		Name:     "libfoo",
+		Version:  "1.0.0",
+		Type:     syftPkg.RustPkg,
+		Language: syftPkg.Rust,
+		CPEs:     []syftPkg.CPE{cpe},
+	}
+	// a package with an unknown version, which is matched by the stock matcher
+	otherPkg := pkg.Package{
+		ID:      pkg.ID(uuid.NewString()),
+		Name:    "libbar",
+		Version: "1.0.0",
+		Type:    syftPkg.UnknownPkg,
+		CPEs:    []syftPkg.CPE{otherCPE},
+	}
+
+	tests := []struct {
+		name     string
+		matchers map[match.MatcherType]MatcherConfig
+		expected []match.MatcherType
+	}{
+		{
+			name:     "defaults",
+			expected: []match.MatcherType{match.RustMatcher, match.StockMatcher},
+		},
+		{
+			name:     "disabled matcher",
+			matchers: map[match.MatcherType]MatcherConfig{match.RustMatcher: {Disabled: true}},
+			expected: []match.MatcherType{match.StockMatcher},
+		},
+		{
+			name:     "disabled stock matcher",
+			matchers: map[match.MatcherType]MatcherConfig{match.StockMatcher: {Disabled: true}},
+			expected: []match.MatcherType{match.RustMatcher},
+		},
+		{
+			name:     "without CPEs",
+			matchers: map[match.MatcherType]MatcherConfig{match.RustMatcher: {SkipCPEs: true}},
+			expected: []match.MatcherType{match.StockMatcher},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := Config{UnknownVersionPolicy: MatchUnknownVersions, Matchers: test.matchers}
+			matches := FindMatchesWithConfig(provider, nil, cfg, rustPkg, otherPkg)
+
+			var actual []match.MatcherType
+			for m := range matches.Enumerate() {
+				actual = append(actual, m.Details.Matchers()...)
+			}
+			assert.ElementsMatch(t, test.expected, actual)
+		})
+	}
+}
+
+// fakePlugin matches every package of its type to a vulnerability of its own.
+type fakePlugin struct{}
+
+func (fakePlugin) PackageTypes() []syftPkg.Type {
+	return []syftPkg.Type{syftPkg.RustPkg, "acme"}
+}
+
+func (fakePlugin) Type() match.MatcherType {
+	return "acme-plugin"
+}
+
+func (p fakePlugin) Match(_ vulnerability.Provider, _ *distro.Distro, pk pkg.Package) ([]match.Match, error) {
+	return []match.Match{{
+		Vulnerability: vulnerability.Vulnerability{ID: "ACME-2021-0001", Namespace: "acme"},
+		Package:       pk,
+		Details:       []match.Detail{{Type: match.ExactDirectMatch, Matcher: p.Type()}},
+	}}, nil
+}
+
+func TestFindMatches_Plugins(t *testing.T) {
+	cpe, err := syftPkg.NewCPE("cpe:2.3:a:libfoo:libfoo:*:*:*:*:*:*:*:*")
+	require.NoError(t, err)
+	provider := &mockCPEProvider{
+		vulns: []vulnerability.Vulnerability{
+			{
+				Constraint: version.MustGetConstraint("< 2.0.0", version.SemanticFormat),
+				ID:         "CVE-2021-fake",
+				Namespace:  "nvd",
+				CPEs:       []syftPkg.CPE{cpe},
+			},
+		},
+	}
+
+	packages := []pkg.Package{
+		// a package of a built-in matcher, which the plugin matches as well
+		{ID: pkg.ID(uuid.NewString()), Name: "libfoo", Version: "1.0.0", Type: syftPkg.RustPkg, Language: syftPkg.Rust, CPEs: []syftPkg.CPE{cpe}},
+		// a package that only the plugin matches (which is not matched by the stock matcher)
+		{ID: pkg.ID(uuid.NewString()), Name: "libfoo", Version: "1.0.0", Type: "acme", CPEs: []syftPkg.CPE{cpe}},
+	}
+
+	matches := FindMatchesWithConfig(provider, nil, Config{Plugins: []Matcher{fakePlugin{}}}, packages...)
+
+	var actual []string
+	for m := range matches.Enumerate() {
+		actual = append(actual, fmt.Sprintf("%s %s %s", m.Package.Type, m.Vulnerability.ID, m.Details[0].Matcher))
+	}
+	assert.ElementsMatch(t, []string{
+		"rust-crate CVE-2021-fake rust-matcher",
+		"rust-crate ACME-2021-0001 acme-plugin",
+		"acme ACME-2021-0001 acme-plugin",
+	}, actual)
+}
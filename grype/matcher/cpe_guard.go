@@ -0,0 +1,144 @@
+package matcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/facebookincubator/nvdtools/wfn"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+const (
+	// LooseCPEMatching matches the CPEs of packages against the CPEs of vulnerabilities regardless of the target software
+	// of the vulnerabilities.
+	LooseCPEMatching CPEStrictness = "loose"
+	// TargetSoftwareCPEMatching does not match a package against the CPE of a vulnerability that targets the software of
+	// another ecosystem than the ecosystem of the package (e.g. a CVE of a node.js library against a python package of
+	// the same name).
+	TargetSoftwareCPEMatching CPEStrictness = "target-software"
+	// StrictCPEMatching only matches a package against the CPE of a vulnerability that targets any software or the
+	// software of the ecosystem of the package, and only by CPEs of the package with a vendor.
+	StrictCPEMatching CPEStrictness = "strict"
+)
+
+var AllCPEStrictnesses = []CPEStrictness{
+	LooseCPEMatching,
+	TargetSoftwareCPEMatching,
+	StrictCPEMatching,
+}
+
+// CPEStrictness determines how strictly the CPEs of packages are matched against the CPEs of vulnerabilities, beyond
+// the comparison of the CPE fields.
+type CPEStrictness string
+
+// ParseCPEStrictness returns the strictness for the given (case-insensitive) name.
+func ParseCPEStrictness(s string) (CPEStrictness, error) {
+	for _, strictness := range AllCPEStrictnesses {
+		if strings.EqualFold(s, string(strictness)) {
+			return strictness, nil
+		}
+	}
+	return "", fmt.Errorf("unknown CPE strictness %q (options: %v)", s, AllCPEStrictnesses)
+}
+
+// targetSoftware are the target_sw values of the CPEs of vulnerabilities of each ecosystem (by language).
+var targetSoftware = map[syftPkg.Language][]string{
+	syftPkg.Java:       {"java", "maven", "jenkins"},
+	syftPkg.JavaScript: {"node.js", "nodejs", "npm", "npmjs", "javascript"},
+	syftPkg.Python:     {"python", "pypi"},
+	syftPkg.PHP:        {"php", "composer", "wordpress", "drupal"},
+	syftPkg.Ruby:       {"ruby", "rails", "ruby_on_rails", "rubygems"},
+	syftPkg.Go:         {"go", "golang"},
+	syftPkg.Rust:       {"rust", "cargo"},
+	pkg.Dotnet:         {".net", "asp.net", "nuget"},
+	pkg.Dart:           {"dart", "flutter"},
+	pkg.Swift:          {"swift", "cocoapods"},
+	pkg.Elixir:         {"elixir", "hex"},
+	pkg.Erlang:         {"erlang", "hex"},
+	pkg.R:              {"r", "cran"},
+	pkg.Haskell:        {"haskell", "hackage"},
+}
+
+// knownTargetSoftware are the target_sw values of the CPEs of vulnerabilities of every ecosystem.
+var knownTargetSoftware = func() map[string]bool {
+	result := make(map[string]bool)
+	for _, values := range targetSoftware {
+		for _, v := range values {
+			result[v] = true
+		}
+	}
+	return result
+}()
+
+// cpeGuard is a provider of a matcher that only provides the vulnerabilities of the CPEs that apply to the ecosystem of
+// the matched package (according to the strictness).
+type cpeGuard struct {
+	vulnerability.Provider
+	strictness CPEStrictness
+	language   syftPkg.Language
+}
+
+// newCPEGuard returns the provider of the given package with the CPE guard of the given strictness (where the loose
+// strictness does not guard the provider).
+func newCPEGuard(provider vulnerability.Provider, strictness CPEStrictness, p pkg.Package) vulnerability.Provider {
+	if strictness == "" || strictness == LooseCPEMatching {
+		return provider
+	}
+	return cpeGuard{Provider: provider, strictness: strictness, language: p.Language}
+}
+
+func (g cpeGuard) GetByCPE(c syftPkg.CPE) ([]vulnerability.Vulnerability, error) {
+	if g.strictness == StrictCPEMatching && (c.Vendor == wfn.Any || c.Vendor == wfn.NA) {
+		// note: a CPE without a vendor matches the products of any vendor with the same name
+		return nil, nil
+	}
+
+	vulns, err := g.Provider.GetByCPE(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []vulnerability.Vulnerability
+	for _, v := range vulns {
+		var cpes []syftPkg.CPE
+		for _, vulnCPE := range v.CPEs {
+			if g.applies(vulnCPE) {
+				cpes = append(cpes, vulnCPE)
+			}
+		}
+		// note: the CPEs of the vulnerability are the CPEs that matched the CPE of the package, of which only the CPEs
+		// that apply to the package are kept
+		if len(cpes) > 0 {
+			v.CPEs = cpes
+			result = append(result, v)
+		}
+	}
+	return result, nil
+}
+
+// applies indicates whether the CPE of a vulnerability applies to the ecosystem of the package.
+func (g cpeGuard) applies(vulnCPE syftPkg.CPE) bool {
+	target := strings.ToLower(vulnCPE.TargetSW)
+	if target == wfn.Any || target == wfn.NA {
+		return true
+	}
+	expected, known := targetSoftware[g.language]
+	if !known {
+		// note: the ecosystem of the package (e.g. an OS package) is not known, so any target software may apply
+		return true
+	}
+	for _, e := range expected {
+		if e == target {
+			return true
+		}
+	}
+	if g.strictness == StrictCPEMatching {
+		return false
+	}
+	// the target software of another ecosystem does not apply, where unknown target software (e.g. an operating system)
+	// may still apply
+	return !knownTargetSoftware[target]
+}
@@ -0,0 +1,117 @@
+package matcher
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestFindMatches_CPEStrictness(t *testing.T) {
+	vuln := func(id string, cpes ...string) vulnerability.Vulnerability {
+		return vulnerability.Vulnerability{
+			Constraint: version.MustGetConstraint("< 2.0.0", version.PythonFormat),
+			ID:         id,
+			Namespace:  "nvd",
+			CPEs:       mustCPEs(t, cpes...),
+		}
+	}
+	provider := &mockCPEProvider{
+		vulns: []vulnerability.Vulnerability{
+			vuln("CVE-any-software", "cpe:2.3:a:ini_project:ini:*:*:*:*:*:*:*:*"),
+			vuln("CVE-python", "cpe:2.3:a:ini_project:ini:*:*:*:*:*:python:*:*"),
+			vuln("CVE-node", "cpe:2.3:a:ini_project:ini:*:*:*:*:*:node.js:*:*"),
+			vuln("CVE-node-and-python", "cpe:2.3:a:ini_project:ini:*:*:*:*:*:node.js:*:*", "cpe:2.3:a:ini_project:ini:*:*:*:*:*:pypi:*:*"),
+			vuln("CVE-unknown-software", "cpe:2.3:a:ini_project:ini:*:*:*:*:*:zope:*:*"),
+		},
+	}
+
+	tests := []struct {
+		strictness CPEStrictness
+		cpes       []string
+		expected   []string
+	}{
+		{
+			strictness: LooseCPEMatching,
+			cpes:       []string{"cpe:2.3:a:ini_project:ini:1.0.0:*:*:*:*:*:*:*"},
+			expected:   []string{"CVE-any-software", "CVE-node", "CVE-node-and-python", "CVE-python", "CVE-unknown-software"},
+		},
+		{
+			strictness: TargetSoftwareCPEMatching,
+			cpes:       []string{"cpe:2.3:a:ini_project:ini:1.0.0:*:*:*:*:*:*:*"},
+			expected:   []string{"CVE-any-software", "CVE-node-and-python", "CVE-python", "CVE-unknown-software"},
+		},
+		{
+			strictness: StrictCPEMatching,
+			cpes:       []string{"cpe:2.3:a:ini_project:ini:1.0.0:*:*:*:*:*:*:*"},
+			expected:   []string{"CVE-any-software", "CVE-node-and-python", "CVE-python"},
+		},
+		{
+			strictness: TargetSoftwareCPEMatching,
+			cpes:       []string{"cpe:2.3:a:*:ini:1.0.0:*:*:*:*:*:*:*"},
+			expected:   []string{"CVE-any-software", "CVE-node-and-python", "CVE-python", "CVE-unknown-software"},
+		},
+		{
+			// a CPE without a vendor is not matched at all
+			strictness: StrictCPEMatching,
+			cpes:       []string{"cpe:2.3:a:*:ini:1.0.0:*:*:*:*:*:*:*"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.strictness), func(t *testing.T) {
+			p := pkg.Package{
+				ID:       pkg.ID(uuid.NewString()),
+				Name:     "ini",
+				Version:  "1.0.0",
+				Type:     syftPkg.PythonPkg,
+				Language: syftPkg.Python,
+				CPEs:     mustCPEs(t, test.cpes...),
+			}
+			cfg := DefaultConfig()
+			cfg.CPEStrictness = test.strictness
+
+			matches := FindMatchesWithConfig(provider, nil, cfg, p)
+
+			var actual []string
+			for m := range matches.Enumerate() {
+				actual = append(actual, m.Vulnerability.ID)
+				if m.Vulnerability.ID == "CVE-node-and-python" && test.strictness != LooseCPEMatching {
+					// only the CPE that applies to the package is reported
+					assert.Len(t, m.Vulnerability.CPEs, 1)
+				}
+			}
+			sort.Strings(actual)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestCPEGuard_unknownEcosystem(t *testing.T) {
+	// the target software of the CPEs of vulnerabilities does not apply to packages of an unknown ecosystem
+	guard := newCPEGuard(&mockCPEProvider{
+		vulns: []vulnerability.Vulnerability{
+			{ID: "CVE-node", CPEs: mustCPEs(t, "cpe:2.3:a:zlib:zlib:*:*:*:*:*:node.js:*:*")},
+		},
+	}, StrictCPEMatching, pkg.Package{Name: "zlib", Type: syftPkg.DebPkg})
+
+	vulns, err := guard.GetByCPE(mustCPEs(t, "cpe:2.3:a:zlib:zlib:1.2.11:*:*:*:*:*:*:*")[0])
+	require.NoError(t, err)
+	assert.Len(t, vulns, 1)
+}
+
+func TestParseCPEStrictness(t *testing.T) {
+	strictness, err := ParseCPEStrictness("Strict")
+	require.NoError(t, err)
+	assert.Equal(t, StrictCPEMatching, strictness)
+
+	_, err = ParseCPEStrictness("fuzzy")
+	assert.Error(t, err)
+}
@@ -22,6 +22,11 @@ func TestMatcherDpkg_matchBySourceIndirection(t *testing.T) {
 		Name:    "neutron",
 		Version: "2014.1.3-6",
 		Type:    syftPkg.DebPkg,
+		Upstreams: []pkg.UpstreamPackage{
+			{
+				Name: "neutron-devel",
+			},
+		},
 		Metadata: pkg.DpkgMetadata{
 			Source: "neutron-devel",
 		},
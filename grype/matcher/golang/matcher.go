@@ -0,0 +1,31 @@
+package golang
+
+import (
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/search"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type Matcher struct {
+}
+
+func (m *Matcher) PackageTypes() []syftPkg.Type {
+	return []syftPkg.Type{syftPkg.GoModulePkg}
+}
+
+func (m *Matcher) Type() match.MatcherType {
+	return match.GoModuleMatcher
+}
+
+func (m *Matcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	criteria := search.CommonCriteria
+	if p.Name != pkg.GoStdlibPackageName && (p.Version == "" || p.Version == "(devel)") {
+		// the main module of a go binary is reported without a usable version, so matching by CPE would result in
+		// false positives for all versions of the module
+		criteria = []search.Criteria{search.ByLanguage}
+	}
+	return search.ByCriteria(store, d, p, m.Type(), criteria...)
+}
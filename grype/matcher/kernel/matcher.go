@@ -0,0 +1,76 @@
+package kernel
+
+import (
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/search"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// subsystemConfigOptions relates kernel vulnerabilities to the config options that build the vulnerable subsystem.
+// A kernel that was built without any of these options cannot be affected by the vulnerability.
+var subsystemConfigOptions = map[string][]string{
+	// TIPC
+	"CVE-2021-43267": {"CONFIG_TIPC"},
+	"CVE-2022-0435":  {"CONFIG_TIPC"},
+	// netfilter
+	"CVE-2021-22555": {"CONFIG_NETFILTER_XTABLES"},
+	"CVE-2022-1015":  {"CONFIG_NF_TABLES"},
+	"CVE-2022-25636": {"CONFIG_NF_TABLES"},
+	// AF_PACKET
+	"CVE-2020-14386": {"CONFIG_PACKET"},
+	// eBPF
+	"CVE-2021-3490": {"CONFIG_BPF_SYSCALL"},
+}
+
+type Matcher struct {
+}
+
+func (m *Matcher) PackageTypes() []syftPkg.Type {
+	return []syftPkg.Type{pkg.LinuxKernelPkg}
+}
+
+func (m *Matcher) Type() match.MatcherType {
+	return match.LinuxKernelMatcher
+}
+
+func (m *Matcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	matches, err := search.ByCriteria(store, d, p, m.Type(), search.ByCPE)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, ok := p.Metadata.(pkg.LinuxKernelMetadata)
+	if !ok || !metadata.HasConfig() {
+		// without the kernel build configuration there is no way to tell which subsystems are built
+		return matches, nil
+	}
+
+	var result []match.Match
+	for _, mt := range matches {
+		if !subsystemBuilt(metadata, mt.Vulnerability.ID) {
+			log.Debugf("suppressing vuln=%q for pkg=%s: the vulnerable subsystem is not built", mt.Vulnerability.ID, p)
+			continue
+		}
+		result = append(result, mt)
+	}
+	return result, nil
+}
+
+// subsystemBuilt indicates if the kernel may be affected by the given vulnerability according to its build
+// configuration. Vulnerabilities that are not related to a specific subsystem are always considered to be built.
+func subsystemBuilt(metadata pkg.LinuxKernelMetadata, id string) bool {
+	options, ok := subsystemConfigOptions[id]
+	if !ok {
+		return true
+	}
+	for _, option := range options {
+		if metadata.Enabled(option) {
+			return true
+		}
+	}
+	return false
+}
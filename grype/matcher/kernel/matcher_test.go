@@ -0,0 +1,126 @@
+package kernel
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/db"
+	grypeDB "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/pkg"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type mockStore struct {
+	backend map[string]map[string][]grypeDB.Vulnerability
+}
+
+func (s *mockStore) GetVulnerability(namespace, name string) ([]grypeDB.Vulnerability, error) {
+	namespaceMap := s.backend[namespace]
+	if namespaceMap == nil {
+		return nil, nil
+	}
+	return namespaceMap[name], nil
+}
+
+func TestMatches(t *testing.T) {
+	kernelCPEs := []string{"cpe:2.3:o:linux:linux_kernel:*:*:*:*:*:*:*:*"}
+	store := mockStore{
+		backend: map[string]map[string][]grypeDB.Vulnerability{
+			"nvd": {
+				"linux_kernel": []grypeDB.Vulnerability{
+					{
+						// TIPC
+						ID:                "CVE-2021-43267",
+						VersionConstraint: ">= 5.10, < 5.14.16",
+						VersionFormat:     "unknown",
+						CPEs:              kernelCPEs,
+					},
+					{
+						// AF_PACKET
+						ID:                "CVE-2020-14386",
+						VersionConstraint: ">= 4.6, < 5.10.1",
+						VersionFormat:     "unknown",
+						CPEs:              kernelCPEs,
+					},
+					{
+						// not related to a specific subsystem
+						ID:                "CVE-2021-4155",
+						VersionConstraint: "< 5.16",
+						VersionFormat:     "unknown",
+						CPEs:              kernelCPEs,
+					},
+					{
+						// not vulnerable by version
+						ID:                "CVE-2022-0847",
+						VersionConstraint: ">= 5.16, < 5.16.11",
+						VersionFormat:     "unknown",
+						CPEs:              kernelCPEs,
+					},
+				},
+			},
+		},
+	}
+
+	provider := db.NewVulnerabilityProvider(&store)
+
+	tests := []struct {
+		name            string
+		metadata        interface{}
+		expectedVulnIDs []string
+	}{
+		{
+			name:            "unknown build configuration",
+			metadata:        pkg.LinuxKernelMetadata{Release: "5.10.0-9-amd64"},
+			expectedVulnIDs: []string{"CVE-2020-14386", "CVE-2021-4155", "CVE-2021-43267"},
+		},
+		{
+			name: "vulnerable subsystem not built",
+			metadata: pkg.LinuxKernelMetadata{
+				Release: "5.10.0-9-amd64",
+				ConfigOptions: map[string]string{
+					"CONFIG_PACKET": "y",
+				},
+			},
+			expectedVulnIDs: []string{"CVE-2020-14386", "CVE-2021-4155"},
+		},
+		{
+			name: "vulnerable subsystem built as a module",
+			metadata: pkg.LinuxKernelMetadata{
+				Release: "5.10.0-9-amd64",
+				ConfigOptions: map[string]string{
+					"CONFIG_TIPC": "m",
+				},
+			},
+			expectedVulnIDs: []string{"CVE-2021-4155", "CVE-2021-43267"},
+		},
+	}
+
+	m := Matcher{}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			p := pkg.Package{
+				ID:       pkg.ID(uuid.NewString()),
+				Name:     "linux-kernel",
+				Version:  "5.10.0",
+				Type:     pkg.LinuxKernelPkg,
+				CPEs:     []syftPkg.CPE{syftPkg.MustCPE("cpe:2.3:o:linux:linux_kernel:5.10.0:*:*:*:*:*:*:*")},
+				Metadata: test.metadata,
+			}
+
+			matches, err := m.Match(provider, nil, p)
+			require.NoError(t, err)
+
+			var actualVulnIDs []string
+			for _, a := range matches {
+				actualVulnIDs = append(actualVulnIDs, a.Vulnerability.ID)
+			}
+			sort.Strings(actualVulnIDs)
+
+			assert.Equal(t, test.expectedVulnIDs, actualVulnIDs)
+		})
+	}
+}
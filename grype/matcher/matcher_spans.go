@@ -0,0 +1,83 @@
+package matcher
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/internal/tracing"
+)
+
+// matcherSpan is the work of a matcher across all (concurrently matched) packages.
+type matcherSpan struct {
+	start    time.Time
+	end      time.Time
+	busy     time.Duration
+	packages int
+	matches  int
+}
+
+// matcherSpans keeps track of the work of every matcher, which is recorded as a span of each matcher (instead of a
+// span for every package, of which there are thousands).
+type matcherSpans struct {
+	lock  sync.Mutex
+	spans map[match.MatcherType]*matcherSpan
+}
+
+func newMatcherSpans() *matcherSpans {
+	return &matcherSpans{spans: make(map[match.MatcherType]*matcherSpan)}
+}
+
+// add adds the matching of a package by the given matcher, which started at the given time and ended now.
+func (s *matcherSpans) add(matcherType match.MatcherType, start time.Time, matches int) {
+	if s == nil {
+		return
+	}
+	end := time.Now()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	span, ok := s.spans[matcherType]
+	if !ok {
+		span = &matcherSpan{start: start}
+		s.spans[matcherType] = span
+	}
+	if start.Before(span.start) {
+		span.start = start
+	}
+	if end.After(span.end) {
+		span.end = end
+	}
+	span.busy += end.Sub(start)
+	span.packages++
+	span.matches += matches
+}
+
+// record records the span of every matcher as a child of the span of the given context.
+func (s *matcherSpans) record(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	matcherTypes := make([]string, 0, len(s.spans))
+	for matcherType := range s.spans {
+		matcherTypes = append(matcherTypes, string(matcherType))
+	}
+	sort.Strings(matcherTypes)
+	for _, matcherType := range matcherTypes {
+		span := s.spans[match.MatcherType(matcherType)]
+		tracing.Record(ctx, "matcher "+matcherType, span.start, span.end,
+			attribute.String("grype.matcher", matcherType),
+			attribute.Int("grype.packages", span.packages),
+			attribute.Int("grype.matches", span.matches),
+			// note: packages are matched concurrently, so the span is longer than the time spent matching
+			attribute.Float64("grype.matcher.busy_seconds", span.busy.Seconds()),
+		)
+	}
+}
@@ -0,0 +1,115 @@
+package matcher
+
+import (
+	"strings"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/facebookincubator/nvdtools/wfn"
+)
+
+// platform is the vendor and product of an operating system CPE (e.g. "cpe:2.3:o:debian:debian_linux:10").
+type platform struct {
+	vendor  string
+	product string
+}
+
+// linuxKernelPlatform is satisfied by any linux distro (e.g. "running on linux" configurations).
+var linuxKernelPlatform = platform{vendor: "linux", product: "linux_kernel"}
+
+// distroPlatforms are the operating system CPEs that identify each distro within NVD configurations.
+var distroPlatforms = map[distro.Type][]platform{
+	distro.Debian:             {{vendor: "debian", product: "debian_linux"}},
+	distro.Ubuntu:             {{vendor: "canonical", product: "ubuntu_linux"}},
+	distro.RedHat:             {{vendor: "redhat", product: "enterprise_linux"}},
+	distro.CentOS:             {{vendor: "centos", product: "centos"}},
+	distro.Fedora:             {{vendor: "fedoraproject", product: "fedora"}},
+	distro.Alpine:             {{vendor: "alpinelinux", product: "alpine_linux"}},
+	distro.AmazonLinux:        {{vendor: "amazon", product: "linux"}, {vendor: "amazon", product: "linux_2"}, {vendor: "amazon", product: "linux_2023"}},
+	distro.OracleLinux:        {{vendor: "oracle", product: "linux"}},
+	distro.OpenSuseLeap:       {{vendor: "opensuse", product: "leap"}},
+	distro.OpenSuseTumbleweed: {{vendor: "opensuse", product: "tumbleweed"}},
+	distro.SLES:               {{vendor: "suse", product: "linux_enterprise_server"}},
+	distro.Photon:             {{vendor: "vmware", product: "photon_os"}},
+	distro.Mariner:            {{vendor: "microsoft", product: "cbl-mariner"}},
+	distro.AzureLinux:         {{vendor: "microsoft", product: "azure_linux"}},
+	distro.RockyLinux:         {{vendor: "rockylinux", product: "rocky_linux"}},
+	distro.AlmaLinux:          {{vendor: "almalinux", product: "almalinux"}},
+}
+
+// platformIndex describes the platform that the scanned packages are running on: the distro (when known) and the CPEs
+// of all packages (e.g. an application that is only vulnerable when running on a java runtime).
+type platformIndex struct {
+	distro *distro.Distro
+	cpes   []syftPkg.CPE
+}
+
+func newPlatformIndex(d *distro.Distro, packages []pkg.Package) platformIndex {
+	var cpes []syftPkg.CPE
+	for _, p := range packages {
+		cpes = append(cpes, p.CPEs...)
+	}
+	return platformIndex{
+		distro: d,
+		cpes:   cpes,
+	}
+}
+
+// onlyMatchingPlatforms removes matches for vulnerabilities that only apply when running on platforms that are not
+// present (e.g. an application CVE that only applies when running on windows, found within a debian image).
+func (i platformIndex) onlyMatchingPlatforms(matches []match.Match) []match.Match {
+	var result []match.Match
+	for _, m := range matches {
+		if !i.satisfies(m.Vulnerability) {
+			log.Debugf("ignoring vulnerability=%q for pkg=%s since it only applies on other platforms", m.Vulnerability.ID, m.Package)
+			continue
+		}
+		result = append(result, m)
+	}
+	return result
+}
+
+// satisfies indicates if any of the platforms of the vulnerability is present. Operating system platforms are always
+// satisfied when the distro is unknown (e.g. when scanning a directory), since the platform cannot be ruled out.
+// Note: the version of platforms is not considered.
+func (i platformIndex) satisfies(v vulnerability.Vulnerability) bool {
+	if len(v.PlatformCPEs) == 0 {
+		return true
+	}
+	for _, c := range v.PlatformCPEs {
+		for _, candidate := range i.cpes {
+			if c.Part == candidate.Part && c.Vendor == candidate.Vendor && c.Product == candidate.Product {
+				return true
+			}
+		}
+		if c.Part != "o" {
+			continue
+		}
+		// note: CPE attributes are escaped (e.g. "cbl\-mariner")
+		p := platform{vendor: wfn.StripSlashes(c.Vendor), product: wfn.StripSlashes(c.Product)}
+		if i.distro == nil || distroSatisfies(i.distro, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func distroSatisfies(d *distro.Distro, p platform) bool {
+	if d.Type == distro.Windows {
+		// windows platforms are identified by edition (e.g. "windows_10" or "windows_server_2019")
+		return p.vendor == "microsoft" && strings.HasPrefix(p.product, "windows")
+	}
+	if p == linuxKernelPlatform {
+		return true
+	}
+	for _, candidate := range distroPlatforms[d.Type] {
+		if p == candidate {
+			return true
+		}
+	}
+	return false
+}
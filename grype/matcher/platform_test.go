@@ -0,0 +1,164 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/syft/syft/linux"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func mustCPEs(t *testing.T, values ...string) []syftPkg.CPE {
+	t.Helper()
+	var cpes []syftPkg.CPE
+	for _, v := range values {
+		c, err := syftPkg.NewCPE(v)
+		require.NoError(t, err)
+		cpes = append(cpes, c)
+	}
+	return cpes
+}
+
+func TestPlatformIndex_Satisfies(t *testing.T) {
+	windows := "cpe:2.3:o:microsoft:windows_10:-:*:*:*:*:*:*:*"
+	debian := "cpe:2.3:o:debian:debian_linux:10.0:*:*:*:*:*:*:*"
+	linuxKernel := "cpe:2.3:o:linux:linux_kernel:-:*:*:*:*:*:*:*"
+	mariner := "cpe:2.3:o:microsoft:cbl-mariner:2.0:*:*:*:*:*:*:*"
+	jre := "cpe:2.3:a:oracle:jre:-:*:*:*:*:*:*:*"
+
+	tests := []struct {
+		name      string
+		distro    distro.Type
+		version   string
+		pkgCPEs   []string
+		platforms []string
+		expected  bool
+	}{
+		{
+			name:     "no platforms",
+			distro:   distro.Debian,
+			version:  "10",
+			expected: true,
+		},
+		{
+			name:      "windows only on debian",
+			distro:    distro.Debian,
+			version:   "10",
+			platforms: []string{windows},
+			expected:  false,
+		},
+		{
+			name:      "windows only on windows",
+			distro:    distro.Windows,
+			version:   "471816",
+			platforms: []string{windows},
+			expected:  true,
+		},
+		{
+			name:      "debian only on debian",
+			distro:    distro.Debian,
+			version:   "11",
+			platforms: []string{windows, debian},
+			expected:  true,
+		},
+		{
+			name:      "debian only on ubuntu",
+			distro:    distro.Ubuntu,
+			version:   "20.04",
+			platforms: []string{debian},
+			expected:  false,
+		},
+		{
+			name:      "linux only on any linux distro",
+			distro:    distro.Alpine,
+			version:   "3.14",
+			platforms: []string{linuxKernel},
+			expected:  true,
+		},
+		{
+			name:      "escaped product",
+			distro:    distro.Mariner,
+			version:   "2.0",
+			platforms: []string{mariner},
+			expected:  true,
+		},
+		{
+			name:      "unknown distro",
+			platforms: []string{windows},
+			expected:  true,
+		},
+		{
+			name:      "application platform is present",
+			distro:    distro.Debian,
+			version:   "10",
+			pkgCPEs:   []string{"cpe:2.3:a:oracle:jre:1.8.0:*:*:*:*:*:*:*"},
+			platforms: []string{jre},
+			expected:  true,
+		},
+		{
+			name:      "application platform is not present",
+			platforms: []string{jre},
+			expected:  false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var d *distro.Distro
+			if test.distro != "" {
+				var err error
+				d, err = distro.New(test.distro, test.version, "")
+				require.NoError(t, err)
+			}
+			packages := []pkg.Package{{Name: "a-pkg", CPEs: mustCPEs(t, test.pkgCPEs...)}}
+			index := newPlatformIndex(d, packages)
+
+			v := vulnerability.Vulnerability{ID: "CVE-2021-fake", PlatformCPEs: mustCPEs(t, test.platforms...)}
+			assert.Equal(t, test.expected, index.satisfies(v))
+		})
+	}
+}
+
+func TestFindMatches_Platforms(t *testing.T) {
+	cpes := mustCPEs(t, "cpe:2.3:a:zoom:zoom:*:*:*:*:*:*:*:*")
+	p := pkg.Package{
+		ID:      pkg.ID(uuid.NewString()),
+		Name:    "zoom",
+		Version: "5.0.0",
+		Type:    syftPkg.UnknownPkg,
+		CPEs:    cpes,
+	}
+
+	provider := &mockCPEProvider{
+		vulns: []vulnerability.Vulnerability{
+			{
+				Constraint: version.MustGetConstraint("< 5.1.0", version.UnknownFormat),
+				ID:         "CVE-2021-any-platform",
+				Namespace:  "nvd",
+				CPEs:       cpes,
+			},
+			{
+				Constraint:   version.MustGetConstraint("< 5.1.0", version.UnknownFormat),
+				ID:           "CVE-2021-windows-only",
+				Namespace:    "nvd",
+				CPEs:         cpes,
+				PlatformCPEs: mustCPEs(t, "cpe:2.3:o:microsoft:windows:-:*:*:*:*:*:*:*"),
+			},
+		},
+	}
+
+	matches := FindMatches(provider, &linux.Release{ID: "debian", VersionID: "10"}, p)
+
+	var actual []string
+	for m := range matches.Enumerate() {
+		actual = append(actual, m.Vulnerability.ID)
+	}
+	assert.Equal(t, []string{"CVE-2021-any-platform"}, actual)
+}
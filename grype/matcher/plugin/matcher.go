@@ -0,0 +1,219 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// Config describes the executable of a plugin and the package types that it matches.
+type Config struct {
+	// Name is the name of the plugin, where the type of the matcher (reported in the match details) is "<name>-plugin"
+	Name string
+	// Command is the executable of the plugin, which is started once (and kept running for every package it matches)
+	Command string
+	Args    []string
+	// PackageTypes are the types of the packages that the plugin matches
+	PackageTypes []syftPkg.Type
+}
+
+// Matcher is a matcher of an external plugin, which is an executable that grype talks to with JSON messages over the
+// stdin and stdout of the plugin (see Request and Response), where the stderr of the plugin is passed through.
+type Matcher struct {
+	config Config
+	// note: the plugin matches a single package at a time, so that queries and matches are not interleaved
+	lock    sync.Mutex
+	cmd     *exec.Cmd
+	stdin   io.WriteCloser
+	encoder *json.Encoder
+	decoder *json.Decoder
+}
+
+// reportedError is an error that the plugin reported (after which the plugin can still match other packages).
+type reportedError struct {
+	reason string
+}
+
+func (e reportedError) Error() string {
+	return e.reason
+}
+
+// New returns the matcher of the plugin of the given config (which is started when it matches the first package).
+func New(config Config) *Matcher {
+	return &Matcher{config: config}
+}
+
+func (m *Matcher) PackageTypes() []syftPkg.Type {
+	return m.config.PackageTypes
+}
+
+func (m *Matcher) Type() match.MatcherType {
+	return match.MatcherType(m.config.Name + "-plugin")
+}
+
+func (m *Matcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.cmd == nil {
+		if err := m.start(); err != nil {
+			return nil, fmt.Errorf("unable to start plugin %q: %w", m.config.Name, err)
+		}
+	}
+
+	matches, err := m.match(store, d, p)
+	if err != nil {
+		if !errors.As(err, &reportedError{}) {
+			// note: the state of the conversation is unknown after a failed exchange, so the plugin is started again
+			// for the next package
+			_ = m.cmd.Process.Kill()
+			if stopErr := m.stop(); stopErr != nil {
+				log.Debugf("stopped matcher plugin=%q: %+v", m.config.Name, stopErr)
+			}
+		}
+		return nil, fmt.Errorf("plugin %q: %w", m.config.Name, err)
+	}
+	return matches, nil
+}
+
+// Close stops the plugin (which is started again when it matches another package).
+func (m *Matcher) Close() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.stop()
+}
+
+func (m *Matcher) start() error {
+	cmd := exec.Command(m.config.Command, m.config.Args...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	log.Debugf("started matcher plugin=%q (pid=%d)", m.config.Name, cmd.Process.Pid)
+
+	m.cmd = cmd
+	m.stdin = stdin
+	m.encoder = json.NewEncoder(stdin)
+	m.decoder = json.NewDecoder(stdout)
+	return nil
+}
+
+func (m *Matcher) stop() error {
+	if m.cmd == nil {
+		return nil
+	}
+	// note: the plugin is expected to exit when its stdin is closed
+	_ = m.stdin.Close()
+	err := m.cmd.Wait()
+	m.cmd = nil
+	if err != nil {
+		return fmt.Errorf("plugin %q failed: %w", m.config.Name, err)
+	}
+	return nil
+}
+
+// match asks the plugin for the matches of the package, answering the queries of the plugin until it responds with the
+// matches.
+func (m *Matcher) match(store vulnerability.Provider, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	if err := m.encoder.Encode(Request{Type: MatchRequest, Package: newPackage(p), Distro: newDistro(d)}); err != nil {
+		return nil, fmt.Errorf("unable to send package: %w", err)
+	}
+
+	queried := make(map[vulnerability.Reference]vulnerability.Vulnerability)
+	for {
+		var response Response
+		if err := m.decoder.Decode(&response); err != nil {
+			return nil, fmt.Errorf("unable to read response: %w", err)
+		}
+
+		switch response.Type {
+		case QueryResponse:
+			request := Request{Type: VulnerabilitiesRequest}
+			vulns, err := query(store, d, p, response.Query)
+			if err != nil {
+				request.Error = err.Error()
+			}
+			for _, v := range vulns {
+				queried[vulnerability.Reference{ID: v.ID, Namespace: v.Namespace}] = v
+				request.Vulnerabilities = append(request.Vulnerabilities, newVulnerability(v))
+			}
+			if err := m.encoder.Encode(request); err != nil {
+				return nil, fmt.Errorf("unable to send vulnerabilities: %w", err)
+			}
+		case MatchesResponse:
+			var matches []match.Match
+			for _, candidate := range response.Matches {
+				if candidate.Vulnerability == "" {
+					return nil, fmt.Errorf("match without a vulnerability")
+				}
+				var queriedVuln *vulnerability.Vulnerability
+				if v, ok := queried[vulnerability.Reference{ID: candidate.Vulnerability, Namespace: candidate.Namespace}]; ok {
+					queriedVuln = &v
+				}
+				matches = append(matches, newMatch(candidate, queriedVuln, p, m.Type()))
+			}
+			return matches, nil
+		case ErrorResponse:
+			return nil, reportedError{reason: response.Error}
+		default:
+			return nil, fmt.Errorf("unknown response type %q", response.Type)
+		}
+	}
+}
+
+// query returns the vulnerabilities of the DB of the given query of the plugin.
+func query(store vulnerability.Provider, d *distro.Distro, p pkg.Package, q *Query) ([]vulnerability.Vulnerability, error) {
+	if q == nil {
+		return nil, fmt.Errorf("query without criteria")
+	}
+
+	target := p
+	if q.Name != "" {
+		target.Name = q.Name
+	}
+	if q.Language != "" {
+		target.Language = syftPkg.Language(q.Language)
+	}
+	if q.PackageType != "" {
+		target.Type = syftPkg.Type(q.PackageType)
+	}
+
+	switch q.By {
+	case QueryByCPE:
+		c, err := syftPkg.NewCPE(q.CPE)
+		if err != nil {
+			return nil, fmt.Errorf("bad CPE %q: %w", q.CPE, err)
+		}
+		return store.GetByCPE(c)
+	case QueryByLanguage:
+		return store.GetByLanguage(target.Language, target)
+	case QueryByPackageType:
+		return store.GetByPackageType(target.Type, target)
+	case QueryByDistro:
+		if d == nil {
+			return nil, nil
+		}
+		return store.GetByDistro(d, target)
+	default:
+		return nil, fmt.Errorf("unknown query %q (options: %s, %s, %s, %s)", q.By, QueryByCPE, QueryByLanguage, QueryByPackageType, QueryByDistro)
+	}
+}
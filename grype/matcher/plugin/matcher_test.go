@@ -0,0 +1,154 @@
+package plugin
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	grypeDb "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// testPluginArg makes the test binary act as a plugin (see runTestPlugin).
+const testPluginArg = "test-matcher-plugin"
+
+func TestMain(m *testing.M) {
+	if len(os.Args) == 2 && os.Args[1] == testPluginArg {
+		runTestPlugin()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runTestPlugin is a plugin that queries the vulnerabilities of the CPE of a package, and matches these (and a
+// vulnerability of its own) to the package.
+func runTestPlugin() {
+	encoder := json.NewEncoder(os.Stdout)
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var request Request
+		if err := json.Unmarshal(scanner.Bytes(), &request); err != nil || request.Type != MatchRequest {
+			os.Exit(1)
+		}
+
+		switch request.Package.Name {
+		case "failing":
+			_ = encoder.Encode(Response{Type: ErrorResponse, Error: "unable to match"})
+			continue
+		case "broken":
+			fmt.Println("not json")
+			continue
+		}
+
+		_ = encoder.Encode(Response{Type: QueryResponse, Query: &Query{By: QueryByCPE, CPE: request.Package.CPEs[0]}})
+		if !scanner.Scan() {
+			os.Exit(1)
+		}
+		var vulns Request
+		if err := json.Unmarshal(scanner.Bytes(), &vulns); err != nil || vulns.Type != VulnerabilitiesRequest {
+			os.Exit(1)
+		}
+
+		matches := []Match{{Vulnerability: "ACME-2021-0001", Namespace: "acme", FixedIn: []string{"1.0.1"}, Confidence: 0.5}}
+		for _, v := range vulns.Vulnerabilities {
+			matches = append(matches, Match{Vulnerability: v.ID, Namespace: v.Namespace, Type: string(match.CPEMatch), SearchedBy: map[string]string{"cpe": v.CPEs[0]}})
+		}
+		_ = encoder.Encode(Response{Type: MatchesResponse, Matches: matches})
+	}
+}
+
+type mockProvider struct {
+	vulns []vulnerability.Vulnerability
+}
+
+func (pr *mockProvider) GetByDistro(*distro.Distro, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (pr *mockProvider) GetByLanguage(syftPkg.Language, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (pr *mockProvider) GetByPackageType(syftPkg.Type, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (pr *mockProvider) GetByCPE(syftPkg.CPE) ([]vulnerability.Vulnerability, error) {
+	return pr.vulns, nil
+}
+
+func newTestMatcher(t *testing.T) *Matcher {
+	t.Helper()
+	executable, err := os.Executable()
+	require.NoError(t, err)
+
+	m := New(Config{Name: "acme", Command: executable, Args: []string{testPluginArg}, PackageTypes: []syftPkg.Type{"acme"}})
+	t.Cleanup(func() {
+		assert.NoError(t, m.Close())
+	})
+	return m
+}
+
+func TestMatcher_Match(t *testing.T) {
+	cpe, err := syftPkg.NewCPE("cpe:2.3:a:acme:widget:*:*:*:*:*:*:*:*")
+	require.NoError(t, err)
+	provider := &mockProvider{
+		vulns: []vulnerability.Vulnerability{
+			{
+				Constraint: version.MustGetConstraint("< 2.0.0", version.UnknownFormat),
+				ID:         "CVE-2021-0001",
+				Namespace:  "nvd",
+				CPEs:       []syftPkg.CPE{cpe},
+				Fix:        vulnerability.Fix{State: grypeDb.NotFixedState},
+			},
+		},
+	}
+	p := pkg.Package{ID: "widget-id", Name: "widget", Version: "1.0.0", Type: "acme", CPEs: []syftPkg.CPE{cpe}}
+
+	m := newTestMatcher(t)
+	assert.Equal(t, match.MatcherType("acme-plugin"), m.Type())
+
+	// the plugin is kept running for every package
+	for i := 0; i < 2; i++ {
+		matches, err := m.Match(provider, nil, p)
+		require.NoError(t, err)
+		require.Len(t, matches, 2)
+
+		assert.Equal(t, "ACME-2021-0001", matches[0].Vulnerability.ID)
+		assert.Equal(t, vulnerability.Fix{Versions: []string{"1.0.1"}, State: grypeDb.FixedState}, matches[0].Vulnerability.Fix)
+		assert.Equal(t, match.Detail{Type: match.ExactDirectMatch, Matcher: "acme-plugin", Confidence: 0.5}, matches[0].Details[0])
+
+		// the queried vulnerability is matched as it is in the DB
+		assert.Equal(t, provider.vulns[0], matches[1].Vulnerability)
+		assert.Equal(t, p, matches[1].Package)
+		assert.Equal(t, match.CPEMatch, matches[1].Details[0].Type)
+		assert.Equal(t, map[string]interface{}{"cpe": cpe.BindToFmtString()}, matches[1].Details[0].SearchedBy)
+	}
+}
+
+func TestMatcher_Match_errors(t *testing.T) {
+	cpe, err := syftPkg.NewCPE("cpe:2.3:a:acme:widget:*:*:*:*:*:*:*:*")
+	require.NoError(t, err)
+	provider := &mockProvider{}
+	m := newTestMatcher(t)
+
+	for _, name := range []string{"failing", "broken"} {
+		_, err := m.Match(provider, nil, pkg.Package{Name: name, Version: "1.0.0", Type: "acme"})
+		assert.Error(t, err, name)
+	}
+
+	// the plugin still matches packages after errors (where a broken plugin is started again)
+	matches, err := m.Match(provider, nil, pkg.Package{Name: "widget", Version: "1.0.0", Type: "acme", CPEs: []syftPkg.CPE{cpe}})
+	require.NoError(t, err)
+	assert.Len(t, matches, 1)
+}
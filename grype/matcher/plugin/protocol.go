@@ -0,0 +1,195 @@
+package plugin
+
+import (
+	grypeDb "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// the types of the messages that grype sends to a plugin (on the stdin of the plugin)
+const (
+	// MatchRequest asks the plugin for the matches of a package
+	MatchRequest = "match"
+	// VulnerabilitiesRequest is the result of the last query of the plugin
+	VulnerabilitiesRequest = "vulnerabilities"
+)
+
+// the types of the messages that a plugin sends to grype (on the stdout of the plugin)
+const (
+	// QueryResponse asks grype for the vulnerabilities of the DB (which grype answers with a VulnerabilitiesRequest)
+	QueryResponse = "query"
+	// MatchesResponse is the result of the last MatchRequest
+	MatchesResponse = "matches"
+	// ErrorResponse reports that the plugin was unable to match the package of the last MatchRequest
+	ErrorResponse = "error"
+)
+
+// the kinds of queries of the vulnerabilities of the DB
+const (
+	QueryByCPE         = "cpe"
+	QueryByLanguage    = "language"
+	QueryByPackageType = "package-type"
+	QueryByDistro      = "distro"
+)
+
+// Request is a message from grype to a plugin, where every message is a JSON object on its own line.
+type Request struct {
+	Type string `json:"type"`
+	// Package is the package to match (for a MatchRequest)
+	Package *Package `json:"package,omitempty"`
+	// Distro is the distro of the scanned source, if known (for a MatchRequest)
+	Distro *Distro `json:"distro,omitempty"`
+	// Vulnerabilities are the vulnerabilities of the DB that the last query found (for a VulnerabilitiesRequest)
+	Vulnerabilities []Vulnerability `json:"vulnerabilities,omitempty"`
+	// Error is the reason the last query failed (for a VulnerabilitiesRequest)
+	Error string `json:"error,omitempty"`
+}
+
+// Response is a message from a plugin to grype, where every message is a JSON object on its own line. A plugin answers
+// every MatchRequest with any number of queries, followed by the matches (or an error).
+type Response struct {
+	Type    string  `json:"type"`
+	Query   *Query  `json:"query,omitempty"`
+	Matches []Match `json:"matches,omitempty"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// Query asks for the vulnerabilities of the DB of a CPE, or of the package that is matched (by language, package type,
+// or distro), where the name, language and type of the package can be replaced (e.g. to query the vulnerabilities of
+// the public package that a proprietary package is built from).
+type Query struct {
+	By          string `json:"by"`
+	CPE         string `json:"cpe,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Language    string `json:"language,omitempty"`
+	PackageType string `json:"packageType,omitempty"`
+}
+
+// Match is a candidate match of the package of the last MatchRequest, which is either one of the vulnerabilities that
+// were queried or a vulnerability that is only known to the plugin.
+type Match struct {
+	Vulnerability string `json:"vulnerability"`
+	Namespace     string `json:"namespace"`
+	// FixedIn and FixState describe the fix of the vulnerability (where the fix of the queried vulnerability is used
+	// when these are empty)
+	FixedIn  []string `json:"fixedIn,omitempty"`
+	FixState string   `json:"fixState,omitempty"`
+	// Type is the type of the match (exact-direct-match when empty)
+	Type       string      `json:"type,omitempty"`
+	SearchedBy interface{} `json:"searchedBy,omitempty"`
+	Found      interface{} `json:"found,omitempty"`
+	Confidence float64     `json:"confidence,omitempty"`
+}
+
+type Package struct {
+	ID        string            `json:"id"`
+	Name      string            `json:"name"`
+	Version   string            `json:"version"`
+	Type      string            `json:"type"`
+	Language  string            `json:"language,omitempty"`
+	PURL      string            `json:"purl,omitempty"`
+	CPEs      []string          `json:"cpes,omitempty"`
+	Locations []string          `json:"locations,omitempty"`
+	Upstreams []UpstreamPackage `json:"upstreams,omitempty"`
+}
+
+type UpstreamPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type Distro struct {
+	Type    string `json:"type"`
+	Version string `json:"version"`
+}
+
+type Vulnerability struct {
+	ID         string   `json:"id"`
+	Namespace  string   `json:"namespace"`
+	Constraint string   `json:"constraint,omitempty"`
+	CPEs       []string `json:"cpes,omitempty"`
+	FixedIn    []string `json:"fixedIn,omitempty"`
+	FixState   string   `json:"fixState,omitempty"`
+}
+
+func newPackage(p pkg.Package) *Package {
+	result := &Package{
+		ID:       string(p.ID),
+		Name:     p.Name,
+		Version:  p.Version,
+		Type:     string(p.Type),
+		Language: string(p.Language),
+		PURL:     p.PURL,
+	}
+	for _, c := range p.CPEs {
+		result.CPEs = append(result.CPEs, c.BindToFmtString())
+	}
+	for _, l := range p.Locations {
+		result.Locations = append(result.Locations, l.RealPath)
+	}
+	for _, u := range p.Upstreams {
+		result.Upstreams = append(result.Upstreams, UpstreamPackage{Name: u.Name, Version: u.Version})
+	}
+	return result
+}
+
+func newDistro(d *distro.Distro) *Distro {
+	if d == nil {
+		return nil
+	}
+	return &Distro{Type: d.Type.String(), Version: d.FullVersion()}
+}
+
+func newVulnerability(v vulnerability.Vulnerability) Vulnerability {
+	result := Vulnerability{
+		ID:        v.ID,
+		Namespace: v.Namespace,
+		FixedIn:   v.Fix.Versions,
+		FixState:  string(v.Fix.State),
+	}
+	if v.Constraint != nil {
+		result.Constraint = v.Constraint.String()
+	}
+	for _, c := range v.CPEs {
+		result.CPEs = append(result.CPEs, c.BindToFmtString())
+	}
+	return result
+}
+
+// newMatch returns the match of the given candidate, where the vulnerability is the queried vulnerability (if any).
+func newMatch(candidate Match, queried *vulnerability.Vulnerability, p pkg.Package, matcherType match.MatcherType) match.Match {
+	vuln := vulnerability.Vulnerability{
+		ID:        candidate.Vulnerability,
+		Namespace: candidate.Namespace,
+		Fix:       vulnerability.Fix{State: grypeDb.UnknownFixState},
+	}
+	if queried != nil {
+		vuln = *queried
+	}
+	if len(candidate.FixedIn) > 0 || candidate.FixState != "" {
+		vuln.Fix = vulnerability.Fix{Versions: candidate.FixedIn, State: grypeDb.FixState(candidate.FixState)}
+		if vuln.Fix.State == "" {
+			vuln.Fix.State = grypeDb.FixedState
+		}
+	}
+
+	matchType := match.Type(candidate.Type)
+	if matchType == "" {
+		matchType = match.ExactDirectMatch
+	}
+	return match.Match{
+		Vulnerability: vuln,
+		Package:       p,
+		Details: []match.Detail{
+			{
+				Type:       matchType,
+				SearchedBy: candidate.SearchedBy,
+				Found:      candidate.Found,
+				Matcher:    matcherType,
+				Confidence: candidate.Confidence,
+			},
+		},
+	}
+}
@@ -2,7 +2,6 @@ package rpmdb
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 
 	"github.com/anchore/grype/grype/distro"
@@ -10,20 +9,10 @@ import (
 	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/grype/grype/search"
 	"github.com/anchore/grype/grype/vulnerability"
-	"github.com/anchore/grype/internal"
-	"github.com/anchore/grype/internal/log"
 	syftPkg "github.com/anchore/syft/syft/pkg"
 	"github.com/jinzhu/copier"
 )
 
-// the source-rpm field has something akin to "util-linux-ng-2.17.2-12.28.el6_9.2.src.rpm"
-// in which case the pattern will extract out the following values for the named capture groups:
-//		name = "util-linux-ng"
-//		version = "2.17.2" (or, if there's an epoch, we'd expect a value like "4:2.17.2")
-//		release = "12.28.el6_9.2"
-//		arch = "src"
-var rpmPackageNamePattern = regexp.MustCompile(`^(?P<name>.*)-(?P<version>.*)-(?P<release>.*)\.(?P<arch>[a-zA-Z][^.]+)(\.rpm)$`)
-
 type Matcher struct {
 }
 
@@ -113,46 +102,50 @@ func (m *Matcher) Match(store vulnerability.Provider, d *distro.Distro, p pkg.Pa
 
 	matches = append(matches, exactMatches...)
 
-	return matches, nil
+	// RHEL module streams (e.g. "nodejs:12" vs "nodejs:14") ship the same package names with independent fixes, so
+	// advisories for a stream the package was not built from do not apply.
+	return filterByModularity(matches, p), nil
 }
 
-func (m *Matcher) matchBySourceIndirection(store vulnerability.ProviderByDistro, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+// filterByModularity removes matches against advisories for a module stream that differs from the module stream of
+// the given package. When the module stream of the package is unknown all matches are kept.
+func filterByModularity(matches []match.Match, p pkg.Package) []match.Match {
 	metadata, ok := p.Metadata.(pkg.RpmdbMetadata)
-	if !ok {
-		return nil, nil
-	}
-
-	// ignore packages without source indirection hints
-	if metadata.SourceRpm == "" {
-		return nil, nil
+	if !ok || metadata.ModularityLabel == nil {
+		return matches
 	}
-
-	sourceName, sourceVersion := getNameAndELVersion(metadata)
-	if sourceName == "" && sourceVersion == "" {
-		log.Warnf("unable to extract name and version from SourceRPM=%q for %s@%s", metadata.SourceRpm, p.Name, p.Version)
-		return nil, nil
+	stream := moduleStream(*metadata.ModularityLabel)
+
+	var filtered []match.Match
+	for _, m := range matches {
+		if m.Vulnerability.RpmModularity != "" && m.Vulnerability.RpmModularity != stream {
+			continue
+		}
+		filtered = append(filtered, m)
 	}
+	return filtered
+}
 
-	// don't include matches if the source package name matches the current package name
-	if sourceName == p.Name {
-		return nil, nil
+// moduleStream returns the "name:stream" portion of a modularity label (e.g. "nodejs:12" from
+// "nodejs:12:8030020201124152102:229f0a1c").
+func moduleStream(label string) string {
+	fields := strings.SplitN(label, ":", 3)
+	if len(fields) < 2 {
+		return label
 	}
+	return fields[0] + ":" + fields[1]
+}
 
-	// use source package name for exact package name matching
-	var indirectPackage pkg.Package
-
-	err := copier.Copy(&indirectPackage, p)
-	if err != nil {
-		return nil, fmt.Errorf("failed to copy package: %w", err)
-	}
-
-	// use the source package name
-	indirectPackage.Name = sourceName
-	indirectPackage.Version = sourceVersion
-
-	matches, err := search.ByPackageDistro(store, d, indirectPackage, m.Type())
-	if err != nil {
-		return nil, fmt.Errorf("failed to find vulnerabilities by dpkg source indirection: %w", err)
+func (m *Matcher) matchBySourceIndirection(store vulnerability.ProviderByDistro, d *distro.Distro, p pkg.Package) ([]match.Match, error) {
+	var matches []match.Match
+
+	// use the source package name and version (as found in the "sourceRPM" field) for exact package name matching
+	for _, indirectPackage := range pkg.UpstreamPackages(p) {
+		indirectMatches, err := search.ByPackageDistro(store, d, indirectPackage, m.Type())
+		if err != nil {
+			return nil, fmt.Errorf("failed to find vulnerabilities by rpm source indirection: %w", err)
+		}
+		matches = append(matches, indirectMatches...)
 	}
 
 	// we want to make certain that we are tracking the match based on the package from the SBOM (not the indirect package).
@@ -192,9 +185,3 @@ func addZeroEpicIfApplicable(version string) string {
 	}
 	return "0:" + version
 }
-
-func getNameAndELVersion(metadata pkg.RpmdbMetadata) (string, string) {
-	groupMatches := internal.MatchCaptureGroups(rpmPackageNamePattern, metadata.SourceRpm)
-	version := groupMatches["version"] + "-" + groupMatches["release"]
-	return groupMatches["name"], version
-}
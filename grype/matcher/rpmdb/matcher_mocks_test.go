@@ -84,6 +84,31 @@ func (pr *mockProvider) stubWithEpoch(packageName, indrectName string) {
 	}
 }
 
+func newMockProviderWithModularity(packageName string) *mockProvider {
+	return &mockProvider{
+		data: map[string]map[string][]vulnerability.Vulnerability{
+			"rhel:8": {
+				packageName: {
+					{
+						Constraint:    version.MustGetConstraint("< 0:12.22.1-1.module_el8.4.0+716+3f6b5a8a", version.RpmFormat),
+						ID:            "CVE-2021-22918",
+						RpmModularity: "nodejs:12",
+					},
+					{
+						Constraint:    version.MustGetConstraint("< 0:14.17.2-1.module_el8.4.0+717+0b8e3d0b", version.RpmFormat),
+						ID:            "CVE-2021-22930",
+						RpmModularity: "nodejs:14",
+					},
+					{
+						Constraint: version.MustGetConstraint("< 0:16.0.0-1.el8", version.RpmFormat),
+						ID:         "CVE-2021-fake-unmodular",
+					},
+				},
+			},
+		},
+	}
+}
+
 func (pr *mockProvider) GetByDistro(d *distro.Distro, p pkg.Package) ([]vulnerability.Vulnerability, error) {
 	var ty = strings.ToLower(d.Type.String())
 	if d.Type == distro.CentOS || d.Type == distro.RedHat || d.Type == distro.RockyLinux || d.Type == distro.AlmaLinux {
@@ -100,3 +125,7 @@ func (pr *mockProvider) GetByCPE(request syftPkg.CPE) (v []vulnerability.Vulnera
 func (pr *mockProvider) GetByLanguage(l syftPkg.Language, p pkg.Package) (v []vulnerability.Vulnerability, err error) {
 	return v, err
 }
+
+func (pr *mockProvider) GetByPackageType(t syftPkg.Type, p pkg.Package) (v []vulnerability.Vulnerability, err error) {
+	return v, err
+}
@@ -19,6 +19,10 @@ func intRef(x int) *int {
 	return &x
 }
 
+func strRef(x string) *string {
+	return &x
+}
+
 func TestMatcherRpmdb(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -34,6 +38,12 @@ func TestMatcherRpmdb(t *testing.T) {
 				Name:    "neutron-libs",
 				Version: "7.1.3-6",
 				Type:    syftPkg.RpmPkg,
+				Upstreams: []pkg.UpstreamPackage{
+					{
+						Name:    "neutron",
+						Version: "7.1.3-6.el8",
+					},
+				},
 				Metadata: pkg.RpmdbMetadata{
 					SourceRpm: "neutron-7.1.3-6.el8.src.rpm",
 				},
@@ -89,6 +99,12 @@ func TestMatcherRpmdb(t *testing.T) {
 				Name:    "neutron-libs",
 				Version: "7.1.3-6",
 				Type:    syftPkg.RpmPkg,
+				Upstreams: []pkg.UpstreamPackage{
+					{
+						Name:    "neutron",
+						Version: "17.16.3-229.el8",
+					},
+				},
 				Metadata: pkg.RpmdbMetadata{
 					SourceRpm: "neutron-17.16.3-229.el8.src.rpm",
 				},
@@ -117,6 +133,12 @@ func TestMatcherRpmdb(t *testing.T) {
 				Name:    "perl-Errno",
 				Version: "0:1.28-419.el8_4.1",
 				Type:    syftPkg.RpmPkg,
+				Upstreams: []pkg.UpstreamPackage{
+					{
+						Name:    "perl",
+						Version: "5.26.3-419.el8_4.1",
+					},
+				},
 				Metadata: pkg.RpmdbMetadata{
 					SourceRpm: "perl-5.26.3-419.el8_4.1.src.rpm",
 					Epoch:     intRef(0),
@@ -233,6 +255,87 @@ func TestMatcherRpmdb(t *testing.T) {
 			},
 			expectedMatches: map[string]match.Type{},
 		},
+		{
+			name: "package from a module stream only matches advisories for the same module stream",
+			p: pkg.Package{
+				ID:      pkg.ID(uuid.NewString()),
+				Name:    "nodejs",
+				Version: "12.21.0-1.module_el8.3.0+623+1a3e2e4c",
+				Type:    syftPkg.RpmPkg,
+				Metadata: pkg.RpmdbMetadata{
+					ModularityLabel: strRef("nodejs:12:8040020210708131418:522a0ee4"),
+				},
+			},
+			setup: func() (vulnerability.Provider, *distro.Distro, Matcher) {
+				matcher := Matcher{}
+				d, err := distro.New(distro.RedHat, "8", "")
+				if err != nil {
+					t.Fatal("could not create distro: ", err)
+				}
+
+				store := newMockProviderWithModularity("nodejs")
+
+				return store, d, matcher
+			},
+			expectedMatches: map[string]match.Type{
+				"CVE-2021-22918":          match.ExactDirectMatch,
+				"CVE-2021-fake-unmodular": match.ExactDirectMatch,
+			},
+		},
+		{
+			name: "package outside of any module stream does not match module stream advisories",
+			p: pkg.Package{
+				ID:      pkg.ID(uuid.NewString()),
+				Name:    "nodejs",
+				Version: "12.21.0-1.module_el8.3.0+623+1a3e2e4c",
+				Type:    syftPkg.RpmPkg,
+				Metadata: pkg.RpmdbMetadata{
+					ModularityLabel: strRef(""),
+				},
+			},
+			setup: func() (vulnerability.Provider, *distro.Distro, Matcher) {
+				matcher := Matcher{}
+				d, err := distro.New(distro.RedHat, "8", "")
+				if err != nil {
+					t.Fatal("could not create distro: ", err)
+				}
+
+				store := newMockProviderWithModularity("nodejs")
+
+				return store, d, matcher
+			},
+			expectedMatches: map[string]match.Type{
+				"CVE-2021-fake-unmodular": match.ExactDirectMatch,
+			},
+		},
+		{
+			name: "package with an unknown module stream matches all advisories",
+			p: pkg.Package{
+				ID:      pkg.ID(uuid.NewString()),
+				Name:    "nodejs",
+				Version: "12.21.0-1.module_el8.3.0+623+1a3e2e4c",
+				Type:    syftPkg.RpmPkg,
+				Metadata: pkg.RpmdbMetadata{
+					ModularityLabel: nil,
+				},
+			},
+			setup: func() (vulnerability.Provider, *distro.Distro, Matcher) {
+				matcher := Matcher{}
+				d, err := distro.New(distro.RedHat, "8", "")
+				if err != nil {
+					t.Fatal("could not create distro: ", err)
+				}
+
+				store := newMockProviderWithModularity("nodejs")
+
+				return store, d, matcher
+			},
+			expectedMatches: map[string]match.Type{
+				"CVE-2021-22918":          match.ExactDirectMatch,
+				"CVE-2021-22930":          match.ExactDirectMatch,
+				"CVE-2021-fake-unmodular": match.ExactDirectMatch,
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -269,66 +372,6 @@ func TestMatcherRpmdb(t *testing.T) {
 	}
 }
 
-func Test_getNameAndELVersion(t *testing.T) {
-	epoch := 1
-	tests := []struct {
-		name            string
-		metadata        pkg.RpmdbMetadata
-		expectedName    string
-		expectedVersion string
-	}{
-		{
-			name: "sqlite-3.26.0-6.el8.src.rpm",
-			metadata: pkg.RpmdbMetadata{
-				SourceRpm: "sqlite-3.26.0-6.el8.src.rpm",
-			},
-			expectedName:    "sqlite",
-			expectedVersion: "3.26.0-6.el8",
-		},
-		{
-			name: "util-linux-ng-2.17.2-12.28.el6_9.src.rpm",
-			metadata: pkg.RpmdbMetadata{
-				SourceRpm: "util-linux-ng-2.17.2-12.28.el6_9.src.rpm",
-			},
-			expectedName:    "util-linux-ng",
-			expectedVersion: "2.17.2-12.28.el6_9",
-		},
-		{
-			name: "util-linux-ng-2.17.2-12.28.el6_9.2.src.rpm",
-			metadata: pkg.RpmdbMetadata{
-				SourceRpm: "util-linux-ng-2.17.2-12.28.el6_9.2.src.rpm",
-			},
-			expectedName:    "util-linux-ng",
-			expectedVersion: "2.17.2-12.28.el6_9.2",
-		},
-		{
-			name: "epoch 1 + sqlite-3.26.0-6.el8.src.rpm",
-			metadata: pkg.RpmdbMetadata{
-				SourceRpm: "sqlite-3.26.0-6.el8.src.rpm",
-				Epoch:     &epoch,
-			},
-			expectedName:    "sqlite",
-			expectedVersion: "3.26.0-6.el8",
-		},
-		{
-			name: "sqlite-bin-3.26.0-6.el8.src.rpm",
-			metadata: pkg.RpmdbMetadata{
-				SourceRpm: "sqlite-1.26.0-6.el8.src.rpm",
-				Epoch:     &epoch,
-			},
-			expectedName:    "sqlite",
-			expectedVersion: "1.26.0-6.el8",
-		},
-	}
-	for _, test := range tests {
-		t.Run(test.name, func(t *testing.T) {
-			actualName, actualVersion := getNameAndELVersion(test.metadata)
-			assert.Equal(t, test.expectedName, actualName)
-			assert.Equal(t, test.expectedVersion, actualVersion)
-		})
-	}
-}
-
 func Test_addZeroEpicIfApplicable(t *testing.T) {
 	tests := []struct {
 		version  string
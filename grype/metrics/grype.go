@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// Default is the registry of the metrics of grype, which are exposed when grype runs as a service (i.e. serve and watch).
+var Default = NewRegistry()
+
+// the names of the caches of CacheRequests
+const (
+	VulnerabilityCache = "vulnerability-db"
+	SBOMCache          = "sbom"
+	MavenSearchCache   = "maven-search"
+	GitHubCache        = "github-advisories"
+)
+
+var (
+	// Scans are the completed scans by their status ("success" or "failure")
+	Scans = Default.NewCounter("grype_scans_total", "The number of completed scans by their status.", "status")
+	// ScanDuration is how long scans took (cataloging and matching)
+	ScanDuration = Default.NewHistogram("grype_scan_duration_seconds", "How long scans took, from cataloging to matching.",
+		[]float64{1, 5, 10, 30, 60, 120, 300, 600, 1800})
+	// Matches are the matches of successful scans by their severity (of watch, the newly appearing matches)
+	Matches = Default.NewCounter("grype_matches_total", "The number of vulnerability matches found by scans by their severity.", "severity")
+	// CacheRequests are the lookups of the caches by the cache and their result ("hit" or "miss")
+	CacheRequests = Default.NewCounter("grype_cache_requests_total", "The number of cache lookups by the cache and their result.", "cache", "result")
+	// Errors are the failed operations by the operation (e.g. "scan" or "db-update")
+	Errors = Default.NewCounter("grype_errors_total", "The number of failed operations by the operation.", "operation")
+	// DBBuilt is when the loaded vulnerability database was built
+	DBBuilt = Default.NewGauge("grype_db_built_timestamp_seconds", "When the loaded vulnerability database was built, in seconds since the epoch.")
+)
+
+var (
+	dbBuiltLock sync.Mutex
+	dbBuilt     time.Time
+)
+
+func init() {
+	Default.NewGaugeFunc("grype_db_age_seconds", "The age of the loaded vulnerability database.", func() (float64, bool) {
+		dbBuiltLock.Lock()
+		defer dbBuiltLock.Unlock()
+		if dbBuilt.IsZero() {
+			return 0, false
+		}
+		return time.Since(dbBuilt).Seconds(), true
+	})
+}
+
+// SetDBBuilt records when the loaded vulnerability database was built (of DBBuilt and the age of the database).
+func SetDBBuilt(built time.Time) {
+	dbBuiltLock.Lock()
+	defer dbBuiltLock.Unlock()
+	dbBuilt = built
+	DBBuilt.Set(float64(built.Unix()))
+}
+
+// ObserveScan records a completed scan of the given duration, where a failed scan is also counted as an error.
+func ObserveScan(duration time.Duration, err error) {
+	ScanDuration.ObserveDuration(duration)
+	if err != nil {
+		Scans.With("failure").Inc()
+		Errors.With("scan").Inc()
+		return
+	}
+	Scans.With("success").Inc()
+}
+
+// ObserveCache records a lookup of the given cache.
+func ObserveCache(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	CacheRequests.With(cache, result).Inc()
+}
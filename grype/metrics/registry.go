@@ -0,0 +1,332 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// source: https://prometheus.io/docs/instrumenting/exposition_formats/#text-based-format
+
+// contentType is the content type of the Prometheus text exposition format.
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metric is a metric family that is written in the text exposition format.
+type metric interface {
+	write(w io.Writer) error
+}
+
+// Registry is a set of metrics that is exposed in the Prometheus text exposition format.
+type Registry struct {
+	lock    sync.Mutex
+	metrics []metric
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(m metric) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.metrics = append(r.metrics, m)
+}
+
+// NewCounter registers a counter with the given label names (a single unlabeled counter when none are given).
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	c := &Counter{
+		family: family{name: name, help: help, labels: labels},
+		values: make(map[string]*Value),
+		keys:   make(map[string][]string),
+	}
+	r.register(c)
+	return c
+}
+
+// NewGauge registers a gauge that is set to a value.
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	g := &Gauge{family: family{name: name, help: help}}
+	r.register(g)
+	return g
+}
+
+// NewGaugeFunc registers a gauge whose value is computed whenever the metrics are written, where the gauge is omitted
+// while the function is not ready (i.e. returns false).
+func (r *Registry) NewGaugeFunc(name, help string, fn func() (float64, bool)) {
+	r.register(&gaugeFunc{family: family{name: name, help: help}, fn: fn})
+}
+
+// NewHistogram registers a histogram with the given (increasing) upper bounds of its buckets.
+func (r *Registry) NewHistogram(name, help string, buckets []float64) *Histogram {
+	h := &Histogram{
+		family:  family{name: name, help: help},
+		buckets: buckets,
+		counts:  make([]uint64, len(buckets)),
+	}
+	r.register(h)
+	return h
+}
+
+// Write writes all metrics in the text exposition format.
+func (r *Registry) Write(w io.Writer) error {
+	r.lock.Lock()
+	metrics := append([]metric{}, r.metrics...)
+	r.lock.Unlock()
+
+	for _, m := range metrics {
+		if err := m.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Handler returns the HTTP handler that serves the metrics (the "/metrics" endpoint scraped by Prometheus).
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet && req.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		_ = r.Write(w)
+	})
+}
+
+// family is the name, help, and label names of a metric.
+type family struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func (f family) writeHeader(w io.Writer, kind string) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, escapeHelp(f.help), f.name, kind)
+	return err
+}
+
+// labelPairs returns the label pairs of the given label values (e.g. `severity="High"`).
+func (f family) labelPairs(values []string) string {
+	pairs := make([]string, len(f.labels))
+	for i, label := range f.labels {
+		pairs[i] = fmt.Sprintf(`%s="%s"`, label, escapeLabelValue(values[i]))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// Value is a value of a metric that is safe to update concurrently.
+type Value struct {
+	lock  sync.Mutex
+	value float64
+}
+
+// Inc adds one to the value.
+func (v *Value) Inc() {
+	v.Add(1)
+}
+
+// Add adds the given (non-negative, for counters) delta to the value.
+func (v *Value) Add(delta float64) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.value += delta
+}
+
+func (v *Value) get() float64 {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	return v.value
+}
+
+// Counter is a monotonically increasing metric, with a value for every combination of label values.
+type Counter struct {
+	family
+	lock   sync.Mutex
+	values map[string]*Value
+	keys   map[string][]string // the label values of every key of values
+}
+
+// With returns the value of the given label values (in the order of the label names of the counter).
+func (c *Counter) With(labelValues ...string) *Value {
+	if len(labelValues) != len(c.labels) {
+		panic(fmt.Sprintf("metric %s has %d labels, got %d values", c.name, len(c.labels), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\x00")
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	v, ok := c.values[key]
+	if !ok {
+		v = &Value{}
+		c.values[key] = v
+		c.keys[key] = append([]string{}, labelValues...)
+	}
+	return v
+}
+
+// Inc adds one to the counter (of a counter without labels).
+func (c *Counter) Inc() {
+	c.With().Inc()
+}
+
+func (c *Counter) write(w io.Writer) error {
+	if err := c.writeHeader(w, "counter"); err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	samples := make([]string, 0, len(keys))
+	for _, key := range keys {
+		samples = append(samples, sample(c.name, c.labelPairs(c.keys[key]), c.values[key].get()))
+	}
+	c.lock.Unlock()
+
+	if len(c.labels) == 0 && len(samples) == 0 {
+		// note: an unlabeled counter is known to be zero before it is first incremented
+		samples = append(samples, sample(c.name, "", 0))
+	}
+	for _, s := range samples {
+		if _, err := io.WriteString(w, s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Gauge is a metric that is set to a value, which is omitted until it is first set.
+type Gauge struct {
+	family
+	lock  sync.Mutex
+	set   bool
+	value float64
+}
+
+// Set sets the value of the gauge.
+func (g *Gauge) Set(value float64) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.set = true
+	g.value = value
+}
+
+func (g *Gauge) write(w io.Writer) error {
+	g.lock.Lock()
+	set, value := g.set, g.value
+	g.lock.Unlock()
+	if !set {
+		return nil
+	}
+	if err := g.writeHeader(w, "gauge"); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, sample(g.name, "", value))
+	return err
+}
+
+type gaugeFunc struct {
+	family
+	fn func() (float64, bool)
+}
+
+func (g *gaugeFunc) write(w io.Writer) error {
+	value, ok := g.fn()
+	if !ok {
+		return nil
+	}
+	if err := g.writeHeader(w, "gauge"); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, sample(g.name, "", value))
+	return err
+}
+
+// Histogram counts observations (e.g. durations) in buckets.
+type Histogram struct {
+	family
+	buckets []float64
+	lock    sync.Mutex
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+// Observe adds an observation to the histogram.
+func (h *Histogram) Observe(value float64) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for i, bound := range h.buckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+// ObserveDuration adds the seconds of the given duration to the histogram.
+func (h *Histogram) ObserveDuration(d time.Duration) {
+	h.Observe(d.Seconds())
+}
+
+func (h *Histogram) write(w io.Writer) error {
+	if err := h.writeHeader(w, "histogram"); err != nil {
+		return err
+	}
+
+	h.lock.Lock()
+	counts := append([]uint64{}, h.counts...)
+	count, sum := h.count, h.sum
+	h.lock.Unlock()
+
+	var b strings.Builder
+	for i, bound := range h.buckets {
+		b.WriteString(sample(h.name+"_bucket", fmt.Sprintf(`le="%s"`, formatFloat(bound)), float64(counts[i])))
+	}
+	b.WriteString(sample(h.name+"_bucket", `le="+Inf"`, float64(count)))
+	b.WriteString(sample(h.name+"_sum", "", sum))
+	b.WriteString(sample(h.name+"_count", "", float64(count)))
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// sample returns the line of a sample with the given label pairs.
+func sample(name, labelPairs string, value float64) string {
+	if labelPairs != "" {
+		return fmt.Sprintf("%s{%s} %s\n", name, labelPairs, formatFloat(value))
+	}
+	return fmt.Sprintf("%s %s\n", name, formatFloat(value))
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
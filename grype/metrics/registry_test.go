@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Write(t *testing.T) {
+	r := NewRegistry()
+	scans := r.NewCounter("test_scans_total", "The number of scans.")
+	matches := r.NewCounter("test_matches_total", "The number of matches\nby severity.", "severity")
+	built := r.NewGauge("test_db_built_timestamp_seconds", "When the database was built.")
+	r.NewGauge("test_unset", "A gauge that is never set.")
+	r.NewGaugeFunc("test_ready", "A computed gauge.", func() (float64, bool) { return 1.5, true })
+	r.NewGaugeFunc("test_not_ready", "A computed gauge that is not ready.", func() (float64, bool) { return 0, false })
+	duration := r.NewHistogram("test_scan_duration_seconds", "How long scans took.", []float64{1, 10})
+
+	matches.With("High").Add(2)
+	matches.With(`Crit"cal`).Inc()
+	built.Set(1640995200)
+	duration.ObserveDuration(500 * time.Millisecond)
+	duration.Observe(5)
+	duration.Observe(20)
+
+	var b bytes.Buffer
+	require.NoError(t, r.Write(&b))
+	assert.Equal(t, `# HELP test_scans_total The number of scans.
+# TYPE test_scans_total counter
+test_scans_total 0
+# HELP test_matches_total The number of matches\nby severity.
+# TYPE test_matches_total counter
+test_matches_total{severity="Crit\"cal"} 1
+test_matches_total{severity="High"} 2
+# HELP test_db_built_timestamp_seconds When the database was built.
+# TYPE test_db_built_timestamp_seconds gauge
+test_db_built_timestamp_seconds 1.6409952e+09
+# HELP test_ready A computed gauge.
+# TYPE test_ready gauge
+test_ready 1.5
+# HELP test_scan_duration_seconds How long scans took.
+# TYPE test_scan_duration_seconds histogram
+test_scan_duration_seconds_bucket{le="1"} 1
+test_scan_duration_seconds_bucket{le="10"} 2
+test_scan_duration_seconds_bucket{le="+Inf"} 3
+test_scan_duration_seconds_sum 25.5
+test_scan_duration_seconds_count 3
+`, b.String())
+
+	scans.Inc()
+	b.Reset()
+	require.NoError(t, r.Write(&b))
+	assert.Contains(t, b.String(), "test_scans_total 1\n")
+}
+
+func TestCounter_With_WrongLabels(t *testing.T) {
+	c := NewRegistry().NewCounter("test_total", "A counter.", "cache", "result")
+	assert.Panics(t, func() { c.With("sbom") })
+}
+
+func TestRegistry_Handler(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("test_total", "A counter.")
+
+	recorder := httptest.NewRecorder()
+	r.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, contentType, recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), "test_total 0\n")
+
+	recorder = httptest.NewRecorder()
+	r.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/metrics", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+}
@@ -0,0 +1,143 @@
+package notify
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/grype/history"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/grype/watch"
+)
+
+// Event is what a notification is about.
+type Event string
+
+const (
+	// ScanFinishedEvent is the notification of the result of a finished scan (of every target of a scan)
+	ScanFinishedEvent Event = "scan-finished"
+	// NewVulnerabilitiesEvent is the notification of the matches that newly appeared while watching targets
+	NewVulnerabilitiesEvent Event = "new-vulnerabilities"
+)
+
+// AllEvents are the events that webhooks can be notified of.
+var AllEvents = []Event{ScanFinishedEvent, NewVulnerabilitiesEvent}
+
+// Notification is the JSON document posted to webhooks, where the report (or the new matches) is only posted to
+// webhooks with the report payload.
+type Notification struct {
+	Event Event     `json:"event"`
+	Time  time.Time `json:"time"`
+	// Text is a single line description of the notification (which chat webhooks, e.g. of Slack and Teams, show)
+	Text    string  `json:"text"`
+	Summary Summary `json:"summary"`
+	// Report is the JSON document of the scan result (of a scan-finished notification)
+	Report *models.Document `json:"report,omitempty"`
+	// Matches are the newly appearing matches (of a new-vulnerabilities notification)
+	Matches []watch.Event `json:"matches,omitempty"`
+}
+
+// Summary describes the matches of a notification.
+type Summary struct {
+	Targets []string `json:"targets"`
+	// Matches is the number of matches (excluding the ignored matches)
+	Matches int `json:"matches"`
+	// Fixable is the number of matches with a fixed version
+	Fixable int            `json:"fixable"`
+	Counts  history.Counts `json:"counts"`
+	// PolicyPassed is whether the configured policy allowed the scan result (when there is a policy)
+	PolicyPassed *bool `json:"policyPassed,omitempty"`
+}
+
+// NewScanFinished returns the notification of the scan result of the given target.
+func NewScanFinished(target string, doc models.Document) Notification {
+	summary := summarize(doc.Matches)
+	summary.Targets = []string{target}
+	if doc.Policy != nil {
+		passed := doc.Policy.Passed
+		summary.PolicyPassed = &passed
+	}
+
+	text := fmt.Sprintf("grype scan of %s finished: %s", target, describe(summary))
+	if summary.PolicyPassed != nil && !*summary.PolicyPassed {
+		text += " (denied by policy)"
+	}
+	return Notification{
+		Event:   ScanFinishedEvent,
+		Time:    time.Now().UTC(),
+		Text:    text,
+		Summary: summary,
+		Report:  &doc,
+	}
+}
+
+// NewNewVulnerabilities returns the notification of the given newly appearing matches of watched targets.
+func NewNewVulnerabilities(events []watch.Event) Notification {
+	matches := make([]models.Match, 0, len(events))
+	targets := make(map[string]struct{})
+	for _, e := range events {
+		matches = append(matches, e.Match)
+		targets[e.Target] = struct{}{}
+	}
+	summary := summarize(matches)
+	for target := range targets {
+		summary.Targets = append(summary.Targets, target)
+	}
+	sort.Strings(summary.Targets)
+
+	return Notification{
+		Event:   NewVulnerabilitiesEvent,
+		Time:    time.Now().UTC(),
+		Text:    fmt.Sprintf("grype found new vulnerabilities in %s: %s", strings.Join(summary.Targets, ", "), describe(summary)),
+		Summary: summary,
+		Matches: events,
+	}
+}
+
+// withoutReport returns the notification without the report and the matches (the summary payload).
+func (n Notification) withoutReport() Notification {
+	n.Report = nil
+	n.Matches = nil
+	return n
+}
+
+func summarize(matches []models.Match) Summary {
+	summary := Summary{Targets: []string{}, Matches: len(matches)}
+	for _, m := range matches {
+		summary.Counts.Add(vulnerability.ParseSeverity(m.Vulnerability.Severity))
+		if len(m.Vulnerability.Fix.Versions) > 0 {
+			summary.Fixable++
+		}
+	}
+	return summary
+}
+
+// describe returns the number of matches of the summary by severity, e.g. "3 vulnerabilities (1 critical, 2 high)".
+func describe(summary Summary) string {
+	if summary.Matches == 0 {
+		return "no vulnerabilities"
+	}
+	var severities []string
+	for _, c := range []struct {
+		name  string
+		count int
+	}{
+		{"critical", summary.Counts.Critical},
+		{"high", summary.Counts.High},
+		{"medium", summary.Counts.Medium},
+		{"low", summary.Counts.Low},
+		{"negligible", summary.Counts.Negligible},
+		{"unknown", summary.Counts.Unknown},
+	} {
+		if c.count > 0 {
+			severities = append(severities, fmt.Sprintf("%d %s", c.count, c.name))
+		}
+	}
+	noun := "vulnerabilities"
+	if summary.Matches == 1 {
+		noun = "vulnerability"
+	}
+	return fmt.Sprintf("%d %s (%s)", summary.Matches, noun, strings.Join(severities, ", "))
+}
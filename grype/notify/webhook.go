@@ -0,0 +1,138 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+const (
+	// EventHeader is the header of the event of a notification
+	EventHeader = "X-Grype-Event"
+	// SignatureHeader is the header of the HMAC-SHA256 signature of the body of a notification (of webhooks with a
+	// secret), in the format "sha256=<hex digest>"
+	SignatureHeader = "X-Grype-Signature-256"
+)
+
+// Payload is what is posted to a webhook.
+type Payload string
+
+const (
+	// SummaryPayload is the notification without the report (which is enough for chat webhooks)
+	SummaryPayload Payload = "summary"
+	// ReportPayload is the notification with the report of a scan (or the newly appearing matches of watched targets)
+	ReportPayload Payload = "report"
+)
+
+// Webhook is a URL that is notified of scans with a JSON document (see Notification).
+type Webhook struct {
+	URL string
+	// Headers are added to every request (e.g. an authorization header)
+	Headers map[string]string
+	// Secret signs the body of every request with HMAC-SHA256 (see SignatureHeader), unsigned when empty
+	Secret  string
+	Payload Payload
+	// Events are the events the webhook is notified of (every event when empty)
+	Events []Event
+}
+
+func (w Webhook) subscribed(event Event) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Notifier posts notifications to webhooks.
+type Notifier struct {
+	client   *http.Client
+	webhooks []Webhook
+}
+
+// NewNotifier is a *Notifier constructor
+func NewNotifier(client *http.Client, webhooks []Webhook) *Notifier {
+	return &Notifier{
+		client:   client,
+		webhooks: webhooks,
+	}
+}
+
+// Notify posts the notification to every webhook that is subscribed to its event, where a failure to notify a webhook
+// does not prevent notifying the other webhooks.
+func (n *Notifier) Notify(notification Notification) error {
+	var errs error
+	for _, webhook := range n.webhooks {
+		if !webhook.subscribed(notification.Event) {
+			continue
+		}
+		if err := n.post(webhook, notification); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (n *Notifier) post(webhook Webhook, notification Notification) error {
+	if webhook.Payload != ReportPayload {
+		notification = notification.withoutReport()
+	}
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("unable to encode notification: %w", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to notify webhook: %w", err)
+	}
+	for name, value := range webhook.Headers {
+		request.Header.Set(name, value)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(EventHeader, string(notification.Event))
+	if webhook.Secret != "" {
+		request.Header.Set(SignatureHeader, Sign([]byte(webhook.Secret), body))
+	}
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		// note: the error of the client has the URL, which may have credentials (e.g. the token of a chat webhook)
+		return fmt.Errorf("unable to notify webhook of host=%q: %w", request.URL.Host, unwrapURLError(err))
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unable to notify webhook of host=%q: unexpected status %q", request.URL.Host, response.Status)
+	}
+	return nil
+}
+
+// Sign returns the value of the SignatureHeader of the given body, which receivers compare (in constant time) with the
+// header to verify the notification.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// unwrapURLError returns the cause of an error of the client, without the URL of the request.
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
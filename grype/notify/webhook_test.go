@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/watch"
+)
+
+type received struct {
+	header       http.Header
+	body         []byte
+	notification map[string]interface{}
+}
+
+func newReceiver(t *testing.T, status int) (*httptest.Server, *[]received) {
+	var requests []received
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		var notification map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &notification))
+		requests = append(requests, received{header: r.Header, body: body, notification: notification})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func testDocument() models.Document {
+	return models.Document{
+		Matches: []models.Match{
+			{Vulnerability: models.Vulnerability{VulnerabilityMetadata: models.VulnerabilityMetadata{ID: "CVE-2021-1", Severity: "Critical"}, Fix: models.Fix{Versions: []string{"1.1"}}}},
+			{Vulnerability: models.Vulnerability{VulnerabilityMetadata: models.VulnerabilityMetadata{ID: "CVE-2021-2", Severity: "High"}}},
+			{Vulnerability: models.Vulnerability{VulnerabilityMetadata: models.VulnerabilityMetadata{ID: "CVE-2021-3", Severity: "High"}}},
+		},
+	}
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	server, requests := newReceiver(t, http.StatusNoContent)
+
+	notifier := NewNotifier(server.Client(), []Webhook{
+		{URL: server.URL + "/summary", Headers: map[string]string{"Authorization": "Bearer token"}},
+		{URL: server.URL + "/report", Payload: ReportPayload, Secret: "secret"},
+		{URL: server.URL + "/watch", Events: []Event{NewVulnerabilitiesEvent}},
+	})
+	require.NoError(t, notifier.Notify(NewScanFinished("alpine:3.14", testDocument())))
+	require.Len(t, *requests, 2)
+
+	summary := (*requests)[0]
+	assert.Equal(t, "Bearer token", summary.header.Get("Authorization"))
+	assert.Equal(t, "application/json", summary.header.Get("Content-Type"))
+	assert.Equal(t, string(ScanFinishedEvent), summary.header.Get(EventHeader))
+	assert.Empty(t, summary.header.Get(SignatureHeader))
+	assert.Equal(t, "grype scan of alpine:3.14 finished: 3 vulnerabilities (1 critical, 2 high)", summary.notification["text"])
+	assert.NotContains(t, summary.notification, "report")
+	assert.Equal(t, map[string]interface{}{
+		"targets": []interface{}{"alpine:3.14"},
+		"matches": float64(3),
+		"fixable": float64(1),
+		"counts": map[string]interface{}{
+			"critical": float64(1), "high": float64(2), "medium": float64(0), "low": float64(0), "negligible": float64(0), "unknown": float64(0),
+		},
+	}, summary.notification["summary"])
+
+	report := (*requests)[1]
+	assert.Contains(t, report.notification, "report")
+	assert.Equal(t, Sign([]byte("secret"), report.body), report.header.Get(SignatureHeader))
+}
+
+func TestNotifier_Notify_NewVulnerabilities(t *testing.T) {
+	server, requests := newReceiver(t, http.StatusOK)
+
+	notifier := NewNotifier(server.Client(), []Webhook{
+		{URL: server.URL, Payload: ReportPayload, Events: []Event{NewVulnerabilitiesEvent}},
+		{URL: server.URL, Events: []Event{ScanFinishedEvent}},
+	})
+	doc := testDocument()
+	events := []watch.Event{{Target: "app:2", Match: doc.Matches[0]}, {Target: "app:1", Match: doc.Matches[1]}}
+	require.NoError(t, notifier.Notify(NewNewVulnerabilities(events)))
+	require.Len(t, *requests, 1)
+
+	notification := (*requests)[0].notification
+	assert.Equal(t, string(NewVulnerabilitiesEvent), notification["event"])
+	assert.Equal(t, "grype found new vulnerabilities in app:1, app:2: 2 vulnerabilities (1 critical, 1 high)", notification["text"])
+	assert.Len(t, notification["matches"], 2)
+}
+
+func TestNotifier_Notify_Failure(t *testing.T) {
+	failing, _ := newReceiver(t, http.StatusInternalServerError)
+	working, requests := newReceiver(t, http.StatusOK)
+
+	notifier := NewNotifier(http.DefaultClient, []Webhook{
+		{URL: failing.URL},
+		{URL: working.URL},
+	})
+	err := notifier.Notify(NewScanFinished("alpine:3.14", models.Document{}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "500")
+	// the other webhooks are still notified
+	assert.Len(t, *requests, 1)
+}
+
+func TestSign(t *testing.T) {
+	// the signature of the example of the GitHub webhook docs
+	assert.Equal(t, "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17",
+		Sign([]byte("It's a Secret to Everybody"), []byte("Hello, World!")))
+}
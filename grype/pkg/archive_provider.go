@@ -0,0 +1,263 @@
+package pkg
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anchore/grype/internal/log"
+)
+
+// ArchiveConfig controls the (opt-in) unpacking of the archives found within directory and file targets (e.g. the
+// tarballs and wheels of a release), whose contents are cataloged along with the target.
+type ArchiveConfig struct {
+	Enabled bool
+	// MaxDepth is how deep archives within unpacked archives are unpacked (where 1 only unpacks the archives of the target)
+	MaxDepth int
+	// MaxSize is the maximum number of bytes unpacked for a target
+	MaxSize int64
+}
+
+var errArchiveSizeLimit = errors.New("reached the size limit of unpacked archives")
+
+// archiveExtensions are the suffixes of the file names of the archives that are unpacked, where java archives are not
+// unpacked since syft catalogs java archives (including nested java archives) on its own.
+var archiveExtensions = map[string]func(string, containerFS, *archiveBudget) error{
+	".tar":     unpackTar(nil),
+	".tar.gz":  unpackTar(gzipReader),
+	".tgz":     unpackTar(gzipReader),
+	".tar.bz2": unpackTar(bzip2Reader),
+	".tbz2":    unpackTar(bzip2Reader),
+	".zip":     unpackZip,
+	".whl":     unpackZip,
+	".egg":     unpackZip,
+}
+
+// archiveProvider catalogs the contents of the archives within the given directory (or of the given archive), where
+// the contents of each archive are cataloged at the path of the archive (e.g. dist/app.tar.gz/usr/lib/...).
+func archiveProvider(spanCtx context.Context, path string, config ProviderConfig) ([]Package, error) {
+	dest, err := ioutil.TempDir("", "grype-archives-")
+	if err != nil {
+		return nil, fmt.Errorf("unable to create directory for unpacked archives: %w", err)
+	}
+	defer os.RemoveAll(dest)
+
+	count, err := unpackArchives(path, dest, config.Archives)
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, nil
+	}
+	log.Debugf("cataloging the contents of %d archives within %q", count, path)
+
+	packages, _, err := syftProvider(spanCtx, "dir:"+dest, config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to catalog unpacked archives: %w", err)
+	}
+	return packages, nil
+}
+
+// archiveBudget is the number of bytes that can still be unpacked.
+type archiveBudget struct {
+	remaining int64
+}
+
+func (b *archiveBudget) reader(r io.Reader) io.Reader {
+	return &budgetReader{reader: r, budget: b}
+}
+
+type budgetReader struct {
+	reader io.Reader
+	budget *archiveBudget
+}
+
+func (r *budgetReader) Read(p []byte) (int, error) {
+	if r.budget.remaining <= 0 {
+		return 0, errArchiveSizeLimit
+	}
+	if int64(len(p)) > r.budget.remaining {
+		p = p[:r.budget.remaining]
+	}
+	n, err := r.reader.Read(p)
+	r.budget.remaining -= int64(n)
+	return n, err
+}
+
+// unpackArchives unpacks the archives within the given directory (or the given archive) into the destination, and then
+// the archives within the unpacked archives up to the maximum depth, returning the number of unpacked archives. Each
+// archive is unpacked into a directory at the path of the archive (which replaces an archive within an archive). When
+// the size limit is reached the remaining archives are skipped (with a warning).
+func unpackArchives(path, dest string, config ArchiveConfig) (int, error) {
+	budget := &archiveBudget{remaining: config.MaxSize}
+	count := 0
+	roots := []string{path}
+	for depth := 1; depth <= config.MaxDepth && len(roots) > 0; depth++ {
+		var unpacked []string
+		for _, root := range roots {
+			err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+				if err != nil {
+					return err
+				}
+				unpack := archiveUnpacker(info)
+				if unpack == nil {
+					return nil
+				}
+
+				log.Debugf("unpacking archive=%q (depth=%d)", p, depth)
+				target, err := unpackArchive(p, path, dest, unpack, budget)
+				if errors.Is(err, errArchiveSizeLimit) {
+					return err
+				}
+				if err != nil {
+					log.Warnf("unable to unpack archive=%q: %+v", p, err)
+					return nil
+				}
+				count++
+				unpacked = append(unpacked, target)
+				return nil
+			})
+			if errors.Is(err, errArchiveSizeLimit) {
+				log.Warnf("skipping the remaining archives: %+v (%d bytes)", err, config.MaxSize)
+				return count, nil
+			}
+			if err != nil {
+				return count, fmt.Errorf("unable to unpack archives: %w", err)
+			}
+		}
+		roots = unpacked
+	}
+	return count, nil
+}
+
+// unpackArchive unpacks the given archive, which is either within the scanned path (and unpacked at the same path
+// within the destination) or is within an unpacked archive (and replaced by its contents), returning the directory of
+// the contents.
+func unpackArchive(archive, path, dest string, unpack func(string, containerFS, *archiveBudget) error, budget *archiveBudget) (string, error) {
+	if !strings.HasPrefix(archive, dest+string(filepath.Separator)) {
+		name := filepath.Base(archive)
+		if archive != path {
+			var err error
+			if name, err = filepath.Rel(path, archive); err != nil {
+				return "", err
+			}
+		}
+		target := filepath.Join(dest, name)
+		return target, unpack(archive, containerFS{root: target}, budget)
+	}
+
+	unpacking := archive + ".unpacking"
+	if err := unpack(archive, containerFS{root: unpacking}, budget); err != nil {
+		if !errors.Is(err, errArchiveSizeLimit) {
+			os.RemoveAll(unpacking)
+		}
+		// note: the contents that were unpacked before reaching the size limit are still cataloged
+		return "", err
+	}
+	if err := os.Remove(archive); err != nil {
+		return "", err
+	}
+	return archive, os.Rename(unpacking, archive)
+}
+
+// archiveUnpacker returns the function that unpacks the given file (if it is an archive).
+func archiveUnpacker(info os.FileInfo) func(string, containerFS, *archiveBudget) error {
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	name := strings.ToLower(info.Name())
+	for extension, unpack := range archiveExtensions {
+		if strings.HasSuffix(name, extension) {
+			return unpack
+		}
+	}
+	return nil
+}
+
+func gzipReader(r io.Reader) (io.Reader, error) {
+	return gzip.NewReader(r)
+}
+
+func bzip2Reader(r io.Reader) (io.Reader, error) {
+	return bzip2.NewReader(r), nil
+}
+
+// unpackTar returns the function that unpacks a tar archive, which is decompressed by the given function (if any).
+func unpackTar(decompress func(io.Reader) (io.Reader, error)) func(string, containerFS, *archiveBudget) error {
+	return func(path string, fs containerFS, budget *archiveBudget) error {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		var reader io.Reader = f
+		if decompress != nil {
+			if reader, err = decompress(f); err != nil {
+				return err
+			}
+		}
+
+		archive := tar.NewReader(reader)
+		for {
+			header, err := archive.Next()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			switch header.Typeflag {
+			case tar.TypeDir:
+				err = fs.mkdir(header.Name)
+			case tar.TypeReg, tar.TypeRegA:
+				err = fs.file(header.Name, header.FileInfo().Mode(), budget.reader(archive))
+			case tar.TypeSymlink:
+				err = fs.symlink(header.Name, header.Linkname)
+			}
+			if err != nil {
+				return fmt.Errorf("unable to unpack %q: %w", header.Name, err)
+			}
+		}
+	}
+}
+
+func unpackZip(path string, fs containerFS, budget *archiveBudget) error {
+	archive, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() {
+			if err := fs.mkdir(entry.Name); err != nil {
+				return err
+			}
+			continue
+		}
+		if !entry.Mode().IsRegular() {
+			continue
+		}
+		contents, err := entry.Open()
+		if err != nil {
+			return fmt.Errorf("unable to unpack %q: %w", entry.Name, err)
+		}
+		err = fs.file(entry.Name, entry.Mode(), budget.reader(contents))
+		contents.Close()
+		if err != nil {
+			return fmt.Errorf("unable to unpack %q: %w", entry.Name, err)
+		}
+	}
+	return nil
+}
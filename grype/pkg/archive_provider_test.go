@@ -0,0 +1,134 @@
+package pkg
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anchore/syft/syft/pkg/cataloger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const wheelMetadata = "Metadata-Version: 2.1\nName: requests\nVersion: 2.25.0\n"
+
+func zipArchive(t *testing.T, files map[string][]byte) []byte {
+	var archive bytes.Buffer
+	w := zip.NewWriter(&archive)
+	for name, contents := range files {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write(contents)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return archive.Bytes()
+}
+
+func tarGzArchive(t *testing.T, files map[string][]byte) []byte {
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	w := tar.NewWriter(gz)
+	for name, contents := range files {
+		require.NoError(t, w.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(contents))}))
+		_, err := w.Write(contents)
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	require.NoError(t, gz.Close())
+	return archive.Bytes()
+}
+
+// releaseDir returns a directory with a tarball, which contains a wheel within a zip.
+func releaseDir(t *testing.T) string {
+	wheel := zipArchive(t, map[string][]byte{
+		"requests-2.25.0.dist-info/METADATA": []byte(wheelMetadata),
+	})
+	bundle := zipArchive(t, map[string][]byte{
+		"wheels/requests-2.25.0-py3-none-any.whl": wheel,
+	})
+	release := tarGzArchive(t, map[string][]byte{
+		"app-1.0/README":     []byte("app"),
+		"app-1.0/bundle.zip": bundle,
+	})
+
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "dist"), 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "dist", "app-1.0.tar.gz"), release, 0644))
+	return dir
+}
+
+func TestUnpackArchives(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   ArchiveConfig
+		count    int
+		expected []string
+		missing  []string
+	}{
+		{
+			name:   "all levels",
+			config: ArchiveConfig{MaxDepth: 3, MaxSize: 1 << 20},
+			count:  3,
+			expected: []string{
+				"dist/app-1.0.tar.gz/app-1.0/README",
+				"dist/app-1.0.tar.gz/app-1.0/bundle.zip/wheels/requests-2.25.0-py3-none-any.whl/requests-2.25.0.dist-info/METADATA",
+			},
+		},
+		{
+			name:     "depth limit",
+			config:   ArchiveConfig{MaxDepth: 2, MaxSize: 1 << 20},
+			count:    2,
+			expected: []string{"dist/app-1.0.tar.gz/app-1.0/bundle.zip/wheels/requests-2.25.0-py3-none-any.whl"},
+			missing:  []string{"dist/app-1.0.tar.gz/app-1.0/bundle.zip/wheels/requests-2.25.0-py3-none-any.whl/requests-2.25.0.dist-info/METADATA"},
+		},
+		{
+			name:    "size limit",
+			config:  ArchiveConfig{MaxDepth: 3, MaxSize: 2},
+			count:   0,
+			missing: []string{"dist/app-1.0.tar.gz/app-1.0/bundle.zip/wheels"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dest := t.TempDir()
+			count, err := unpackArchives(releaseDir(t), dest, test.config)
+			require.NoError(t, err)
+			assert.Equal(t, test.count, count)
+			for _, name := range test.expected {
+				assert.FileExists(t, filepath.Join(dest, name))
+			}
+			for _, name := range test.missing {
+				assert.NoFileExists(t, filepath.Join(dest, name))
+				assert.NoDirExists(t, filepath.Join(dest, name))
+			}
+		})
+	}
+}
+
+func TestProvide_UnpackArchives(t *testing.T) {
+	dir := releaseDir(t)
+	config := ProviderConfig{
+		CatalogingOptions: cataloger.DefaultConfig(),
+		Archives:          ArchiveConfig{Enabled: true, MaxDepth: 3, MaxSize: 1 << 20},
+	}
+
+	packages, _, err := Provide("dir:"+dir, config)
+	require.NoError(t, err)
+	require.Len(t, packages, 1)
+	assert.Equal(t, "requests", packages[0].Name)
+	assert.Equal(t, "2.25.0", packages[0].Version)
+	require.NotEmpty(t, packages[0].Locations)
+	assert.Equal(t, "dist/app-1.0.tar.gz/app-1.0/bundle.zip/wheels/requests-2.25.0-py3-none-any.whl/requests-2.25.0.dist-info/METADATA", packages[0].Locations[0].RealPath)
+
+	config.Archives.Enabled = false
+	packages, _, err = Provide("dir:"+dir, config)
+	require.NoError(t, err)
+	assert.Empty(t, packages)
+}
@@ -0,0 +1,569 @@
+package pkg
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/stereoscope/pkg/image"
+	"github.com/anchore/syft/syft"
+	"github.com/anchore/syft/syft/format"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/mitchellh/go-homedir"
+)
+
+const (
+	attestationScheme = "att:"
+
+	dsseEnvelopeMediaType = "application/vnd.dsse.envelope.v1+json"
+	inTotoPayloadType     = "application/vnd.in-toto+json"
+
+	cosignCertificateAnnotation   = "dev.sigstore.cosign/certificate"
+	cosignChainAnnotation         = "dev.sigstore.cosign/chain"
+	cosignPredicateTypeAnnotation = "predicateType"
+
+	// maxAttestationSize bounds the size of an attestation that is read from the registry
+	maxAttestationSize = 256 << 20
+)
+
+// sbomPredicateTypes are the (prefixes of the) in-toto predicate types of SBOM attestations.
+var sbomPredicateTypes = []string{"https://syft.dev/bom", "https://spdx.dev/Document", "https://cyclonedx.org/bom"}
+
+// fulcioIssuerOID is the extension of a Fulcio certificate with the OIDC issuer of the identity.
+var fulcioIssuerOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+
+// AttestationConfig controls the (opt-in) discovery of a signed SBOM attestation of an image, which is scanned
+// instead of pulling and cataloging the image. An attestation is only used when it is signed by the key, or by a
+// certificate of the identity (issued by one of the roots).
+type AttestationConfig struct {
+	Enabled bool
+	// Key is the public key that signs the attestations
+	Key crypto.PublicKey
+	// Identity is the subject (e.g. an email or a workflow URI) of the certificate that signs the attestations
+	Identity string
+	// Issuer is the OIDC issuer of the identity (if given)
+	Issuer string
+	// Roots are the certificate authorities of the certificates (e.g. the Fulcio root)
+	Roots *x509.CertPool
+	// SkipVerify scans the attestations of attestation targets ("att:") without verifying their signatures
+	SkipVerify bool
+}
+
+// ParsePublicKey parses a PEM encoded public key (ECDSA, RSA, or ed25519).
+func ParsePublicKey(contents []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(contents)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded public key found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse public key: %w", err)
+	}
+	return key, nil
+}
+
+type dsseEnvelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+	Signatures  []struct {
+		KeyID string `json:"keyid"`
+		Sig   string `json:"sig"`
+	} `json:"signatures"`
+}
+
+type inTotoStatement struct {
+	PredicateType string `json:"predicateType"`
+	Subject       []struct {
+		Name   string            `json:"name"`
+		Digest map[string]string `json:"digest"`
+	} `json:"subject"`
+	Predicate json.RawMessage `json:"predicate"`
+}
+
+// attestation is a DSSE envelope, with the annotations of the layer of the envelope (e.g. the signing certificate).
+type attestation struct {
+	envelope    []byte
+	annotations map[string]string
+}
+
+// attestationProvider catalogs an image from its SBOM attestation, which is discovered through the Referrers API of the
+// registry or else the cosign tag convention ("<repo>:sha256-<digest>.att"). When there is no verified SBOM
+// attestation the image is cataloged as usual.
+func attestationProvider(userInput string, config ProviderConfig) ([]Package, Context, error) {
+	if !config.Attestations.Enabled {
+		return nil, Context{}, errDoesNotProvide
+	}
+	reference, err := registryReference(userInput)
+	if err != nil {
+		return nil, Context{}, errDoesNotProvide
+	}
+
+	digest, attestations, err := registryAttestations(reference, config.RegistryOptions)
+	if err != nil {
+		log.Warnf("unable to use an SBOM attestation of image=%q (cataloging the image instead): %+v", reference, err)
+		return nil, Context{}, errDoesNotProvide
+	}
+	decoded, err := sbomFromAttestations(attestations, &digest, config.Attestations, true)
+	if err != nil {
+		log.Warnf("unable to use an SBOM attestation of image=%q (cataloging the image instead): %+v", reference, err)
+		return nil, Context{}, errDoesNotProvide
+	}
+	if decoded == nil {
+		log.Infof("no verified SBOM attestation found for image=%q (cataloging the image instead)", reference)
+		return nil, Context{}, errDoesNotProvide
+	}
+	log.Infof("scanning the SBOM attestation of image=%q", reference)
+	return attestedPackages(decoded, config)
+}
+
+// attestationInputProvider catalogs the SBOM of an in-toto SBOM attestation given as "att:<file>" (a DSSE envelope,
+// or the JSON lines of "cosign download attestation") or "att:<image>" (the attestations of the image in the
+// registry). Unlike discovered attestations, an attestation that cannot be verified is an error (unless verification
+// is skipped).
+func attestationInputProvider(userInput string, config ProviderConfig) ([]Package, Context, error) {
+	if !strings.HasPrefix(userInput, attestationScheme) {
+		return nil, Context{}, errDoesNotProvide
+	}
+	target := strings.TrimPrefix(userInput, attestationScheme)
+
+	expanded, err := homedir.Expand(target)
+	if err != nil {
+		return nil, Context{}, fmt.Errorf("unable to expand attestation path: %w", err)
+	}
+
+	var subject *v1.Hash
+	var attestations []attestation
+	if info, err := os.Stat(expanded); err == nil && !info.IsDir() {
+		if attestations, err = fileAttestations(expanded); err != nil {
+			return nil, Context{}, err
+		}
+	} else {
+		reference, err := registryReference(target)
+		if err != nil {
+			return nil, Context{}, err
+		}
+		digest, found, err := registryAttestations(reference, config.RegistryOptions)
+		if err != nil {
+			return nil, Context{}, err
+		}
+		subject, attestations = &digest, found
+	}
+
+	verify := !config.Attestations.SkipVerify
+	if !verify {
+		log.Warnf("the signatures of the attestations of %q are not verified", target)
+	} else if config.Attestations.Key == nil && config.Attestations.Identity == "" {
+		return nil, Context{}, fmt.Errorf("unable to verify the attestations of %q without a key or a certificate identity", target)
+	}
+	decoded, err := sbomFromAttestations(attestations, subject, config.Attestations, verify)
+	if err != nil {
+		return nil, Context{}, fmt.Errorf("no usable SBOM attestation in %q: %w", target, err)
+	}
+	if decoded == nil {
+		return nil, Context{}, fmt.Errorf("no SBOM attestation found in %q", target)
+	}
+	return attestedPackages(decoded, config)
+}
+
+func attestedPackages(decoded *sbom.SBOM, config ProviderConfig) ([]Package, Context, error) {
+	return FromCatalogWithConfig(decoded.Artifacts.PackageCatalog, config.Catalog), Context{
+		Source: &decoded.Source,
+		Distro: decoded.Artifacts.LinuxDistribution,
+	}, nil
+}
+
+// PushAttestation attaches the given attestation (a DSSE envelope of an in-toto statement about the image) to the image
+// of the given reference in the registry the way cosign does, as a layer of the "<repo>:sha256-<digest>.att" image
+// (which is created when the image has no attestations yet), and returns the reference of the attestations.
+func PushAttestation(reference string, envelope []byte, registryOptions *image.RegistryOptions) (string, error) {
+	if registryOptions == nil {
+		registryOptions = &image.RegistryOptions{}
+	}
+	var nameOptions []name.Option
+	if registryOptions.InsecureUseHTTP {
+		nameOptions = append(nameOptions, name.Insecure)
+	}
+	ref, err := name.ParseReference(reference, nameOptions...)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse registry reference=%q: %w", reference, err)
+	}
+	options := remoteOptions(ref, registryOptions)
+
+	statement, err := verifyAttestation(attestation{envelope: envelope}, AttestationConfig{}, false)
+	if err != nil {
+		return "", err
+	}
+	descriptor, err := remote.Head(ref, options...)
+	if err != nil {
+		return "", fmt.Errorf("failed to get image descriptor from registry: %w", err)
+	}
+	if !statement.hasSubject(descriptor.Digest) {
+		return "", fmt.Errorf("the attestation is not about the image %s in the registry", descriptor.Digest)
+	}
+
+	tag := ref.Context().Tag(strings.Replace(descriptor.Digest.String(), ":", "-", 1) + ".att")
+	attestations, err := remote.Image(tag, options...)
+	var terr *transport.Error
+	switch {
+	case errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound:
+		attestations = empty.Image
+	case err != nil:
+		return "", fmt.Errorf("unable to get the attestations of the image: %w", err)
+	}
+
+	attestations, err = mutate.Append(attestations, mutate.Addendum{
+		Layer:       static.NewLayer(envelope, dsseEnvelopeMediaType),
+		MediaType:   dsseEnvelopeMediaType,
+		Annotations: map[string]string{cosignPredicateTypeAnnotation: statement.PredicateType},
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := remote.Write(tag, attestations, options...); err != nil {
+		return "", fmt.Errorf("unable to push attestation: %w", err)
+	}
+	return tag.String(), nil
+}
+
+// registryAttestations returns the digest of the given image and its attestations in the registry, which are found
+// through the Referrers API of the registry and the cosign tag convention.
+func registryAttestations(reference string, registryOptions *image.RegistryOptions) (v1.Hash, []attestation, error) {
+	if registryOptions == nil {
+		registryOptions = &image.RegistryOptions{}
+	}
+	var nameOptions []name.Option
+	if registryOptions.InsecureUseHTTP {
+		nameOptions = append(nameOptions, name.Insecure)
+	}
+	ref, err := name.ParseReference(reference, nameOptions...)
+	if err != nil {
+		return v1.Hash{}, nil, fmt.Errorf("unable to parse registry reference=%q: %w", reference, err)
+	}
+	options := remoteOptions(ref, registryOptions)
+
+	descriptor, err := remote.Head(ref, options...)
+	if err != nil {
+		return v1.Hash{}, nil, fmt.Errorf("failed to get image descriptor from registry: %w", err)
+	}
+
+	manifests, err := referrers(ref, descriptor.Digest, registryOptions)
+	if err != nil {
+		log.Debugf("unable to list the referrers of image=%q: %+v", reference, err)
+	}
+	// the cosign tag convention, for registries without the Referrers API (or attestations attached by cosign)
+	manifests = append(manifests, ref.Context().Tag(strings.Replace(descriptor.Digest.String(), ":", "-", 1)+".att"))
+
+	var attestations []attestation
+	for _, manifest := range manifests {
+		img, err := remote.Image(manifest, options...)
+		if err != nil {
+			log.Debugf("no attestations at %q: %+v", manifest, err)
+			continue
+		}
+		found, err := manifestAttestations(img)
+		if err != nil {
+			log.Warnf("skipping attestations at %q: %+v", manifest, err)
+			continue
+		}
+		attestations = append(attestations, found...)
+	}
+	return descriptor.Digest, attestations, nil
+}
+
+// referrers returns the manifests that refer to the given image digest, from the Referrers API of the registry.
+func referrers(ref name.Reference, digest v1.Hash, registryOptions *image.RegistryOptions) ([]name.Reference, error) {
+	repository := ref.Context()
+	rt, err := transport.New(repository.Registry, registryAuthenticator(ref, registryOptions), registryTransport(registryOptions), []string{repository.Scope(transport.PullScope)})
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s://%s/v2/%s/referrers/%s", repository.Registry.Scheme(), repository.RegistryStr(), repository.RepositoryStr(), digest)
+	response, err := (&http.Client{Transport: rt}).Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the registry responded with status %d (the Referrers API may not be supported)", response.StatusCode)
+	}
+
+	var index v1.IndexManifest
+	if err := json.NewDecoder(io.LimitReader(response.Body, maxAttestationSize)).Decode(&index); err != nil {
+		return nil, fmt.Errorf("unable to decode referrers: %w", err)
+	}
+	var manifests []name.Reference
+	for _, m := range index.Manifests {
+		if m.MediaType.IsImage() {
+			manifests = append(manifests, repository.Digest(m.Digest.String()))
+		}
+	}
+	return manifests, nil
+}
+
+// manifestAttestations returns the DSSE envelopes of the layers of the given manifest.
+func manifestAttestations(img v1.Image) ([]attestation, error) {
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, err
+	}
+
+	var attestations []attestation
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != dsseEnvelopeMediaType {
+			continue
+		}
+		blob, err := img.LayerByDigest(layer.Digest)
+		if err != nil {
+			return nil, err
+		}
+		reader, err := blob.Compressed()
+		if err != nil {
+			return nil, err
+		}
+		contents, err := ioutil.ReadAll(io.LimitReader(reader, maxAttestationSize))
+		reader.Close()
+		if err != nil {
+			return nil, err
+		}
+		attestations = append(attestations, attestation{envelope: contents, annotations: layer.Annotations})
+	}
+	return attestations, nil
+}
+
+// fileAttestations returns the DSSE envelopes of the given file, which has a single envelope or an envelope per line.
+func fileAttestations(path string) ([]attestation, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open attestation: %w", err)
+	}
+	defer f.Close()
+
+	var attestations []attestation
+	decoder := json.NewDecoder(io.LimitReader(f, maxAttestationSize))
+	for {
+		var envelope json.RawMessage
+		err := decoder.Decode(&envelope)
+		if errors.Is(err, io.EOF) {
+			return attestations, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode attestation: %w", err)
+		}
+		attestations = append(attestations, attestation{envelope: envelope})
+	}
+}
+
+// sbomFromAttestations returns the SBOM of the first (verified) SBOM attestation, which must be a statement about the
+// given subject (if any), where SBOMs in formats that cannot be decoded are skipped.
+func sbomFromAttestations(attestations []attestation, subject *v1.Hash, config AttestationConfig, verify bool) (*sbom.SBOM, error) {
+	var errs []string
+	for _, att := range attestations {
+		statement, err := verifyAttestation(att, config, verify)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		if subject != nil && !statement.hasSubject(*subject) {
+			errs = append(errs, fmt.Sprintf("the attestation is not about the image %s", subject))
+			continue
+		}
+		document := statement.sbom()
+		if document == nil {
+			continue
+		}
+		decoded, formatOption, err := syft.Decode(bytes.NewReader(document))
+		if err != nil || formatOption == format.UnknownFormatOption {
+			errs = append(errs, fmt.Sprintf("unable to decode the SBOM of predicate type %q", statement.PredicateType))
+			continue
+		}
+		return decoded, nil
+	}
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "; "))
+	}
+	return nil, nil
+}
+
+// verifyAttestation verifies the signature of the given DSSE envelope (unless verification is skipped) and returns its
+// in-toto statement.
+func verifyAttestation(att attestation, config AttestationConfig, verify bool) (*inTotoStatement, error) {
+	var envelope dsseEnvelope
+	if err := json.Unmarshal(att.envelope, &envelope); err != nil {
+		return nil, fmt.Errorf("unable to decode DSSE envelope: %w", err)
+	}
+	if envelope.PayloadType != inTotoPayloadType {
+		return nil, fmt.Errorf("unsupported DSSE payload type %q", envelope.PayloadType)
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode DSSE payload: %w", err)
+	}
+
+	var statement inTotoStatement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return nil, fmt.Errorf("unable to decode in-toto statement: %w", err)
+	}
+	if !verify {
+		return &statement, nil
+	}
+
+	key, err := attestationKey(att.annotations, config)
+	if err != nil {
+		return nil, err
+	}
+
+	message := dssePAE(envelope.PayloadType, payload)
+	verified := false
+	for _, signature := range envelope.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(signature.Sig)
+		if err != nil {
+			continue
+		}
+		if verifySignature(key, message, sig) {
+			verified = true
+			break
+		}
+	}
+	if !verified {
+		return nil, fmt.Errorf("the attestation signature could not be verified")
+	}
+	return &statement, nil
+}
+
+// attestationKey returns the key that must have signed an attestation: the configured key, or else the key of the
+// certificate of the attestation, when the certificate is issued by one of the roots for the configured identity.
+// Since the transparency log is not consulted, the certificate is verified at the start of its validity period (the
+// short-lived certificates of keyless signing expire minutes after signing).
+func attestationKey(annotations map[string]string, config AttestationConfig) (crypto.PublicKey, error) {
+	if config.Key != nil {
+		return config.Key, nil
+	}
+	if config.Identity == "" || config.Roots == nil {
+		return nil, fmt.Errorf("no key or certificate identity to verify the attestation")
+	}
+
+	block, _ := pem.Decode([]byte(annotations[cosignCertificateAnnotation]))
+	if block == nil {
+		return nil, fmt.Errorf("the attestation has no signing certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse signing certificate: %w", err)
+	}
+
+	intermediates := x509.NewCertPool()
+	intermediates.AppendCertsFromPEM([]byte(annotations[cosignChainAnnotation]))
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         config.Roots,
+		Intermediates: intermediates,
+		CurrentTime:   cert.NotBefore,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	}); err != nil {
+		return nil, fmt.Errorf("untrusted signing certificate: %w", err)
+	}
+
+	if !certificateHasIdentity(cert, config.Identity) {
+		return nil, fmt.Errorf("the signing certificate is not issued for identity %q", config.Identity)
+	}
+	if config.Issuer != "" && certificateIssuer(cert) != config.Issuer {
+		return nil, fmt.Errorf("the signing certificate is not issued by OIDC issuer %q", config.Issuer)
+	}
+	return cert.PublicKey, nil
+}
+
+func certificateHasIdentity(cert *x509.Certificate, identity string) bool {
+	for _, email := range cert.EmailAddresses {
+		if email == identity {
+			return true
+		}
+	}
+	for _, uri := range cert.URIs {
+		if uri.String() == identity {
+			return true
+		}
+	}
+	return false
+}
+
+func certificateIssuer(cert *x509.Certificate) string {
+	for _, extension := range cert.Extensions {
+		if extension.Id.Equal(fulcioIssuerOID) {
+			return string(extension.Value)
+		}
+	}
+	return ""
+}
+
+// dssePAE is the pre-authentication encoding of a DSSE payload, which is what is signed.
+func dssePAE(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+func verifySignature(key crypto.PublicKey, message, sig []byte) bool {
+	digest := sha256.Sum256(message)
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(k, digest[:], sig)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+	case ed25519.PublicKey:
+		return ed25519.Verify(k, message, sig)
+	}
+	return false
+}
+
+func (s inTotoStatement) hasSubject(digest v1.Hash) bool {
+	for _, subject := range s.Subject {
+		if subject.Digest[digest.Algorithm] == digest.Hex {
+			return true
+		}
+	}
+	return false
+}
+
+// sbom returns the SBOM of the predicate of an SBOM statement, which is either the SBOM document or (as attested by
+// cosign for non-JSON SBOMs) an object with the SBOM document as "Data".
+func (s inTotoStatement) sbom() []byte {
+	isSBOM := false
+	for _, predicateType := range sbomPredicateTypes {
+		isSBOM = isSBOM || strings.HasPrefix(s.PredicateType, predicateType)
+	}
+	if !isSBOM {
+		return nil
+	}
+
+	var wrapped struct {
+		Data string `json:"Data"`
+	}
+	if err := json.Unmarshal(s.Predicate, &wrapped); err == nil && wrapped.Data != "" {
+		return []byte(wrapped.Data)
+	}
+	if len(s.Predicate) == 0 || string(s.Predicate) == "null" {
+		return nil
+	}
+	return s.Predicate
+}
@@ -0,0 +1,355 @@
+package pkg
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anchore/stereoscope/pkg/image"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signedAttestation returns a DSSE envelope of an SBOM statement about the given digest, signed by the given key.
+func signedAttestation(t *testing.T, key *ecdsa.PrivateKey, subject v1.Hash) []byte {
+	predicate, err := ioutil.ReadFile("test-fixtures/syft-alpine.json")
+	require.NoError(t, err)
+	statement, err := json.Marshal(map[string]interface{}{
+		"_type":         "https://in-toto.io/Statement/v0.1",
+		"predicateType": "https://syft.dev/bom",
+		"subject":       []interface{}{map[string]interface{}{"name": "app", "digest": map[string]string{subject.Algorithm: subject.Hex}}},
+		"predicate":     json.RawMessage(predicate),
+	})
+	require.NoError(t, err)
+
+	digest := sha256.Sum256(dssePAE(inTotoPayloadType, statement))
+	sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+
+	envelope, err := json.Marshal(map[string]interface{}{
+		"payloadType": inTotoPayloadType,
+		"payload":     base64.StdEncoding.EncodeToString(statement),
+		"signatures":  []interface{}{map[string]string{"sig": base64.StdEncoding.EncodeToString(sig)}},
+	})
+	require.NoError(t, err)
+	return envelope
+}
+
+func attestationImage(t *testing.T, envelope []byte, annotations map[string]string) v1.Image {
+	img, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer:       static.NewLayer(envelope, dsseEnvelopeMediaType),
+		MediaType:   dsseEnvelopeMediaType,
+		Annotations: annotations,
+	})
+	require.NoError(t, err)
+	return img
+}
+
+// signingCertificate returns a CA and a code signing certificate of the given email (for the key), issued by the CA.
+func signingCertificate(t *testing.T, key *ecdsa.PrivateKey, email string) (*x509.CertPool, string) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	leafTemplate := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       time.Now().Add(-time.Hour),
+		NotAfter:        time.Now().Add(-50 * time.Minute),
+		EmailAddresses:  []string{email},
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		ExtraExtensions: []pkix.Extension{{Id: fulcioIssuerOID, Value: []byte("https://accounts.example.com")}},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	return roots, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER}))
+}
+
+func TestAttestationProvider(t *testing.T) {
+	referrers := make(map[string]v1.IndexManifest)
+	registryHandler := registry.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/referrers/") {
+			index, ok := referrers[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(index))
+			return
+		}
+		registryHandler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	roots, certificate := signingCertificate(t, key, "builder@example.com")
+
+	// pushImage pushes an image with the given attestation (by the cosign tag convention or as a referrer)
+	pushImage := func(repository string, attestation func(v1.Hash) v1.Image, asReferrer bool) string {
+		img, err := random.Image(64, 1)
+		require.NoError(t, err)
+		digest, err := img.Digest()
+		require.NoError(t, err)
+		ref, err := name.ParseReference(fmt.Sprintf("%s/%s:latest", host, repository))
+		require.NoError(t, err)
+		require.NoError(t, remote.Write(ref, img))
+		if attestation == nil {
+			return ref.String()
+		}
+
+		att := attestation(digest)
+		if !asReferrer {
+			tag := ref.Context().Tag(strings.Replace(digest.String(), ":", "-", 1) + ".att")
+			require.NoError(t, remote.Write(tag, att))
+			return ref.String()
+		}
+		attDigest, err := att.Digest()
+		require.NoError(t, err)
+		require.NoError(t, remote.Write(ref.Context().Digest(attDigest.String()), att))
+		referrers[fmt.Sprintf("/v2/%s/referrers/%s", repository, digest)] = v1.IndexManifest{
+			SchemaVersion: 2,
+			MediaType:     types.OCIImageIndex,
+			Manifests:     []v1.Descriptor{{MediaType: types.OCIManifestSchema1, Digest: attDigest}},
+		}
+		return ref.String()
+	}
+	signedBy := func(signer *ecdsa.PrivateKey, annotations map[string]string) func(v1.Hash) v1.Image {
+		return func(digest v1.Hash) v1.Image {
+			return attestationImage(t, signedAttestation(t, signer, digest), annotations)
+		}
+	}
+
+	keyConfig := AttestationConfig{Enabled: true, Key: crypto.PublicKey(&key.PublicKey)}
+	tests := []struct {
+		name        string
+		attestation func(v1.Hash) v1.Image
+		asReferrer  bool
+		config      AttestationConfig
+		provides    bool
+	}{
+		{
+			name:        "cosign tag signed by key",
+			attestation: signedBy(key, nil),
+			config:      keyConfig,
+			provides:    true,
+		},
+		{
+			name:        "referrer signed by key",
+			attestation: signedBy(key, nil),
+			asReferrer:  true,
+			config:      keyConfig,
+			provides:    true,
+		},
+		{
+			name:        "signed by another key",
+			attestation: signedBy(otherKey, nil),
+			config:      keyConfig,
+		},
+		{
+			name: "statement about another image",
+			attestation: func(v1.Hash) v1.Image {
+				return attestationImage(t, signedAttestation(t, key, v1.Hash{Algorithm: "sha256", Hex: strings.Repeat("0", 64)}), nil)
+			},
+			config: keyConfig,
+		},
+		{
+			name:   "no attestation",
+			config: keyConfig,
+		},
+		{
+			name:        "disabled",
+			attestation: signedBy(key, nil),
+			config:      AttestationConfig{Key: keyConfig.Key},
+		},
+		{
+			name:        "certificate of identity",
+			attestation: signedBy(key, map[string]string{cosignCertificateAnnotation: certificate}),
+			config:      AttestationConfig{Enabled: true, Identity: "builder@example.com", Issuer: "https://accounts.example.com", Roots: roots},
+			provides:    true,
+		},
+		{
+			name:        "certificate of another identity",
+			attestation: signedBy(key, map[string]string{cosignCertificateAnnotation: certificate}),
+			config:      AttestationConfig{Enabled: true, Identity: "someone@example.com", Roots: roots},
+		},
+		{
+			name:        "certificate of another issuer",
+			attestation: signedBy(key, map[string]string{cosignCertificateAnnotation: certificate}),
+			config:      AttestationConfig{Enabled: true, Identity: "builder@example.com", Issuer: "https://other.example.com", Roots: roots},
+		},
+		{
+			name:        "certificate of an untrusted CA",
+			attestation: signedBy(key, map[string]string{cosignCertificateAnnotation: certificate}),
+			config:      AttestationConfig{Enabled: true, Identity: "builder@example.com", Roots: x509.NewCertPool()},
+		},
+	}
+
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			input := "registry:" + pushImage(fmt.Sprintf("app%d", i), test.attestation, test.asReferrer)
+			config := ProviderConfig{
+				RegistryOptions: &image.RegistryOptions{InsecureUseHTTP: true},
+				Attestations:    test.config,
+			}
+
+			packages, ctx, err := attestationProvider(input, config)
+			if !test.provides {
+				assert.ErrorIs(t, err, errDoesNotProvide)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, packages)
+			require.NotNil(t, ctx.Source)
+			assert.Equal(t, "alpine:fake", ctx.Source.ImageMetadata.UserInput)
+		})
+	}
+}
+
+func TestAttestationInputProvider(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	subject := v1.Hash{Algorithm: "sha256", Hex: strings.Repeat("a", 64)}
+
+	// as downloaded by cosign: an envelope per line
+	path := filepath.Join(t.TempDir(), "sbom.att.json")
+	contents := append(append(signedAttestation(t, key, subject), '\n'), signedAttestation(t, key, subject)...)
+	require.NoError(t, ioutil.WriteFile(path, contents, 0600))
+
+	tests := []struct {
+		name     string
+		input    string
+		config   AttestationConfig
+		provides bool
+		wantErr  bool
+	}{
+		{
+			name:     "verified by key",
+			input:    "att:" + path,
+			config:   AttestationConfig{Key: crypto.PublicKey(&key.PublicKey)},
+			provides: true,
+		},
+		{
+			name:    "signed by another key",
+			input:   "att:" + path,
+			config:  AttestationConfig{Key: crypto.PublicKey(&otherKey.PublicKey)},
+			wantErr: true,
+		},
+		{
+			name:    "without a key",
+			input:   "att:" + path,
+			wantErr: true,
+		},
+		{
+			name:     "verification skipped",
+			input:    "att:" + path,
+			config:   AttestationConfig{SkipVerify: true},
+			provides: true,
+		},
+		{
+			name:   "not an attestation target",
+			input:  "sbom:" + path,
+			config: AttestationConfig{Key: crypto.PublicKey(&key.PublicKey)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			packages, _, err := attestationInputProvider(test.input, ProviderConfig{Attestations: test.config})
+			switch {
+			case test.wantErr:
+				assert.Error(t, err)
+				assert.NotErrorIs(t, err, errDoesNotProvide)
+			case test.provides:
+				require.NoError(t, err)
+				assert.NotEmpty(t, packages)
+			default:
+				assert.ErrorIs(t, err, errDoesNotProvide)
+			}
+		})
+	}
+}
+
+func TestPushAttestation(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	registryOptions := &image.RegistryOptions{InsecureUseHTTP: true}
+
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	digest, err := img.Digest()
+	require.NoError(t, err)
+	reference := strings.TrimPrefix(server.URL, "http://") + "/app:latest"
+	ref, err := name.ParseReference(reference)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, img))
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	// pushing again appends the attestation to the attestations of the image
+	for i := 0; i < 2; i++ {
+		pushed, err := PushAttestation(reference, signedAttestation(t, key, digest), registryOptions)
+		require.NoError(t, err)
+		assert.Equal(t, ref.Context().Tag(strings.Replace(digest.String(), ":", "-", 1)+".att").String(), pushed)
+	}
+
+	found, attestations, err := registryAttestations(reference, registryOptions)
+	require.NoError(t, err)
+	assert.Equal(t, digest, found)
+	require.Len(t, attestations, 2)
+	assert.Equal(t, "https://syft.dev/bom", attestations[0].annotations[cosignPredicateTypeAnnotation])
+
+	decoded, err := sbomFromAttestations(attestations, &digest, AttestationConfig{Key: crypto.PublicKey(&key.PublicKey)}, true)
+	require.NoError(t, err)
+	assert.NotNil(t, decoded)
+
+	_, err = PushAttestation(reference, signedAttestation(t, key, v1.Hash{Algorithm: "sha256", Hex: strings.Repeat("0", 64)}), registryOptions)
+	assert.Error(t, err)
+}
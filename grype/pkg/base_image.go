@@ -0,0 +1,41 @@
+package pkg
+
+import (
+	"encoding/json"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/source"
+)
+
+const (
+	// BaseImageNameAnnotation is the OCI image manifest annotation with the reference of the base image of an image.
+	BaseImageNameAnnotation = "org.opencontainers.image.base.name"
+	// BaseImageDigestAnnotation is the OCI image manifest annotation with the manifest digest of the base image.
+	BaseImageDigestAnnotation = "org.opencontainers.image.base.digest"
+)
+
+// BaseImageReference returns the reference of the base image of the given image source, according to the annotations
+// of the image manifest (pinned to the annotated digest when there is one), or an empty string when the manifest does
+// not describe the base image.
+func BaseImageReference(src *source.Metadata) string {
+	if src == nil || src.Scheme != source.ImageScheme || len(src.ImageMetadata.RawManifest) == 0 {
+		return ""
+	}
+
+	var manifest struct {
+		Annotations map[string]string `json:"annotations"`
+	}
+	if err := json.Unmarshal(src.ImageMetadata.RawManifest, &manifest); err != nil {
+		log.Warnf("unable to read the annotations of the image manifest: %+v", err)
+		return ""
+	}
+
+	name := manifest.Annotations[BaseImageNameAnnotation]
+	if name == "" {
+		return ""
+	}
+	if digest := manifest.Annotations[BaseImageDigestAnnotation]; digest != "" {
+		return name + "@" + digest
+	}
+	return name
+}
@@ -0,0 +1,59 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestBaseImageReference(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      *source.Metadata
+		expected string
+	}{
+		{
+			name: "name and digest",
+			src: &source.Metadata{
+				Scheme: source.ImageScheme,
+				ImageMetadata: source.ImageMetadata{
+					RawManifest: []byte(`{"annotations": {"org.opencontainers.image.base.name": "docker.io/library/alpine:3.14", "org.opencontainers.image.base.digest": "sha256:e1c082e3d3c45cccac829840a25941e679c25d438cc8412c2fa221cf1a824e6a"}}`),
+				},
+			},
+			expected: "docker.io/library/alpine:3.14@sha256:e1c082e3d3c45cccac829840a25941e679c25d438cc8412c2fa221cf1a824e6a",
+		},
+		{
+			name: "name only",
+			src: &source.Metadata{
+				Scheme: source.ImageScheme,
+				ImageMetadata: source.ImageMetadata{
+					RawManifest: []byte(`{"annotations": {"org.opencontainers.image.base.name": "docker.io/library/alpine:3.14"}}`),
+				},
+			},
+			expected: "docker.io/library/alpine:3.14",
+		},
+		{
+			name: "no annotations",
+			src: &source.Metadata{
+				Scheme: source.ImageScheme,
+				ImageMetadata: source.ImageMetadata{
+					RawManifest: []byte(`{"schemaVersion": 2}`),
+				},
+			},
+		},
+		{
+			name: "not an image",
+			src:  &source.Metadata{Scheme: source.DirectoryScheme},
+		},
+		{
+			name: "no source",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, BaseImageReference(test.src))
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package pkg
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+const binaryCatalogerName = "binary-cataloger"
+
+// binaryCPETarget is the vendor and product of a CPE that a classified binary may be matched by.
+type binaryCPETarget struct {
+	vendor  string
+	product string
+}
+
+// binaryClassifier identifies a well-known component within a (possibly statically linked) binary from evidence
+// within the binary contents.
+type binaryClassifier struct {
+	// name is the name of the package for the component
+	name string
+	// globs select the files which may contain the component
+	globs []string
+	// evidence captures the version of the component (with a "version" named group) from the binary contents
+	evidence *regexp.Regexp
+	// cpes are the only CPE vendor and product combinations that the component may be matched by
+	cpes []binaryCPETarget
+}
+
+var binaryClassifiers = []binaryClassifier{
+	{
+		name:     "openssl",
+		globs:    []string{"**/bin/openssl", "**/libcrypto.so*", "**/libssl.so*"},
+		evidence: regexp.MustCompile(`OpenSSL (?P<version>[0-9]+\.[0-9]+\.[0-9]+[a-z]?)\s`),
+		cpes:     []binaryCPETarget{{vendor: "openssl", product: "openssl"}},
+	},
+	{
+		name:     "nginx",
+		globs:    []string{"**/nginx"},
+		evidence: regexp.MustCompile(`nginx/(?P<version>[0-9]+\.[0-9]+\.[0-9]+)`),
+		cpes:     []binaryCPETarget{{vendor: "f5", product: "nginx"}, {vendor: "nginx", product: "nginx"}},
+	},
+	{
+		name:     "node",
+		globs:    []string{"**/bin/node"},
+		evidence: regexp.MustCompile(`nodejs\.org/download/release/v(?P<version>[0-9]+\.[0-9]+\.[0-9]+)/`),
+		cpes:     []binaryCPETarget{{vendor: "nodejs", product: "node.js"}},
+	},
+	{
+		name:     "busybox",
+		globs:    []string{"**/busybox"},
+		evidence: regexp.MustCompile(`BusyBox v(?P<version>[0-9]+\.[0-9]+\.[0-9]+)`),
+		cpes:     []binaryCPETarget{{vendor: "busybox", product: "busybox"}},
+	},
+	{
+		name:     "httpd",
+		globs:    []string{"**/bin/httpd"},
+		evidence: regexp.MustCompile(`Apache/(?P<version>2\.[0-9]+\.[0-9]+)`),
+		cpes:     []binaryCPETarget{{vendor: "apache", product: "http_server"}},
+	},
+}
+
+// AllowedBinaryCPE indicates if the given CPE is one that a binary package may be matched by. Binaries identified by
+// classifiers are only matched by the exact vendor and product combinations that are known to describe the classified
+// component, otherwise (as with generated CPEs) there is a high chance of false positives.
+func AllowedBinaryCPE(c pkg.CPE) bool {
+	for _, classifier := range binaryClassifiers {
+		for _, target := range classifier.cpes {
+			if c.Vendor == target.vendor && c.Product == target.product {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// binaryCataloger finds well-known components within binaries (e.g. a statically linked openssl) which are not owned
+// by any package manager, and therefore would otherwise not be matched.
+type binaryCataloger struct {
+	classifiers []binaryClassifier
+}
+
+func newBinaryCataloger() *binaryCataloger {
+	return &binaryCataloger{
+		classifiers: binaryClassifiers,
+	}
+}
+
+func (c *binaryCataloger) Name() string {
+	return binaryCatalogerName
+}
+
+func (c *binaryCataloger) Catalog(resolver source.FileResolver) ([]pkg.Package, []artifact.Relationship, error) {
+	var packages []pkg.Package
+	for _, classifier := range c.classifiers {
+		// the same file may be selected by several globs
+		seen := make(map[source.Coordinates]struct{})
+		for _, glob := range classifier.globs {
+			locations, err := resolver.FilesByGlob(glob)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unable to find binaries by glob=%q: %w", glob, err)
+			}
+			for _, location := range locations {
+				if _, ok := seen[location.Coordinates]; ok {
+					continue
+				}
+				seen[location.Coordinates] = struct{}{}
+
+				version, err := classifier.classify(resolver, location)
+				if err != nil {
+					log.Warnf("unable to classify binary at location=%+v: %+v", location, err)
+					continue
+				}
+				if version == "" {
+					continue
+				}
+				packages = append(packages, classifier.newPackage(version, location))
+			}
+		}
+	}
+
+	sort.SliceStable(packages, func(i, j int) bool {
+		return packages[i].Locations[0].RealPath < packages[j].Locations[0].RealPath
+	})
+	return packages, nil, nil
+}
+
+// classify returns the version of the component found within the given binary, or an empty string if the component
+// was not found.
+func (b binaryClassifier) classify(resolver source.FileResolver, location source.Location) (string, error) {
+	reader, err := resolver.FileContentsByLocation(location)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			log.Warnf("unable to close binary at location=%+v: %+v", location, err)
+		}
+	}()
+
+	contents, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+
+	match := b.evidence.FindSubmatch(contents)
+	if match == nil {
+		return "", nil
+	}
+	return string(match[b.evidence.SubexpIndex("version")]), nil
+}
+
+func (b binaryClassifier) newPackage(version string, location source.Location) pkg.Package {
+	var cpes []pkg.CPE
+	for _, target := range b.cpes {
+		cpes = append(cpes, pkg.MustCPE(fmt.Sprintf("cpe:2.3:a:%s:%s:%s:*:*:*:*:*:*:*", target.vendor, target.product, version)))
+	}
+
+	p := pkg.Package{
+		Name:         b.name,
+		Version:      version,
+		FoundBy:      binaryCatalogerName,
+		Locations:    []source.Location{location},
+		Type:         BinaryPkg,
+		CPEs:         cpes,
+		PURL:         fmt.Sprintf("pkg:generic/%s@%s", b.name, version),
+		MetadataType: BinaryMetadataType,
+		Metadata: BinaryMetadata{
+			Classifier: b.name,
+		},
+	}
+	p.SetID()
+	return p
+}
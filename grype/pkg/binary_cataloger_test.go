@@ -0,0 +1,119 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestBinaryCataloger(t *testing.T) {
+	src, err := source.NewFromDirectory("test-fixtures/binary")
+	require.NoError(t, err)
+
+	resolver, err := src.FileResolver(source.SquashedScope)
+	require.NoError(t, err)
+
+	packages, _, err := newBinaryCataloger().Catalog(resolver)
+	require.NoError(t, err)
+
+	type expected struct {
+		name    string
+		version string
+		path    string
+		cpes    []string
+	}
+
+	var actual []expected
+	for _, p := range packages {
+		assert.Equal(t, BinaryPkg, p.Type)
+		assert.Equal(t, BinaryMetadataType, p.MetadataType)
+		assert.Equal(t, BinaryMetadata{Classifier: p.Name}, p.Metadata)
+
+		var cpes []string
+		for _, c := range p.CPEs {
+			cpes = append(cpes, c.BindToFmtString())
+		}
+		actual = append(actual, expected{
+			name:    p.Name,
+			version: p.Version,
+			path:    p.Locations[0].RealPath,
+			cpes:    cpes,
+		})
+	}
+
+	// note: busybox has no version evidence and is not cataloged
+	assert.Equal(t, []expected{
+		{
+			name:    "node",
+			version: "16.13.0",
+			path:    "usr/local/bin/node",
+			cpes:    []string{"cpe:2.3:a:nodejs:node.js:16.13.0:*:*:*:*:*:*:*"},
+		},
+		{
+			name:    "openssl",
+			version: "1.1.1k",
+			path:    "usr/local/bin/openssl",
+			cpes:    []string{"cpe:2.3:a:openssl:openssl:1.1.1k:*:*:*:*:*:*:*"},
+		},
+		{
+			name:    "nginx",
+			version: "1.21.4",
+			path:    "usr/sbin/nginx",
+			cpes: []string{
+				"cpe:2.3:a:f5:nginx:1.21.4:*:*:*:*:*:*:*",
+				"cpe:2.3:a:nginx:nginx:1.21.4:*:*:*:*:*:*:*",
+			},
+		},
+	}, actual)
+}
+
+func TestAllowedBinaryCPE(t *testing.T) {
+	tests := []struct {
+		cpe      string
+		expected bool
+	}{
+		{cpe: "cpe:2.3:a:openssl:openssl:1.1.1k:*:*:*:*:*:*:*", expected: true},
+		{cpe: "cpe:2.3:a:f5:nginx:1.21.4:*:*:*:*:*:*:*", expected: true},
+		// the product alone is not enough
+		{cpe: "cpe:2.3:a:openssl_project:openssl:1.1.1k:*:*:*:*:*:*:*", expected: false},
+		{cpe: "cpe:2.3:a:node:node:16.13.0:*:*:*:*:*:*:*", expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.cpe, func(t *testing.T) {
+			assert.Equal(t, test.expected, AllowedBinaryCPE(pkg.MustCPE(test.cpe)))
+		})
+	}
+}
+
+func TestOwnedByPackage(t *testing.T) {
+	owned := map[string]struct{}{
+		"/usr/bin/openssl": {},
+	}
+
+	tests := []struct {
+		name     string
+		paths    []string
+		expected bool
+	}{
+		{name: "owned", paths: []string{"/usr/bin/openssl"}, expected: true},
+		{name: "not owned", paths: []string{"/usr/local/bin/openssl"}, expected: false},
+		{name: "partially owned", paths: []string{"/usr/bin/openssl", "/usr/local/bin/openssl"}, expected: false},
+		{name: "owned (relative to a scanned directory)", paths: []string{"usr/bin/openssl"}, expected: true},
+		{name: "no locations", expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var locations []source.Location
+			for _, p := range test.paths {
+				locations = append(locations, source.NewLocation(p))
+			}
+			assert.Equal(t, test.expected, ownedByPackage(owned, pkg.Package{Locations: locations}))
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package pkg
+
+type BinaryMetadata struct {
+	Classifier string // the name of the classifier that identified the component within the binary (e.g. "openssl")
+}
@@ -0,0 +1,113 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/pkg/cataloger/common"
+)
+
+const bitnamiCatalogerName = "bitnami-cataloger"
+
+// bitnamiSBOMGlob matches the SPDX documents that bitnami ships alongside each component it packages
+// (e.g. "/opt/bitnami/redis/.spdx-redis.spdx")
+const bitnamiSBOMGlob = "**/opt/bitnami/**/.spdx-*.spdx"
+
+type bitnamiSPDXDocument struct {
+	Packages []struct {
+		Name             string `json:"name"`
+		VersionInfo      string `json:"versionInfo"`
+		LicenseConcluded string `json:"licenseConcluded"`
+		ExternalRefs     []struct {
+			ReferenceType    string `json:"referenceType"`
+			ReferenceLocator string `json:"referenceLocator"`
+		} `json:"externalRefs"`
+	} `json:"packages"`
+}
+
+// newBitnamiCataloger returns a cataloger for components packaged by bitnami (which syft does not catalog).
+func newBitnamiCataloger() *common.GenericCataloger {
+	globParsers := map[string]common.ParserFn{
+		bitnamiSBOMGlob: parseBitnamiSBOM,
+	}
+
+	return common.NewGenericCataloger(nil, globParsers, bitnamiCatalogerName)
+}
+
+// parseBitnamiSBOM returns the bitnami packages described within a bitnami SPDX (JSON) document. Packages without a
+// bitnami package URL are ignored, since these describe content that is otherwise cataloged.
+func parseBitnamiSBOM(_ string, reader io.Reader) ([]*pkg.Package, []artifact.Relationship, error) {
+	var doc bitnamiSPDXDocument
+	if err := json.NewDecoder(reader).Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse bitnami SPDX document: %w", err)
+	}
+
+	var packages []*pkg.Package
+	for _, p := range doc.Packages {
+		var purl string
+		var cpes []pkg.CPE
+		for _, ref := range p.ExternalRefs {
+			switch ref.ReferenceType {
+			case "purl":
+				purl = ref.ReferenceLocator
+			case "cpe23Type":
+				c, err := pkg.NewCPE(ref.ReferenceLocator)
+				if err != nil {
+					log.Debugf("unable to parse bitnami CPE %q: %+v", ref.ReferenceLocator, err)
+					continue
+				}
+				cpes = append(cpes, c)
+			}
+		}
+
+		if purlType, _ := typeFromPURL(purl); purlType != BitnamiPkg {
+			continue
+		}
+
+		name := p.Name
+		if _, purlName := purlNamespaceAndName(purl); purlName != "" {
+			name = purlName
+		}
+
+		version := p.VersionInfo
+		if purlVersion := bitnamiVersionFromPURL(purl); purlVersion != "" {
+			// the package URL version includes the bitnami revision (which the SPDX version info may not)
+			version = purlVersion
+		}
+
+		if name == "" || version == "" {
+			continue
+		}
+
+		var licenses []string
+		if license := p.LicenseConcluded; license != "" && license != "NOASSERTION" && license != "NONE" {
+			licenses = append(licenses, license)
+		}
+
+		packages = append(packages, &pkg.Package{
+			Name:     name,
+			Version:  version,
+			Licenses: licenses,
+			Type:     BitnamiPkg,
+			PURL:     purl,
+			CPEs:     cpes,
+		})
+	}
+	return packages, nil, nil
+}
+
+// bitnamiVersionFromPURL returns the version from the given package URL, ignoring the qualifiers and subpath.
+func bitnamiVersionFromPURL(purl string) string {
+	if i := strings.IndexAny(purl, "?#"); i >= 0 {
+		purl = purl[:i]
+	}
+	if i := strings.LastIndex(purl, "@"); i >= 0 {
+		return purl[i+1:]
+	}
+	return ""
+}
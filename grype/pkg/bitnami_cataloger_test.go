@@ -0,0 +1,33 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestBitnamiCataloger(t *testing.T) {
+	src, err := source.NewFromDirectory("test-fixtures/bitnami")
+	require.NoError(t, err)
+
+	resolver, err := src.FileResolver(source.SquashedScope)
+	require.NoError(t, err)
+
+	packages, _, err := newBitnamiCataloger().Catalog(resolver)
+	require.NoError(t, err)
+
+	// note: only packages with a bitnami package URL are expected (jemalloc is ignored)
+	require.Len(t, packages, 1)
+	p := packages[0]
+
+	assert.Equal(t, "redis", p.Name)
+	assert.Equal(t, "7.0.11-1", p.Version)
+	assert.Equal(t, []string{"BSD-3-Clause"}, p.Licenses)
+	assert.Equal(t, "pkg:bitnami/redis@7.0.11-1?arch=amd64&distro=debian-11", p.PURL)
+	assert.Equal(t, BitnamiPkg, p.Type)
+	require.Len(t, p.CPEs, 1)
+	assert.Equal(t, "cpe:2.3:*:redis:redis:7.0.11:*:*:*:*:*:*:*", p.CPEs[0].BindToFmtString())
+}
@@ -0,0 +1,83 @@
+package pkg
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/pkg/cataloger/common"
+	"github.com/anchore/syft/syft/pkg/cataloger/common/cpe"
+)
+
+const chocolateyCatalogerName = "chocolatey-cataloger"
+
+// chocolateyNuspecGlob matches the package specification of each package installed with chocolatey
+// (e.g. "/ProgramData/chocolatey/lib/git.install/git.install.nuspec")
+const chocolateyNuspecGlob = "**/ProgramData/chocolatey/lib/*/*.nuspec"
+
+// chocolateyNuspec represents the fields of interest within a chocolatey package specification
+type chocolateyNuspec struct {
+	Metadata struct {
+		ID         string `xml:"id"`
+		Version    string `xml:"version"`
+		LicenseURL string `xml:"licenseUrl"`
+	} `xml:"metadata"`
+}
+
+// chocolateyVariantSuffixes are the suffixes used by chocolatey to distinguish the variants of the same upstream
+// software (e.g. "git.install" and "git.portable").
+var chocolateyVariantSuffixes = []string{".install", ".portable", ".commandline"}
+
+// newChocolateyCataloger returns a cataloger for packages installed with chocolatey (which syft does not catalog).
+func newChocolateyCataloger() *common.GenericCataloger {
+	globParsers := map[string]common.ParserFn{
+		chocolateyNuspecGlob: parseChocolateyNuspec,
+	}
+
+	return common.NewGenericCataloger(nil, globParsers, chocolateyCatalogerName)
+}
+
+func parseChocolateyNuspec(_ string, reader io.Reader) ([]*pkg.Package, []artifact.Relationship, error) {
+	var spec chocolateyNuspec
+	if err := xml.NewDecoder(reader).Decode(&spec); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse chocolatey nuspec: %w", err)
+	}
+
+	name, version := strings.TrimSpace(spec.Metadata.ID), strings.TrimSpace(spec.Metadata.Version)
+	if name == "" || version == "" {
+		return nil, nil, nil
+	}
+
+	p := &pkg.Package{
+		Name:    name,
+		Version: version,
+		Type:    ChocolateyPkg,
+		PURL:    fmt.Sprintf("pkg:chocolatey/%s@%s", url.PathEscape(name), url.PathEscape(version)),
+	}
+
+	// chocolatey packages are named after the upstream software they install, however, variants of the same
+	// software are distinguished by a suffix, which is not part of the upstream name
+	p.CPEs = cpe.Generate(pkg.Package{
+		Name:    chocolateyUpstreamName(name),
+		Version: version,
+		Type:    ChocolateyPkg,
+	})
+
+	return []*pkg.Package{p}, nil, nil
+}
+
+// chocolateyUpstreamName returns the name of the upstream software installed by the chocolatey package
+// (e.g. "git.install" becomes "git").
+func chocolateyUpstreamName(name string) string {
+	lower := strings.ToLower(name)
+	for _, suffix := range chocolateyVariantSuffixes {
+		if strings.HasSuffix(lower, suffix) {
+			return name[:len(name)-len(suffix)]
+		}
+	}
+	return name
+}
@@ -0,0 +1,54 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestChocolateyCataloger(t *testing.T) {
+	src, err := source.NewFromDirectory("test-fixtures/windows")
+	require.NoError(t, err)
+
+	resolver, err := src.FileResolver(source.SquashedScope)
+	require.NoError(t, err)
+
+	packages, _, err := newChocolateyCataloger().Catalog(resolver)
+	require.NoError(t, err)
+
+	type expectation struct {
+		version string
+		purl    string
+		product string
+	}
+
+	expected := map[string]expectation{
+		"git.install": {
+			version: "2.34.1",
+			purl:    "pkg:chocolatey/git.install@2.34.1",
+			product: "git",
+		},
+		"7zip": {
+			version: "19.0",
+			purl:    "pkg:chocolatey/7zip@19.0",
+			product: "7zip",
+		},
+	}
+
+	require.Len(t, packages, len(expected))
+	for _, p := range packages {
+		e, ok := expected[p.Name]
+		require.True(t, ok, "unexpected package: %s", p.Name)
+
+		assert.Equal(t, e.version, p.Version)
+		assert.Equal(t, e.purl, p.PURL)
+		assert.Equal(t, ChocolateyPkg, p.Type)
+		require.NotEmpty(t, p.CPEs)
+		for _, c := range p.CPEs {
+			assert.Equal(t, e.product, c.Product)
+		}
+	}
+}
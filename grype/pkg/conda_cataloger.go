@@ -0,0 +1,137 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/pkg/cataloger/common"
+)
+
+const condaCatalogerName = "conda-meta-cataloger"
+
+// condaMetaGlob matches the package records within a conda environment
+// (e.g. "/opt/conda/conda-meta/numpy-1.21.2-py39h20f2e39_0.json")
+const condaMetaGlob = "**/conda-meta/*.json"
+
+// condaMeta represents the fields of interest within a conda-meta package record
+type condaMeta struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Build   string      `json:"build"`
+	Channel string      `json:"channel"`
+	Subdir  string      `json:"subdir"`
+	Noarch  interface{} `json:"noarch"`
+	Depends []string    `json:"depends"`
+	License string      `json:"license"`
+}
+
+// newCondaCataloger returns a cataloger for packages installed into conda environments (which syft does not catalog).
+func newCondaCataloger() *common.GenericCataloger {
+	globParsers := map[string]common.ParserFn{
+		condaMetaGlob: parseCondaMeta,
+	}
+
+	return common.NewGenericCataloger(nil, globParsers, condaCatalogerName)
+}
+
+func parseCondaMeta(_ string, reader io.Reader) ([]*pkg.Package, []artifact.Relationship, error) {
+	var entry condaMeta
+	if err := json.NewDecoder(reader).Decode(&entry); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse conda-meta record: %w", err)
+	}
+
+	if entry.Name == "" || entry.Version == "" {
+		return nil, nil, nil
+	}
+
+	metadata := CondaMetadata{
+		Channel: condaChannelName(entry.Channel, entry.Subdir),
+		Subdir:  entry.Subdir,
+		Build:   entry.Build,
+	}
+
+	var licenses []string
+	if entry.License != "" {
+		licenses = append(licenses, entry.License)
+	}
+
+	return []*pkg.Package{
+		{
+			Name:         entry.Name,
+			Version:      entry.Version,
+			Licenses:     licenses,
+			Language:     condaLanguage(entry),
+			Type:         CondaPkg,
+			PURL:         condaPackageURL(entry.Name, entry.Version, metadata),
+			MetadataType: CondaMetadataType,
+			Metadata:     metadata,
+		},
+	}, nil, nil
+}
+
+// condaChannelName returns the name of the channel from the channel URL recorded by conda
+// (e.g. "https://conda.anaconda.org/conda-forge/linux-64" becomes "conda-forge", and
+// "https://repo.anaconda.com/pkgs/main/linux-64" becomes "main").
+func condaChannelName(channel, subdir string) string {
+	channel = strings.TrimSuffix(strings.TrimSpace(channel), "/")
+	if subdir != "" {
+		channel = strings.TrimSuffix(strings.TrimSuffix(channel, subdir), "/")
+	}
+	if i := strings.LastIndex(channel, "/"); i >= 0 {
+		channel = channel[i+1:]
+	}
+	return channel
+}
+
+// condaLanguage returns the language ecosystem of the given conda package, which is only known for python packages.
+// Note: the python interpreter itself is not considered to be a python package.
+func condaLanguage(entry condaMeta) pkg.Language {
+	if entry.Name == "python" {
+		return pkg.UnknownLanguage
+	}
+	if noarch, ok := entry.Noarch.(string); ok && noarch == "python" {
+		return pkg.Python
+	}
+	for _, dependency := range entry.Depends {
+		// dependencies are match specs (e.g. "python >=3.9,<3.10.0a0")
+		if fields := strings.Fields(dependency); len(fields) > 0 && fields[0] == "python" {
+			return pkg.Python
+		}
+	}
+	return pkg.UnknownLanguage
+}
+
+// condaPackageURL returns the package URL for the given conda package, including the channel (as the same package
+// name may refer to different packages across channels), see
+// https://github.com/package-url/purl-spec/blob/master/PURL-TYPES.rst#conda
+func condaPackageURL(name, version string, metadata CondaMetadata) string {
+	qualifiers := map[string]string{
+		"build":   metadata.Build,
+		"channel": metadata.Channel,
+		"subdir":  metadata.Subdir,
+	}
+
+	var keys []string
+	for key, value := range qualifiers {
+		if value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	purl := fmt.Sprintf("pkg:conda/%s@%s", url.PathEscape(name), url.PathEscape(version))
+	for i, key := range keys {
+		separator := "&"
+		if i == 0 {
+			separator = "?"
+		}
+		purl += fmt.Sprintf("%s%s=%s", separator, key, url.QueryEscape(qualifiers[key]))
+	}
+	return purl
+}
@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestCondaCataloger(t *testing.T) {
+	src, err := source.NewFromDirectory("test-fixtures/conda")
+	require.NoError(t, err)
+
+	resolver, err := src.FileResolver(source.SquashedScope)
+	require.NoError(t, err)
+
+	packages, _, err := newCondaCataloger().Catalog(resolver)
+	require.NoError(t, err)
+
+	type expectation struct {
+		version  string
+		language syftPkg.Language
+		purl     string
+		metadata CondaMetadata
+	}
+
+	expected := map[string]expectation{
+		"numpy": {
+			version:  "1.21.2",
+			language: syftPkg.Python,
+			purl:     "pkg:conda/numpy@1.21.2?build=py39h20f2e39_0&channel=main&subdir=linux-64",
+			metadata: CondaMetadata{Channel: "main", Subdir: "linux-64", Build: "py39h20f2e39_0"},
+		},
+		"openssl": {
+			version:  "1.1.1l",
+			language: syftPkg.UnknownLanguage,
+			purl:     "pkg:conda/openssl@1.1.1l?build=h7f8727e_0&channel=conda-forge&subdir=linux-64",
+			metadata: CondaMetadata{Channel: "conda-forge", Subdir: "linux-64", Build: "h7f8727e_0"},
+		},
+		"requests": {
+			version:  "2.26.0",
+			language: syftPkg.Python,
+			purl:     "pkg:conda/requests@2.26.0?build=pyhd3eb1b0_0&channel=main&subdir=noarch",
+			metadata: CondaMetadata{Channel: "main", Subdir: "noarch", Build: "pyhd3eb1b0_0"},
+		},
+	}
+
+	require.Len(t, packages, len(expected))
+	for _, p := range packages {
+		t.Run(p.Name, func(t *testing.T) {
+			e, ok := expected[p.Name]
+			require.True(t, ok, "unexpected package: %s", p.Name)
+			assert.Equal(t, e.version, p.Version)
+			assert.Equal(t, CondaPkg, p.Type)
+			assert.Equal(t, e.language, p.Language)
+			assert.Equal(t, e.purl, p.PURL)
+			assert.Equal(t, CondaMetadataType, p.MetadataType)
+			assert.Equal(t, e.metadata, p.Metadata)
+			assert.Equal(t, e.metadata, New(p).Metadata)
+		})
+	}
+}
+
+func TestNew_CondaMetadataFromPURL(t *testing.T) {
+	actual := New(syftPkg.Package{
+		Name:    "numpy",
+		Version: "1.21.2",
+		PURL:    "pkg:conda/numpy@1.21.2?build=py39h20f2e39_0&channel=main&subdir=linux-64",
+	})
+
+	assert.Equal(t, CondaPkg, actual.Type)
+	assert.Equal(t, CondaMetadata{Channel: "main", Subdir: "linux-64", Build: "py39h20f2e39_0"}, actual.Metadata)
+}
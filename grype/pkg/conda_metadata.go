@@ -0,0 +1,7 @@
+package pkg
+
+type CondaMetadata struct {
+	Channel string // the channel the package was installed from (e.g. "conda-forge" or "main")
+	Subdir  string // the platform specific subdirectory of the channel (e.g. "linux-64" or "noarch")
+	Build   string // the build string (e.g. "py39h20f2e39_0")
+}
@@ -0,0 +1,151 @@
+package pkg
+
+import (
+	"archive/tar"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// containerFS writes the filesystem of a container into a directory, where symlinks are rewritten to be relative
+// within the directory (an absolute symlink would otherwise resolve to the filesystem of the host when scanned).
+type containerFS struct {
+	root string
+}
+
+// path returns the path within the directory of the given path within the container, which cannot escape the
+// directory.
+func (fs containerFS) path(name string) string {
+	return filepath.Join(fs.root, filepath.Clean(string(filepath.Separator)+name))
+}
+
+func (fs containerFS) mkdir(name string) error {
+	return os.MkdirAll(fs.path(name), 0755)
+}
+
+func (fs containerFS) file(name string, mode os.FileMode, contents io.Reader) error {
+	p := fs.path(name)
+	if err := fs.prepare(p); err != nil {
+		return err
+	}
+	// the scan only needs to read the file
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, contents); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (fs containerFS) symlink(name, target string) error {
+	name = filepath.Clean(string(filepath.Separator) + name)
+	dir := filepath.Dir(name)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(dir, target)
+	}
+	// the target is resolved within the container (where ".." cannot climb above the root) and made relative
+	relative, err := filepath.Rel(dir, filepath.Clean(target))
+	if err != nil {
+		return err
+	}
+
+	p := fs.path(name)
+	if err := fs.prepare(p); err != nil {
+		return err
+	}
+	return os.Symlink(relative, p)
+}
+
+func (fs containerFS) link(name, target string) error {
+	p := fs.path(name)
+	if err := fs.prepare(p); err != nil {
+		return err
+	}
+	return os.Link(fs.path(target), p)
+}
+
+// prepare creates the parent directory of the given path and removes an existing file at the path (e.g. an earlier
+// entry of an archive for the same file).
+func (fs containerFS) prepare(p string) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// extractTar writes the files, directories, and links of the given archive (e.g. an exported container), where other
+// files (e.g. devices) are skipped.
+func (fs containerFS) extractTar(reader io.Reader) error {
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read archive: %w", err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = fs.mkdir(header.Name)
+		case tar.TypeReg, tar.TypeRegA:
+			err = fs.file(header.Name, header.FileInfo().Mode(), archive)
+		case tar.TypeSymlink:
+			err = fs.symlink(header.Name, header.Linkname)
+		case tar.TypeLink:
+			err = fs.link(header.Name, header.Linkname)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to extract %q: %w", header.Name, err)
+		}
+	}
+}
+
+// copyDir copies the files, directories, and links of the given directory (e.g. the root filesystem of a container),
+// where other files (e.g. devices) are skipped.
+func (fs containerFS) copyDir(dir string) error {
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if errors.Is(err, os.ErrNotExist) {
+			// the file was removed by the running container after it was listed
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			return fs.mkdir(name)
+		case mode.IsRegular():
+			f, err := os.Open(p)
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return fs.file(name, mode, f)
+		case mode&os.ModeSymlink != 0:
+			target, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			return fs.symlink(name, target)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,136 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/source"
+	"github.com/docker/docker/client"
+)
+
+const (
+	dockerContainerScheme     = "docker-container:"
+	podmanContainerScheme     = "podman:"
+	containerdContainerScheme = "containerd:"
+)
+
+// containerdTaskDir is where containerd mounts the root filesystem of running containers (by namespace and ID).
+var containerdTaskDir = "/run/containerd/io.containerd.runtime.v2.task"
+
+// containerProvider catalogs the filesystem of a running container (including packages installed after the container
+// started), given as "docker-container:<name or ID>", "podman:<name or ID>", or "containerd:[<namespace>/]<ID>".
+func containerProvider(spanCtx context.Context, userInput string, config ProviderConfig) ([]Package, Context, error) {
+	var export func(containerFS) error
+	switch {
+	case strings.HasPrefix(userInput, dockerContainerScheme):
+		container := strings.TrimPrefix(userInput, dockerContainerScheme)
+		export = func(fs containerFS) error {
+			return exportContainer(fs, container, client.FromEnv)
+		}
+	case strings.HasPrefix(userInput, podmanContainerScheme):
+		container := strings.TrimPrefix(userInput, podmanContainerScheme)
+		export = func(fs containerFS) error {
+			return exportContainer(fs, container, client.WithHost(podmanHost()))
+		}
+	case strings.HasPrefix(userInput, containerdContainerScheme):
+		container := strings.TrimPrefix(userInput, containerdContainerScheme)
+		export = func(fs containerFS) error {
+			return copyContainerdRootfs(fs, container)
+		}
+	default:
+		return nil, Context{}, errDoesNotProvide
+	}
+
+	return filesystemCopyProvider(spanCtx, userInput, config, "container", export)
+}
+
+// filesystemCopyProvider catalogs a copy of the filesystem of the given input (e.g. a running container), which is
+// written into a temporary directory by the given function.
+func filesystemCopyProvider(spanCtx context.Context, userInput string, config ProviderConfig, kind string, copyFS func(containerFS) error) ([]Package, Context, error) {
+	root, err := ioutil.TempDir("", "grype-"+kind+"-")
+	if err != nil {
+		return nil, Context{}, fmt.Errorf("unable to create directory for the %s filesystem: %w", kind, err)
+	}
+	defer os.RemoveAll(root)
+
+	log.Debugf("copying the filesystem of %s=%q", kind, userInput)
+	if err := copyFS(containerFS{root: root}); err != nil {
+		return nil, Context{}, fmt.Errorf("unable to copy the filesystem of %s=%q: %w", kind, userInput, err)
+	}
+
+	packages, ctx, err := syftProvider(spanCtx, "dir:"+root, config)
+	if err != nil {
+		return nil, Context{}, err
+	}
+	// the temporary directory is meaningless within a report
+	ctx.Source = &source.Metadata{Scheme: source.DirectoryScheme, Path: userInput}
+	return packages, ctx, nil
+}
+
+// exportContainer copies the filesystem of a container from a Docker (compatible) API.
+func exportContainer(fs containerFS, container string, host client.Opt) error {
+	cli, err := client.NewClientWithOpts(host, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return fmt.Errorf("unable to create container runtime client: %w", err)
+	}
+	defer cli.Close()
+
+	export, err := cli.ContainerExport(context.Background(), container)
+	if err != nil {
+		return err
+	}
+	defer export.Close()
+	return fs.extractTar(export)
+}
+
+// podmanHost returns the address of the Docker compatible API of podman, which is $CONTAINER_HOST when given, or
+// else the socket of the user (for rootless podman) or of the system.
+func podmanHost() string {
+	if host := os.Getenv("CONTAINER_HOST"); host != "" {
+		return host
+	}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		socket := filepath.Join(runtimeDir, "podman", "podman.sock")
+		if _, err := os.Stat(socket); err == nil {
+			return "unix://" + socket
+		}
+	}
+	return "unix:///run/podman/podman.sock"
+}
+
+// copyContainerdRootfs copies the root filesystem of a running containerd container, which is given by ID within a
+// namespace (e.g. "k8s.io/<ID>"), or else by ID within any namespace.
+func copyContainerdRootfs(fs containerFS, container string) error {
+	var candidates []string
+	if strings.Contains(container, "/") {
+		candidates = []string{filepath.Join(containerdTaskDir, container, "rootfs")}
+	} else {
+		namespaces, err := ioutil.ReadDir(containerdTaskDir)
+		if err != nil {
+			return fmt.Errorf("unable to list containerd namespaces: %w", err)
+		}
+		for _, namespace := range namespaces {
+			candidates = append(candidates, filepath.Join(containerdTaskDir, namespace.Name(), container, "rootfs"))
+		}
+	}
+
+	var found []string
+	for _, rootfs := range candidates {
+		if info, err := os.Stat(rootfs); err == nil && info.IsDir() {
+			found = append(found, rootfs)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return fmt.Errorf("no running containerd container found (within %s)", containerdTaskDir)
+	case 1:
+		return fs.copyDir(found[0])
+	default:
+		return fmt.Errorf("the container is running within multiple namespaces, give the namespace as <namespace>/<ID>")
+	}
+}
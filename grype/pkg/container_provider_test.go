@@ -0,0 +1,93 @@
+package pkg
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anchore/syft/syft/pkg/cataloger"
+	"github.com/anchore/syft/syft/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerFS_ExtractTar(t *testing.T) {
+	var archive bytes.Buffer
+	w := tar.NewWriter(&archive)
+	for _, header := range []*tar.Header{
+		{Name: "etc/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "etc/os-release", Typeflag: tar.TypeReg, Mode: 0400, Size: 3},
+		{Name: "etc/hosts", Typeflag: tar.TypeLink, Linkname: "etc/os-release"},
+		{Name: "lib", Typeflag: tar.TypeSymlink, Linkname: "/usr/lib"},
+		{Name: "usr/lib/passwd", Typeflag: tar.TypeSymlink, Linkname: "../../../../../etc/passwd"},
+		{Name: "dev/null", Typeflag: tar.TypeChar, Devmajor: 1, Devminor: 3},
+		{Name: "../../escape", Typeflag: tar.TypeReg, Mode: 0644, Size: 3},
+	} {
+		require.NoError(t, w.WriteHeader(header))
+		if header.Size > 0 {
+			_, err := w.Write([]byte("abc"))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, w.Close())
+
+	root := t.TempDir()
+	require.NoError(t, containerFS{root: root}.extractTar(&archive))
+
+	contents, err := ioutil.ReadFile(filepath.Join(root, "etc", "hosts"))
+	require.NoError(t, err)
+	assert.Equal(t, "abc", string(contents))
+
+	// absolute and escaping symlinks are made relative within the root
+	target, err := os.Readlink(filepath.Join(root, "lib"))
+	require.NoError(t, err)
+	assert.Equal(t, "usr/lib", target)
+	target, err = os.Readlink(filepath.Join(root, "usr", "lib", "passwd"))
+	require.NoError(t, err)
+	assert.Equal(t, "../../etc/passwd", target)
+
+	// entries cannot escape the root
+	assert.FileExists(t, filepath.Join(root, "escape"))
+	assert.NoFileExists(t, filepath.Join(root, "dev", "null"))
+}
+
+func TestContainerProvider_Containerd(t *testing.T) {
+	taskDir := t.TempDir()
+	original := containerdTaskDir
+	containerdTaskDir = taskDir
+	t.Cleanup(func() {
+		containerdTaskDir = original
+	})
+
+	rootfs := filepath.Join(taskDir, "k8s.io", "abc123", "rootfs")
+	for name, contents := range map[string]string{
+		"etc/os-release":       "ID=alpine\nVERSION_ID=3.14.2\n",
+		"lib/apk/db/installed": "P:musl\nV:1.2.2-r3\nA:x86_64\n\n",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(rootfs, name)), 0755))
+		require.NoError(t, ioutil.WriteFile(filepath.Join(rootfs, name), []byte(contents), 0644))
+	}
+	// an absolute symlink must not resolve to the host
+	require.NoError(t, os.Symlink("/etc", filepath.Join(rootfs, "host-etc")))
+
+	config := ProviderConfig{CatalogingOptions: cataloger.DefaultConfig()}
+	for _, input := range []string{"containerd:abc123", "containerd:k8s.io/abc123"} {
+		t.Run(input, func(t *testing.T) {
+			packages, ctx, err := Provide(input, config)
+			require.NoError(t, err)
+
+			require.Len(t, packages, 1)
+			assert.Equal(t, "musl", packages[0].Name)
+			assert.Equal(t, "1.2.2-r3", packages[0].Version)
+			require.NotNil(t, ctx.Distro)
+			assert.Equal(t, "alpine", ctx.Distro.ID)
+			assert.Equal(t, &source.Metadata{Scheme: source.DirectoryScheme, Path: input}, ctx.Source)
+		})
+	}
+
+	_, _, err := Provide("containerd:missing", config)
+	assert.Error(t, err)
+}
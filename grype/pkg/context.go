@@ -1,6 +1,9 @@
 package pkg
 
 import (
+	"time"
+
+	"github.com/anchore/grype/grype/policy"
 	"github.com/anchore/syft/syft/linux"
 	"github.com/anchore/syft/syft/source"
 )
@@ -8,4 +11,8 @@ import (
 type Context struct {
 	Source *source.Metadata
 	Distro *linux.Release
+	// DistroEOL is when the distro release reached (or will reach) the end of life, if known
+	DistroEOL *time.Time
+	// Policy is the decision of the configured policy over the scan result, if any
+	Policy *policy.Decision
 }
@@ -0,0 +1,108 @@
+package pkg
+
+import (
+	"fmt"
+
+	"github.com/bmatcuk/doublestar/v2"
+	"github.com/facebookincubator/nvdtools/wfn"
+
+	"github.com/anchore/grype/internal/log"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// CPEOverride changes the CPEs of the packages that match the criteria of the override (before the packages are
+// matched), e.g. to remove the generated CPEs of a package that collide with an unrelated product of the NVD.
+type CPEOverride struct {
+	// Name, Type and PURL are glob expressions of the packages that the override applies to (where an empty criterion
+	// matches any package)
+	Name string
+	Type string
+	PURL string
+	// Replace are the CPEs that replace every CPE of the package (when not empty)
+	Replace []syftPkg.CPE
+	// Remove are the patterns of the CPEs that are removed from the package, where a field of a pattern that is ANY
+	// ("*") matches any value (e.g. "cpe:2.3:a:*:jq:*:*:*:*:*:*:*:*" removes every CPE of the jq product)
+	Remove []syftPkg.CPE
+	// Add are the CPEs that are added to the package
+	Add []syftPkg.CPE
+}
+
+// applies indicates whether the override applies to the given package.
+func (o CPEOverride) applies(p Package) (bool, error) {
+	for _, criterion := range []struct {
+		pattern string
+		value   string
+	}{
+		{pattern: o.Name, value: p.Name},
+		{pattern: o.Type, value: string(p.Type)},
+		{pattern: o.PURL, value: p.PURL},
+	} {
+		if criterion.pattern == "" {
+			continue
+		}
+		matches, err := doublestar.Match(criterion.pattern, criterion.value)
+		if err != nil || !matches {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// apply returns the CPEs of the package after the override (where the CPEs are replaced, then removed, then added).
+func (o CPEOverride) apply(cpes []syftPkg.CPE) []syftPkg.CPE {
+	if len(o.Replace) > 0 {
+		cpes = o.Replace
+	}
+
+	var result []syftPkg.CPE
+	for _, c := range cpes {
+		if !o.removes(c) {
+			result = append(result, c)
+		}
+	}
+
+	for _, c := range o.Add {
+		if !containsCPE(result, c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// removes indicates whether the given CPE matches any of the patterns of the CPEs to remove.
+func (o CPEOverride) removes(cpe syftPkg.CPE) bool {
+	for i := range o.Remove {
+		if wfn.Match(&o.Remove[i], &cpe) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsCPE(cpes []syftPkg.CPE, cpe syftPkg.CPE) bool {
+	for _, c := range cpes {
+		if c.BindToFmtString() == cpe.BindToFmtString() {
+			return true
+		}
+	}
+	return false
+}
+
+// applyCPEOverrides changes the CPEs of the given packages by the overrides that apply to them (in the given order).
+func applyCPEOverrides(packages []Package, overrides []CPEOverride) error {
+	for i, p := range packages {
+		for _, o := range overrides {
+			applies, err := o.applies(p)
+			if err != nil {
+				return fmt.Errorf("bad CPE override: %w", err)
+			}
+			if !applies {
+				continue
+			}
+			// note: CPEs are excluded from the package ID, so are safe to mutate
+			packages[i].CPEs = o.apply(packages[i].CPEs)
+			log.Debugf("overrode CPEs of package=%q (type=%q): %d CPEs", p.Name, p.Type, len(packages[i].CPEs))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestApplyCPEOverrides(t *testing.T) {
+	jqCPEs := []syftPkg.CPE{
+		syftPkg.MustCPE("cpe:2.3:a:jq:jq:1.0.0:*:*:*:*:*:*:*"),
+		syftPkg.MustCPE("cpe:2.3:a:jq_project:jq:1.0.0:*:*:*:*:*:*:*"),
+		syftPkg.MustCPE("cpe:2.3:a:jq:node-jq:1.0.0:*:*:*:*:*:*:*"),
+	}
+
+	tests := []struct {
+		name      string
+		overrides []CPEOverride
+		expected  map[string][]string
+	}{
+		{
+			name: "no overrides",
+			expected: map[string][]string{
+				"npm:jq": {"cpe:2.3:a:jq:jq:1.0.0:*:*:*:*:*:*:*", "cpe:2.3:a:jq_project:jq:1.0.0:*:*:*:*:*:*:*", "cpe:2.3:a:jq:node-jq:1.0.0:*:*:*:*:*:*:*"},
+				"apk:jq": {"cpe:2.3:a:jq:jq:1.0.0:*:*:*:*:*:*:*", "cpe:2.3:a:jq_project:jq:1.0.0:*:*:*:*:*:*:*", "cpe:2.3:a:jq:node-jq:1.0.0:*:*:*:*:*:*:*"},
+			},
+		},
+		{
+			name: "remove by pattern",
+			overrides: []CPEOverride{
+				{Name: "jq", Type: "npm", Remove: []syftPkg.CPE{syftPkg.MustCPE("cpe:2.3:a:*:jq:*:*:*:*:*:*:*:*")}},
+			},
+			expected: map[string][]string{
+				"npm:jq": {"cpe:2.3:a:jq:node-jq:1.0.0:*:*:*:*:*:*:*"},
+				"apk:jq": {"cpe:2.3:a:jq:jq:1.0.0:*:*:*:*:*:*:*", "cpe:2.3:a:jq_project:jq:1.0.0:*:*:*:*:*:*:*", "cpe:2.3:a:jq:node-jq:1.0.0:*:*:*:*:*:*:*"},
+			},
+		},
+		{
+			name: "remove every CPE",
+			overrides: []CPEOverride{
+				{Type: "npm", Remove: []syftPkg.CPE{syftPkg.MustCPE("cpe:2.3:*:*:*:*:*:*:*:*:*:*:*")}},
+			},
+			expected: map[string][]string{
+				"npm:jq": nil,
+				"apk:jq": {"cpe:2.3:a:jq:jq:1.0.0:*:*:*:*:*:*:*", "cpe:2.3:a:jq_project:jq:1.0.0:*:*:*:*:*:*:*", "cpe:2.3:a:jq:node-jq:1.0.0:*:*:*:*:*:*:*"},
+			},
+		},
+		{
+			name: "replace and add by purl",
+			overrides: []CPEOverride{
+				{
+					PURL:    "pkg:alpine/jq@*",
+					Replace: []syftPkg.CPE{syftPkg.MustCPE("cpe:2.3:a:jq_project:jq:*:*:*:*:*:*:*:*")},
+					Add:     []syftPkg.CPE{syftPkg.MustCPE("cpe:2.3:a:stedolan:jq:*:*:*:*:*:*:*:*"), syftPkg.MustCPE("cpe:2.3:a:jq_project:jq:*:*:*:*:*:*:*:*")},
+				},
+			},
+			expected: map[string][]string{
+				"npm:jq": {"cpe:2.3:a:jq:jq:1.0.0:*:*:*:*:*:*:*", "cpe:2.3:a:jq_project:jq:1.0.0:*:*:*:*:*:*:*", "cpe:2.3:a:jq:node-jq:1.0.0:*:*:*:*:*:*:*"},
+				"apk:jq": {"cpe:2.3:a:jq_project:jq:*:*:*:*:*:*:*:*", "cpe:2.3:a:stedolan:jq:*:*:*:*:*:*:*:*"},
+			},
+		},
+		{
+			name: "overrides in order",
+			overrides: []CPEOverride{
+				{Name: "j*", Add: []syftPkg.CPE{syftPkg.MustCPE("cpe:2.3:a:acme:jq:*:*:*:*:*:*:*:*")}},
+				{Name: "jq", Remove: []syftPkg.CPE{syftPkg.MustCPE("cpe:2.3:a:jq*:*:*:*:*:*:*:*:*:*")}},
+			},
+			expected: map[string][]string{
+				"npm:jq": {"cpe:2.3:a:acme:jq:*:*:*:*:*:*:*:*"},
+				"apk:jq": {"cpe:2.3:a:acme:jq:*:*:*:*:*:*:*:*"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			packages := []Package{
+				{Name: "jq", Version: "1.0.0", Type: syftPkg.NpmPkg, PURL: "pkg:npm/jq@1.0.0", CPEs: append([]syftPkg.CPE(nil), jqCPEs...)},
+				{Name: "jq", Version: "1.0.0", Type: syftPkg.ApkPkg, PURL: "pkg:alpine/jq@1.0.0", CPEs: append([]syftPkg.CPE(nil), jqCPEs...)},
+			}
+			require.NoError(t, applyCPEOverrides(packages, test.overrides))
+
+			actual := make(map[string][]string)
+			for _, p := range packages {
+				key := string(p.Type) + ":" + p.Name
+				actual[key] = nil
+				for _, c := range p.CPEs {
+					actual[key] = append(actual[key], c.BindToFmtString())
+				}
+			}
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
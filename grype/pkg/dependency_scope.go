@@ -0,0 +1,299 @@
+package pkg
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/anchore/grype/internal/log"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+// the dependency scopes of packages that are only needed to develop or test a project, where the scope of every other
+// package is empty (the package is needed at runtime, or the scope is not known)
+const (
+	DevelopmentScope = "development"
+	TestScope        = "test"
+)
+
+var (
+	// gemfileGroupBlockPattern matches the start of a group block of a Gemfile (e.g. "group :development, :test do")
+	gemfileGroupBlockPattern = regexp.MustCompile(`^group\s*\(?\s*(.+?)\s*\)?\s+do\b`)
+	// gemfileBlockPattern matches the start of any other block of a Gemfile (e.g. "platforms :jruby do")
+	gemfileBlockPattern = regexp.MustCompile(`\bdo\s*(\|[^|]*\|)?\s*$`)
+	// gemfileGemPattern matches a gem of a Gemfile (e.g. "gem 'rspec-rails', '~> 5.0', group: :test")
+	gemfileGemPattern = regexp.MustCompile(`^gem\s*\(?\s*["']([^"']+)["'](.*)$`)
+	// gemfileGroupOptionPattern matches the group option of a gem (e.g. "group: :test" or ":groups => [:test]")
+	gemfileGroupOptionPattern = regexp.MustCompile(`:?groups?:?\s*(=>)?\s*(\[[^\]]*\]|:\w+|["'][^"']*["'])`)
+	// gemfileGroupNamePattern matches the name of a group within a list of groups (e.g. ":test" or "'test'")
+	gemfileGroupNamePattern = regexp.MustCompile(`:(\w+)|["'](\w+)["']`)
+)
+
+// addDependencyScopes sets the scope of each npm and bundler package from the lock file (and the Gemfile) the package
+// was found in, which the syft catalogers do not capture. A package has a scope only when every location of the package
+// has the same scope.
+func addDependencyScopes(resolver source.FileResolver, packages []Package) {
+	scopesByFile := make(map[string]map[string]string)
+	for i, p := range packages {
+		var read func(source.FileResolver, source.Location) (map[string]string, error)
+		var key string
+		switch p.Type {
+		case syftPkg.NpmPkg:
+			read, key = npmLockScopes, p.Name+"@"+p.Version
+		case syftPkg.GemPkg:
+			read, key = bundlerScopes, p.Name
+		default:
+			continue
+		}
+
+		scope := ""
+		for j, location := range p.Locations {
+			scopes, ok := scopesByFile[location.RealPath]
+			if !ok {
+				var err error
+				scopes, err = read(resolver, location)
+				if err != nil {
+					log.Debugf("unable to read dependency scopes from %q: %+v", location.RealPath, err)
+				}
+				scopesByFile[location.RealPath] = scopes
+			}
+
+			s := scopes[key]
+			if s == "" || (j > 0 && s != scope) {
+				scope = ""
+				break
+			}
+			scope = s
+		}
+		packages[i].Scope = scope
+	}
+}
+
+// IsDevelopmentDependency indicates whether the package is only needed to develop or test a project.
+func (p Package) IsDevelopmentDependency() bool {
+	return p.Scope == DevelopmentScope || p.Scope == TestScope
+}
+
+// filterDevelopmentDependencies returns the packages that are needed at runtime (or of which the scope is not known).
+func filterDevelopmentDependencies(packages []Package) []Package {
+	var out []Package
+	for _, p := range packages {
+		if !p.IsDevelopmentDependency() {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+type npmLockDependency struct {
+	Version      string                       `json:"version"`
+	Dev          bool                         `json:"dev"`
+	Dependencies map[string]npmLockDependency `json:"dependencies"`
+}
+
+// npmLockScopes returns the scope of every package within the given package-lock.json, keyed by the name and version of
+// the package (where the dependencies that are only needed for development are flagged by npm).
+func npmLockScopes(resolver source.FileResolver, location source.Location) (map[string]string, error) {
+	if path.Base(location.RealPath) != "package-lock.json" {
+		return nil, nil
+	}
+	reader, err := resolver.FileContentsByLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var lock struct {
+		Dependencies map[string]npmLockDependency `json:"dependencies"`
+	}
+	if err := json.NewDecoder(reader).Decode(&lock); err != nil {
+		return nil, fmt.Errorf("unable to parse package-lock.json: %w", err)
+	}
+
+	scopes := make(map[string]string)
+	var add func(map[string]npmLockDependency)
+	add = func(dependencies map[string]npmLockDependency) {
+		for name, d := range dependencies {
+			key := name + "@" + d.Version
+			switch {
+			case !d.Dev:
+				// note: a package that is needed at runtime anywhere in the tree is needed at runtime
+				scopes[key] = ""
+			case !hasKey(scopes, key):
+				scopes[key] = DevelopmentScope
+			}
+			add(d.Dependencies)
+		}
+	}
+	add(lock.Dependencies)
+	return scopes, nil
+}
+
+func hasKey(m map[string]string, key string) bool {
+	_, ok := m[key]
+	return ok
+}
+
+// bundlerScopes returns the scope of every gem within the given Gemfile.lock, keyed by the name of the gem, where the
+// groups of the gems are given by the Gemfile next to the lock file. A gem is scoped to development (or test) when it is
+// only required by gems of the development (or test) group.
+func bundlerScopes(resolver source.FileResolver, location source.Location) (map[string]string, error) {
+	if path.Base(location.RealPath) != "Gemfile.lock" {
+		return nil, nil
+	}
+	gemfile := resolver.RelativeFileByPath(location, path.Join(path.Dir(location.RealPath), "Gemfile"))
+	if gemfile == nil {
+		return nil, nil
+	}
+
+	groups, err := readGemfileGroups(resolver, *gemfile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse Gemfile: %w", err)
+	}
+	requires, err := readGemfileLockRequirements(resolver, location)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse Gemfile.lock: %w", err)
+	}
+
+	// note: the scope of a gem is the first of the scopes that requires it (where runtime comes first)
+	scopes := make(map[string]string)
+	for _, scope := range []string{"", DevelopmentScope, TestScope} {
+		var visit func(string)
+		visit = func(name string) {
+			if hasKey(scopes, name) {
+				return
+			}
+			scopes[name] = scope
+			for _, r := range requires[name] {
+				visit(r)
+			}
+		}
+		for name, gemGroups := range groups {
+			if gemfileScope(gemGroups) == scope {
+				visit(name)
+			}
+		}
+	}
+	return scopes, nil
+}
+
+// gemfileScope returns the scope of a gem of the given groups, where a gem of no group (or any group other than the
+// development and test groups) is needed at runtime.
+func gemfileScope(groups []string) string {
+	scope := ""
+	for _, g := range groups {
+		switch {
+		case g == DevelopmentScope:
+			scope = DevelopmentScope
+		case g == TestScope:
+			if scope == "" {
+				scope = TestScope
+			}
+		default:
+			return ""
+		}
+	}
+	return scope
+}
+
+// readGemfileGroups returns the groups of every gem of the Gemfile at the given location.
+func readGemfileGroups(resolver source.FileResolver, location source.Location) (map[string][]string, error) {
+	reader, err := resolver.FileContentsByLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return parseGemfileGroups(reader)
+}
+
+// readGemfileLockRequirements returns the requirements of every gem of the Gemfile.lock at the given location.
+func readGemfileLockRequirements(resolver source.FileResolver, location source.Location) (map[string][]string, error) {
+	reader, err := resolver.FileContentsByLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return parseGemfileLockRequirements(reader)
+}
+
+// parseGemfileGroups returns the groups of every gem of the given Gemfile, keyed by the name of the gem (where the
+// groups of a gem without a group are empty).
+func parseGemfileGroups(reader io.Reader) (map[string][]string, error) {
+	groups := make(map[string][]string)
+	// note: every block of the Gemfile is on the stack, where the blocks that are not groups have no group names
+	var blocks [][]string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+
+		switch {
+		case line == "end":
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+		case gemfileGroupBlockPattern.MatchString(line):
+			blocks = append(blocks, gemfileGroupNames(gemfileGroupBlockPattern.FindStringSubmatch(line)[1]))
+		case gemfileGemPattern.MatchString(line):
+			match := gemfileGemPattern.FindStringSubmatch(line)
+			var gemGroups []string
+			for _, b := range blocks {
+				gemGroups = append(gemGroups, b...)
+			}
+			if option := gemfileGroupOptionPattern.FindStringSubmatch(match[2]); option != nil {
+				gemGroups = append(gemGroups, gemfileGroupNames(option[2])...)
+			}
+			groups[match[1]] = gemGroups
+		case gemfileBlockPattern.MatchString(line):
+			blocks = append(blocks, nil)
+		}
+	}
+	return groups, scanner.Err()
+}
+
+func gemfileGroupNames(expression string) []string {
+	var names []string
+	for _, match := range gemfileGroupNamePattern.FindAllStringSubmatch(expression, -1) {
+		names = append(names, match[1]+match[2])
+	}
+	return names
+}
+
+// parseGemfileLockRequirements returns the names of the gems that every gem of the given Gemfile.lock requires.
+func parseGemfileLockRequirements(reader io.Reader) (map[string][]string, error) {
+	requires := make(map[string][]string)
+	var current string
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch indent {
+		case 4:
+			// a gem of the specs of a section (e.g. "    rails (6.1.4)")
+			current = fields[0]
+			if _, ok := requires[current]; !ok {
+				requires[current] = nil
+			}
+		case 6:
+			// a requirement of the last gem (e.g. "      actionpack (= 6.1.4)")
+			if current != "" {
+				requires[current] = append(requires[current], fields[0])
+			}
+		default:
+			current = ""
+		}
+	}
+	return requires, scanner.Err()
+}
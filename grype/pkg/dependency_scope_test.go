@@ -0,0 +1,121 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestAddDependencyScopes(t *testing.T) {
+	src, err := source.NewFromDirectory("test-fixtures/dependency-scope")
+	require.NoError(t, err)
+	resolver, err := src.FileResolver(source.SquashedScope)
+	require.NoError(t, err)
+
+	locations := func(paths ...string) []source.Location {
+		var result []source.Location
+		for _, p := range paths {
+			found, err := resolver.FilesByPath(p)
+			require.NoError(t, err)
+			require.Len(t, found, 1, p)
+			result = append(result, found[0])
+		}
+		return result
+	}
+	npm := func(name, version string, paths ...string) Package {
+		return Package{Name: name, Version: version, Type: syftPkg.NpmPkg, Locations: locations(paths...)}
+	}
+	gem := func(name, version string) Package {
+		return Package{Name: name, Version: version, Type: syftPkg.GemPkg, Locations: locations("/bundler/Gemfile.lock")}
+	}
+
+	packages := []Package{
+		npm("express", "4.17.1", "/npm/package-lock.json"),
+		npm("debug", "2.6.9", "/npm/package-lock.json"),
+		npm("debug", "4.3.3", "/npm/package-lock.json"),
+		npm("jest", "27.4.5", "/npm/package-lock.json"),
+		// a dev dependency that is also found in another file (of which the scope is not known) may be needed at runtime
+		npm("minimist", "1.2.5", "/npm/package-lock.json", "/bundler/Gemfile"),
+		gem("rails", "6.1.4"),
+		gem("rack", "2.2.3"),
+		gem("pg", "1.2.3"),
+		gem("byebug", "11.1.3"),
+		gem("listen", "3.7.0"),
+		gem("rb-inotify", "0.10.1"),
+		gem("rubocop", "1.24.0"),
+		gem("rainbow", "3.0.0"),
+		gem("capybara", "3.36.0"),
+		gem("nokogiri", "1.12.5"),
+		gem("racc", "1.6.0"),
+		gem("rspec-rails", "5.0.2"),
+		{Name: "libc", Version: "2.31", Type: syftPkg.DebPkg},
+	}
+
+	addDependencyScopes(resolver, packages)
+
+	actual := make(map[string]string)
+	for _, p := range packages {
+		actual[string(p.Type)+":"+p.Name+"@"+p.Version] = p.Scope
+	}
+	assert.Equal(t, map[string]string{
+		"npm:express@4.17.1":    "",
+		"npm:debug@2.6.9":       "",
+		"npm:debug@4.3.3":       DevelopmentScope,
+		"npm:jest@27.4.5":       DevelopmentScope,
+		"npm:minimist@1.2.5":    "",
+		"gem:rails@6.1.4":       "",
+		"gem:rack@2.2.3":        "",
+		"gem:pg@1.2.3":          "",
+		"gem:byebug@11.1.3":     DevelopmentScope,
+		"gem:listen@3.7.0":      DevelopmentScope,
+		"gem:rb-inotify@0.10.1": DevelopmentScope,
+		"gem:rubocop@1.24.0":    DevelopmentScope,
+		"gem:rainbow@3.0.0":     DevelopmentScope,
+		"gem:capybara@3.36.0":   TestScope,
+		"gem:nokogiri@1.12.5":   TestScope,
+		"gem:racc@1.6.0":        TestScope,
+		"gem:rspec-rails@5.0.2": TestScope,
+		"deb:libc@2.31":         "",
+	}, actual)
+
+	assert.Equal(t, []string{"npm:express", "npm:debug", "npm:minimist", "gem:rails", "gem:rack", "gem:pg", "deb:libc"}, typedNames(filterDevelopmentDependencies(packages)))
+}
+
+func TestParseGemfileGroups(t *testing.T) {
+	gemfile := `
+source "https://rubygems.org"
+gem 'rails'
+gem "sqlite3", group: "development"
+gem "rspec", :group => :test
+group(:development, :test) do
+  platforms :mri do
+    gem "byebug"
+  end
+  gem "faker", groups: [:test] # also in the test group
+end
+gem "puma"
+`
+	groups, err := parseGemfileGroups(strings.NewReader(gemfile))
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"rails":   nil,
+		"sqlite3": {"development"},
+		"rspec":   {"test"},
+		"byebug":  {"development", "test"},
+		"faker":   {"development", "test", "test"},
+		"puma":    nil,
+	}, groups)
+}
+
+func typedNames(packages []Package) []string {
+	var result []string
+	for _, p := range packages {
+		result = append(result, string(p.Type)+":"+p.Name)
+	}
+	return result
+}
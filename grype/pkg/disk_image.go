@@ -0,0 +1,144 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+const sectorSize = 512
+
+// diskFormat is the format of a VM disk image, named as by qemu-img.
+type diskFormat string
+
+const (
+	rawDiskFormat   diskFormat = "raw"
+	qcow2DiskFormat diskFormat = "qcow2"
+	vmdkDiskFormat  diskFormat = "vmdk"
+	vhdDiskFormat   diskFormat = "vpc"
+	vhdxDiskFormat  diskFormat = "vhdx"
+)
+
+// detectDiskFormat determines the format of a disk image of the given size by its magic bytes, where a disk image
+// without any known header is raw.
+func detectDiskFormat(r io.ReaderAt, size int64) (diskFormat, error) {
+	header := make([]byte, 32)
+	if _, err := r.ReadAt(header, 0); err != nil && err != io.EOF {
+		return "", fmt.Errorf("unable to read disk image header: %w", err)
+	}
+
+	switch {
+	case bytes.HasPrefix(header, []byte("QFI\xfb")):
+		return qcow2DiskFormat, nil
+	case bytes.HasPrefix(header, []byte("KDMV")), bytes.HasPrefix(header, []byte("# Disk DescriptorFile")):
+		return vmdkDiskFormat, nil
+	case bytes.HasPrefix(header, []byte("vhdxfile")):
+		return vhdxDiskFormat, nil
+	case bytes.HasPrefix(header, []byte("conectix")):
+		// the copy of the footer at the start of a dynamic VHD
+		return vhdDiskFormat, nil
+	}
+
+	if size >= sectorSize {
+		// the footer at the end of a fixed VHD
+		footer := make([]byte, 8)
+		if _, err := r.ReadAt(footer, size-sectorSize); err != nil && err != io.EOF {
+			return "", fmt.Errorf("unable to read disk image footer: %w", err)
+		}
+		if bytes.Equal(footer, []byte("conectix")) {
+			return vhdDiskFormat, nil
+		}
+	}
+	return rawDiskFormat, nil
+}
+
+// partition is a partition of a raw disk image, in bytes.
+type partition struct {
+	offset int64
+	size   int64
+}
+
+// diskPartitions returns the partitions of a raw disk image of the given size from its GPT or MBR partition table,
+// where a disk image without a partition table is a single filesystem. Logical partitions (within an extended MBR
+// partition) are not supported.
+func diskPartitions(r io.ReaderAt, size int64) ([]partition, error) {
+	mbr := make([]byte, sectorSize)
+	if _, err := r.ReadAt(mbr, 0); err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("the disk image is too small")
+		}
+		return nil, fmt.Errorf("unable to read master boot record: %w", err)
+	}
+
+	if mbr[510] != 0x55 || mbr[511] != 0xaa {
+		return []partition{{offset: 0, size: size}}, nil
+	}
+
+	var partitions []partition
+	for i := 0; i < 4; i++ {
+		entry := mbr[446+i*16 : 446+(i+1)*16]
+		switch partitionType := entry[4]; partitionType {
+		case 0x00:
+			// unused
+			continue
+		case 0xee:
+			// the protective MBR of a GPT disk
+			return gptPartitions(r)
+		case 0x05, 0x0f, 0x85:
+			// extended partitions
+			continue
+		}
+		start := int64(binary.LittleEndian.Uint32(entry[8:12]))
+		sectors := int64(binary.LittleEndian.Uint32(entry[12:16]))
+		if sectors == 0 {
+			continue
+		}
+		partitions = append(partitions, partition{offset: start * sectorSize, size: sectors * sectorSize})
+	}
+
+	if len(partitions) == 0 {
+		// e.g. a FAT filesystem (which has a boot sector signature) without a partition table
+		return []partition{{offset: 0, size: size}}, nil
+	}
+	return partitions, nil
+}
+
+// gptPartitions returns the partitions of the GUID partition table of a disk image.
+func gptPartitions(r io.ReaderAt) ([]partition, error) {
+	header := make([]byte, 92)
+	if _, err := r.ReadAt(header, sectorSize); err != nil {
+		return nil, fmt.Errorf("unable to read GPT header: %w", err)
+	}
+	if !bytes.Equal(header[:8], []byte("EFI PART")) {
+		return nil, fmt.Errorf("bad GPT header signature")
+	}
+
+	entriesLBA := int64(binary.LittleEndian.Uint64(header[72:80]))
+	entries := int64(binary.LittleEndian.Uint32(header[80:84]))
+	entrySize := int64(binary.LittleEndian.Uint32(header[84:88]))
+	if entrySize < 128 || entries > 1024 {
+		return nil, fmt.Errorf("bad GPT header (%d entries of %d bytes)", entries, entrySize)
+	}
+
+	table := make([]byte, entries*entrySize)
+	if _, err := r.ReadAt(table, entriesLBA*sectorSize); err != nil {
+		return nil, fmt.Errorf("unable to read GPT partition entries: %w", err)
+	}
+
+	var partitions []partition
+	unused := make([]byte, 16)
+	for i := int64(0); i < entries; i++ {
+		entry := table[i*entrySize : (i+1)*entrySize]
+		if bytes.Equal(entry[:16], unused) {
+			continue
+		}
+		first := int64(binary.LittleEndian.Uint64(entry[32:40]))
+		last := int64(binary.LittleEndian.Uint64(entry[40:48]))
+		if last < first {
+			continue
+		}
+		partitions = append(partitions, partition{offset: first * sectorSize, size: (last - first + 1) * sectorSize})
+	}
+	return partitions, nil
+}
@@ -0,0 +1,138 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectDiskFormat(t *testing.T) {
+	fixedVHD := make([]byte, 4096)
+	copy(fixedVHD[4096-sectorSize:], "conectix")
+
+	tests := []struct {
+		name     string
+		contents []byte
+		expected diskFormat
+	}{
+		{name: "qcow2", contents: []byte("QFI\xfb\x00\x00\x00\x03"), expected: qcow2DiskFormat},
+		{name: "sparse vmdk", contents: []byte("KDMV\x01\x00\x00\x00"), expected: vmdkDiskFormat},
+		{name: "vmdk descriptor", contents: []byte("# Disk DescriptorFile\nversion=1\n"), expected: vmdkDiskFormat},
+		{name: "vhdx", contents: []byte("vhdxfile\x00\x00"), expected: vhdxDiskFormat},
+		{name: "dynamic vhd", contents: []byte("conectix\x00\x00\x00\x02"), expected: vhdDiskFormat},
+		{name: "fixed vhd", contents: fixedVHD, expected: vhdDiskFormat},
+		{name: "raw", contents: make([]byte, 4096), expected: rawDiskFormat},
+		{name: "tiny raw", contents: []byte("ab"), expected: rawDiskFormat},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			format, err := detectDiskFormat(bytes.NewReader(test.contents), int64(len(test.contents)))
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, format)
+		})
+	}
+}
+
+func mbrDisk(entries ...[16]byte) []byte {
+	disk := make([]byte, 4*sectorSize)
+	for i, entry := range entries {
+		copy(disk[446+i*16:], entry[:])
+	}
+	disk[510], disk[511] = 0x55, 0xaa
+	return disk
+}
+
+func mbrEntry(partitionType byte, start, sectors uint32) (entry [16]byte) {
+	entry[4] = partitionType
+	binary.LittleEndian.PutUint32(entry[8:12], start)
+	binary.LittleEndian.PutUint32(entry[12:16], sectors)
+	return entry
+}
+
+func gptDisk() []byte {
+	disk := mbrDisk(mbrEntry(0xee, 1, 0xffffffff))
+	disk = append(disk, make([]byte, 4*sectorSize)...)
+
+	header := disk[sectorSize:]
+	copy(header, "EFI PART")
+	binary.LittleEndian.PutUint64(header[72:80], 2)
+	binary.LittleEndian.PutUint32(header[80:84], 4)
+	binary.LittleEndian.PutUint32(header[84:88], 128)
+
+	entries := disk[2*sectorSize:]
+	// an EFI system partition, an unused entry, and a linux filesystem
+	entries[0] = 0x28
+	binary.LittleEndian.PutUint64(entries[32:40], 2048)
+	binary.LittleEndian.PutUint64(entries[40:48], 4095)
+	entries[256] = 0xaf
+	binary.LittleEndian.PutUint64(entries[256+32:256+40], 4096)
+	binary.LittleEndian.PutUint64(entries[256+40:256+48], 8191)
+	return disk
+}
+
+func TestDiskPartitions(t *testing.T) {
+	tests := []struct {
+		name     string
+		disk     []byte
+		expected []partition
+		wantErr  bool
+	}{
+		{
+			name: "mbr",
+			disk: mbrDisk(
+				mbrEntry(0x83, 2048, 1024),
+				mbrEntry(0x00, 0, 0),
+				mbrEntry(0x05, 4096, 2048),
+				mbrEntry(0x82, 8192, 512),
+			),
+			expected: []partition{
+				{offset: 2048 * sectorSize, size: 1024 * sectorSize},
+				{offset: 8192 * sectorSize, size: 512 * sectorSize},
+			},
+		},
+		{
+			name: "gpt",
+			disk: gptDisk(),
+			expected: []partition{
+				{offset: 2048 * sectorSize, size: 2048 * sectorSize},
+				{offset: 4096 * sectorSize, size: 4096 * sectorSize},
+			},
+		},
+		{
+			name:     "no partition table",
+			disk:     make([]byte, 4*sectorSize),
+			expected: []partition{{offset: 0, size: 4 * sectorSize}},
+		},
+		{
+			name:     "boot sector without partitions",
+			disk:     mbrDisk(),
+			expected: []partition{{offset: 0, size: 4 * sectorSize}},
+		},
+		{
+			name:    "protective mbr without gpt header",
+			disk:    mbrDisk(mbrEntry(0xee, 1, 0xffffffff)),
+			wantErr: true,
+		},
+		{
+			name:    "too small",
+			disk:    []byte("abc"),
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			partitions, err := diskPartitions(bytes.NewReader(test.disk), int64(len(test.disk)))
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, partitions)
+		})
+	}
+}
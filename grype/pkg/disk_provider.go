@@ -0,0 +1,171 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/anchore/grype/internal/log"
+)
+
+const vmDiskScheme = "vm:"
+
+// vmDiskProvider catalogs the filesystem of a VM disk image (qcow2, VMDK, VHD, VHDX, or raw), given as "vm:<path>".
+// The filesystems of the disk are mounted read-only, with guestmount (from libguestfs) when it is installed, or else
+// as loop devices (which requires root privileges, and qemu-img to convert disk images that are not raw), and copied
+// for the scan.
+func vmDiskProvider(spanCtx context.Context, userInput string, config ProviderConfig) ([]Package, Context, error) {
+	if !strings.HasPrefix(userInput, vmDiskScheme) {
+		return nil, Context{}, errDoesNotProvide
+	}
+	path := strings.TrimPrefix(userInput, vmDiskScheme)
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, Context{}, fmt.Errorf("unable to open disk image: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, Context{}, fmt.Errorf("unable to stat disk image: %w", err)
+	}
+	format, err := detectDiskFormat(f, info.Size())
+	if err != nil {
+		return nil, Context{}, err
+	}
+	log.Debugf("disk image=%q has format=%s", path, format)
+
+	return filesystemCopyProvider(spanCtx, userInput, config, "vm", func(fs containerFS) error {
+		if _, err := exec.LookPath("guestmount"); err == nil {
+			return copyGuestFilesystem(fs, path, format)
+		}
+		return copyDiskPartitions(fs, path, format)
+	})
+}
+
+// copyGuestFilesystem copies the filesystem of the operating system of a disk image, which guestmount inspects to
+// mount every filesystem of the operating system at its mount point.
+func copyGuestFilesystem(fs containerFS, path string, format diskFormat) error {
+	mountPoint, err := ioutil.TempDir("", "grype-vm-mount-")
+	if err != nil {
+		return fmt.Errorf("unable to create mount point: %w", err)
+	}
+	defer os.RemoveAll(mountPoint)
+
+	if err := runCommand("guestmount", "-a", path, "--format="+string(format), "-i", "--ro", mountPoint); err != nil {
+		return err
+	}
+	defer func() {
+		if err := runCommand("guestunmount", mountPoint); err != nil {
+			log.Warnf("unable to unmount disk image: %+v", err)
+		}
+	}()
+
+	return fs.copyDir(mountPoint)
+}
+
+// copyDiskPartitions copies the filesystems of the partitions of a disk image, which are mounted as loop devices.
+// The partition of the operating system (i.e. with an os-release file) is copied to the root, while any other
+// partition N is copied to /partitionN (unless it is the only partition).
+func copyDiskPartitions(fs containerFS, path string, format diskFormat) error {
+	workDir, err := ioutil.TempDir("", "grype-vm-mount-")
+	if err != nil {
+		return fmt.Errorf("unable to create mount point: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	raw := path
+	if format != rawDiskFormat {
+		// qemu-img writes a sparse file, so only the allocated blocks of the disk take space
+		raw = filepath.Join(workDir, "disk.raw")
+		if err := runCommand("qemu-img", "convert", "-f", string(format), "-O", "raw", path, raw); err != nil {
+			return fmt.Errorf("unable to convert disk image to raw (is qemu-img installed?): %w", err)
+		}
+	}
+
+	f, err := os.Open(raw)
+	if err != nil {
+		return fmt.Errorf("unable to open disk image: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("unable to stat disk image: %w", err)
+	}
+	partitions, err := diskPartitions(f, info.Size())
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	var mounted []string
+	defer func() {
+		for _, mountPoint := range mounted {
+			if err := runCommand("umount", mountPoint); err != nil {
+				log.Warnf("unable to unmount disk image partition: %+v", err)
+			}
+		}
+	}()
+
+	root := -1
+	mountPoints := make(map[int]string)
+	for i, p := range partitions {
+		mountPoint := filepath.Join(workDir, "partition"+strconv.Itoa(i+1))
+		if err := os.Mkdir(mountPoint, 0700); err != nil {
+			return fmt.Errorf("unable to create mount point: %w", err)
+		}
+		options := fmt.Sprintf("ro,loop,offset=%d,sizelimit=%d", p.offset, p.size)
+		if err := runCommand("mount", "-o", options, raw, mountPoint); err != nil {
+			// e.g. swap, or a filesystem unknown to the kernel
+			log.Debugf("skipping partition %d of disk image: %+v", i+1, err)
+			continue
+		}
+		mounted = append(mounted, mountPoint)
+		mountPoints[i] = mountPoint
+
+		if root < 0 && hasOSRelease(mountPoint) {
+			root = i
+		}
+	}
+	if len(mounted) == 0 {
+		return fmt.Errorf("unable to mount any partition of the disk image (mounting requires root privileges, or else install guestmount)")
+	}
+
+	for i, mountPoint := range mountPoints {
+		target := fs
+		if i != root && (root >= 0 || len(mountPoints) > 1) {
+			target = containerFS{root: fs.path("partition" + strconv.Itoa(i+1))}
+		}
+		if err := target.copyDir(mountPoint); err != nil {
+			return fmt.Errorf("unable to copy partition %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func hasOSRelease(dir string) bool {
+	for _, release := range []string{"etc/os-release", "usr/lib/os-release"} {
+		if _, err := os.Lstat(filepath.Join(dir, release)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// runCommand runs the given command, where a failure includes the output of the command.
+func runCommand(name string, args ...string) error {
+	var output bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(output.String()))
+	}
+	return nil
+}
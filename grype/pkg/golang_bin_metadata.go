@@ -0,0 +1,6 @@
+package pkg
+
+type GolangBinMetadata struct {
+	GoCompiledVersion string
+	Architecture      string
+}
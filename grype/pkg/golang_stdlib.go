@@ -0,0 +1,67 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+// GoStdlibPackageName is the name used for the go standard library package, which is how go toolchain
+// vulnerabilities are reported (e.g. within the Go vulnerability database and GitHub Security Advisories).
+const GoStdlibPackageName = "stdlib"
+
+// goStdlibPackages creates a synthetic "stdlib" package for every unique go toolchain version found within the go
+// binaries of the given packages. This allows for go toolchain vulnerabilities to be matched against the compiler
+// version embedded within binaries.
+func goStdlibPackages(packages []Package) []Package {
+	stdlibs := make(map[string]*Package)
+	for _, p := range packages {
+		metadata, ok := p.Metadata.(GolangBinMetadata)
+		if !ok || metadata.GoCompiledVersion == "" {
+			continue
+		}
+
+		for _, l := range p.Locations {
+			key := fmt.Sprintf("%s:%s", metadata.GoCompiledVersion, l.RealPath)
+			if _, exists := stdlibs[key]; exists {
+				continue
+			}
+			stdlibs[key] = newGoStdlibPackage(metadata, l)
+		}
+	}
+
+	keys := make([]string, 0, len(stdlibs))
+	for k := range stdlibs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make([]Package, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, *stdlibs[k])
+	}
+	return result
+}
+
+func newGoStdlibPackage(metadata GolangBinMetadata, location source.Location) *Package {
+	p := &Package{
+		ID:        ID(fmt.Sprintf("%s@%s:%s", GoStdlibPackageName, metadata.GoCompiledVersion, location.RealPath)),
+		Name:      GoStdlibPackageName,
+		Version:   metadata.GoCompiledVersion,
+		Locations: []source.Location{location},
+		Language:  pkg.Go,
+		Type:      pkg.GoModulePkg,
+		PURL:      fmt.Sprintf("pkg:golang/%s@%s", GoStdlibPackageName, metadata.GoCompiledVersion),
+		Metadata:  metadata,
+	}
+
+	// NVD tracks go toolchain vulnerabilities against the golang:go product
+	cpe, err := pkg.NewCPE(fmt.Sprintf("cpe:2.3:a:golang:go:%s:*:*:*:*:*:*:*", strings.TrimPrefix(metadata.GoCompiledVersion, "go")))
+	if err == nil {
+		p.CPEs = []pkg.CPE{cpe}
+	}
+	return p
+}
@@ -0,0 +1,120 @@
+package pkg
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/pkg/cataloger/common"
+)
+
+const haskellCatalogerName = "haskell-cataloger"
+
+// stackPackagePattern matches hackage package identifiers within stack lock files, which may include a hash and
+// revision (e.g. "aeson-2.0.3.0@sha256:d3ab4f...,5851")
+var stackPackagePattern = regexp.MustCompile(`^(?P<name>[A-Za-z0-9-]+)-(?P<version>\d+(\.\d+)*)(@.*)?$`)
+
+// cabalConstraintPattern matches pinned version constraints within cabal freeze files (e.g. "any.aeson ==2.0.3.0")
+var cabalConstraintPattern = regexp.MustCompile(`^(any\.)?(?P<name>[A-Za-z0-9-]+)\s*==\s*(?P<version>\d+(\.\d+)*)$`)
+
+type stackLock struct {
+	Packages []struct {
+		Completed struct {
+			Hackage string `yaml:"hackage"`
+		} `yaml:"completed"`
+	} `yaml:"packages"`
+}
+
+// newHaskellCataloger returns a cataloger for haskell packages pinned within stack and cabal lock files (which syft
+// does not catalog).
+func newHaskellCataloger() *common.GenericCataloger {
+	globParsers := map[string]common.ParserFn{
+		"**/stack.yaml.lock":      parseStackLock,
+		"**/cabal.project.freeze": parseCabalFreeze,
+	}
+
+	return common.NewGenericCataloger(nil, globParsers, haskellCatalogerName)
+}
+
+// parseStackLock returns the hackage packages from a stack.yaml.lock file (packages from other sources, such as git
+// repositories, are ignored).
+func parseStackLock(_ string, reader io.Reader) ([]*pkg.Package, []artifact.Relationship, error) {
+	contents, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read stack lock file: %w", err)
+	}
+
+	var lock stackLock
+	if err := yaml.Unmarshal(contents, &lock); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse stack lock file: %w", err)
+	}
+
+	var packages []*pkg.Package
+	for _, p := range lock.Packages {
+		match := stackPackagePattern.FindStringSubmatch(p.Completed.Hackage)
+		if match == nil {
+			continue
+		}
+		packages = append(packages, newHackagePackage(
+			match[stackPackagePattern.SubexpIndex("name")],
+			match[stackPackagePattern.SubexpIndex("version")],
+		))
+	}
+	return packages, nil, nil
+}
+
+// parseCabalFreeze returns the packages pinned within the constraints of a cabal.project.freeze file (flag and
+// installed constraints are ignored).
+func parseCabalFreeze(_ string, reader io.Reader) ([]*pkg.Package, []artifact.Relationship, error) {
+	contents, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read cabal freeze file: %w", err)
+	}
+
+	var packages []*pkg.Package
+	var inConstraints bool
+	for _, line := range strings.Split(string(contents), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "constraints:"):
+			inConstraints = true
+			trimmed = strings.TrimPrefix(trimmed, "constraints:")
+		case trimmed != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t"):
+			// any other (non-continuation) field ends the constraints
+			inConstraints = false
+		}
+
+		if !inConstraints {
+			continue
+		}
+
+		for _, constraint := range strings.Split(trimmed, ",") {
+			match := cabalConstraintPattern.FindStringSubmatch(strings.TrimSpace(constraint))
+			if match == nil {
+				continue
+			}
+			packages = append(packages, newHackagePackage(
+				match[cabalConstraintPattern.SubexpIndex("name")],
+				match[cabalConstraintPattern.SubexpIndex("version")],
+			))
+		}
+	}
+	return packages, nil, nil
+}
+
+func newHackagePackage(name, version string) *pkg.Package {
+	return &pkg.Package{
+		Name:     name,
+		Version:  version,
+		Language: Haskell,
+		Type:     HackagePkg,
+		PURL:     fmt.Sprintf("pkg:hackage/%s@%s", url.PathEscape(name), url.PathEscape(version)),
+	}
+}
@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestParseStackLock(t *testing.T) {
+	f, err := os.Open("test-fixtures/haskell/stack/stack.yaml.lock")
+	require.NoError(t, err)
+	defer f.Close()
+
+	packages, _, err := parseStackLock(f.Name(), f)
+	require.NoError(t, err)
+
+	assertHackagePackages(t, map[string]string{
+		"aeson": "2.0.3.0",
+		"warp":  "3.3.18",
+	}, packages)
+}
+
+func TestParseCabalFreeze(t *testing.T) {
+	f, err := os.Open("test-fixtures/haskell/cabal/cabal.project.freeze")
+	require.NoError(t, err)
+	defer f.Close()
+
+	packages, _, err := parseCabalFreeze(f.Name(), f)
+	require.NoError(t, err)
+
+	assertHackagePackages(t, map[string]string{
+		"Cabal": "3.4.1.0",
+		"aeson": "2.0.3.0",
+		"text":  "1.2.5.0",
+	}, packages)
+}
+
+func assertHackagePackages(t *testing.T, expected map[string]string, packages []*syftPkg.Package) {
+	t.Helper()
+
+	actual := make(map[string]string)
+	for _, p := range packages {
+		assert.Equal(t, HackagePkg, p.Type)
+		assert.Equal(t, Haskell, p.Language)
+		assert.Equal(t, "pkg:hackage/"+p.Name+"@"+p.Version, p.PURL)
+		actual[p.Name] = p.Version
+	}
+	assert.Equal(t, expected, actual)
+}
@@ -0,0 +1,99 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/pkg/cataloger/common"
+	"github.com/anchore/syft/syft/pkg/cataloger/common/cpe"
+)
+
+const homebrewCatalogerName = "homebrew-cellar-cataloger"
+
+// homebrewReceiptGlob matches the install receipt of each formula installed into a homebrew (or linuxbrew) Cellar
+// (e.g. "/opt/homebrew/Cellar/openssl@1.1/1.1.1k_1/INSTALL_RECEIPT.json" or
+// "/home/linuxbrew/.linuxbrew/Cellar/jq/1.6/INSTALL_RECEIPT.json")
+const homebrewReceiptGlob = "**/Cellar/*/*/INSTALL_RECEIPT.json"
+
+// homebrewReceipt represents the fields of interest within a formula install receipt
+type homebrewReceipt struct {
+	Source struct {
+		Tap string `json:"tap"`
+	} `json:"source"`
+}
+
+// newHomebrewCataloger returns a cataloger for formulae installed with homebrew (which syft does not catalog).
+func newHomebrewCataloger() *common.GenericCataloger {
+	globParsers := map[string]common.ParserFn{
+		homebrewReceiptGlob: parseHomebrewReceipt,
+	}
+
+	return common.NewGenericCataloger(nil, globParsers, homebrewCatalogerName)
+}
+
+func parseHomebrewReceipt(receiptPath string, reader io.Reader) ([]*pkg.Package, []artifact.Relationship, error) {
+	var receipt homebrewReceipt
+	if err := json.NewDecoder(reader).Decode(&receipt); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse homebrew install receipt: %w", err)
+	}
+
+	// the Cellar is laid out by formula name and installed version (e.g. "Cellar/openssl@1.1/1.1.1k_1")
+	versionDir := path.Dir(receiptPath)
+	name := path.Base(path.Dir(versionDir))
+	version, revision := splitHomebrewVersion(path.Base(versionDir))
+	if name == "" || version == "" {
+		return nil, nil, nil
+	}
+
+	metadata := HomebrewMetadata{
+		Tap:      receipt.Source.Tap,
+		Revision: revision,
+	}
+
+	p := &pkg.Package{
+		Name:         name,
+		Version:      version,
+		Type:         HomebrewPkg,
+		PURL:         homebrewPackageURL(name, version, metadata),
+		MetadataType: HomebrewMetadataType,
+		Metadata:     metadata,
+	}
+
+	// versioned formulae are named after the upstream project and the version series they track
+	// (e.g. "openssl@1.1" and "python@3.9"), which is not part of the upstream name
+	p.CPEs = cpe.Generate(pkg.Package{
+		Name:    strings.SplitN(name, "@", 2)[0],
+		Version: version,
+		Type:    HomebrewPkg,
+	})
+
+	return []*pkg.Package{p}, nil, nil
+}
+
+// splitHomebrewVersion splits an installed formula version into the upstream version and the formula revision
+// (e.g. "1.1.1k_1" becomes "1.1.1k" and 1).
+func splitHomebrewVersion(installed string) (string, int) {
+	if idx := strings.LastIndex(installed, "_"); idx > 0 {
+		if revision, err := strconv.Atoi(installed[idx+1:]); err == nil {
+			return installed[:idx], revision
+		}
+	}
+	return installed, 0
+}
+
+// homebrewPackageURL returns a package URL for the formula, qualified by the tap when the formula was not installed
+// from homebrew/core.
+func homebrewPackageURL(name, version string, metadata HomebrewMetadata) string {
+	purl := fmt.Sprintf("pkg:brew/%s@%s", url.PathEscape(name), url.PathEscape(version))
+	if metadata.Tap != "" && metadata.Tap != "homebrew/core" {
+		purl += fmt.Sprintf("?tap=%s", url.QueryEscape(metadata.Tap))
+	}
+	return purl
+}
@@ -0,0 +1,88 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestHomebrewCataloger(t *testing.T) {
+	src, err := source.NewFromDirectory("test-fixtures/homebrew")
+	require.NoError(t, err)
+
+	resolver, err := src.FileResolver(source.SquashedScope)
+	require.NoError(t, err)
+
+	packages, _, err := newHomebrewCataloger().Catalog(resolver)
+	require.NoError(t, err)
+
+	type expectation struct {
+		version  string
+		purl     string
+		product  string
+		metadata HomebrewMetadata
+	}
+
+	expected := map[string]expectation{
+		"openssl@1.1": {
+			version:  "1.1.1k",
+			purl:     "pkg:brew/openssl@1.1@1.1.1k",
+			product:  "openssl",
+			metadata: HomebrewMetadata{Tap: "homebrew/core", Revision: 1},
+		},
+		"jq": {
+			version:  "1.6",
+			purl:     "pkg:brew/jq@1.6",
+			product:  "jq",
+			metadata: HomebrewMetadata{Tap: "homebrew/core"},
+		},
+		"terraform": {
+			version:  "1.1.2",
+			purl:     "pkg:brew/terraform@1.1.2?tap=hashicorp%2Ftap",
+			product:  "terraform",
+			metadata: HomebrewMetadata{Tap: "hashicorp/tap"},
+		},
+	}
+
+	require.Len(t, packages, len(expected))
+	for _, p := range packages {
+		e, ok := expected[p.Name]
+		require.True(t, ok, "unexpected package: %s", p.Name)
+
+		assert.Equal(t, e.version, p.Version)
+		assert.Equal(t, e.purl, p.PURL)
+		assert.Equal(t, HomebrewPkg, p.Type)
+		assert.Equal(t, HomebrewMetadataType, p.MetadataType)
+		assert.Equal(t, e.metadata, p.Metadata)
+		require.NotEmpty(t, p.CPEs)
+		for _, c := range p.CPEs {
+			assert.Equal(t, e.product, c.Product)
+			assert.Equal(t, e.version, c.Version)
+		}
+	}
+}
+
+func TestSplitHomebrewVersion(t *testing.T) {
+	tests := []struct {
+		installed        string
+		expectedVersion  string
+		expectedRevision int
+	}{
+		{installed: "1.6", expectedVersion: "1.6"},
+		{installed: "1.1.1k_1", expectedVersion: "1.1.1k", expectedRevision: 1},
+		{installed: "2021.1_12", expectedVersion: "2021.1", expectedRevision: 12},
+		// not a revision
+		{installed: "2.0_beta", expectedVersion: "2.0_beta"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.installed, func(t *testing.T) {
+			version, revision := splitHomebrewVersion(test.installed)
+			assert.Equal(t, test.expectedVersion, version)
+			assert.Equal(t, test.expectedRevision, revision)
+		})
+	}
+}
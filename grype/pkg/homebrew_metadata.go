@@ -0,0 +1,6 @@
+package pkg
+
+type HomebrewMetadata struct {
+	Tap      string // the tap the formula was installed from (e.g. "homebrew/core")
+	Revision int    // the formula revision, which is incremented for rebuilds of the same upstream version
+}
@@ -0,0 +1,106 @@
+package pkg
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/source"
+)
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+// Layer describes the image layer that introduced a package.
+type Layer struct {
+	Index   int    // the position of the layer within the image (starting at 0 for the bottom layer)
+	Digest  string // the digest of the layer
+	Command string // the command that created the layer (e.g. a Dockerfile instruction), if known
+}
+
+// imageConfig is the part of the image configuration (see the OCI image spec) describing how each layer was created.
+type imageConfig struct {
+	History []struct {
+		CreatedBy  string `json:"created_by"`
+		EmptyLayer bool   `json:"empty_layer"`
+	} `json:"history"`
+}
+
+// addLayers attributes each package of an image to the earliest layer that any of its locations are within, which is
+// the layer that introduced the package (e.g. the base image or a Dockerfile instruction of the image itself).
+func addLayers(packages []Package, src *source.Metadata) {
+	if src == nil || src.Scheme != source.ImageScheme {
+		return
+	}
+
+	layers := imageLayers(src.ImageMetadata)
+	indexByDigest := make(map[string]int)
+	for idx, l := range layers {
+		indexByDigest[l.Digest] = idx
+	}
+
+	for i := range packages {
+		introduced := -1
+		for _, l := range packages[i].Locations {
+			if idx, ok := indexByDigest[l.FileSystemID]; ok && (introduced < 0 || idx < introduced) {
+				introduced = idx
+			}
+		}
+		if introduced >= 0 {
+			layer := layers[introduced]
+			packages[i].Layer = &layer
+		}
+	}
+}
+
+// imageLayers returns the layers of an image, with the command that created each layer when the image configuration
+// describes it.
+func imageLayers(metadata source.ImageMetadata) []Layer {
+	var commands []string
+	if len(metadata.RawConfig) > 0 {
+		var config imageConfig
+		if err := json.Unmarshal(metadata.RawConfig, &config); err != nil {
+			log.Warnf("unable to read the history of the image layers: %+v", err)
+		}
+		for _, h := range config.History {
+			// entries of empty layers (e.g. ENV instructions) do not correspond to a layer
+			if !h.EmptyLayer {
+				commands = append(commands, layerCommand(h.CreatedBy))
+			}
+		}
+	}
+	if len(commands) != len(metadata.Layers) {
+		// the history does not describe all layers, so the command of each layer is unknown
+		commands = nil
+	}
+
+	layers := make([]Layer, 0, len(metadata.Layers))
+	for idx, l := range metadata.Layers {
+		layer := Layer{
+			Index:  idx,
+			Digest: l.Digest,
+		}
+		if commands != nil {
+			layer.Command = commands[idx]
+		}
+		layers = append(layers, layer)
+	}
+	return layers
+}
+
+// layerCommand returns the Dockerfile instruction of a layer given how the layer was created, e.g.
+// "/bin/sh -c apk add curl" is "RUN apk add curl" and "/bin/sh -c #(nop) ADD file:abc in / " is "ADD file:abc in /".
+func layerCommand(createdBy string) string {
+	command := strings.TrimSpace(createdBy)
+	// images built with BuildKit describe the instruction and mark the command as such
+	command = strings.TrimSpace(strings.TrimSuffix(command, "# buildkit"))
+	switch {
+	case strings.HasPrefix(command, "/bin/sh -c #(nop)"):
+		command = strings.TrimPrefix(command, "/bin/sh -c #(nop)")
+	case strings.HasPrefix(command, "/bin/sh -c "):
+		command = "RUN " + strings.TrimPrefix(command, "/bin/sh -c ")
+	case strings.HasPrefix(command, "RUN /bin/sh -c "):
+		command = "RUN " + strings.TrimPrefix(command, "RUN /bin/sh -c ")
+	}
+	return whitespace.ReplaceAllString(strings.TrimSpace(command), " ")
+}
@@ -0,0 +1,87 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestLayerCommand(t *testing.T) {
+	tests := []struct {
+		createdBy string
+		expected  string
+	}{
+		{
+			createdBy: "/bin/sh -c #(nop) ADD file:9a4f77dfaba7fd2aa78186e4ef0e7486ad55101cefc1fabbc1b385601bb38920 in / ",
+			expected:  "ADD file:9a4f77dfaba7fd2aa78186e4ef0e7486ad55101cefc1fabbc1b385601bb38920 in /",
+		},
+		{
+			createdBy: "/bin/sh -c #(nop)  CMD [\"/bin/sh\"]",
+			expected:  "CMD [\"/bin/sh\"]",
+		},
+		{
+			createdBy: "/bin/sh -c apk add --no-cache   curl",
+			expected:  "RUN apk add --no-cache curl",
+		},
+		{
+			createdBy: "RUN /bin/sh -c apk add curl # buildkit",
+			expected:  "RUN apk add curl",
+		},
+		{
+			createdBy: "COPY app.jar /app/ # buildkit",
+			expected:  "COPY app.jar /app/",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			assert.Equal(t, test.expected, layerCommand(test.createdBy))
+		})
+	}
+}
+
+func TestAddLayers(t *testing.T) {
+	src := &source.Metadata{
+		Scheme: source.ImageScheme,
+		ImageMetadata: source.ImageMetadata{
+			Layers: []source.LayerMetadata{
+				{Digest: "sha256:base"},
+				{Digest: "sha256:app"},
+			},
+			RawConfig: []byte(`{"history": [
+				{"created_by": "/bin/sh -c #(nop) ADD file:abc in / "},
+				{"created_by": "/bin/sh -c #(nop)  ENV A=b", "empty_layer": true},
+				{"created_by": "COPY app.jar /app/ # buildkit"}
+			]}`),
+		},
+	}
+
+	location := func(digest string) source.Location {
+		l := source.NewLocation("/some/path")
+		l.FileSystemID = digest
+		return l
+	}
+
+	packages := []Package{
+		{Name: "base", Locations: []source.Location{location("sha256:app"), location("sha256:base")}},
+		{Name: "app", Locations: []source.Location{location("sha256:app")}},
+		{Name: "elsewhere", Locations: []source.Location{location("sha256:unknown")}},
+	}
+	addLayers(packages, src)
+
+	assert.Equal(t, &Layer{Index: 0, Digest: "sha256:base", Command: "ADD file:abc in /"}, packages[0].Layer)
+	assert.Equal(t, &Layer{Index: 1, Digest: "sha256:app", Command: "COPY app.jar /app/"}, packages[1].Layer)
+	assert.Nil(t, packages[2].Layer)
+
+	// without a history that describes every layer, layers are only described by digest
+	src.ImageMetadata.RawConfig = []byte(`{"history": [{"created_by": "/bin/sh -c #(nop) ADD file:abc in / "}]}`)
+	packages[0].Layer = nil
+	addLayers(packages[:1], src)
+	assert.Equal(t, &Layer{Index: 0, Digest: "sha256:base"}, packages[0].Layer)
+
+	// packages of other sources are not attributed to layers
+	packages[0].Layer = nil
+	addLayers(packages[:1], &source.Metadata{Scheme: source.DirectoryScheme})
+	assert.Nil(t, packages[0].Layer)
+}
@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+// imageSchemes are the schemes that an image reference may be given with, where references without a scheme are
+// pulled from the registry.
+var imageSchemes = []string{"registry", "docker"}
+
+var (
+	// registryHost is a registry host name (or address) with an optional port, which go-containerregistry does not
+	// validate (e.g. "dir:" is accepted as the registry of "dir:/")
+	registryHost = regexp.MustCompile(`^(\[[0-9a-fA-F:]+\]|[a-zA-Z0-9]([a-zA-Z0-9.-]*[a-zA-Z0-9])?)(:[0-9]+)?$`)
+	// repositoryPath is a repository within a registry (see the reference grammar of the OCI distribution spec)
+	repositoryPath = regexp.MustCompile(`^[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*(/[a-z0-9]+((\.|_|__|-+)[a-z0-9]+)*)*$`)
+)
+
+// ImageReference is a validated reference to an image within a registry (or the docker daemon), which (unlike the
+// input of Provide) cannot refer to anything else (e.g. "dir:/" or "sbom:/etc/passwd"). This is how references from
+// untrusted clients (e.g. of an API) are scanned.
+type ImageReference struct {
+	// Scheme is where the image is read from ("registry" or "docker")
+	Scheme string
+	// Reference is the image within the registry
+	Reference name.Reference
+}
+
+// ParseImageReference parses the given image reference (e.g. "alpine:3.14", "registry:ghcr.io/org/app@sha256:..."),
+// rejecting any other scheme than "registry" and "docker" and any reference that is not a valid image reference.
+func ParseImageReference(image string) (ImageReference, error) {
+	image = strings.TrimSpace(image)
+	scheme := "registry"
+	for _, s := range imageSchemes {
+		if strings.HasPrefix(image, s+":") {
+			scheme = s
+			image = strings.TrimPrefix(image, s+":")
+			break
+		}
+	}
+	return newImageReference(scheme, image)
+}
+
+// RegistryImageReference parses the given reference to an image within a registry (e.g. the image of a container of a
+// pod), which has no scheme (e.g. "registry:2" is the "registry" image).
+func RegistryImageReference(image string) (ImageReference, error) {
+	return newImageReference("registry", strings.TrimSpace(image))
+}
+
+func newImageReference(scheme, image string) (ImageReference, error) {
+	ref, err := name.ParseReference(image)
+	if err == nil && (!registryHost.MatchString(ref.Context().RegistryStr()) || !repositoryPath.MatchString(ref.Context().RepositoryStr())) {
+		err = fmt.Errorf("not an image within a registry")
+	}
+	if err != nil {
+		return ImageReference{}, fmt.Errorf("invalid image reference %q (only images within a registry can be scanned): %w", image, err)
+	}
+	return ImageReference{Scheme: scheme, Reference: ref}, nil
+}
+
+// HasDigest indicates if the reference is pinned to an image digest (e.g. "alpine@sha256:..."), where references by
+// tag may refer to another image at any time.
+func (r ImageReference) HasDigest() bool {
+	_, ok := r.Reference.(name.Digest)
+	return ok
+}
+
+// Input returns the reference as the input of Provide (e.g. "registry:alpine:3.14").
+func (r ImageReference) Input() string {
+	return r.Scheme + ":" + r.Reference.String()
+}
+
+// String returns the reference as given (without the scheme).
+func (r ImageReference) String() string {
+	return r.Reference.String()
+}
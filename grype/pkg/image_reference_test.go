@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImageReference(t *testing.T) {
+	const digest = "sha256:6845222dafaba46394bded167974eaca737ad7988b9a77decbfc2c686ba49480"
+	tests := []struct {
+		image     string
+		input     string
+		hasDigest bool
+		err       bool
+	}{
+		{image: "alpine:3.14", input: "registry:alpine:3.14"},
+		{image: "alpine", input: "registry:alpine"},
+		{image: "localhost:5000/org/app:1.0", input: "registry:localhost:5000/org/app:1.0"},
+		{image: "registry:ghcr.io/org/app@" + digest, input: "registry:ghcr.io/org/app@" + digest, hasDigest: true},
+		{image: "docker:alpine:3.14", input: "docker:alpine:3.14"},
+		{image: " alpine:3.14 ", input: "registry:alpine:3.14"},
+		// a scheme that is not of an image is the name of an image within the registry
+		{image: "sbom:latest", input: "registry:sbom:latest"},
+		// other sources are never read
+		{image: "dir:/", err: true},
+		{image: "file:/etc/passwd", err: true},
+		{image: "sbom:/tmp/sbom.json", err: true},
+		{image: "oci-dir:/tmp/image", err: true},
+		{image: "docker-archive:/tmp/image.tar", err: true},
+		{image: "/etc", err: true},
+		{image: "registry:", err: true},
+		{image: "", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.image, func(t *testing.T) {
+			ref, err := ParseImageReference(test.image)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.input, ref.Input())
+			assert.Equal(t, test.hasDigest, ref.HasDigest())
+		})
+	}
+}
+
+func TestRegistryImageReference(t *testing.T) {
+	tests := []struct {
+		image string
+		input string
+		err   bool
+	}{
+		{image: "alpine:3.14", input: "registry:alpine:3.14"},
+		// images are never read with a scheme
+		{image: "registry:2", input: "registry:registry:2"},
+		{image: "docker:20.10", input: "registry:docker:20.10"},
+		{image: "dir:/", err: true},
+		{image: "registry:ghcr.io/org/app", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.image, func(t *testing.T) {
+			ref, err := RegistryImageReference(test.image)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.input, ref.Input())
+		})
+	}
+}
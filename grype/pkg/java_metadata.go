@@ -5,4 +5,6 @@ type JavaMetadata struct {
 	PomArtifactID string
 	PomGroupID    string
 	ManifestName  string
+	Classifier    string // the maven classifier of the artifact (e.g. "jdk8" or "sources"), if known
+	ParentID      ID     // the package of the (e.g. shaded or uber) java archive that this package is bundled within, if any
 }
@@ -0,0 +1,175 @@
+package pkg
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/internal"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/pkg/cataloger/common/cpe"
+	"github.com/anchore/syft/syft/source"
+)
+
+// shadedJavaPackages returns a package for each maven artifact that was shaded into a java archive (i.e. has a
+// pom.properties within the archive) but that is not already one of the given packages. This covers uber jars without
+// a manifest and artifacts whose name is a prefix of another bundled artifact, which are otherwise not cataloged.
+func shadedJavaPackages(resolver source.FileResolver, packages []Package) []Package {
+	known := make(map[string]struct{})
+	for _, p := range packages {
+		if metadata, ok := p.Metadata.(JavaMetadata); ok {
+			known[shadedJavaKey(metadata.VirtualPath, p.Name, p.Version)] = struct{}{}
+		}
+	}
+
+	var result []Package
+	for _, p := range packages {
+		metadata, ok := p.Metadata.(JavaMetadata)
+		// note: nested archives (e.g. "app.jar:BOOT-INF/lib/dep.jar") cannot be read from the source directly
+		if !ok || metadata.ParentID != "" || strings.Contains(metadata.VirtualPath, ":") || len(p.Locations) == 0 {
+			continue
+		}
+
+		properties, err := archivePomProperties(resolver, p.Locations[0])
+		if err != nil {
+			log.Debugf("unable to read pom.properties from java archive %q: %+v", metadata.VirtualPath, err)
+			continue
+		}
+
+		for _, props := range properties {
+			key := shadedJavaKey(metadata.VirtualPath, props.ArtifactID, props.Version)
+			if _, exists := known[key]; exists {
+				continue
+			}
+			known[key] = struct{}{}
+			result = append(result, newShadedJavaPackage(p, metadata, props))
+		}
+	}
+	return result
+}
+
+// shadedJavaKey identifies an artifact within the (outermost) java archive it was found in.
+func shadedJavaKey(virtualPath, name, version string) string {
+	archive := strings.SplitN(virtualPath, ":", 2)[0]
+	return fmt.Sprintf("%s:%s@%s", archive, name, version)
+}
+
+func newShadedJavaPackage(parent Package, parentMetadata JavaMetadata, props pkg.PomProperties) Package {
+	virtualPath := parentMetadata.VirtualPath + ":" + props.ArtifactID
+	syftPackage := pkg.Package{
+		Name:         props.ArtifactID,
+		Version:      props.Version,
+		Locations:    parent.Locations,
+		Language:     pkg.Java,
+		Type:         props.PkgTypeIndicated(),
+		PURL:         fmt.Sprintf("pkg:maven/%s/%s@%s", props.GroupID, props.ArtifactID, props.Version),
+		MetadataType: pkg.JavaMetadataType,
+		Metadata: pkg.JavaMetadata{
+			VirtualPath:   virtualPath,
+			PomProperties: &props,
+		},
+	}
+	syftPackage.CPEs = cpe.Generate(syftPackage)
+
+	p := New(syftPackage)
+	p.ID = ID(fmt.Sprintf("%s:%s:%s@%s", parent.ID, props.GroupID, props.ArtifactID, props.Version))
+	metadata := p.Metadata.(JavaMetadata)
+	metadata.ParentID = parent.ID
+	p.Metadata = metadata
+	return p
+}
+
+// archivePomProperties returns the (complete) pom.properties within the java archive at the given location, ordered by
+// path within the archive.
+func archivePomProperties(resolver source.FileResolver, location source.Location) ([]pkg.PomProperties, error) {
+	reader, err := resolver.FileContentsByLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// note: reading a zip requires random access, which the source does not provide
+	f, err := ioutil.TempFile("", internal.ApplicationName+"-java-archive")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp java archive file: %w", err)
+	}
+	defer func() {
+		if err := os.Remove(f.Name()); err != nil {
+			log.Warnf("failed to remove temp java archive file: %+v", err)
+		}
+	}()
+	defer f.Close()
+
+	size, err := io.Copy(f, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy java archive to temp file: %w", err)
+	}
+
+	archive, err := zip.NewReader(f, size)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read java archive: %w", err)
+	}
+
+	var result []pkg.PomProperties
+	for _, entry := range archive.File {
+		if matched, _ := path.Match("META-INF/maven/*/*/pom.properties", entry.Name); !matched {
+			continue
+		}
+
+		props, err := readPomProperties(entry)
+		if err != nil {
+			log.Debugf("unable to read %q from java archive %q: %+v", entry.Name, location.RealPath, err)
+			continue
+		}
+		if props.GroupID == "" || props.ArtifactID == "" || props.Version == "" {
+			continue
+		}
+		result = append(result, props)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path < result[j].Path
+	})
+	return result, nil
+}
+
+// readPomProperties parses the (java properties formatted) pom.properties within a java archive.
+func readPomProperties(entry *zip.File) (pkg.PomProperties, error) {
+	contents, err := entry.Open()
+	if err != nil {
+		return pkg.PomProperties{}, err
+	}
+	defer contents.Close()
+
+	props := pkg.PomProperties{Path: entry.Name}
+	scanner := bufio.NewScanner(contents)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		idx := strings.IndexAny(line, "=:")
+		if idx < 0 {
+			continue
+		}
+		value := strings.TrimSpace(line[idx+1:])
+		switch strings.TrimSpace(line[:idx]) {
+		case "groupId":
+			props.GroupID = value
+		case "artifactId":
+			props.ArtifactID = value
+			props.Name = value
+		case "version":
+			props.Version = value
+		}
+	}
+	return props, scanner.Err()
+}
@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestShadedJavaPackages(t *testing.T) {
+	src, err := source.NewFromDirectory("test-fixtures/java-shaded")
+	require.NoError(t, err)
+	resolver, err := src.FileResolver(source.SquashedScope)
+	require.NoError(t, err)
+
+	locations, err := resolver.FilesByPath("app-all.jar")
+	require.NoError(t, err)
+	require.Len(t, locations, 1)
+
+	packages := []Package{
+		{
+			ID:        "app-id",
+			Name:      "app",
+			Version:   "1.0.0",
+			Locations: locations,
+			Type:      pkg.JavaPkg,
+			Metadata:  JavaMetadata{VirtualPath: "app-all.jar", PomGroupID: "com.example", PomArtifactID: "app"},
+		},
+		{
+			// already cataloged from the pom.properties within the archive
+			ID:        "log4j-api-id",
+			Name:      "log4j-api",
+			Version:   "2.14.1",
+			Locations: locations,
+			Type:      pkg.JavaPkg,
+			Metadata:  JavaMetadata{VirtualPath: "app-all.jar:log4j-api", PomGroupID: "org.apache.logging.log4j", PomArtifactID: "log4j-api", ParentID: "app-id"},
+		},
+	}
+
+	actual := shadedJavaPackages(resolver, packages)
+	require.Len(t, actual, 1)
+
+	p := actual[0]
+	assert.Equal(t, ID("app-id:org.apache.logging.log4j:log4j-core@2.14.1"), p.ID)
+	assert.Equal(t, "log4j-core", p.Name)
+	assert.Equal(t, "2.14.1", p.Version)
+	assert.Equal(t, pkg.JavaPkg, p.Type)
+	assert.Equal(t, pkg.Java, p.Language)
+	assert.Equal(t, locations, p.Locations)
+	assert.Equal(t, "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", p.PURL)
+	assert.Equal(t, JavaMetadata{
+		VirtualPath:   "app-all.jar:log4j-core",
+		PomArtifactID: "log4j-core",
+		PomGroupID:    "org.apache.logging.log4j",
+		ParentID:      "app-id",
+	}, p.Metadata)
+
+	var cpes []string
+	for _, c := range p.CPEs {
+		cpes = append(cpes, c.BindToFmtString())
+	}
+	assert.Contains(t, cpes, "cpe:2.3:a:apache:log4j:2.14.1:*:*:*:*:*:*:*")
+}
+
+func TestNew_JavaParent(t *testing.T) {
+	parent := pkg.Package{Name: "app", Version: "1.0.0", Type: pkg.JavaPkg}
+	parent.SetID()
+
+	p := New(pkg.Package{
+		Name:         "log4j-core",
+		Version:      "2.14.1",
+		Type:         pkg.JavaPkg,
+		MetadataType: pkg.JavaMetadataType,
+		Metadata: pkg.JavaMetadata{
+			VirtualPath:   "app.jar:log4j-core",
+			PomProperties: &pkg.PomProperties{GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-core", Version: "2.14.1"},
+			Parent:        &parent,
+		},
+	})
+
+	assert.Equal(t, ID(parent.ID()), p.Metadata.(JavaMetadata).ParentID)
+}
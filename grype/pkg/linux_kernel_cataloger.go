@@ -0,0 +1,181 @@
+package pkg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+const linuxKernelCatalogerName = "linux-kernel-cataloger"
+
+const (
+	// linuxKernelImageGlob matches installed kernel images (e.g. "/boot/vmlinuz-5.10.0-9-amd64")
+	linuxKernelImageGlob = "**/boot/vmlinuz-*"
+	// linuxKernelModulesGlob matches the list of built-in modules that is installed alongside the modules for each
+	// kernel release (e.g. "/lib/modules/5.10.0-9-amd64/modules.builtin")
+	linuxKernelModulesGlob = "**/lib/modules/*/modules.builtin"
+	// linuxKernelBootConfigGlob matches kernel build configurations (e.g. "/boot/config-5.10.0-9-amd64")
+	linuxKernelBootConfigGlob = "**/boot/config-*"
+	// linuxKernelModulesConfigGlob matches kernel build configurations installed with the modules (as on fedora)
+	linuxKernelModulesConfigGlob = "**/lib/modules/*/config"
+)
+
+// linuxKernelVersionPattern matches the upstream kernel version at the start of a kernel release
+// (e.g. "5.10.0" from "5.10.0-9-amd64" or "4.18.0" from "4.18.0-348.el8.x86_64")
+var linuxKernelVersionPattern = regexp.MustCompile(`^\d+\.\d+(\.\d+)?`)
+
+// linuxKernelConfigPattern matches enabled options within a kernel build configuration (e.g. "CONFIG_TIPC=m")
+var linuxKernelConfigPattern = regexp.MustCompile(`^(CONFIG_[A-Za-z0-9_]+)=(.+)$`)
+
+// linuxKernelCataloger finds installed linux kernels (which syft does not catalog), combining the kernel image,
+// modules, and build configuration found for each kernel release into a single package.
+type linuxKernelCataloger struct{}
+
+func newLinuxKernelCataloger() *linuxKernelCataloger {
+	return &linuxKernelCataloger{}
+}
+
+func (c *linuxKernelCataloger) Name() string {
+	return linuxKernelCatalogerName
+}
+
+type linuxKernel struct {
+	locations []source.Location
+	config    map[string]string
+}
+
+func (c *linuxKernelCataloger) Catalog(resolver source.FileResolver) ([]pkg.Package, []artifact.Relationship, error) {
+	kernels := make(map[string]*linuxKernel)
+	kernel := func(release string) *linuxKernel {
+		if _, ok := kernels[release]; !ok {
+			kernels[release] = &linuxKernel{}
+		}
+		return kernels[release]
+	}
+
+	for _, glob := range []string{linuxKernelImageGlob, linuxKernelModulesGlob} {
+		locations, err := resolver.FilesByGlob(glob)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to find linux kernel files by glob=%q: %w", glob, err)
+		}
+		for _, location := range locations {
+			if release := linuxKernelRelease(location.RealPath); release != "" {
+				k := kernel(release)
+				k.locations = append(k.locations, location)
+			}
+		}
+	}
+
+	for _, glob := range []string{linuxKernelBootConfigGlob, linuxKernelModulesConfigGlob} {
+		locations, err := resolver.FilesByGlob(glob)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to find linux kernel config files by glob=%q: %w", glob, err)
+		}
+		for _, location := range locations {
+			release := linuxKernelRelease(location.RealPath)
+			k, ok := kernels[release]
+			if !ok || k.config != nil {
+				// only consider configurations for kernels that are installed
+				continue
+			}
+			config, err := readLinuxKernelConfig(resolver, location)
+			if err != nil {
+				log.Warnf("unable to read linux kernel config at location=%+v: %+v", location, err)
+				continue
+			}
+			k.config = config
+			k.locations = append(k.locations, location)
+		}
+	}
+
+	var releases []string
+	for release := range kernels {
+		releases = append(releases, release)
+	}
+	sort.Strings(releases)
+
+	var packages []pkg.Package
+	for _, release := range releases {
+		k := kernels[release]
+		version := linuxKernelVersionPattern.FindString(release)
+		p := pkg.Package{
+			Name:         "linux-kernel",
+			Version:      version,
+			FoundBy:      linuxKernelCatalogerName,
+			Locations:    k.locations,
+			Licenses:     []string{"GPL-2.0-only"},
+			Type:         LinuxKernelPkg,
+			CPEs:         []pkg.CPE{linuxKernelCPE(version)},
+			PURL:         fmt.Sprintf("pkg:generic/linux-kernel@%s", version),
+			MetadataType: LinuxKernelMetadataType,
+			Metadata: LinuxKernelMetadata{
+				Release:       release,
+				ConfigOptions: k.config,
+			},
+		}
+		p.SetID()
+		packages = append(packages, p)
+	}
+	return packages, nil, nil
+}
+
+// linuxKernelRelease returns the kernel release described by the given kernel image, modules, or config path, or an
+// empty string if the path does not describe a kernel release (e.g. "/boot/vmlinuz-rescue-...").
+func linuxKernelRelease(p string) string {
+	var release string
+	switch base := path.Base(p); {
+	case strings.HasPrefix(base, "vmlinuz-"):
+		release = strings.TrimPrefix(base, "vmlinuz-")
+	case strings.HasPrefix(base, "config-") && path.Base(path.Dir(p)) == "boot":
+		release = strings.TrimPrefix(base, "config-")
+	default:
+		// the modules directory is named after the release (e.g. "/lib/modules/5.10.0-9-amd64/modules.builtin")
+		release = path.Base(path.Dir(p))
+	}
+	if !linuxKernelVersionPattern.MatchString(release) {
+		return ""
+	}
+	return release
+}
+
+func linuxKernelCPE(version string) pkg.CPE {
+	return pkg.MustCPE(fmt.Sprintf("cpe:2.3:o:linux:linux_kernel:%s:*:*:*:*:*:*:*", version))
+}
+
+func readLinuxKernelConfig(resolver source.FileResolver, location source.Location) (map[string]string, error) {
+	reader, err := resolver.FileContentsByLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			log.Warnf("unable to close linux kernel config at location=%+v: %+v", location, err)
+		}
+	}()
+
+	return parseLinuxKernelConfig(reader)
+}
+
+// parseLinuxKernelConfig returns the enabled options from a kernel build configuration (options that are not set are
+// commented out, e.g. "# CONFIG_TIPC is not set").
+func parseLinuxKernelConfig(reader io.Reader) (map[string]string, error) {
+	config := make(map[string]string)
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		match := linuxKernelConfigPattern.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		config[match[1]] = strings.Trim(match[2], `"`)
+	}
+	return config, scanner.Err()
+}
@@ -0,0 +1,89 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestLinuxKernelCataloger(t *testing.T) {
+	src, err := source.NewFromDirectory("test-fixtures/linux-kernel")
+	require.NoError(t, err)
+
+	resolver, err := src.FileResolver(source.SquashedScope)
+	require.NoError(t, err)
+
+	packages, _, err := newLinuxKernelCataloger().Catalog(resolver)
+	require.NoError(t, err)
+
+	// note: the rescue kernel image is not a kernel release and is ignored
+	require.Len(t, packages, 2)
+
+	// a kernel with only modules installed (and no known build configuration)
+	p := packages[0]
+	assert.Equal(t, "linux-kernel", p.Name)
+	assert.Equal(t, "4.19.0", p.Version)
+	assert.Equal(t, LinuxKernelPkg, p.Type)
+	assert.Equal(t, "pkg:generic/linux-kernel@4.19.0", p.PURL)
+	require.Len(t, p.CPEs, 1)
+	assert.Equal(t, "cpe:2.3:o:linux:linux_kernel:4.19.0:*:*:*:*:*:*:*", p.CPEs[0].BindToFmtString())
+	assert.Equal(t, LinuxKernelMetadataType, p.MetadataType)
+	metadata, ok := p.Metadata.(LinuxKernelMetadata)
+	require.True(t, ok)
+	assert.Equal(t, "4.19.0-18-amd64", metadata.Release)
+	assert.False(t, metadata.HasConfig())
+	assert.True(t, metadata.Enabled("CONFIG_TIPC"))
+
+	// a kernel with an image, modules, and build configuration installed
+	p = packages[1]
+	assert.Equal(t, "5.10.0", p.Version)
+	assert.Len(t, p.Locations, 3)
+	metadata, ok = p.Metadata.(LinuxKernelMetadata)
+	require.True(t, ok)
+	assert.Equal(t, "5.10.0-9-amd64", metadata.Release)
+	assert.True(t, metadata.HasConfig())
+	assert.True(t, metadata.Enabled("CONFIG_PACKET"))
+	assert.True(t, metadata.Enabled("CONFIG_NETFILTER_XTABLES"))
+	assert.False(t, metadata.Enabled("CONFIG_TIPC"))
+	assert.False(t, metadata.Enabled("CONFIG_NF_TABLES"))
+}
+
+func TestLinuxKernelRelease(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "/boot/vmlinuz-5.10.0-9-amd64", expected: "5.10.0-9-amd64"},
+		{path: "/boot/vmlinuz-4.18.0-348.el8.x86_64", expected: "4.18.0-348.el8.x86_64"},
+		{path: "/boot/vmlinuz-0-rescue-4b4b1a0c8f9e4d2b", expected: ""},
+		{path: "/boot/vmlinuz-rescue-4b4b1a0c8f9e4d2b", expected: ""},
+		{path: "/boot/config-5.10.0-9-amd64", expected: "5.10.0-9-amd64"},
+		{path: "/lib/modules/5.15.0-1019-aws/modules.builtin", expected: "5.15.0-1019-aws"},
+		{path: "/lib/modules/5.14.10-300.fc35.x86_64/config", expected: "5.14.10-300.fc35.x86_64"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			assert.Equal(t, test.expected, linuxKernelRelease(test.path))
+		})
+	}
+}
+
+func TestParseLinuxKernelConfig(t *testing.T) {
+	config, err := parseLinuxKernelConfig(strings.NewReader(`
+# CONFIG_TIPC is not set
+CONFIG_PACKET=y
+CONFIG_NF_TABLES=m
+CONFIG_DEFAULT_HOSTNAME="(none)"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"CONFIG_PACKET":           "y",
+		"CONFIG_NF_TABLES":        "m",
+		"CONFIG_DEFAULT_HOSTNAME": "(none)",
+	}, config)
+}
@@ -0,0 +1,23 @@
+package pkg
+
+type LinuxKernelMetadata struct {
+	Release string // the full kernel release, as reported by "uname -r" (e.g. "5.10.0-9-amd64")
+	// ConfigOptions are the options the kernel was built with that are enabled (either built in or as a module, e.g.
+	// "CONFIG_TIPC": "m"), or nil if the kernel build configuration is unknown.
+	ConfigOptions map[string]string `json:"-"`
+}
+
+// HasConfig indicates if the kernel build configuration is known.
+func (m LinuxKernelMetadata) HasConfig() bool {
+	return m.ConfigOptions != nil
+}
+
+// Enabled indicates if the given config option (e.g. "CONFIG_TIPC") is either built in or built as a module. Note
+// that when the kernel build configuration is unknown all options are considered to be enabled.
+func (m LinuxKernelMetadata) Enabled(option string) bool {
+	if !m.HasConfig() {
+		return true
+	}
+	value := m.ConfigOptions[option]
+	return value != "" && value != "n"
+}
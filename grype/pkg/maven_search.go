@@ -0,0 +1,208 @@
+package pkg
+
+import (
+	"crypto/sha1" // nolint:gosec // maven central indexes archives by SHA-1
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/grype/metrics"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/source"
+)
+
+const DefaultMavenSearchURL = "https://search.maven.org/solrsearch/select"
+
+// MavenSearchConfig controls the (opt-in) lookup of java archives that have no maven coordinates (e.g. renamed or
+// repackaged jars without a pom.properties) against Maven Central by the SHA-1 digest of the archive.
+type MavenSearchConfig struct {
+	Enabled  bool
+	BaseURL  string
+	Timeout  time.Duration
+	CacheDir string // where lookup results are kept between runs (no results are kept when empty)
+}
+
+type mavenCoordinates struct {
+	GroupID    string `json:"groupId"`
+	ArtifactID string `json:"artifactId"`
+	Version    string `json:"version"`
+}
+
+type mavenSearchResponse struct {
+	Response struct {
+		Docs []struct {
+			GroupID    string `json:"g"`
+			ArtifactID string `json:"a"`
+			Version    string `json:"v"`
+		} `json:"docs"`
+	} `json:"response"`
+}
+
+type mavenSearcher struct {
+	config MavenSearchConfig
+	client *http.Client
+	// results by digest, where nil indicates the archive is not known to maven central
+	results map[string]*mavenCoordinates
+}
+
+func newMavenSearcher(config MavenSearchConfig) *mavenSearcher {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultMavenSearchURL
+	}
+	return &mavenSearcher{
+		config:  config,
+		client:  &http.Client{Timeout: config.Timeout},
+		results: make(map[string]*mavenCoordinates),
+	}
+}
+
+// searchBySha1 returns the maven coordinates of the archive with the given SHA-1 digest, or nil when the archive is not
+// known to maven central.
+func (s *mavenSearcher) searchBySha1(digest string) (*mavenCoordinates, error) {
+	if result, ok := s.results[digest]; ok {
+		return result, nil
+	}
+
+	result, ok := s.readCache(digest)
+	if s.config.CacheDir != "" {
+		metrics.ObserveCache(metrics.MavenSearchCache, ok)
+	}
+	if !ok {
+		var err error
+		result, err = s.query(digest)
+		if err != nil {
+			return nil, err
+		}
+		s.writeCache(digest, result)
+	}
+
+	s.results[digest] = result
+	return result, nil
+}
+
+func (s *mavenSearcher) query(digest string) (*mavenCoordinates, error) {
+	requestURL := fmt.Sprintf("%s?q=%s&rows=1&wt=json", s.config.BaseURL, url.QueryEscape(fmt.Sprintf("1:%q", digest)))
+	resp, err := s.client.Get(requestURL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to search maven central: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to search maven central: HTTP %d", resp.StatusCode)
+	}
+
+	var response mavenSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("unable to parse maven central search response: %w", err)
+	}
+
+	if len(response.Response.Docs) == 0 {
+		return nil, nil
+	}
+	doc := response.Response.Docs[0]
+	return &mavenCoordinates{
+		GroupID:    doc.GroupID,
+		ArtifactID: doc.ArtifactID,
+		Version:    doc.Version,
+	}, nil
+}
+
+func (s *mavenSearcher) cachePath(digest string) string {
+	return filepath.Join(s.config.CacheDir, digest+".json")
+}
+
+func (s *mavenSearcher) readCache(digest string) (*mavenCoordinates, bool) {
+	if s.config.CacheDir == "" {
+		return nil, false
+	}
+	contents, err := ioutil.ReadFile(s.cachePath(digest))
+	if err != nil {
+		return nil, false
+	}
+	var result *mavenCoordinates
+	if err := json.Unmarshal(contents, &result); err != nil {
+		log.Debugf("ignoring invalid maven search cache entry for %s: %+v", digest, err)
+		return nil, false
+	}
+	return result, true
+}
+
+func (s *mavenSearcher) writeCache(digest string, result *mavenCoordinates) {
+	if s.config.CacheDir == "" {
+		return
+	}
+	contents, err := json.Marshal(result)
+	if err != nil {
+		log.Debugf("unable to encode maven search cache entry for %s: %+v", digest, err)
+		return
+	}
+	if err := os.MkdirAll(s.config.CacheDir, 0755); err != nil {
+		log.Debugf("unable to create maven search cache dir: %+v", err)
+		return
+	}
+	if err := ioutil.WriteFile(s.cachePath(digest), contents, 0600); err != nil {
+		log.Debugf("unable to write maven search cache entry for %s: %+v", digest, err)
+	}
+}
+
+// addMavenCoordinates sets the maven coordinates of java archives that have none (and the version when unknown) by
+// searching for the digest of each archive on maven central.
+func addMavenCoordinates(resolver source.FileResolver, searcher *mavenSearcher, packages []Package) {
+	for i, p := range packages {
+		metadata, ok := p.Metadata.(JavaMetadata)
+		if !ok || (metadata.PomGroupID != "" && metadata.PomArtifactID != "") || len(p.Locations) == 0 {
+			continue
+		}
+		// nested archives (e.g. "app.jar:BOOT-INF/lib/dep.jar") cannot be read from the source directly
+		if strings.Contains(metadata.VirtualPath, ":") {
+			continue
+		}
+
+		digest, err := sha1Digest(resolver, p.Locations[0])
+		if err != nil {
+			log.Debugf("unable to determine digest of java archive %q: %+v", metadata.VirtualPath, err)
+			continue
+		}
+
+		coordinates, err := searcher.searchBySha1(digest)
+		if err != nil {
+			// the remaining lookups would most likely fail the same way (e.g. timeouts without network access)
+			log.Warnf("skipping maven central search for the remaining java archives: %+v", err)
+			return
+		}
+		if coordinates == nil {
+			continue
+		}
+
+		log.Debugf("found maven coordinates %s:%s:%s for java archive %q", coordinates.GroupID, coordinates.ArtifactID, coordinates.Version, metadata.VirtualPath)
+		metadata.PomGroupID = coordinates.GroupID
+		metadata.PomArtifactID = coordinates.ArtifactID
+		packages[i].Metadata = metadata
+		if p.Version == "" {
+			packages[i].Version = coordinates.Version
+		}
+	}
+}
+
+func sha1Digest(resolver source.FileResolver, location source.Location) (string, error) {
+	reader, err := resolver.FileContentsByLocation(location)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	hasher := sha1.New() // nolint:gosec // maven central indexes archives by SHA-1
+	if _, err := io.Copy(hasher, reader); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
@@ -0,0 +1,162 @@
+package pkg
+
+import (
+	"crypto/sha1" // nolint:gosec // maven central indexes archives by SHA-1
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft/source"
+)
+
+const mavenSearchDigest = "0ba2a8ae94c3b2bc6e5b9e1c0a5f6a4d4f0c1e2d"
+
+// newMavenSearchServer returns a server that knows the archive with the given digest as commons-text 1.9.
+func newMavenSearchServer(t *testing.T, digest string, requests *int) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		assert.Equal(t, "json", r.URL.Query().Get("wt"))
+		if r.URL.Query().Get("q") != fmt.Sprintf("1:%q", digest) {
+			fmt.Fprint(w, `{"response":{"numFound":0,"docs":[]}}`)
+			return
+		}
+		fmt.Fprint(w, `{"response":{"numFound":1,"docs":[{"id":"org.apache.commons:commons-text:1.9","g":"org.apache.commons","a":"commons-text","v":"1.9"}]}}`)
+	}))
+}
+
+func TestMavenSearcher_SearchBySha1(t *testing.T) {
+	requests := 0
+	server := newMavenSearchServer(t, mavenSearchDigest, &requests)
+	defer server.Close()
+
+	cacheDir := t.TempDir()
+	searcher := newMavenSearcher(MavenSearchConfig{Enabled: true, BaseURL: server.URL, Timeout: time.Second, CacheDir: cacheDir})
+
+	expected := &mavenCoordinates{GroupID: "org.apache.commons", ArtifactID: "commons-text", Version: "1.9"}
+
+	actual, err := searcher.searchBySha1(mavenSearchDigest)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	actual, err = searcher.searchBySha1("da39a3ee5e6b4b0d3255bfef95601890afd80709")
+	require.NoError(t, err)
+	assert.Nil(t, actual)
+
+	// results are remembered within the searcher...
+	_, err = searcher.searchBySha1(mavenSearchDigest)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+
+	// ...and between searchers that share a cache dir (including archives that are not known)
+	searcher = newMavenSearcher(MavenSearchConfig{Enabled: true, BaseURL: server.URL, Timeout: time.Second, CacheDir: cacheDir})
+	actual, err = searcher.searchBySha1(mavenSearchDigest)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+	actual, err = searcher.searchBySha1("da39a3ee5e6b4b0d3255bfef95601890afd80709")
+	require.NoError(t, err)
+	assert.Nil(t, actual)
+	assert.Equal(t, 2, requests)
+}
+
+func TestMavenSearcher_SearchBySha1_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "server error",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			},
+		},
+		{
+			name: "invalid response",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `<html>`)
+			},
+		},
+		{
+			name: "timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(200 * time.Millisecond)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := httptest.NewServer(test.handler)
+			defer server.Close()
+
+			cacheDir := t.TempDir()
+			searcher := newMavenSearcher(MavenSearchConfig{Enabled: true, BaseURL: server.URL, Timeout: 50 * time.Millisecond, CacheDir: cacheDir})
+
+			_, err := searcher.searchBySha1(mavenSearchDigest)
+			require.Error(t, err)
+
+			// failures must not be cached
+			entries, err := ioutil.ReadDir(cacheDir)
+			require.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestAddMavenCoordinates(t *testing.T) {
+	contents, err := ioutil.ReadFile("test-fixtures/maven/renamed.jar")
+	require.NoError(t, err)
+	digest := sha1.Sum(contents) // nolint:gosec // maven central indexes archives by SHA-1
+
+	requests := 0
+	server := newMavenSearchServer(t, hex.EncodeToString(digest[:]), &requests)
+	defer server.Close()
+
+	src, err := source.NewFromDirectory("test-fixtures/maven")
+	require.NoError(t, err)
+	resolver, err := src.FileResolver(source.SquashedScope)
+	require.NoError(t, err)
+
+	locations, err := resolver.FilesByPath("renamed.jar")
+	require.NoError(t, err)
+	require.Len(t, locations, 1)
+
+	packages := []Package{
+		{
+			Name:      "renamed",
+			Locations: locations,
+			Metadata:  JavaMetadata{VirtualPath: "renamed.jar"},
+		},
+		{
+			// already has maven coordinates
+			Name:      "commons-lang3",
+			Version:   "3.12.0",
+			Locations: locations,
+			Metadata:  JavaMetadata{VirtualPath: "renamed.jar", PomGroupID: "org.apache.commons", PomArtifactID: "commons-lang3"},
+		},
+		{
+			// nested archives cannot be read directly
+			Name:      "nested",
+			Locations: locations,
+			Metadata:  JavaMetadata{VirtualPath: "renamed.jar:lib/nested.jar"},
+		},
+	}
+
+	searcher := newMavenSearcher(MavenSearchConfig{Enabled: true, BaseURL: server.URL, Timeout: time.Second})
+	addMavenCoordinates(resolver, searcher, packages)
+
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, "1.9", packages[0].Version)
+	assert.Equal(t, JavaMetadata{VirtualPath: "renamed.jar", PomGroupID: "org.apache.commons", PomArtifactID: "commons-text"}, packages[0].Metadata)
+	assert.Equal(t, "3.12.0", packages[1].Version)
+	assert.Equal(t, "commons-lang3", packages[1].Metadata.(JavaMetadata).PomArtifactID)
+	assert.Equal(t, "", packages[2].Version)
+	assert.Equal(t, JavaMetadata{VirtualPath: "renamed.jar:lib/nested.jar"}, packages[2].Metadata)
+}
@@ -0,0 +1,102 @@
+package pkg
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// rpm only allows alphanumerics and "._+~^" within versions and releases (notably a dash is not allowed, which is
+	// why upstream versions such as "1.0-rc1" are converted to "1.0~rc1")
+	rpmVersionPattern = regexp.MustCompile(`^[a-zA-Z0-9._+~^]+$`)
+	rpmArchPattern    = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+	rpmEpochPattern   = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// nevra is the name, epoch, version, release and architecture that identify an rpm.
+type nevra struct {
+	name    string
+	epoch   *int
+	version string
+	release string
+	arch    string
+}
+
+// parseNEVRA strictly parses an rpm file name (e.g. "util-linux-ng-2.17.2-12.28.el6_9.2.src.rpm") or NEVRA string.
+// The epoch may either prefix the name ("1:bash-4.4.19-14.el8.x86_64") or the version ("bash-1:4.4.19-14.el8.x86_64").
+func parseNEVRA(value string) (nevra, error) {
+	remaining := strings.TrimSuffix(value, ".rpm")
+
+	archIdx := strings.LastIndex(remaining, ".")
+	if archIdx < 0 {
+		return nevra{}, fmt.Errorf("no architecture in rpm=%q", value)
+	}
+	result := nevra{arch: remaining[archIdx+1:]}
+	remaining = remaining[:archIdx]
+	if !rpmArchPattern.MatchString(result.arch) {
+		return nevra{}, fmt.Errorf("invalid architecture=%q in rpm=%q", result.arch, value)
+	}
+
+	releaseIdx := strings.LastIndex(remaining, "-")
+	if releaseIdx < 0 {
+		return nevra{}, fmt.Errorf("no release in rpm=%q", value)
+	}
+	result.release = remaining[releaseIdx+1:]
+	remaining = remaining[:releaseIdx]
+	if !rpmVersionPattern.MatchString(result.release) {
+		return nevra{}, fmt.Errorf("invalid release=%q in rpm=%q", result.release, value)
+	}
+
+	versionIdx := strings.LastIndex(remaining, "-")
+	if versionIdx < 0 {
+		return nevra{}, fmt.Errorf("no version in rpm=%q", value)
+	}
+	result.name = remaining[:versionIdx]
+	result.version = remaining[versionIdx+1:]
+
+	var epoch *string
+	if fields := strings.SplitN(result.version, ":", 2); len(fields) == 2 {
+		epoch, result.version = &fields[0], fields[1]
+	}
+	if fields := strings.SplitN(result.name, ":", 2); len(fields) == 2 {
+		if epoch != nil {
+			return nevra{}, fmt.Errorf("multiple epochs in rpm=%q", value)
+		}
+		epoch, result.name = &fields[0], fields[1]
+	}
+
+	if !rpmVersionPattern.MatchString(result.version) {
+		return nevra{}, fmt.Errorf("invalid version=%q in rpm=%q", result.version, value)
+	}
+	if result.name == "" || strings.ContainsAny(result.name, ": \t") {
+		return nevra{}, fmt.Errorf("invalid name=%q in rpm=%q", result.name, value)
+	}
+
+	if epoch != nil {
+		if !rpmEpochPattern.MatchString(*epoch) {
+			return nevra{}, fmt.Errorf("invalid epoch=%q in rpm=%q", *epoch, value)
+		}
+		e, err := strconv.Atoi(*epoch)
+		if err != nil {
+			return nevra{}, fmt.Errorf("invalid epoch=%q in rpm=%q: %w", *epoch, value, err)
+		}
+		result.epoch = &e
+	}
+
+	return result, nil
+}
+
+// versionRelease returns the version and release of the rpm, including the epoch when known (e.g. "4:5.26.3-419.el8").
+func (n nevra) versionRelease() string {
+	if n.epoch != nil {
+		return fmt.Sprintf("%d:%s-%s", *n.epoch, n.version, n.release)
+	}
+	return fmt.Sprintf("%s-%s", n.version, n.release)
+}
+
+// String returns the NEVRA in the "name-[epoch:]version-release.arch" form.
+func (n nevra) String() string {
+	return fmt.Sprintf("%s-%s.%s", n.name, n.versionRelease(), n.arch)
+}
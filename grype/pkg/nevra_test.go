@@ -0,0 +1,173 @@
+package pkg
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNEVRA(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected nevra
+		wantErr  require.ErrorAssertionFunc
+	}{
+		{
+			value:    "util-linux-ng-2.17.2-12.28.el6_9.2.src.rpm",
+			expected: nevra{name: "util-linux-ng", version: "2.17.2", release: "12.28.el6_9.2", arch: "src"},
+		},
+		{
+			value:    "bash-4.4.19-14.el8.x86_64",
+			expected: nevra{name: "bash", version: "4.4.19", release: "14.el8", arch: "x86_64"},
+		},
+		{
+			value:    "perl-4:5.26.3-419.el8.src.rpm",
+			expected: nevra{name: "perl", epoch: intRef(4), version: "5.26.3", release: "419.el8", arch: "src"},
+		},
+		{
+			value:    "4:perl-5.26.3-419.el8.src.rpm",
+			expected: nevra{name: "perl", epoch: intRef(4), version: "5.26.3", release: "419.el8", arch: "src"},
+		},
+		{
+			// an upstream version of "3.0.0-beta1" is converted by rpm to "3.0.0~beta1"
+			value:    "openssl-3.0.0~beta1-1.fc35.src.rpm",
+			expected: nevra{name: "openssl", version: "3.0.0~beta1", release: "1.fc35", arch: "src"},
+		},
+		{
+			value:    "python3-pip-21.3.1^20211215git-2.fc36.noarch.rpm",
+			expected: nevra{name: "python3-pip", version: "21.3.1^20211215git", release: "2.fc36", arch: "noarch"},
+		},
+		{
+			value:   "src-rpm-info",
+			wantErr: require.Error,
+		},
+		{
+			value:   "sqlite-:3.26.0-6.el8.src.rpm",
+			wantErr: require.Error,
+		},
+		{
+			value:   "sqlite-6.el8.src.rpm",
+			wantErr: require.Error,
+		},
+		{
+			value:   "-3.26.0-6.el8.src.rpm",
+			wantErr: require.Error,
+		},
+		{
+			value:   "1:sqlite-2:3.26.0-6.el8.src.rpm",
+			wantErr: require.Error,
+		},
+		{
+			value:   "sqlite-a:3.26.0-6.el8.src.rpm",
+			wantErr: require.Error,
+		},
+		{
+			value:   "sqlite-3.26.0-6.el8.8.rpm",
+			wantErr: require.Error,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			if test.wantErr == nil {
+				test.wantErr = require.NoError
+			}
+			actual, err := parseNEVRA(test.value)
+			test.wantErr(t, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+// randomNEVRA generates valid rpm identities for property based tests.
+type randomNEVRA struct {
+	nevra
+}
+
+func (randomNEVRA) Generate(r *rand.Rand, _ int) reflect.Value {
+	const (
+		nameChars    = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789._+-"
+		versionChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789._+~^"
+	)
+	str := func(chars string, min, max int) string {
+		var sb strings.Builder
+		for i := 0; i < min+r.Intn(max-min+1); i++ {
+			sb.WriteByte(chars[r.Intn(len(chars))])
+		}
+		return sb.String()
+	}
+
+	n := nevra{
+		// names may have dashes, but cannot start with one (otherwise the name would be empty)
+		name:    str(nameChars[:62], 1, 1) + str(nameChars, 0, 20),
+		version: str(versionChars, 1, 12),
+		release: str(versionChars, 1, 12),
+		arch:    str(nameChars[:52], 1, 1) + str(nameChars[:62]+"_", 0, 6),
+	}
+	if r.Intn(2) == 0 {
+		epoch := r.Intn(100)
+		n.epoch = &epoch
+	}
+	return reflect.ValueOf(randomNEVRA{n})
+}
+
+func TestParseNEVRA_RoundTrip(t *testing.T) {
+	roundTrip := func(n randomNEVRA, withSuffix bool) bool {
+		value := n.String()
+		if withSuffix {
+			value += ".rpm"
+		}
+		actual, err := parseNEVRA(value)
+		if err != nil {
+			t.Logf("unable to parse %q: %+v", value, err)
+			return false
+		}
+		return reflect.DeepEqual(n.nevra, actual)
+	}
+	require.NoError(t, quick.Check(roundTrip, nil))
+}
+
+func TestParseNEVRA_EpochPrefix(t *testing.T) {
+	// the epoch may prefix the name instead of the version without changing the meaning
+	epochPrefix := func(n randomNEVRA) bool {
+		if n.epoch == nil {
+			return true
+		}
+		value := fmt.Sprintf("%d:%s-%s-%s.%s", *n.epoch, n.name, n.version, n.release, n.arch)
+		actual, err := parseNEVRA(value)
+		return err == nil && reflect.DeepEqual(n.nevra, actual)
+	}
+	require.NoError(t, quick.Check(epochPrefix, nil))
+}
+
+func TestParseNEVRA_NeverMisparses(t *testing.T) {
+	// any value that is accepted must be fully described by the parsed result (nothing is silently dropped or moved).
+	// note: epochs are excluded since they may be given in more than one position (see TestParseNEVRA_EpochPrefix)
+	noMisparse := func(value string) bool {
+		actual, err := parseNEVRA(value)
+		if err != nil {
+			return true
+		}
+		return actual.String() == strings.TrimSuffix(value, ".rpm")
+	}
+	config := &quick.Config{
+		Values: func(values []reflect.Value, r *rand.Rand) {
+			const chars = "ab1-._~"
+			var sb strings.Builder
+			for i := 0; i < r.Intn(24); i++ {
+				sb.WriteByte(chars[r.Intn(len(chars))])
+			}
+			values[0] = reflect.ValueOf(sb.String())
+		},
+		MaxCount: 5000,
+	}
+	require.NoError(t, quick.Check(noMisparse, config))
+}
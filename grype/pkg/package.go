@@ -2,6 +2,7 @@ package pkg
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/anchore/grype/internal/log"
 	"github.com/anchore/syft/syft/pkg"
@@ -19,60 +20,30 @@ type Package struct {
 	Locations []source.Location // the locations that lead to the discovery of this package (note: this is not necessarily the locations that make up this package)
 	Language  pkg.Language      // the language ecosystem this package belongs to (e.g. JavaScript, Python, etc)
 	Licenses  []string
-	Type      pkg.Type    // the package type (e.g. Npm, Yarn, Python, Rpm, Deb, etc)
-	CPEs      []pkg.CPE   // all possible Common Platform Enumerators
-	PURL      string      // the Package URL (see https://github.com/package-url/purl-spec)
-	Metadata  interface{} // This is NOT the syft metadata! Only the select data needed for vulnerability matching
+	Type      pkg.Type          // the package type (e.g. Npm, Yarn, Python, Rpm, Deb, etc)
+	Arch      string            // the architecture the package was built for (e.g. x86_64, aarch64, noarch), if known
+	CPEs      []pkg.CPE         // all possible Common Platform Enumerators
+	PURL      string            // the Package URL (see https://github.com/package-url/purl-spec)
+	Upstreams []UpstreamPackage // the packages this package was built from (e.g. the source package of a dpkg binary package)
+	Layer     *Layer            // the image layer that introduced this package (only for packages within an image)
+	Scope     string            // the dependency scope of the package (e.g. "development"), when it is only needed to develop or test a project
+	Metadata  interface{}       // This is NOT the syft metadata! Only the select data needed for vulnerability matching
 }
 
 func New(p pkg.Package) Package {
-	var metadata interface{}
-
-	switch p.MetadataType {
-	case pkg.DpkgMetadataType:
-		if value, ok := p.Metadata.(pkg.DpkgMetadata); ok {
-			metadata = DpkgMetadata{Source: value.Source}
-		} else {
-			log.Warnf("unable to extract DPKG metadata for %s", p)
-		}
-	case pkg.RpmdbMetadataType:
-		if value, ok := p.Metadata.(pkg.RpmdbMetadata); ok {
-			metadata = RpmdbMetadata{SourceRpm: value.SourceRpm, Epoch: value.Epoch}
-		} else {
-			log.Warnf("unable to extract RPM metadata for %s", p)
-		}
-	case pkg.JavaMetadataType:
-		if value, ok := p.Metadata.(pkg.JavaMetadata); ok {
-			var artifact, group, name string
-			if value.PomProperties != nil {
-				artifact = value.PomProperties.ArtifactID
-				group = value.PomProperties.GroupID
-			}
-			if value.Manifest != nil {
-				if n, ok := value.Manifest.Main["Name"]; ok {
-					name = n
-				}
-			}
-
-			metadata = JavaMetadata{
-				VirtualPath:   value.VirtualPath,
-				PomArtifactID: artifact,
-				PomGroupID:    group,
-				ManifestName:  name,
+	// packages from ecosystems that syft does not catalog may still be described within an SBOM, in which case the
+	// package URL is the most reliable indication of the ecosystem
+	if p.Type == "" || p.Type == pkg.UnknownPkg {
+		if purlType, purlLanguage := typeFromPURL(p.PURL); purlType != "" {
+			p.Type = purlType
+			if p.Language == "" || p.Language == pkg.UnknownLanguage {
+				p.Language = purlLanguage
 			}
-		} else {
-			log.Warnf("unable to extract Java metadata for %s", p)
-		}
-	case pkg.ApkMetadataType:
-		if value, ok := p.Metadata.(pkg.ApkMetadata); ok {
-			metadata = ApkMetadata{
-				OriginPackage: value.OriginPackage,
-			}
-		} else {
-			log.Warnf("unable to extract APK metadata for %s", p)
 		}
 	}
 
+	metadata, upstreams := dataFromPkg(p)
+
 	return Package{
 		ID:        ID(p.ID()),
 		Name:      p.Name,
@@ -81,18 +52,39 @@ func New(p pkg.Package) Package {
 		Licenses:  p.Licenses,
 		Language:  p.Language,
 		Type:      p.Type,
+		Arch:      archFromPkg(p),
 		CPEs:      p.CPEs,
 		PURL:      p.PURL,
+		Upstreams: upstreams,
 		Metadata:  metadata,
 	}
 }
 
+// CatalogConfig controls how the packages of a catalog are converted.
+type CatalogConfig struct {
+	// Deduplicate merges packages with the same package URL (and metadata) that were found at several locations into
+	// one package, instead of keeping one package for each location the package was found at
+	Deduplicate bool
+}
+
+// FromCatalog converts the packages of the given catalog.
 func FromCatalog(catalog *pkg.Catalog) []Package {
+	return FromCatalogWithConfig(catalog, CatalogConfig{})
+}
+
+// FromCatalogWithConfig converts the packages of the given catalog according to the given configuration.
+func FromCatalogWithConfig(catalog *pkg.Catalog, config CatalogConfig) []Package {
 	result := make([]Package, 0, catalog.PackageCount())
 	for _, p := range catalog.Sorted() {
 		result = append(result, New(p))
 	}
-	return result
+	if config.Deduplicate {
+		result = deduplicateByPURL(result)
+	}
+	if invalid := undecomposableSourceRpms(result); len(invalid) > 0 {
+		log.Warnf("unable to extract name and version from the source RPM of %d packages: %s", len(invalid), strings.Join(invalid, ", "))
+	}
+	return append(result, goStdlibPackages(result)...)
 }
 
 // Stringer to represent a package.
@@ -108,3 +100,327 @@ func ByID(id ID, pkgs []Package) *Package {
 	}
 	return nil
 }
+
+// archFromPkg returns the architecture of the given package, preferring the "arch" package URL qualifier (which is
+// present for packages described by an SBOM) over the package manager metadata.
+func archFromPkg(p pkg.Package) string {
+	if arch := purlQualifiers(p.PURL)["arch"]; arch != "" {
+		return arch
+	}
+
+	switch metadata := p.Metadata.(type) {
+	case pkg.RpmdbMetadata:
+		return metadata.Arch
+	case pkg.ApkMetadata:
+		return metadata.Architecture
+	case pkg.DpkgMetadata:
+		return metadata.Architecture
+	}
+	return ""
+}
+
+func dataFromPkg(p pkg.Package) (interface{}, []UpstreamPackage) {
+	var metadata interface{}
+	var upstreams []UpstreamPackage
+
+	switch p.MetadataType {
+	case pkg.DpkgMetadataType:
+		metadata, upstreams = dpkgDataFromPkg(p)
+	case pkg.RpmdbMetadataType:
+		metadata, upstreams = rpmdbDataFromPkg(p)
+	case pkg.JavaMetadataType:
+		metadata = javaDataFromPkg(p)
+	case pkg.ApkMetadataType:
+		metadata, upstreams = apkDataFromPkg(p)
+	case pkg.GolangBinMetadataType:
+		metadata = golangBinDataFromPkg(p)
+	case pkg.PhpComposerJSONMetadataType:
+		metadata = phpComposerDataFromPkg(p)
+	case CondaMetadataType:
+		metadata = condaDataFromPkg(p)
+	case LinuxKernelMetadataType:
+		metadata = linuxKernelDataFromPkg(p)
+	case BinaryMetadataType:
+		metadata = binaryDataFromPkg(p)
+	case HomebrewMetadataType:
+		metadata = homebrewDataFromPkg(p)
+	}
+
+	if metadata == nil {
+		// packages without (syft) metadata may still have enough information elsewhere to support matching
+		switch p.Type {
+		case pkg.PhpComposerPkg:
+			metadata = phpComposerDataFromPURL(p)
+		case SwiftPkg:
+			metadata = swiftDataFromPkg(p)
+		case CondaPkg:
+			metadata = condaDataFromPURL(p)
+		case pkg.JavaPkg, pkg.JenkinsPluginPkg:
+			metadata = javaDataFromPURL(p)
+		}
+	}
+	return metadata, upstreams
+}
+
+func dpkgDataFromPkg(p pkg.Package) (metadata interface{}, upstreams []UpstreamPackage) {
+	if value, ok := p.Metadata.(pkg.DpkgMetadata); ok {
+		metadata = DpkgMetadata{Source: value.Source}
+		if value.Source != "" {
+			// note: the installed (binary) package version is used when searching by the source package name
+			upstreams = append(upstreams, UpstreamPackage{
+				Name: value.Source,
+			})
+		}
+	} else {
+		log.Warnf("unable to extract DPKG metadata for %s", p)
+	}
+	return metadata, upstreams
+}
+
+func rpmdbDataFromPkg(p pkg.Package) (metadata interface{}, upstreams []UpstreamPackage) {
+	if value, ok := p.Metadata.(pkg.RpmdbMetadata); ok {
+		// note: the modularity label is not captured by the rpmdb cataloger, so is read separately from the rpmdb when
+		// scanning a source (see addRpmModularityLabels) and is otherwise unknown
+		metadata = RpmdbMetadata{SourceRpm: value.SourceRpm, Epoch: value.Epoch}
+		if value.SourceRpm != "" {
+			// note: source RPMs that cannot be decomposed are reported for all packages at once (see FromCatalog)
+			name, version := getNameAndELVersion(value.SourceRpm)
+			if name != "" && name != p.Name {
+				// don't include upstreams if the source package name matches the current package name
+				upstreams = append(upstreams, UpstreamPackage{
+					Name:    name,
+					Version: version,
+				})
+			}
+		}
+	} else {
+		log.Warnf("unable to extract RPM metadata for %s", p)
+	}
+	return metadata, upstreams
+}
+
+// getNameAndELVersion returns the name and version of the given source RPM (e.g. "util-linux-ng" and
+// "2.17.2-12.28.el6_9.2" from "util-linux-ng-2.17.2-12.28.el6_9.2.src.rpm"), or empty values if it cannot be parsed.
+func getNameAndELVersion(sourceRpm string) (string, string) {
+	srpm, err := parseNEVRA(sourceRpm)
+	if err != nil {
+		return "", ""
+	}
+	return srpm.name, srpm.versionRelease()
+}
+
+// undecomposableSourceRpms returns the packages (with their source RPM) whose source RPM could not be parsed, and so
+// are not matched by source RPM.
+func undecomposableSourceRpms(packages []Package) []string {
+	var result []string
+	for _, p := range packages {
+		metadata, ok := p.Metadata.(RpmdbMetadata)
+		if !ok || metadata.SourceRpm == "" {
+			continue
+		}
+		if _, err := parseNEVRA(metadata.SourceRpm); err != nil {
+			result = append(result, fmt.Sprintf("%s@%s (sourceRPM=%q)", p.Name, p.Version, metadata.SourceRpm))
+		}
+	}
+	return result
+}
+
+func javaDataFromPkg(p pkg.Package) (metadata interface{}) {
+	if value, ok := p.Metadata.(pkg.JavaMetadata); ok {
+		var artifact, group, name string
+		if value.PomProperties != nil {
+			artifact = value.PomProperties.ArtifactID
+			group = value.PomProperties.GroupID
+		}
+		if value.Manifest != nil {
+			if n, ok := value.Manifest.Main["Name"]; ok {
+				name = n
+			}
+		}
+
+		var parentID ID
+		if value.Parent != nil {
+			parentID = ID(value.Parent.ID())
+		}
+
+		javaMetadata := JavaMetadata{
+			VirtualPath:   value.VirtualPath,
+			PomArtifactID: artifact,
+			PomGroupID:    group,
+			ManifestName:  name,
+			ParentID:      parentID,
+		}
+		if group == "" && artifact == "" {
+			// SBOMs may describe java packages without pom properties, but with the maven coordinates in the package URL
+			if fromPURL, ok := javaDataFromPURL(p).(JavaMetadata); ok {
+				javaMetadata.PomGroupID = fromPURL.PomGroupID
+				javaMetadata.PomArtifactID = fromPURL.PomArtifactID
+				javaMetadata.Classifier = fromPURL.Classifier
+			}
+		}
+		metadata = javaMetadata
+	} else {
+		log.Warnf("unable to extract Java metadata for %s", p)
+	}
+	return metadata
+}
+
+func apkDataFromPkg(p pkg.Package) (metadata interface{}, upstreams []UpstreamPackage) {
+	if value, ok := p.Metadata.(pkg.ApkMetadata); ok {
+		metadata = ApkMetadata{OriginPackage: value.OriginPackage}
+		if value.OriginPackage != "" && value.OriginPackage != p.Name {
+			upstreams = append(upstreams, UpstreamPackage{
+				Name: value.OriginPackage,
+			})
+		}
+	} else {
+		log.Warnf("unable to extract APK metadata for %s", p)
+	}
+	return metadata, upstreams
+}
+
+func golangBinDataFromPkg(p pkg.Package) (metadata interface{}) {
+	if value, ok := p.Metadata.(pkg.GolangBinMetadata); ok {
+		metadata = GolangBinMetadata{
+			GoCompiledVersion: value.GoCompiledVersion,
+			Architecture:      value.Architecture,
+		}
+	} else {
+		log.Warnf("unable to extract Go binary metadata for %s", p)
+	}
+	return metadata
+}
+
+func phpComposerDataFromPkg(p pkg.Package) (metadata interface{}) {
+	if value, ok := p.Metadata.(pkg.PhpComposerJSONMetadata); ok {
+		name := value.Name
+		if name == "" {
+			name = p.Name
+		}
+		// composer package names are always in the form of "vendor/name"
+		fields := strings.SplitN(name, "/", 2)
+		if len(fields) == 2 {
+			metadata = PhpComposerMetadata{Vendor: fields[0], Name: fields[1]}
+		} else {
+			metadata = phpComposerDataFromPURL(p)
+		}
+	} else {
+		log.Warnf("unable to extract PHP composer metadata for %s", p)
+	}
+	return metadata
+}
+
+func phpComposerDataFromPURL(p pkg.Package) (metadata interface{}) {
+	if vendor, name := purlNamespaceAndName(p.PURL); vendor != "" && name != "" {
+		metadata = PhpComposerMetadata{Vendor: vendor, Name: name}
+	}
+	return metadata
+}
+
+// javaDataFromPURL returns the maven coordinates of a java package from a maven package URL
+// (e.g. "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1?classifier=jdk8").
+func javaDataFromPURL(p pkg.Package) (metadata interface{}) {
+	if PURLType(p.PURL) != "maven" {
+		return nil
+	}
+	if group, artifact := purlNamespaceAndName(p.PURL); group != "" && artifact != "" {
+		metadata = JavaMetadata{
+			PomGroupID:    group,
+			PomArtifactID: artifact,
+			Classifier:    purlQualifiers(p.PURL)["classifier"],
+		}
+	}
+	return metadata
+}
+
+func swiftDataFromPkg(p pkg.Package) (metadata interface{}) {
+	var repositoryURL string
+	if namespace, name := purlNamespaceAndName(p.PURL); namespace != "" && name != "" {
+		// e.g. pkg:swift/github.com/apple/swift-nio@2.41.1
+		repositoryURL = namespace + "/" + name
+	} else if strings.Contains(p.Name, "/") {
+		// the package name may be the repository URL itself (as found in Package.resolved)
+		repositoryURL = p.Name
+	}
+
+	if repositoryURL = normalizeSwiftRepositoryURL(repositoryURL); repositoryURL != "" {
+		metadata = SwiftMetadata{RepositoryURL: repositoryURL}
+	} else {
+		log.Warnf("unable to extract Swift repository URL for %s", p)
+	}
+	return metadata
+}
+
+// normalizeSwiftRepositoryURL removes the scheme, any credentials, and the ".git" suffix from the given repository
+// URL (e.g. "https://github.com/apple/swift-nio.git" becomes "github.com/apple/swift-nio").
+func normalizeSwiftRepositoryURL(repositoryURL string) string {
+	repositoryURL = strings.TrimSpace(repositoryURL)
+	i := strings.Index(repositoryURL, "://")
+	hasScheme := i >= 0
+	if hasScheme {
+		repositoryURL = repositoryURL[i+3:]
+	}
+	if i := strings.Index(repositoryURL, "@"); i >= 0 {
+		repositoryURL = repositoryURL[i+1:]
+	}
+	if !hasScheme {
+		// scp-like git URLs use a colon to separate the host from the path (e.g. "git@github.com:apple/swift-nio.git")
+		repositoryURL = strings.Replace(repositoryURL, ":", "/", 1)
+	}
+	repositoryURL = strings.TrimSuffix(strings.TrimSuffix(repositoryURL, "/"), ".git")
+
+	fields := strings.SplitN(repositoryURL, "/", 2)
+	if len(fields) != 2 || fields[1] == "" {
+		return ""
+	}
+	// the host is case-insensitive, however, the path may not be
+	return strings.ToLower(fields[0]) + "/" + fields[1]
+}
+
+func condaDataFromPkg(p pkg.Package) (metadata interface{}) {
+	if value, ok := p.Metadata.(CondaMetadata); ok {
+		metadata = value
+	} else {
+		log.Warnf("unable to extract conda metadata for %s", p)
+	}
+	return metadata
+}
+
+func linuxKernelDataFromPkg(p pkg.Package) (metadata interface{}) {
+	if value, ok := p.Metadata.(LinuxKernelMetadata); ok {
+		metadata = value
+	} else {
+		log.Warnf("unable to extract linux kernel metadata for %s", p)
+	}
+	return metadata
+}
+
+func binaryDataFromPkg(p pkg.Package) (metadata interface{}) {
+	if value, ok := p.Metadata.(BinaryMetadata); ok {
+		metadata = value
+	} else {
+		log.Warnf("unable to extract binary metadata for %s", p)
+	}
+	return metadata
+}
+
+func homebrewDataFromPkg(p pkg.Package) (metadata interface{}) {
+	if value, ok := p.Metadata.(HomebrewMetadata); ok {
+		metadata = value
+	} else {
+		log.Warnf("unable to extract homebrew metadata for %s", p)
+	}
+	return metadata
+}
+
+func condaDataFromPURL(p pkg.Package) (metadata interface{}) {
+	qualifiers := purlQualifiers(p.PURL)
+	if qualifiers["channel"] != "" || qualifiers["build"] != "" {
+		metadata = CondaMetadata{
+			Channel: qualifiers["channel"],
+			Subdir:  qualifiers["subdir"],
+			Build:   qualifiers["build"],
+		}
+	}
+	return metadata
+}
@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v2"
+
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// PackageExclusion excludes the packages with a name (and optionally a type) that matches the glob expressions of the
+// exclusion from matching, e.g. the packages of test fixtures or of build-time only tooling.
+type PackageExclusion struct {
+	// Name is the glob expression of the package name (where "*" does not match a "/" and "**" does)
+	Name string
+	// Type is the glob expression of the package type (any type when empty)
+	Type string
+}
+
+// ParsePackageExclusion returns the exclusion of the given expression, which is a glob expression of the package name,
+// optionally prefixed by the package type (or a glob expression of it) and a colon, e.g. "eslint*", "npm:*-test-utils"
+// or "go-module:**".
+func ParsePackageExclusion(expression string) (PackageExclusion, error) {
+	exclusion := PackageExclusion{Name: expression}
+	if i := strings.Index(expression, ":"); i >= 0 && isTypeExpression(expression[:i]) {
+		exclusion = PackageExclusion{Type: expression[:i], Name: expression[i+1:]}
+	}
+	if exclusion.Name == "" {
+		return PackageExclusion{}, fmt.Errorf("package exclusion %q has no package name", expression)
+	}
+	// note: a bad pattern is only reported when the matching reaches it, so each pattern is matched against itself
+	for _, pattern := range []string{exclusion.Name, exclusion.Type} {
+		if _, err := doublestar.Match(pattern, pattern); err != nil {
+			return PackageExclusion{}, fmt.Errorf("bad package exclusion %q: %w", expression, err)
+		}
+	}
+	return exclusion, nil
+}
+
+// isTypeExpression indicates whether the given prefix of an exclusion is the package type (instead of the part of a
+// package name that contains a colon, such as a maven "group:artifact" name).
+func isTypeExpression(prefix string) bool {
+	if strings.ContainsAny(prefix, "*?[{") {
+		return true
+	}
+	for _, t := range syftPkg.AllPkgs {
+		if string(t) == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+func (e PackageExclusion) String() string {
+	if e.Type == "" {
+		return e.Name
+	}
+	return e.Type + ":" + e.Name
+}
+
+// matches indicates whether the package is excluded by the exclusion.
+func (e PackageExclusion) matches(p Package) (bool, error) {
+	if e.Type != "" {
+		matches, err := doublestar.Match(e.Type, string(p.Type))
+		if err != nil || !matches {
+			return false, err
+		}
+	}
+	return doublestar.Match(e.Name, p.Name)
+}
+
+// filterPackageNameExclusions returns the packages that do not match any of the given exclusions.
+func filterPackageNameExclusions(packages []Package, exclusions []PackageExclusion) ([]Package, error) {
+	var out []Package
+packages:
+	for _, p := range packages {
+		for _, exclusion := range exclusions {
+			excluded, err := exclusion.matches(p)
+			if err != nil {
+				return nil, err
+			}
+			if excluded {
+				continue packages
+			}
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
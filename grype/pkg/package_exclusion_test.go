@@ -0,0 +1,141 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/pkg/cataloger"
+)
+
+func TestParsePackageExclusion(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   PackageExclusion
+		wantErr    require.ErrorAssertionFunc
+	}{
+		{
+			expression: "eslint*",
+			expected:   PackageExclusion{Name: "eslint*"},
+		},
+		{
+			expression: "npm:*-test-utils",
+			expected:   PackageExclusion{Type: "npm", Name: "*-test-utils"},
+		},
+		{
+			expression: "*:jest",
+			expected:   PackageExclusion{Type: "*", Name: "jest"},
+		},
+		{
+			expression: "go-module:github.com/acme/tools/**",
+			expected:   PackageExclusion{Type: "go-module", Name: "github.com/acme/tools/**"},
+		},
+		{
+			// a colon that does not follow a package type is part of the name
+			expression: "org.acme:test-support",
+			expected:   PackageExclusion{Name: "org.acme:test-support"},
+		},
+		{
+			expression: "npm:",
+			wantErr:    require.Error,
+		},
+		{
+			expression: "",
+			wantErr:    require.Error,
+		},
+		{
+			expression: "npm:[test",
+			wantErr:    require.Error,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expression, func(t *testing.T) {
+			if test.wantErr == nil {
+				test.wantErr = require.NoError
+			}
+			exclusion, err := ParsePackageExclusion(test.expression)
+			test.wantErr(t, err)
+			assert.Equal(t, test.expected, exclusion)
+		})
+	}
+}
+
+func Test_filterPackageNameExclusions(t *testing.T) {
+	packages := []Package{
+		{Name: "eslint", Type: pkg.NpmPkg},
+		{Name: "eslint-plugin-react", Type: pkg.NpmPkg},
+		{Name: "acme-test-utils", Type: pkg.NpmPkg},
+		{Name: "acme-test-utils", Type: pkg.PythonPkg},
+		{Name: "github.com/acme/tools/lint", Type: pkg.GoModulePkg},
+		{Name: "github.com/acme/app", Type: pkg.GoModulePkg},
+	}
+
+	tests := []struct {
+		name       string
+		exclusions []string
+		expected   []string
+	}{
+		{
+			name:     "exclude nothing",
+			expected: []string{"npm/eslint", "npm/eslint-plugin-react", "npm/acme-test-utils", "python/acme-test-utils", "go-module/github.com/acme/tools/lint", "go-module/github.com/acme/app"},
+		},
+		{
+			name:       "exclude by name",
+			exclusions: []string{"eslint*", "*-test-utils"},
+			expected:   []string{"go-module/github.com/acme/tools/lint", "go-module/github.com/acme/app"},
+		},
+		{
+			name:       "exclude by name and type",
+			exclusions: []string{"npm:*-test-utils"},
+			expected:   []string{"npm/eslint", "npm/eslint-plugin-react", "python/acme-test-utils", "go-module/github.com/acme/tools/lint", "go-module/github.com/acme/app"},
+		},
+		{
+			name:       "exclude every package of a type",
+			exclusions: []string{"go-module:**"},
+			expected:   []string{"npm/eslint", "npm/eslint-plugin-react", "npm/acme-test-utils", "python/acme-test-utils"},
+		},
+		{
+			name:       "single star does not match a slash",
+			exclusions: []string{"github.com/acme/*", "github.com/acme/tools/**"},
+			expected:   []string{"npm/eslint", "npm/eslint-plugin-react", "npm/acme-test-utils", "python/acme-test-utils"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var exclusions []PackageExclusion
+			for _, e := range test.exclusions {
+				exclusion, err := ParsePackageExclusion(e)
+				require.NoError(t, err)
+				exclusions = append(exclusions, exclusion)
+			}
+
+			filtered, err := filterPackageNameExclusions(packages, exclusions)
+			require.NoError(t, err)
+
+			var names []string
+			for _, p := range filtered {
+				names = append(names, string(p.Type)+"/"+p.Name)
+			}
+			assert.Equal(t, test.expected, names)
+		})
+	}
+}
+
+func TestProviderPackageExcludes(t *testing.T) {
+	cfg := ProviderConfig{
+		PackageExclusions: []PackageExclusion{{Type: "java-archive", Name: "tomcat-*"}},
+		CatalogingOptions: cataloger.DefaultConfig(),
+	}
+	packages, _, err := Provide("test-fixtures/syft-spring.json", cfg)
+	require.NoError(t, err)
+
+	var names []string
+	for _, p := range packages {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"charsets"}, names)
+}
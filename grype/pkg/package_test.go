@@ -9,6 +9,7 @@ import (
 	"github.com/scylladb/go-set"
 	"github.com/scylladb/go-set/strset"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNew_MetadataExtraction(t *testing.T) {
@@ -209,21 +210,28 @@ func TestNew_MetadataExtraction(t *testing.T) {
 				MetadataType: syftPkg.GolangBinMetadataType,
 				Metadata: syftPkg.GolangBinMetadata{
 					GoCompiledVersion: "1.0.0",
+					Architecture:      "amd64",
 					H1Digest:          "a",
 				},
 			},
-			metadata: nil,
+			metadata: GolangBinMetadata{
+				GoCompiledVersion: "1.0.0",
+				Architecture:      "amd64",
+			},
 		},
 		{
 			name: "php-composer-metadata",
 			syftPkg: syftPkg.Package{
 				MetadataType: syftPkg.PhpComposerJSONMetadataType,
 				Metadata: syftPkg.PhpComposerJSONMetadata{
-					Name:    "a",
+					Name:    "a/b",
 					Version: "a",
 				},
 			},
-			metadata: nil,
+			metadata: PhpComposerMetadata{
+				Vendor: "a",
+				Name:   "b",
+			},
 		},
 	}
 
@@ -251,6 +259,231 @@ func TestNew_MetadataExtraction(t *testing.T) {
 	}
 }
 
+func TestNew_UpstreamExtraction(t *testing.T) {
+	tests := []struct {
+		name      string
+		syftPkg   syftPkg.Package
+		upstreams []UpstreamPackage
+	}{
+		{
+			name: "dpkg with source",
+			syftPkg: syftPkg.Package{
+				Name:         "libssl1.1",
+				MetadataType: syftPkg.DpkgMetadataType,
+				Metadata: syftPkg.DpkgMetadata{
+					Source:        "openssl",
+					SourceVersion: "1.1.1d-0+deb10u7",
+				},
+			},
+			upstreams: []UpstreamPackage{
+				{
+					Name: "openssl",
+				},
+			},
+		},
+		{
+			name: "dpkg with source that matches the package name",
+			syftPkg: syftPkg.Package{
+				Name:         "openssl",
+				MetadataType: syftPkg.DpkgMetadataType,
+				Metadata: syftPkg.DpkgMetadata{
+					Source: "openssl",
+				},
+			},
+			upstreams: []UpstreamPackage{
+				{
+					Name: "openssl",
+				},
+			},
+		},
+		{
+			name: "rpm with source rpm",
+			syftPkg: syftPkg.Package{
+				Name:         "perl-Errno",
+				MetadataType: syftPkg.RpmdbMetadataType,
+				Metadata: syftPkg.RpmdbMetadata{
+					SourceRpm: "perl-5.26.3-419.el8_4.1.src.rpm",
+				},
+			},
+			upstreams: []UpstreamPackage{
+				{
+					Name:    "perl",
+					Version: "5.26.3-419.el8_4.1",
+				},
+			},
+		},
+		{
+			name: "rpm with source rpm that matches the package name",
+			syftPkg: syftPkg.Package{
+				Name:         "sqlite",
+				MetadataType: syftPkg.RpmdbMetadataType,
+				Metadata: syftPkg.RpmdbMetadata{
+					SourceRpm: "sqlite-3.26.0-6.el8.src.rpm",
+				},
+			},
+		},
+		{
+			name: "rpm with invalid source rpm",
+			syftPkg: syftPkg.Package{
+				Name:         "sqlite",
+				MetadataType: syftPkg.RpmdbMetadataType,
+				Metadata: syftPkg.RpmdbMetadata{
+					SourceRpm: "src-rpm-info",
+				},
+			},
+		},
+		{
+			name: "apk with origin package",
+			syftPkg: syftPkg.Package{
+				Name:         "musl-utils",
+				MetadataType: syftPkg.ApkMetadataType,
+				Metadata: syftPkg.ApkMetadata{
+					OriginPackage: "musl",
+				},
+			},
+			upstreams: []UpstreamPackage{
+				{
+					Name: "musl",
+				},
+			},
+		},
+		{
+			name: "npm has no upstreams",
+			syftPkg: syftPkg.Package{
+				Name: "lodash",
+				Type: syftPkg.NpmPkg,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.upstreams, New(test.syftPkg).Upstreams)
+		})
+	}
+}
+
+func TestUpstreamPackages(t *testing.T) {
+	p := Package{
+		Name:    "musl-utils",
+		Version: "1.2.2-r3",
+		Type:    syftPkg.ApkPkg,
+		CPEs: []syftPkg.CPE{
+			must(syftPkg.NewCPE("cpe:2.3:a:musl-utils:musl-utils:1.2.2-r3:*:*:*:*:*:*:*")),
+			must(syftPkg.NewCPE("cpe:2.3:a:musl-utils:musl-utils:1.2.2-r3:*:*:*:*:*:*:*")),
+		},
+		Upstreams: []UpstreamPackage{
+			{
+				Name: "musl",
+			},
+			{
+				Name:    "musl-dev",
+				Version: "1.2.2-r4",
+			},
+		},
+	}
+
+	expected := []Package{
+		{
+			Name:    "musl",
+			Version: "1.2.2-r3",
+			Type:    syftPkg.ApkPkg,
+			CPEs: []syftPkg.CPE{
+				must(syftPkg.NewCPE("cpe:2.3:a:musl:musl:1.2.2-r3:*:*:*:*:*:*:*")),
+			},
+		},
+		{
+			Name:    "musl-dev",
+			Version: "1.2.2-r4",
+			Type:    syftPkg.ApkPkg,
+			CPEs: []syftPkg.CPE{
+				must(syftPkg.NewCPE("cpe:2.3:a:musl-dev:musl-dev:1.2.2-r4:*:*:*:*:*:*:*")),
+			},
+		},
+	}
+
+	assert.Equal(t, expected, UpstreamPackages(p))
+}
+
+func Test_getNameAndELVersion(t *testing.T) {
+	tests := []struct {
+		sourceRpm       string
+		expectedName    string
+		expectedVersion string
+	}{
+		{
+			sourceRpm:       "sqlite-3.26.0-6.el8.src.rpm",
+			expectedName:    "sqlite",
+			expectedVersion: "3.26.0-6.el8",
+		},
+		{
+			sourceRpm:       "util-linux-ng-2.17.2-12.28.el6_9.src.rpm",
+			expectedName:    "util-linux-ng",
+			expectedVersion: "2.17.2-12.28.el6_9",
+		},
+		{
+			sourceRpm:       "util-linux-ng-2.17.2-12.28.el6_9.2.src.rpm",
+			expectedName:    "util-linux-ng",
+			expectedVersion: "2.17.2-12.28.el6_9.2",
+		},
+		{
+			sourceRpm:       "sqlite-1.26.0-6.el8.src.rpm",
+			expectedName:    "sqlite",
+			expectedVersion: "1.26.0-6.el8",
+		},
+		{
+			sourceRpm:       "perl-4:5.26.3-419.el8.src.rpm",
+			expectedName:    "perl",
+			expectedVersion: "4:5.26.3-419.el8",
+		},
+		{
+			sourceRpm:       "openssl-3.0.0~beta1-1.fc35.src.rpm",
+			expectedName:    "openssl",
+			expectedVersion: "3.0.0~beta1-1.fc35",
+		},
+		{
+			sourceRpm: "src-rpm-info",
+		},
+		{
+			// the version is missing, which must not result in a partial version
+			sourceRpm: "sqlite--6.el8.src.rpm",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.sourceRpm, func(t *testing.T) {
+			actualName, actualVersion := getNameAndELVersion(test.sourceRpm)
+			assert.Equal(t, test.expectedName, actualName)
+			assert.Equal(t, test.expectedVersion, actualVersion)
+		})
+	}
+}
+
+func Test_undecomposableSourceRpms(t *testing.T) {
+	packages := []Package{
+		{
+			Name:     "sqlite-libs",
+			Version:  "3.26.0-6.el8",
+			Metadata: RpmdbMetadata{SourceRpm: "sqlite-3.26.0-6.el8.src.rpm"},
+		},
+		{
+			Name:     "bogus",
+			Version:  "1.0-1",
+			Metadata: RpmdbMetadata{SourceRpm: "bogus.src.rpm"},
+		},
+		{
+			Name:     "no-source",
+			Version:  "1.0-1",
+			Metadata: RpmdbMetadata{},
+		},
+		{
+			Name:     "neutron",
+			Version:  "2014.1.3-6",
+			Metadata: DpkgMetadata{Source: "neutron-devel"},
+		},
+	}
+
+	assert.Equal(t, []string{`bogus@1.0-1 (sourceRPM="bogus.src.rpm")`}, undecomposableSourceRpms(packages))
+}
+
 func TestFromCatalog_DoesNotPanic(t *testing.T) {
 	catalog := syftPkg.NewCatalog()
 
@@ -272,6 +505,427 @@ func TestFromCatalog_DoesNotPanic(t *testing.T) {
 	})
 }
 
+func TestFromCatalog_GoStdlib(t *testing.T) {
+	catalog := syftPkg.NewCatalog()
+
+	for _, name := range []string{"github.com/anchore/a", "github.com/anchore/b"} {
+		catalog.Add(syftPkg.Package{
+			Name:    name,
+			Version: "v1.0.0",
+			Locations: []source.Location{
+				source.NewLocation("/bin/app"),
+			},
+			Type:         syftPkg.GoModulePkg,
+			Language:     syftPkg.Go,
+			MetadataType: syftPkg.GolangBinMetadataType,
+			Metadata: syftPkg.GolangBinMetadata{
+				GoCompiledVersion: "go1.17.2",
+				Architecture:      "amd64",
+			},
+		})
+	}
+
+	packages := FromCatalog(catalog)
+	assert.Len(t, packages, 3)
+
+	stdlib := packages[2]
+	assert.Equal(t, GoStdlibPackageName, stdlib.Name)
+	assert.Equal(t, "go1.17.2", stdlib.Version)
+	assert.Equal(t, syftPkg.GoModulePkg, stdlib.Type)
+	assert.Equal(t, syftPkg.Go, stdlib.Language)
+	assert.Equal(t, "/bin/app", stdlib.Locations[0].RealPath)
+	assert.Equal(t, []syftPkg.CPE{must(syftPkg.NewCPE("cpe:2.3:a:golang:go:1.17.2:*:*:*:*:*:*:*"))}, stdlib.CPEs)
+}
+
+func TestFromCatalog_Deduplication(t *testing.T) {
+	newCatalog := func() *syftPkg.Catalog {
+		catalog := syftPkg.NewCatalog()
+		for _, l := range []struct {
+			path string
+			cpe  string
+		}{
+			{path: "/layer-1/app.jar", cpe: "cpe:2.3:a:apache:log4j:2.14.1:*:*:*:*:*:*:*"},
+			{path: "/layer-2/app.jar", cpe: "cpe:2.3:a:apache:log4j-core:2.14.1:*:*:*:*:*:*:*"},
+			{path: "/layer-1/app.jar", cpe: "cpe:2.3:a:apache:log4j:2.14.1:*:*:*:*:*:*:*"},
+		} {
+			catalog.Add(syftPkg.Package{
+				Name:      "log4j-core",
+				Version:   "2.14.1",
+				Locations: []source.Location{source.NewLocation(l.path)},
+				Type:      syftPkg.JavaPkg,
+				Language:  syftPkg.Java,
+				CPEs:      []syftPkg.CPE{must(syftPkg.NewCPE(l.cpe))},
+				PURL:      "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+			})
+		}
+		// a package with a different name that claims the same package URL is not the same package
+		catalog.Add(syftPkg.Package{
+			Name:      "log4j-api",
+			Version:   "2.14.1",
+			Locations: []source.Location{source.NewLocation("/layer-1/api.jar")},
+			Type:      syftPkg.JavaPkg,
+			Language:  syftPkg.Java,
+			PURL:      "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+		})
+		return catalog
+	}
+
+	packages := FromCatalogWithConfig(newCatalog(), CatalogConfig{Deduplicate: true})
+	require.Len(t, packages, 2)
+
+	var merged Package
+	for _, p := range packages {
+		if p.Name == "log4j-core" {
+			merged = p
+		}
+	}
+	var paths []string
+	for _, l := range merged.Locations {
+		paths = append(paths, l.RealPath)
+	}
+	assert.ElementsMatch(t, []string{"/layer-1/app.jar", "/layer-2/app.jar"}, paths)
+	assert.ElementsMatch(t, []syftPkg.CPE{
+		must(syftPkg.NewCPE("cpe:2.3:a:apache:log4j:2.14.1:*:*:*:*:*:*:*")),
+		must(syftPkg.NewCPE("cpe:2.3:a:apache:log4j-core:2.14.1:*:*:*:*:*:*:*")),
+	}, merged.CPEs)
+
+	// packages are not merged by default
+	packages = FromCatalog(newCatalog())
+	assert.Len(t, packages, 4)
+}
+
+func TestDeduplicateByPURL_Metadata(t *testing.T) {
+	newPackage := func(path, groupID string) Package {
+		return Package{
+			ID:        ID(path),
+			Name:      "log4j-core",
+			Version:   "2.14.1",
+			Locations: []source.Location{source.NewLocation(path)},
+			Type:      syftPkg.JavaPkg,
+			PURL:      "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+			Metadata:  JavaMetadata{PomGroupID: groupID},
+		}
+	}
+
+	packages := deduplicateByPURL([]Package{
+		newPackage("/layer-1/app.jar", "org.apache.logging.log4j"),
+		newPackage("/layer-2/app.jar", "org.apache.logging.log4j"),
+		// the metadata of another location differs (e.g. a shaded copy), which is kept for matching
+		newPackage("/layer-2/shaded.jar", "com.example.shaded"),
+	})
+	require.Len(t, packages, 2)
+
+	var paths []string
+	for _, l := range packages[0].Locations {
+		paths = append(paths, l.RealPath)
+	}
+	assert.Equal(t, []string{"/layer-1/app.jar", "/layer-2/app.jar"}, paths)
+	assert.Equal(t, JavaMetadata{PomGroupID: "org.apache.logging.log4j"}, packages[0].Metadata)
+
+	require.Len(t, packages[1].Locations, 1)
+	assert.Equal(t, "/layer-2/shaded.jar", packages[1].Locations[0].RealPath)
+	assert.Equal(t, JavaMetadata{PomGroupID: "com.example.shaded"}, packages[1].Metadata)
+}
+
 func intRef(i int) *int {
 	return &i
 }
+
+func TestNew_TypeFromPURL(t *testing.T) {
+	tests := []struct {
+		name             string
+		syftPkg          syftPkg.Package
+		expectedType     syftPkg.Type
+		expectedLanguage syftPkg.Language
+	}{
+		{
+			name: "type given",
+			syftPkg: syftPkg.Package{
+				Name:     "a",
+				Type:     syftPkg.NpmPkg,
+				Language: syftPkg.JavaScript,
+				PURL:     "pkg:nuget/a@1.0.0",
+			},
+			expectedType:     syftPkg.NpmPkg,
+			expectedLanguage: syftPkg.JavaScript,
+		},
+		{
+			name: "nuget purl",
+			syftPkg: syftPkg.Package{
+				Name: "Newtonsoft.Json",
+				PURL: "pkg:nuget/Newtonsoft.Json@12.0.3",
+			},
+			expectedType:     DotnetPkg,
+			expectedLanguage: Dotnet,
+		},
+		{
+			name: "pub purl",
+			syftPkg: syftPkg.Package{
+				Name: "http",
+				Type: syftPkg.UnknownPkg,
+				PURL: "pkg:pub/http@0.13.4",
+			},
+			expectedType:     DartPubPkg,
+			expectedLanguage: Dart,
+		},
+		{
+			name: "hex purl",
+			syftPkg: syftPkg.Package{
+				Name: "plug",
+				PURL: "pkg:hex/plug@1.11.0",
+			},
+			expectedType:     HexPkg,
+			expectedLanguage: Elixir,
+		},
+		{
+			name: "unknown purl type",
+			syftPkg: syftPkg.Package{
+				Name: "a",
+				Type: syftPkg.UnknownPkg,
+				PURL: "pkg:bogus/a@1.0.0",
+			},
+			expectedType: syftPkg.UnknownPkg,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := New(test.syftPkg)
+			assert.Equal(t, test.expectedType, actual.Type)
+			assert.Equal(t, test.expectedLanguage, actual.Language)
+		})
+	}
+}
+
+func TestNew_Arch(t *testing.T) {
+	tests := []struct {
+		name     string
+		syftPkg  syftPkg.Package
+		expected string
+	}{
+		{
+			name: "rpmdb metadata",
+			syftPkg: syftPkg.Package{
+				Name:         "bash",
+				Type:         syftPkg.RpmPkg,
+				MetadataType: syftPkg.RpmdbMetadataType,
+				Metadata:     syftPkg.RpmdbMetadata{Arch: "aarch64"},
+			},
+			expected: "aarch64",
+		},
+		{
+			name: "apk metadata",
+			syftPkg: syftPkg.Package{
+				Name:         "busybox",
+				Type:         syftPkg.ApkPkg,
+				MetadataType: syftPkg.ApkMetadataType,
+				Metadata:     syftPkg.ApkMetadata{Architecture: "x86_64"},
+			},
+			expected: "x86_64",
+		},
+		{
+			name: "purl qualifier takes precedence over metadata",
+			syftPkg: syftPkg.Package{
+				Name:         "bash",
+				Type:         syftPkg.RpmPkg,
+				PURL:         "pkg:rpm/redhat/bash@4.4.19-14.el8?arch=noarch",
+				MetadataType: syftPkg.RpmdbMetadataType,
+				Metadata:     syftPkg.RpmdbMetadata{Arch: "aarch64"},
+			},
+			expected: "noarch",
+		},
+		{
+			name: "unknown arch",
+			syftPkg: syftPkg.Package{
+				Name: "lodash",
+				Type: syftPkg.NpmPkg,
+				PURL: "pkg:npm/lodash@4.17.21",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, New(test.syftPkg).Arch)
+		})
+	}
+}
+
+func TestNew_JavaMetadataFromPURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		syftPkg  syftPkg.Package
+		metadata interface{}
+	}{
+		{
+			name: "coordinates from purl",
+			syftPkg: syftPkg.Package{
+				Name: "log4j-core",
+				PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+			},
+			metadata: JavaMetadata{
+				PomGroupID:    "org.apache.logging.log4j",
+				PomArtifactID: "log4j-core",
+			},
+		},
+		{
+			name: "classifier from purl",
+			syftPkg: syftPkg.Package{
+				Name: "netty-tcnative-boringssl-static",
+				Type: syftPkg.JavaPkg,
+				PURL: "pkg:maven/io.netty/netty-tcnative-boringssl-static@2.0.46.Final?classifier=linux-x86_64&type=jar",
+			},
+			metadata: JavaMetadata{
+				PomGroupID:    "io.netty",
+				PomArtifactID: "netty-tcnative-boringssl-static",
+				Classifier:    "linux-x86_64",
+			},
+		},
+		{
+			name: "java metadata without pom properties",
+			syftPkg: syftPkg.Package{
+				Name:         "log4j-core",
+				Type:         syftPkg.JavaPkg,
+				PURL:         "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+				MetadataType: syftPkg.JavaMetadataType,
+				Metadata: syftPkg.JavaMetadata{
+					VirtualPath: "/app/log4j-core.jar",
+				},
+			},
+			metadata: JavaMetadata{
+				VirtualPath:   "/app/log4j-core.jar",
+				PomGroupID:    "org.apache.logging.log4j",
+				PomArtifactID: "log4j-core",
+			},
+		},
+		{
+			name: "pom properties take precedence over the purl",
+			syftPkg: syftPkg.Package{
+				Name:         "log4j-core",
+				Type:         syftPkg.JavaPkg,
+				PURL:         "pkg:maven/log4j-core/log4j-core@2.14.1",
+				MetadataType: syftPkg.JavaMetadataType,
+				Metadata: syftPkg.JavaMetadata{
+					PomProperties: &syftPkg.PomProperties{GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-core"},
+				},
+			},
+			metadata: JavaMetadata{
+				PomGroupID:    "org.apache.logging.log4j",
+				PomArtifactID: "log4j-core",
+			},
+		},
+		{
+			name: "no group",
+			syftPkg: syftPkg.Package{
+				Name: "log4j-core",
+				Type: syftPkg.JavaPkg,
+				PURL: "pkg:maven/log4j-core@2.14.1",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := New(test.syftPkg)
+			assert.Equal(t, syftPkg.JavaPkg, actual.Type)
+			assert.Equal(t, test.metadata, actual.Metadata)
+		})
+	}
+}
+
+func TestNew_PhpComposerMetadataFromPURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		syftPkg  syftPkg.Package
+		metadata interface{}
+	}{
+		{
+			name: "vendor from purl",
+			syftPkg: syftPkg.Package{
+				Name: "http-kernel",
+				PURL: "pkg:composer/symfony/http-kernel@4.4.0",
+			},
+			metadata: PhpComposerMetadata{
+				Vendor: "symfony",
+				Name:   "http-kernel",
+			},
+		},
+		{
+			name: "escaped purl",
+			syftPkg: syftPkg.Package{
+				Name: "b",
+				Type: syftPkg.PhpComposerPkg,
+				PURL: "pkg:composer/a%2Dvendor/b@1.0.0?repository_url=example.com",
+			},
+			metadata: PhpComposerMetadata{
+				Vendor: "a-vendor",
+				Name:   "b",
+			},
+		},
+		{
+			name: "no vendor",
+			syftPkg: syftPkg.Package{
+				Name: "b",
+				Type: syftPkg.PhpComposerPkg,
+				PURL: "pkg:composer/b@1.0.0",
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := New(test.syftPkg)
+			assert.Equal(t, syftPkg.PhpComposerPkg, actual.Type)
+			assert.Equal(t, test.metadata, actual.Metadata)
+		})
+	}
+}
+
+func TestNew_SwiftMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		syftPkg  syftPkg.Package
+		metadata interface{}
+	}{
+		{
+			name: "repository URL from purl",
+			syftPkg: syftPkg.Package{
+				Name: "swift-nio",
+				PURL: "pkg:swift/github.com/apple/swift-nio@2.41.1",
+			},
+			metadata: SwiftMetadata{
+				RepositoryURL: "github.com/apple/swift-nio",
+			},
+		},
+		{
+			name: "repository URL as name",
+			syftPkg: syftPkg.Package{
+				Name: "https://GitHub.com/apple/swift-nio.git",
+				Type: SwiftPkg,
+			},
+			metadata: SwiftMetadata{
+				RepositoryURL: "github.com/apple/swift-nio",
+			},
+		},
+		{
+			name: "scp-like repository URL as name",
+			syftPkg: syftPkg.Package{
+				Name: "git@github.com:apple/swift-nio.git",
+				Type: SwiftPkg,
+			},
+			metadata: SwiftMetadata{
+				RepositoryURL: "github.com/apple/swift-nio",
+			},
+		},
+		{
+			name: "no repository URL",
+			syftPkg: syftPkg.Package{
+				Name: "swift-nio",
+				Type: SwiftPkg,
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual := New(test.syftPkg)
+			assert.Equal(t, SwiftPkg, actual.Type)
+			assert.Equal(t, test.metadata, actual.Metadata)
+		})
+	}
+}
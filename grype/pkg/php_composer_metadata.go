@@ -0,0 +1,6 @@
+package pkg
+
+type PhpComposerMetadata struct {
+	Vendor string
+	Name   string
+}
@@ -0,0 +1,156 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/stereoscope/pkg/image"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/scylladb/go-set/strset"
+)
+
+// nonImageSchemes are the schemes of inputs that are not image references.
+var nonImageSchemes = strset.New("dir", "file", "sbom", "docker-container", "podman", "containerd", "vm", "att")
+
+// PlatformImage is the image of a single platform of an image reference.
+type PlatformImage struct {
+	// Platform is the platform of the image (e.g. "linux/arm64/v8")
+	Platform string
+	// Input is the image pinned to the manifest of the platform (e.g. "registry:docker.io/library/alpine@sha256:...")
+	Input string
+}
+
+// ImagePlatforms returns the image of each platform of the given image reference (which is pulled from a registry),
+// where a reference of a manifest list has an image for each platform and any other reference has a single image.
+func ImagePlatforms(userInput string, registryOptions *image.RegistryOptions) ([]PlatformImage, error) {
+	reference, err := registryReference(userInput)
+	if err != nil {
+		return nil, err
+	}
+	if registryOptions == nil {
+		registryOptions = &image.RegistryOptions{}
+	}
+
+	var nameOptions []name.Option
+	if registryOptions.InsecureUseHTTP {
+		nameOptions = append(nameOptions, name.Insecure)
+	}
+	ref, err := name.ParseReference(reference, nameOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse registry reference=%q: %w", reference, err)
+	}
+
+	descriptor, err := remote.Get(ref, remoteOptions(ref, registryOptions)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image descriptor from registry: %w", err)
+	}
+	pinned := func(digest v1.Hash) string {
+		return fmt.Sprintf("registry:%s@%s", ref.Context().Name(), digest)
+	}
+
+	if !descriptor.MediaType.IsIndex() {
+		img, err := descriptor.Image()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get image from registry: %w", err)
+		}
+		config, err := img.ConfigFile()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get image config from registry: %w", err)
+		}
+		platform := v1.Platform{OS: config.OS, Architecture: config.Architecture}
+		return []PlatformImage{{Platform: platformString(platform), Input: pinned(descriptor.Digest)}}, nil
+	}
+
+	index, err := descriptor.ImageIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image index from registry: %w", err)
+	}
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get image index manifest from registry: %w", err)
+	}
+
+	var images []PlatformImage
+	for _, m := range manifest.Manifests {
+		// note: build attestations are attached as manifests without a platform (i.e. "unknown/unknown")
+		if m.Platform == nil || m.Platform.OS == "unknown" || !m.MediaType.IsImage() {
+			continue
+		}
+		images = append(images, PlatformImage{Platform: platformString(*m.Platform), Input: pinned(m.Digest)})
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("the manifest list of %q has no platform images", reference)
+	}
+	return images, nil
+}
+
+// SelectPlatform returns the image of the given platform ("os/arch" or "os/arch/variant"), where a platform without a
+// variant matches any variant.
+func SelectPlatform(images []PlatformImage, platform string) (PlatformImage, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return PlatformImage{}, fmt.Errorf("bad platform %q (expected os/arch or os/arch/variant, e.g. linux/arm64)", platform)
+	}
+
+	var available []string
+	for _, img := range images {
+		if img.Platform == platform || (len(parts) == 2 && strings.HasPrefix(img.Platform, platform+"/")) {
+			return img, nil
+		}
+		available = append(available, img.Platform)
+	}
+	return PlatformImage{}, fmt.Errorf("the image is not available for platform %s (available platforms: %s)", platform, strings.Join(available, ", "))
+}
+
+// registryReference returns the image reference of the given input, which must be pulled from a registry.
+func registryReference(userInput string) (string, error) {
+	candidates := strings.SplitN(userInput, image.SchemeSeparator, 2)
+	if len(candidates) < 2 {
+		return userInput, nil
+	}
+
+	scheme := candidates[0]
+	switch {
+	case image.ParseSourceScheme(scheme) == image.OciRegistrySource:
+		return candidates[1], nil
+	case image.ParseSourceScheme(scheme) != image.UnknownSource, nonImageSchemes.Has(scheme):
+		return "", fmt.Errorf("a platform can only be selected for images pulled from a registry (e.g. registry:<image>)")
+	default:
+		// e.g. "alpine:3.14", where the tag is mistaken for a scheme
+		return userInput, nil
+	}
+}
+
+func platformString(platform v1.Platform) string {
+	parts := []string{platform.OS, platform.Architecture}
+	if platform.Variant != "" {
+		parts = append(parts, platform.Variant)
+	}
+	return strings.Join(parts, "/")
+}
+
+// remoteOptions returns the options to access the registry of the given reference (as used when pulling images).
+func remoteOptions(ref name.Reference, registryOptions *image.RegistryOptions) []remote.Option {
+	return []remote.Option{
+		remote.WithTransport(registryTransport(registryOptions)),
+		remote.WithAuth(registryAuthenticator(ref, registryOptions)),
+	}
+}
+
+// registryAuthenticator returns the configured credentials of the registry of the given reference, or else the
+// credentials of the docker config.
+func registryAuthenticator(ref name.Reference, registryOptions *image.RegistryOptions) authn.Authenticator {
+	if authenticator := registryOptions.Authenticator(ref.Context().RegistryStr()); authenticator != nil {
+		return authenticator
+	}
+	authenticator, err := authn.DefaultKeychain.Resolve(ref.Context())
+	if err != nil {
+		log.Debugf("unable to read registry credentials from the docker config: %+v", err)
+		return authn.Anonymous
+	}
+	return authenticator
+}
@@ -0,0 +1,114 @@
+package pkg
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anchore/stereoscope/pkg/image"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImagePlatforms(t *testing.T) {
+	server := httptest.NewServer(registry.New())
+	defer server.Close()
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	var addenda []mutate.IndexAddendum
+	digests := make(map[string]v1.Hash)
+	for _, platform := range []v1.Platform{
+		{OS: "linux", Architecture: "amd64"},
+		{OS: "linux", Architecture: "arm64", Variant: "v8"},
+		{OS: "unknown", Architecture: "unknown"},
+	} {
+		platform := platform
+		img, err := random.Image(64, 1)
+		require.NoError(t, err)
+		digest, err := img.Digest()
+		require.NoError(t, err)
+		digests[platformString(platform)] = digest
+		addenda = append(addenda, mutate.IndexAddendum{Add: img, Descriptor: v1.Descriptor{Platform: &platform}})
+	}
+	index := mutate.AppendManifests(empty.Index, addenda...)
+	indexRef, err := name.ParseReference(fmt.Sprintf("%s/app:multi", host))
+	require.NoError(t, err)
+	require.NoError(t, remote.WriteIndex(indexRef, index))
+
+	single, err := random.Image(64, 1)
+	require.NoError(t, err)
+	single, err = mutate.ConfigFile(single, &v1.ConfigFile{OS: "linux", Architecture: "amd64"})
+	require.NoError(t, err)
+	singleDigest, err := single.Digest()
+	require.NoError(t, err)
+	singleRef, err := name.ParseReference(fmt.Sprintf("%s/app:single", host))
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(singleRef, single))
+
+	options := &image.RegistryOptions{InsecureUseHTTP: true}
+
+	images, err := ImagePlatforms(fmt.Sprintf("registry:%s/app:multi", host), options)
+	require.NoError(t, err)
+	assert.Equal(t, []PlatformImage{
+		{Platform: "linux/amd64", Input: fmt.Sprintf("registry:%s/app@%s", host, digests["linux/amd64"])},
+		{Platform: "linux/arm64/v8", Input: fmt.Sprintf("registry:%s/app@%s", host, digests["linux/arm64/v8"])},
+	}, images)
+
+	selected, err := SelectPlatform(images, "linux/arm64")
+	require.NoError(t, err)
+	assert.Equal(t, images[1], selected)
+	_, err = SelectPlatform(images, "linux/s390x")
+	assert.Error(t, err)
+
+	images, err = ImagePlatforms(fmt.Sprintf("%s/app:single", host), options)
+	require.NoError(t, err)
+	assert.Equal(t, []PlatformImage{
+		{Platform: "linux/amd64", Input: fmt.Sprintf("registry:%s/app@%s", host, singleDigest)},
+	}, images)
+
+	_, err = ImagePlatforms("dir:./src", options)
+	assert.Error(t, err)
+	_, err = ImagePlatforms("docker-archive:image.tar", options)
+	assert.Error(t, err)
+}
+
+func TestSelectPlatform(t *testing.T) {
+	images := []PlatformImage{
+		{Platform: "linux/amd64", Input: "amd64"},
+		{Platform: "linux/arm/v7", Input: "armv7"},
+		{Platform: "linux/arm64/v8", Input: "arm64"},
+	}
+
+	tests := []struct {
+		platform string
+		expected string
+		wantErr  bool
+	}{
+		{platform: "linux/amd64", expected: "amd64"},
+		{platform: "linux/arm64", expected: "arm64"},
+		{platform: "linux/arm64/v8", expected: "arm64"},
+		{platform: "linux/arm/v6", wantErr: true},
+		{platform: "windows/amd64", wantErr: true},
+		{platform: "amd64", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.platform, func(t *testing.T) {
+			selected, err := SelectPlatform(images, test.platform)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, selected.Input)
+		})
+	}
+}
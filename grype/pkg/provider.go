@@ -1,6 +1,7 @@
 package pkg
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -13,18 +14,82 @@ var errDoesNotProvide = fmt.Errorf("cannot provide packages from the given sourc
 
 // Provide a set of packages and context metadata describing where they were sourced from.
 func Provide(userInput string, config ProviderConfig) ([]Package, Context, error) {
-	packages, ctx, err := syftSBOMProvider(userInput)
-	if !errors.Is(err, errDoesNotProvide) {
-		if len(config.Exclusions) > 0 {
-			packages, err = filterPackageExclusions(packages, config.Exclusions)
-			if err != nil {
-				return nil, ctx, err
+	return ProvideContext(context.Background(), userInput, config)
+}
+
+// ProvideContext is Provide, where the spans of reading the source (e.g. pulling an image) and cataloging it are
+// children of the span of the given context.
+func ProvideContext(spanCtx context.Context, userInput string, config ProviderConfig) ([]Package, Context, error) {
+	packages, ctx, err := provide(spanCtx, userInput, config)
+	if err != nil {
+		return nil, ctx, err
+	}
+
+	if len(config.PackageExclusions) > 0 {
+		packages, err = filterPackageNameExclusions(packages, config.PackageExclusions)
+		if err != nil {
+			return nil, ctx, err
+		}
+	}
+
+	if config.ExcludeDevDependencies {
+		packages = filterDevelopmentDependencies(packages)
+	}
+
+	if err := applyCPEOverrides(packages, config.CPEOverrides); err != nil {
+		return nil, ctx, err
+	}
+
+	if config.Distro != nil {
+		ctx.Distro = config.Distro
+	}
+
+	addLayers(packages, ctx.Source)
+
+	return packages, ctx, nil
+}
+
+func provide(spanCtx context.Context, userInput string, config ProviderConfig) ([]Package, Context, error) {
+	// note: the paths of the packages of SBOMs (and attestations of SBOMs) are excluded here, where the paths of the
+	// sources that are cataloged are excluded by syft
+	for _, sbomProvider := range []func(string, ProviderConfig) ([]Package, Context, error){
+		syftSBOMProvider,
+		attestationInputProvider,
+		attestationProvider,
+	} {
+		packages, ctx, err := sbomProvider(userInput, config)
+		if !errors.Is(err, errDoesNotProvide) {
+			if err == nil && len(config.Exclusions) > 0 {
+				packages, err = filterPackageExclusions(packages, config.Exclusions)
 			}
+			return packages, ctx, err
 		}
+	}
+
+	packages, ctx, err := containerProvider(spanCtx, userInput, config)
+	if !errors.Is(err, errDoesNotProvide) {
 		return packages, ctx, err
 	}
 
-	return syftProvider(userInput, config)
+	packages, ctx, err = vmDiskProvider(spanCtx, userInput, config)
+	if !errors.Is(err, errDoesNotProvide) {
+		return packages, ctx, err
+	}
+
+	packages, ctx, err = syftProvider(spanCtx, userInput, config)
+	if err != nil || !config.Archives.Enabled || ctx.Source == nil {
+		return packages, ctx, err
+	}
+
+	switch ctx.Source.Scheme {
+	case source.DirectoryScheme, source.FileScheme:
+		archivePackages, err := archiveProvider(spanCtx, ctx.Source.Path, config)
+		if err != nil {
+			return nil, ctx, err
+		}
+		packages = append(packages, archivePackages...)
+	}
+	return packages, ctx, nil
 }
 
 // This will filter the provided packages list based on a set of exclusion expressions. Globs
@@ -2,6 +2,7 @@ package pkg
 
 import (
 	"github.com/anchore/stereoscope/pkg/image"
+	"github.com/anchore/syft/syft/linux"
 	"github.com/anchore/syft/syft/pkg/cataloger"
 )
 
@@ -9,4 +10,22 @@ type ProviderConfig struct {
 	RegistryOptions   *image.RegistryOptions
 	Exclusions        []string
 	CatalogingOptions cataloger.Config
+	// PackageExclusions are the packages (by name and type) that are excluded from matching
+	PackageExclusions []PackageExclusion
+	// ExcludeDevDependencies excludes the packages that are only needed to develop or test a project from matching
+	ExcludeDevDependencies bool
+	// CPEOverrides change the CPEs of the packages (in the given order) before the packages are matched
+	CPEOverrides []CPEOverride
+	// Distro is the distro to use for matching instead of the distro detected from the source (if any)
+	Distro *linux.Release
+	// MavenSearch controls the lookup of java archives without maven coordinates against Maven Central
+	MavenSearch MavenSearchConfig
+	// Catalog controls how the cataloged packages are converted (e.g. if packages with the same package URL are merged)
+	Catalog CatalogConfig
+	// SBOMCache controls the reuse of the packages cataloged by syft for images that were scanned before
+	SBOMCache SBOMCacheConfig
+	// Archives controls the unpacking of the archives within directory and file targets
+	Archives ArchiveConfig
+	// Attestations controls the scanning of the signed SBOM attestations of images instead of the images
+	Attestations AttestationConfig
 }
@@ -3,11 +3,14 @@ package pkg
 import (
 	"testing"
 
+	"github.com/anchore/grype/grype/distro"
 	"github.com/anchore/syft/syft/pkg/cataloger"
 
 	"github.com/anchore/stereoscope/pkg/imagetest"
+	"github.com/anchore/syft/syft/linux"
 	"github.com/anchore/syft/syft/source"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestProviderLocationExcludes(t *testing.T) {
@@ -232,3 +235,34 @@ func Test_matchesLocation(t *testing.T) {
 		})
 	}
 }
+
+func TestProviderDistroOverride(t *testing.T) {
+	tests := []struct {
+		name     string
+		distro   *linux.Release
+		expected distro.Type
+	}{
+		{
+			name:     "detected distro",
+			expected: distro.Alpine,
+		},
+		{
+			name:     "overridden distro",
+			distro:   &linux.Release{ID: "wolfi"},
+			expected: distro.Wolfi,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := ProviderConfig{
+				CatalogingOptions: cataloger.DefaultConfig(),
+				Distro:            test.distro,
+			}
+			_, ctx, err := Provide("test-fixtures/syft-alpine.json", cfg)
+			require.NoError(t, err)
+			require.NotNil(t, ctx.Distro)
+			assert.Equal(t, test.expected, distro.TypeFromRelease(*ctx.Distro))
+		})
+	}
+}
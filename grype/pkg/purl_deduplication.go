@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"reflect"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+// deduplicateByPURL merges packages with the same package URL into the first of these packages, which then has the
+// locations and CPEs of all merged packages. The same package is commonly found at several locations (e.g. the same
+// package within several layers of an image), which would otherwise be matched (and reported) once per location.
+// Note: package URLs within SBOMs are not always accurate, so packages are only merged when their type, name and version
+// are the same as well. Packages are only merged when their metadata is the same too, so that the metadata of every
+// location is kept for matching (e.g. java archives are not merged, since their metadata has the path of each archive).
+func deduplicateByPURL(packages []Package) []Package {
+	type key struct {
+		purl    string
+		pkgType pkg.Type
+		name    string
+		version string
+	}
+
+	result := make([]Package, 0, len(packages))
+	indicesByKey := make(map[key][]int)
+	for _, p := range packages {
+		if p.PURL == "" {
+			result = append(result, p)
+			continue
+		}
+
+		k := key{purl: p.PURL, pkgType: p.Type, name: p.Name, version: p.Version}
+		idx := -1
+		for _, i := range indicesByKey[k] {
+			if reflect.DeepEqual(result[i].Metadata, p.Metadata) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			indicesByKey[k] = append(indicesByKey[k], len(result))
+			result = append(result, p)
+			continue
+		}
+
+		merged := &result[idx]
+		merged.Locations = mergeLocations(merged.Locations, p.Locations)
+		merged.CPEs = mergeCPEs(merged.CPEs, p.CPEs)
+	}
+
+	if merged := len(packages) - len(result); merged > 0 {
+		log.Debugf("merged %d packages with the same package URL as another package", merged)
+	}
+	return result
+}
+
+// mergeLocations returns the union of the given locations (in order).
+func mergeLocations(locations, others []source.Location) []source.Location {
+	type key struct {
+		coordinates source.Coordinates
+		virtualPath string
+	}
+	seen := make(map[key]struct{})
+	result := make([]source.Location, 0, len(locations)+len(others))
+	for _, l := range append(append([]source.Location{}, locations...), others...) {
+		k := key{coordinates: l.Coordinates, virtualPath: l.VirtualPath}
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		result = append(result, l)
+	}
+	return result
+}
+
+// mergeCPEs returns the union of the given CPEs (in order).
+func mergeCPEs(cpes, others []pkg.CPE) []pkg.CPE {
+	seen := make(map[string]struct{})
+	result := make([]pkg.CPE, 0, len(cpes)+len(others))
+	for _, c := range append(append([]pkg.CPE{}, cpes...), others...) {
+		k := c.BindToFmtString()
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		result = append(result, c)
+	}
+	return result
+}
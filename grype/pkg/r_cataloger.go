@@ -0,0 +1,90 @@
+package pkg
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/pkg/cataloger/common"
+)
+
+const rDescriptionCatalogerName = "r-description-cataloger"
+
+// rDescriptionGlob matches the DESCRIPTION file of each installed R package
+// (e.g. "/usr/local/lib/R/site-library/ggplot2/DESCRIPTION")
+const rDescriptionGlob = "**/DESCRIPTION"
+
+// newRDescriptionCataloger returns a cataloger for installed R packages (which syft does not catalog).
+func newRDescriptionCataloger() *common.GenericCataloger {
+	globParsers := map[string]common.ParserFn{
+		rDescriptionGlob: parseRDescription,
+	}
+
+	return common.NewGenericCataloger(nil, globParsers, rDescriptionCatalogerName)
+}
+
+// parseRDescription parses an R package DESCRIPTION file, which is in the debian control file format (DCF).
+// See https://cran.r-project.org/doc/manuals/r-release/R-exts.html#The-DESCRIPTION-file
+func parseRDescription(_ string, reader io.Reader) ([]*pkg.Package, []artifact.Relationship, error) {
+	fields, err := parseDCF(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse R DESCRIPTION file: %w", err)
+	}
+
+	name, version := fields["Package"], fields["Version"]
+	if name == "" || version == "" {
+		// other DESCRIPTION files may be found that do not describe an R package
+		return nil, nil, nil
+	}
+
+	var licenses []string
+	if license := fields["License"]; license != "" {
+		licenses = append(licenses, license)
+	}
+
+	return []*pkg.Package{
+		{
+			Name:     name,
+			Version:  version,
+			Licenses: licenses,
+			Language: R,
+			Type:     RPkg,
+			PURL:     fmt.Sprintf("pkg:cran/%s@%s", url.PathEscape(name), url.PathEscape(version)),
+		},
+	}, nil, nil
+}
+
+// parseDCF returns the fields from the first paragraph of a debian control file formatted document, joining any
+// continuation lines.
+func parseDCF(reader io.Reader) (map[string]string, error) {
+	fields := make(map[string]string)
+	var key string
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.TrimSpace(line) == "":
+			if len(fields) > 0 {
+				return fields, nil
+			}
+		case strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t"):
+			if key == "" {
+				return nil, fmt.Errorf("continuation line without a field: %q", line)
+			}
+			fields[key] += " " + strings.TrimSpace(line)
+		default:
+			i := strings.Index(line, ":")
+			if i < 0 {
+				return nil, fmt.Errorf("malformed field: %q", line)
+			}
+			key = strings.TrimSpace(line[:i])
+			fields[key] = strings.TrimSpace(line[i+1:])
+		}
+	}
+	return fields, scanner.Err()
+}
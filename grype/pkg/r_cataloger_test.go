@@ -0,0 +1,53 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestRDescriptionCataloger(t *testing.T) {
+	src, err := source.NewFromDirectory("test-fixtures/r")
+	require.NoError(t, err)
+
+	resolver, err := src.FileResolver(source.SquashedScope)
+	require.NoError(t, err)
+
+	packages, _, err := newRDescriptionCataloger().Catalog(resolver)
+	require.NoError(t, err)
+
+	type expectation struct {
+		version  string
+		licenses []string
+		purl     string
+	}
+
+	expected := map[string]expectation{
+		"ggplot2": {
+			version:  "3.3.5",
+			licenses: []string{"MIT + file LICENSE"},
+			purl:     "pkg:cran/ggplot2@3.3.5",
+		},
+		"Rcpp": {
+			version:  "1.0.7",
+			licenses: []string{"GPL (>= 2)"},
+			purl:     "pkg:cran/Rcpp@1.0.7",
+		},
+	}
+
+	require.Len(t, packages, len(expected))
+	for _, p := range packages {
+		t.Run(p.Name, func(t *testing.T) {
+			e, ok := expected[p.Name]
+			require.True(t, ok, "unexpected package: %s", p.Name)
+			assert.Equal(t, e.version, p.Version)
+			assert.Equal(t, e.licenses, p.Licenses)
+			assert.Equal(t, e.purl, p.PURL)
+			assert.Equal(t, RPkg, p.Type)
+			assert.Equal(t, R, p.Language)
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package pkg
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"os/exec"
+	"regexp"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/grype/internal/proxy"
+	"github.com/anchore/stereoscope/pkg/image"
+	"github.com/docker/docker-credential-helpers/client"
+	"github.com/docker/docker-credential-helpers/credentials"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/v1/google"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// RegistryAccessConfig configures every access to registries beyond the registry options of an access (e.g. the
+// credentials of the config): the credential helpers of registries, the ambient credentials of cloud registries, and the
+// certificate authorities of private registries.
+type RegistryAccessConfig struct {
+	// CredentialHelpers are the docker credential helpers of registries by host, where a helper is named by its suffix
+	// (e.g. "ecr-login" for docker-credential-ecr-login)
+	CredentialHelpers map[string]string
+	// CloudCredentials uses the credentials of the environment (e.g. of the instance role or workload identity) for
+	// AWS ECR, Google (GCR and Artifact Registry), and Azure ACR registries
+	CloudCredentials bool
+	// CACerts are the certificate authorities of registries (in addition to the system roots)
+	CACerts *x509.CertPool
+}
+
+var (
+	// dockerKeychain is the keychain of the docker config (and its credential helpers)
+	dockerKeychain = authn.DefaultKeychain
+	// registryCACerts are the configured certificate authorities of registries (if any)
+	registryCACerts *x509.CertPool
+
+	ecrRegistry = regexp.MustCompile(`^\d{12}\.dkr\.ecr(-fips)?\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+	acrRegistry = regexp.MustCompile(`^[a-z0-9]+\.azurecr\.(io|cn|us)$`)
+)
+
+// ConfigureRegistryAccess applies the given config to every registry access, including the images pulled by
+// stereoscope (which uses the default keychain without configured credentials, and the default transport of
+// go-containerregistry unless TLS verification is skipped), where registries are accessed through the configured proxy
+// (see proxy.Configure). Credentials are resolved from the credential helper of the
+// registry, then the docker config, and then the credentials of the cloud environment.
+func ConfigureRegistryAccess(config RegistryAccessConfig) {
+	var keychains []authn.Keychain
+	if len(config.CredentialHelpers) > 0 {
+		keychains = append(keychains, helperKeychain{helpers: config.CredentialHelpers})
+	}
+	keychains = append(keychains, dockerKeychain)
+	if config.CloudCredentials {
+		keychains = append(keychains, cloudKeychain{})
+	}
+	authn.DefaultKeychain = authn.NewMultiKeychain(keychains...)
+
+	remote.DefaultTransport.Proxy = proxy.ForRequest
+	registryCACerts = config.CACerts
+	if config.CACerts != nil {
+		remote.DefaultTransport.TLSClientConfig = &tls.Config{RootCAs: config.CACerts} //nolint:gosec // the minimum version is the default of the client
+	}
+}
+
+// registryTransport returns the transport of registry requests, which trusts the configured certificate authorities.
+func registryTransport(registryOptions *image.RegistryOptions) http.RoundTripper {
+	if !registryOptions.InsecureSkipTLSVerify && registryCACerts == nil {
+		return http.DefaultTransport
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{ //nolint:gosec // explicitly configured
+		InsecureSkipVerify: registryOptions.InsecureSkipTLSVerify,
+		RootCAs:            registryCACerts,
+	}
+	return transport
+}
+
+// helperKeychain resolves the credentials of registries from their configured credential helpers.
+type helperKeychain struct {
+	helpers map[string]string
+}
+
+func (k helperKeychain) Resolve(resource authn.Resource) (authn.Authenticator, error) {
+	helper, ok := k.helpers[resource.RegistryStr()]
+	if !ok {
+		return authn.Anonymous, nil
+	}
+	return helperAuthenticator(helper, resource.RegistryStr()), nil
+}
+
+// cloudKeychain resolves the credentials of cloud registries from the environment: with the credential helpers of
+// AWS ECR (docker-credential-ecr-login) and Azure ACR (docker-credential-acr-env), and with the application default
+// credentials (or gcloud) for Google registries.
+type cloudKeychain struct{}
+
+func (cloudKeychain) Resolve(resource authn.Resource) (authn.Authenticator, error) {
+	registry := resource.RegistryStr()
+	switch {
+	case ecrRegistry.MatchString(registry):
+		return helperAuthenticator("ecr-login", registry), nil
+	case acrRegistry.MatchString(registry):
+		return helperAuthenticator("acr-env", registry), nil
+	}
+
+	authenticator, err := google.Keychain.Resolve(resource)
+	if err != nil {
+		log.Warnf("unable to get the Google credentials of registry=%q: %+v", registry, err)
+		return authn.Anonymous, nil
+	}
+	return authenticator, nil
+}
+
+// helperAuthenticator returns the credentials of the registry from the given docker credential helper, where the
+// registry is accessed anonymously when the helper is not installed or has no credentials.
+func helperAuthenticator(helper, registry string) authn.Authenticator {
+	program := "docker-credential-" + helper
+	if _, err := exec.LookPath(program); err != nil {
+		log.Warnf("unable to get the credentials of registry=%q: the credential helper %q is not installed", registry, program)
+		return authn.Anonymous
+	}
+
+	creds, err := client.Get(client.NewShellProgramFunc(program), registry)
+	switch {
+	case credentials.IsErrCredentialsNotFound(err):
+		log.Debugf("the credential helper %q has no credentials of registry=%q", program, registry)
+		return authn.Anonymous
+	case err != nil:
+		log.Warnf("unable to get the credentials of registry=%q from %q: %+v", registry, program, err)
+		return authn.Anonymous
+	}
+	log.Debugf("using the credentials of registry=%q from %q", registry, program)
+
+	// note: this is how credential helpers return identity tokens (as by the docker CLI)
+	if creds.Username == "<token>" {
+		return authn.FromConfig(authn.AuthConfig{IdentityToken: creds.Secret})
+	}
+	return authn.FromConfig(authn.AuthConfig{Username: creds.Username, Password: creds.Secret})
+}
@@ -0,0 +1,125 @@
+package pkg
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anchore/stereoscope/pkg/image"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// resetRegistryAccess restores the registry access of before the test
+func resetRegistryAccess(t *testing.T) {
+	keychain, tlsConfig := authn.DefaultKeychain, remote.DefaultTransport.TLSClientConfig
+	t.Cleanup(func() {
+		authn.DefaultKeychain, remote.DefaultTransport.TLSClientConfig, registryCACerts = keychain, tlsConfig, nil
+	})
+}
+
+func TestConfigureRegistryAccess_CredentialHelpers(t *testing.T) {
+	resetRegistryAccess(t)
+
+	// a credential helper that has the credentials of a single registry
+	dir := t.TempDir()
+	helper := `#!/bin/sh
+read registry
+if [ "$registry" = "registry.example.com" ]; then
+  echo '{"ServerURL":"registry.example.com","Username":"user","Secret":"pass"}'
+else
+  echo "credentials not found in native keychain"
+  exit 1
+fi
+`
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "docker-credential-test"), []byte(helper), 0755))
+	path := os.Getenv("PATH")
+	require.NoError(t, os.Setenv("PATH", dir+string(os.PathListSeparator)+path))
+	defer os.Setenv("PATH", path)
+
+	ConfigureRegistryAccess(RegistryAccessConfig{CredentialHelpers: map[string]string{
+		"registry.example.com": "test",
+		"other.example.com":    "test",
+		"missing.example.com":  "missing",
+	}})
+
+	tests := []struct {
+		registry string
+		expected authn.AuthConfig
+	}{
+		{registry: "registry.example.com", expected: authn.AuthConfig{Username: "user", Password: "pass"}},
+		{registry: "other.example.com"},
+		{registry: "missing.example.com"},
+		{registry: "unconfigured.example.com"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.registry, func(t *testing.T) {
+			reg, err := name.NewRegistry(test.registry)
+			require.NoError(t, err)
+			authenticator, err := authn.DefaultKeychain.Resolve(reg)
+			require.NoError(t, err)
+			config, err := authenticator.Authorization()
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, *config)
+		})
+	}
+}
+
+func TestConfigureRegistryAccess_CACerts(t *testing.T) {
+	resetRegistryAccess(t)
+
+	server := httptest.NewTLSServer(registry.New())
+	defer server.Close()
+	reference := fmt.Sprintf("%s/app:latest", strings.TrimPrefix(server.URL, "https://"))
+	ref, err := name.ParseReference(reference)
+	require.NoError(t, err)
+	img, err := random.Image(64, 1)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, img, remote.WithTransport(server.Client().Transport)))
+
+	// the certificate of the registry is not trusted
+	_, err = remote.Head(ref, remoteOptions(ref, &image.RegistryOptions{})...)
+	assert.Error(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	ConfigureRegistryAccess(RegistryAccessConfig{CACerts: roots})
+
+	_, err = remote.Head(ref, remoteOptions(ref, &image.RegistryOptions{})...)
+	assert.NoError(t, err)
+	// as when pulling images, without an explicit transport
+	_, err = remote.Head(ref)
+	assert.NoError(t, err)
+}
+
+func TestCloudRegistries(t *testing.T) {
+	tests := []struct {
+		registry string
+		ecr, acr bool
+	}{
+		{registry: "123456789012.dkr.ecr.us-east-1.amazonaws.com", ecr: true},
+		{registry: "123456789012.dkr.ecr-fips.us-gov-west-1.amazonaws.com", ecr: true},
+		{registry: "123456789012.dkr.ecr.cn-north-1.amazonaws.com.cn", ecr: true},
+		{registry: "public.ecr.aws"},
+		{registry: "myregistry.azurecr.io", acr: true},
+		{registry: "ghcr.io"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.registry, func(t *testing.T) {
+			assert.Equal(t, test.ecr, ecrRegistry.MatchString(test.registry))
+			assert.Equal(t, test.acr, acrRegistry.MatchString(test.registry))
+		})
+	}
+}
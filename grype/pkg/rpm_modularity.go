@@ -0,0 +1,209 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/anchore/go-rpmdb/pkg/bdb"
+	"github.com/anchore/grype/internal"
+	"github.com/anchore/grype/internal/log"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+// rpm header tags and tag types that are needed to identify the module stream of a package (see
+// https://github.com/rpm-software-management/rpm/blob/master/include/rpm/rpmtag.h)
+const (
+	rpmTagName            = 1000
+	rpmTagVersion         = 1001
+	rpmTagRelease         = 1002
+	rpmTagEpoch           = 1003
+	rpmTagArch            = 1022
+	rpmTagModularityLabel = 5096
+
+	rpmTypeInt32  = 4
+	rpmTypeString = 6
+)
+
+// rpmHeader is the subset of an rpm header that identifies a package and the module stream it was built for.
+type rpmHeader struct {
+	name            string
+	version         string
+	release         string
+	epoch           *int
+	arch            string
+	modularityLabel string
+}
+
+// key returns the name and version of the package in the same form as the rpmdb cataloger (e.g. "perl@4:5.26.3-419.el8").
+func (h rpmHeader) key() string {
+	if h.epoch != nil {
+		return fmt.Sprintf("%s@%d:%s-%s", h.name, *h.epoch, h.version, h.release)
+	}
+	return fmt.Sprintf("%s@%s-%s", h.name, h.version, h.release)
+}
+
+// addRpmModularityLabels sets the module stream label of each rpm package from the rpmdb the package was found in, which
+// the rpmdb cataloger does not capture.
+func addRpmModularityLabels(resolver source.FileResolver, packages []Package) {
+	labelsByDB := make(map[string]map[string]string)
+	for i, p := range packages {
+		metadata, ok := p.Metadata.(RpmdbMetadata)
+		if p.Type != syftPkg.RpmPkg || !ok {
+			continue
+		}
+
+		for _, location := range p.Locations {
+			labels, ok := labelsByDB[location.RealPath]
+			if !ok {
+				var err error
+				labels, err = rpmModularityLabels(resolver, location)
+				if err != nil {
+					log.Debugf("unable to read rpm modularity labels from %q: %+v", location.RealPath, err)
+				}
+				labelsByDB[location.RealPath] = labels
+			}
+
+			if label, ok := labels[p.Name+"@"+p.Version]; ok {
+				metadata.ModularityLabel = &label
+				packages[i].Metadata = metadata
+				break
+			}
+		}
+	}
+}
+
+// rpmModularityLabels returns the module stream label (which is empty for packages that are not part of a module
+// stream) of every package within the given rpmdb, keyed by the name and version of the package.
+func rpmModularityLabels(resolver source.FileResolver, location source.Location) (map[string]string, error) {
+	reader, err := resolver.FileContentsByLocation(location)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			log.Warnf("unable to close rpmdb reader: %+v", err)
+		}
+	}()
+
+	f, err := ioutil.TempFile("", internal.ApplicationName+"-rpmdb")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp rpmdb file: %w", err)
+	}
+	defer func() {
+		if err := os.Remove(f.Name()); err != nil {
+			log.Warnf("failed to remove temp rpmdb file: %+v", err)
+		}
+	}()
+
+	_, err = io.Copy(f, reader)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy rpmdb contents to temp file: %w", err)
+	}
+
+	db, err := bdb.Open(f.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make(map[string]string)
+	var readErr error
+	for entry := range db.Read() {
+		// note: all entries must be read, otherwise the reader will not complete
+		if entry.Err != nil {
+			readErr = entry.Err
+			continue
+		}
+		if readErr != nil {
+			continue
+		}
+
+		header, err := parseRpmHeader(entry.Value)
+		if err != nil {
+			readErr = err
+			continue
+		}
+		labels[header.key()] = header.modularityLabel
+	}
+
+	return labels, readErr
+}
+
+// parseRpmHeader parses the identifying fields of an rpm header blob as stored within an rpmdb (see
+// https://github.com/rpm-software-management/rpm/blob/master/lib/header.c).
+func parseRpmHeader(data []byte) (rpmHeader, error) {
+	var il, dl int32
+	reader := bytes.NewReader(data)
+	if err := binary.Read(reader, binary.BigEndian, &il); err != nil {
+		return rpmHeader{}, fmt.Errorf("invalid index length: %w", err)
+	}
+	if err := binary.Read(reader, binary.BigEndian, &dl); err != nil {
+		return rpmHeader{}, fmt.Errorf("invalid data length: %w", err)
+	}
+
+	dataStart := 8 + int(il)*16
+	if il < 0 || dl < 0 || dataStart+int(dl) > len(data) {
+		return rpmHeader{}, fmt.Errorf("invalid header lengths (index=%d data=%d size=%d)", il, dl, len(data))
+	}
+	store := data[dataStart : dataStart+int(dl)]
+
+	var header rpmHeader
+	for i := 0; i < int(il); i++ {
+		var entry struct {
+			Tag    int32
+			Type   uint32
+			Offset int32
+			Count  uint32
+		}
+		if err := binary.Read(reader, binary.BigEndian, &entry); err != nil {
+			return rpmHeader{}, fmt.Errorf("invalid index entry: %w", err)
+		}
+
+		// region tags (and any other entry outside of the data store) do not describe the package
+		if entry.Offset < 0 || int(entry.Offset) >= len(store) {
+			continue
+		}
+		value := store[entry.Offset:]
+
+		switch {
+		case entry.Type == rpmTypeString:
+			str := rpmString(value)
+			switch entry.Tag {
+			case rpmTagName:
+				header.name = str
+			case rpmTagVersion:
+				header.version = str
+			case rpmTagRelease:
+				header.release = str
+			case rpmTagArch:
+				header.arch = str
+			case rpmTagModularityLabel:
+				header.modularityLabel = str
+			}
+		case entry.Type == rpmTypeInt32 && entry.Tag == rpmTagEpoch && len(value) >= 4:
+			epoch := int(int32(binary.BigEndian.Uint32(value)))
+			header.epoch = &epoch
+		}
+	}
+
+	if header.name == "" {
+		return rpmHeader{}, fmt.Errorf("header has no package name")
+	}
+
+	return header, nil
+}
+
+// rpmString returns the null terminated string at the start of the given value.
+func rpmString(value []byte) string {
+	if idx := bytes.IndexByte(value, 0); idx >= 0 {
+		return string(value[:idx])
+	}
+	return string(value)
+}
@@ -0,0 +1,142 @@
+package pkg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type rpmHeaderEntry struct {
+	tag   int32
+	typ   uint32
+	value []byte
+}
+
+func rpmStringEntry(tag int32, value string) rpmHeaderEntry {
+	return rpmHeaderEntry{tag: tag, typ: rpmTypeString, value: append([]byte(value), 0)}
+}
+
+func rpmInt32Entry(tag int32, value int32) rpmHeaderEntry {
+	data := make([]byte, 4)
+	binary.BigEndian.PutUint32(data, uint32(value))
+	return rpmHeaderEntry{tag: tag, typ: rpmTypeInt32, value: data}
+}
+
+// rpmHeaderBlob creates a header blob as stored within an rpmdb, including the leading region tag.
+func rpmHeaderBlob(t *testing.T, entries ...rpmHeaderEntry) []byte {
+	t.Helper()
+
+	var index, store bytes.Buffer
+	writeIndex := func(tag int32, typ uint32, offset int32, count uint32) {
+		for _, v := range []interface{}{tag, typ, offset, count} {
+			require.NoError(t, binary.Write(&index, binary.BigEndian, v))
+		}
+	}
+
+	// the region tag references the trailer at the end of the data store
+	writeIndex(63, 7, -1, 16)
+	for _, e := range entries {
+		writeIndex(e.tag, e.typ, int32(store.Len()), 1)
+		store.Write(e.value)
+	}
+
+	var blob bytes.Buffer
+	require.NoError(t, binary.Write(&blob, binary.BigEndian, int32(len(entries)+1)))
+	require.NoError(t, binary.Write(&blob, binary.BigEndian, int32(store.Len())))
+	blob.Write(index.Bytes())
+	blob.Write(store.Bytes())
+	return blob.Bytes()
+}
+
+func TestParseRpmHeader(t *testing.T) {
+	tests := []struct {
+		name        string
+		blob        func(t *testing.T) []byte
+		expected    rpmHeader
+		expectedKey string
+		wantErr     require.ErrorAssertionFunc
+	}{
+		{
+			name: "modular package with epoch",
+			blob: func(t *testing.T) []byte {
+				return rpmHeaderBlob(t,
+					rpmStringEntry(rpmTagName, "nodejs"),
+					rpmStringEntry(rpmTagVersion, "12.21.0"),
+					rpmStringEntry(rpmTagRelease, "1.module_el8.3.0+623+1a3e2e4c"),
+					rpmInt32Entry(rpmTagEpoch, 1),
+					rpmStringEntry(rpmTagArch, "x86_64"),
+					rpmStringEntry(rpmTagModularityLabel, "nodejs:12:8030020210304194401:229f0a1c"),
+				)
+			},
+			expected: rpmHeader{
+				name:            "nodejs",
+				version:         "12.21.0",
+				release:         "1.module_el8.3.0+623+1a3e2e4c",
+				epoch:           intRef(1),
+				arch:            "x86_64",
+				modularityLabel: "nodejs:12:8030020210304194401:229f0a1c",
+			},
+			expectedKey: "nodejs@1:12.21.0-1.module_el8.3.0+623+1a3e2e4c",
+		},
+		{
+			name: "non-modular package without epoch",
+			blob: func(t *testing.T) []byte {
+				return rpmHeaderBlob(t,
+					rpmStringEntry(rpmTagName, "bash"),
+					rpmStringEntry(rpmTagVersion, "4.4.19"),
+					rpmStringEntry(rpmTagRelease, "14.el8"),
+					rpmStringEntry(rpmTagArch, "x86_64"),
+				)
+			},
+			expected: rpmHeader{
+				name:    "bash",
+				version: "4.4.19",
+				release: "14.el8",
+				arch:    "x86_64",
+			},
+			expectedKey: "bash@4.4.19-14.el8",
+		},
+		{
+			name: "header without a name",
+			blob: func(t *testing.T) []byte {
+				return rpmHeaderBlob(t, rpmStringEntry(rpmTagVersion, "4.4.19"))
+			},
+			wantErr: require.Error,
+		},
+		{
+			name: "truncated header",
+			blob: func(t *testing.T) []byte {
+				blob := rpmHeaderBlob(t, rpmStringEntry(rpmTagName, "bash"))
+				return blob[:len(blob)-2]
+			},
+			wantErr: require.Error,
+		},
+		{
+			name: "empty header",
+			blob: func(t *testing.T) []byte {
+				return nil
+			},
+			wantErr: require.Error,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.wantErr == nil {
+				test.wantErr = require.NoError
+			}
+
+			actual, err := parseRpmHeader(test.blob(t))
+			test.wantErr(t, err)
+			if err != nil {
+				return
+			}
+
+			assert.Equal(t, test.expected, actual)
+			assert.Equal(t, test.expectedKey, actual.key())
+		})
+	}
+}
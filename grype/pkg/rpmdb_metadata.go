@@ -3,4 +3,7 @@ package pkg
 type RpmdbMetadata struct {
 	SourceRpm string
 	Epoch     *int
+	// ModularityLabel is the module stream label of the package (e.g. "nodejs:12:8030020201124152102:229f0a1c"), which
+	// is empty for packages that are not part of a module stream and nil when unknown.
+	ModularityLabel *string
 }
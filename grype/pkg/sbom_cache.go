@@ -0,0 +1,126 @@
+package pkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anchore/grype/grype/metrics"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft"
+	"github.com/anchore/syft/syft/format"
+	"github.com/anchore/syft/syft/linux"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/anchore/syft/syft/source"
+)
+
+// SBOMCacheConfig controls the (opt-in) caching of the packages cataloged by syft for each image digest, so that
+// scans of an image that was scanned before skip cataloging.
+type SBOMCacheConfig struct {
+	Enabled bool
+	Dir     string
+}
+
+type sbomCache struct {
+	config SBOMCacheConfig
+}
+
+func newSBOMCache(config SBOMCacheConfig) *sbomCache {
+	return &sbomCache{config: config}
+}
+
+// key identifies the SBOM of the given source, which is only cached for images with a known digest. The search scope
+// and the exclusions are part of the key since they change the cataloged packages.
+func (c *sbomCache) key(src source.Metadata, config ProviderConfig) string {
+	if !c.config.Enabled || c.config.Dir == "" || src.Scheme != source.ImageScheme {
+		return ""
+	}
+	digest := src.ImageMetadata.ManifestDigest
+	if digest == "" {
+		digest = src.ImageMetadata.ID
+	}
+	if digest == "" {
+		return ""
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join(append([]string{
+		digest,
+		string(config.CatalogingOptions.Search.Scope),
+	}, config.Exclusions...), "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+func (c *sbomCache) path(key string) string {
+	return filepath.Join(c.config.Dir, key+".json")
+}
+
+// read returns the cached catalog and distro of the given source (where a nil catalog indicates there is none).
+func (c *sbomCache) read(src source.Metadata, config ProviderConfig) (*pkg.Catalog, *linux.Release) {
+	key := c.key(src, config)
+	if key == "" {
+		return nil, nil
+	}
+	catalog, theDistro := c.readEntry(key, src)
+	metrics.ObserveCache(metrics.SBOMCache, catalog != nil)
+	return catalog, theDistro
+}
+
+func (c *sbomCache) readEntry(key string, src source.Metadata) (*pkg.Catalog, *linux.Release) {
+	contents, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, nil
+	}
+	cached, _, err := syft.Decode(bytes.NewReader(contents))
+	if err != nil || cached.Artifacts.PackageCatalog == nil {
+		log.Debugf("ignoring invalid SBOM cache entry for %s: %+v", src.ImageMetadata.UserInput, err)
+		return nil, nil
+	}
+	log.Debugf("using cached SBOM for image=%q", src.ImageMetadata.UserInput)
+	return cached.Artifacts.PackageCatalog, cached.Artifacts.LinuxDistribution
+}
+
+// write caches the catalog and distro of the given source.
+func (c *sbomCache) write(src source.Metadata, config ProviderConfig, catalog *pkg.Catalog, theDistro *linux.Release) {
+	key := c.key(src, config)
+	if key == "" {
+		return
+	}
+	contents, err := syft.Encode(sbom.SBOM{
+		Artifacts: sbom.Artifacts{
+			PackageCatalog:    catalog,
+			LinuxDistribution: theDistro,
+		},
+		Source: src,
+	}, format.JSONOption)
+	if err != nil {
+		log.Debugf("unable to encode SBOM cache entry: %+v", err)
+		return
+	}
+	if err := os.MkdirAll(c.config.Dir, 0755); err != nil {
+		log.Debugf("unable to create SBOM cache dir: %+v", err)
+		return
+	}
+	if err := ioutil.WriteFile(c.path(key), contents, 0600); err != nil {
+		log.Debugf("unable to write SBOM cache entry: %+v", err)
+	}
+}
+
+// catalogWithCache catalogs the packages of the given source with syft, unless the SBOM of the source is cached.
+func catalogWithCache(src *source.Source, config ProviderConfig) (*pkg.Catalog, *linux.Release, error) {
+	cache := newSBOMCache(config.SBOMCache)
+	if catalog, theDistro := cache.read(src.Metadata, config); catalog != nil {
+		return catalog, theDistro, nil
+	}
+
+	catalog, _, theDistro, err := syft.CatalogPackages(src, config.CatalogingOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	cache.write(src.Metadata, config, catalog, theDistro)
+	return catalog, theDistro, nil
+}
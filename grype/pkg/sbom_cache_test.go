@@ -0,0 +1,98 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/anchore/syft/syft/linux"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSBOMCache_Key(t *testing.T) {
+	image := source.Metadata{
+		Scheme: source.ImageScheme,
+		ImageMetadata: source.ImageMetadata{
+			UserInput:      "alpine:latest",
+			ID:             "sha256:id",
+			ManifestDigest: "sha256:manifest",
+		},
+	}
+	config := ProviderConfig{}
+	config.CatalogingOptions.Search.Scope = source.SquashedScope
+
+	cache := newSBOMCache(SBOMCacheConfig{Enabled: true, Dir: t.TempDir()})
+	key := cache.key(image, config)
+	assert.NotEmpty(t, key)
+
+	otherImage := image
+	otherImage.ImageMetadata.ManifestDigest = "sha256:other"
+	assert.NotEqual(t, key, cache.key(otherImage, config), "digest")
+
+	otherScope := config
+	otherScope.CatalogingOptions.Search.Scope = source.AllLayersScope
+	assert.NotEqual(t, key, cache.key(image, otherScope), "scope")
+
+	otherExclusions := config
+	otherExclusions.Exclusions = []string{"/usr/**"}
+	assert.NotEqual(t, key, cache.key(image, otherExclusions), "exclusions")
+
+	noManifestDigest := image
+	noManifestDigest.ImageMetadata.ManifestDigest = ""
+	assert.NotEmpty(t, cache.key(noManifestDigest, config), "falls back to the image ID")
+
+	noDigest := noManifestDigest
+	noDigest.ImageMetadata.ID = ""
+	assert.Empty(t, cache.key(noDigest, config))
+
+	assert.Empty(t, cache.key(source.Metadata{Scheme: source.DirectoryScheme, Path: "/"}, config))
+	assert.Empty(t, newSBOMCache(SBOMCacheConfig{Dir: t.TempDir()}).key(image, config), "disabled")
+}
+
+func TestSBOMCache_ReadWrite(t *testing.T) {
+	image := source.Metadata{
+		Scheme: source.ImageScheme,
+		ImageMetadata: source.ImageMetadata{
+			UserInput:      "alpine:latest",
+			ManifestDigest: "sha256:manifest",
+		},
+	}
+	config := ProviderConfig{}
+	config.CatalogingOptions.Search.Scope = source.SquashedScope
+	cache := newSBOMCache(SBOMCacheConfig{Enabled: true, Dir: t.TempDir()})
+
+	catalog, theDistro := cache.read(image, config)
+	assert.Nil(t, catalog, "nothing cached yet")
+	assert.Nil(t, theDistro)
+
+	cache.write(image, config, syftPkg.NewCatalog(syftPkg.Package{
+		Name:     "musl",
+		Version:  "1.2.2-r3",
+		Type:     syftPkg.ApkPkg,
+		PURL:     "pkg:alpine/musl@1.2.2-r3",
+		Language: syftPkg.UnknownLanguage,
+		Licenses: []string{"MIT"},
+		Locations: []source.Location{
+			source.NewLocationFromCoordinates(source.Coordinates{RealPath: "/lib/apk/db/installed", FileSystemID: "sha256:layer"}),
+		},
+		MetadataType: syftPkg.ApkMetadataType,
+		Metadata:     syftPkg.ApkMetadata{Package: "musl", Version: "1.2.2-r3", OriginPackage: "musl"},
+	}), &linux.Release{ID: "alpine", VersionID: "3.14.2"})
+
+	catalog, theDistro = cache.read(image, config)
+	require.NotNil(t, catalog)
+	require.Equal(t, 1, catalog.PackageCount())
+	p := catalog.Sorted()[0]
+	assert.Equal(t, "musl", p.Name)
+	assert.Equal(t, "1.2.2-r3", p.Version)
+	assert.Equal(t, "sha256:layer", p.Locations[0].FileSystemID)
+	assert.Equal(t, "musl", p.Metadata.(syftPkg.ApkMetadata).OriginPackage)
+	require.NotNil(t, theDistro)
+	assert.Equal(t, "3.14.2", theDistro.VersionID)
+
+	otherScope := config
+	otherScope.CatalogingOptions.Search.Scope = source.AllLayersScope
+	catalog, _ = cache.read(image, otherScope)
+	assert.Nil(t, catalog, "cached for another scope")
+}
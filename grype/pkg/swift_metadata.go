@@ -0,0 +1,5 @@
+package pkg
+
+type SwiftMetadata struct {
+	RepositoryURL string // the normalized repository URL (e.g. "github.com/apple/swift-nio")
+}
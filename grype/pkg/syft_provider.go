@@ -1,28 +1,159 @@
 package pkg
 
 import (
-	"github.com/anchore/syft/syft"
+	"context"
+	"fmt"
+	"path"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/anchore/grype/internal/tracing"
+	"github.com/anchore/syft/syft/linux"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/pkg/cataloger"
+	"github.com/anchore/syft/syft/pkg/cataloger/common/cpe"
 	"github.com/anchore/syft/syft/source"
 )
 
-func syftProvider(userInput string, config ProviderConfig) ([]Package, Context, error) {
+func syftProvider(spanCtx context.Context, userInput string, config ProviderConfig) ([]Package, Context, error) {
 	if config.CatalogingOptions.Search.Scope == "" {
 		return nil, Context{}, errDoesNotProvide
 	}
 
+	_, span := tracing.Start(spanCtx, "read source")
 	src, cleanup, err := source.New(userInput, config.RegistryOptions, config.Exclusions)
+	if err == nil {
+		span.SetAttributes(attribute.String("grype.source.scheme", string(src.Metadata.Scheme)))
+		if src.Metadata.Scheme == source.ImageScheme {
+			// note: images are pulled (or exported from the daemon) when the source is created
+			span.SetName("pull image")
+			span.SetAttributes(
+				attribute.String("grype.image", src.Metadata.ImageMetadata.UserInput),
+				attribute.String("grype.image.digest", src.Metadata.ImageMetadata.ManifestDigest),
+				attribute.Int64("grype.image.size", src.Metadata.ImageMetadata.Size),
+			)
+		}
+	}
+	tracing.End(span, err)
 	if err != nil {
 		return nil, Context{}, err
 	}
 	defer cleanup()
 
-	catalog, _, theDistro, err := syft.CatalogPackages(src, config.CatalogingOptions)
+	_, span = tracing.Start(spanCtx, "catalog", attribute.String("grype.source.scheme", string(src.Metadata.Scheme)))
+	packages, theDistro, err := catalogSource(src, config)
+	if err == nil {
+		span.SetAttributes(attribute.Int("grype.packages", len(packages)))
+	}
+	tracing.End(span, err)
 	if err != nil {
 		return nil, Context{}, err
 	}
 
-	return FromCatalog(catalog), Context{
+	return packages, Context{
 		Source: &src.Metadata,
 		Distro: theDistro,
 	}, nil
 }
+
+// catalogSource catalogs the packages of the given source, with syft and the catalogers of grype.
+func catalogSource(src *source.Source, config ProviderConfig) ([]Package, *linux.Release, error) {
+	catalog, theDistro, err := catalogWithCache(src, config)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = catalogAdditionalPackages(src, config, catalog); err != nil {
+		return nil, nil, err
+	}
+
+	if theDistro == nil {
+		// windows filesystems have no linux release, however, the windows release is needed for MSRC matching
+		theDistro = windowsRelease(catalog)
+	}
+
+	packages := FromCatalogWithConfig(catalog, config.Catalog)
+
+	resolver, err := src.FileResolver(config.CatalogingOptions.Search.Scope)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to determine resolver while reading rpm modularity labels: %w", err)
+	}
+	addRpmModularityLabels(resolver, packages)
+	addDependencyScopes(resolver, packages)
+	packages = append(packages, shadedJavaPackages(resolver, packages)...)
+	if config.MavenSearch.Enabled {
+		addMavenCoordinates(resolver, newMavenSearcher(config.MavenSearch), packages)
+	}
+
+	return packages, theDistro, nil
+}
+
+// catalogAdditionalPackages adds packages from ecosystems that syft does not catalog to the given catalog.
+func catalogAdditionalPackages(src *source.Source, config ProviderConfig, catalog *pkg.Catalog) error {
+	resolver, err := src.FileResolver(config.CatalogingOptions.Search.Scope)
+	if err != nil {
+		return fmt.Errorf("unable to determine resolver while cataloging packages: %w", err)
+	}
+
+	owned := ownedFiles(catalog)
+	for _, c := range additionalCatalogers() {
+		packages, _, err := c.Catalog(resolver)
+		if err != nil {
+			return fmt.Errorf("unable to catalog packages with %q: %w", c.Name(), err)
+		}
+
+		for _, p := range packages {
+			if p.Type == BinaryPkg && ownedByPackage(owned, p) {
+				// the binary was installed by a package manager, so it is already matched by that package
+				continue
+			}
+			// note: CPEs are excluded from the package ID, so are safe to mutate
+			if len(p.CPEs) == 0 {
+				p.CPEs = cpe.Generate(p)
+			}
+			catalog.Add(p)
+		}
+	}
+	return nil
+}
+
+func additionalCatalogers() []cataloger.Cataloger {
+	return []cataloger.Cataloger{
+		newCondaCataloger(),
+		newRDescriptionCataloger(),
+		newHaskellCataloger(),
+		newBitnamiCataloger(),
+		newLinuxKernelCataloger(),
+		newBinaryCataloger(),
+		newHomebrewCataloger(),
+		newWindowsCataloger(),
+		newChocolateyCataloger(),
+	}
+}
+
+// ownedFiles returns all paths claimed by the package manager metadata of the given packages.
+func ownedFiles(catalog *pkg.Catalog) map[string]struct{} {
+	owned := make(map[string]struct{})
+	for p := range catalog.Enumerate() {
+		if owner, ok := p.Metadata.(pkg.FileOwner); ok {
+			for _, f := range owner.OwnedFiles() {
+				owned[f] = struct{}{}
+			}
+		}
+	}
+	return owned
+}
+
+// ownedByPackage indicates if all locations of the given package are owned by another package.
+func ownedByPackage(owned map[string]struct{}, p pkg.Package) bool {
+	if len(p.Locations) == 0 {
+		return false
+	}
+	for _, l := range p.Locations {
+		// note: paths are relative to the root of a scanned directory, but package managers claim absolute paths
+		if _, ok := owned[path.Join("/", l.RealPath)]; !ok {
+			return false
+		}
+	}
+	return true
+}
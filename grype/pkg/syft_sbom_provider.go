@@ -18,7 +18,7 @@ import (
 	"github.com/mitchellh/go-homedir"
 )
 
-func syftSBOMProvider(userInput string) ([]Package, Context, error) {
+func syftSBOMProvider(userInput string, config ProviderConfig) ([]Package, Context, error) {
 	reader, err := getSBOMReader(userInput)
 	if err != nil {
 		return nil, Context{}, err
@@ -32,7 +32,7 @@ func syftSBOMProvider(userInput string) ([]Package, Context, error) {
 		return nil, Context{}, errDoesNotProvide
 	}
 
-	return FromCatalog(sbom.Artifacts.PackageCatalog), Context{
+	return FromCatalogWithConfig(sbom.Artifacts.PackageCatalog, config.Catalog), Context{
 		Source: &sbom.Source,
 		Distro: sbom.Artifacts.LinuxDistribution,
 	}, nil
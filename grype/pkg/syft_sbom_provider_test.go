@@ -45,6 +45,7 @@ func TestParseSyftJSON(t *testing.T) {
 						"GPL-2.0-only",
 					},
 					Type: "rpm",
+					Arch: "x86_64",
 					CPEs: []pkg.CPE{
 						must(pkg.NewCPE("cpe:2.3:a:*:alpine-baselayout:3.2.0-r6:*:*:*:*:*:*:*")),
 						must(pkg.NewCPE("cpe:2.3:a:alpine-baselayout:alpine-baselayout:3.2.0-r6:*:*:*:*:*:*:*")),
@@ -66,11 +67,17 @@ func TestParseSyftJSON(t *testing.T) {
 						"LGPL-3.0-or-later",
 					},
 					Type: "dpkg",
+					Arch: "x86_64",
 					CPEs: []pkg.CPE{
 						must(pkg.NewCPE("cpe:2.3:a:*:gmp:6.2.0-r0:*:*:*:*:*:*:*")),
 						must(pkg.NewCPE("cpe:2.3:a:gmp:gmp:6.2.0-r0:*:*:*:*:*:*:*")),
 					},
-					PURL:     "pkg:alpine/gmp@6.2.0-r0?arch=x86_64",
+					PURL: "pkg:alpine/gmp@6.2.0-r0?arch=x86_64",
+					Upstreams: []UpstreamPackage{
+						{
+							Name: "a-source",
+						},
+					},
 					Metadata: DpkgMetadata{Source: "a-source"},
 				},
 				{
@@ -87,6 +94,7 @@ func TestParseSyftJSON(t *testing.T) {
 						"LGPL-3.0-or-later",
 					},
 					Type: "java-archive",
+					Arch: "x86_64",
 					CPEs: []pkg.CPE{
 						must(pkg.NewCPE("cpe:2.3:a:*:gmp:6.2.0-r0:*:*:*:*:*:*:*")),
 						must(pkg.NewCPE("cpe:2.3:a:gmp:gmp:6.2.0-r0:*:*:*:*:*:*:*")),
@@ -134,7 +142,7 @@ func TestParseSyftJSON(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.Fixture, func(t *testing.T) {
-			pkgs, context, err := syftSBOMProvider(test.Fixture)
+			pkgs, context, err := syftSBOMProvider(test.Fixture, ProviderConfig{})
 			if err != nil {
 				t.Fatalf("unable to parse: %+v", err)
 			}
@@ -163,7 +171,7 @@ func TestParseSyftJSON(t *testing.T) {
 }
 
 func TestParseSyftJSON_BadCPEs(t *testing.T) {
-	pkgs, _, err := syftSBOMProvider("test-fixtures/syft-java-bad-cpes.json")
+	pkgs, _, err := syftSBOMProvider("test-fixtures/syft-java-bad-cpes.json", ProviderConfig{})
 	assert.NoError(t, err)
 	assert.Len(t, pkgs, 1)
 }
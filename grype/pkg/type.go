@@ -0,0 +1,191 @@
+package pkg
+
+import (
+	"net/url"
+	"strings"
+
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// Package types for ecosystems that are not cataloged by syft, but may be described within a provided SBOM.
+const (
+	DotnetPkg  syftPkg.Type = "dotnet"
+	DartPubPkg syftPkg.Type = "dart-pub"
+	SwiftPkg   syftPkg.Type = "swift"
+	HexPkg     syftPkg.Type = "hex"
+	CondaPkg   syftPkg.Type = "conda"
+	RPkg       syftPkg.Type = "R-package"
+	HackagePkg syftPkg.Type = "hackage"
+	BitnamiPkg syftPkg.Type = "bitnami"
+	// LinuxKernelPkg is an installed linux kernel (cataloged by grype)
+	LinuxKernelPkg syftPkg.Type = "linux-kernel"
+	// BinaryPkg is a well-known component identified within a binary (cataloged by grype)
+	BinaryPkg syftPkg.Type = "binary"
+	// HomebrewPkg is a formula installed with homebrew (cataloged by grype)
+	HomebrewPkg syftPkg.Type = "homebrew"
+	// ChocolateyPkg is a package installed with chocolatey on windows (cataloged by grype)
+	ChocolateyPkg syftPkg.Type = "chocolatey"
+)
+
+// AdditionalTypes are all package types supported for matching beyond those which syft provides.
+var AdditionalTypes = []syftPkg.Type{
+	DotnetPkg,
+	DartPubPkg,
+	SwiftPkg,
+	HexPkg,
+	CondaPkg,
+	RPkg,
+	HackagePkg,
+	BitnamiPkg,
+	LinuxKernelPkg,
+	BinaryPkg,
+	HomebrewPkg,
+	ChocolateyPkg,
+}
+
+// Languages for ecosystems that are not cataloged by syft, but may be described within a provided SBOM.
+const (
+	Dotnet  syftPkg.Language = "dotnet"
+	Dart    syftPkg.Language = "dart"
+	Swift   syftPkg.Language = "swift"
+	Elixir  syftPkg.Language = "elixir"
+	Erlang  syftPkg.Language = "erlang"
+	R       syftPkg.Language = "R"
+	Haskell syftPkg.Language = "haskell"
+)
+
+// Metadata types for packages cataloged by grype (not syft).
+const (
+	CondaMetadataType       syftPkg.MetadataType = "CondaMetadata"
+	LinuxKernelMetadataType syftPkg.MetadataType = "LinuxKernelMetadata"
+	BinaryMetadataType      syftPkg.MetadataType = "BinaryMetadata"
+	HomebrewMetadataType    syftPkg.MetadataType = "HomebrewMetadata"
+)
+
+// AdditionalLanguages are all languages supported for matching beyond those which syft provides.
+var AdditionalLanguages = []syftPkg.Language{
+	Dotnet,
+	Dart,
+	Swift,
+	Elixir,
+	Erlang,
+	R,
+	Haskell,
+}
+
+// purlTypes maps package URL types (see https://github.com/package-url/purl-spec) onto package types and languages.
+var purlTypes = map[string]struct {
+	pkgType  syftPkg.Type
+	language syftPkg.Language
+}{
+	"alpine":     {syftPkg.ApkPkg, ""},
+	"apk":        {syftPkg.ApkPkg, ""},
+	"bitnami":    {BitnamiPkg, ""},
+	"brew":       {HomebrewPkg, ""},
+	"cargo":      {syftPkg.RustPkg, syftPkg.Rust},
+	"chocolatey": {ChocolateyPkg, ""},
+	"composer":   {syftPkg.PhpComposerPkg, syftPkg.PHP},
+	"conda":      {CondaPkg, ""},
+	"cran":       {RPkg, R},
+	"deb":        {syftPkg.DebPkg, ""},
+	"gem":        {syftPkg.GemPkg, syftPkg.Ruby},
+	"golang":     {syftPkg.GoModulePkg, syftPkg.Go},
+	"hackage":    {HackagePkg, Haskell},
+	"hex":        {HexPkg, Elixir}, // note: may also be an erlang package, however, both are matched the same way
+	"maven":      {syftPkg.JavaPkg, syftPkg.Java},
+	"npm":        {syftPkg.NpmPkg, syftPkg.JavaScript},
+	"nuget":      {DotnetPkg, Dotnet},
+	"pub":        {DartPubPkg, Dart},
+	"pypi":       {syftPkg.PythonPkg, syftPkg.Python},
+	"rpm":        {syftPkg.RpmPkg, ""},
+	"swift":      {SwiftPkg, Swift},
+}
+
+// typeFromPURL returns the package type and language described by the given package URL (if known).
+func typeFromPURL(purl string) (syftPkg.Type, syftPkg.Language) {
+	if value, ok := purlTypes[PURLType(purl)]; ok {
+		return value.pkgType, value.language
+	}
+	return "", ""
+}
+
+// PURLType returns the (lowercase) type of the given package URL (e.g. "pkg:npm/lodash@4.17.21" returns "npm"), or
+// an empty string if the value is not a package URL.
+func PURLType(purl string) string {
+	if !strings.HasPrefix(purl, "pkg:") {
+		return ""
+	}
+	return strings.ToLower(strings.SplitN(strings.TrimPrefix(purl, "pkg:"), "/", 2)[0])
+}
+
+// purlNamespaceAndName returns the (unescaped) namespace and name from the given package URL, ignoring the version,
+// qualifiers and subpath (e.g. "pkg:composer/symfony/http-kernel@4.4.0" returns "symfony" and "http-kernel").
+func purlNamespaceAndName(purl string) (string, string) {
+	if !strings.HasPrefix(purl, "pkg:") {
+		return "", ""
+	}
+	remaining := strings.TrimPrefix(purl, "pkg:")
+	if i := strings.IndexAny(remaining, "?#"); i >= 0 {
+		remaining = remaining[:i]
+	}
+	if i := strings.LastIndex(remaining, "@"); i >= 0 {
+		remaining = remaining[:i]
+	}
+
+	// the first segment is the type, the last segment is the name, and everything in between is the namespace
+	segments := strings.Split(strings.Trim(remaining, "/"), "/")
+	if len(segments) < 2 {
+		return "", ""
+	}
+	for i, segment := range segments {
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			segments[i] = unescaped
+		}
+	}
+	return strings.Join(segments[1:len(segments)-1], "/"), segments[len(segments)-1]
+}
+
+// purlQualifiers returns the (unescaped) qualifiers from the given package URL
+// (e.g. "pkg:conda/numpy@1.21.2?channel=main" returns {"channel": "main"}).
+func purlQualifiers(purl string) map[string]string {
+	qualifiers := make(map[string]string)
+	if !strings.HasPrefix(purl, "pkg:") {
+		return qualifiers
+	}
+	purl = strings.SplitN(purl, "#", 2)[0]
+	fields := strings.SplitN(purl, "?", 2)
+	if len(fields) != 2 {
+		return qualifiers
+	}
+	values, err := url.ParseQuery(fields[1])
+	if err != nil {
+		return qualifiers
+	}
+	for key := range values {
+		qualifiers[strings.ToLower(key)] = values.Get(key)
+	}
+	return qualifiers
+}
+
+// PURLPackage returns the (unescaped) name, version and qualifiers from the given package URL (e.g.
+// "pkg:rpm/redhat/openssl@1.1.1g-15.el8_3?arch=x86_64&epoch=1" returns "openssl", "1.1.1g-15.el8_3" and
+// {"arch": "x86_64", "epoch": "1"}), where the version is empty when the package URL has none.
+func PURLPackage(purl string) (string, string, map[string]string) {
+	_, name := purlNamespaceAndName(purl)
+	if name == "" {
+		return "", "", nil
+	}
+
+	var version string
+	remaining := purl
+	if i := strings.IndexAny(remaining, "?#"); i >= 0 {
+		remaining = remaining[:i]
+	}
+	if i := strings.LastIndex(remaining, "@"); i >= 0 {
+		version = remaining[i+1:]
+		if unescaped, err := url.PathUnescape(version); err == nil {
+			version = unescaped
+		}
+	}
+	return name, version, purlQualifiers(purl)
+}
@@ -0,0 +1,60 @@
+package pkg
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/scylladb/go-set/strset"
+)
+
+// UpstreamPackage represents a package that the installed package was built from (e.g. the source package for a dpkg
+// binary package, the source RPM for an RPM, or the origin package for an APK). Vulnerability data for distros is
+// frequently tracked against the upstream package instead of the installed package.
+type UpstreamPackage struct {
+	Name    string // the upstream package name
+	Version string // the version of the upstream package (empty if it is the same as the installed package version)
+}
+
+// UpstreamPackages returns a synthetic package for each upstream of the given package, where the name, version, and
+// CPEs have been replaced with the upstream package information (all other fields are kept as-is).
+func UpstreamPackages(p Package) (pkgs []Package) {
+	for _, u := range p.Upstreams {
+		tmp := p
+		tmp.Name = u.Name
+		tmp.Upstreams = nil
+		if u.Version != "" {
+			tmp.Version = u.Version
+		}
+
+		// for each cpe, replace pkg name (and version, if specified) with the upstream values and add to set
+		cpeStrings := strset.New()
+		for _, cpe := range p.CPEs {
+			updatedCPE, err := pkg.NewCPE(strings.ReplaceAll(cpe.BindToFmtString(), p.Name, u.Name))
+			if err != nil {
+				continue
+			}
+			if u.Version != "" {
+				updatedCPE.Version = u.Version
+			}
+			cpeStrings.Add(updatedCPE.BindToFmtString())
+		}
+
+		cpeList := cpeStrings.List()
+		sort.Strings(cpeList)
+
+		// with each entry in set, convert string to CPE and update the upstream package CPEs
+		var updatedCPEs []pkg.CPE
+		for _, cpeString := range cpeList {
+			updatedCPE, err := pkg.NewCPE(cpeString)
+			if err != nil {
+				continue
+			}
+			updatedCPEs = append(updatedCPEs, updatedCPE)
+		}
+		tmp.CPEs = updatedCPEs
+
+		pkgs = append(pkgs, tmp)
+	}
+	return pkgs
+}
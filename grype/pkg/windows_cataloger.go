@@ -0,0 +1,168 @@
+package pkg
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"regexp"
+	"strconv"
+
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/syft/syft/artifact"
+	"github.com/anchore/syft/syft/linux"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+const windowsCatalogerName = "windows-servicing-cataloger"
+
+// windowsRollupGlob matches the servicing package manifests of the cumulative updates installed into a windows
+// filesystem (e.g. "/Files/Windows/servicing/Packages/Package_for_RollupFix~31bf3856ad364e35~amd64~~17763.2366.1.7.mum"
+// within a windows container image layer)
+const windowsRollupGlob = "**/Windows/servicing/Packages/Package_for_RollupFix~*.mum"
+
+// windowsRollupBuildPattern matches the OS build and revision that a cumulative update brings the system to, from the
+// name of the servicing package manifest (e.g. "17763" and "2366" from "...~~17763.2366.1.7.mum")
+var windowsRollupBuildPattern = regexp.MustCompile(`~~(?P<build>\d+)\.(?P<revision>\d+)(\.\d+)*\.mum$`)
+
+// windowsKBPattern matches the KB that a servicing package manifest installs (e.g. `identifier="KB5008218"`)
+var windowsKBPattern = regexp.MustCompile(`identifier="KB(?P<kb>\d+)"`)
+
+// windowsProduct is a windows release as identified within MSRC data.
+type windowsProduct struct {
+	id   string // the MSRC product ID (e.g. "11572")
+	name string // the MSRC product name (e.g. "Windows Server 2019 (Server Core installation)")
+}
+
+// windowsContainerProducts maps OS builds onto the MSRC products that windows container base images are built from.
+// Note: container base images are either server core or nano server images (which share the same servicing data).
+var windowsContainerProducts = map[string]windowsProduct{
+	"14393": {id: "10855", name: "Windows Server 2016 (Server Core installation)"},
+	"17763": {id: "11572", name: "Windows Server 2019 (Server Core installation)"},
+	"20348": {id: "11924", name: "Windows Server 2022 (Server Core installation)"},
+}
+
+// windowsBaseKB is the KB "version" of a windows release without any cumulative updates installed (see the msrc
+// matcher).
+const windowsBaseKB = "base"
+
+// windowsCataloger finds the windows release and the cumulative update (KB) level of a windows filesystem, which is
+// described as a package for the msrc matcher.
+type windowsCataloger struct{}
+
+func newWindowsCataloger() *windowsCataloger {
+	return &windowsCataloger{}
+}
+
+func (c *windowsCataloger) Name() string {
+	return windowsCatalogerName
+}
+
+type windowsRollup struct {
+	build    string
+	revision int
+	location source.Location
+}
+
+func (c *windowsCataloger) Catalog(resolver source.FileResolver) ([]pkg.Package, []artifact.Relationship, error) {
+	locations, err := resolver.FilesByGlob(windowsRollupGlob)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to find windows servicing packages by glob=%q: %w", windowsRollupGlob, err)
+	}
+
+	// the latest cumulative update describes the KB level of the system (each includes all previous updates)
+	var latest *windowsRollup
+	for _, location := range locations {
+		match := windowsRollupBuildPattern.FindStringSubmatch(path.Base(location.RealPath))
+		if match == nil {
+			continue
+		}
+		revision, err := strconv.Atoi(match[windowsRollupBuildPattern.SubexpIndex("revision")])
+		if err != nil {
+			continue
+		}
+		if latest == nil || revision > latest.revision {
+			latest = &windowsRollup{
+				build:    match[windowsRollupBuildPattern.SubexpIndex("build")],
+				revision: revision,
+				location: location,
+			}
+		}
+	}
+	if latest == nil {
+		return nil, nil, nil
+	}
+
+	product, ok := windowsContainerProducts[latest.build]
+	if !ok {
+		log.Warnf("unable to determine the windows product for build=%q", latest.build)
+		return nil, nil, nil
+	}
+
+	kb, err := readWindowsKB(resolver, latest.location)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p := pkg.Package{
+		Name:         product.id,
+		Version:      kb,
+		FoundBy:      windowsCatalogerName,
+		Locations:    []source.Location{latest.location},
+		Type:         pkg.KbPkg,
+		MetadataType: pkg.KbPackageMetadataType,
+		Metadata: pkg.KbPackageMetadata{
+			ProductID: product.id,
+			Kb:        kb,
+		},
+	}
+	p.SetID()
+
+	return []pkg.Package{p}, nil, nil
+}
+
+// readWindowsKB returns the KB number installed by the given servicing package manifest.
+func readWindowsKB(resolver source.FileResolver, location source.Location) (string, error) {
+	reader, err := resolver.FileContentsByLocation(location)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			log.Warnf("unable to close windows servicing package at location=%+v: %+v", location, err)
+		}
+	}()
+
+	contents, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("unable to read windows servicing package: %w", err)
+	}
+
+	match := windowsKBPattern.FindSubmatch(contents)
+	if match == nil {
+		return windowsBaseKB, nil
+	}
+	return string(match[windowsKBPattern.SubexpIndex("kb")]), nil
+}
+
+// windowsRelease returns the windows release described by the KB level packages found by the windows cataloger, which
+// is used in place of a linux release to select the MSRC namespace to match against.
+func windowsRelease(catalog *pkg.Catalog) *linux.Release {
+	for p := range catalog.Enumerate(pkg.KbPkg) {
+		if p.FoundBy != windowsCatalogerName {
+			continue
+		}
+		for _, product := range windowsContainerProducts {
+			if product.id != p.Name {
+				continue
+			}
+			return &linux.Release{
+				PrettyName: product.name,
+				Name:       "windows",
+				ID:         "windows",
+				VersionID:  product.id,
+			}
+		}
+	}
+	return nil
+}
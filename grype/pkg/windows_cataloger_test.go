@@ -0,0 +1,42 @@
+package pkg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+)
+
+func TestWindowsCataloger(t *testing.T) {
+	src, err := source.NewFromDirectory("test-fixtures/windows")
+	require.NoError(t, err)
+
+	resolver, err := src.FileResolver(source.SquashedScope)
+	require.NoError(t, err)
+
+	packages, _, err := newWindowsCataloger().Catalog(resolver)
+	require.NoError(t, err)
+
+	// note: only the latest cumulative update describes the KB level
+	require.Len(t, packages, 1)
+	p := packages[0]
+
+	assert.Equal(t, "11572", p.Name)
+	assert.Equal(t, "5008218", p.Version)
+	assert.Equal(t, syftPkg.KbPkg, p.Type)
+	assert.Equal(t, syftPkg.KbPackageMetadata{ProductID: "11572", Kb: "5008218"}, p.Metadata)
+
+	catalog := syftPkg.NewCatalog(packages...)
+	release := windowsRelease(catalog)
+	require.NotNil(t, release)
+	assert.Equal(t, "windows", release.ID)
+	assert.Equal(t, "11572", release.VersionID)
+	assert.Equal(t, "Windows Server 2019 (Server Core installation)", release.PrettyName)
+}
+
+func TestWindowsRelease_NotWindows(t *testing.T) {
+	assert.Nil(t, windowsRelease(syftPkg.NewCatalog()))
+}
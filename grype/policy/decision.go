@@ -0,0 +1,16 @@
+package policy
+
+// Decision is the pass/fail decision of a policy over a scan result.
+type Decision struct {
+	// Policy is the policy that made the decision (e.g. the path of the policy file)
+	Policy string
+	// Denials are the reasons the policy denied the scan result (the scan result passes when there are none)
+	Denials []string
+	// Warnings are the reasons the policy warned about the scan result (which do not fail the scan result)
+	Warnings []string
+}
+
+// Passed indicates whether the policy allowed the scan result.
+func (d Decision) Passed() bool {
+	return len(d.Denials) == 0
+}
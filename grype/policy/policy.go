@@ -0,0 +1,126 @@
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/open-policy-agent/opa/rego"
+	"github.com/open-policy-agent/opa/util"
+)
+
+// DefaultQuery is the query of the decision of a policy, which is the document of the "grype" package of the policy.
+const DefaultQuery = "data.grype"
+
+// Policy is a Rego policy that decides whether a scan result passes, where the input of the policy is the grype JSON
+// document of the scan result. The policy is evaluated in-process (with the Rego engine of OPA), and the decision is
+// the document of the query, where the "deny" rule has the reasons the scan result fails (the scan result passes when
+// there are none) and the "warn" rule has the reasons to warn about the scan result (which do not fail it), e.g.:
+//
+//	package grype
+//
+//	deny[msg] {
+//		m := input.matches[_]
+//		m.vulnerability.severity == "Critical"
+//		msg := sprintf("%s in %s", [m.vulnerability.id, m.artifact.name])
+//	}
+type Policy struct {
+	file  string
+	query string
+	eval  rego.PreparedEvalQuery
+}
+
+// New returns the policy of the given Rego file, which is queried with the given query (or DefaultQuery when
+// empty). The policy is compiled once, so an invalid policy (e.g. with a parse error) is rejected here.
+func New(file, query string) (*Policy, error) {
+	module, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read policy: %w", err)
+	}
+	if query == "" {
+		query = DefaultQuery
+	}
+
+	eval, err := rego.New(
+		rego.Query(query),
+		rego.Module(file, string(module)),
+	).PrepareForEval(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("unable to compile policy=%q: %w", file, err)
+	}
+
+	return &Policy{
+		file:  file,
+		query: query,
+		eval:  eval,
+	}, nil
+}
+
+func (p *Policy) String() string {
+	return p.file
+}
+
+// Evaluate returns the decision of the policy over the given scan result (e.g. the grype JSON document), which is
+// given to the policy as it is encoded in JSON.
+func (p *Policy) Evaluate(doc interface{}) (*Decision, error) {
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode the policy input: %w", err)
+	}
+	var input interface{}
+	if err := util.UnmarshalJSON(encoded, &input); err != nil {
+		return nil, fmt.Errorf("unable to encode the policy input: %w", err)
+	}
+
+	results, err := p.eval.Eval(context.Background(), rego.EvalInput(input))
+	if err != nil {
+		return nil, fmt.Errorf("unable to evaluate policy=%q: %w", p.file, err)
+	}
+	if len(results) == 0 || len(results[0].Expressions) == 0 {
+		return nil, fmt.Errorf("the query %q of policy=%q is undefined (is the policy missing the package?)", p.query, p.file)
+	}
+
+	return p.decision(results[0].Expressions[0].Value)
+}
+
+type evalDecision struct {
+	Deny []interface{} `json:"deny"`
+	Warn []interface{} `json:"warn"`
+}
+
+// decision returns the decision of the value of the policy query, which must be an object with the deny and warn rules.
+func (p *Policy) decision(value interface{}) (*Decision, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the evaluation of policy=%q: %w", p.file, err)
+	}
+	var d evalDecision
+	if err := json.Unmarshal(encoded, &d); err != nil {
+		return nil, fmt.Errorf("the query %q of policy=%q is not a decision with deny and warn rules: %w", p.query, p.file, err)
+	}
+
+	return &Decision{
+		Policy:   p.file,
+		Denials:  messages(d.Deny),
+		Warnings: messages(d.Warn),
+	}, nil
+}
+
+// messages returns the messages of the results of a rule, where results that are not strings (e.g. objects with the
+// details of a denial) are described by their JSON encoding.
+func messages(results []interface{}) []string {
+	var msgs []string
+	for _, result := range results {
+		if msg, ok := result.(string); ok {
+			msgs = append(msgs, msg)
+			continue
+		}
+		encoded, err := json.Marshal(result)
+		if err != nil {
+			encoded = []byte(fmt.Sprintf("%v", result))
+		}
+		msgs = append(msgs, string(encoded))
+	}
+	return msgs
+}
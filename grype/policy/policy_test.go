@@ -0,0 +1,157 @@
+package policy_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/anchore/grype/grype/policy"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writePolicy(t *testing.T, module string) string {
+	file := filepath.Join(t.TempDir(), "policy.rego")
+	require.NoError(t, ioutil.WriteFile(file, []byte(module), 0600))
+	return file
+}
+
+func match(id, severity, fixState, name string) models.Match {
+	return models.Match{
+		Vulnerability: models.Vulnerability{
+			VulnerabilityMetadata: models.VulnerabilityMetadata{ID: id, Severity: severity},
+			Fix:                   models.Fix{State: fixState},
+		},
+		Artifact: models.Package{Name: name},
+	}
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	doc := models.Document{
+		Matches: []models.Match{
+			match("CVE-2021-1", "Critical", "fixed", "openssl"),
+			match("CVE-2021-2", "High", "not-fixed", "bash"),
+			match("CVE-2021-3", "Low", "fixed", "zlib"),
+		},
+	}
+
+	tests := []struct {
+		name     string
+		module   string
+		query    string
+		expected *policy.Decision
+		wantErr  string
+	}{
+		{
+			name: "denied",
+			module: `package grype
+
+deny[msg] {
+	m := input.matches[_]
+	m.vulnerability.severity == "Critical"
+	msg := sprintf("%s in %s", [m.vulnerability.id, m.artifact.name])
+}
+
+warn[msg] {
+	m := input.matches[_]
+	m.vulnerability.fix.state == "not-fixed"
+	msg := sprintf("%s has no fix", [m.vulnerability.id])
+}
+`,
+			expected: &policy.Decision{
+				Denials:  []string{"CVE-2021-1 in openssl"},
+				Warnings: []string{"CVE-2021-2 has no fix"},
+			},
+		},
+		{
+			name: "passed",
+			module: `package grype
+
+deny[msg] {
+	fixable := [m | m := input.matches[_]; m.vulnerability.severity == "High"; m.vulnerability.fix.state == "fixed"]
+	count(fixable) > 0
+	msg := sprintf("%d high vulnerabilities have a fix", [count(fixable)])
+}
+`,
+			expected: &policy.Decision{},
+		},
+		{
+			name: "denials that are not strings",
+			module: `package grype
+
+deny[{"id": m.vulnerability.id}] {
+	m := input.matches[_]
+	m.artifact.name == "zlib"
+}
+`,
+			expected: &policy.Decision{Denials: []string{`{"id":"CVE-2021-3"}`}},
+		},
+		{
+			name: "custom query",
+			module: `package custom
+
+decision := {"deny": [m.vulnerability.id | m := input.matches[_]; m.vulnerability.severity == "High"]}
+`,
+			query:    "data.custom.decision",
+			expected: &policy.Decision{Denials: []string{"CVE-2021-2"}},
+		},
+		{
+			name:     "without rules",
+			module:   "package grype\n",
+			expected: &policy.Decision{},
+		},
+		{
+			name:    "undefined",
+			module:  "package other\n\ndeny[\"always\"]\n",
+			wantErr: "is undefined",
+		},
+		{
+			name:    "not a decision",
+			module:  "package custom\n\ndecision := true\n",
+			query:   "data.custom.decision",
+			wantErr: "is not a decision",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			file := writePolicy(t, test.module)
+
+			p, err := policy.New(file, test.query)
+			require.NoError(t, err)
+
+			decision, err := p.Evaluate(doc)
+			if test.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			test.expected.Policy = file
+			assert.Equal(t, test.expected, decision)
+			assert.Equal(t, len(test.expected.Denials) == 0, decision.Passed())
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := policy.New(filepath.Join(t.TempDir(), "missing.rego"), "")
+	assert.Error(t, err)
+
+	_, err = policy.New(writePolicy(t, "package grype\n\ndeny[msg] {\n"), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rego_parse_error")
+
+	_, err = policy.New(writePolicy(t, "package grype\n\ndeny[msg] {\n\tmsg := undefined_function(input)\n}\n"), "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rego_type_error")
+
+	_, err = policy.New(writePolicy(t, "package grype\n"), "data.grype[")
+	assert.Error(t, err)
+
+	p, err := policy.New(writePolicy(t, "package grype\n"), "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, p.String())
+}
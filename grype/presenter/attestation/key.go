@@ -0,0 +1,101 @@
+package attestation
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// the PEM block types of the encrypted private keys of cosign (the latter from newer releases)
+var encryptedKeyTypes = map[string]bool{
+	"ENCRYPTED COSIGN PRIVATE KEY":   true,
+	"ENCRYPTED SIGSTORE PRIVATE KEY": true,
+}
+
+// encryptedKey is an encrypted private key of cosign: a PKCS8 key sealed by nacl/secretbox with a key derived from
+// the password by scrypt.
+type encryptedKey struct {
+	KDF struct {
+		Name   string `json:"name"`
+		Params struct {
+			N int `json:"N"`
+			R int `json:"r"`
+			P int `json:"p"`
+		} `json:"params"`
+		Salt []byte `json:"salt"`
+	} `json:"kdf"`
+	Cipher struct {
+		Name  string `json:"name"`
+		Nonce []byte `json:"nonce"`
+	} `json:"cipher"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// ParseSigningKey parses a PEM encoded private key (ECDSA, RSA, or ed25519), which is either unencrypted (PKCS8,
+// SEC 1, or PKCS1) or an encrypted private key of cosign (as made by "cosign generate-key-pair"), which is decrypted
+// with the given password.
+func ParseSigningKey(contents, password []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(contents)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM encoded private key found")
+	}
+
+	var key interface{}
+	var err error
+	switch {
+	case encryptedKeyTypes[block.Type]:
+		der, decryptErr := decryptKey(block.Bytes, password)
+		if decryptErr != nil {
+			return nil, decryptErr
+		}
+		key, err = x509.ParsePKCS8PrivateKey(der)
+	case block.Type == "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case block.Type == "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse private key: %w", err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+func decryptKey(contents, password []byte) ([]byte, error) {
+	var encrypted encryptedKey
+	if err := json.Unmarshal(contents, &encrypted); err != nil {
+		return nil, fmt.Errorf("unable to decode encrypted private key: %w", err)
+	}
+	if encrypted.KDF.Name != "scrypt" || encrypted.Cipher.Name != "nacl/secretbox" {
+		return nil, fmt.Errorf("unsupported private key encryption (kdf=%q, cipher=%q)", encrypted.KDF.Name, encrypted.Cipher.Name)
+	}
+	if len(encrypted.Cipher.Nonce) != 24 {
+		return nil, fmt.Errorf("bad private key nonce")
+	}
+
+	params := encrypted.KDF.Params
+	derived, err := scrypt.Key(password, encrypted.KDF.Salt, params.N, params.R, params.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("unable to derive private key encryption key: %w", err)
+	}
+
+	var key [32]byte
+	var nonce [24]byte
+	copy(key[:], derived)
+	copy(nonce[:], encrypted.Cipher.Nonce)
+	decrypted, ok := secretbox.Open(nil, encrypted.Ciphertext, &nonce, &key)
+	if !ok {
+		return nil, fmt.Errorf("unable to decrypt private key (wrong password?)")
+	}
+	return decrypted, nil
+}
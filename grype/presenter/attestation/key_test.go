@@ -0,0 +1,100 @@
+package attestation
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// cosignKey encrypts the given PKCS8 key with the given password, as "cosign generate-key-pair" does.
+func cosignKey(t *testing.T, der, password []byte) []byte {
+	var encrypted encryptedKey
+	encrypted.KDF.Name = "scrypt"
+	encrypted.KDF.Params.N, encrypted.KDF.Params.R, encrypted.KDF.Params.P = 1024, 8, 1
+	encrypted.KDF.Salt = []byte("0123456789abcdef0123456789abcdef")
+	encrypted.Cipher.Name = "nacl/secretbox"
+	encrypted.Cipher.Nonce = []byte("0123456789abcdef01234567")
+
+	derived, err := scrypt.Key(password, encrypted.KDF.Salt, 1024, 8, 1, 32)
+	require.NoError(t, err)
+	var key [32]byte
+	var nonce [24]byte
+	copy(key[:], derived)
+	copy(nonce[:], encrypted.Cipher.Nonce)
+	encrypted.Ciphertext = secretbox.Seal(nil, der, &nonce, &key)
+
+	contents, err := json.Marshal(encrypted)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED COSIGN PRIVATE KEY", Bytes: contents})
+}
+
+func TestParseSigningKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecDER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+	sec1DER, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	edDER, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		contents []byte
+		password string
+		wantErr  bool
+	}{
+		{
+			name:     "pkcs8",
+			contents: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: ecDER}),
+		},
+		{
+			name:     "sec1",
+			contents: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1DER}),
+		},
+		{
+			name:     "ed25519",
+			contents: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: edDER}),
+		},
+		{
+			name:     "encrypted cosign key",
+			contents: cosignKey(t, ecDER, []byte("secret")),
+			password: "secret",
+		},
+		{
+			name:     "encrypted cosign key with the wrong password",
+			contents: cosignKey(t, ecDER, []byte("secret")),
+			password: "guess",
+			wantErr:  true,
+		},
+		{
+			name:     "not PEM",
+			contents: []byte("key"),
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			signer, err := ParseSigningKey(test.contents, []byte(test.password))
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, signer.Public())
+		})
+	}
+}
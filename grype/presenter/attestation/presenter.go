@@ -0,0 +1,232 @@
+package attestation
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anchore/grype/grype/db"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/version"
+	"github.com/anchore/syft/syft/source"
+)
+
+const (
+	// PredicateType is the in-toto predicate type of a vulnerability scan result (as attested by cosign)
+	PredicateType = "https://cosign.sigstore.dev/attestation/vuln/v1"
+
+	statementType = "https://in-toto.io/Statement/v0.1"
+	payloadType   = "application/vnd.in-toto+json"
+	scannerURI    = "pkg:github/anchore/grype"
+)
+
+// Options are the parameters of a result attestation besides the scan result.
+type Options struct {
+	// Signer is the private key that signs the attestation
+	Signer crypto.Signer
+	// ScanStartedOn is when the scan started (the scan finishes when the attestation is presented)
+	ScanStartedOn time.Time
+}
+
+// Presenter writes the scan result of an image as a signed in-toto attestation (a DSSE envelope) of a vulnerability
+// predicate, which is the grype JSON document of the result.
+type Presenter struct {
+	matches          match.Matches
+	ignoredMatches   []match.IgnoredMatch
+	packages         []pkg.Package
+	context          pkg.Context
+	metadataProvider vulnerability.MetadataProvider
+	appConfig        interface{}
+	dbStatus         interface{}
+	options          Options
+
+	once     sync.Once
+	envelope []byte
+	err      error
+}
+
+// NewPresenter is a *Presenter constructor
+func NewPresenter(matches match.Matches, ignoredMatches []match.IgnoredMatch, packages []pkg.Package, context pkg.Context, metadataProvider vulnerability.MetadataProvider, appConfig interface{}, dbStatus interface{}, options Options) *Presenter {
+	return &Presenter{
+		matches:          matches,
+		ignoredMatches:   ignoredMatches,
+		packages:         packages,
+		context:          context,
+		metadataProvider: metadataProvider,
+		appConfig:        appConfig,
+		dbStatus:         dbStatus,
+		options:          options,
+	}
+}
+
+// Present writes the DSSE envelope of the attestation (on a single line, as downloaded by cosign)
+func (pres *Presenter) Present(output io.Writer) error {
+	envelope, err := pres.Envelope()
+	if err != nil {
+		return err
+	}
+	_, err = output.Write(append(envelope, '\n'))
+	return err
+}
+
+// Envelope returns the DSSE envelope of the attestation, which is only signed once (the same envelope is presented and
+// pushed to the registry).
+func (pres *Presenter) Envelope() ([]byte, error) {
+	pres.once.Do(func() {
+		pres.envelope, pres.err = pres.sign()
+	})
+	return pres.envelope, pres.err
+}
+
+type statement struct {
+	Type          string        `json:"_type"`
+	PredicateType string        `json:"predicateType"`
+	Subject       []subject     `json:"subject"`
+	Predicate     vulnPredicate `json:"predicate"`
+}
+
+type subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type vulnPredicate struct {
+	Invocation invocation `json:"invocation"`
+	Scanner    scanner    `json:"scanner"`
+	Metadata   metadata   `json:"metadata"`
+}
+
+type invocation struct {
+	Parameters interface{} `json:"parameters,omitempty"`
+	URI        string      `json:"uri,omitempty"`
+	EventID    string      `json:"event_id,omitempty"`
+	BuilderID  string      `json:"builder.id,omitempty"`
+}
+
+type scanner struct {
+	URI     string          `json:"uri"`
+	Version string          `json:"version"`
+	DB      database        `json:"db"`
+	Result  models.Document `json:"result"`
+}
+
+type database struct {
+	Version string `json:"version"`
+}
+
+type metadata struct {
+	ScanStartedOn  time.Time `json:"scanStartedOn"`
+	ScanFinishedOn time.Time `json:"scanFinishedOn"`
+}
+
+type envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []signature `json:"signatures"`
+}
+
+type signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+func (pres *Presenter) sign() ([]byte, error) {
+	if pres.options.Signer == nil {
+		return nil, fmt.Errorf("a signing key is required to attest the scan result")
+	}
+	subj, err := imageSubject(pres.context.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := models.NewDocument(pres.packages, pres.context, pres.matches, pres.ignoredMatches, pres.metadataProvider,
+		pres.appConfig, pres.dbStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	scannerVersion := version.FromBuild().Version
+	payload, err := json.Marshal(statement{
+		Type:          statementType,
+		PredicateType: PredicateType,
+		Subject:       []subject{subj},
+		Predicate: vulnPredicate{
+			Invocation: invocation{Parameters: pres.appConfig},
+			Scanner: scanner{
+				URI:     scannerURI + "@" + scannerVersion,
+				Version: scannerVersion,
+				DB:      databaseOf(pres.dbStatus),
+				Result:  doc,
+			},
+			Metadata: metadata{
+				ScanStartedOn:  pres.options.ScanStartedOn.UTC(),
+				ScanFinishedOn: time.Now().UTC(),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode in-toto statement: %w", err)
+	}
+
+	sig, err := signPayload(pres.options.Signer, payload)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign the result attestation: %w", err)
+	}
+	return json.Marshal(envelope{
+		PayloadType: payloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []signature{{Sig: base64.StdEncoding.EncodeToString(sig)}},
+	})
+}
+
+// imageSubject returns the in-toto subject of the scanned image, which is the digest of the image in the registry (when
+// known, e.g. for images from the docker daemon) or else the digest of its manifest.
+func imageSubject(src *source.Metadata) (subject, error) {
+	if src == nil || src.Scheme != source.ImageScheme {
+		return subject{}, fmt.Errorf("only the scan result of an image can be attested")
+	}
+	name, digest := src.ImageMetadata.UserInput, src.ImageMetadata.ManifestDigest
+	if len(src.ImageMetadata.RepoDigests) > 0 {
+		fields := strings.SplitN(src.ImageMetadata.RepoDigests[0], "@", 2)
+		if len(fields) == 2 {
+			name, digest = fields[0], fields[1]
+		}
+	}
+
+	fields := strings.SplitN(digest, ":", 2)
+	if len(fields) != 2 || fields[1] == "" {
+		return subject{}, fmt.Errorf("the digest of image %q is unknown", name)
+	}
+	return subject{Name: name, Digest: map[string]string{fields[0]: fields[1]}}, nil
+}
+
+func databaseOf(dbStatus interface{}) database {
+	status, ok := dbStatus.(*db.Status)
+	if !ok || status == nil {
+		return database{}
+	}
+	return database{
+		Version: fmt.Sprintf("v%d (built %s)", status.SchemaVersion, status.Built.UTC().Format(time.RFC3339)),
+	}
+}
+
+// signPayload signs the DSSE pre-authentication encoding of the given payload (with SHA-256, except for ed25519 keys).
+func signPayload(signer crypto.Signer, payload []byte) ([]byte, error) {
+	message := []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+	if _, ok := signer.Public().(ed25519.PublicKey); ok {
+		return signer.Sign(rand.Reader, message, crypto.Hash(0))
+	}
+	digest := sha256.Sum256(message)
+	return signer.Sign(rand.Reader, digest[:], crypto.SHA256)
+}
@@ -0,0 +1,138 @@
+package attestation
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/source"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPresenter(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	p := pkg.Package{ID: "package-1-id", Name: "package-1", Version: "1.1.1", Type: syftPkg.DebPkg}
+	matches := match.NewMatches()
+	matches.Add(match.Match{
+		Vulnerability: vulnerability.Vulnerability{ID: "CVE-1999-0001", Namespace: "source-1"},
+		Package:       p,
+	})
+	digest := "sha256:" + fmt.Sprintf("%064x", 1)
+
+	tests := []struct {
+		name          string
+		source        *source.Metadata
+		signer        bool
+		subjectName   string
+		subjectDigest string
+		wantErr       bool
+	}{
+		{
+			name: "image from a registry",
+			source: &source.Metadata{
+				Scheme:        source.ImageScheme,
+				ImageMetadata: source.ImageMetadata{UserInput: "alpine:3.14", ManifestDigest: digest},
+			},
+			signer:        true,
+			subjectName:   "alpine:3.14",
+			subjectDigest: digest,
+		},
+		{
+			name: "image with a repo digest",
+			source: &source.Metadata{
+				Scheme: source.ImageScheme,
+				ImageMetadata: source.ImageMetadata{
+					UserInput:      "alpine:3.14",
+					ManifestDigest: "sha256:" + fmt.Sprintf("%064x", 2),
+					RepoDigests:    []string{"alpine@" + digest},
+				},
+			},
+			signer:        true,
+			subjectName:   "alpine",
+			subjectDigest: digest,
+		},
+		{
+			name:    "directory",
+			source:  &source.Metadata{Scheme: source.DirectoryScheme, Path: "/src"},
+			signer:  true,
+			wantErr: true,
+		},
+		{
+			name: "without a signer",
+			source: &source.Metadata{
+				Scheme:        source.ImageScheme,
+				ImageMetadata: source.ImageMetadata{UserInput: "alpine:3.14", ManifestDigest: digest},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := Options{ScanStartedOn: time.Now()}
+			if test.signer {
+				options.Signer = key
+			}
+			pres := NewPresenter(matches, nil, []pkg.Package{p}, pkg.Context{Source: test.source}, models.NewMetadataMock(), nil, nil, options)
+
+			var buffer bytes.Buffer
+			err := pres.Present(&buffer)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var env envelope
+			require.NoError(t, json.Unmarshal(buffer.Bytes(), &env))
+			assert.Equal(t, payloadType, env.PayloadType)
+			payload, err := base64.StdEncoding.DecodeString(env.Payload)
+			require.NoError(t, err)
+
+			require.Len(t, env.Signatures, 1)
+			sig, err := base64.StdEncoding.DecodeString(env.Signatures[0].Sig)
+			require.NoError(t, err)
+			message := sha256.Sum256([]byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload)))
+			assert.True(t, ecdsa.VerifyASN1(&key.PublicKey, message[:], sig))
+
+			var stmt struct {
+				PredicateType string    `json:"predicateType"`
+				Subject       []subject `json:"subject"`
+				Predicate     struct {
+					Scanner struct {
+						URI    string          `json:"uri"`
+						Result models.Document `json:"result"`
+					} `json:"scanner"`
+				} `json:"predicate"`
+			}
+			require.NoError(t, json.Unmarshal(payload, &stmt))
+			assert.Equal(t, PredicateType, stmt.PredicateType)
+			require.Len(t, stmt.Subject, 1)
+			assert.Equal(t, test.subjectName, stmt.Subject[0].Name)
+			assert.Equal(t, test.subjectDigest, "sha256:"+stmt.Subject[0].Digest["sha256"])
+			assert.Contains(t, stmt.Predicate.Scanner.URI, scannerURI+"@")
+			require.Len(t, stmt.Predicate.Scanner.Result.Matches, 1)
+			assert.Equal(t, "CVE-1999-0001", stmt.Predicate.Scanner.Result.Matches[0].Vulnerability.ID)
+
+			// the attestation is signed once, so that the presented and pushed attestations are the same
+			again, err := pres.Envelope()
+			require.NoError(t, err)
+			assert.Equal(t, bytes.TrimSpace(buffer.Bytes()), again)
+		})
+	}
+}
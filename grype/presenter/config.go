@@ -1,11 +1,18 @@
 package presenter
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/presenter/attestation"
+	"github.com/anchore/grype/grype/presenter/table"
+)
 
 // Config is the presenter domain's configuration data structure.
 type Config struct {
 	format           format
 	templateFilePath string
+	attestation      attestation.Options
+	table            table.Options
 }
 
 // ValidatedConfig returns a new, validated presenter.Config. If a valid Config cannot be created using the given input,
@@ -42,3 +49,22 @@ func ValidatedConfig(output, outputTemplateFile string) (Config, error) {
 		format: format,
 	}, nil
 }
+
+// AttestsResult indicates that the output format is a signed attestation of the scan result, which requires a signer
+// (see WithAttestation).
+func (c Config) AttestsResult() bool {
+	return c.format == attestFormat
+}
+
+// WithAttestation returns the config with the options of attestations of the scan result (the signer and when the scan
+// started).
+func (c Config) WithAttestation(options attestation.Options) Config {
+	c.attestation = options
+	return c
+}
+
+// WithTable returns the config with the order and the columns of the table output format.
+func (c Config) WithTable(options table.Options) Config {
+	c.table = options
+	return c
+}
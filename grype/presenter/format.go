@@ -7,9 +7,12 @@ import (
 const (
 	unknownFormat   format = "unknown"
 	jsonFormat      format = "json"
+	jsonLinesFormat format = "json-lines"
 	tableFormat     format = "table"
 	cycloneDXFormat format = "cyclonedx"
 	templateFormat  format = "template"
+	attestFormat    format = "attestation"
+	protoFormat     format = "proto"
 )
 
 // format is a dedicated type to represent a specific kind of presenter output format.
@@ -26,12 +29,18 @@ func parse(userInput string) format {
 		return tableFormat
 	case strings.ToLower(jsonFormat.String()):
 		return jsonFormat
+	case strings.ToLower(jsonLinesFormat.String()), "jsonl":
+		return jsonLinesFormat
 	case strings.ToLower(tableFormat.String()):
 		return tableFormat
 	case strings.ToLower(cycloneDXFormat.String()):
 		return cycloneDXFormat
 	case strings.ToLower(templateFormat.String()):
 		return templateFormat
+	case strings.ToLower(attestFormat.String()):
+		return attestFormat
+	case strings.ToLower(protoFormat.String()), "protobuf":
+		return protoFormat
 	default:
 		return unknownFormat
 	}
@@ -40,7 +49,10 @@ func parse(userInput string) format {
 // AvailableFormats is a list of presenter format options available to users.
 var AvailableFormats = []format{
 	jsonFormat,
+	jsonLinesFormat,
 	tableFormat,
 	cycloneDXFormat,
 	templateFormat,
+	attestFormat,
+	protoFormat,
 }
@@ -23,6 +23,22 @@ func TestParse(t *testing.T) {
 			"jSOn",
 			jsonFormat,
 		},
+		{
+			"json-lines",
+			jsonLinesFormat,
+		},
+		{
+			"JSONL",
+			jsonLinesFormat,
+		},
+		{
+			"proto",
+			protoFormat,
+		},
+		{
+			"protobuf",
+			protoFormat,
+		},
 		{
 			"booboodepoopoo",
 			unknownFormat,
@@ -0,0 +1,45 @@
+package json
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// LinesPresenter reports each match as a JSON document on its own line (JSON Lines), where each line is written as soon
+// as the match is described. Unlike the JSON document reported by Presenter, the report is never held in memory as a
+// whole, which matters for scans with a very large number of matches.
+type LinesPresenter struct {
+	matches          match.Matches
+	packages         []pkg.Package
+	metadataProvider vulnerability.MetadataProvider
+}
+
+// NewLinesPresenter is a *LinesPresenter constructor
+func NewLinesPresenter(matches match.Matches, packages []pkg.Package, metadataProvider vulnerability.MetadataProvider) *LinesPresenter {
+	return &LinesPresenter{
+		matches:          matches,
+		packages:         packages,
+		metadataProvider: metadataProvider,
+	}
+}
+
+// Present writes a JSON Lines report, with one match per line
+func (pres *LinesPresenter) Present(output io.Writer) error {
+	writer := bufio.NewWriter(output)
+	enc := json.NewEncoder(writer)
+	// prevent > and < from being escaped in the payload
+	enc.SetEscapeHTML(false)
+	err := models.EnumerateMatches(pres.packages, pres.matches, pres.metadataProvider, func(m models.Match) error {
+		return enc.Encode(&m)
+	})
+	if err != nil {
+		return err
+	}
+	return writer.Flush()
+}
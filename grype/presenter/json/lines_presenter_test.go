@@ -0,0 +1,48 @@
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+)
+
+func TestLinesPresenter(t *testing.T) {
+	matches, packages, context, metadataProvider, appConfig, dbStatus := models.GenerateAnalysis(t)
+
+	var buffer bytes.Buffer
+	require.NoError(t, NewLinesPresenter(matches, packages, metadataProvider).Present(&buffer))
+
+	// every line is a match of the JSON document (in the same order)
+	doc, err := models.NewDocument(packages, context, matches, nil, metadataProvider, appConfig, dbStatus)
+	require.NoError(t, err)
+	require.NotEmpty(t, doc.Matches)
+
+	var actual []models.Match
+	scanner := bufio.NewScanner(&buffer)
+	for scanner.Scan() {
+		var m models.Match
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &m))
+		actual = append(actual, m)
+	}
+	require.NoError(t, scanner.Err())
+
+	expected, err := json.Marshal(doc.Matches)
+	require.NoError(t, err)
+	actualJSON, err := json.Marshal(actual)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(expected), string(actualJSON))
+}
+
+func TestLinesPresenter_NoMatches(t *testing.T) {
+	var buffer bytes.Buffer
+	require.NoError(t, NewLinesPresenter(match.NewMatches(), []pkg.Package{}, nil).Present(&buffer))
+	assert.Empty(t, buffer.String())
+}
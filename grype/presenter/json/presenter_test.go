@@ -133,7 +133,14 @@ func TestJsonImgsPresenter(t *testing.T) {
 				Type:    match.ExactIndirectMatch,
 				Matcher: match.DpkgMatcher,
 				SearchedBy: map[string]interface{}{
-					"language": "java",
+					"distro": map[string]string{
+						"type":    "ubuntu",
+						"version": "20.04",
+					},
+					"package": map[string]string{
+						"name":    "a source!",
+						"version": "1.1.1",
+					},
 				},
 				Found: map[string]interface{}{
 					"constraint": "< 2.0.0",
@@ -281,7 +288,14 @@ func TestJsonDirsPresenter(t *testing.T) {
 				Type:    match.ExactIndirectMatch,
 				Matcher: match.DpkgMatcher,
 				SearchedBy: map[string]interface{}{
-					"language": "java",
+					"distro": map[string]string{
+						"type":    "ubuntu",
+						"version": "20.04",
+					},
+					"package": map[string]string{
+						"name":    "a source!",
+						"version": "1.0.1",
+					},
 				},
 				Found: map[string]interface{}{
 					"constraint": "< 2.0.0",
@@ -1,25 +1,43 @@
 package models
 
 import (
+	"time"
+
 	"github.com/anchore/syft/syft/linux"
 )
 
 // distribution provides information about a detected Linux distribution.
 type distribution struct {
-	Name    string   `json:"name"`    // Name of the Linux distribution
-	Version string   `json:"version"` // Version of the Linux distribution (major or major.minor version)
-	IDLike  []string `json:"idLike"`  // the ID_LIKE field found within the /etc/os-release file
+	Name      string     `json:"name"`                // Name of the Linux distribution
+	Version   string     `json:"version"`             // Version of the Linux distribution (major or major.minor version)
+	IDLike    []string   `json:"idLike"`              // the ID_LIKE field found within the /etc/os-release file
+	EndOfLife *endOfLife `json:"endOfLife,omitempty"` // the end of life of the Linux distribution release (if known)
+}
+
+// endOfLife describes when a Linux distribution release stops receiving vulnerability data.
+type endOfLife struct {
+	Date    string `json:"date"`    // the end of life date (RFC 3339)
+	Reached bool   `json:"reached"` // whether the end of life date has passed
 }
 
 // newDistribution creates a struct with the Linux distribution to be represented in JSON.
-func newDistribution(d *linux.Release) distribution {
+func newDistribution(d *linux.Release, eol *time.Time) distribution {
 	if d == nil {
 		return distribution{}
 	}
 
+	var eolModel *endOfLife
+	if eol != nil {
+		eolModel = &endOfLife{
+			Date:    eol.UTC().Format(time.RFC3339),
+			Reached: !time.Now().Before(*eol),
+		}
+	}
+
 	return distribution{
-		Name:    d.Name,
-		Version: d.Version,
-		IDLike:  d.IDLike,
+		Name:      d.Name,
+		Version:   d.Version,
+		IDLike:    d.IDLike,
+		EndOfLife: eolModel,
 	}
 }
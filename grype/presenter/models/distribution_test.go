@@ -0,0 +1,76 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anchore/syft/syft/linux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDistribution(t *testing.T) {
+	past := time.Date(2020, time.November, 30, 0, 0, 0, 0, time.UTC)
+	future := time.Now().AddDate(1, 0, 0).UTC().Truncate(time.Second)
+
+	release := &linux.Release{
+		Name:    "CentOS Linux",
+		Version: "8",
+		IDLike:  []string{"rhel", "fedora"},
+	}
+
+	tests := []struct {
+		name     string
+		release  *linux.Release
+		eol      *time.Time
+		expected distribution
+	}{
+		{
+			name:     "no distro",
+			eol:      &past,
+			expected: distribution{},
+		},
+		{
+			name:    "unknown end of life",
+			release: release,
+			expected: distribution{
+				Name:    "CentOS Linux",
+				Version: "8",
+				IDLike:  []string{"rhel", "fedora"},
+			},
+		},
+		{
+			name:    "reached end of life",
+			release: release,
+			eol:     &past,
+			expected: distribution{
+				Name:    "CentOS Linux",
+				Version: "8",
+				IDLike:  []string{"rhel", "fedora"},
+				EndOfLife: &endOfLife{
+					Date:    "2020-11-30T00:00:00Z",
+					Reached: true,
+				},
+			},
+		},
+		{
+			name:    "upcoming end of life",
+			release: release,
+			eol:     &future,
+			expected: distribution{
+				Name:    "CentOS Linux",
+				Version: "8",
+				IDLike:  []string{"rhel", "fedora"},
+				EndOfLife: &endOfLife{
+					Date:    future.Format(time.RFC3339),
+					Reached: false,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, newDistribution(test.release, test.eol))
+		})
+	}
+}
@@ -12,29 +12,25 @@ import (
 
 // Document represents the JSON document to be presented
 type Document struct {
-	Matches        []Match        `json:"matches"`
-	IgnoredMatches []IgnoredMatch `json:"ignoredMatches,omitempty"`
-	Source         *source        `json:"source"`
-	Distro         distribution   `json:"distro"`
-	Descriptor     descriptor     `json:"descriptor"`
+	Matches        []Match         `json:"matches"`
+	IgnoredMatches []IgnoredMatch  `json:"ignoredMatches,omitempty"`
+	Source         *source         `json:"source"`
+	Distro         distribution    `json:"distro"`
+	Descriptor     descriptor      `json:"descriptor"`
+	Policy         *policyDecision `json:"policy,omitempty"`
+	Schema         schema          `json:"schema"`
 }
 
 // NewDocument creates and populates a new Document struct, representing the populated JSON document.
 func NewDocument(packages []pkg.Package, context pkg.Context, matches match.Matches, ignoredMatches []match.IgnoredMatch, metadataProvider vulnerability.MetadataProvider, appConfig interface{}, dbStatus interface{}) (Document, error) {
 	// we must preallocate the findings to ensure the JSON document does not show "null" when no matches are found
 	var findings = make([]Match, 0)
-	for _, m := range matches.Sorted() {
-		p := pkg.ByID(m.Package.ID, packages)
-		if p == nil {
-			return Document{}, fmt.Errorf("unable to find package in collection: %+v", p)
-		}
-
-		matchModel, err := newMatch(m, *p, metadataProvider)
-		if err != nil {
-			return Document{}, err
-		}
-
-		findings = append(findings, *matchModel)
+	err := EnumerateMatches(packages, matches, metadataProvider, func(m Match) error {
+		findings = append(findings, m)
+		return nil
+	})
+	if err != nil {
+		return Document{}, err
 	}
 
 	var src *source
@@ -69,12 +65,43 @@ func NewDocument(packages []pkg.Package, context pkg.Context, matches match.Matc
 		Matches:        findings,
 		IgnoredMatches: ignoredMatchModels,
 		Source:         src,
-		Distro:         newDistribution(context.Distro),
+		Distro:         newDistribution(context.Distro, context.DistroEOL),
 		Descriptor: descriptor{
 			Name:                  internal.ApplicationName,
 			Version:               version.FromBuild().Version,
 			Configuration:         appConfig,
 			VulnerabilityDBStatus: dbStatus,
 		},
+		Policy: newPolicyDecision(context.Policy),
+		Schema: newSchema(),
 	}, nil
 }
+
+// EnumerateMatches calls the given function with the model of each match (in sorted order). Each model is built only
+// when the function is called for it, so callers that do not keep the models (e.g. that write each model out) never
+// hold all models in memory at once.
+func EnumerateMatches(packages []pkg.Package, matches match.Matches, metadataProvider vulnerability.MetadataProvider, fn func(Match) error) error {
+	packagesByID := make(map[pkg.ID]pkg.Package, len(packages))
+	for _, p := range packages {
+		packagesByID[p.ID] = p
+	}
+
+	suggestedFixes := match.SuggestedFixes(matches)
+	for _, m := range matches.Sorted() {
+		p, ok := packagesByID[m.Package.ID]
+		if !ok {
+			return fmt.Errorf("unable to find package in collection: %+v", m.Package.ID)
+		}
+
+		matchModel, err := newMatch(m, p, metadataProvider)
+		if err != nil {
+			return err
+		}
+		matchModel.Artifact.SuggestedFix = suggestedFixes[m.Package.ID]
+
+		if err := fn(*matchModel); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -8,9 +8,11 @@ type IgnoredMatch struct {
 }
 
 type IgnoreRule struct {
-	Vulnerability string             `json:"vulnerability,omitempty"`
-	FixState      string             `json:"fix-state,omitempty"`
-	Package       *IgnoreRulePackage `json:"package,omitempty"`
+	Vulnerability  string             `json:"vulnerability,omitempty"`
+	IncludeAliases bool               `json:"include-aliases,omitempty"`
+	FixState       string             `json:"fix-state,omitempty"`
+	Package        *IgnoreRulePackage `json:"package,omitempty"`
+	Reason         string             `json:"reason,omitempty"`
 }
 
 type IgnoreRulePackage struct {
@@ -18,25 +20,29 @@ type IgnoreRulePackage struct {
 	Version  string `json:"version,omitempty"`
 	Type     string `json:"type,omitempty"`
 	Location string `json:"location,omitempty"`
+	PURL     string `json:"purl,omitempty"`
 }
 
 func newIgnoreRule(r match.IgnoreRule) IgnoreRule {
 	var ignoreRulePackage *IgnoreRulePackage
 
 	// We'll only set the package part of the rule not to `nil` if there are any values to fill out.
-	if p := r.Package; p.Name != "" || p.Version != "" || p.Type != "" || p.Location != "" {
+	if p := r.Package; p.Name != "" || p.Version != "" || p.Type != "" || p.Location != "" || p.PURL != "" {
 		ignoreRulePackage = &IgnoreRulePackage{
 			Name:     r.Package.Name,
 			Version:  r.Package.Version,
 			Type:     r.Package.Type,
 			Location: r.Package.Location,
+			PURL:     r.Package.PURL,
 		}
 	}
 
 	return IgnoreRule{
-		Vulnerability: r.Vulnerability,
-		FixState:      r.FixState,
-		Package:       ignoreRulePackage,
+		Vulnerability:  r.Vulnerability,
+		IncludeAliases: r.IncludeAliases,
+		FixState:       r.FixState,
+		Package:        ignoreRulePackage,
+		Reason:         r.Reason,
 	}
 }
 
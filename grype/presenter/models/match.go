@@ -12,6 +12,7 @@ import (
 type Match struct {
 	Vulnerability          Vulnerability           `json:"vulnerability"`
 	RelatedVulnerabilities []VulnerabilityMetadata `json:"relatedVulnerabilities"`
+	References             []string                `json:"references"`
 	MatchDetails           []MatchDetails          `json:"matchDetails"`
 	Artifact               Package                 `json:"artifact"`
 }
@@ -25,15 +26,9 @@ type MatchDetails struct {
 }
 
 func newMatch(m match.Match, p pkg.Package, metadataProvider vulnerability.MetadataProvider) (*Match, error) {
-	relatedVulnerabilities := make([]VulnerabilityMetadata, 0)
-	for _, r := range m.Vulnerability.RelatedVulnerabilities {
-		relatedMetadata, err := metadataProvider.GetMetadata(r.ID, r.Namespace)
-		if err != nil {
-			return nil, fmt.Errorf("unable to fetch related vuln=%q metadata: %+v", r, err)
-		}
-		if relatedMetadata != nil {
-			relatedVulnerabilities = append(relatedVulnerabilities, NewVulnerabilityMetadata(r.ID, r.Namespace, relatedMetadata))
-		}
+	relatedVulnerabilities, err := NewRelatedVulnerabilities(m, metadataProvider)
+	if err != nil {
+		return nil, err
 	}
 
 	metadata, err := metadataProvider.GetMetadata(m.Vulnerability.ID, m.Vulnerability.Namespace)
@@ -51,10 +46,53 @@ func newMatch(m match.Match, p pkg.Package, metadataProvider vulnerability.Metad
 		}
 	}
 
+	vuln := NewVulnerability(m.Vulnerability, metadata)
 	return &Match{
-		Vulnerability:          NewVulnerability(m.Vulnerability, metadata),
-		Artifact:               newPackage(p),
+		Vulnerability:          vuln,
+		Artifact:               newPackage(p, m.Details.Upstreams()),
 		RelatedVulnerabilities: relatedVulnerabilities,
+		References:             NewReferences(vuln, relatedVulnerabilities),
 		MatchDetails:           details,
 	}, nil
 }
+
+// NewRelatedVulnerabilities returns the metadata of the related vulnerabilities of the match (e.g. the CVE of a GHSA),
+// where a related vulnerability without metadata (e.g. an alias from another vulnerability database) is reported by
+// its ID and namespace alone.
+func NewRelatedVulnerabilities(m match.Match, metadataProvider vulnerability.MetadataProvider) ([]VulnerabilityMetadata, error) {
+	relatedVulnerabilities := make([]VulnerabilityMetadata, 0)
+	for _, r := range m.Vulnerability.RelatedVulnerabilities {
+		relatedMetadata, err := metadataProvider.GetMetadata(r.ID, r.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("unable to fetch related vuln=%q metadata: %+v", r, err)
+		}
+		relatedVulnerabilities = append(relatedVulnerabilities, NewVulnerabilityMetadata(r.ID, r.Namespace, relatedMetadata))
+	}
+	return relatedVulnerabilities, nil
+}
+
+// NewReferences returns the reference URLs of a vulnerability (without duplicates): the data source and the URLs of
+// the vulnerability, the links of its advisories, then the data sources and the URLs of its related vulnerabilities.
+func NewReferences(vuln Vulnerability, related []VulnerabilityMetadata) []string {
+	references := make([]string, 0)
+	seen := make(map[string]bool)
+	add := func(urls ...string) {
+		for _, u := range urls {
+			if u != "" && !seen[u] {
+				seen[u] = true
+				references = append(references, u)
+			}
+		}
+	}
+
+	add(vuln.DataSource)
+	add(vuln.URLs...)
+	for _, a := range vuln.Advisories {
+		add(a.Link)
+	}
+	for _, r := range related {
+		add(r.DataSource)
+		add(r.URLs...)
+	}
+	return references
+}
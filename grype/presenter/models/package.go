@@ -8,18 +8,35 @@ import (
 
 // Package is meant to be only the fields that are needed when displaying a single pkg.Package object for the JSON presenter.
 type Package struct {
-	Name      string                   `json:"name"`
-	Version   string                   `json:"version"`
-	Type      syftPkg.Type             `json:"type"`
-	Locations []syftSource.Coordinates `json:"locations"`
-	Language  syftPkg.Language         `json:"language"`
-	Licenses  []string                 `json:"licenses"`
-	CPEs      []string                 `json:"cpes"`
-	PURL      string                   `json:"purl"`
-	Metadata  interface{}              `json:"metadata"`
+	Name         string                   `json:"name"`
+	Version      string                   `json:"version"`
+	Type         syftPkg.Type             `json:"type"`
+	Locations    []syftSource.Coordinates `json:"locations"`
+	Language     syftPkg.Language         `json:"language"`
+	Licenses     []string                 `json:"licenses"`
+	CPEs         []string                 `json:"cpes"`
+	PURL         string                   `json:"purl"`
+	Upstreams    []UpstreamPackage        `json:"upstreams"` // the upstream packages searched to make an indirect match (if any)
+	Layer        *Layer                   `json:"layer,omitempty"`
+	Scope        string                   `json:"scope,omitempty"`        // the dependency scope of the package (e.g. "development"), if the package is only needed to develop or test a project
+	SuggestedFix string                   `json:"suggestedFix,omitempty"` // the lowest version of the package that fixes all of its (not ignored) vulnerabilities with a fix
+	Metadata     interface{}              `json:"metadata"`
 }
 
-func newPackage(p pkg.Package) Package {
+// Layer is the JSON representation of the image layer that introduced a package.
+type Layer struct {
+	Index   int    `json:"index"`
+	Digest  string `json:"digest"`
+	Command string `json:"command,omitempty"`
+}
+
+// UpstreamPackage is the JSON representation of a package that the artifact was built from.
+type UpstreamPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+func newPackage(p pkg.Package, matchedUpstreams []pkg.UpstreamPackage) Package {
 	var cpes = make([]string, 0)
 	for _, c := range p.CPEs {
 		cpes = append(cpes, c.BindToFmtString())
@@ -35,6 +52,23 @@ func newPackage(p pkg.Package) Package {
 		coordinates = append(coordinates, l.Coordinates)
 	}
 
+	var upstreams = make([]UpstreamPackage, 0)
+	for _, u := range matchedUpstreams {
+		upstreams = append(upstreams, UpstreamPackage{
+			Name:    u.Name,
+			Version: u.Version,
+		})
+	}
+
+	var layer *Layer
+	if p.Layer != nil {
+		layer = &Layer{
+			Index:   p.Layer.Index,
+			Digest:  p.Layer.Digest,
+			Command: p.Layer.Command,
+		}
+	}
+
 	return Package{
 		Name:      p.Name,
 		Version:   p.Version,
@@ -44,6 +78,9 @@ func newPackage(p pkg.Package) Package {
 		Type:      p.Type,
 		CPEs:      cpes,
 		PURL:      p.PURL,
+		Upstreams: upstreams,
+		Layer:     layer,
+		Scope:     p.Scope,
 		Metadata:  p.Metadata,
 	}
 }
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"github.com/anchore/grype/grype/policy"
+)
+
+// policyDecision is the decision of the configured policy over the scan result.
+type policyDecision struct {
+	Policy   string   `json:"policy"`             // the policy that made the decision (e.g. the path of the policy file)
+	Passed   bool     `json:"passed"`             // whether the policy allowed the scan result
+	Denials  []string `json:"denials,omitempty"`  // the reasons the policy denied the scan result
+	Warnings []string `json:"warnings,omitempty"` // the reasons the policy warned about the scan result
+}
+
+// newPolicyDecision creates a struct with the policy decision to be represented in JSON (or nil without a decision).
+func newPolicyDecision(d *policy.Decision) *policyDecision {
+	if d == nil {
+		return nil
+	}
+	return &policyDecision{
+		Policy:   d.Policy,
+		Passed:   d.Passed(),
+		Denials:  d.Denials,
+		Warnings: d.Warnings,
+	}
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/anchore/grype/internal"
+	"github.com/anchore/grype/internal/jsonschema"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// schema identifies the version of the shape of the JSON document, so that consumers can pin the shape they parse.
+type schema struct {
+	Version string `json:"version"`
+	URL     string `json:"url"`
+}
+
+func newSchema() schema {
+	return schema{
+		Version: internal.JSONSchemaVersion,
+		URL:     JSONSchemaURL(internal.JSONSchemaVersion),
+	}
+}
+
+// JSONSchemaURL returns where the JSON schema of the given schema version is published.
+func JSONSchemaURL(version string) string {
+	return fmt.Sprintf("https://raw.githubusercontent.com/anchore/grype/main/schema/json/schema-%s.json", version)
+}
+
+// JSONSchema returns the JSON schema of the JSON document (see Document) for the current schema version.
+func JSONSchema() *jsonschema.Schema {
+	s := jsonschema.Reflect(Document{})
+	s.ID = JSONSchemaURL(internal.JSONSchemaVersion)
+	s.Title = fmt.Sprintf("%s JSON report (schema version %s)", internal.ApplicationName, internal.JSONSchemaVersion)
+	return s
+}
+
+// ValidateDocument checks the given JSON document against the JSON schema of the current schema version, returning a
+// description of each violation (none when the document is valid). An error is returned when the document cannot be
+// validated: when it is not a JSON object, or has no (or another) schema version.
+func ValidateDocument(contents []byte) ([]string, error) {
+	var doc struct {
+		Schema *schema `json:"schema"`
+	}
+	if err := json.Unmarshal(contents, &doc); err != nil {
+		return nil, fmt.Errorf("unable to parse document: %w", err)
+	}
+	if doc.Schema == nil || doc.Schema.Version == "" {
+		return nil, fmt.Errorf("document has no schema version (is it a JSON report, see \"-o json\"?)")
+	}
+	if doc.Schema.Version != internal.JSONSchemaVersion {
+		return nil, fmt.Errorf("document has schema version %s, but only schema version %s is supported (see %s)",
+			doc.Schema.Version, internal.JSONSchemaVersion, JSONSchemaURL(doc.Schema.Version))
+	}
+
+	result, err := gojsonschema.Validate(gojsonschema.NewGoLoader(JSONSchema()), gojsonschema.NewBytesLoader(contents))
+	if err != nil {
+		return nil, fmt.Errorf("unable to validate document: %w", err)
+	}
+
+	var violations []string
+	for _, e := range result.Errors() {
+		violations = append(violations, e.String())
+	}
+	return violations, nil
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/internal"
+)
+
+func TestJSONSchema_IsPublished(t *testing.T) {
+	// the published schema of the current version must describe the current shape of the document: when the shape
+	// changes, bump internal.JSONSchemaVersion and run "make generate-json-schema"
+	published, err := ioutil.ReadFile(fmt.Sprintf("../../../schema/json/schema-%s.json", internal.JSONSchemaVersion))
+	require.NoError(t, err)
+
+	var actual bytes.Buffer
+	enc := json.NewEncoder(&actual)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", " ")
+	require.NoError(t, enc.Encode(JSONSchema()))
+
+	assert.Equal(t, string(published), actual.String())
+}
+
+func TestValidateDocument(t *testing.T) {
+	matches, packages, context, metadataProvider, appConfig, dbStatus := GenerateAnalysis(t)
+	doc, err := NewDocument(packages, context, matches, nil, metadataProvider, appConfig, dbStatus)
+	require.NoError(t, err)
+	valid, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		document   string
+		violations int
+		err        string
+	}{
+		{
+			name:     "valid",
+			document: string(valid),
+		},
+		{
+			name:       "wrong types and unknown properties",
+			document:   fmt.Sprintf(`{"matches": {}, "extra": true, "schema": {"version": %q, "url": ""}}`, internal.JSONSchemaVersion),
+			violations: 6, // the type of matches (reported twice, as it is nullable), the extra property, and the missing source, distro and descriptor
+		},
+		{
+			name:     "not JSON",
+			document: "matches: []",
+			err:      "unable to parse document",
+		},
+		{
+			name:     "no schema version",
+			document: `{"matches": []}`,
+			err:      "document has no schema version",
+		},
+		{
+			name:     "another schema version",
+			document: `{"matches": [], "schema": {"version": "0.1.0"}}`,
+			err:      "document has schema version 0.1.0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			violations, err := ValidateDocument([]byte(test.document))
+			if test.err != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), test.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, violations, test.violations, "violations: %v", violations)
+		})
+	}
+}
@@ -1,15 +1,23 @@
 package models
 
-import "github.com/anchore/grype/grype/vulnerability"
+import (
+	"time"
+
+	"github.com/anchore/grype/grype/vulnerability"
+)
 
 type VulnerabilityMetadata struct {
-	ID          string   `json:"id"`
-	DataSource  string   `json:"dataSource"`
-	Namespace   string   `json:"namespace,omitempty"`
-	Severity    string   `json:"severity,omitempty"`
-	URLs        []string `json:"urls"`
-	Description string   `json:"description,omitempty"`
-	Cvss        []Cvss   `json:"cvss"`
+	ID           string     `json:"id"`
+	DataSource   string     `json:"dataSource"`
+	Namespace    string     `json:"namespace,omitempty"`
+	Severity     string     `json:"severity,omitempty"`
+	URLs         []string   `json:"urls"`
+	Description  string     `json:"description,omitempty"`
+	Cvss         []Cvss     `json:"cvss"`
+	Published    *time.Time `json:"published,omitempty"`
+	Modified     *time.Time `json:"modified,omitempty"`
+	CWEs         []string   `json:"cwes,omitempty"`
+	Exploitation string     `json:"exploitation,omitempty"`
 }
 
 func NewVulnerabilityMetadata(id, namespace string, metadata *vulnerability.Metadata) VulnerabilityMetadata {
@@ -17,6 +25,8 @@ func NewVulnerabilityMetadata(id, namespace string, metadata *vulnerability.Meta
 		return VulnerabilityMetadata{
 			ID:        id,
 			Namespace: namespace,
+			URLs:      make([]string, 0),
+			Cvss:      make([]Cvss, 0),
 		}
 	}
 
@@ -26,12 +36,16 @@ func NewVulnerabilityMetadata(id, namespace string, metadata *vulnerability.Meta
 	}
 
 	return VulnerabilityMetadata{
-		ID:          id,
-		DataSource:  metadata.DataSource,
-		Namespace:   metadata.Namespace,
-		Severity:    metadata.Severity,
-		URLs:        urls,
-		Description: metadata.Description,
-		Cvss:        NewCVSS(metadata),
+		ID:           id,
+		DataSource:   metadata.DataSource,
+		Namespace:    metadata.Namespace,
+		Severity:     metadata.Severity,
+		URLs:         urls,
+		Description:  metadata.Description,
+		Cvss:         NewCVSS(metadata),
+		Published:    metadata.Published,
+		Modified:     metadata.Modified,
+		CWEs:         metadata.CWEs,
+		Exploitation: metadata.Exploitation,
 	}
 }
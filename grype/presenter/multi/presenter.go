@@ -0,0 +1,251 @@
+package multi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/anchore/grype/grype/history"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/presenter/table"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/olekukonko/tablewriter"
+)
+
+// Target is the result of scanning one of several targets.
+type Target struct {
+	// Input is the target as given by the user (e.g. "alpine:latest")
+	Input string
+	// Platform is the scanned platform of a multi-platform image (if a platform was selected)
+	Platform       string
+	Matches        match.Matches
+	IgnoredMatches []match.IgnoredMatch
+	Packages       []pkg.Package
+	Context        pkg.Context
+	// Err describes why the target could not be scanned (there are no results when set)
+	Err error
+}
+
+func (t Target) String() string {
+	if t.Platform == "" {
+		return t.Input
+	}
+	return fmt.Sprintf("%s (%s)", t.Input, t.Platform)
+}
+
+// TargetSummary is the number of vulnerabilities of a target by severity.
+type TargetSummary struct {
+	Target   string         `json:"target"`
+	Packages int            `json:"packages"`
+	Counts   history.Counts `json:"counts"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// Summary aggregates the vulnerabilities of all targets.
+type Summary struct {
+	Targets []TargetSummary `json:"targets"`
+	// Counts is the number of vulnerabilities of all targets by severity (a vulnerability is counted for each target)
+	Counts history.Counts `json:"counts"`
+	// Vulnerabilities is the number of distinct vulnerabilities across all targets
+	Vulnerabilities int `json:"vulnerabilities"`
+	// Failed is the number of targets that could not be scanned
+	Failed int `json:"failed"`
+}
+
+// Summarize counts the vulnerabilities of the given targets.
+func Summarize(targets []Target, metadataProvider vulnerability.MetadataProvider) (Summary, error) {
+	var summary Summary
+	vulnerabilities := make(map[string]struct{})
+	for _, t := range targets {
+		targetSummary := TargetSummary{Target: t.String(), Packages: len(t.Packages)}
+		if t.Err != nil {
+			targetSummary.Error = t.Err.Error()
+			summary.Failed++
+			summary.Targets = append(summary.Targets, targetSummary)
+			continue
+		}
+
+		var err error
+		// note: the enumeration is never stopped early, which would leak the enumerating goroutine
+		for m := range t.Matches.Enumerate() {
+			if err != nil {
+				continue
+			}
+			var metadata *vulnerability.Metadata
+			metadata, err = metadataProvider.GetMetadata(m.Vulnerability.ID, m.Vulnerability.Namespace)
+			if err != nil {
+				err = fmt.Errorf("unable to fetch vuln=%q metadata: %w", m.Vulnerability.ID, err)
+				continue
+			}
+			var severity vulnerability.Severity
+			if metadata != nil {
+				severity = vulnerability.ParseSeverity(metadata.Severity)
+			}
+			targetSummary.Counts.Add(severity)
+			vulnerabilities[m.Vulnerability.ID] = struct{}{}
+		}
+		if err != nil {
+			return Summary{}, err
+		}
+
+		summary.Counts = summary.Counts.Plus(targetSummary.Counts)
+		summary.Targets = append(summary.Targets, targetSummary)
+	}
+	summary.Vulnerabilities = len(vulnerabilities)
+	return summary, nil
+}
+
+// TablePresenter reports the table of each target in its own section, followed by a summary of all targets.
+type TablePresenter struct {
+	targets          []Target
+	metadataProvider vulnerability.MetadataProvider
+	options          table.Options
+}
+
+// NewTablePresenter is a *TablePresenter constructor
+func NewTablePresenter(targets []Target, metadataProvider vulnerability.MetadataProvider, options table.Options) *TablePresenter {
+	return &TablePresenter{
+		targets:          targets,
+		metadataProvider: metadataProvider,
+		options:          options,
+	}
+}
+
+// Present writes a section for each target and the summary of all targets
+func (pres *TablePresenter) Present(output io.Writer) error {
+	for _, t := range pres.targets {
+		if _, err := fmt.Fprintf(output, "TARGET: %s\n", t); err != nil {
+			return err
+		}
+		if t.Err != nil {
+			if _, err := fmt.Fprintf(output, "Failed to scan: %v\n\n", t.Err); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := table.NewPresenter(t.Matches, t.Packages, pres.metadataProvider, pres.options).Present(output); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(output, "\n"); err != nil {
+			return err
+		}
+	}
+
+	summary, err := Summarize(pres.targets, pres.metadataProvider)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(output, "SUMMARY\n"); err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, t := range summary.Targets {
+		rows = append(rows, summaryRow(t.Target, t.Packages, t.Counts, t.Error))
+	}
+	packages := 0
+	for _, t := range summary.Targets {
+		packages += t.Packages
+	}
+	rows = append(rows, summaryRow("total", packages, summary.Counts, ""))
+
+	writer := tablewriter.NewWriter(output)
+
+	writer.SetHeader([]string{"Target", "Packages", "Critical", "High", "Medium", "Low", "Negligible", "Unknown", "Error"})
+	writer.SetAutoWrapText(false)
+	writer.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	writer.SetAlignment(tablewriter.ALIGN_LEFT)
+
+	writer.SetHeaderLine(false)
+	writer.SetBorder(false)
+	writer.SetAutoFormatHeaders(true)
+	writer.SetCenterSeparator("")
+	writer.SetColumnSeparator("")
+	writer.SetRowSeparator("")
+	writer.SetTablePadding("  ")
+	writer.SetNoWhiteSpace(true)
+
+	writer.AppendBulk(rows)
+	writer.Render()
+
+	_, err = fmt.Fprintf(output, "\n%d distinct vulnerabilities across %d targets (%d failed)\n", summary.Vulnerabilities, len(summary.Targets), summary.Failed)
+	return err
+}
+
+func summaryRow(target string, packages int, counts history.Counts, err string) []string {
+	return []string{
+		target,
+		strconv.Itoa(packages),
+		strconv.Itoa(counts.Critical),
+		strconv.Itoa(counts.High),
+		strconv.Itoa(counts.Medium),
+		strconv.Itoa(counts.Low),
+		strconv.Itoa(counts.Negligible),
+		strconv.Itoa(counts.Unknown),
+		err,
+	}
+}
+
+// Document is the combined JSON report of several targets.
+type Document struct {
+	Targets []TargetDocument `json:"targets"`
+	Summary Summary          `json:"summary"`
+}
+
+// TargetDocument is the JSON report of a single target (as reported when scanning the target on its own).
+type TargetDocument struct {
+	Target   string           `json:"target"`
+	Platform string           `json:"platform,omitempty"`
+	Error    string           `json:"error,omitempty"`
+	Report   *models.Document `json:"report,omitempty"`
+}
+
+// JSONPresenter reports the JSON document of each target and the summary of all targets as a single JSON document.
+type JSONPresenter struct {
+	targets          []Target
+	metadataProvider vulnerability.MetadataProvider
+	appConfig        interface{}
+	dbStatus         interface{}
+}
+
+// NewJSONPresenter is a *JSONPresenter constructor
+func NewJSONPresenter(targets []Target, metadataProvider vulnerability.MetadataProvider, appConfig interface{}, dbStatus interface{}) *JSONPresenter {
+	return &JSONPresenter{
+		targets:          targets,
+		metadataProvider: metadataProvider,
+		appConfig:        appConfig,
+		dbStatus:         dbStatus,
+	}
+}
+
+// Present writes the combined JSON document
+func (pres *JSONPresenter) Present(output io.Writer) error {
+	var doc Document
+	for _, t := range pres.targets {
+		targetDoc := TargetDocument{Target: t.Input, Platform: t.Platform}
+		if t.Err != nil {
+			targetDoc.Error = t.Err.Error()
+		} else {
+			report, err := models.NewDocument(t.Packages, t.Context, t.Matches, t.IgnoredMatches, pres.metadataProvider, pres.appConfig, pres.dbStatus)
+			if err != nil {
+				return err
+			}
+			targetDoc.Report = &report
+		}
+		doc.Targets = append(doc.Targets, targetDoc)
+	}
+
+	var err error
+	if doc.Summary, err = Summarize(pres.targets, pres.metadataProvider); err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(output)
+	// prevent > and < from being escaped in the payload
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", " ")
+	return enc.Encode(&doc)
+}
@@ -0,0 +1,67 @@
+package multi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/anchore/grype/grype/history"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/presenter/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateTargets(t *testing.T) []Target {
+	t.Helper()
+	matches, packages, context, _, _, _ := models.GenerateAnalysis(t)
+	return []Target{
+		{Input: "alpine:3.14", Matches: matches, Packages: packages, Context: context},
+		{Input: "dir:./src", Matches: matches, Packages: packages, Context: context},
+		{Input: "missing:latest", Err: errors.New("image not found")},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	summary, err := Summarize(generateTargets(t), models.NewMetadataMock())
+	require.NoError(t, err)
+
+	assert.Equal(t, []TargetSummary{
+		{Target: "alpine:3.14", Packages: 2, Counts: history.Counts{Critical: 1, Low: 1}},
+		{Target: "dir:./src", Packages: 2, Counts: history.Counts{Critical: 1, Low: 1}},
+		{Target: "missing:latest", Error: "image not found"},
+	}, summary.Targets)
+	assert.Equal(t, history.Counts{Critical: 2, Low: 2}, summary.Counts)
+	assert.Equal(t, 2, summary.Vulnerabilities)
+	assert.Equal(t, 1, summary.Failed)
+}
+
+func TestTablePresenter(t *testing.T) {
+	var buffer bytes.Buffer
+	require.NoError(t, NewTablePresenter(generateTargets(t), models.NewMetadataMock(), table.Options{}).Present(&buffer))
+	output := buffer.String()
+
+	assert.Contains(t, output, "TARGET: alpine:3.14\n")
+	assert.Contains(t, output, "TARGET: dir:./src\n")
+	assert.Contains(t, output, "TARGET: missing:latest\nFailed to scan: image not found\n")
+	assert.Contains(t, output, "CVE-1999-0002")
+	assert.Contains(t, output, "SUMMARY\n")
+	assert.Contains(t, output, "2 distinct vulnerabilities across 3 targets (1 failed)")
+}
+
+func TestJSONPresenter(t *testing.T) {
+	var buffer bytes.Buffer
+	require.NoError(t, NewJSONPresenter(generateTargets(t), models.NewMetadataMock(), nil, nil).Present(&buffer))
+
+	var doc Document
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &doc))
+
+	require.Len(t, doc.Targets, 3)
+	assert.Equal(t, "alpine:3.14", doc.Targets[0].Target)
+	require.NotNil(t, doc.Targets[0].Report)
+	assert.Len(t, doc.Targets[0].Report.Matches, 2)
+	assert.Equal(t, "image not found", doc.Targets[2].Error)
+	assert.Nil(t, doc.Targets[2].Report)
+	assert.Equal(t, history.Counts{Critical: 2, Low: 2}, doc.Summary.Counts)
+}
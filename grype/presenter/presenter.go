@@ -1,14 +1,18 @@
 package presenter
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/anchore/grype/grype/presenter/template"
 
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/attestation"
 	"github.com/anchore/grype/grype/presenter/cyclonedx"
 	"github.com/anchore/grype/grype/presenter/json"
+	"github.com/anchore/grype/grype/presenter/multi"
+	"github.com/anchore/grype/grype/presenter/proto"
 	"github.com/anchore/grype/grype/presenter/table"
 	"github.com/anchore/grype/grype/vulnerability"
 )
@@ -23,12 +27,42 @@ func GetPresenter(presenterConfig Config, matches match.Matches, ignoredMatches
 	switch presenterConfig.format {
 	case jsonFormat:
 		return json.NewPresenter(matches, ignoredMatches, packages, context, metadataProvider, appConfig, dbStatus)
+	case jsonLinesFormat:
+		return json.NewLinesPresenter(matches, packages, metadataProvider)
 	case tableFormat:
-		return table.NewPresenter(matches, packages, metadataProvider)
+		return table.NewPresenter(matches, packages, metadataProvider, presenterConfig.table)
 	case cycloneDXFormat:
 		return cyclonedx.NewPresenter(matches, packages, context.Source, metadataProvider)
 	case templateFormat:
 		return template.NewPresenter(matches, ignoredMatches, packages, context, metadataProvider, appConfig, dbStatus, presenterConfig.templateFilePath)
+	case attestFormat:
+		return attestation.NewPresenter(matches, ignoredMatches, packages, context, metadataProvider, appConfig, dbStatus, presenterConfig.attestation)
+	case protoFormat:
+		return proto.NewPresenter(matches, ignoredMatches, packages, context, metadataProvider, appConfig, dbStatus)
+	default:
+		return nil
+	}
+}
+
+// ValidateMultiTarget checks that the format of the config supports reporting several targets, where only some formats
+// support several targets.
+func ValidateMultiTarget(presenterConfig Config) error {
+	switch presenterConfig.format {
+	case jsonFormat, tableFormat:
+		return nil
+	default:
+		return fmt.Errorf("the %q output format does not support scanning several targets (use %q or %q)", presenterConfig.format, tableFormat, jsonFormat)
+	}
+}
+
+// GetMultiTargetPresenter retrieves a Presenter of the combined report of several targets that matches a CLI option
+// (which must be validated with ValidateMultiTarget)
+func GetMultiTargetPresenter(presenterConfig Config, targets []multi.Target, metadataProvider vulnerability.MetadataProvider, appConfig interface{}, dbStatus interface{}) Presenter {
+	switch presenterConfig.format {
+	case jsonFormat:
+		return multi.NewJSONPresenter(targets, metadataProvider, appConfig, dbStatus)
+	case tableFormat:
+		return multi.NewTablePresenter(targets, metadataProvider, presenterConfig.table)
 	default:
 		return nil
 	}
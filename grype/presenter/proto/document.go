@@ -0,0 +1,245 @@
+package proto
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/anchore/grype/grype/presenter/models"
+)
+
+// NewDocument converts the model of the JSON document into a protobuf Document with the same content.
+func NewDocument(doc models.Document) (*Document, error) {
+	result := &Document{
+		Distro: &Distribution{
+			Name:    doc.Distro.Name,
+			Version: doc.Distro.Version,
+			IdLike:  doc.Distro.IDLike,
+		},
+		Schema: &Schema{
+			Version: doc.Schema.Version,
+			Url:     doc.Schema.URL,
+		},
+	}
+
+	for _, m := range doc.Matches {
+		match, err := newMatch(m)
+		if err != nil {
+			return nil, err
+		}
+		result.Matches = append(result.Matches, match)
+	}
+
+	for _, m := range doc.IgnoredMatches {
+		match, err := newMatch(m.Match)
+		if err != nil {
+			return nil, err
+		}
+		ignored := &IgnoredMatch{Match: match}
+		for _, r := range m.AppliedIgnoreRules {
+			ignored.AppliedIgnoreRules = append(ignored.AppliedIgnoreRules, newIgnoreRule(r))
+		}
+		result.IgnoredMatches = append(result.IgnoredMatches, ignored)
+	}
+
+	if doc.Source != nil {
+		target, err := jsonValue(doc.Source.Target)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode source target: %w", err)
+		}
+		result.Source = &Source{
+			Type:   doc.Source.Type,
+			Target: target,
+		}
+	}
+
+	if eol := doc.Distro.EndOfLife; eol != nil {
+		result.Distro.EndOfLife = &EndOfLife{
+			Date:    eol.Date,
+			Reached: eol.Reached,
+		}
+	}
+
+	configuration, err := jsonValue(doc.Descriptor.Configuration)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode configuration: %w", err)
+	}
+	dbStatus, err := jsonValue(doc.Descriptor.VulnerabilityDBStatus)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode database status: %w", err)
+	}
+	result.Descriptor_ = &Descriptor{
+		Name:          doc.Descriptor.Name,
+		Version:       doc.Descriptor.Version,
+		Configuration: configuration,
+		Db:            dbStatus,
+	}
+
+	if p := doc.Policy; p != nil {
+		result.Policy = &PolicyDecision{
+			Policy:   p.Policy,
+			Passed:   p.Passed,
+			Denials:  p.Denials,
+			Warnings: p.Warnings,
+		}
+	}
+
+	return result, nil
+}
+
+func newMatch(m models.Match) (*Match, error) {
+	vulnerabilityMetadata, err := newVulnerabilityMetadata(m.Vulnerability.VulnerabilityMetadata)
+	if err != nil {
+		return nil, err
+	}
+	vuln := &Vulnerability{
+		Metadata: vulnerabilityMetadata,
+		Fix: &Fix{
+			Versions: m.Vulnerability.Fix.Versions,
+			State:    m.Vulnerability.Fix.State,
+		},
+	}
+	for _, a := range m.Vulnerability.Advisories {
+		vuln.Advisories = append(vuln.Advisories, &Advisory{Id: a.ID, Link: a.Link})
+	}
+
+	var related []*VulnerabilityMetadata
+	for _, r := range m.RelatedVulnerabilities {
+		metadata, err := newVulnerabilityMetadata(r)
+		if err != nil {
+			return nil, err
+		}
+		related = append(related, metadata)
+	}
+
+	var details []*MatchDetails
+	for _, d := range m.MatchDetails {
+		searchedBy, err := jsonValue(d.SearchedBy)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode match details of vuln=%q: %w", m.Vulnerability.ID, err)
+		}
+		found, err := jsonValue(d.Found)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode match details of vuln=%q: %w", m.Vulnerability.ID, err)
+		}
+		details = append(details, &MatchDetails{
+			Type:       d.Type,
+			Matcher:    d.Matcher,
+			SearchedBy: searchedBy,
+			Found:      found,
+		})
+	}
+
+	artifact, err := newPackage(m.Artifact)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Match{
+		Vulnerability:          vuln,
+		RelatedVulnerabilities: related,
+		References:             m.References,
+		MatchDetails:           details,
+		Artifact:               artifact,
+	}, nil
+}
+
+func newVulnerabilityMetadata(m models.VulnerabilityMetadata) (*VulnerabilityMetadata, error) {
+	result := &VulnerabilityMetadata{
+		Id:           m.ID,
+		DataSource:   m.DataSource,
+		Namespace:    m.Namespace,
+		Severity:     m.Severity,
+		Urls:         m.URLs,
+		Description:  m.Description,
+		Published:    timestamp(m.Published),
+		Modified:     timestamp(m.Modified),
+		Cwes:         m.CWEs,
+		Exploitation: m.Exploitation,
+	}
+	for _, c := range m.Cvss {
+		vendorMetadata, err := jsonValue(c.VendorMetadata)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode CVSS vendor metadata of vuln=%q: %w", m.ID, err)
+		}
+		result.Cvss = append(result.Cvss, &Cvss{
+			Version: c.Version,
+			Vector:  c.Vector,
+			Metrics: &CvssMetrics{
+				BaseScore:           c.Metrics.BaseScore,
+				ExploitabilityScore: c.Metrics.ExploitabilityScore,
+				ImpactScore:         c.Metrics.ImpactScore,
+			},
+			VendorMetadata: vendorMetadata,
+		})
+	}
+	return result, nil
+}
+
+func newPackage(p models.Package) (*Package, error) {
+	metadata, err := jsonValue(p.Metadata)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode metadata of package=%q: %w", p.Name, err)
+	}
+
+	result := &Package{
+		Name:         p.Name,
+		Version:      p.Version,
+		Type:         string(p.Type),
+		Language:     string(p.Language),
+		Licenses:     p.Licenses,
+		Cpes:         p.CPEs,
+		Purl:         p.PURL,
+		Scope:        p.Scope,
+		SuggestedFix: p.SuggestedFix,
+		Metadata:     metadata,
+	}
+	for _, l := range p.Locations {
+		result.Locations = append(result.Locations, &Coordinates{Path: l.RealPath, LayerId: l.FileSystemID})
+	}
+	for _, u := range p.Upstreams {
+		result.Upstreams = append(result.Upstreams, &UpstreamPackage{Name: u.Name, Version: u.Version})
+	}
+	if l := p.Layer; l != nil {
+		result.Layer = &Layer{
+			Index:   int32(l.Index),
+			Digest:  l.Digest,
+			Command: l.Command,
+		}
+	}
+	return result, nil
+}
+
+func newIgnoreRule(r models.IgnoreRule) *IgnoreRule {
+	result := &IgnoreRule{
+		Vulnerability: r.Vulnerability,
+		FixState:      r.FixState,
+		Reason:        r.Reason,
+	}
+	if p := r.Package; p != nil {
+		result.Package = &IgnoreRulePackage{
+			Name:     p.Name,
+			Version:  p.Version,
+			Type:     p.Type,
+			Location: p.Location,
+			Purl:     p.PURL,
+		}
+	}
+	return result
+}
+
+// jsonValue returns the JSON encoding of a value without a fixed shape, where a nil value is not encoded.
+func jsonValue(v interface{}) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// timestamp returns the RFC 3339 form of the given time (as within the JSON output), which is empty when unknown.
+func timestamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
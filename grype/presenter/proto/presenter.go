@@ -0,0 +1,59 @@
+package proto
+
+import (
+	"fmt"
+	"io"
+
+	protobuf "google.golang.org/protobuf/proto"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// Presenter reports the scan result as a binary protobuf Document (see document.proto), which is much cheaper to parse
+// than the JSON output when ingesting a large number of scan results.
+type Presenter struct {
+	matches          match.Matches
+	ignoredMatches   []match.IgnoredMatch
+	packages         []pkg.Package
+	context          pkg.Context
+	metadataProvider vulnerability.MetadataProvider
+	appConfig        interface{}
+	dbStatus         interface{}
+}
+
+// NewPresenter is a *Presenter constructor
+func NewPresenter(matches match.Matches, ignoredMatches []match.IgnoredMatch, packages []pkg.Package, context pkg.Context, metadataProvider vulnerability.MetadataProvider, appConfig interface{}, dbStatus interface{}) *Presenter {
+	return &Presenter{
+		matches:          matches,
+		ignoredMatches:   ignoredMatches,
+		packages:         packages,
+		metadataProvider: metadataProvider,
+		context:          context,
+		appConfig:        appConfig,
+		dbStatus:         dbStatus,
+	}
+}
+
+// Present writes the protobuf encoding of the scan result
+func (pres *Presenter) Present(output io.Writer) error {
+	doc, err := models.NewDocument(pres.packages, pres.context, pres.matches, pres.ignoredMatches, pres.metadataProvider,
+		pres.appConfig, pres.dbStatus)
+	if err != nil {
+		return err
+	}
+
+	message, err := NewDocument(doc)
+	if err != nil {
+		return err
+	}
+
+	contents, err := protobuf.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("unable to encode document: %w", err)
+	}
+	_, err = output.Write(contents)
+	return err
+}
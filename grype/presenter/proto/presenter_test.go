@@ -0,0 +1,92 @@
+package proto
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	protobuf "google.golang.org/protobuf/proto"
+
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/internal"
+)
+
+func TestPresenter(t *testing.T) {
+	matches, packages, context, metadataProvider, _, _ := models.GenerateAnalysis(t)
+	appConfig := map[string]interface{}{"output": "proto"}
+
+	var buffer bytes.Buffer
+	pres := NewPresenter(matches, nil, packages, context, metadataProvider, appConfig, nil)
+	require.NoError(t, pres.Present(&buffer))
+
+	var actual Document
+	require.NoError(t, protobuf.Unmarshal(buffer.Bytes(), &actual))
+
+	expected, err := models.NewDocument(packages, context, matches, nil, metadataProvider, appConfig, nil)
+	require.NoError(t, err)
+
+	require.Len(t, actual.Matches, len(expected.Matches))
+	for idx, m := range actual.Matches {
+		e := expected.Matches[idx]
+		assert.Equal(t, e.Vulnerability.ID, m.GetVulnerability().GetMetadata().GetId())
+		assert.Equal(t, e.Vulnerability.Severity, m.GetVulnerability().GetMetadata().GetSeverity())
+		assert.Equal(t, e.Vulnerability.Fix.State, m.GetVulnerability().GetFix().GetState())
+		assert.Len(t, m.GetVulnerability().GetMetadata().GetCvss(), len(e.Vulnerability.Cvss))
+		assert.Len(t, m.GetRelatedVulnerabilities(), len(e.RelatedVulnerabilities))
+		assert.Equal(t, e.Artifact.Name, m.GetArtifact().GetName())
+		assert.Equal(t, e.Artifact.Version, m.GetArtifact().GetVersion())
+		assert.Equal(t, string(e.Artifact.Type), m.GetArtifact().GetType())
+		require.Len(t, m.GetMatchDetails(), len(e.MatchDetails))
+
+		// values without a fixed shape are JSON encoded
+		searchedBy, err := json.Marshal(e.MatchDetails[0].SearchedBy)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(searchedBy), string(m.GetMatchDetails()[0].GetSearchedBy()))
+	}
+
+	assert.Equal(t, "image", actual.GetSource().GetType())
+	assert.NotEmpty(t, actual.GetSource().GetTarget())
+	assert.Equal(t, "centos", actual.GetDistro().GetName())
+	assert.Equal(t, []string{"rhel"}, actual.GetDistro().GetIdLike())
+	assert.Equal(t, internal.ApplicationName, actual.GetDescriptor_().GetName())
+	assert.JSONEq(t, `{"output": "proto"}`, string(actual.GetDescriptor_().GetConfiguration()))
+	assert.Empty(t, actual.GetDescriptor_().GetDb())
+	assert.Equal(t, internal.JSONSchemaVersion, actual.GetSchema().GetVersion())
+}
+
+func TestNewDocument_IgnoredMatches(t *testing.T) {
+	doc := models.Document{
+		IgnoredMatches: []models.IgnoredMatch{
+			{
+				Match: models.Match{
+					Vulnerability: models.Vulnerability{
+						VulnerabilityMetadata: models.VulnerabilityMetadata{ID: "CVE-2021-1"},
+					},
+					Artifact: models.Package{Name: "openssl"},
+				},
+				AppliedIgnoreRules: []models.IgnoreRule{
+					{
+						Vulnerability: "CVE-2021-1",
+						Reason:        "not reachable",
+						Package:       &models.IgnoreRulePackage{Name: "openssl"},
+					},
+				},
+			},
+		},
+	}
+
+	actual, err := NewDocument(doc)
+	require.NoError(t, err)
+	assert.Empty(t, actual.GetMatches())
+	assert.Nil(t, actual.GetSource())
+	assert.Nil(t, actual.GetPolicy())
+
+	require.Len(t, actual.GetIgnoredMatches(), 1)
+	ignored := actual.GetIgnoredMatches()[0]
+	assert.Equal(t, "CVE-2021-1", ignored.GetMatch().GetVulnerability().GetMetadata().GetId())
+	require.Len(t, ignored.GetAppliedIgnoreRules(), 1)
+	assert.Equal(t, "not reachable", ignored.GetAppliedIgnoreRules()[0].GetReason())
+	assert.Equal(t, "openssl", ignored.GetAppliedIgnoreRules()[0].GetPackage().GetName())
+}
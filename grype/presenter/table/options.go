@@ -0,0 +1,110 @@
+package table
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortBy is the order of the rows of the table.
+type SortBy string
+
+const (
+	// SortByPackage orders the rows by the package name and version (the default)
+	SortByPackage SortBy = "package"
+	// SortBySeverity orders the rows by severity (the most severe first)
+	SortBySeverity SortBy = "severity"
+	// SortByVulnerability orders the rows by the vulnerability ID
+	SortByVulnerability SortBy = "vuln"
+	// SortByFix orders the rows by the fix state (fixed first, won't fix last)
+	SortByFix SortBy = "fix"
+)
+
+// SortOptions are the orders of the rows that can be selected.
+var SortOptions = []SortBy{SortByPackage, SortBySeverity, SortByVulnerability, SortByFix}
+
+// Column is a column of the table.
+type Column string
+
+const (
+	NameColumn          Column = "name"
+	InstalledColumn     Column = "installed"
+	FixedInColumn       Column = "fixed-in"
+	SuggestedFixColumn  Column = "suggested-fix"
+	VulnerabilityColumn Column = "vulnerability"
+	SeverityColumn      Column = "severity"
+	TypeColumn          Column = "type"
+	LayerColumn         Column = "layer"
+	ScopeColumn         Column = "scope"
+	RelatedColumn       Column = "related"
+	ReferencesColumn    Column = "references"
+	PublishedColumn     Column = "published"
+)
+
+// AvailableColumns are the columns that can be selected.
+var AvailableColumns = []Column{NameColumn, InstalledColumn, FixedInColumn, SuggestedFixColumn, VulnerabilityColumn, SeverityColumn, TypeColumn, LayerColumn, ScopeColumn, RelatedColumn, ReferencesColumn, PublishedColumn}
+
+// defaultColumns are the columns of the table when no columns are selected (with the layer column when any package is
+// attributed to a layer).
+var defaultColumns = []Column{NameColumn, InstalledColumn, FixedInColumn, VulnerabilityColumn, SeverityColumn}
+
+var columnHeaders = map[Column]string{
+	NameColumn:          "Name",
+	InstalledColumn:     "Installed",
+	FixedInColumn:       "Fixed-In",
+	SuggestedFixColumn:  "Suggested-Fix",
+	VulnerabilityColumn: "Vulnerability",
+	SeverityColumn:      "Severity",
+	TypeColumn:          "Type",
+	LayerColumn:         "Layer",
+	ScopeColumn:         "Scope",
+	RelatedColumn:       "Related",
+	ReferencesColumn:    "References",
+	PublishedColumn:     "Published",
+}
+
+// refColumns are the columns that are added to the table in the wide mode of references.
+var refColumns = []Column{RelatedColumn, ReferencesColumn}
+
+// Options are the order and the columns of the table.
+type Options struct {
+	SortBy SortBy
+	// Columns are the columns of the table in the given order (the default columns when empty)
+	Columns []Column
+	// ShowRefs adds the related vulnerabilities and the reference URLs of each vulnerability to the table (unless these
+	// columns are selected), with one reference URL on each line of the row
+	ShowRefs bool
+}
+
+// ParseOptions returns the options of the given order and column names, where the empty order is the default order.
+func ParseOptions(sortBy string, columns []string) (Options, error) {
+	options := Options{SortBy: SortByPackage}
+	if sortBy != "" {
+		options.SortBy = SortBy(strings.ToLower(sortBy))
+		if !isSortOption(options.SortBy) {
+			return Options{}, fmt.Errorf("unsupported table order %q, options are: %+v", sortBy, SortOptions)
+		}
+	}
+
+	seen := make(map[Column]bool)
+	for _, name := range columns {
+		c := Column(strings.ToLower(strings.TrimSpace(name)))
+		if _, ok := columnHeaders[c]; !ok {
+			return Options{}, fmt.Errorf("unsupported table column %q, available columns are: %+v", name, AvailableColumns)
+		}
+		if seen[c] {
+			return Options{}, fmt.Errorf("table column %q is given more than once", name)
+		}
+		seen[c] = true
+		options.Columns = append(options.Columns, c)
+	}
+	return options, nil
+}
+
+func isSortOption(sortBy SortBy) bool {
+	for _, s := range SortOptions {
+		if s == sortBy {
+			return true
+		}
+	}
+	return false
+}
@@ -9,33 +9,56 @@ import (
 	grypeDb "github.com/anchore/grype/grype/db/v3"
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
 	"github.com/anchore/grype/grype/vulnerability"
 	"github.com/olekukonko/tablewriter"
 )
 
+// maxLayerNameLength is the length that the description of a layer is truncated to (commands can be very long).
+const maxLayerNameLength = 40
+
 // Presenter is a generic struct for holding fields needed for reporting
 type Presenter struct {
 	results          match.Matches
 	packages         []pkg.Package
 	metadataProvider vulnerability.MetadataProvider
+	options          Options
 }
 
 // NewPresenter is a *Presenter constructor
-func NewPresenter(results match.Matches, packages []pkg.Package, metadataProvider vulnerability.MetadataProvider) *Presenter {
+func NewPresenter(results match.Matches, packages []pkg.Package, metadataProvider vulnerability.MetadataProvider, options Options) *Presenter {
 	return &Presenter{
 		results:          results,
 		packages:         packages,
 		metadataProvider: metadataProvider,
+		options:          options,
 	}
 }
 
+// row is a match of the table, with the values of every column (of which only the selected columns are reported).
+type row struct {
+	values   map[Column]string
+	severity vulnerability.Severity
+	fixRank  int
+}
+
 // Present creates a JSON-based reporting
 func (pres *Presenter) Present(output io.Writer) error {
-	rows := make([][]string, 0)
+	columns := pres.options.Columns
+	if len(columns) == 0 {
+		columns = defaultColumns
+		if hasLayers(pres.results) {
+			columns = append(columns, LayerColumn)
+		}
+	}
+	if pres.options.ShowRefs {
+		columns = withColumns(columns, refColumns...)
+	}
 
-	columns := []string{"Name", "Installed", "Fixed-In", "Vulnerability", "Severity"}
+	suggestedFixes := match.SuggestedFixes(pres.results)
+	var matchRows []row
 	for m := range pres.results.Enumerate() {
-		var severity string
+		var severity, published string
 
 		metadata, err := pres.metadataProvider.GetMetadata(m.Vulnerability.ID, m.Vulnerability.Namespace)
 		if err != nil {
@@ -44,6 +67,9 @@ func (pres *Presenter) Present(output io.Writer) error {
 
 		if metadata != nil {
 			severity = metadata.Severity
+			if metadata.Published != nil {
+				published = metadata.Published.Format("2006-01-02")
+			}
 		}
 
 		fixVersion := strings.Join(m.Vulnerability.Fix.Versions, ", ")
@@ -54,28 +80,57 @@ func (pres *Presenter) Present(output io.Writer) error {
 			fixVersion = ""
 		}
 
-		rows = append(rows, []string{m.Package.Name, m.Package.Version, fixVersion, m.Vulnerability.ID, severity})
+		related, references, err := pres.references(m, metadata, columns)
+		if err != nil {
+			return err
+		}
+
+		matchRows = append(matchRows, row{
+			values: map[Column]string{
+				NameColumn:          packageName(m),
+				InstalledColumn:     m.Package.Version,
+				FixedInColumn:       fixVersion,
+				SuggestedFixColumn:  suggestedFixes[m.Package.ID],
+				VulnerabilityColumn: m.Vulnerability.ID,
+				SeverityColumn:      severity,
+				TypeColumn:          string(m.Package.Type),
+				LayerColumn:         layerName(m.Package.Layer),
+				ScopeColumn:         m.Package.Scope,
+				RelatedColumn:       related,
+				ReferencesColumn:    references,
+				PublishedColumn:     published,
+			},
+			severity: vulnerability.ParseSeverity(severity),
+			fixRank:  fixRank(m.Vulnerability.Fix),
+		})
 	}
 
-	if len(rows) == 0 {
+	if len(matchRows) == 0 {
 		_, err := io.WriteString(output, "No vulnerabilities found\n")
 		return err
 	}
 
-	// sort by name, version, then type
-	sort.SliceStable(rows, func(i, j int) bool {
-		for col := 0; col < len(columns); col++ {
-			if rows[i][0] != rows[j][0] {
-				return rows[i][col] < rows[j][col]
-			}
-		}
-		return false
+	sort.SliceStable(matchRows, func(i, j int) bool {
+		return less(pres.options.SortBy, matchRows[i], matchRows[j])
 	})
+
+	headers := make([]string, 0, len(columns))
+	for _, c := range columns {
+		headers = append(headers, columnHeaders[c])
+	}
+	rows := make([][]string, 0, len(matchRows))
+	for _, r := range matchRows {
+		values := make([]string, 0, len(columns))
+		for _, c := range columns {
+			values = append(values, r.values[c])
+		}
+		rows = append(rows, values)
+	}
 	rows = removeDuplicateRows(rows)
 
 	table := tablewriter.NewWriter(output)
 
-	table.SetHeader(columns)
+	table.SetHeader(headers)
 	table.SetAutoWrapText(false)
 	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
@@ -95,6 +150,138 @@ func (pres *Presenter) Present(output io.Writer) error {
 	return nil
 }
 
+// withColumns returns the given columns with the additional columns that are not among them yet.
+func withColumns(columns []Column, additional ...Column) []Column {
+	result := append([]Column{}, columns...)
+	for _, a := range additional {
+		if !containsColumn(columns, a) {
+			result = append(result, a)
+		}
+	}
+	return result
+}
+
+// references returns the IDs of the related vulnerabilities of the match (comma-separated) and the reference URLs of
+// the vulnerability (one on each line), when any of these columns is shown.
+func (pres *Presenter) references(m match.Match, metadata *vulnerability.Metadata, columns []Column) (string, string, error) {
+	if !containsColumn(columns, RelatedColumn) && !containsColumn(columns, ReferencesColumn) {
+		return "", "", nil
+	}
+
+	related, err := models.NewRelatedVulnerabilities(m, pres.metadataProvider)
+	if err != nil {
+		return "", "", err
+	}
+	var ids []string
+	for _, r := range related {
+		ids = append(ids, r.ID)
+	}
+	references := models.NewReferences(models.NewVulnerability(m.Vulnerability, metadata), related)
+	return strings.Join(ids, ", "), strings.Join(references, "\n"), nil
+}
+
+func containsColumn(columns []Column, column Column) bool {
+	for _, c := range columns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// secondaryOrder are the columns that order the rows with the same value of the selected order, so that the order of
+// the rows does not depend on the order in which matches were found.
+var secondaryOrder = []Column{NameColumn, InstalledColumn, VulnerabilityColumn, FixedInColumn, SuggestedFixColumn, SeverityColumn, TypeColumn, LayerColumn, ScopeColumn, RelatedColumn, ReferencesColumn, PublishedColumn}
+
+// less orders the rows by the given order, then by package and vulnerability.
+func less(sortBy SortBy, a, b row) bool {
+	switch sortBy {
+	case SortBySeverity:
+		if a.severity != b.severity {
+			return a.severity > b.severity
+		}
+	case SortByVulnerability:
+		if a.values[VulnerabilityColumn] != b.values[VulnerabilityColumn] {
+			return a.values[VulnerabilityColumn] < b.values[VulnerabilityColumn]
+		}
+	case SortByFix:
+		if a.fixRank != b.fixRank {
+			return a.fixRank < b.fixRank
+		}
+	}
+
+	for _, c := range secondaryOrder {
+		if a.values[c] != b.values[c] {
+			return a.values[c] < b.values[c]
+		}
+	}
+	return false
+}
+
+// fixRank orders the fix states of vulnerabilities, where vulnerabilities with a fix come first and vulnerabilities
+// that won't be fixed come last.
+func fixRank(fix vulnerability.Fix) int {
+	switch {
+	case fix.State == grypeDb.FixedState || len(fix.Versions) > 0:
+		return 0
+	case fix.State == grypeDb.WontFixState:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// packageName returns the name of the matched package, annotated with the upstream package(s) that were searched when
+// the vulnerability was only found through an upstream package (e.g. "libssl1.1 (via openssl@1.1.1d-0+deb10u7)").
+func packageName(m match.Match) string {
+	upstreams := m.Details.Upstreams()
+	if len(upstreams) == 0 {
+		return m.Package.Name
+	}
+
+	var names []string
+	for _, u := range upstreams {
+		if u.Version != "" {
+			names = append(names, fmt.Sprintf("%s@%s", u.Name, u.Version))
+		} else {
+			names = append(names, u.Name)
+		}
+	}
+	return fmt.Sprintf("%s (via %s)", m.Package.Name, strings.Join(names, ", "))
+}
+
+// hasLayers indicates if any matched package is attributed to an image layer.
+func hasLayers(matches match.Matches) bool {
+	found := false
+	// note: the enumeration is never stopped early, which would leak the enumerating goroutine
+	for m := range matches.Enumerate() {
+		if m.Package.Layer != nil {
+			found = true
+		}
+	}
+	return found
+}
+
+// layerName describes the image layer that introduced a package, preferring the command that created the layer (e.g.
+// "3: RUN apk add curl") over the digest of the layer.
+func layerName(l *pkg.Layer) string {
+	if l == nil {
+		return ""
+	}
+
+	name := l.Command
+	if name == "" {
+		name = strings.TrimPrefix(l.Digest, "sha256:")
+		if len(name) > 12 {
+			name = name[:12]
+		}
+	}
+	if len(name) > maxLayerNameLength {
+		name = name[:maxLayerNameLength-3] + "..."
+	}
+	return fmt.Sprintf("%d: %s", l.Index, name)
+}
+
 func removeDuplicateRows(items [][]string) [][]string {
 	seen := map[string][]string{}
 	// nolint:prealloc
@@ -3,9 +3,11 @@ package table
 import (
 	"bytes"
 	"flag"
+	"strings"
 	"testing"
 
 	"github.com/anchore/go-testutils"
+	grypeDb "github.com/anchore/grype/grype/db/v3"
 	"github.com/anchore/grype/grype/match"
 	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/grype/grype/presenter/models"
@@ -13,6 +15,8 @@ import (
 	syftPkg "github.com/anchore/syft/syft/pkg"
 	"github.com/go-test/deep"
 	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var update = flag.Bool("update", false, "update the *.golden files for json presenters")
@@ -73,13 +77,41 @@ func TestTablePresenter(t *testing.T) {
 		},
 	}
 
+	var pkg3 = pkg.Package{
+		ID:      "package-3-id",
+		Name:    "package-3",
+		Version: "3.0.1",
+		Type:    syftPkg.DebPkg,
+		Upstreams: []pkg.UpstreamPackage{
+			{
+				Name: "package-3-source",
+			},
+		},
+	}
+
+	var match3 = match.Match{
+
+		Vulnerability: vulnerability.Vulnerability{
+			ID:        "CVE-1999-0003",
+			Namespace: "source-1",
+		},
+		Package: pkg3,
+		Details: []match.Detail{
+			{
+				Type:       match.ExactIndirectMatch,
+				Matcher:    match.DpkgMatcher,
+				SearchedBy: searchedByPackage("package-3-source", "3.0.1"),
+			},
+		},
+	}
+
 	matches := match.NewMatches()
 
-	matches.Add(match1, match2)
+	matches.Add(match1, match2, match3)
 
-	packages := []pkg.Package{pkg1, pkg2}
+	packages := []pkg.Package{pkg1, pkg2, pkg3}
 
-	pres := NewPresenter(matches, packages, models.NewMetadataMock())
+	pres := NewPresenter(matches, packages, models.NewMetadataMock(), Options{})
 
 	// TODO: add a constructor for a match.Match when the data is better shaped
 
@@ -112,7 +144,7 @@ func TestEmptyTablePresenter(t *testing.T) {
 
 	matches := match.NewMatches()
 
-	pres := NewPresenter(matches, []pkg.Package{}, models.NewMetadataMock())
+	pres := NewPresenter(matches, []pkg.Package{}, models.NewMetadataMock(), Options{})
 
 	// run presenter
 	err := pres.Present(&buffer)
@@ -134,6 +166,195 @@ func TestEmptyTablePresenter(t *testing.T) {
 
 }
 
+func TestTablePresenter_options(t *testing.T) {
+	newMatch := func(id, namespace, name, version string, fix vulnerability.Fix) match.Match {
+		return match.Match{
+			Vulnerability: vulnerability.Vulnerability{ID: id, Namespace: namespace, Fix: fix},
+			Package:       pkg.Package{ID: pkg.ID(name + "-id"), Name: name, Version: version, Type: syftPkg.DebPkg},
+		}
+	}
+	matches := match.NewMatches()
+	matches.Add(
+		// Low
+		newMatch("CVE-1999-0001", "source-1", "package-b", "1.0.0", vulnerability.Fix{State: grypeDb.WontFixState}),
+		// Critical
+		newMatch("CVE-1999-0002", "source-2", "package-c", "2.0.0", vulnerability.Fix{State: grypeDb.NotFixedState}),
+		// High
+		newMatch("CVE-1999-0003", "source-1", "package-a", "3.0.0", vulnerability.Fix{State: grypeDb.FixedState, Versions: []string{"3.0.1"}}),
+		newMatch("CVE-1999-0001", "source-1", "package-a", "3.0.0", vulnerability.Fix{State: grypeDb.WontFixState}),
+	)
+
+	tests := []struct {
+		name     string
+		options  Options
+		expected []string
+	}{
+		{
+			name:    "by package",
+			options: Options{SortBy: SortByPackage, Columns: []Column{NameColumn, VulnerabilityColumn}},
+			expected: []string{
+				"NAME       VULNERABILITY",
+				"package-a  CVE-1999-0001",
+				"package-a  CVE-1999-0003",
+				"package-b  CVE-1999-0001",
+				"package-c  CVE-1999-0002",
+			},
+		},
+		{
+			name:    "by severity",
+			options: Options{SortBy: SortBySeverity, Columns: []Column{VulnerabilityColumn, SeverityColumn, NameColumn}},
+			expected: []string{
+				"VULNERABILITY  SEVERITY  NAME",
+				"CVE-1999-0002  Critical  package-c",
+				"CVE-1999-0003  High      package-a",
+				"CVE-1999-0001  Low       package-a",
+				"CVE-1999-0001  Low       package-b",
+			},
+		},
+		{
+			name:    "by vulnerability",
+			options: Options{SortBy: SortByVulnerability, Columns: []Column{VulnerabilityColumn, NameColumn, TypeColumn}},
+			expected: []string{
+				"VULNERABILITY  NAME       TYPE",
+				"CVE-1999-0001  package-a  deb",
+				"CVE-1999-0001  package-b  deb",
+				"CVE-1999-0002  package-c  deb",
+				"CVE-1999-0003  package-a  deb",
+			},
+		},
+		{
+			name:    "by fix",
+			options: Options{SortBy: SortByFix, Columns: []Column{NameColumn, FixedInColumn, VulnerabilityColumn}},
+			expected: []string{
+				"NAME       FIXED-IN     VULNERABILITY",
+				"package-a  3.0.1        CVE-1999-0003",
+				"package-c               CVE-1999-0002",
+				"package-a  (won't fix)  CVE-1999-0001",
+				"package-b  (won't fix)  CVE-1999-0001",
+			},
+		},
+		{
+			name:    "duplicate rows of the selected columns",
+			options: Options{SortBy: SortByPackage, Columns: []Column{NameColumn}},
+			expected: []string{
+				"NAME",
+				"package-a",
+				"package-b",
+				"package-c",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			require.NoError(t, NewPresenter(matches, nil, models.NewMetadataMock(), test.options).Present(&buffer))
+
+			var lines []string
+			for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+				lines = append(lines, strings.TrimRight(line, " "))
+			}
+			assert.Equal(t, test.expected, lines)
+		})
+	}
+}
+
+// referenceMetadata is a metadata provider of vulnerabilities with data sources and reference URLs.
+type referenceMetadata map[string]vulnerability.Metadata
+
+func (m referenceMetadata) GetMetadata(id, _ string) (*vulnerability.Metadata, error) {
+	metadata, ok := m[id]
+	if !ok {
+		return nil, nil
+	}
+	return &metadata, nil
+}
+
+func TestTablePresenter_showRefs(t *testing.T) {
+	matches := match.NewMatches(match.Match{
+		Vulnerability: vulnerability.Vulnerability{
+			ID:        "GHSA-p6mc-m468-83gw",
+			Namespace: "github:language:javascript",
+			RelatedVulnerabilities: []vulnerability.Reference{
+				{ID: "CVE-2020-8203", Namespace: "nvd"},
+				{ID: "OSV-2020-1", Namespace: "osv:npm"},
+			},
+			Advisories: []vulnerability.Advisory{{ID: "GHSA-p6mc-m468-83gw", Link: "https://github.com/advisories/GHSA-p6mc-m468-83gw"}},
+		},
+		Package: pkg.Package{ID: "lodash-id", Name: "lodash", Version: "4.17.15", Type: syftPkg.NpmPkg},
+	})
+	metadata := referenceMetadata{
+		"GHSA-p6mc-m468-83gw": {DataSource: "https://github.com/advisories/GHSA-p6mc-m468-83gw"},
+		"CVE-2020-8203": {
+			DataSource: "https://nvd.nist.gov/vuln/detail/CVE-2020-8203",
+			URLs:       []string{"https://hackerone.com/reports/712065"},
+		},
+	}
+
+	var buffer bytes.Buffer
+	options := Options{Columns: []Column{NameColumn, VulnerabilityColumn}, ShowRefs: true}
+	require.NoError(t, NewPresenter(matches, nil, metadata, options).Present(&buffer))
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(buffer.String()), "\n") {
+		lines = append(lines, strings.TrimRight(line, " "))
+	}
+	assert.Equal(t, []string{
+		"NAME    VULNERABILITY        RELATED                    REFERENCES",
+		"lodash  GHSA-p6mc-m468-83gw  CVE-2020-8203, OSV-2020-1  https://github.com/advisories/GHSA-p6mc-m468-83gw",
+		"                                                        https://nvd.nist.gov/vuln/detail/CVE-2020-8203",
+		"                                                        https://hackerone.com/reports/712065",
+	}, lines)
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		sortBy   string
+		columns  []string
+		expected Options
+		wantErr  bool
+	}{
+		{
+			name:     "defaults",
+			expected: Options{SortBy: SortByPackage},
+		},
+		{
+			name:     "order and columns",
+			sortBy:   "Severity",
+			columns:  []string{"vulnerability", " Name "},
+			expected: Options{SortBy: SortBySeverity, Columns: []Column{VulnerabilityColumn, NameColumn}},
+		},
+		{
+			name:    "unknown order",
+			sortBy:  "cvss",
+			wantErr: true,
+		},
+		{
+			name:    "unknown column",
+			columns: []string{"name", "purl"},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate column",
+			columns: []string{"name", "name"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := ParseOptions(test.sortBy, test.columns)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
 func TestRemoveDuplicateRows(t *testing.T) {
 	data := [][]string{
 		{"1", "2", "3"},
@@ -162,3 +383,107 @@ func TestRemoveDuplicateRows(t *testing.T) {
 	}
 
 }
+
+func TestPackageName(t *testing.T) {
+	p := pkg.Package{
+		Name:    "libssl1.1",
+		Version: "1.1.1d-0+deb10u7",
+		Type:    syftPkg.DebPkg,
+	}
+
+	tests := []struct {
+		name     string
+		details  match.Details
+		expected string
+	}{
+		{
+			name: "direct match",
+			details: match.Details{
+				{Type: match.ExactDirectMatch, SearchedBy: searchedByPackage("libssl1.1", "1.1.1d-0+deb10u7")},
+			},
+			expected: "libssl1.1",
+		},
+		{
+			name: "indirect match",
+			details: match.Details{
+				{Type: match.ExactIndirectMatch, SearchedBy: searchedByPackage("openssl", "1.1.1d-0+deb10u7")},
+			},
+			expected: "libssl1.1 (via openssl@1.1.1d-0+deb10u7)",
+		},
+		{
+			name: "direct and indirect match",
+			details: match.Details{
+				{Type: match.ExactIndirectMatch, SearchedBy: searchedByPackage("openssl", "1.1.1d-0+deb10u7")},
+				{Type: match.ExactDirectMatch, SearchedBy: searchedByPackage("libssl1.1", "1.1.1d-0+deb10u7")},
+			},
+			expected: "libssl1.1",
+		},
+		{
+			name: "indirect match without package information",
+			details: match.Details{
+				{Type: match.ExactIndirectMatch, SearchedBy: map[string]interface{}{"some": "key"}},
+			},
+			expected: "libssl1.1",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := match.Match{
+				Package: p,
+				Details: test.details,
+			}
+			if actual := packageName(m); actual != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
+
+func searchedByPackage(name, version string) map[string]interface{} {
+	return map[string]interface{}{
+		"distro": map[string]string{
+			"type":    "debian",
+			"version": "10",
+		},
+		"package": map[string]string{
+			"name":    name,
+			"version": version,
+		},
+		"namespace": "debian:10",
+	}
+}
+
+func TestLayerName(t *testing.T) {
+	tests := []struct {
+		name     string
+		layer    *pkg.Layer
+		expected string
+	}{
+		{
+			name:     "no layer",
+			expected: "",
+		},
+		{
+			name:     "command",
+			layer:    &pkg.Layer{Index: 3, Digest: "sha256:0123456789abcdef", Command: "RUN apk add curl"},
+			expected: "3: RUN apk add curl",
+		},
+		{
+			name:     "long command",
+			layer:    &pkg.Layer{Index: 1, Command: "RUN apt-get update && apt-get install -y curl ca-certificates"},
+			expected: "1: RUN apt-get update && apt-get install...",
+		},
+		{
+			name:     "digest",
+			layer:    &pkg.Layer{Index: 0, Digest: "sha256:0123456789abcdef"},
+			expected: "0: 0123456789ab",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if actual := layerName(test.layer); actual != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package publish
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// FilePublisher writes the JSON document of every result to a file.
+type FilePublisher struct {
+	path string
+}
+
+// NewFilePublisher is a *FilePublisher constructor, where the path is a template of the file of a result (see
+// ExpandName), e.g. "results/{target}-{timestamp}.json".
+func NewFilePublisher(path string) *FilePublisher {
+	return &FilePublisher{path: path}
+}
+
+func (p *FilePublisher) Publish(result Result) error {
+	document, err := encodeDocument(result)
+	if err != nil {
+		return err
+	}
+	path := ExpandName(p.path, result)
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("unable to create the directory of %q: %w", path, err)
+		}
+	}
+	if err := ioutil.WriteFile(path, document, 0644); err != nil { //nolint:gosec // the result is not sensitive
+		return fmt.Errorf("unable to write the scan result of target=%q to %q: %w", result.Target, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package publish
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// TargetHeader is the header of the target of a result posted by the HTTPPublisher.
+const TargetHeader = "X-Grype-Target"
+
+// HTTPPublisher posts the JSON document of every result to a URL.
+type HTTPPublisher struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+// NewHTTPPublisher is a *HTTPPublisher constructor, where the headers are added to every request (e.g. an
+// authorization header).
+func NewHTTPPublisher(client *http.Client, url string, headers map[string]string) *HTTPPublisher {
+	return &HTTPPublisher{
+		url:     url,
+		headers: headers,
+		client:  client,
+	}
+}
+
+func (p *HTTPPublisher) Publish(result Result) error {
+	document, err := encodeDocument(result)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPost, p.url, bytes.NewReader(document))
+	if err != nil {
+		return fmt.Errorf("unable to post the scan result: %w", err)
+	}
+	for name, value := range p.headers {
+		request.Header.Set(name, value)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(TargetHeader, result.Target)
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		// note: the URL is not part of the error, since it may have credentials
+		return fmt.Errorf("unable to post the scan result of target=%q to host=%q: %w", result.Target, request.URL.Host, unwrapURLError(err))
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unable to post the scan result of target=%q to host=%q: unexpected status %q", result.Target, request.URL.Host, response.Status)
+	}
+	return nil
+}
+
+// unwrapURLError returns the cause of an error of the client, without the URL of the request.
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
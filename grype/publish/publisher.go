@@ -0,0 +1,92 @@
+package publish
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/hashicorp/go-multierror"
+)
+
+// Publisher ships the scan result of a target somewhere (e.g. to object storage or an internal service).
+type Publisher interface {
+	// Publish publishes the scan result
+	Publish(Result) error
+}
+
+// Result is the scan result of a target, with both the JSON document of the result (which most publishers publish)
+// and the matches and packages it describes (for publishers that convert the result to another format).
+type Result struct {
+	// Target is the scanned target as given by the user (e.g. "alpine:3.14")
+	Target           string
+	Time             time.Time
+	Document         models.Document
+	Matches          match.Matches
+	IgnoredMatches   []match.IgnoredMatch
+	Packages         []pkg.Package
+	Context          pkg.Context
+	MetadataProvider vulnerability.MetadataProvider
+}
+
+// NewResult returns the result of the given scan of the target, as of now.
+func NewResult(target string, matches match.Matches, ignoredMatches []match.IgnoredMatch, packages []pkg.Package, context pkg.Context, metadataProvider vulnerability.MetadataProvider, appConfig interface{}, dbStatus interface{}) (Result, error) {
+	doc, err := models.NewDocument(packages, context, matches, ignoredMatches, metadataProvider, appConfig, dbStatus)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{
+		Target:           target,
+		Time:             time.Now().UTC(),
+		Document:         doc,
+		Matches:          matches,
+		IgnoredMatches:   ignoredMatches,
+		Packages:         packages,
+		Context:          context,
+		MetadataProvider: metadataProvider,
+	}, nil
+}
+
+// PublishAll publishes the result with every publisher, where a failure of a publisher does not prevent publishing with
+// the other publishers.
+func PublishAll(publishers []Publisher, result Result) error {
+	var errs error
+	for _, p := range publishers {
+		if err := p.Publish(result); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs
+}
+
+// encodeDocument returns the JSON document of the result (as written by the json output format).
+func encodeDocument(result Result) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	// prevent > and < from being escaped in the payload
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", " ")
+	if err := enc.Encode(&result.Document); err != nil {
+		return nil, fmt.Errorf("unable to encode the scan result of target=%q: %w", result.Target, err)
+	}
+	return buf.Bytes(), nil
+}
+
+var unsafeNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// ExpandName returns the given name template (e.g. of a file path or an object key) for the result, where "{target}"
+// is replaced by the target (with every run of characters other than letters, digits, ".", "_", and "-" replaced by
+// "_") and "{timestamp}" by the time of the result (e.g. "20220131T150405Z").
+func ExpandName(template string, result Result) string {
+	target := strings.Trim(unsafeNameChars.ReplaceAllString(result.Target, "_"), "_")
+	return strings.NewReplacer(
+		"{target}", target,
+		"{timestamp}", result.Time.UTC().Format("20060102T150405Z"),
+	).Replace(template)
+}
@@ -0,0 +1,135 @@
+package publish
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/presenter/models"
+)
+
+func testResult() Result {
+	return Result{
+		Target: "ghcr.io/org/app:1.0",
+		Time:   time.Date(2022, 1, 31, 15, 4, 5, 0, time.UTC),
+		Document: models.Document{
+			Matches: []models.Match{{Vulnerability: models.Vulnerability{VulnerabilityMetadata: models.VulnerabilityMetadata{ID: "CVE-2021-1"}}}},
+		},
+	}
+}
+
+func decodeDocument(t *testing.T, document []byte) models.Document {
+	var doc models.Document
+	require.NoError(t, json.Unmarshal(document, &doc))
+	return doc
+}
+
+func TestExpandName(t *testing.T) {
+	tests := []struct {
+		template string
+		expected string
+	}{
+		{template: "results/{target}.json", expected: "results/ghcr.io_org_app_1.0.json"},
+		{template: "{target}/{timestamp}.json", expected: "ghcr.io_org_app_1.0/20220131T150405Z.json"},
+		{template: "result.json", expected: "result.json"},
+	}
+	for _, test := range tests {
+		t.Run(test.template, func(t *testing.T) {
+			assert.Equal(t, test.expected, ExpandName(test.template, testResult()))
+		})
+	}
+}
+
+func TestFilePublisher(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, NewFilePublisher(filepath.Join(dir, "results", "{target}.json")).Publish(testResult()))
+
+	document, err := ioutil.ReadFile(filepath.Join(dir, "results", "ghcr.io_org_app_1.0.json"))
+	require.NoError(t, err)
+	assert.Equal(t, "CVE-2021-1", decodeDocument(t, document).Matches[0].Vulnerability.ID)
+}
+
+func TestHTTPPublisher(t *testing.T) {
+	var received []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "ghcr.io/org/app:1.0", r.Header.Get(TargetHeader))
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var err error
+		received, err = ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+	}))
+	defer server.Close()
+
+	publisher := NewHTTPPublisher(server.Client(), server.URL, map[string]string{"Authorization": "Bearer token"})
+	require.NoError(t, publisher.Publish(testResult()))
+	assert.Equal(t, "CVE-2021-1", decodeDocument(t, received).Matches[0].Vulnerability.ID)
+
+	err := NewHTTPPublisher(server.Client(), server.URL, nil).Publish(testResult())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401")
+}
+
+type fakeUploader struct {
+	inputs []*s3manager.UploadInput
+	err    error
+}
+
+func (u *fakeUploader) Upload(input *s3manager.UploadInput, _ ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error) {
+	u.inputs = append(u.inputs, input)
+	return &s3manager.UploadOutput{}, u.err
+}
+
+func TestS3Publisher(t *testing.T) {
+	uploader := &fakeUploader{}
+	publisher := &S3Publisher{
+		config:   S3Config{Bucket: "results", Key: "grype/{target}/{timestamp}.json"},
+		uploader: uploader,
+	}
+	require.NoError(t, publisher.Publish(testResult()))
+
+	require.Len(t, uploader.inputs, 1)
+	input := uploader.inputs[0]
+	assert.Equal(t, "results", aws.StringValue(input.Bucket))
+	assert.Equal(t, "grype/ghcr.io_org_app_1.0/20220131T150405Z.json", aws.StringValue(input.Key))
+	assert.Equal(t, "application/json", aws.StringValue(input.ContentType))
+	document, err := ioutil.ReadAll(input.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "CVE-2021-1", decodeDocument(t, document).Matches[0].Vulnerability.ID)
+
+	uploader.err = errors.New("access denied")
+	assert.Error(t, publisher.Publish(testResult()))
+}
+
+type failingPublisher struct{}
+
+func (failingPublisher) Publish(Result) error {
+	return errors.New("unavailable")
+}
+
+func TestPublishAll(t *testing.T) {
+	uploader := &fakeUploader{}
+	publishers := []Publisher{
+		failingPublisher{},
+		&S3Publisher{config: S3Config{Bucket: "results", Key: "{target}.json"}, uploader: uploader},
+	}
+	err := PublishAll(publishers, testResult())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unavailable")
+	// the other publishers still publish
+	assert.Len(t, uploader.inputs, 1)
+}
@@ -0,0 +1,76 @@
+package publish
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// S3Config is the bucket (and the S3 service) results are uploaded to.
+type S3Config struct {
+	Bucket string
+	// Key is a template of the object key of a result (see ExpandName), e.g. "grype/{target}/{timestamp}.json"
+	Key string
+	// Region is the region of the bucket (by default the region of the AWS environment)
+	Region string
+	// Endpoint is the endpoint of an S3 compatible service (e.g. MinIO), by default AWS S3
+	Endpoint string
+	// PathStyle addresses the bucket in the path of requests instead of the host (which S3 compatible services often
+	// require)
+	PathStyle bool
+}
+
+// S3Publisher uploads the JSON document of every result to an S3 bucket, with the credentials of the AWS environment
+// (the env vars, the shared credentials and config files, or the instance role).
+type S3Publisher struct {
+	config   S3Config
+	uploader uploader
+}
+
+// uploader is the part of the uploader that is used (which tests replace).
+type uploader interface {
+	Upload(*s3manager.UploadInput, ...func(*s3manager.Uploader)) (*s3manager.UploadOutput, error)
+}
+
+// NewS3Publisher is a *S3Publisher constructor
+func NewS3Publisher(config S3Config) (*S3Publisher, error) {
+	awsConfig := aws.NewConfig().WithS3ForcePathStyle(config.PathStyle)
+	if config.Region != "" {
+		awsConfig = awsConfig.WithRegion(config.Region)
+	}
+	if config.Endpoint != "" {
+		awsConfig = awsConfig.WithEndpoint(config.Endpoint)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:            *awsConfig,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to configure the AWS session: %w", err)
+	}
+	return &S3Publisher{
+		config:   config,
+		uploader: s3manager.NewUploader(sess),
+	}, nil
+}
+
+func (p *S3Publisher) Publish(result Result) error {
+	document, err := encodeDocument(result)
+	if err != nil {
+		return err
+	}
+	key := ExpandName(p.config.Key, result)
+	_, err = p.uploader.Upload(&s3manager.UploadInput{
+		Bucket:      aws.String(p.config.Bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(document),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to upload the scan result of target=%q to s3://%s/%s: %w", result.Target, p.config.Bucket, key, err)
+	}
+	return nil
+}
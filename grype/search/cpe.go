@@ -65,7 +65,7 @@ func ByPackageCPE(store vulnerability.ProviderByCPE, p pkg.Package, upstreamMatc
 		if searchVersion == wfn.NA || searchVersion == wfn.Any {
 			searchVersion = p.Version
 		}
-		verObj, err := version.NewVersion(searchVersion, version.FormatFromPkgType(p.Type))
+		verObj, err := version.NewVersion(searchVersion, version.FormatFromPkg(p))
 		if err != nil {
 			return nil, fmt.Errorf("matcher failed to parse version pkg=%q ver=%q: %w", p.Name, p.Version, err)
 		}
@@ -11,6 +11,7 @@ var (
 	ByCPE          Criteria = "by-cpe"
 	ByLanguage     Criteria = "by-language"
 	ByDistro       Criteria = "by-distro"
+	ByType         Criteria = "by-package-type"
 	CommonCriteria          = []Criteria{
 		ByLanguage,
 		ByCPE,
@@ -35,6 +36,12 @@ func ByCriteria(store vulnerability.Provider, d *distro.Distro, p pkg.Package, u
 				return nil, err
 			}
 			matches = append(matches, m...)
+		case ByType:
+			m, err := ByPackageType(store, p, upstreamMatcher)
+			if err != nil {
+				return nil, err
+			}
+			matches = append(matches, m...)
 		case ByDistro:
 			m, err := ByPackageDistro(store, d, p, upstreamMatcher)
 			if err != nil {
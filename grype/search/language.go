@@ -25,6 +25,9 @@ func ByPackageLanguage(store vulnerability.ProviderByLanguage, p pkg.Package, up
 		return nil, fmt.Errorf("unable to filter language-related vulnerabilities: %w", err)
 	}
 
+	// OSV mirrors other advisory databases, so the same flaw may be found within more than one namespace
+	applicableVulns = onlyUniqueOSV(applicableVulns)
+
 	var matches []match.Match
 	for _, vuln := range applicableVulns {
 		matches = append(matches, match.Match{
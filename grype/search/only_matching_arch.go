@@ -0,0 +1,33 @@
+package search
+
+import (
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// onlyMatchingArch removes vulnerabilities from advisories that are scoped to architectures other than the architecture
+// of the given package (e.g. an x86_64 only advisory does not apply to an aarch64 or noarch package). When the
+// architecture of the package is unknown all vulnerabilities are kept.
+func onlyMatchingArch(p pkg.Package, allVulns []vulnerability.Vulnerability) []vulnerability.Vulnerability {
+	if p.Arch == "" {
+		return allVulns
+	}
+
+	var vulns []vulnerability.Vulnerability
+	for _, vuln := range allVulns {
+		if len(vuln.Arches) > 0 && !containsArch(vuln.Arches, p.Arch) {
+			continue
+		}
+		vulns = append(vulns, vuln)
+	}
+	return vulns
+}
+
+func containsArch(arches []string, arch string) bool {
+	for _, a := range arches {
+		if a == arch {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+func TestOnlyMatchingArch(t *testing.T) {
+	vulns := []vulnerability.Vulnerability{
+		{ID: "CVE-2021-any-arch"},
+		{ID: "CVE-2021-x86_64", Arches: []string{"x86_64"}},
+		{ID: "CVE-2021-aarch64", Arches: []string{"aarch64", "ppc64le"}},
+	}
+
+	tests := []struct {
+		name     string
+		arch     string
+		expected []string
+	}{
+		{
+			name:     "unknown arch keeps all vulnerabilities",
+			arch:     "",
+			expected: []string{"CVE-2021-any-arch", "CVE-2021-x86_64", "CVE-2021-aarch64"},
+		},
+		{
+			name:     "matching arch",
+			arch:     "x86_64",
+			expected: []string{"CVE-2021-any-arch", "CVE-2021-x86_64"},
+		},
+		{
+			name:     "matching one of many arches",
+			arch:     "ppc64le",
+			expected: []string{"CVE-2021-any-arch", "CVE-2021-aarch64"},
+		},
+		{
+			name:     "noarch package does not match arch scoped advisories",
+			arch:     "noarch",
+			expected: []string{"CVE-2021-any-arch"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var actual []string
+			for _, v := range onlyMatchingArch(pkg.Package{Name: "kernel", Arch: test.arch}, vulns) {
+				actual = append(actual, v.ID)
+			}
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package search
+
+import (
+	"strings"
+
+	grypeDB "github.com/anchore/grype/grype/db/v3"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// onlyUniqueOSV removes vulnerabilities from OSV namespaces that describe the same flaw as a vulnerability from any
+// other namespace (e.g. a RUSTSEC advisory that is an alias of a GHSA, or a GHSA mirrored by OSV), where both are
+// related when either is an alias of the other or they share an alias (e.g. the same CVE).
+func onlyUniqueOSV(allVulns []vulnerability.Vulnerability) []vulnerability.Vulnerability {
+	otherIDs := make(map[string]struct{})
+	for _, vuln := range allVulns {
+		if isOSV(vuln) {
+			continue
+		}
+		otherIDs[vuln.ID] = struct{}{}
+		for _, related := range vuln.RelatedVulnerabilities {
+			otherIDs[related.ID] = struct{}{}
+		}
+	}
+
+	var vulns []vulnerability.Vulnerability
+	for _, vuln := range allVulns {
+		if isOSV(vuln) && isKnownAlias(vuln, otherIDs) {
+			continue
+		}
+		vulns = append(vulns, vuln)
+	}
+	return vulns
+}
+
+func isOSV(vuln vulnerability.Vulnerability) bool {
+	return strings.HasPrefix(vuln.Namespace, grypeDB.OSVNamespacePrefix+":")
+}
+
+func isKnownAlias(vuln vulnerability.Vulnerability, ids map[string]struct{}) bool {
+	if _, exists := ids[vuln.ID]; exists {
+		return true
+	}
+	for _, related := range vuln.RelatedVulnerabilities {
+		if _, exists := ids[related.ID]; exists {
+			return true
+		}
+	}
+	return false
+}
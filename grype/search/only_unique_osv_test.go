@@ -0,0 +1,73 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+func TestOnlyUniqueOSV(t *testing.T) {
+	tests := []struct {
+		name     string
+		vulns    []vulnerability.Vulnerability
+		expected []string
+	}{
+		{
+			name: "OSV advisory that is an alias of a GHSA",
+			vulns: []vulnerability.Vulnerability{
+				{ID: "GHSA-5h46-h7hh-c6x9", Namespace: "github:rust"},
+				{ID: "RUSTSEC-2021-0078", Namespace: "osv:crates.io", RelatedVulnerabilities: []vulnerability.Reference{
+					{ID: "GHSA-5h46-h7hh-c6x9", Namespace: "github:rust"},
+				}},
+			},
+			expected: []string{"GHSA-5h46-h7hh-c6x9"},
+		},
+		{
+			name: "OSV advisory that shares a CVE with a GHSA",
+			vulns: []vulnerability.Vulnerability{
+				{ID: "GHSA-p782-xgp4-8hr8", Namespace: "github:go", RelatedVulnerabilities: []vulnerability.Reference{
+					{ID: "CVE-2022-29526", Namespace: "nvd"},
+				}},
+				{ID: "GO-2022-0493", Namespace: "osv:go", RelatedVulnerabilities: []vulnerability.Reference{
+					{ID: "CVE-2022-29526", Namespace: "nvd"},
+				}},
+			},
+			expected: []string{"GHSA-p782-xgp4-8hr8"},
+		},
+		{
+			name: "GHSA mirrored by OSV",
+			vulns: []vulnerability.Vulnerability{
+				{ID: "GHSA-5h46-h7hh-c6x9", Namespace: "github:rust"},
+				{ID: "GHSA-5h46-h7hh-c6x9", Namespace: "osv:crates.io"},
+			},
+			expected: []string{"GHSA-5h46-h7hh-c6x9"},
+		},
+		{
+			name: "unrelated OSV advisories are kept",
+			vulns: []vulnerability.Vulnerability{
+				{ID: "GHSA-5h46-h7hh-c6x9", Namespace: "github:rust", RelatedVulnerabilities: []vulnerability.Reference{
+					{ID: "CVE-2021-32715", Namespace: "nvd"},
+				}},
+				{ID: "RUSTSEC-2021-0001", Namespace: "osv:crates.io", RelatedVulnerabilities: []vulnerability.Reference{
+					{ID: "CVE-2021-0001", Namespace: "nvd"},
+				}},
+				{ID: "RUSTSEC-2021-0002", Namespace: "osv:crates.io", RelatedVulnerabilities: []vulnerability.Reference{
+					{ID: "CVE-2021-0001", Namespace: "nvd"},
+				}},
+			},
+			expected: []string{"GHSA-5h46-h7hh-c6x9", "RUSTSEC-2021-0001", "RUSTSEC-2021-0002"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var actual []string
+			for _, v := range onlyUniqueOSV(test.vulns) {
+				actual = append(actual, v.ID)
+			}
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
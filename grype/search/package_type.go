@@ -0,0 +1,57 @@
+package search
+
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// ByPackageType retrieves all vulnerabilities from the namespaces associated with the package type (instead
+// of the package language) that apply to the package version.
+func ByPackageType(store vulnerability.ProviderByPackageType, p pkg.Package, upstreamMatcher match.MatcherType) ([]match.Match, error) {
+	verObj, err := version.NewVersionFromPkg(p)
+	if err != nil {
+		return nil, fmt.Errorf("matcher failed to parse version pkg=%q ver=%q: %w", p.Name, p.Version, err)
+	}
+
+	allPkgVulns, err := store.GetByPackageType(p.Type, p)
+	if err != nil {
+		return nil, fmt.Errorf("matcher failed to fetch type=%q pkg=%q: %w", p.Type, p.Name, err)
+	}
+
+	applicableVulns, err := onlyVulnerableVersions(verObj, allPkgVulns)
+	if err != nil {
+		return nil, fmt.Errorf("unable to filter package-type-related vulnerabilities: %w", err)
+	}
+
+	// OSV mirrors other advisory databases, so the same flaw may be found within more than one namespace
+	applicableVulns = onlyUniqueOSV(applicableVulns)
+
+	var matches []match.Match
+	for _, vuln := range applicableVulns {
+		matches = append(matches, match.Match{
+
+			Vulnerability: vuln,
+			Package:       p,
+			Details: []match.Detail{
+				{
+					Type:       match.ExactDirectMatch,
+					Confidence: 1.0, // TODO: this is hard coded for now
+					Matcher:    upstreamMatcher,
+					SearchedBy: map[string]interface{}{
+						"packageType": string(p.Type),
+						"namespace":   vuln.Namespace,
+					},
+					Found: map[string]interface{}{
+						"versionConstraint": vuln.Constraint.String(),
+					},
+				},
+			},
+		})
+	}
+
+	return matches, err
+}
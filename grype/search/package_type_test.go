@@ -0,0 +1,88 @@
+package search
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type mockPackageTypeProvider struct {
+	data map[string]map[string][]vulnerability.Vulnerability
+}
+
+func newMockProviderByPackageType() *mockPackageTypeProvider {
+	pr := mockPackageTypeProvider{
+		data: make(map[string]map[string][]vulnerability.Vulnerability),
+	}
+	pr.stub()
+	return &pr
+}
+
+func (pr *mockPackageTypeProvider) stub() {
+	pr.data["osv:bitnami"] = map[string][]vulnerability.Vulnerability{
+		"redis": {
+			{
+				Constraint: version.MustGetConstraint(">= 7.0.0, < 7.0.12", version.BitnamiFormat),
+				ID:         "CVE-2023-fake-1",
+				Namespace:  "osv:bitnami",
+			},
+			{
+				Constraint: version.MustGetConstraint("< 7.0.11-1", version.BitnamiFormat),
+				ID:         "CVE-2023-fake-2",
+				Namespace:  "osv:bitnami",
+			},
+		},
+	}
+}
+
+func (pr *mockPackageTypeProvider) GetByPackageType(t syftPkg.Type, p pkg.Package) ([]vulnerability.Vulnerability, error) {
+	if t != pkg.BitnamiPkg {
+		panic(fmt.Errorf("test mock only supports bitnami"))
+	}
+	return pr.data["osv:bitnami"][p.Name], nil
+}
+
+func TestFindMatchesByPackageType(t *testing.T) {
+	p := pkg.Package{
+		ID:      pkg.ID(uuid.NewString()),
+		Name:    "redis",
+		Version: "7.0.11-1",
+		Type:    pkg.BitnamiPkg,
+	}
+
+	expected := []match.Match{
+		{
+			Vulnerability: vulnerability.Vulnerability{
+				ID: "CVE-2023-fake-1",
+			},
+			Package: p,
+			Details: []match.Detail{
+				{
+					Type:       match.ExactDirectMatch,
+					Confidence: 1,
+					SearchedBy: map[string]interface{}{
+						"packageType": "bitnami",
+						"namespace":   "osv:bitnami",
+					},
+					Found: map[string]interface{}{
+						"versionConstraint": ">= 7.0.0, < 7.0.12 (bitnami)",
+					},
+					Matcher: match.BitnamiMatcher,
+				},
+			},
+		},
+	}
+
+	store := newMockProviderByPackageType()
+	actual, err := ByPackageType(store, p, match.BitnamiMatcher)
+	assert.NoError(t, err)
+	assertMatchesUsingIDsForVulnerabilities(t, expected, actual)
+}
@@ -0,0 +1,171 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"github.com/anchore/grype/grype/metrics"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/server/rpc"
+)
+
+// DefaultGRPCMaxMessageSize is the largest message (e.g. a request with an SBOM) that is received by default, which is
+// the largest SBOM that can be submitted to the REST API.
+const DefaultGRPCMaxMessageSize = maxSBOMSize
+
+// GRPCConfig controls how the gRPC scanning API is served.
+type GRPCConfig struct {
+	// TLSCertFile and TLSKeyFile are the certificate and key to serve the API over TLS (which is served without TLS
+	// when not given)
+	TLSCertFile string
+	TLSKeyFile  string
+	// MaxMessageSize is the largest message that is received or sent, in bytes
+	MaxMessageSize int
+}
+
+// ServerOptions returns the options of the gRPC server (see grpc.NewServer) for the config.
+func (c GRPCConfig) ServerOptions() ([]grpc.ServerOption, error) {
+	maxMessageSize := c.MaxMessageSize
+	if maxMessageSize <= 0 {
+		maxMessageSize = DefaultGRPCMaxMessageSize
+	}
+	options := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(maxMessageSize),
+		grpc.MaxSendMsgSize(maxMessageSize),
+	}
+
+	if c.TLSCertFile != "" || c.TLSKeyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(c.TLSCertFile, c.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load TLS certificate: %w", err)
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+	return options, nil
+}
+
+// grpcService implements the gRPC scanning API (see rpc/grype.proto) on top of the server.
+type grpcService struct {
+	rpc.UnimplementedScannerServer
+	server *Server
+}
+
+// RegisterGRPC registers the gRPC scanning API of the server.
+func (s *Server) RegisterGRPC(registrar grpc.ServiceRegistrar) {
+	rpc.RegisterScannerServer(registrar, &grpcService{server: s})
+}
+
+func (g *grpcService) Scan(_ context.Context, request *rpc.ScanRequest) (*rpc.ScanResponse, error) {
+	response := &rpc.ScanResponse{}
+	err := g.scan(request, func(m *rpc.Match) error {
+		response.Matches = append(response.Matches, m)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+func (g *grpcService) ScanStream(request *rpc.ScanRequest, stream rpc.Scanner_ScanStreamServer) error {
+	return g.scan(request, stream.Send)
+}
+
+func (g *grpcService) DBStatus(context.Context, *rpc.DBStatusRequest) (*rpc.DBStatusResponse, error) {
+	dbStatus := g.server.dbStatus
+	if dbStatus == nil {
+		return nil, status.Error(codes.Unavailable, "no vulnerability database loaded")
+	}
+	response := &rpc.DBStatusResponse{
+		Built:         dbStatus.Built.Format(time.RFC3339),
+		SchemaVersion: int32(dbStatus.SchemaVersion),
+		Location:      dbStatus.Location,
+		Checksum:      dbStatus.Checksum,
+	}
+	if dbStatus.Err != nil {
+		response.Error = dbStatus.Err.Error()
+	}
+	return response, nil
+}
+
+// scan scans the input of the request, calling the given function with every match as soon as it is described. Like
+// the REST API, only images within a registry are scanned and scans are rejected while the maximum number are running.
+func (g *grpcService) scan(request *rpc.ScanRequest, fn func(*rpc.Match) error) error {
+	if !g.server.acquire() {
+		return status.Error(codes.ResourceExhausted, errTooManyScans.Error())
+	}
+	defer g.server.release()
+
+	var input string
+	switch {
+	case request.GetImage() != "":
+		image, err := imageInput(request.GetImage())
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		input = image
+	case len(request.GetSbom()) > 0:
+		sbom, cleanup, err := sbomInput(bytes.NewReader(request.GetSbom()))
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		defer cleanup()
+		input = sbom
+	default:
+		return status.Error(codes.InvalidArgument, "an image or an SBOM is required")
+	}
+
+	start := time.Now()
+	result, err := g.server.match(input, g.server.currentIgnoreRules())
+	if err != nil {
+		metrics.ObserveScan(time.Since(start), err)
+		if errors.Is(err, errCatalog) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	err = models.EnumerateMatches(result.packages, result.remainingMatches, g.server.metadataProvider, func(m models.Match) error {
+		metrics.Matches.With(m.Vulnerability.Severity).Inc()
+		return fn(newRPCMatch(m))
+	})
+	metrics.ObserveScan(time.Since(start), err)
+	return err
+}
+
+func newRPCMatch(m models.Match) *rpc.Match {
+	var locations []string
+	for _, l := range m.Artifact.Locations {
+		locations = append(locations, l.RealPath)
+	}
+
+	return &rpc.Match{
+		Vulnerability: &rpc.Vulnerability{
+			Id:              m.Vulnerability.ID,
+			Namespace:       m.Vulnerability.Namespace,
+			DataSource:      m.Vulnerability.DataSource,
+			Severity:        m.Vulnerability.Severity,
+			Description:     m.Vulnerability.Description,
+			Urls:            m.Vulnerability.URLs,
+			FixedInVersions: m.Vulnerability.Fix.Versions,
+			FixState:        m.Vulnerability.Fix.State,
+		},
+		Artifact: &rpc.Package{
+			Name:      m.Artifact.Name,
+			Version:   m.Artifact.Version,
+			Type:      string(m.Artifact.Type),
+			Language:  string(m.Artifact.Language),
+			Purl:      m.Artifact.PURL,
+			Cpes:      m.Artifact.CPEs,
+			Locations: locations,
+		},
+	}
+}
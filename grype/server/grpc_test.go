@@ -0,0 +1,206 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/server/rpc"
+)
+
+func newTestClient(t *testing.T, config Config) rpc.ScannerClient {
+	t.Helper()
+	return newTestClientWithOptions(t, config, GRPCConfig{}, grpc.WithInsecure())
+}
+
+func newTestClientWithOptions(t *testing.T, config Config, grpcConfig GRPCConfig, dialOptions ...grpc.DialOption) rpc.ScannerClient {
+	t.Helper()
+	s, _ := newTestServer(t, config)
+
+	serverOptions, err := grpcConfig.ServerOptions()
+	require.NoError(t, err)
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer(serverOptions...)
+	s.RegisterGRPC(grpcServer)
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet", append(dialOptions,
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+	)...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return rpc.NewScannerClient(conn)
+}
+
+func TestGRPC_Scan(t *testing.T) {
+	client := newTestClient(t, Config{})
+	sbom, err := ioutil.ReadFile("test-fixtures/syft-alpine.json")
+	require.NoError(t, err)
+
+	response, err := client.Scan(context.Background(), &rpc.ScanRequest{Input: &rpc.ScanRequest_Sbom{Sbom: sbom}})
+	require.NoError(t, err)
+	require.Len(t, response.Matches, 1)
+	m := response.Matches[0]
+	assert.Equal(t, "CVE-2021-fake", m.Vulnerability.Id)
+	assert.Equal(t, "High", m.Vulnerability.Severity)
+	assert.Equal(t, "alpine-baselayout", m.Artifact.Name)
+	assert.Equal(t, "3.2.0-r6", m.Artifact.Version)
+	assert.Equal(t, []string{"/lib/apk/db/installed"}, m.Artifact.Locations)
+}
+
+func TestGRPC_ScanStream(t *testing.T) {
+	client := newTestClient(t, Config{})
+	sbom, err := ioutil.ReadFile("test-fixtures/syft-alpine.json")
+	require.NoError(t, err)
+
+	stream, err := client.ScanStream(context.Background(), &rpc.ScanRequest{Input: &rpc.ScanRequest_Sbom{Sbom: sbom}})
+	require.NoError(t, err)
+
+	var ids []string
+	for {
+		m, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		ids = append(ids, m.Vulnerability.Id)
+	}
+	assert.Equal(t, []string{"CVE-2021-fake"}, ids)
+}
+
+func TestGRPC_ScanIgnoreRules(t *testing.T) {
+	client := newTestClient(t, Config{IgnoreRules: []match.IgnoreRule{{Vulnerability: "CVE-2021-fake"}}})
+	sbom, err := ioutil.ReadFile("test-fixtures/syft-alpine.json")
+	require.NoError(t, err)
+
+	response, err := client.Scan(context.Background(), &rpc.ScanRequest{Input: &rpc.ScanRequest_Sbom{Sbom: sbom}})
+	require.NoError(t, err)
+	assert.Empty(t, response.Matches)
+}
+
+func TestGRPC_ScanInvalidRequest(t *testing.T) {
+	client := newTestClient(t, Config{})
+
+	_, err := client.Scan(context.Background(), &rpc.ScanRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = client.Scan(context.Background(), &rpc.ScanRequest{Input: &rpc.ScanRequest_Sbom{Sbom: []byte("not an sbom")}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// images are only read from a registry (or the docker daemon)
+	for _, image := range []string{"dir:/", "file:/etc/passwd", "sbom:/tmp/sbom.json"} {
+		_, err = client.Scan(context.Background(), &rpc.ScanRequest{Input: &rpc.ScanRequest_Image{Image: image}})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err), image)
+	}
+}
+
+func TestGRPC_MaxConcurrentScans(t *testing.T) {
+	s, _ := newTestServer(t, Config{MaxConcurrentScans: 1})
+	service := &grpcService{server: s}
+
+	// another scan is running
+	require.True(t, s.acquire())
+	_, err := service.Scan(context.Background(), &rpc.ScanRequest{})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	s.release()
+	_, err = service.Scan(context.Background(), &rpc.ScanRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	// the slot of the completed scan is released
+	assert.True(t, s.acquire())
+}
+
+func TestGRPC_MaxMessageSize(t *testing.T) {
+	client := newTestClientWithOptions(t, Config{}, GRPCConfig{MaxMessageSize: 1024}, grpc.WithInsecure())
+	sbom, err := ioutil.ReadFile("test-fixtures/syft-alpine.json")
+	require.NoError(t, err)
+	require.Greater(t, len(sbom), 1024)
+
+	_, err = client.Scan(context.Background(), &rpc.ScanRequest{Input: &rpc.ScanRequest_Sbom{Sbom: sbom}})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestGRPC_TLS(t *testing.T) {
+	certFile, keyFile, certPool := newTestCertificate(t)
+	client := newTestClientWithOptions(t, Config{}, GRPCConfig{TLSCertFile: certFile, TLSKeyFile: keyFile},
+		grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(certPool, "localhost")),
+	)
+
+	response, err := client.DBStatus(context.Background(), &rpc.DBStatusRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), response.SchemaVersion)
+
+	_, err = GRPCConfig{TLSCertFile: certFile}.ServerOptions()
+	assert.Error(t, err, "a certificate without a key is rejected")
+}
+
+// newTestCertificate writes a self-signed certificate for localhost, returning the certificate and key files, and the
+// pool that trusts the certificate.
+func newTestCertificate(t *testing.T) (string, string, *x509.CertPool) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return certFile, keyFile, pool
+}
+
+func TestGRPC_DBStatus(t *testing.T) {
+	client := newTestClient(t, Config{})
+
+	response, err := client.DBStatus(context.Background(), &rpc.DBStatusRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), response.SchemaVersion)
+	assert.Equal(t, "2022-01-01T00:00:00Z", response.Built)
+	assert.Empty(t, response.Error)
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"time"
+
+	"github.com/anchore/grype/grype/presenter/models"
+)
+
+const (
+	// PendingStatus indicates the scan has been submitted but is not complete yet
+	PendingStatus = "pending"
+	// CompleteStatus indicates the scan is complete and the document is available
+	CompleteStatus = "complete"
+	// FailedStatus indicates the scan failed (see the error)
+	FailedStatus = "failed"
+)
+
+// Scan is a submitted scan and (once complete) its result.
+type Scan struct {
+	ID        string           `json:"id"`
+	Input     string           `json:"input"`
+	Status    string           `json:"status"`
+	Error     string           `json:"error,omitempty"`
+	Submitted time.Time        `json:"submitted"`
+	Completed *time.Time       `json:"completed,omitempty"`
+	Document  *models.Document `json:"document,omitempty"`
+}
+
+// summary returns the scan without its document (for listing many scans).
+func (s Scan) summary() Scan {
+	s.Document = nil
+	return s
+}
@@ -0,0 +1,389 @@
+package server
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/anchore/grype/grype/db"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/metrics"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+	"github.com/anchore/grype/internal/log"
+)
+
+// DefaultMaxScans is the number of scans that are kept in memory by default.
+const DefaultMaxScans = 1000
+
+// DefaultMaxConcurrentScans is the number of scans that run at the same time by default.
+const DefaultMaxConcurrentScans = 4
+
+// maxSBOMSize is the largest SBOM that can be submitted.
+const maxSBOMSize = 100 << 20
+
+// Config controls how the server scans.
+type Config struct {
+	Provider pkg.ProviderConfig
+	Matcher  matcher.Config
+	// IgnoreRules are the initial ignore rules (which can be changed through the API)
+	IgnoreRules []match.IgnoreRule
+	// MaxScans is the number of scans that are kept, where the oldest scans are discarded first
+	MaxScans int
+	// MaxConcurrentScans is the number of scans that run at the same time, where further scans are rejected until a
+	// scan completes
+	MaxConcurrentScans int
+	// Token is the bearer token that is required to change the ignore rules (which cannot be changed without a token)
+	Token string
+}
+
+// Server scans images and SBOMs against a vulnerability database that is loaded once.
+type Server struct {
+	provider         vulnerability.Provider
+	metadataProvider vulnerability.MetadataProvider
+	dbStatus         *db.Status
+	config           Config
+
+	lock        sync.RWMutex
+	ignoreRules []match.IgnoreRule
+	scans       map[string]*Scan
+	order       []string // scan IDs from oldest to newest
+	pending     sync.WaitGroup
+	running     chan struct{} // a slot for each running scan
+}
+
+// New returns a server that scans against the given providers.
+func New(provider vulnerability.Provider, metadataProvider vulnerability.MetadataProvider, dbStatus *db.Status, config Config) *Server {
+	if config.MaxScans <= 0 {
+		config.MaxScans = DefaultMaxScans
+	}
+	if config.MaxConcurrentScans <= 0 {
+		config.MaxConcurrentScans = DefaultMaxConcurrentScans
+	}
+	return &Server{
+		provider:         provider,
+		metadataProvider: metadataProvider,
+		dbStatus:         dbStatus,
+		config:           config,
+		ignoreRules:      append([]match.IgnoreRule{}, config.IgnoreRules...),
+		scans:            make(map[string]*Scan),
+		running:          make(chan struct{}, config.MaxConcurrentScans),
+	}
+}
+
+// Handler returns the HTTP handler of the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/scans", s.handleScans)
+	mux.HandleFunc("/v1/scans/", s.handleScan)
+	mux.HandleFunc("/v1/db/status", s.handleDBStatus)
+	mux.HandleFunc("/v1/ignore-rules", s.handleIgnoreRules)
+	mux.Handle("/metrics", metrics.Default.Handler())
+	return mux
+}
+
+// Wait blocks until all submitted scans are complete.
+func (s *Server) Wait() {
+	s.pending.Wait()
+}
+
+// imageRequest is the body of a request to scan an image.
+type imageRequest struct {
+	Image string `json:"image"`
+}
+
+func (s *Server) handleScans(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.lock.RLock()
+		scans := make([]Scan, 0, len(s.order))
+		for _, id := range s.order {
+			scans = append(scans, s.scans[id].summary())
+		}
+		s.lock.RUnlock()
+		writeJSON(w, http.StatusOK, scans)
+	case http.MethodPost:
+		if !s.acquire() {
+			writeError(w, http.StatusTooManyRequests, errTooManyScans)
+			return
+		}
+		input, cleanup, err := scanInput(r)
+		if err != nil {
+			s.release()
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		scan := s.submit(input, cleanup)
+		writeJSON(w, http.StatusAccepted, scan)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("unsupported method %s", r.Method))
+	}
+}
+
+func (s *Server) handleScan(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("unsupported method %s", r.Method))
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/v1/scans/")
+	s.lock.RLock()
+	scan, ok := s.scans[id]
+	var result Scan
+	if ok {
+		result = *scan
+	}
+	s.lock.RUnlock()
+
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("no scan with ID %q", id))
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleDBStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("unsupported method %s", r.Method))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.dbStatus)
+}
+
+func (s *Server) handleIgnoreRules(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		if !s.authorized(r) {
+			writeError(w, http.StatusUnauthorized, errors.New("a valid bearer token is required to change the ignore rules"))
+			return
+		}
+		var rules []match.IgnoreRule
+		if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("unable to decode ignore rules: %w", err))
+			return
+		}
+		s.lock.Lock()
+		if r.Method == http.MethodPut {
+			s.ignoreRules = rules
+		} else {
+			s.ignoreRules = append(s.ignoreRules, rules...)
+		}
+		s.lock.Unlock()
+	default:
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("unsupported method %s", r.Method))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.currentIgnoreRules())
+}
+
+// authorized indicates if the request has the bearer token of the server (where no request is authorized without one).
+func (s *Server) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return s.config.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.config.Token)) == 1
+}
+
+// errTooManyScans indicates that a scan was rejected since the maximum number of scans are running.
+var errTooManyScans = errors.New("too many scans are running, try again later")
+
+// acquire takes a slot for a scan to run, unless the maximum number of scans are running.
+func (s *Server) acquire() bool {
+	select {
+	case s.running <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// release frees the slot of a scan.
+func (s *Server) release() {
+	<-s.running
+}
+
+// scanInput returns the input to scan from the request, which is either an image reference (as a JSON document) or
+// an SBOM (as any other content), which is kept in a temporary file until the returned cleanup is called. Only images
+// within a registry (or the docker daemon) are scanned, so that clients cannot read anything else (e.g. "dir:/").
+func scanInput(r *http.Request) (string, func(), error) {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxSBOMSize))
+		if err != nil {
+			return "", nil, fmt.Errorf("unable to read request: %w", err)
+		}
+		var request imageRequest
+		if err := json.Unmarshal(body, &request); err == nil && request.Image != "" {
+			input, err := imageInput(request.Image)
+			if err != nil {
+				return "", nil, err
+			}
+			return input, func() {}, nil
+		}
+		// not an image request, so the body is expected to be a JSON SBOM
+		return sbomInput(bytes.NewReader(body))
+	}
+	return sbomInput(io.LimitReader(r.Body, maxSBOMSize))
+}
+
+// imageInput returns the input to scan the given image reference.
+func imageInput(image string) (string, error) {
+	ref, err := pkg.ParseImageReference(image)
+	if err != nil {
+		return "", err
+	}
+	return ref.Input(), nil
+}
+
+func sbomInput(reader io.Reader) (string, func(), error) {
+	f, err := ioutil.TempFile("", "grype-sbom-")
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to store SBOM: %w", err)
+	}
+	cleanup := func() {
+		_ = os.Remove(f.Name())
+	}
+	if _, err := io.Copy(f, reader); err != nil {
+		_ = f.Close()
+		cleanup()
+		return "", nil, fmt.Errorf("unable to store SBOM: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("unable to store SBOM: %w", err)
+	}
+	return "sbom:" + f.Name(), cleanup, nil
+}
+
+// submit starts scanning the given input (with an acquired slot, which is released once the scan is complete),
+// returning the pending scan.
+func (s *Server) submit(input string, cleanup func()) Scan {
+	scan := &Scan{
+		ID:        uuid.NewString(),
+		Input:     input,
+		Status:    PendingStatus,
+		Submitted: time.Now(),
+	}
+	if strings.HasPrefix(input, "sbom:") {
+		// the temporary file is meaningless to the client
+		scan.Input = "sbom"
+	}
+
+	s.lock.Lock()
+	s.scans[scan.ID] = scan
+	s.order = append(s.order, scan.ID)
+	for len(s.order) > s.config.MaxScans {
+		delete(s.scans, s.order[0])
+		s.order = s.order[1:]
+	}
+	ignoreRules := append([]match.IgnoreRule{}, s.ignoreRules...)
+	result := *scan
+	s.lock.Unlock()
+
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+		defer s.release()
+		defer cleanup()
+
+		start := time.Now()
+		doc, err := s.scan(input, ignoreRules)
+		metrics.ObserveScan(time.Since(start), err)
+		if doc != nil {
+			for _, m := range doc.Matches {
+				metrics.Matches.With(m.Vulnerability.Severity).Inc()
+			}
+		}
+
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		completed := time.Now()
+		scan.Completed = &completed
+		if err != nil {
+			log.Warnf("scan=%q failed: %+v", scan.ID, err)
+			scan.Status = FailedStatus
+			scan.Error = err.Error()
+			return
+		}
+		scan.Status = CompleteStatus
+		scan.Document = doc
+	}()
+
+	return result
+}
+
+// scanResult is the outcome of cataloging and matching an input.
+type scanResult struct {
+	packages         []pkg.Package
+	context          pkg.Context
+	remainingMatches match.Matches
+	ignoredMatches   []match.IgnoredMatch
+}
+
+// errCatalog indicates the input could not be cataloged (e.g. an unknown image or an invalid SBOM).
+var errCatalog = errors.New("failed to catalog")
+
+func (s *Server) match(input string, ignoreRules []match.IgnoreRule) (*scanResult, error) {
+	packages, context, err := pkg.Provide(input, s.config.Provider)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errCatalog, err)
+	}
+
+	allMatches := matcher.FindMatchesWithConfig(s.provider, context.Distro, s.config.Matcher, packages...)
+	remainingMatches, ignoredMatches := match.ApplyIgnoreRules(allMatches, ignoreRules)
+
+	return &scanResult{
+		packages:         packages,
+		context:          context,
+		remainingMatches: remainingMatches,
+		ignoredMatches:   ignoredMatches,
+	}, nil
+}
+
+func (s *Server) scan(input string, ignoreRules []match.IgnoreRule) (*models.Document, error) {
+	result, err := s.match(input, ignoreRules)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := models.NewDocument(result.packages, result.context, result.remainingMatches, result.ignoredMatches, s.metadataProvider, nil, s.dbStatus)
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// currentIgnoreRules returns a copy of the ignore rules applied to new scans.
+func (s *Server) currentIgnoreRules() []match.IgnoreRule {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return append(make([]match.IgnoreRule, 0, len(s.ignoreRules)), s.ignoreRules...)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(value); err != nil {
+		log.Warnf("unable to write response: %+v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{Error: err.Error()})
+}
@@ -0,0 +1,257 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/db"
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type mockProvider struct{}
+
+func (mockProvider) GetByDistro(_ *distro.Distro, p pkg.Package) ([]vulnerability.Vulnerability, error) {
+	if p.Name != "alpine-baselayout" {
+		return nil, nil
+	}
+	return []vulnerability.Vulnerability{{
+		Constraint: version.MustGetConstraint("< 3.3.0", version.RpmFormat),
+		ID:         "CVE-2021-fake",
+		Namespace:  "rhel:8",
+	}}, nil
+}
+
+func (mockProvider) GetByLanguage(syftPkg.Language, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (mockProvider) GetByPackageType(syftPkg.Type, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (mockProvider) GetByCPE(syftPkg.CPE) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+type mockMetadataProvider struct{}
+
+func (mockMetadataProvider) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	return &vulnerability.Metadata{ID: id, Namespace: namespace, Severity: "High"}, nil
+}
+
+func newTestServer(t *testing.T, config Config) (*Server, *httptest.Server) {
+	t.Helper()
+	config.Matcher = matcher.Config{Workers: 1}
+	s := New(mockProvider{}, mockMetadataProvider{}, &db.Status{SchemaVersion: 3, Built: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}, config)
+	server := httptest.NewServer(s.Handler())
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+func request(t *testing.T, method, url, contentType string, body []byte, expectedStatus int, result interface{}) {
+	t.Helper()
+	authorizedRequest(t, method, url, "", contentType, body, expectedStatus, result)
+}
+
+func authorizedRequest(t *testing.T, method, url, token, contentType string, body []byte, expectedStatus int, result interface{}) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	response, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	require.Equal(t, expectedStatus, response.StatusCode)
+	if result != nil {
+		require.NoError(t, json.NewDecoder(response.Body).Decode(result))
+	}
+}
+
+func TestServer_Scans(t *testing.T) {
+	s, server := newTestServer(t, Config{})
+	sbom, err := ioutil.ReadFile("test-fixtures/syft-alpine.json")
+	require.NoError(t, err)
+
+	var submitted Scan
+	request(t, http.MethodPost, server.URL+"/v1/scans", "application/json", sbom, http.StatusAccepted, &submitted)
+	assert.NotEmpty(t, submitted.ID)
+	assert.Equal(t, "sbom", submitted.Input)
+	assert.Equal(t, PendingStatus, submitted.Status)
+
+	var invalid Scan
+	request(t, http.MethodPost, server.URL+"/v1/scans", "text/plain", []byte("not an sbom"), http.StatusAccepted, &invalid)
+
+	s.Wait()
+
+	var scan Scan
+	request(t, http.MethodGet, server.URL+"/v1/scans/"+submitted.ID, "", nil, http.StatusOK, &scan)
+	assert.Equal(t, CompleteStatus, scan.Status)
+	assert.NotNil(t, scan.Completed)
+	require.NotNil(t, scan.Document)
+	require.Len(t, scan.Document.Matches, 1)
+	assert.Equal(t, "CVE-2021-fake", scan.Document.Matches[0].Vulnerability.ID)
+	assert.Equal(t, "alpine-baselayout", scan.Document.Matches[0].Artifact.Name)
+
+	request(t, http.MethodGet, server.URL+"/v1/scans/"+invalid.ID, "", nil, http.StatusOK, &scan)
+	assert.Equal(t, FailedStatus, scan.Status)
+	assert.NotEmpty(t, scan.Error)
+
+	var scans []Scan
+	request(t, http.MethodGet, server.URL+"/v1/scans", "", nil, http.StatusOK, &scans)
+	require.Len(t, scans, 2)
+	assert.Equal(t, submitted.ID, scans[0].ID)
+	assert.Nil(t, scans[0].Document, "scans are listed without documents")
+
+	request(t, http.MethodGet, server.URL+"/v1/scans/unknown", "", nil, http.StatusNotFound, nil)
+	request(t, http.MethodDelete, server.URL+"/v1/scans", "", nil, http.StatusMethodNotAllowed, nil)
+}
+
+func TestServer_MaxScans(t *testing.T) {
+	s, server := newTestServer(t, Config{MaxScans: 2})
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		var scan Scan
+		request(t, http.MethodPost, server.URL+"/v1/scans", "text/plain", []byte("not an sbom"), http.StatusAccepted, &scan)
+		ids = append(ids, scan.ID)
+	}
+	s.Wait()
+
+	var scans []Scan
+	request(t, http.MethodGet, server.URL+"/v1/scans", "", nil, http.StatusOK, &scans)
+	require.Len(t, scans, 2)
+	assert.Equal(t, ids[1:], []string{scans[0].ID, scans[1].ID})
+	request(t, http.MethodGet, server.URL+"/v1/scans/"+ids[0], "", nil, http.StatusNotFound, nil)
+}
+
+func TestServer_MaxConcurrentScans(t *testing.T) {
+	s, server := newTestServer(t, Config{MaxConcurrentScans: 1})
+
+	// another scan is running
+	require.True(t, s.acquire())
+	request(t, http.MethodPost, server.URL+"/v1/scans", "text/plain", []byte("not an sbom"), http.StatusTooManyRequests, nil)
+
+	s.release()
+	request(t, http.MethodPost, server.URL+"/v1/scans", "text/plain", []byte("not an sbom"), http.StatusAccepted, nil)
+	s.Wait()
+
+	// the slot of a completed scan is released
+	request(t, http.MethodPost, server.URL+"/v1/scans", "text/plain", []byte("not an sbom"), http.StatusAccepted, nil)
+	s.Wait()
+
+	// the slot of a rejected request is released
+	request(t, http.MethodPost, server.URL+"/v1/scans", "application/json", []byte(`{"image": "dir:/"}`), http.StatusBadRequest, nil)
+	request(t, http.MethodPost, server.URL+"/v1/scans", "text/plain", []byte("not an sbom"), http.StatusAccepted, nil)
+	s.Wait()
+}
+
+func TestServer_IgnoreRules(t *testing.T) {
+	const token = "a-token"
+	s, server := newTestServer(t, Config{IgnoreRules: []match.IgnoreRule{{Vulnerability: "CVE-initial"}}, Token: token})
+
+	var rules []match.IgnoreRule
+	request(t, http.MethodGet, server.URL+"/v1/ignore-rules", "", nil, http.StatusOK, &rules)
+	assert.Equal(t, []match.IgnoreRule{{Vulnerability: "CVE-initial"}}, rules)
+
+	// the ignore rules are only changed with the token
+	request(t, http.MethodPut, server.URL+"/v1/ignore-rules", "application/json", []byte(`[]`), http.StatusUnauthorized, nil)
+	authorizedRequest(t, http.MethodPost, server.URL+"/v1/ignore-rules", "another-token", "application/json", []byte(`[]`), http.StatusUnauthorized, nil)
+
+	authorizedRequest(t, http.MethodPut, server.URL+"/v1/ignore-rules", token, "application/json", []byte(`[{"vulnerability": "CVE-2021-fake"}]`), http.StatusOK, &rules)
+	assert.Equal(t, []match.IgnoreRule{{Vulnerability: "CVE-2021-fake"}}, rules)
+
+	authorizedRequest(t, http.MethodPost, server.URL+"/v1/ignore-rules", token, "application/json", []byte(`[{"package": {"name": "gmp"}}]`), http.StatusOK, &rules)
+	assert.Len(t, rules, 2)
+
+	authorizedRequest(t, http.MethodPut, server.URL+"/v1/ignore-rules", token, "application/json", []byte(`not rules`), http.StatusBadRequest, nil)
+
+	sbom, err := ioutil.ReadFile("test-fixtures/syft-alpine.json")
+	require.NoError(t, err)
+	var scan Scan
+	request(t, http.MethodPost, server.URL+"/v1/scans", "", sbom, http.StatusAccepted, &scan)
+	s.Wait()
+
+	request(t, http.MethodGet, server.URL+"/v1/scans/"+scan.ID, "", nil, http.StatusOK, &scan)
+	require.NotNil(t, scan.Document)
+	assert.Empty(t, scan.Document.Matches)
+	assert.Len(t, scan.Document.IgnoredMatches, 1)
+}
+
+func TestServer_DBStatus(t *testing.T) {
+	_, server := newTestServer(t, Config{})
+
+	var status db.Status
+	request(t, http.MethodGet, server.URL+"/v1/db/status", "", nil, http.StatusOK, &status)
+	assert.Equal(t, 3, status.SchemaVersion)
+}
+
+func TestServer_IgnoreRulesWithoutToken(t *testing.T) {
+	_, server := newTestServer(t, Config{})
+
+	// the ignore rules cannot be changed when the server has no token
+	request(t, http.MethodPut, server.URL+"/v1/ignore-rules", "application/json", []byte(`[]`), http.StatusUnauthorized, nil)
+	request(t, http.MethodPost, server.URL+"/v1/ignore-rules", "application/json", []byte(`[]`), http.StatusUnauthorized, nil)
+}
+
+func TestServer_Metrics(t *testing.T) {
+	s, server := newTestServer(t, Config{})
+	sbom, err := ioutil.ReadFile("test-fixtures/syft-alpine.json")
+	require.NoError(t, err)
+	request(t, http.MethodPost, server.URL+"/v1/scans", "application/json", sbom, http.StatusAccepted, nil)
+	s.Wait()
+
+	response, err := http.Get(server.URL + "/metrics")
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	body, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	// note: the metrics are of every scan of the tests of the package
+	assert.Contains(t, string(body), `grype_scans_total{status="success"}`)
+	assert.Contains(t, string(body), `grype_matches_total{severity="High"}`)
+	assert.Contains(t, string(body), "grype_scan_duration_seconds_count")
+}
+
+func TestServer_ImageRequest(t *testing.T) {
+	input, cleanup, err := scanInput(httptest.NewRequest(http.MethodPost, "/v1/scans", strings.NewReader(`{"image": "alpine:latest"}`)))
+	require.NoError(t, err)
+	defer cleanup()
+	// note: the image request is only recognized as JSON
+	assert.True(t, strings.HasPrefix(input, "sbom:"))
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/scans", strings.NewReader(`{"image": "alpine:latest"}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	input, cleanup, err = scanInput(req)
+	require.NoError(t, err)
+	defer cleanup()
+	assert.Equal(t, "registry:alpine:latest", input)
+
+	// images are only read from a registry (or the docker daemon)
+	for _, image := range []string{"dir:/", "file:/etc/passwd", "sbom:/tmp/sbom.json", "docker-archive:/tmp/image.tar"} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/scans", strings.NewReader(`{"image": "`+image+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		_, _, err := scanInput(req)
+		assert.Error(t, err, image)
+	}
+}
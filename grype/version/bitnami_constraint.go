@@ -0,0 +1,69 @@
+package version
+
+import (
+	"fmt"
+)
+
+type bitnamiConstraint struct {
+	raw        string
+	expression constraintExpression
+}
+
+func newBitnamiConstraint(raw string) (bitnamiConstraint, error) {
+	if raw == "" {
+		// an empty constraint is always satisfied
+		return bitnamiConstraint{}, nil
+	}
+
+	constraints, err := newConstraintExpression(raw, newBitnamiComparator)
+	if err != nil {
+		return bitnamiConstraint{}, fmt.Errorf("unable to parse bitnami constraint phrase: %w", err)
+	}
+
+	return bitnamiConstraint{
+		raw:        raw,
+		expression: constraints,
+	}, nil
+}
+
+func newBitnamiComparator(unit constraintUnit) (Comparator, error) {
+	ver, err := newBitnamiVersion(unit.version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse constraint version (%s): %w", unit.version, err)
+	}
+	return ver, nil
+}
+
+func (c bitnamiConstraint) supported(format Format) bool {
+	return format == BitnamiFormat
+}
+
+func (c bitnamiConstraint) Satisfied(version *Version) (bool, error) {
+	if c.raw == "" && version != nil {
+		// an empty constraint is always satisfied
+		return true, nil
+	} else if version == nil {
+		if c.raw != "" {
+			// a non-empty constraint with no version given should always fail
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if !c.supported(version.Format) {
+		return false, fmt.Errorf("(bitnami) unsupported format: %s", version.Format)
+	}
+
+	if version.rich.bitnamiVer == nil {
+		return false, fmt.Errorf("no rich bitnami version given: %+v", version)
+	}
+
+	return c.expression.satisfied(version)
+}
+
+func (c bitnamiConstraint) String() string {
+	if c.raw == "" {
+		return "none (bitnami)"
+	}
+	return fmt.Sprintf("%s (bitnami)", c.raw)
+}
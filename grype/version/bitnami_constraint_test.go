@@ -0,0 +1,37 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionBitnamiConstraint(t *testing.T) {
+	tests := []testCase{
+		// empty values
+		{version: "2.3.1", constraint: "", satisfied: true},
+		// typical cases
+		{version: "2.3.1", constraint: "< 2.0.0", satisfied: false},
+		{version: "1.3.1-1", constraint: ">= 1.0.0, < 2.0.0", satisfied: true},
+		{version: "0.6.0-3", constraint: "> 0.1.0, < 0.5.0 || > 1.0.0, < 2.0.0", satisfied: false},
+		// the revision is not a semver pre-release
+		{version: "7.0.11-2", constraint: "< 7.0.11", satisfied: false},
+		{version: "7.0.11-2", constraint: ">= 7.0.11", satisfied: true},
+		// the revision only breaks ties when specified on both sides
+		{version: "7.0.11-2", constraint: "= 7.0.11", satisfied: true},
+		{version: "7.0.11-1", constraint: "< 7.0.11-2", satisfied: true},
+		{version: "7.0.11-3", constraint: "< 7.0.11-2", satisfied: false},
+		{version: "7.0.10-9", constraint: "< 7.0.11-0", satisfied: true},
+		// upstream pre-releases are still honored
+		{version: "1.0.0-rc.1-1", constraint: "< 1.0.0", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newBitnamiConstraint(test.constraint)
+			assert.NoError(t, err, "unexpected error from newBitnamiConstraint: %v", err)
+
+			test.assertVersionConstraint(t, BitnamiFormat, constraint)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// bitnamiRevisionPattern matches the package revision that bitnami appends to the upstream version of a component
+// (e.g. the "-2" within "7.0.11-2").
+var bitnamiRevisionPattern = regexp.MustCompile(`^(.+)-(\d+)$`)
+
+type bitnamiVersion struct {
+	semVer *semanticVersion
+	// revision is the bitnami package revision, or -1 if no revision was given
+	revision int
+}
+
+func newBitnamiVersion(raw string) (*bitnamiVersion, error) {
+	raw = strings.TrimSpace(raw)
+	upstream, revision := raw, -1
+	if match := bitnamiRevisionPattern.FindStringSubmatch(raw); match != nil {
+		value, err := strconv.Atoi(match[2])
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse bitnami revision %q: %w", match[2], err)
+		}
+		upstream, revision = match[1], value
+	}
+
+	semVer, err := newSemanticVersion(upstream)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse bitnami version %q: %w", raw, err)
+	}
+
+	return &bitnamiVersion{
+		semVer:   semVer,
+		revision: revision,
+	}, nil
+}
+
+func (v *bitnamiVersion) Compare(other *Version) (int, error) {
+	if other.Format != BitnamiFormat {
+		return -1, fmt.Errorf("unable to compare bitnami version to given format: %s", other.Format)
+	}
+	if other.rich.bitnamiVer == nil {
+		return -1, fmt.Errorf("given empty bitnamiVersion object")
+	}
+
+	return other.rich.bitnamiVer.compare(v), nil
+}
+
+// compare returns 0 if v == other, 1 if v > other, and -1 if v < other. The upstream versions are compared as semver,
+// with the bitnami revision only breaking ties when both versions specify one (e.g. "7.0.11" is equal to "7.0.11-2",
+// however, "7.0.11-1" is less than "7.0.11-2").
+func (v *bitnamiVersion) compare(other *bitnamiVersion) int {
+	if result := v.semVer.verObj.Compare(other.semVer.verObj); result != 0 {
+		return result
+	}
+	if v.revision < 0 || other.revision < 0 {
+		return 0
+	}
+	return compareInts(v.revision, other.revision)
+}
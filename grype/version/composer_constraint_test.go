@@ -0,0 +1,52 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionComposerConstraint(t *testing.T) {
+	tests := []testCase{
+		// empty values
+		{version: "2.3.1", constraint: "", satisfied: true},
+		// typical cases
+		{version: "2.3.1", constraint: "< 2.0.0", satisfied: false},
+		{version: "1.3.1", constraint: ">= 1.0.0, < 2.0.0", satisfied: true},
+		{version: "0.6.0", constraint: "> 0.1.0, < 0.5.0 || > 1.0.0, < 2.0.0", satisfied: false},
+		{version: "4.4.30", constraint: ">=4.4.0,<4.4.31", satisfied: true},
+		// "v" prefixes and missing segments
+		{version: "v1.2.3", constraint: "= 1.2.3", satisfied: true},
+		{version: "v1.2", constraint: "= 1.2.0.0", satisfied: true},
+		{version: "1.2.3.4", constraint: "> 1.2.3", satisfied: true},
+		// stability suffixes
+		{version: "1.0.0-beta2", constraint: "< 1.0.0", satisfied: true},
+		{version: "1.0.0-beta2", constraint: "< 1.0.0-beta10", satisfied: true},
+		{version: "1.0.0-alpha1", constraint: "< 1.0.0-beta1", satisfied: true},
+		{version: "1.0.0-RC1", constraint: "> 1.0.0-beta3", satisfied: true},
+		{version: "1.0.0-rc.1", constraint: "= 1.0.0-RC1", satisfied: true},
+		{version: "1.0.0-b1", constraint: "= 1.0.0-beta1", satisfied: true},
+		{version: "1.0.0-dev", constraint: "< 1.0.0-alpha1", satisfied: true},
+		{version: "1.0.0-beta2-dev", constraint: "< 1.0.0-beta2", satisfied: true},
+		{version: "1.0.0-patch1", constraint: "> 1.0.0", satisfied: true},
+		{version: "1.0.0-pl2", constraint: "< 1.0.1-alpha1", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newComposerConstraint(test.constraint)
+			assert.NoError(t, err, "unexpected error from newComposerConstraint: %v", err)
+
+			test.assertVersionConstraint(t, ComposerFormat, constraint)
+		})
+	}
+}
+
+func TestNewComposerVersion_Invalid(t *testing.T) {
+	for _, raw := range []string{"", "dev-main", "1.2.3.4.5", "1.0.0-gamma"} {
+		t.Run(raw, func(t *testing.T) {
+			_, err := newComposerVersion(raw)
+			assert.Error(t, err)
+		})
+	}
+}
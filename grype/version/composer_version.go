@@ -0,0 +1,122 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// composerVersionPattern matches normalizable composer versions, which may have a "v" prefix, up to four numeric parts
+// and an optional stability suffix (e.g. "v1.2.3", "1.2.3.4", "1.0.0-beta2", "1.0.0-RC1", or "1.0.0-alpha.1-dev").
+// See https://getcomposer.org/doc/articles/versions.md
+var composerVersionPattern = regexp.MustCompile(`(?i)^v?(?P<numeric>\d+(\.\d+){0,3})([.-]?(?P<stability>stable|beta|b|rc|alpha|a|patch|pl|p)([.-]?(?P<stabilityNumber>\d+))?)?(?P<dev>[.-]?dev)?$`)
+
+// composer stabilities in increasing order of precedence (a "dev" suffix indicates a version that precedes the
+// version it is attached to)
+const (
+	composerDevStability = iota
+	composerAlphaStability
+	composerBetaStability
+	composerRCStability
+	composerStableStability
+	composerPatchStability
+)
+
+var composerStabilities = map[string]int{
+	"alpha":  composerAlphaStability,
+	"a":      composerAlphaStability,
+	"beta":   composerBetaStability,
+	"b":      composerBetaStability,
+	"rc":     composerRCStability,
+	"stable": composerStableStability,
+	"patch":  composerPatchStability,
+	"pl":     composerPatchStability,
+	"p":      composerPatchStability,
+}
+
+type composerVersion struct {
+	numeric         [4]int
+	stability       int
+	stabilityNumber int
+	dev             bool
+}
+
+func newComposerVersion(raw string) (*composerVersion, error) {
+	match := composerVersionPattern.FindStringSubmatch(strings.TrimSpace(raw))
+	if match == nil {
+		// note: branch versions (e.g. "dev-main") cannot be ordered relative to tagged releases
+		return nil, fmt.Errorf("unable to parse composer version: %q", raw)
+	}
+
+	ver := composerVersion{
+		stability: composerStableStability,
+	}
+
+	for i, part := range strings.Split(match[composerVersionPattern.SubexpIndex("numeric")], ".") {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse composer version segment %q: %w", part, err)
+		}
+		ver.numeric[i] = value
+	}
+
+	if stability := match[composerVersionPattern.SubexpIndex("stability")]; stability != "" {
+		ver.stability = composerStabilities[strings.ToLower(stability)]
+	}
+
+	if number := match[composerVersionPattern.SubexpIndex("stabilityNumber")]; number != "" {
+		value, err := strconv.Atoi(number)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse composer stability number %q: %w", number, err)
+		}
+		ver.stabilityNumber = value
+	}
+
+	if match[composerVersionPattern.SubexpIndex("dev")] != "" {
+		if ver.stability == composerStableStability {
+			// "1.0.0-dev" is a development version of 1.0.0 (not a development version of the stable release)
+			ver.stability = composerDevStability
+		} else {
+			ver.dev = true
+		}
+	}
+
+	return &ver, nil
+}
+
+func (v *composerVersion) Compare(other *Version) (int, error) {
+	if other.Format != ComposerFormat {
+		return -1, fmt.Errorf("unable to compare composer version to given format: %s", other.Format)
+	}
+	if other.rich.composerVer == nil {
+		return -1, fmt.Errorf("given empty composerVersion object")
+	}
+
+	return other.rich.composerVer.compare(v), nil
+}
+
+// compare returns 0 if v == other, 1 if v > other, and -1 if v < other.
+func (v *composerVersion) compare(other *composerVersion) int {
+	for i := range v.numeric {
+		if v.numeric[i] != other.numeric[i] {
+			return compareInts(v.numeric[i], other.numeric[i])
+		}
+	}
+
+	if v.stability != other.stability {
+		return compareInts(v.stability, other.stability)
+	}
+
+	if v.stabilityNumber != other.stabilityNumber {
+		return compareInts(v.stabilityNumber, other.stabilityNumber)
+	}
+
+	switch {
+	case v.dev == other.dev:
+		return 0
+	case v.dev:
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,69 @@
+package version
+
+import (
+	"fmt"
+)
+
+type condaConstraint struct {
+	raw        string
+	expression constraintExpression
+}
+
+func newCondaConstraint(raw string) (condaConstraint, error) {
+	if raw == "" {
+		// an empty constraint is always satisfied
+		return condaConstraint{}, nil
+	}
+
+	constraints, err := newConstraintExpression(raw, newCondaComparator)
+	if err != nil {
+		return condaConstraint{}, fmt.Errorf("unable to parse conda constraint phrase: %w", err)
+	}
+
+	return condaConstraint{
+		raw:        raw,
+		expression: constraints,
+	}, nil
+}
+
+func newCondaComparator(unit constraintUnit) (Comparator, error) {
+	ver, err := newCondaVersion(unit.version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse constraint version (%s): %w", unit.version, err)
+	}
+	return ver, nil
+}
+
+func (c condaConstraint) supported(format Format) bool {
+	return format == CondaFormat
+}
+
+func (c condaConstraint) Satisfied(version *Version) (bool, error) {
+	if c.raw == "" && version != nil {
+		// an empty constraint is always satisfied
+		return true, nil
+	} else if version == nil {
+		if c.raw != "" {
+			// a non-empty constraint with no version given should always fail
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if !c.supported(version.Format) {
+		return false, fmt.Errorf("(conda) unsupported format: %s", version.Format)
+	}
+
+	if version.rich.condaVer == nil {
+		return false, fmt.Errorf("no rich conda version given: %+v", version)
+	}
+
+	return c.expression.satisfied(version)
+}
+
+func (c condaConstraint) String() string {
+	if c.raw == "" {
+		return "none (conda)"
+	}
+	return fmt.Sprintf("%s (conda)", c.raw)
+}
@@ -0,0 +1,61 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionCondaConstraint(t *testing.T) {
+	tests := []testCase{
+		// empty values
+		{version: "2.3.1", constraint: "", satisfied: true},
+		// typical cases
+		{version: "2.3.1", constraint: "< 2.0.0", satisfied: false},
+		{version: "1.3.1", constraint: ">= 1.0.0, < 2.0.0", satisfied: true},
+		{version: "0.6.0", constraint: "> 0.1.0, < 0.5.0 || > 1.0.0, < 2.0.0", satisfied: false},
+		{version: "1.1.1l", constraint: "< 1.1.1m", satisfied: true},
+		{version: "1.1.1l", constraint: "> 1.1.1k", satisfied: true},
+		// note: letters sort before numbers (and missing parts are treated as zero), unlike openssl's versioning
+		{version: "1.1.1l", constraint: "< 1.1.1", satisfied: true},
+		{version: "2021.10", constraint: "> 2021.9", satisfied: true},
+		// ordering examples from the conda documentation
+		{version: "0.4", constraint: "= 0.4.0", satisfied: true},
+		{version: "0.4.0", constraint: "< 0.4.1.rc", satisfied: true},
+		{version: "0.4.1.rc", constraint: "= 0.4.1.RC", satisfied: true},
+		{version: "0.4.1.RC", constraint: "< 0.4.1", satisfied: true},
+		{version: "0.5a1", constraint: "< 0.5b3", satisfied: true},
+		{version: "0.5C1", constraint: "< 0.5", satisfied: true},
+		{version: "0.9.6", constraint: "< 0.960923", satisfied: true},
+		{version: "1.1dev1", constraint: "< 1.1a1", satisfied: true},
+		{version: "1.1.0dev1", constraint: "= 1.1.dev1", satisfied: true},
+		{version: "1.1.0rc1", constraint: "< 1.1.0", satisfied: true},
+		{version: "1.1.0", constraint: "< 1.1.0post1", satisfied: true},
+		{version: "1.1post1", constraint: "> 1.1.0", satisfied: true},
+		{version: "1.1post1", constraint: "< 1996.07.12", satisfied: true},
+		{version: "1!0.4.1", constraint: "> 1996.07.12", satisfied: true},
+		{version: "1.0+abc.7", constraint: "> 1.0+abc.2", satisfied: true},
+		{version: "1.0_1", constraint: "= 1.0.1", satisfied: true},
+		// build strings are not considered
+		{version: "1.21.2-py39h20f2e39_0", constraint: "= 1.21.2", satisfied: true},
+		{version: "1.21.2=py39h20f2e39_0", constraint: "< 1.21.3", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newCondaConstraint(test.constraint)
+			assert.NoError(t, err, "unexpected error from newCondaConstraint: %v", err)
+
+			test.assertVersionConstraint(t, CondaFormat, constraint)
+		})
+	}
+}
+
+func TestNewCondaVersion_Invalid(t *testing.T) {
+	for _, raw := range []string{"", "-py39_0", "1..2", "a!1.0"} {
+		t.Run(raw, func(t *testing.T) {
+			_, err := newCondaVersion(raw)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package version
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// condaVersionPartPattern splits a version component into runs of numerals and non-numerals (e.g. "1a1" becomes
+// ["1", "a", "1"])
+var condaVersionPartPattern = regexp.MustCompile(`\d+|[^\d]+`)
+
+// condaBuildSeparators are the characters that separate a version from a build string (e.g. "1.21.2-py39h20f2e39_0"
+// as found in package filenames, or "1.21.2=py39h20f2e39_0" as found in match specs). Note that conda does not allow
+// any of these characters within a version.
+const condaBuildSeparators = "-= "
+
+// condaVersionPart is a single run within a version component, which is either numeric (number) or a string (str)
+type condaVersionPart struct {
+	number float64
+	str    string
+}
+
+func (p condaVersionPart) isString() bool {
+	return p.str != ""
+}
+
+// condaVersion implements conda's VersionOrder, see
+// https://github.com/conda/conda/blob/master/conda/models/version.py
+type condaVersion struct {
+	epoch      int
+	components [][]condaVersionPart
+	local      [][]condaVersionPart
+}
+
+func newCondaVersion(raw string) (*condaVersion, error) {
+	version := strings.ToLower(strings.TrimSpace(raw))
+
+	// remove any build string
+	if i := strings.IndexAny(version, condaBuildSeparators); i >= 0 {
+		version = version[:i]
+	}
+	if version == "" {
+		return nil, fmt.Errorf("unable to parse conda version: %q", raw)
+	}
+
+	var ver condaVersion
+	if fields := strings.SplitN(version, "!", 2); len(fields) == 2 {
+		epoch, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse conda version epoch %q: %w", raw, err)
+		}
+		ver.epoch = epoch
+		version = fields[1]
+	}
+
+	var local string
+	if fields := strings.SplitN(version, "+", 2); len(fields) == 2 {
+		version, local = fields[0], fields[1]
+	}
+
+	var err error
+	if ver.components, err = parseCondaVersionComponents(version); err != nil {
+		return nil, fmt.Errorf("unable to parse conda version %q: %w", raw, err)
+	}
+	if local != "" {
+		if ver.local, err = parseCondaVersionComponents(local); err != nil {
+			return nil, fmt.Errorf("unable to parse conda local version %q: %w", raw, err)
+		}
+	}
+	return &ver, nil
+}
+
+func parseCondaVersionComponents(version string) ([][]condaVersionPart, error) {
+	// underscores are treated as component separators
+	version = strings.ReplaceAll(version, "_", ".")
+
+	var components [][]condaVersionPart
+	for _, component := range strings.Split(version, ".") {
+		runs := condaVersionPartPattern.FindAllString(component, -1)
+		if len(runs) == 0 {
+			return nil, fmt.Errorf("empty version component")
+		}
+
+		var parts []condaVersionPart
+		if _, err := strconv.Atoi(runs[0]); err != nil {
+			// components always start with a number
+			parts = append(parts, condaVersionPart{})
+		}
+		for _, run := range runs {
+			parts = append(parts, newCondaVersionPart(run))
+		}
+		components = append(components, parts)
+	}
+	return components, nil
+}
+
+func newCondaVersionPart(run string) condaVersionPart {
+	if value, err := strconv.Atoi(run); err == nil {
+		return condaVersionPart{number: float64(value)}
+	}
+	switch run {
+	case "post":
+		// post releases are greater than all other versions
+		return condaVersionPart{number: math.Inf(1)}
+	case "dev":
+		// dev releases are less than all other strings (uppercase sorts before lowercase)
+		return condaVersionPart{str: "DEV"}
+	}
+	return condaVersionPart{str: run}
+}
+
+func (v *condaVersion) Compare(other *Version) (int, error) {
+	if other.Format != CondaFormat {
+		return -1, fmt.Errorf("unable to compare conda version to given format: %s", other.Format)
+	}
+	if other.rich.condaVer == nil {
+		return -1, fmt.Errorf("given empty condaVersion object")
+	}
+
+	return other.rich.condaVer.compare(v), nil
+}
+
+// compare returns 0 if v == other, 1 if v > other, and -1 if v < other.
+func (v *condaVersion) compare(other *condaVersion) int {
+	if v.epoch != other.epoch {
+		return compareInts(v.epoch, other.epoch)
+	}
+	if c := compareCondaVersionComponents(v.components, other.components); c != 0 {
+		return c
+	}
+	return compareCondaVersionComponents(v.local, other.local)
+}
+
+func compareCondaVersionComponents(a, b [][]condaVersionPart) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var aParts, bParts []condaVersionPart
+		if i < len(a) {
+			aParts = a[i]
+		}
+		if i < len(b) {
+			bParts = b[i]
+		}
+
+		// missing components and parts are treated as zero
+		for j := 0; j < len(aParts) || j < len(bParts); j++ {
+			var aPart, bPart condaVersionPart
+			if j < len(aParts) {
+				aPart = aParts[j]
+			}
+			if j < len(bParts) {
+				bPart = bParts[j]
+			}
+
+			switch {
+			case aPart == bPart:
+				continue
+			case aPart.isString() && !bPart.isString():
+				return -1
+			case !aPart.isString() && bPart.isString():
+				return 1
+			case aPart.isString():
+				return strings.Compare(aPart.str, bPart.str)
+			case aPart.number < bPart.number:
+				return -1
+			default:
+				return 1
+			}
+		}
+	}
+	return 0
+}
@@ -15,22 +15,44 @@ func GetConstraint(constStr string, format Format) (Constraint, error) {
 		return newApkConstraint(constStr)
 	case SemanticFormat:
 		return newSemanticConstraint(constStr)
+	case SemverRangeFormat:
+		return newSemverRangeConstraint(constStr)
 	case DebFormat:
 		return newDebConstraint(constStr)
 	case RpmFormat:
 		return newRpmConstraint(constStr)
 	case PythonFormat:
-		// This is specific to PythonFormat so that it adheres to PEP440 and its odd corner-cases
-		// It is significantly odd enough, that the fuzzyConstraint is the best bet to compare versions.
-		// Although this will work in most cases, some oddities aren't supported, like:
-		// 1.0b2.post345.dev456 which is allowed by the spec. In that case (a dev release of a post release)
-		// the comparator will fail. See https://www.python.org/dev/peps/pep-0440
-		return newFuzzyConstraint(constStr, "python")
+		return newPep440Constraint(constStr)
 	case KBFormat:
 		return newKBConstraint(constStr)
+	case GolangFormat:
+		return newGolangConstraint(constStr)
+	case NugetFormat:
+		return newNugetConstraint(constStr)
+	case ComposerFormat:
+		return newComposerConstraint(constStr)
+	case GemFormat:
+		return newGemConstraint(constStr)
+	case HexFormat:
+		return newHexConstraint(constStr)
+	case CondaFormat:
+		return newCondaConstraint(constStr)
+	case RFormat:
+		return newRConstraint(constStr)
+	case HaskellFormat:
+		return newHaskellConstraint(constStr)
+	case NpmFormat:
+		return newNpmConstraint(constStr)
+	case MavenFormat:
+		return newMavenConstraint(constStr)
+	case BitnamiFormat:
+		return newBitnamiConstraint(constStr)
 	case UnknownFormat:
 		return newFuzzyConstraint(constStr, "unknown")
 	}
+	if _, ok := customFormat(format); ok {
+		return newCustomConstraint(constStr, format)
+	}
 	return nil, fmt.Errorf("could not find constraint for given format: %s", format)
 }
 
@@ -0,0 +1,65 @@
+package version
+
+import (
+	"fmt"
+)
+
+type customConstraint struct {
+	raw        string
+	format     Format
+	expression constraintExpression
+}
+
+func newCustomConstraint(raw string, format Format) (customConstraint, error) {
+	if raw == "" {
+		// an empty constraint is always satisfied
+		return customConstraint{format: format}, nil
+	}
+
+	constraints, err := newConstraintExpression(raw, func(unit constraintUnit) (Comparator, error) {
+		ver, err := newCustomVersion(unit.version, format)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse constraint version (%s): %w", unit.version, err)
+		}
+		return ver, nil
+	})
+	if err != nil {
+		return customConstraint{}, fmt.Errorf("unable to parse %s constraint phrase: %w", format, err)
+	}
+
+	return customConstraint{
+		raw:        raw,
+		format:     format,
+		expression: constraints,
+	}, nil
+}
+
+func (c customConstraint) Satisfied(version *Version) (bool, error) {
+	if c.raw == "" && version != nil {
+		// an empty constraint is always satisfied
+		return true, nil
+	} else if version == nil {
+		if c.raw != "" {
+			// a non-empty constraint with no version given should always fail
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if version.Format != c.format {
+		return false, fmt.Errorf("(%s) unsupported format: %s", c.format, version.Format)
+	}
+
+	if version.rich.customVer == nil {
+		return false, fmt.Errorf("no rich %s version given: %+v", c.format, version)
+	}
+
+	return c.expression.satisfied(version)
+}
+
+func (c customConstraint) String() string {
+	if c.raw == "" {
+		return fmt.Sprintf("none (%s)", c.format)
+	}
+	return fmt.Sprintf("%s (%s)", c.raw, c.format)
+}
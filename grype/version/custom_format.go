@@ -0,0 +1,156 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	grypePkg "github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/syft/syft/pkg"
+)
+
+// CustomVersion is a parsed version of a custom (registered) version format.
+type CustomVersion interface {
+	// Compare returns 0 if the version is equal to other, 1 if greater, and -1 if less. The other version is always
+	// parsed by the same format.
+	Compare(other CustomVersion) (int, error)
+}
+
+// CustomFormat describes a versioning scheme that is not natively supported (e.g. vendor firmware versioning), which
+// may be registered with RegisterFormat.
+type CustomFormat struct {
+	// Name identifies the format, matching the version format of vulnerability records (case-insensitive).
+	Name string
+	// PackageTypes are the package types versioned with this format.
+	PackageTypes []pkg.Type
+	// PURLTypes are the package URL types (e.g. "generic") versioned with this format, taking precedence over the
+	// package type.
+	PURLTypes []string
+	// Parse returns the version for the given raw version string.
+	Parse func(raw string) (CustomVersion, error)
+}
+
+var customFormats = struct {
+	sync.RWMutex
+	formats []CustomFormat
+}{}
+
+// RegisterFormat adds a custom version format, returning the format that it is assigned. Once registered, the versions
+// of packages with any of the given package or package URL types are parsed and compared using the custom format by
+// all matchers, as are any vulnerability constraints with the same format name.
+func RegisterFormat(f CustomFormat) (Format, error) {
+	if strings.TrimSpace(f.Name) == "" {
+		return UnknownFormat, fmt.Errorf("custom version format must have a name")
+	}
+	if f.Parse == nil {
+		return UnknownFormat, fmt.Errorf("custom version format %q must have a parse function", f.Name)
+	}
+	if existing := ParseFormat(f.Name); existing != UnknownFormat {
+		return UnknownFormat, fmt.Errorf("version format %q is already defined", f.Name)
+	}
+
+	customFormats.Lock()
+	defer customFormats.Unlock()
+
+	// check again while holding the lock, in case the same format is being registered concurrently
+	for _, existing := range customFormats.formats {
+		if strings.EqualFold(existing.Name, f.Name) {
+			return UnknownFormat, fmt.Errorf("version format %q is already defined", f.Name)
+		}
+	}
+
+	customFormats.formats = append(customFormats.formats, f)
+	return Format(len(formatStr) + len(customFormats.formats) - 1), nil
+}
+
+// customFormat returns the custom format definition for the given format (if it is a registered custom format).
+func customFormat(f Format) (CustomFormat, bool) {
+	customFormats.RLock()
+	defer customFormats.RUnlock()
+
+	idx := int(f) - len(formatStr)
+	if idx < 0 || idx >= len(customFormats.formats) {
+		return CustomFormat{}, false
+	}
+	return customFormats.formats[idx], true
+}
+
+// customFormatBy returns the first registered custom format that satisfies the given predicate.
+func customFormatBy(predicate func(CustomFormat) bool) (Format, bool) {
+	customFormats.RLock()
+	defer customFormats.RUnlock()
+
+	for idx, f := range customFormats.formats {
+		if predicate(f) {
+			return Format(len(formatStr) + idx), true
+		}
+	}
+	return UnknownFormat, false
+}
+
+func customFormatFromName(name string) (Format, bool) {
+	return customFormatBy(func(f CustomFormat) bool {
+		return strings.EqualFold(f.Name, name)
+	})
+}
+
+func customFormatFromPkgType(t pkg.Type) (Format, bool) {
+	return customFormatBy(func(f CustomFormat) bool {
+		for _, candidate := range f.PackageTypes {
+			if candidate == t {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func customFormatFromPURL(purl string) (Format, bool) {
+	purlType := grypePkg.PURLType(purl)
+	if purlType == "" {
+		return UnknownFormat, false
+	}
+	return customFormatBy(func(f CustomFormat) bool {
+		for _, candidate := range f.PURLTypes {
+			if strings.EqualFold(candidate, purlType) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// customVersion adapts a version of a custom format to a Comparator.
+type customVersion struct {
+	format Format
+	ver    CustomVersion
+}
+
+func newCustomVersion(raw string, format Format) (*customVersion, error) {
+	f, ok := customFormat(format)
+	if !ok {
+		return nil, fmt.Errorf("unknown custom version format: %d", format)
+	}
+	ver, err := f.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s version %q: %w", f.Name, raw, err)
+	}
+	if ver == nil {
+		return nil, fmt.Errorf("unable to parse %s version %q", f.Name, raw)
+	}
+	return &customVersion{
+		format: format,
+		ver:    ver,
+	}, nil
+}
+
+func (v *customVersion) Compare(other *Version) (int, error) {
+	if other.Format != v.format {
+		return -1, fmt.Errorf("unable to compare %s version to given format: %s", v.format, other.Format)
+	}
+	if other.rich.customVer == nil {
+		return -1, fmt.Errorf("given empty customVersion object")
+	}
+
+	return other.rich.customVer.ver.Compare(v.ver)
+}
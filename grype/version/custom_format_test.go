@@ -0,0 +1,120 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	grypePkg "github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/syft/syft/pkg"
+)
+
+const firmwarePkg pkg.Type = "test-firmware"
+
+// firmwareVersion is an example vendor versioning scheme, where versions are a build number with a "FW" prefix
+// (e.g. "FW1021").
+type firmwareVersion int
+
+func parseFirmwareVersion(raw string) (CustomVersion, error) {
+	if !strings.HasPrefix(raw, "FW") {
+		return nil, fmt.Errorf("missing FW prefix")
+	}
+	build, err := strconv.Atoi(strings.TrimPrefix(raw, "FW"))
+	if err != nil {
+		return nil, err
+	}
+	return firmwareVersion(build), nil
+}
+
+func (v firmwareVersion) Compare(other CustomVersion) (int, error) {
+	o, ok := other.(firmwareVersion)
+	if !ok {
+		return -1, fmt.Errorf("unexpected version type: %T", other)
+	}
+	return compareInts(int(v), int(o)), nil
+}
+
+// firmwareFormat is registered once per test binary, since registrations cannot be removed.
+var firmwareFormat = func() Format {
+	format, err := RegisterFormat(CustomFormat{
+		Name:         "test-firmware",
+		PackageTypes: []pkg.Type{firmwarePkg},
+		PURLTypes:    []string{"firmware"},
+		Parse:        parseFirmwareVersion,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return format
+}()
+
+func TestRegisterFormat_Detection(t *testing.T) {
+	assert.Equal(t, "test-firmware", firmwareFormat.String())
+	assert.Equal(t, firmwareFormat, ParseFormat("Test-Firmware"))
+	assert.Equal(t, firmwareFormat, FormatFromPkgType(firmwarePkg))
+	assert.Equal(t, firmwareFormat, FormatFromPkg(grypePkg.Package{Type: pkg.UnknownPkg, PURL: "pkg:firmware/acme/router@FW1021"}))
+	assert.Equal(t, UnknownFormat, FormatFromPkg(grypePkg.Package{Type: pkg.UnknownPkg, PURL: "pkg:generic/acme/router@FW1021"}))
+}
+
+func TestRegisterFormat_Constraint(t *testing.T) {
+	tests := []testCase{
+		{version: "FW1021", constraint: "", satisfied: true},
+		{version: "FW1021", constraint: "< FW1100", satisfied: true},
+		{version: "FW1021", constraint: ">= FW900, < FW1000 || = FW1021", satisfied: true},
+		{version: "FW1100", constraint: "< FW1100", satisfied: false},
+		{version: "FW999", constraint: "> FW1000", satisfied: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := GetConstraint(test.constraint, firmwareFormat)
+			require.NoError(t, err)
+
+			test.assertVersionConstraint(t, firmwareFormat, constraint)
+		})
+	}
+}
+
+func TestRegisterFormat_FromPkg(t *testing.T) {
+	ver, err := NewVersionFromPkg(grypePkg.Package{Type: firmwarePkg, Version: "FW1021"})
+	require.NoError(t, err)
+	assert.Equal(t, firmwareFormat, ver.Format)
+
+	_, err = NewVersionFromPkg(grypePkg.Package{Type: firmwarePkg, Version: "1.0.21"})
+	assert.Error(t, err)
+}
+
+func TestRegisterFormat_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		format CustomFormat
+	}{
+		{
+			name:   "missing name",
+			format: CustomFormat{Parse: parseFirmwareVersion},
+		},
+		{
+			name:   "missing parse function",
+			format: CustomFormat{Name: "test-missing-parse"},
+		},
+		{
+			name:   "built-in format",
+			format: CustomFormat{Name: "semver", Parse: parseFirmwareVersion},
+		},
+		{
+			name:   "already registered",
+			format: CustomFormat{Name: "TEST-FIRMWARE", Parse: parseFirmwareVersion},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := RegisterFormat(test.format)
+			assert.Error(t, err)
+		})
+	}
+}
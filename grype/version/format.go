@@ -3,6 +3,7 @@ package version
 import (
 	"strings"
 
+	grypePkg "github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/syft/syft/pkg"
 )
 
@@ -14,6 +15,18 @@ const (
 	RpmFormat
 	PythonFormat
 	KBFormat
+	GolangFormat
+	NugetFormat
+	ComposerFormat
+	GemFormat
+	HexFormat
+	CondaFormat
+	RFormat
+	HaskellFormat
+	BitnamiFormat
+	MavenFormat
+	NpmFormat
+	SemverRangeFormat
 )
 
 type Format int
@@ -26,6 +39,18 @@ var formatStr = []string{
 	"RPM",
 	"Python",
 	"KB",
+	"Go",
+	"NuGet",
+	"Composer",
+	"Gem",
+	"Hex",
+	"Conda",
+	"R",
+	"Haskell",
+	"Bitnami",
+	"Maven",
+	"npm",
+	"SemverRange",
 }
 
 var Formats = []Format{
@@ -35,6 +60,18 @@ var Formats = []Format{
 	RpmFormat,
 	PythonFormat,
 	KBFormat,
+	GolangFormat,
+	NugetFormat,
+	ComposerFormat,
+	GemFormat,
+	HexFormat,
+	CondaFormat,
+	RFormat,
+	HaskellFormat,
+	BitnamiFormat,
+	MavenFormat,
+	NpmFormat,
+	SemverRangeFormat,
 }
 
 func ParseFormat(userStr string) Format {
@@ -47,15 +84,55 @@ func ParseFormat(userStr string) Format {
 		return DebFormat
 	case strings.ToLower(RpmFormat.String()), "rpmdb":
 		return RpmFormat
-	case strings.ToLower(PythonFormat.String()), "python":
+	case strings.ToLower(PythonFormat.String()), "python", "pep440", "pypi":
 		return PythonFormat
 	case strings.ToLower(KBFormat.String()), "kb":
 		return KBFormat
+	case strings.ToLower(GolangFormat.String()), "golang", "go-module":
+		return GolangFormat
+	case strings.ToLower(NugetFormat.String()), "dotnet":
+		return NugetFormat
+	case strings.ToLower(ComposerFormat.String()), "php", "php-composer":
+		return ComposerFormat
+	case strings.ToLower(GemFormat.String()), "ruby", "gemfile":
+		return GemFormat
+	case strings.ToLower(HexFormat.String()), "elixir", "erlang":
+		return HexFormat
+	case strings.ToLower(CondaFormat.String()):
+		return CondaFormat
+	case strings.ToLower(RFormat.String()), "cran":
+		return RFormat
+	case strings.ToLower(HaskellFormat.String()), "hackage", "pvp":
+		return HaskellFormat
+	case strings.ToLower(BitnamiFormat.String()):
+		return BitnamiFormat
+	case strings.ToLower(MavenFormat.String()), "java":
+		return MavenFormat
+	case strings.ToLower(NpmFormat.String()), "node", "javascript":
+		return NpmFormat
+	case strings.ToLower(SemverRangeFormat.String()), "cargo", "crates.io", "pub":
+		return SemverRangeFormat
+	}
+	if format, ok := customFormatFromName(userStr); ok {
+		return format
 	}
 	return UnknownFormat
 }
 
+// FormatFromPkg returns the version format for the given package, preferring any custom format registered for the
+// package URL type or package type.
+func FormatFromPkg(p grypePkg.Package) Format {
+	if format, ok := customFormatFromPURL(p.PURL); ok {
+		return format
+	}
+	return FormatFromPkgType(p.Type)
+}
+
 func FormatFromPkgType(t pkg.Type) Format {
+	if format, ok := customFormatFromPkgType(t); ok {
+		return format
+	}
+
 	var format Format
 	switch t {
 	case pkg.ApkPkg:
@@ -65,11 +142,33 @@ func FormatFromPkgType(t pkg.Type) Format {
 	case pkg.RpmPkg:
 		format = RpmFormat
 	case pkg.GemPkg:
+		format = GemFormat
+	case grypePkg.HexPkg:
+		format = HexFormat
+	case grypePkg.CondaPkg:
+		format = CondaFormat
+	case grypePkg.RPkg:
+		format = RFormat
+	case grypePkg.HackagePkg:
+		format = HaskellFormat
+	case grypePkg.BitnamiPkg:
+		format = BitnamiFormat
+	case pkg.JavaPkg, pkg.JenkinsPluginPkg:
+		format = MavenFormat
+	case pkg.NpmPkg:
+		format = NpmFormat
+	case pkg.RustPkg, grypePkg.DartPubPkg, grypePkg.SwiftPkg:
 		format = SemanticFormat
 	case pkg.PythonPkg:
 		format = PythonFormat
 	case pkg.KbPkg:
 		format = KBFormat
+	case pkg.GoModulePkg:
+		format = GolangFormat
+	case grypePkg.DotnetPkg, grypePkg.ChocolateyPkg:
+		format = NugetFormat
+	case pkg.PhpComposerPkg:
+		format = ComposerFormat
 	default:
 		format = UnknownFormat
 	}
@@ -77,6 +176,9 @@ func FormatFromPkgType(t pkg.Type) Format {
 }
 
 func (f Format) String() string {
+	if custom, ok := customFormat(f); ok {
+		return custom.Name
+	}
 	if int(f) >= len(formatStr) || f < 0 {
 		return formatStr[0]
 	}
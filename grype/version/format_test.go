@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	grypePkg "github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/syft/syft/pkg"
 )
 
@@ -28,6 +29,34 @@ func TestParseFormat(t *testing.T) {
 			input:  "semver",
 			format: SemanticFormat,
 		},
+		{
+			input:  "go",
+			format: GolangFormat,
+		},
+		{
+			input:  "golang",
+			format: GolangFormat,
+		},
+		{
+			input:  "gem",
+			format: GemFormat,
+		},
+		{
+			input:  "pep440",
+			format: PythonFormat,
+		},
+		{
+			input:  "pypi",
+			format: PythonFormat,
+		},
+		{
+			input:  "cargo",
+			format: SemverRangeFormat,
+		},
+		{
+			input:  "pub",
+			format: SemverRangeFormat,
+		},
 	}
 
 	for _, test := range tests {
@@ -52,8 +81,32 @@ func TestFormatFromPkgType(t *testing.T) {
 		},
 		{
 			pkgType: pkg.GemPkg,
+			format:  GemFormat,
+		},
+		{
+			pkgType: pkg.GoModulePkg,
+			format:  GolangFormat,
+		},
+		{
+			pkgType: pkg.RustPkg,
+			format:  SemanticFormat,
+		},
+		{
+			pkgType: grypePkg.DartPubPkg,
 			format:  SemanticFormat,
 		},
+		{
+			pkgType: pkg.PythonPkg,
+			format:  PythonFormat,
+		},
+		{
+			pkgType: pkg.JavaPkg,
+			format:  MavenFormat,
+		},
+		{
+			pkgType: pkg.NpmPkg,
+			format:  NpmFormat,
+		},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,104 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// gemPessimisticPattern matches pessimistic constraint units (e.g. "~> 1.2.3")
+var gemPessimisticPattern = regexp.MustCompile(`^~>\s*(?P<version>\S+)$`)
+
+type gemConstraint struct {
+	raw        string
+	expression constraintExpression
+}
+
+func newGemConstraint(raw string) (gemConstraint, error) {
+	if raw == "" {
+		// an empty constraint is always satisfied
+		return gemConstraint{}, nil
+	}
+
+	expanded, err := expandGemPessimisticConstraints(raw)
+	if err != nil {
+		return gemConstraint{}, err
+	}
+
+	constraints, err := newConstraintExpression(expanded, newGemComparator)
+	if err != nil {
+		return gemConstraint{}, fmt.Errorf("unable to parse gem constraint phrase: %w", err)
+	}
+
+	return gemConstraint{
+		raw:        raw,
+		expression: constraints,
+	}, nil
+}
+
+// expandGemPessimisticConstraints rewrites pessimistic constraints into explicit comparisons
+// (e.g. "~> 1.2.3" becomes ">= 1.2.3, < 1.3").
+func expandGemPessimisticConstraints(phrase string) (string, error) {
+	orParts := strings.Split(phrase, "||")
+	for orIdx, orPart := range orParts {
+		andParts := strings.Split(orPart, ",")
+		for andIdx, andPart := range andParts {
+			andPart = strings.TrimSpace(andPart)
+			match := gemPessimisticPattern.FindStringSubmatch(andPart)
+			if match == nil {
+				andParts[andIdx] = andPart
+				continue
+			}
+			lower := match[gemPessimisticPattern.SubexpIndex("version")]
+			upper, err := bumpGemVersion(lower)
+			if err != nil {
+				return "", fmt.Errorf("unable to expand pessimistic constraint %q: %w", andPart, err)
+			}
+			andParts[andIdx] = fmt.Sprintf(">= %s, < %s", lower, upper)
+		}
+		orParts[orIdx] = strings.Join(andParts, ", ")
+	}
+	return strings.Join(orParts, " || "), nil
+}
+
+func newGemComparator(unit constraintUnit) (Comparator, error) {
+	ver, err := newGemVersion(unit.version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse constraint version (%s): %w", unit.version, err)
+	}
+	return ver, nil
+}
+
+func (c gemConstraint) supported(format Format) bool {
+	return format == GemFormat
+}
+
+func (c gemConstraint) Satisfied(version *Version) (bool, error) {
+	if c.raw == "" && version != nil {
+		// an empty constraint is always satisfied
+		return true, nil
+	} else if version == nil {
+		if c.raw != "" {
+			// a non-empty constraint with no version given should always fail
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if !c.supported(version.Format) {
+		return false, fmt.Errorf("(gem) unsupported format: %s", version.Format)
+	}
+
+	if version.rich.gemVer == nil {
+		return false, fmt.Errorf("no rich gem version given: %+v", version)
+	}
+
+	return c.expression.satisfied(version)
+}
+
+func (c gemConstraint) String() string {
+	if c.raw == "" {
+		return "none (gem)"
+	}
+	return fmt.Sprintf("%s (gem)", c.raw)
+}
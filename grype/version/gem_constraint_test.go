@@ -0,0 +1,79 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionGemConstraint(t *testing.T) {
+	tests := []testCase{
+		// empty values
+		{version: "2.3.1", constraint: "", satisfied: true},
+		// typical cases
+		{version: "2.3.1", constraint: "< 2.0.0", satisfied: false},
+		{version: "1.3.1", constraint: ">= 1.0.0, < 2.0.0", satisfied: true},
+		{version: "0.6.0", constraint: "> 0.1.0, < 0.5.0 || > 1.0.0, < 2.0.0", satisfied: false},
+		// multi-segment versions
+		{version: "4.2.11.1", constraint: "< 4.2.11.2", satisfied: true},
+		{version: "4.2.11.1", constraint: "> 4.2.11", satisfied: true},
+		{version: "1.0.0", constraint: "= 1", satisfied: true},
+		// pre-release versions
+		{version: "1.0.0.pre", constraint: "< 1.0.0", satisfied: true},
+		{version: "6.0.0.rc1", constraint: "< 6.0.0", satisfied: true},
+		{version: "6.0.0.rc1", constraint: "> 6.0.0.beta3", satisfied: true},
+		{version: "6.0.0.rc2", constraint: "> 6.0.0.rc1", satisfied: true},
+		{version: "6.0.0.rc.2", constraint: "= 6.0.0.rc2", satisfied: true},
+		{version: "1.0.a", constraint: "= 1.a", satisfied: true},
+		{version: "1.0-beta", constraint: "= 1.0.pre.beta", satisfied: true},
+		{version: "5.2.4.3", constraint: ">= 5.2.4.rc1, < 5.2.4.3", satisfied: false},
+		// pessimistic constraints
+		{version: "1.2.9", constraint: "~> 1.2.3", satisfied: true},
+		{version: "1.3.0", constraint: "~> 1.2.3", satisfied: false},
+		{version: "1.3.0.pre", constraint: "~> 1.2.3", satisfied: true},
+		{version: "1.9", constraint: "~> 1.2", satisfied: true},
+		{version: "2.0", constraint: "~> 1.2", satisfied: false},
+		{version: "2.0.1", constraint: "~> 2.0.0.pre1", satisfied: true},
+		{version: "5.2.4.2", constraint: "~> 5.2.4, >= 5.2.4.3 || >= 6.0.3.1", satisfied: false},
+		{version: "5.2.4.3", constraint: "~> 5.2.4, >= 5.2.4.3 || >= 6.0.3.1", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newGemConstraint(test.constraint)
+			assert.NoError(t, err, "unexpected error from newGemConstraint: %v", err)
+
+			test.assertVersionConstraint(t, GemFormat, constraint)
+		})
+	}
+}
+
+func Test_bumpGemVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{version: "1.2.3", expected: "1.3"},
+		{version: "1.2", expected: "2"},
+		{version: "1", expected: "2"},
+		{version: "2.0.0.pre1", expected: "2.1"},
+		{version: "5.2.4.3", expected: "5.2.5"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			actual, err := bumpGemVersion(test.version)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestNewGemVersion_Invalid(t *testing.T) {
+	for _, raw := range []string{"", "a.b.c", "1..2", ">= 1.0"} {
+		t.Run(raw, func(t *testing.T) {
+			_, err := newGemVersion(raw)
+			assert.Error(t, err)
+		})
+	}
+}
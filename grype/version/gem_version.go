@@ -0,0 +1,151 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// gemVersionPattern matches valid Gem::Version strings (e.g. "1.2.3", "1.0.0.pre1", "2.0.0.rc.2", or "1.0-beta").
+// See https://github.com/rubygems/rubygems/blob/master/lib/rubygems/version.rb
+var gemVersionPattern = regexp.MustCompile(`^[0-9]+(\.[0-9a-zA-Z]+)*(-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+// gemSegmentPattern splits a version into numeric and alphabetic segments (e.g. "1.0.0.pre1" becomes
+// [1, 0, 0, "pre", 1])
+var gemSegmentPattern = regexp.MustCompile(`[0-9]+|[a-zA-Z]+`)
+
+// gemSegment is either a numeric segment (number) or a pre-release segment (str)
+type gemSegment struct {
+	number int
+	str    string
+}
+
+func (s gemSegment) isString() bool {
+	return s.str != ""
+}
+
+type gemVersion struct {
+	segments []gemSegment
+}
+
+func newGemVersion(raw string) (*gemVersion, error) {
+	raw = strings.TrimSpace(raw)
+	if !gemVersionPattern.MatchString(raw) {
+		return nil, fmt.Errorf("unable to parse gem version: %q", raw)
+	}
+
+	// rubygems treats a "-" as the start of a pre-release (e.g. "1.0-beta" is equivalent to "1.0.pre.beta")
+	raw = strings.ReplaceAll(raw, "-", ".pre.")
+
+	var segments []gemSegment
+	for _, part := range gemSegmentPattern.FindAllString(raw, -1) {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			segments = append(segments, gemSegment{str: part})
+			continue
+		}
+		segments = append(segments, gemSegment{number: value})
+	}
+
+	return &gemVersion{
+		segments: canonicalGemSegments(segments),
+	}, nil
+}
+
+// canonicalGemSegments removes trailing zeros from both the release segments and the pre-release segments, such that
+// "1.0.0" is equivalent to "1" and "1.0.a" is equivalent to "1.a" (as with Gem::Version#canonical_segments).
+func canonicalGemSegments(segments []gemSegment) []gemSegment {
+	stringStart := len(segments)
+	for i, s := range segments {
+		if s.isString() {
+			stringStart = i
+			break
+		}
+	}
+
+	trimZeros := func(s []gemSegment) []gemSegment {
+		end := len(s)
+		for end > 0 && !s[end-1].isString() && s[end-1].number == 0 {
+			end--
+		}
+		return s[:end]
+	}
+
+	var canonical []gemSegment
+	canonical = append(canonical, trimZeros(segments[:stringStart])...)
+	return append(canonical, trimZeros(segments[stringStart:])...)
+}
+
+func (v *gemVersion) Compare(other *Version) (int, error) {
+	if other.Format != GemFormat {
+		return -1, fmt.Errorf("unable to compare gem version to given format: %s", other.Format)
+	}
+	if other.rich.gemVer == nil {
+		return -1, fmt.Errorf("given empty gemVersion object")
+	}
+
+	return other.rich.gemVer.compare(v), nil
+}
+
+// compare returns 0 if v == other, 1 if v > other, and -1 if v < other. Missing segments are treated as zero, and any
+// pre-release (string) segment is lower than a numeric segment.
+func (v *gemVersion) compare(other *gemVersion) int {
+	length := len(v.segments)
+	if len(other.segments) > length {
+		length = len(other.segments)
+	}
+
+	for i := 0; i < length; i++ {
+		lhs, rhs := gemSegmentOrZero(v.segments, i), gemSegmentOrZero(other.segments, i)
+		switch {
+		case lhs == rhs:
+			continue
+		case lhs.isString() && !rhs.isString():
+			return -1
+		case !lhs.isString() && rhs.isString():
+			return 1
+		case lhs.isString():
+			return strings.Compare(lhs.str, rhs.str)
+		default:
+			return compareInts(lhs.number, rhs.number)
+		}
+	}
+	return 0
+}
+
+func gemSegmentOrZero(segments []gemSegment, i int) gemSegment {
+	if i < len(segments) {
+		return segments[i]
+	}
+	return gemSegment{}
+}
+
+// bumpGemVersion returns the upper bound of a pessimistic ("~>") constraint for the given version, which is the
+// version with any pre-release segments and the last release segment removed, and then incremented
+// (e.g. "1.2.3" becomes "1.3" and "2.0.0.pre1" becomes "2.1"), as with Gem::Version#bump.
+func bumpGemVersion(raw string) (string, error) {
+	if !gemVersionPattern.MatchString(raw) {
+		return "", fmt.Errorf("unable to parse gem version: %q", raw)
+	}
+
+	var numbers []int
+	for _, part := range gemSegmentPattern.FindAllString(strings.ReplaceAll(raw, "-", ".pre."), -1) {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			break
+		}
+		numbers = append(numbers, value)
+	}
+
+	if len(numbers) > 1 {
+		numbers = numbers[:len(numbers)-1]
+	}
+	numbers[len(numbers)-1]++
+
+	parts := make([]string, len(numbers))
+	for i, n := range numbers {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, "."), nil
+}
@@ -0,0 +1,69 @@
+package version
+
+import (
+	"fmt"
+)
+
+type golangConstraint struct {
+	raw        string
+	expression constraintExpression
+}
+
+func newGolangConstraint(raw string) (golangConstraint, error) {
+	if raw == "" {
+		// an empty constraint is always satisfied
+		return golangConstraint{}, nil
+	}
+
+	constraints, err := newConstraintExpression(raw, newGolangComparator)
+	if err != nil {
+		return golangConstraint{}, fmt.Errorf("unable to parse golang constraint phrase: %w", err)
+	}
+
+	return golangConstraint{
+		raw:        raw,
+		expression: constraints,
+	}, nil
+}
+
+func newGolangComparator(unit constraintUnit) (Comparator, error) {
+	ver, err := newGolangVersion(unit.version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse constraint version (%s): %w", unit.version, err)
+	}
+	return ver, nil
+}
+
+func (c golangConstraint) supported(format Format) bool {
+	return format == GolangFormat || format == SemanticFormat
+}
+
+func (c golangConstraint) Satisfied(version *Version) (bool, error) {
+	if c.raw == "" && version != nil {
+		// an empty constraint is always satisfied
+		return true, nil
+	} else if version == nil {
+		if c.raw != "" {
+			// a non-empty constraint with no version given should always fail
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if !c.supported(version.Format) {
+		return false, fmt.Errorf("(golang) unsupported format: %s", version.Format)
+	}
+
+	if version.rich.semVer == nil {
+		return false, fmt.Errorf("no rich golang version given: %+v", version)
+	}
+
+	return c.expression.satisfied(version)
+}
+
+func (c golangConstraint) String() string {
+	if c.raw == "" {
+		return "none (go)"
+	}
+	return fmt.Sprintf("%s (go)", c.raw)
+}
@@ -0,0 +1,80 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionGolangConstraint(t *testing.T) {
+	tests := []testCase{
+		// empty values
+		{version: "v2.3.1", constraint: "", satisfied: true},
+		// v-prefixed module versions
+		{version: "v2.3.1", constraint: "< 2.0.0", satisfied: false},
+		{version: "v1.3.1", constraint: "> 1.0.0, < 2.0.0", satisfied: true},
+		{version: "v1.3.1", constraint: "> v1.0.0, < v2.0.0", satisfied: true},
+		{version: "v2.0.0", constraint: "> 1.0.0, <= 2.0.0", satisfied: true},
+		{version: "v0.6.0", constraint: "> 0.1.0, < 0.5.0 || > 1.0.0, < 2.0.0", satisfied: false},
+		// incompatible module versions
+		{version: "v17.12.0+incompatible", constraint: "< 18.0.0", satisfied: true},
+		{version: "v18.0.0+incompatible", constraint: "< 18.0.0", satisfied: false},
+		// pseudo-versions
+		{version: "v0.0.0-20210226172049-e18ecbb05110", constraint: "< 0.0.0-20210302000000-000000000000", satisfied: true},
+		{version: "v0.0.0-20210226172049-e18ecbb05110", constraint: "< 0.0.0-20210101000000-000000000000", satisfied: false},
+		{version: "v0.0.0-20210226172049-e18ecbb05110", constraint: "< 0.1.0", satisfied: true},
+		{version: "v1.2.4-0.20191109021931-daa7c04131f5", constraint: "< 1.2.4", satisfied: true},
+		{version: "v1.2.4-0.20191109021931-daa7c04131f5", constraint: "< 1.2.3", satisfied: false},
+		// go toolchain versions
+		{version: "go1.17.2", constraint: "< 1.17.3", satisfied: true},
+		{version: "go1.17.2", constraint: "< 1.17.2", satisfied: false},
+		{version: "go1.17", constraint: "< 1.17.1", satisfied: true},
+		{version: "go1.18rc1", constraint: "< 1.18.0", satisfied: true},
+		{version: "go1.18beta2", constraint: "< 1.18.0-rc1", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newGolangConstraint(test.constraint)
+			assert.NoError(t, err, "unexpected error from newGolangConstraint: %v", err)
+
+			test.assertVersionConstraint(t, GolangFormat, constraint)
+		})
+	}
+}
+
+func TestVersionSemanticConstraint_GolangVersion(t *testing.T) {
+	tests := []testCase{
+		{version: "v1.3.1", constraint: "> 1.0.0, < 2.0.0", satisfied: true},
+		{version: "v2.3.1", constraint: "> 1.0.0, < 2.0.0", satisfied: false},
+		{version: "go1.16.9", constraint: "< 1.16.10", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newSemanticConstraint(test.constraint)
+			assert.NoError(t, err, "unexpected error from newSemanticConstraint: %v", err)
+
+			test.assertVersionConstraint(t, GolangFormat, constraint)
+		})
+	}
+}
+
+func Test_normalizeGolangVersion(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{raw: "v1.2.3", expected: "1.2.3"},
+		{raw: "v2.0.0+incompatible", expected: "2.0.0"},
+		{raw: "v0.0.0-20210226172049-e18ecbb05110", expected: "0.0.0-20210226172049-e18ecbb05110"},
+		{raw: "go1.17.2", expected: "1.17.2"},
+		{raw: "go1.17", expected: "1.17.0"},
+		{raw: "go1.18rc1", expected: "1.18.0-rc1"},
+	}
+	for _, test := range tests {
+		t.Run(test.raw, func(t *testing.T) {
+			assert.Equal(t, test.expected, normalizeGolangVersion(test.raw))
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	hashiVer "github.com/anchore/go-version"
+)
+
+// goToolchainPattern matches go toolchain versions as embedded in compiled binaries (e.g. "go1.17.2" or "go1.18rc1")
+var goToolchainPattern = regexp.MustCompile(`^go(?P<major>\d+)(\.(?P<minor>\d+))?(\.(?P<patch>\d+))?(?P<prerelease>(beta|rc)\d+)?$`)
+
+// golangVersion represents a go module version (e.g. "v1.2.3", "v2.0.0+incompatible", or a pseudo-version such as
+// "v0.0.0-20210226172049-e18ecbb05110") or a go toolchain version (e.g. "go1.17.2").
+type golangVersion struct {
+	raw    string
+	verObj *hashiVer.Version
+}
+
+func newGolangVersion(raw string) (*golangVersion, error) {
+	verObj, err := hashiVer.NewVersion(normalizeGolangVersion(raw))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create golang version obj: %w", err)
+	}
+	return &golangVersion{
+		raw:    raw,
+		verObj: verObj,
+	}, nil
+}
+
+// normalizeGolangVersion converts go specific version strings into a semver-compatible form. Note that pseudo-versions
+// are already valid semver (the timestamp and commit are captured as a pre-release), so only the "v" prefix and the
+// "+incompatible" suffix need to be removed.
+func normalizeGolangVersion(raw string) string {
+	raw = strings.TrimSpace(raw)
+
+	if match := goToolchainPattern.FindStringSubmatch(raw); match != nil {
+		groups := make(map[string]string)
+		for i, name := range goToolchainPattern.SubexpNames() {
+			if name != "" {
+				groups[name] = match[i]
+			}
+		}
+		normalized := groups["major"]
+		for _, segment := range []string{"minor", "patch"} {
+			if groups[segment] == "" {
+				groups[segment] = "0"
+			}
+			normalized += "." + groups[segment]
+		}
+		if groups["prerelease"] != "" {
+			normalized += "-" + groups["prerelease"]
+		}
+		return normalized
+	}
+
+	raw = strings.TrimPrefix(raw, "v")
+	return strings.TrimSuffix(raw, "+incompatible")
+}
+
+func (v *golangVersion) Compare(other *Version) (int, error) {
+	if other.Format != GolangFormat && other.Format != SemanticFormat {
+		return -1, fmt.Errorf("unable to compare golang version to given format: %s", other.Format)
+	}
+	if other.rich.semVer == nil {
+		return -1, fmt.Errorf("given empty golang version object")
+	}
+
+	return other.rich.semVer.verObj.Compare(v.verObj), nil
+}
@@ -0,0 +1,69 @@
+package version
+
+import (
+	"fmt"
+)
+
+type haskellConstraint struct {
+	raw        string
+	expression constraintExpression
+}
+
+func newHaskellConstraint(raw string) (haskellConstraint, error) {
+	if raw == "" {
+		// an empty constraint is always satisfied
+		return haskellConstraint{}, nil
+	}
+
+	constraints, err := newConstraintExpression(raw, newHaskellComparator)
+	if err != nil {
+		return haskellConstraint{}, fmt.Errorf("unable to parse haskell constraint phrase: %w", err)
+	}
+
+	return haskellConstraint{
+		raw:        raw,
+		expression: constraints,
+	}, nil
+}
+
+func newHaskellComparator(unit constraintUnit) (Comparator, error) {
+	ver, err := newHaskellVersion(unit.version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse constraint version (%s): %w", unit.version, err)
+	}
+	return ver, nil
+}
+
+func (c haskellConstraint) supported(format Format) bool {
+	return format == HaskellFormat
+}
+
+func (c haskellConstraint) Satisfied(version *Version) (bool, error) {
+	if c.raw == "" && version != nil {
+		// an empty constraint is always satisfied
+		return true, nil
+	} else if version == nil {
+		if c.raw != "" {
+			// a non-empty constraint with no version given should always fail
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if !c.supported(version.Format) {
+		return false, fmt.Errorf("(haskell) unsupported format: %s", version.Format)
+	}
+
+	if version.rich.haskellVer == nil {
+		return false, fmt.Errorf("no rich haskell version given: %+v", version)
+	}
+
+	return c.expression.satisfied(version)
+}
+
+func (c haskellConstraint) String() string {
+	if c.raw == "" {
+		return "none (haskell)"
+	}
+	return fmt.Sprintf("%s (haskell)", c.raw)
+}
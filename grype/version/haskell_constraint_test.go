@@ -0,0 +1,41 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionHaskellConstraint(t *testing.T) {
+	tests := []testCase{
+		// empty values
+		{version: "2.3.1", constraint: "", satisfied: true},
+		// typical cases
+		{version: "2.3.1", constraint: "< 2.0.0", satisfied: false},
+		{version: "1.3.1", constraint: ">= 1.0.0, < 2.0.0", satisfied: true},
+		{version: "0.6.0", constraint: "> 0.1.0, < 0.5.0 || > 1.0.0, < 2.0.0", satisfied: false},
+		{version: "2.0.3.0", constraint: "< 2.0.3.1", satisfied: true},
+		{version: "0.10.1", constraint: "> 0.9.99", satisfied: true},
+		// a longer version is greater than its prefix
+		{version: "2.0.3.0", constraint: "> 2.0.3", satisfied: true},
+		{version: "2.0", constraint: "< 2.0.0", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newHaskellConstraint(test.constraint)
+			assert.NoError(t, err, "unexpected error from newHaskellConstraint: %v", err)
+
+			test.assertVersionConstraint(t, HaskellFormat, constraint)
+		})
+	}
+}
+
+func TestNewHaskellVersion_Invalid(t *testing.T) {
+	for _, raw := range []string{"", "1.2-3", "1.2.a", "1..2"} {
+		t.Run(raw, func(t *testing.T) {
+			_, err := newHaskellVersion(raw)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// haskellVersionPattern matches package versions that follow the haskell package versioning policy (PVP), which are
+// sequences of non-negative integers separated by "." (e.g. "2.0.3.0"). See https://pvp.haskell.org/
+var haskellVersionPattern = regexp.MustCompile(`^\d+(\.\d+)*$`)
+
+type haskellVersion struct {
+	segments []int
+}
+
+func newHaskellVersion(raw string) (*haskellVersion, error) {
+	raw = strings.TrimSpace(raw)
+	if !haskellVersionPattern.MatchString(raw) {
+		return nil, fmt.Errorf("unable to parse haskell version: %q", raw)
+	}
+
+	var ver haskellVersion
+	for _, part := range strings.Split(raw, ".") {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse haskell version segment %q: %w", part, err)
+		}
+		ver.segments = append(ver.segments, value)
+	}
+	return &ver, nil
+}
+
+func (v *haskellVersion) Compare(other *Version) (int, error) {
+	if other.Format != HaskellFormat {
+		return -1, fmt.Errorf("unable to compare haskell version to given format: %s", other.Format)
+	}
+	if other.rich.haskellVer == nil {
+		return -1, fmt.Errorf("given empty haskellVersion object")
+	}
+
+	return other.rich.haskellVer.compare(v), nil
+}
+
+// compare returns 0 if v == other, 1 if v > other, and -1 if v < other. As with Data.Version, a version is greater
+// than any version it is a prefix of (e.g. "1.2.0" is greater than "1.2").
+func (v *haskellVersion) compare(other *haskellVersion) int {
+	return compareIntSegments(v.segments, other.segments)
+}
@@ -0,0 +1,73 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// hexRequirementReplacer translates the boolean keywords and equality operator within elixir version requirements
+// (e.g. ">= 1.0.0 and < 1.2.3 or == 2.0.0") into the equivalent semantic version constraint syntax. Note that the
+// pessimistic operator (e.g. "~> 2.1") has the same meaning in both.
+// See https://hexdocs.pm/elixir/Version.html#module-requirements
+var hexRequirementReplacer = strings.NewReplacer(" and ", ", ", " or ", " || ", "==", "=")
+
+// hexKeywordPattern matches any boolean keyword that remains after translation (e.g. a keyword not surrounded by spaces)
+var hexKeywordPattern = regexp.MustCompile(`\b(and|or)\b`)
+
+type hexConstraint struct {
+	raw      string
+	semantic semanticConstraint
+}
+
+func newHexConstraint(raw string) (hexConstraint, error) {
+	if raw == "" {
+		// an empty constraint is always satisfied
+		return hexConstraint{}, nil
+	}
+
+	translated := hexRequirementReplacer.Replace(strings.TrimSpace(raw))
+	if hexKeywordPattern.MatchString(translated) {
+		return hexConstraint{}, fmt.Errorf("unable to parse hex version requirement: %q", raw)
+	}
+
+	semantic, err := newSemanticConstraint(translated)
+	if err != nil {
+		return hexConstraint{}, fmt.Errorf("unable to parse hex version requirement %q: %w", raw, err)
+	}
+
+	return hexConstraint{
+		raw:      raw,
+		semantic: semantic,
+	}, nil
+}
+
+func (c hexConstraint) supported(format Format) bool {
+	return format == HexFormat || format == SemanticFormat
+}
+
+func (c hexConstraint) Satisfied(version *Version) (bool, error) {
+	if c.raw == "" && version != nil {
+		// an empty constraint is always satisfied
+		return true, nil
+	} else if version == nil {
+		if c.raw != "" {
+			// a non-empty constraint with no version given should always fail
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if !c.supported(version.Format) {
+		return false, fmt.Errorf("(hex) unsupported format: %s", version.Format)
+	}
+
+	return c.semantic.Satisfied(version)
+}
+
+func (c hexConstraint) String() string {
+	if c.raw == "" {
+		return "none (hex)"
+	}
+	return fmt.Sprintf("%s (hex)", c.raw)
+}
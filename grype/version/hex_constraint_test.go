@@ -0,0 +1,47 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionHexConstraint(t *testing.T) {
+	tests := []testCase{
+		// empty values
+		{version: "2.3.1", constraint: "", satisfied: true},
+		// typical cases
+		{version: "2.3.1", constraint: "< 2.0.0", satisfied: false},
+		{version: "1.3.1", constraint: ">= 1.0.0 and < 2.0.0", satisfied: true},
+		{version: "0.6.0", constraint: "> 0.1.0 and < 0.5.0 or > 1.0.0 and < 2.0.0", satisfied: false},
+		{version: "1.5.0", constraint: "> 0.1.0 and < 0.5.0 or > 1.0.0 and < 2.0.0", satisfied: true},
+		{version: "2.0.0", constraint: "== 2.0.0", satisfied: true},
+		{version: "2.0.1", constraint: "!= 2.0.0", satisfied: true},
+		// pessimistic requirements
+		{version: "2.9.0", constraint: "~> 2.0", satisfied: true},
+		{version: "3.0.0", constraint: "~> 2.0", satisfied: false},
+		{version: "2.1.9", constraint: "~> 2.1.2", satisfied: true},
+		{version: "2.2.0", constraint: "~> 2.1.2", satisfied: false},
+		{version: "2.1.1", constraint: "~> 2.1.2", satisfied: false},
+		// pre-release versions
+		{version: "1.0.0-rc.1", constraint: "< 1.0.0", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newHexConstraint(test.constraint)
+			assert.NoError(t, err, "unexpected error from newHexConstraint: %v", err)
+
+			test.assertVersionConstraint(t, HexFormat, constraint)
+		})
+	}
+}
+
+func TestNewHexConstraint_Invalid(t *testing.T) {
+	for _, raw := range []string{">= 1.0.0 and", "or < 1.0.0", ">> 1.0.0"} {
+		t.Run(raw, func(t *testing.T) {
+			_, err := newHexConstraint(raw)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionMavenConstraint(t *testing.T) {
+	tests := []testCase{
+		// empty values
+		{version: "2.3.1", constraint: "", satisfied: true},
+		// typical cases
+		{version: "2.17.0", constraint: "< 2.17.1", satisfied: true},
+		{version: "2.17.1", constraint: "< 2.17.1", satisfied: false},
+		{version: "2.3.1", constraint: ">= 2.0, < 2.17.1", satisfied: true},
+		{version: "0.6.0", constraint: "> 0.1.0, < 0.5.0 || > 1.0.0, < 2.0.0", satisfied: false},
+		// trailing zeros are insignificant
+		{version: "1.0.0", constraint: "= 1", satisfied: true},
+		{version: "1-0", constraint: "= 1.0", satisfied: true},
+		// qualifier ordering
+		{version: "2.15.0-rc1", constraint: "< 2.15.0", satisfied: true},
+		{version: "1.0-alpha-1", constraint: "< 1.0-beta-1", satisfied: true},
+		{version: "1.0-beta-1", constraint: "< 1.0-milestone-1", satisfied: true},
+		{version: "1.0-milestone-1", constraint: "< 1.0-rc-1", satisfied: true},
+		{version: "1.0-rc-1", constraint: "< 1.0-SNAPSHOT", satisfied: true},
+		{version: "1.0-SNAPSHOT", constraint: "< 1.0", satisfied: true},
+		{version: "1.0", constraint: "< 1.0-sp", satisfied: true},
+		{version: "1.0-sp", constraint: "< 1.0-whatever", satisfied: true},
+		{version: "1.0-whatever", constraint: "< 1.0.1", satisfied: true},
+		// qualifier aliases and shorthands
+		{version: "1.0a1", constraint: "= 1.0-alpha-1", satisfied: true},
+		{version: "1.0m2", constraint: "= 1.0-milestone-2", satisfied: true},
+		{version: "1.0.RELEASE", constraint: "= 1.0", satisfied: true},
+		{version: "1.0.Final", constraint: "= 1.0-ga", satisfied: true},
+		{version: "1.0-cr1", constraint: "= 1.0-rc1", satisfied: true},
+		// list and number transitions
+		{version: "1-1", constraint: "< 1.1", satisfied: true},
+		{version: "1-sp", constraint: "< 1-1", satisfied: true},
+		{version: "1.0.0.10", constraint: "> 1.0.0.9", satisfied: true},
+		{version: "2.9.10.8", constraint: "> 2.9.10", satisfied: true},
+		{version: "9.4.44.v20210927", constraint: "< 9.4.44.v20211011", satisfied: true},
+		{version: "12345678901234567890", constraint: "> 9", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newMavenConstraint(test.constraint)
+			assert.NoError(t, err, "unexpected error from newMavenConstraint: %v", err)
+
+			test.assertVersionConstraint(t, MavenFormat, constraint)
+		})
+	}
+}
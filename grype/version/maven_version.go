@@ -0,0 +1,242 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// mavenQualifiers are the well-known qualifiers in ascending order, where the release qualifier is empty (e.g.
+// "1.0-alpha" < "1.0-beta" < "1.0-milestone" < "1.0-rc" < "1.0-snapshot" < "1.0" < "1.0-sp").
+var mavenQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+// mavenQualifierAliases are alternative spellings of well-known qualifiers.
+var mavenQualifierAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+// mavenItem is a single parsed element of a maven version, compared as maven's ComparableVersion would.
+// See https://maven.apache.org/ref/3.8.4/maven-artifact/apidocs/org/apache/maven/artifact/versioning/ComparableVersion.html
+type mavenItem interface {
+	// compare returns 0 if the item is equal to other, 1 if greater, and -1 if less, where a nil other item is the
+	// "null" item (e.g. when one version has fewer items than the other).
+	compare(other mavenItem) int
+	isNull() bool
+}
+
+type mavenIntItem string
+
+type mavenStringItem string
+
+type mavenListItem []mavenItem
+
+type mavenVersion struct {
+	items *mavenListItem
+}
+
+func newMavenVersion(raw string) (*mavenVersion, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, fmt.Errorf("unable to parse empty maven version")
+	}
+	return &mavenVersion{items: parseMavenVersion(strings.ToLower(raw))}, nil
+}
+
+// parseMavenVersion splits the given version into items on "." and "-" separators as well as transitions between
+// digits and characters, where "-" and transitions start a new sub-list.
+func parseMavenVersion(raw string) *mavenListItem {
+	root := &mavenListItem{}
+	list := root
+	stack := []*mavenListItem{root}
+
+	pushList := func() {
+		sub := &mavenListItem{}
+		*list = append(*list, sub)
+		list = sub
+		stack = append(stack, sub)
+	}
+
+	isDigit := false
+	start := 0
+	for i, c := range raw {
+		switch {
+		case c == '.' || c == '-':
+			if i == start {
+				*list = append(*list, mavenIntItem("0"))
+			} else {
+				*list = append(*list, newMavenItem(isDigit, raw[start:i], false))
+			}
+			start = i + 1
+			if c == '-' {
+				pushList()
+			}
+		case c >= '0' && c <= '9':
+			if !isDigit && i > start {
+				// a qualifier directly followed by a digit (e.g. "1.0alpha1") starts a new sub-list
+				*list = append(*list, newMavenItem(false, raw[start:i], true))
+				start = i
+				pushList()
+			}
+			isDigit = true
+		default:
+			if isDigit && i > start {
+				*list = append(*list, newMavenItem(true, raw[start:i], false))
+				start = i
+				pushList()
+			}
+			isDigit = false
+		}
+	}
+	if len(raw) > start {
+		*list = append(*list, newMavenItem(isDigit, raw[start:], false))
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+	return root
+}
+
+func newMavenItem(isDigit bool, value string, followedByDigit bool) mavenItem {
+	if isDigit {
+		value = strings.TrimLeft(value, "0")
+		if value == "" {
+			value = "0"
+		}
+		return mavenIntItem(value)
+	}
+
+	if followedByDigit && len(value) == 1 {
+		// shorthand qualifiers are only recognized when followed by a number (e.g. "1.0a1" but not "1.0a")
+		switch value {
+		case "a":
+			value = "alpha"
+		case "b":
+			value = "beta"
+		case "m":
+			value = "milestone"
+		}
+	}
+	if alias, ok := mavenQualifierAliases[value]; ok {
+		value = alias
+	}
+	return mavenStringItem(value)
+}
+
+func (v *mavenVersion) Compare(other *Version) (int, error) {
+	if other.Format != MavenFormat {
+		return -1, fmt.Errorf("unable to compare maven version to given format: %s", other.Format)
+	}
+	if other.rich.mavenVer == nil {
+		return -1, fmt.Errorf("given empty mavenVersion object")
+	}
+
+	return other.rich.mavenVer.compare(v), nil
+}
+
+// compare returns 0 if v == other, 1 if v > other, and -1 if v < other.
+func (v *mavenVersion) compare(other *mavenVersion) int {
+	return v.items.compare(other.items)
+}
+
+func (i mavenIntItem) isNull() bool {
+	return i == "0"
+}
+
+func (i mavenIntItem) compare(other mavenItem) int {
+	switch o := other.(type) {
+	case nil:
+		if i.isNull() {
+			return 0
+		}
+		return 1
+	case mavenIntItem:
+		// values are without leading zeros, so longer values are larger (avoiding any overflow)
+		if result := compareInts(len(i), len(o)); result != 0 {
+			return result
+		}
+		return strings.Compare(string(i), string(o))
+	default:
+		// a number is greater than any qualifier or sub-list (e.g. "1.1" > "1-sp" and "1.1" > "1-1")
+		return 1
+	}
+}
+
+func (s mavenStringItem) isNull() bool {
+	return s == ""
+}
+
+// comparable returns a key for the qualifier that orders well-known qualifiers first (as listed) followed by all
+// unknown qualifiers in lexical order.
+func (s mavenStringItem) comparable() string {
+	for i, q := range mavenQualifiers {
+		if string(s) == q {
+			return fmt.Sprintf("%d", i)
+		}
+	}
+	return fmt.Sprintf("%d-%s", len(mavenQualifiers), s)
+}
+
+func (s mavenStringItem) compare(other mavenItem) int {
+	switch o := other.(type) {
+	case nil:
+		// compare against the release qualifier
+		return strings.Compare(s.comparable(), mavenStringItem("").comparable())
+	case mavenStringItem:
+		return strings.Compare(s.comparable(), o.comparable())
+	default:
+		// a qualifier is less than any number or sub-list (e.g. "1-rc" < "1-1")
+		return -1
+	}
+}
+
+func (l *mavenListItem) isNull() bool {
+	return len(*l) == 0
+}
+
+// normalize removes trailing null items (e.g. "1.0.0" becomes "1"), stopping at the first non-null, non-list item.
+func (l *mavenListItem) normalize() {
+	for i := len(*l) - 1; i >= 0; i-- {
+		item := (*l)[i]
+		if item.isNull() {
+			*l = append((*l)[:i], (*l)[i+1:]...)
+			continue
+		}
+		if _, ok := item.(*mavenListItem); !ok {
+			break
+		}
+	}
+}
+
+func (l *mavenListItem) compare(other mavenItem) int {
+	switch o := other.(type) {
+	case nil:
+		if len(*l) == 0 {
+			return 0
+		}
+		return (*l)[0].compare(nil)
+	case mavenIntItem:
+		return -1
+	case mavenStringItem:
+		return 1
+	case *mavenListItem:
+		for i := 0; i < len(*l) || i < len(*o); i++ {
+			var result int
+			switch {
+			case i >= len(*l):
+				result = -(*o)[i].compare(nil)
+			case i >= len(*o):
+				result = (*l)[i].compare(nil)
+			default:
+				result = (*l)[i].compare((*o)[i])
+			}
+			if result != 0 {
+				return result
+			}
+		}
+		return 0
+	}
+	return 0
+}
@@ -0,0 +1,255 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// npmHyphenRangePattern matches hyphen ranges (e.g. "1.2.3 - 2.3.4")
+var npmHyphenRangePattern = regexp.MustCompile(`^(\S+)\s+-\s+(\S+)$`)
+
+// npmRangeUnitPattern matches a single operator and (partial) version pair within a space or comma separated range
+var npmRangeUnitPattern = regexp.MustCompile(`(<=|>=|<|>|=|~>|~|\^)?\s*([^\s,<>=~^]+)`)
+
+// npmPartialPattern matches versions that may be partial or contain wildcards (e.g. "1", "1.2.x", or "*")
+var npmPartialPattern = regexp.MustCompile(`^[v=]*(\d+|[xX*])(?:\.(\d+|[xX*]))?(?:\.(\d+|[xX*]))?(-?[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?(\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+
+type npmConstraint struct {
+	raw        string
+	expression constraintExpression
+}
+
+func newNpmConstraint(raw string) (npmConstraint, error) {
+	if raw == "" {
+		// an empty constraint is always satisfied
+		return npmConstraint{}, nil
+	}
+
+	expanded, err := expandNpmRange(raw)
+	if err != nil {
+		return npmConstraint{}, err
+	}
+
+	constraints, err := newConstraintExpression(expanded, newNpmComparator)
+	if err != nil {
+		return npmConstraint{}, fmt.Errorf("unable to parse npm constraint phrase: %w", err)
+	}
+
+	return npmConstraint{
+		raw:        raw,
+		expression: constraints,
+	}, nil
+}
+
+// npmPartial is a version within a range, where only the first n segments were given (the rest being wildcards).
+type npmPartial struct {
+	segments   [3]int
+	n          int
+	prerelease string
+}
+
+func parseNpmPartial(raw string) (npmPartial, error) {
+	var p npmPartial
+	match := npmPartialPattern.FindStringSubmatch(raw)
+	if match == nil {
+		return p, fmt.Errorf("invalid npm range version: %q", raw)
+	}
+	for i := 0; i < 3; i++ {
+		value := match[i+1]
+		if value == "" || value == "x" || value == "X" || value == "*" {
+			break
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return p, fmt.Errorf("invalid npm range version %q: %w", raw, err)
+		}
+		p.segments[i] = n
+		p.n++
+	}
+	if p.n == 3 {
+		p.prerelease = strings.TrimPrefix(match[4], "-")
+	}
+	return p, nil
+}
+
+// String returns the partial version with any wildcards replaced with zero (e.g. "1.x" becomes "1.0.0").
+func (p npmPartial) String() string {
+	ver := fmt.Sprintf("%d.%d.%d", p.segments[0], p.segments[1], p.segments[2])
+	if p.prerelease != "" {
+		ver += "-" + p.prerelease
+	}
+	return ver
+}
+
+// next returns the lowest version (including prereleases) beyond all versions matched by the partial version at the
+// given segment (e.g. "1.2.x" at the minor segment becomes "1.3.0-0").
+func (p npmPartial) next(idx int) string {
+	next := [3]int{}
+	copy(next[:idx], p.segments[:idx])
+	next[idx] = p.segments[idx] + 1
+	return fmt.Sprintf("%d.%d.%d-0", next[0], next[1], next[2])
+}
+
+// upperBound returns the explicit upper bound implied by a partial version (e.g. "1.2" is "< 1.3.0-0").
+func (p npmPartial) upperBound() string {
+	return "< " + p.next(p.n-1)
+}
+
+// expandNpmRange rewrites a node-semver range (including hyphen, x-, tilde, and caret ranges) into explicit
+// comparisons (e.g. "^1.2.3" becomes ">= 1.2.3, < 2.0.0-0"). Note that unlike node-semver, a prerelease version is
+// compared by precedence against all comparators (as with the includePrerelease option), since a prerelease within a
+// vulnerable range is just as vulnerable.
+// See https://github.com/npm/node-semver#ranges
+func expandNpmRange(phrase string) (string, error) {
+	orParts := strings.Split(phrase, "||")
+	for orIdx, orPart := range orParts {
+		orPart = strings.TrimSpace(orPart)
+
+		var andParts []string
+		if match := npmHyphenRangePattern.FindStringSubmatch(orPart); match != nil {
+			expanded, err := expandNpmHyphenRange(match[1], match[2])
+			if err != nil {
+				return "", err
+			}
+			andParts = append(andParts, expanded...)
+		} else {
+			for _, unit := range npmRangeUnitPattern.FindAllStringSubmatch(orPart, -1) {
+				expanded, err := expandNpmRangeUnit(unit[1], unit[2])
+				if err != nil {
+					return "", err
+				}
+				andParts = append(andParts, expanded...)
+			}
+		}
+
+		if len(andParts) == 0 {
+			// an empty range matches any version
+			andParts = []string{">= 0.0.0-0"}
+		}
+		orParts[orIdx] = strings.Join(andParts, ", ")
+	}
+	return strings.Join(orParts, " || "), nil
+}
+
+func expandNpmHyphenRange(lowerRaw, upperRaw string) ([]string, error) {
+	lower, err := parseNpmPartial(lowerRaw)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := parseNpmPartial(upperRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	expanded := []string{">= " + lower.String()}
+	switch upper.n {
+	case 0:
+		// no upper bound
+	case 3:
+		expanded = append(expanded, "<= "+upper.String())
+	default:
+		expanded = append(expanded, upper.upperBound())
+	}
+	return expanded, nil
+}
+
+// nolint:funlen
+func expandNpmRangeUnit(op, raw string) ([]string, error) {
+	p, err := parseNpmPartial(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.n == 0 {
+		switch op {
+		case "<", ">":
+			// nothing is less or greater than any version
+			return []string{"< 0.0.0-0"}, nil
+		default:
+			return []string{">= 0.0.0-0"}, nil
+		}
+	}
+
+	switch op {
+	case "", "=":
+		if p.n == 3 {
+			return []string{"= " + p.String()}, nil
+		}
+		return []string{">= " + p.String(), p.upperBound()}, nil
+	case "~", "~>":
+		idx := 1
+		if p.n == 1 {
+			idx = 0
+		}
+		return []string{">= " + p.String(), "< " + p.next(idx)}, nil
+	case "^":
+		// the upper bound increments the left-most non-zero segment (or the last given segment if all are zero)
+		idx := 0
+		for idx < p.n-1 && p.segments[idx] == 0 {
+			idx++
+		}
+		return []string{">= " + p.String(), "< " + p.next(idx)}, nil
+	case ">":
+		if p.n == 3 {
+			return []string{"> " + p.String()}, nil
+		}
+		return []string{">= " + strings.TrimSuffix(p.next(p.n-1), "-0")}, nil
+	case ">=":
+		return []string{">= " + p.String()}, nil
+	case "<":
+		if p.n == 3 {
+			return []string{"< " + p.String()}, nil
+		}
+		return []string{"< " + p.String() + "-0"}, nil
+	case "<=":
+		if p.n == 3 {
+			return []string{"<= " + p.String()}, nil
+		}
+		return []string{p.upperBound()}, nil
+	}
+	return nil, fmt.Errorf("unknown npm range operator: %q", op)
+}
+
+func newNpmComparator(unit constraintUnit) (Comparator, error) {
+	ver, err := newNpmVersion(unit.version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse constraint version (%s): %w", unit.version, err)
+	}
+	return ver, nil
+}
+
+func (c npmConstraint) supported(format Format) bool {
+	return format == NpmFormat
+}
+
+func (c npmConstraint) Satisfied(version *Version) (bool, error) {
+	if c.raw == "" && version != nil {
+		// an empty constraint is always satisfied
+		return true, nil
+	} else if version == nil {
+		if c.raw != "" {
+			// a non-empty constraint with no version given should always fail
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if !c.supported(version.Format) {
+		return false, fmt.Errorf("(npm) unsupported format: %s", version.Format)
+	}
+
+	if version.rich.npmVer == nil {
+		return false, fmt.Errorf("no rich npm version given: %+v", version)
+	}
+
+	return c.expression.satisfied(version)
+}
+
+func (c npmConstraint) String() string {
+	if c.raw == "" {
+		return "none (npm)"
+	}
+	return fmt.Sprintf("%s (npm)", c.raw)
+}
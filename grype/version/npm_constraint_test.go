@@ -0,0 +1,96 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionNpmConstraint(t *testing.T) {
+	tests := []testCase{
+		// empty values
+		{version: "2.3.1", constraint: "", satisfied: true},
+		{version: "2.3.1", constraint: "*", satisfied: true},
+		// typical (advisory) cases
+		{version: "4.17.20", constraint: "< 4.17.21", satisfied: true},
+		{version: "4.17.21", constraint: "< 4.17.21", satisfied: false},
+		{version: "1.3.1", constraint: ">= 1.0.0, < 2.0.0", satisfied: true},
+		{version: "1.3.1", constraint: ">=1.0.0 <2.0.0", satisfied: true},
+		{version: "0.6.0", constraint: "> 0.1.0, < 0.5.0 || > 1.0.0, < 2.0.0", satisfied: false},
+		{version: "v1.2.3", constraint: "=1.2.3", satisfied: true},
+		// caret ranges
+		{version: "1.9.9", constraint: "^1.2.3", satisfied: true},
+		{version: "2.0.0", constraint: "^1.2.3", satisfied: false},
+		{version: "2.0.0-rc.1", constraint: "^1.2.3", satisfied: false},
+		{version: "0.2.9", constraint: "^0.2.3", satisfied: true},
+		{version: "0.3.0", constraint: "^0.2.3", satisfied: false},
+		{version: "0.0.3", constraint: "^0.0.3", satisfied: true},
+		{version: "0.0.4", constraint: "^0.0.3", satisfied: false},
+		{version: "0.0.9", constraint: "^0.0.x", satisfied: true},
+		{version: "0.1.0", constraint: "^0.0", satisfied: false},
+		{version: "0.9.0", constraint: "^0.x", satisfied: true},
+		{version: "1.5.0", constraint: "^1.2.x", satisfied: true},
+		// tilde ranges
+		{version: "1.2.9", constraint: "~1.2.3", satisfied: true},
+		{version: "1.3.0", constraint: "~1.2.3", satisfied: false},
+		{version: "1.9.0", constraint: "~1", satisfied: true},
+		{version: "0.2.4", constraint: "~0.2.3-beta.2", satisfied: true},
+		{version: "0.2.3-beta.4", constraint: "~0.2.3-beta.2", satisfied: true},
+		// x-ranges and partial versions
+		{version: "1.2.9", constraint: "1.2.x", satisfied: true},
+		{version: "1.3.0", constraint: "1.2", satisfied: false},
+		{version: "1.99.0", constraint: "1", satisfied: true},
+		{version: "1.2.9", constraint: "<=1.2", satisfied: true},
+		{version: "1.3.0", constraint: "<=1.2", satisfied: false},
+		{version: "1.1.9", constraint: "<1.2", satisfied: true},
+		{version: "1.2.0-alpha", constraint: "<1.2", satisfied: false},
+		{version: "1.2.9", constraint: ">1.2", satisfied: false},
+		{version: "1.3.0", constraint: ">1.2", satisfied: true},
+		{version: "1.0.0", constraint: ">*", satisfied: false},
+		// hyphen ranges
+		{version: "2.3.4", constraint: "1.2.3 - 2.3.4", satisfied: true},
+		{version: "2.3.5", constraint: "1.2.3 - 2.3.4", satisfied: false},
+		{version: "2.3.9", constraint: "1.2.3 - 2.3", satisfied: true},
+		{version: "2.4.0", constraint: "1.2.3 - 2.3", satisfied: false},
+		{version: "1.2.0", constraint: "1.2 - 2", satisfied: true},
+		// prerelease precedence
+		{version: "1.0.0-alpha", constraint: "< 1.0.0-alpha.1", satisfied: true},
+		{version: "1.0.0-alpha.beta", constraint: "< 1.0.0-beta", satisfied: true},
+		{version: "1.0.0-beta.2", constraint: "< 1.0.0-beta.11", satisfied: true},
+		{version: "1.0.0-rc.1", constraint: "< 1.0.0", satisfied: true},
+		{version: "1.5.0-beta", constraint: ">= 1.0.0, < 1.6.0", satisfied: true},
+		// build metadata is ignored
+		{version: "1.2.3+build.5", constraint: "= 1.2.3", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newNpmConstraint(test.constraint)
+			assert.NoError(t, err, "unexpected error from newNpmConstraint: %v", err)
+
+			test.assertVersionConstraint(t, NpmFormat, constraint)
+		})
+	}
+}
+
+func TestExpandNpmRange(t *testing.T) {
+	tests := []struct {
+		phrase   string
+		expected string
+	}{
+		{phrase: "^1.2.3", expected: ">= 1.2.3, < 2.0.0-0"},
+		{phrase: "^0.0.3", expected: ">= 0.0.3, < 0.0.4-0"},
+		{phrase: "~1.2", expected: ">= 1.2.0, < 1.3.0-0"},
+		{phrase: "1.x || >=2.5.0 || 5.0.0 - 7.2.3", expected: ">= 1.0.0, < 2.0.0-0 || >= 2.5.0 || >= 5.0.0, <= 7.2.3"},
+		{phrase: ">= 1.0.0, < 1.2.3", expected: ">= 1.0.0, < 1.2.3"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.phrase, func(t *testing.T) {
+			actual, err := expandNpmRange(test.phrase)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
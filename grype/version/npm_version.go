@@ -0,0 +1,65 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// npmVersionPattern matches (loose) node-semver versions, which may have a leading "v" or "=" (e.g. "v1.2.3-beta.1")
+// See https://github.com/npm/node-semver#versions
+var npmVersionPattern = regexp.MustCompile(`^[v=\s]*(\d+)\.(\d+)\.(\d+)(?:-?([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))?(?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?$`)
+
+type npmVersion struct {
+	major, minor, patch int
+	prerelease          []string
+}
+
+func newNpmVersion(raw string) (*npmVersion, error) {
+	match := npmVersionPattern.FindStringSubmatch(strings.TrimSpace(raw))
+	if match == nil {
+		return nil, fmt.Errorf("unable to parse npm version: %q", raw)
+	}
+
+	var ver npmVersion
+	for i, value := range []*int{&ver.major, &ver.minor, &ver.patch} {
+		n, err := strconv.Atoi(match[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse npm version %q: %w", raw, err)
+		}
+		*value = n
+	}
+	if match[4] != "" {
+		ver.prerelease = strings.Split(match[4], ".")
+	}
+	return &ver, nil
+}
+
+func (v *npmVersion) Compare(other *Version) (int, error) {
+	if other.Format != NpmFormat {
+		return -1, fmt.Errorf("unable to compare npm version to given format: %s", other.Format)
+	}
+	if other.rich.npmVer == nil {
+		return -1, fmt.Errorf("given empty npmVersion object")
+	}
+
+	return other.rich.npmVer.compare(v), nil
+}
+
+// compare returns 0 if v == other, 1 if v > other, and -1 if v < other, where a version with a prerelease has lower
+// precedence than the same version without one (build metadata is ignored).
+func (v *npmVersion) compare(other *npmVersion) int {
+	if result := compareIntSegments([]int{v.major, v.minor, v.patch}, []int{other.major, other.minor, other.patch}); result != 0 {
+		return result
+	}
+	switch {
+	case len(v.prerelease) == 0 && len(other.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(other.prerelease) == 0:
+		return -1
+	}
+	return comparePrereleaseIdentifiers(v.prerelease, other.prerelease)
+}
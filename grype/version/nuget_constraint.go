@@ -0,0 +1,69 @@
+package version
+
+import (
+	"fmt"
+)
+
+type nugetConstraint struct {
+	raw        string
+	expression constraintExpression
+}
+
+func newNugetConstraint(raw string) (nugetConstraint, error) {
+	if raw == "" {
+		// an empty constraint is always satisfied
+		return nugetConstraint{}, nil
+	}
+
+	constraints, err := newConstraintExpression(raw, newNugetComparator)
+	if err != nil {
+		return nugetConstraint{}, fmt.Errorf("unable to parse nuget constraint phrase: %w", err)
+	}
+
+	return nugetConstraint{
+		raw:        raw,
+		expression: constraints,
+	}, nil
+}
+
+func newNugetComparator(unit constraintUnit) (Comparator, error) {
+	ver, err := newNugetVersion(unit.version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse constraint version (%s): %w", unit.version, err)
+	}
+	return ver, nil
+}
+
+func (c nugetConstraint) supported(format Format) bool {
+	return format == NugetFormat
+}
+
+func (c nugetConstraint) Satisfied(version *Version) (bool, error) {
+	if c.raw == "" && version != nil {
+		// an empty constraint is always satisfied
+		return true, nil
+	} else if version == nil {
+		if c.raw != "" {
+			// a non-empty constraint with no version given should always fail
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if !c.supported(version.Format) {
+		return false, fmt.Errorf("(nuget) unsupported format: %s", version.Format)
+	}
+
+	if version.rich.nugetVer == nil {
+		return false, fmt.Errorf("no rich nuget version given: %+v", version)
+	}
+
+	return c.expression.satisfied(version)
+}
+
+func (c nugetConstraint) String() string {
+	if c.raw == "" {
+		return "none (nuget)"
+	}
+	return fmt.Sprintf("%s (nuget)", c.raw)
+}
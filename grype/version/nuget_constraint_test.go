@@ -0,0 +1,50 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionNugetConstraint(t *testing.T) {
+	tests := []testCase{
+		// empty values
+		{version: "2.3.1", constraint: "", satisfied: true},
+		// typical cases
+		{version: "2.3.1", constraint: "< 2.0.0", satisfied: false},
+		{version: "1.3.1", constraint: ">= 1.0.0, < 2.0.0", satisfied: true},
+		{version: "0.6.0", constraint: "> 0.1.0, < 0.5.0 || > 1.0.0, < 2.0.0", satisfied: false},
+		// four part versions
+		{version: "4.5.0.1", constraint: "< 4.5.0.2", satisfied: true},
+		{version: "4.5.0.2", constraint: "< 4.5.0.2", satisfied: false},
+		{version: "4.5.0.1", constraint: "> 4.5", satisfied: true},
+		{version: "4.5.0.0", constraint: "= 4.5", satisfied: true},
+		// leading zeros and build metadata are not significant
+		{version: "1.01.2", constraint: "= 1.1.2", satisfied: true},
+		{version: "1.1.2+build.5", constraint: "= 1.1.2", satisfied: true},
+		// pre-release versions
+		{version: "1.0.0-beta", constraint: "< 1.0.0", satisfied: true},
+		{version: "1.0.0-beta.2", constraint: "< 1.0.0-beta.10", satisfied: true},
+		{version: "1.0.0-Beta.2", constraint: "= 1.0.0-beta.2", satisfied: true},
+		{version: "1.0.0-alpha", constraint: "< 1.0.0-alpha.1", satisfied: true},
+		{version: "1.0.0-rc.1", constraint: "> 1.0.0-beta.11", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newNugetConstraint(test.constraint)
+			assert.NoError(t, err, "unexpected error from newNugetConstraint: %v", err)
+
+			test.assertVersionConstraint(t, NugetFormat, constraint)
+		})
+	}
+}
+
+func TestNewNugetVersion_Invalid(t *testing.T) {
+	for _, raw := range []string{"", "1.2.3.4.5", "a.b.c", "1.2.3-"} {
+		t.Run(raw, func(t *testing.T) {
+			_, err := newNugetVersion(raw)
+			assert.Error(t, err)
+		})
+	}
+}
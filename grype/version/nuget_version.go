@@ -0,0 +1,108 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// nugetVersionPattern matches NuGet versions, which may have up to four numeric parts followed by an optional
+// pre-release label and build metadata (e.g. "1.2.3.4-beta.1+build"). See
+// https://docs.microsoft.com/en-us/nuget/concepts/package-versioning
+var nugetVersionPattern = regexp.MustCompile(`^v?(?P<numeric>\d+(\.\d+){0,3})(-(?P<prerelease>[0-9A-Za-z\-.]+))?(\+[0-9A-Za-z\-.]+)?$`)
+
+type nugetVersion struct {
+	numeric    [4]int
+	prerelease []string
+}
+
+func newNugetVersion(raw string) (*nugetVersion, error) {
+	match := nugetVersionPattern.FindStringSubmatch(strings.TrimSpace(raw))
+	if match == nil {
+		return nil, fmt.Errorf("unable to parse nuget version: %q", raw)
+	}
+
+	var ver nugetVersion
+	for i, part := range strings.Split(match[nugetVersionPattern.SubexpIndex("numeric")], ".") {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse nuget version segment %q: %w", part, err)
+		}
+		ver.numeric[i] = value
+	}
+
+	if prerelease := match[nugetVersionPattern.SubexpIndex("prerelease")]; prerelease != "" {
+		// pre-release labels are compared case-insensitively
+		ver.prerelease = strings.Split(strings.ToLower(prerelease), ".")
+	}
+
+	return &ver, nil
+}
+
+func (v *nugetVersion) Compare(other *Version) (int, error) {
+	if other.Format != NugetFormat {
+		return -1, fmt.Errorf("unable to compare nuget version to given format: %s", other.Format)
+	}
+	if other.rich.nugetVer == nil {
+		return -1, fmt.Errorf("given empty nugetVersion object")
+	}
+
+	return other.rich.nugetVer.compare(v), nil
+}
+
+// compare returns 0 if v == other, 1 if v > other, and -1 if v < other.
+func (v *nugetVersion) compare(other *nugetVersion) int {
+	for i := range v.numeric {
+		if v.numeric[i] != other.numeric[i] {
+			return compareInts(v.numeric[i], other.numeric[i])
+		}
+	}
+
+	// a release version has a higher precedence than any pre-release version of the same numeric version
+	switch {
+	case len(v.prerelease) == 0 && len(other.prerelease) == 0:
+		return 0
+	case len(v.prerelease) == 0:
+		return 1
+	case len(other.prerelease) == 0:
+		return -1
+	}
+
+	return comparePrereleaseIdentifiers(v.prerelease, other.prerelease)
+}
+
+// comparePrereleaseIdentifiers compares dot-separated pre-release identifiers according to semver 2.0 rules:
+// numeric identifiers are compared numerically and have a lower precedence than alphanumeric identifiers, which are
+// compared lexically. A larger set of identifiers has a higher precedence when all preceding identifiers are equal.
+func comparePrereleaseIdentifiers(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		aNum, aErr := strconv.Atoi(a[i])
+		bNum, bErr := strconv.Atoi(b[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if aNum != bNum {
+				return compareInts(aNum, bNum)
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(a[i], b[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	return compareInts(len(a), len(b))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
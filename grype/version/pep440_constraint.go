@@ -0,0 +1,169 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pep440CompatiblePattern matches compatible release constraint units (e.g. "~= 2.2.post3")
+var pep440CompatiblePattern = regexp.MustCompile(`^~=\s*(?P<version>\S+)$`)
+
+// pep440EqualityPattern matches the (arbitrary) equality operators of a constraint unit (e.g. "== 1.0" or "=== 1.0")
+var pep440EqualityPattern = regexp.MustCompile(`^={2,3}`)
+
+type pep440Constraint struct {
+	raw        string
+	expression *constraintExpression
+	// fuzzy is used for versions that do not conform to PEP 440 (e.g. legacy setuptools versions)
+	fuzzy *fuzzyConstraint
+}
+
+func newPep440Constraint(raw string) (pep440Constraint, error) {
+	if raw == "" {
+		// an empty constraint is always satisfied
+		return pep440Constraint{}, nil
+	}
+
+	normalized, err := normalizePep440Constraint(raw)
+	if err != nil {
+		return pep440Constraint{}, err
+	}
+
+	fuzzy, err := newFuzzyConstraint(normalized, "python")
+	if err != nil {
+		return pep440Constraint{}, fmt.Errorf("unable to parse python constraint phrase: %w", err)
+	}
+
+	constraint := pep440Constraint{
+		raw:   raw,
+		fuzzy: fuzzy,
+	}
+
+	if expression, err := newConstraintExpression(normalized, newPep440Comparator); err == nil {
+		constraint.expression = &expression
+	}
+
+	return constraint, nil
+}
+
+// normalizePep440Constraint rewrites the PEP 440 operators that have no direct equivalent into explicit comparisons
+// (e.g. "== 1.0" becomes "= 1.0" and "~= 1.4.5" becomes ">= 1.4.5, < 1.5.dev0").
+// See https://www.python.org/dev/peps/pep-0440/#version-specifiers
+func normalizePep440Constraint(phrase string) (string, error) {
+	orParts := strings.Split(phrase, "||")
+	for orIdx, orPart := range orParts {
+		andParts := strings.Split(orPart, ",")
+		for andIdx, andPart := range andParts {
+			andPart = pep440EqualityPattern.ReplaceAllString(strings.TrimSpace(andPart), "=")
+			match := pep440CompatiblePattern.FindStringSubmatch(andPart)
+			if match == nil {
+				andParts[andIdx] = andPart
+				continue
+			}
+			lower := match[pep440CompatiblePattern.SubexpIndex("version")]
+			upper, err := bumpPep440Version(lower)
+			if err != nil {
+				return "", fmt.Errorf("unable to expand compatible release constraint %q: %w", andPart, err)
+			}
+			andParts[andIdx] = fmt.Sprintf(">= %s, < %s", lower, upper)
+		}
+		orParts[orIdx] = strings.Join(andParts, ", ")
+	}
+	return strings.Join(orParts, " || "), nil
+}
+
+// bumpPep440Version returns the lowest possible version of the next compatible release series, dropping the final
+// release segment and incrementing the one before it (e.g. "2.2.post3" becomes "3.dev0").
+func bumpPep440Version(raw string) (string, error) {
+	ver, err := newPep440Version(raw)
+	if err != nil {
+		return "", err
+	}
+	if len(ver.release) < 2 {
+		return "", fmt.Errorf("compatible release version must have at least two release segments: %q", raw)
+	}
+
+	segments := make([]string, len(ver.release)-1)
+	for i, value := range ver.release[:len(ver.release)-1] {
+		segments[i] = strconv.Itoa(value)
+	}
+	last := len(segments) - 1
+	segments[last] = strconv.Itoa(ver.release[last] + 1)
+
+	bumped := strings.Join(segments, ".") + ".dev0"
+	if ver.epoch != 0 {
+		bumped = fmt.Sprintf("%d!%s", ver.epoch, bumped)
+	}
+	return bumped, nil
+}
+
+func newPep440Comparator(unit constraintUnit) (Comparator, error) {
+	ver, err := newPep440Version(unit.version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse constraint version (%s): %w", unit.version, err)
+	}
+	return pep440Specifier{operator: unit.rangeOperator, version: ver}, nil
+}
+
+// pep440Specifier compares versions to the version of a constraint unit the way PEP 440 version specifiers match
+// versions, rather than by the ordering of versions alone: the local version label of a version is ignored when the
+// specified version has none (e.g. "== 1.0" matches "1.0+ubuntu.1"), and "> V" does not match post-releases of V
+// unless V is a post-release itself (e.g. "> 1.0" does not match "1.0.post1").
+// See https://www.python.org/dev/peps/pep-0440/#version-specifiers
+type pep440Specifier struct {
+	operator operator
+	version  *pep440Version
+}
+
+func (s pep440Specifier) Compare(other *Version) (int, error) {
+	if other.Format != PythonFormat {
+		return -1, fmt.Errorf("unable to compare PEP 440 version to given format: %s", other.Format)
+	}
+	if other.rich.pep440Ver == nil {
+		return -1, fmt.Errorf("given empty pep440Version object")
+	}
+
+	candidate := other.rich.pep440Ver
+	if s.version.local == nil && candidate.local != nil {
+		public := *candidate
+		public.local = nil
+		candidate = &public
+	}
+
+	result := candidate.compare(s.version)
+	if s.operator == GT && result > 0 && s.version.post == nil && candidate.post != nil &&
+		candidate.base().compare(s.version.base()) == 0 {
+		// a post-release of the specified version is not greater than it
+		return 0, nil
+	}
+	return result, nil
+}
+
+func (c pep440Constraint) Satisfied(version *Version) (bool, error) {
+	if c.raw == "" && version != nil {
+		// an empty constraint is always satisfied
+		return true, nil
+	} else if version == nil {
+		if c.raw != "" {
+			// a non-empty constraint with no version given should always fail
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if c.expression == nil || version.Format != PythonFormat || version.rich.pep440Ver == nil {
+		// either the constraint or the version does not conform to PEP 440, fallback to fuzzy comparison
+		return c.fuzzy.Satisfied(version)
+	}
+
+	return c.expression.satisfied(version)
+}
+
+func (c pep440Constraint) String() string {
+	if c.raw == "" {
+		return "none (python)"
+	}
+	return fmt.Sprintf("%s (python)", c.raw)
+}
@@ -0,0 +1,83 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionPep440Constraint(t *testing.T) {
+	tests := []testCase{
+		// empty values
+		{version: "2.3.1", constraint: "", satisfied: true},
+		// typical cases
+		{version: "2.3.1", constraint: "< 2.0.0", satisfied: false},
+		{version: "1.3.1", constraint: ">= 1.0.0, < 2.0.0", satisfied: true},
+		{version: "0.6.0", constraint: "> 0.1.0, < 0.5.0 || > 1.0.0, < 2.0.0", satisfied: false},
+		{version: "1.0", constraint: "== 1.0.0", satisfied: true},
+		{version: "1.0", constraint: "=== 1.0", satisfied: true},
+		{version: "1.0.1", constraint: "== 1.0", satisfied: false},
+		// epochs
+		{version: "1!1.0", constraint: "> 2.0", satisfied: true},
+		{version: "2.0", constraint: "< 1!1.0", satisfied: true},
+		// pre, post, and dev releases
+		{version: "1.0rc1", constraint: "< 1.0", satisfied: true},
+		{version: "1.0a2", constraint: "< 1.0b1", satisfied: true},
+		{version: "1.0.post1", constraint: "> 1.0", satisfied: false},
+		{version: "1.0.post1", constraint: ">= 1.0", satisfied: true},
+		{version: "1.0.post2", constraint: "> 1.0.post1", satisfied: true},
+		{version: "1.0.1", constraint: "> 1.0", satisfied: true},
+		{version: "1.0rc1.post1", constraint: "> 1.0rc1", satisfied: false},
+		{version: "1.0.post1", constraint: "> 1.0rc1", satisfied: true},
+		{version: "1.0.post1", constraint: "< 1.0.1", satisfied: true},
+		{version: "1.0.dev1", constraint: "< 1.0a1", satisfied: true},
+		{version: "1.0a1.dev1", constraint: "< 1.0a1", satisfied: true},
+		{version: "1.0.post1.dev1", constraint: "< 1.0.post1", satisfied: true},
+		{version: "1.0.post1.dev1", constraint: "> 1.0", satisfied: false},
+		{version: "1.0b2.post345.dev456", constraint: ">= 1.0b2, < 1.0b2.post345", satisfied: true},
+		// alternative spellings
+		{version: "1.0-alpha.2", constraint: "= 1.0a2", satisfied: true},
+		{version: "1.0c1", constraint: "= 1.0rc1", satisfied: true},
+		{version: "1.0-1", constraint: "= 1.0.post1", satisfied: true},
+		{version: "v1.0.0-DEV", constraint: "= 1.0.dev0", satisfied: true},
+		// local version labels
+		{version: "1.0+ubuntu.1", constraint: "== 1.0", satisfied: true},
+		{version: "1.0+ubuntu.1", constraint: "<= 1.0", satisfied: true},
+		{version: "1.0+ubuntu.1", constraint: "> 1.0", satisfied: false},
+		{version: "1.0+ubuntu.1", constraint: "== 1.0+ubuntu.2", satisfied: false},
+		{version: "1.0", constraint: "== 1.0+ubuntu.1", satisfied: false},
+		{version: "1.0+ubuntu.1", constraint: "< 1.0.post1", satisfied: true},
+		{version: "1.0+abc.5", constraint: "< 1.0+abc.10", satisfied: true},
+		{version: "1.0+5", constraint: "> 1.0+abc", satisfied: true},
+		// compatible releases
+		{version: "2.9.1", constraint: "~= 2.2", satisfied: true},
+		{version: "3.0.dev1", constraint: "~= 2.2", satisfied: false},
+		{version: "1.4.9", constraint: "~= 1.4.5", satisfied: true},
+		{version: "1.5.0", constraint: "~= 1.4.5", satisfied: false},
+		// legacy versions fallback to fuzzy comparison
+		{version: "2004d", constraint: "< 2005", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newPep440Constraint(test.constraint)
+			assert.NoError(t, err, "unexpected error from newPep440Constraint: %v", err)
+
+			test.assertVersionConstraint(t, PythonFormat, constraint)
+		})
+	}
+}
+
+func TestPep440ConstraintFromFormat(t *testing.T) {
+	constraint, err := GetConstraint("< 1.0", PythonFormat)
+	require.NoError(t, err)
+
+	// fuzzy comparison would otherwise consider the release candidate to be greater than the final release
+	ver, err := NewVersion("1.0rc1", PythonFormat)
+	require.NoError(t, err)
+
+	satisfied, err := constraint.Satisfied(ver)
+	require.NoError(t, err)
+	assert.True(t, satisfied)
+}
@@ -0,0 +1,226 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pep440VersionPattern matches (and normalizes the spelling of) python package versions.
+// See https://www.python.org/dev/peps/pep-0440/#appendix-b-parsing-version-strings-with-regular-expressions
+var pep440VersionPattern = regexp.MustCompile(`(?i)^v?` +
+	`(?:(?P<epoch>\d+)!)?` +
+	`(?P<release>\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(?P<pre_l>alpha|a|beta|b|preview|pre|c|rc)[-_.]?(?P<pre_n>\d+)?)?` +
+	`(?:-(?P<post_n1>\d+)|[-_.]?(?P<post_l>post|rev|r)[-_.]?(?P<post_n2>\d+)?)?` +
+	`(?:[-_.]?(?P<dev_l>dev)[-_.]?(?P<dev_n>\d+)?)?` +
+	`(?:\+(?P<local>[a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+// pep440PrePhases orders the pre-release phases (alternative spellings are normalized onto "a", "b", and "rc").
+var pep440PrePhases = map[string]int{
+	"a":       0,
+	"alpha":   0,
+	"b":       1,
+	"beta":    1,
+	"c":       2,
+	"rc":      2,
+	"pre":     2,
+	"preview": 2,
+}
+
+type pep440PreRelease struct {
+	phase  int
+	number int
+}
+
+type pep440Version struct {
+	epoch   int
+	release []int
+	pre     *pep440PreRelease
+	post    *int
+	dev     *int
+	local   []string
+}
+
+func newPep440Version(raw string) (*pep440Version, error) {
+	raw = strings.TrimSpace(raw)
+	match := pep440VersionPattern.FindStringSubmatch(raw)
+	if match == nil {
+		return nil, fmt.Errorf("unable to parse PEP 440 version: %q", raw)
+	}
+	group := func(name string) string {
+		return match[pep440VersionPattern.SubexpIndex(name)]
+	}
+	number := func(value string) (int, error) {
+		if value == "" {
+			// an implicit number is 0 (e.g. "1.0rc" is "1.0rc0")
+			return 0, nil
+		}
+		return strconv.Atoi(value)
+	}
+
+	var ver pep440Version
+	var err error
+	if ver.epoch, err = number(group("epoch")); err != nil {
+		return nil, fmt.Errorf("unable to parse PEP 440 epoch %q: %w", raw, err)
+	}
+
+	for _, part := range strings.Split(group("release"), ".") {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse PEP 440 release segment %q: %w", part, err)
+		}
+		ver.release = append(ver.release, value)
+	}
+
+	if label := group("pre_l"); label != "" {
+		value, err := number(group("pre_n"))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse PEP 440 pre-release %q: %w", raw, err)
+		}
+		ver.pre = &pep440PreRelease{phase: pep440PrePhases[strings.ToLower(label)], number: value}
+	}
+
+	if postNumber := group("post_n1"); postNumber != "" || group("post_l") != "" {
+		if postNumber == "" {
+			postNumber = group("post_n2")
+		}
+		value, err := number(postNumber)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse PEP 440 post-release %q: %w", raw, err)
+		}
+		ver.post = &value
+	}
+
+	if group("dev_l") != "" {
+		value, err := number(group("dev_n"))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse PEP 440 dev-release %q: %w", raw, err)
+		}
+		ver.dev = &value
+	}
+
+	if local := group("local"); local != "" {
+		ver.local = strings.FieldsFunc(strings.ToLower(local), func(r rune) bool {
+			return r == '-' || r == '_' || r == '.'
+		})
+	}
+
+	return &ver, nil
+}
+
+func (v *pep440Version) Compare(other *Version) (int, error) {
+	if other.Format != PythonFormat {
+		return -1, fmt.Errorf("unable to compare PEP 440 version to given format: %s", other.Format)
+	}
+	if other.rich.pep440Ver == nil {
+		return -1, fmt.Errorf("given empty pep440Version object")
+	}
+
+	return other.rich.pep440Ver.compare(v), nil
+}
+
+// base returns the version without its post-release, dev-release, and local version label (e.g. "1.0rc1" for
+// "1.0rc1.post2.dev3+ubuntu.1").
+func (v *pep440Version) base() *pep440Version {
+	return &pep440Version{
+		epoch:   v.epoch,
+		release: v.release,
+		pre:     v.pre,
+	}
+}
+
+// compare returns 0 if v == other, 1 if v > other, and -1 if v < other, ordering versions by epoch, release (ignoring
+// trailing zeros), pre-release, post-release, dev-release, and finally local version label. Note that a dev-release
+// of a final release sorts before any pre-release of the same release (e.g. "1.0.dev1" < "1.0a1" < "1.0").
+func (v *pep440Version) compare(other *pep440Version) int {
+	if result := compareInts(v.epoch, other.epoch); result != 0 {
+		return result
+	}
+	if result := compareIntSegments(trimTrailingZeros(v.release), trimTrailingZeros(other.release)); result != 0 {
+		return result
+	}
+	if result := comparePep440PreReleases(v, other); result != 0 {
+		return result
+	}
+	if result := compareOptionalInts(v.post, other.post, -1); result != 0 {
+		return result
+	}
+	if result := compareOptionalInts(v.dev, other.dev, 1); result != 0 {
+		return result
+	}
+	return comparePep440Locals(v.local, other.local)
+}
+
+// preReleaseRank orders versions without a pre-release relative to those with one for the same release.
+func (v *pep440Version) preReleaseRank() int {
+	switch {
+	case v.pre != nil:
+		return 0
+	case v.post == nil && v.dev != nil:
+		// a dev-release of the final release comes before all pre-releases
+		return -1
+	default:
+		return 1
+	}
+}
+
+func comparePep440PreReleases(a, b *pep440Version) int {
+	if result := compareInts(a.preReleaseRank(), b.preReleaseRank()); result != 0 {
+		return result
+	}
+	if a.pre == nil || b.pre == nil {
+		return 0
+	}
+	if result := compareInts(a.pre.phase, b.pre.phase); result != 0 {
+		return result
+	}
+	return compareInts(a.pre.number, b.pre.number)
+}
+
+// compareOptionalInts compares the given values, where a missing value sorts either before (missing = -1) or after
+// (missing = 1) any present value.
+func compareOptionalInts(a, b *int, missing int) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return missing
+	case b == nil:
+		return -missing
+	}
+	return compareInts(*a, *b)
+}
+
+// comparePep440Locals compares local version labels, where numeric segments sort after alphanumeric segments and a
+// version without a local label sorts before any version with one.
+func comparePep440Locals(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		aValue, aErr := strconv.Atoi(a[i])
+		bValue, bErr := strconv.Atoi(b[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if result := compareInts(aValue, bValue); result != 0 {
+				return result
+			}
+		case aErr == nil:
+			return 1
+		case bErr == nil:
+			return -1
+		default:
+			if result := strings.Compare(a[i], b[i]); result != 0 {
+				return result
+			}
+		}
+	}
+	return compareInts(len(a), len(b))
+}
+
+func trimTrailingZeros(segments []int) []int {
+	end := len(segments)
+	for end > 0 && segments[end-1] == 0 {
+		end--
+	}
+	return segments[:end]
+}
@@ -0,0 +1,69 @@
+package version
+
+import (
+	"fmt"
+)
+
+type rConstraint struct {
+	raw        string
+	expression constraintExpression
+}
+
+func newRConstraint(raw string) (rConstraint, error) {
+	if raw == "" {
+		// an empty constraint is always satisfied
+		return rConstraint{}, nil
+	}
+
+	constraints, err := newConstraintExpression(raw, newRComparator)
+	if err != nil {
+		return rConstraint{}, fmt.Errorf("unable to parse R constraint phrase: %w", err)
+	}
+
+	return rConstraint{
+		raw:        raw,
+		expression: constraints,
+	}, nil
+}
+
+func newRComparator(unit constraintUnit) (Comparator, error) {
+	ver, err := newRVersion(unit.version)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse constraint version (%s): %w", unit.version, err)
+	}
+	return ver, nil
+}
+
+func (c rConstraint) supported(format Format) bool {
+	return format == RFormat
+}
+
+func (c rConstraint) Satisfied(version *Version) (bool, error) {
+	if c.raw == "" && version != nil {
+		// an empty constraint is always satisfied
+		return true, nil
+	} else if version == nil {
+		if c.raw != "" {
+			// a non-empty constraint with no version given should always fail
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if !c.supported(version.Format) {
+		return false, fmt.Errorf("(R) unsupported format: %s", version.Format)
+	}
+
+	if version.rich.rVer == nil {
+		return false, fmt.Errorf("no rich R version given: %+v", version)
+	}
+
+	return c.expression.satisfied(version)
+}
+
+func (c rConstraint) String() string {
+	if c.raw == "" {
+		return "none (R)"
+	}
+	return fmt.Sprintf("%s (R)", c.raw)
+}
@@ -0,0 +1,43 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionRConstraint(t *testing.T) {
+	tests := []testCase{
+		// empty values
+		{version: "2.3.1", constraint: "", satisfied: true},
+		// typical cases
+		{version: "2.3.1", constraint: "< 2.0.0", satisfied: false},
+		{version: "1.3.1", constraint: ">= 1.0.0, < 2.0.0", satisfied: true},
+		{version: "0.6.0", constraint: "> 0.1.0, < 0.5.0 || > 1.0.0, < 2.0.0", satisfied: false},
+		// dash and dot separators
+		{version: "1.2-3", constraint: "= 1.2.3", satisfied: true},
+		{version: "1.2-10", constraint: "> 1.2-9", satisfied: true},
+		{version: "0.9-14", constraint: "< 0.10-1", satisfied: true},
+		// a longer version is greater than its prefix
+		{version: "1.2.0", constraint: "> 1.2", satisfied: true},
+		{version: "1.2", constraint: "< 1.2-0", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newRConstraint(test.constraint)
+			assert.NoError(t, err, "unexpected error from newRConstraint: %v", err)
+
+			test.assertVersionConstraint(t, RFormat, constraint)
+		})
+	}
+}
+
+func TestNewRVersion_Invalid(t *testing.T) {
+	for _, raw := range []string{"", "1", "1.2.a", "1..2", "1.2-"} {
+		t.Run(raw, func(t *testing.T) {
+			_, err := newRVersion(raw)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// rVersionPattern matches R package versions, which are sequences of at least two non-negative integers separated
+// by either "." or "-" (e.g. "1.0.7" or "1.2-3").
+// See https://cran.r-project.org/doc/manuals/r-release/R-exts.html#The-DESCRIPTION-file
+var rVersionPattern = regexp.MustCompile(`^\d+([.-]\d+)+$`)
+
+type rVersion struct {
+	segments []int
+}
+
+func newRVersion(raw string) (*rVersion, error) {
+	raw = strings.TrimSpace(raw)
+	if !rVersionPattern.MatchString(raw) {
+		return nil, fmt.Errorf("unable to parse R version: %q", raw)
+	}
+
+	var ver rVersion
+	for _, part := range strings.FieldsFunc(raw, func(r rune) bool { return r == '.' || r == '-' }) {
+		value, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse R version segment %q: %w", part, err)
+		}
+		ver.segments = append(ver.segments, value)
+	}
+	return &ver, nil
+}
+
+func (v *rVersion) Compare(other *Version) (int, error) {
+	if other.Format != RFormat {
+		return -1, fmt.Errorf("unable to compare R version to given format: %s", other.Format)
+	}
+	if other.rich.rVer == nil {
+		return -1, fmt.Errorf("given empty rVersion object")
+	}
+
+	return other.rich.rVer.compare(v), nil
+}
+
+// compare returns 0 if v == other, 1 if v > other, and -1 if v < other. Note that the separators are not significant
+// (e.g. "1.2-3" is equal to "1.2.3") and that a version is greater than any version it is a prefix of (e.g. "1.2.0" is
+// greater than "1.2"), as with R's package_version.
+func (v *rVersion) compare(other *rVersion) int {
+	return compareIntSegments(v.segments, other.segments)
+}
+
+// compareIntSegments lexically compares the given numeric segments, where a sequence is greater than any sequence it
+// is a prefix of.
+func compareIntSegments(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return compareInts(a[i], b[i])
+		}
+	}
+	return compareInts(len(a), len(b))
+}
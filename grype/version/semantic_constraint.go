@@ -35,7 +35,9 @@ func newSemanticConstraint(constStr string) (semanticConstraint, error) {
 }
 
 func (c semanticConstraint) supported(format Format) bool {
-	return format == SemanticFormat
+	// go versions are normalized into semantic versions, so they can be compared against semantic constraints (as can
+	// gem, hex, npm, and semver range versions which are semver compatible)
+	return format == SemanticFormat || format == GolangFormat || format == GemFormat || format == HexFormat || format == NpmFormat || format == SemverRangeFormat
 }
 
 func (c semanticConstraint) Satisfied(version *Version) (bool, error) {
@@ -0,0 +1,201 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type semverRangeConstraint struct {
+	raw      string
+	semantic semanticConstraint
+}
+
+// newSemverRangeConstraint parses a semantic version constraint that may contain caret, tilde, and wildcard range
+// operators (as used by cargo and dart pub), which are not natively supported and so must be made explicit.
+func newSemverRangeConstraint(raw string) (semverRangeConstraint, error) {
+	if raw == "" {
+		// an empty constraint is always satisfied
+		return semverRangeConstraint{}, nil
+	}
+
+	expanded, err := expandSemverRanges(normalizer.Replace(raw))
+	if err != nil {
+		return semverRangeConstraint{}, err
+	}
+
+	semantic, err := newSemanticConstraint(expanded)
+	if err != nil {
+		return semverRangeConstraint{}, fmt.Errorf("unable to parse semver range %q: %w", raw, err)
+	}
+
+	return semverRangeConstraint{
+		raw:      raw,
+		semantic: semantic,
+	}, nil
+}
+
+func (c semverRangeConstraint) supported(format Format) bool {
+	return format == SemverRangeFormat || format == SemanticFormat
+}
+
+func (c semverRangeConstraint) Satisfied(version *Version) (bool, error) {
+	if c.raw == "" && version != nil {
+		// an empty constraint is always satisfied
+		return true, nil
+	} else if version == nil {
+		if c.raw != "" {
+			// a non-empty constraint with no version given should always fail
+			return false, nil
+		}
+		return true, nil
+	}
+
+	if !c.supported(version.Format) {
+		return false, fmt.Errorf("(semver range) unsupported format: %s", version.Format)
+	}
+
+	return c.semantic.Satisfied(version)
+}
+
+func (c semverRangeConstraint) String() string {
+	if c.raw == "" {
+		return "none (semver range)"
+	}
+	return fmt.Sprintf("%s (semver range)", c.raw)
+}
+
+// semverRangeUnitPattern matches a single operator and version pair, allowing for space-separated units within the
+// same and'ed group (e.g. ">=1.0.0 <2.0.0", as used by dart pub)
+var semverRangeUnitPattern = regexp.MustCompile(`[<>=!~^]*\s*[^\s<>=!~^]+`)
+
+// expandSemverRanges rewrites caret (e.g. "^1.2.3"), tilde (e.g. "~1.2.3"), and wildcard (e.g. "1.2.*") range
+// operators (as used by cargo and other semver-based ecosystems) into explicit comparisons that can be evaluated as
+// a regular semantic version constraint (e.g. "^1.2.3" becomes ">= 1.2.3, < 2.0.0").
+func expandSemverRanges(phrase string) (string, error) {
+	orParts := strings.Split(phrase, "||")
+	for orIdx, orPart := range orParts {
+		var andParts []string
+		for _, andPart := range strings.Split(orPart, ",") {
+			units := semverRangeUnitPattern.FindAllString(andPart, -1)
+			if len(units) == 0 {
+				units = []string{andPart}
+			}
+			for _, unit := range units {
+				expanded, err := expandSemverRange(strings.TrimSpace(unit))
+				if err != nil {
+					return "", err
+				}
+				andParts = append(andParts, expanded)
+			}
+		}
+		orParts[orIdx] = strings.Join(andParts, ", ")
+	}
+	return strings.Join(orParts, " || "), nil
+}
+
+// nolint:funlen
+func expandSemverRange(unit string) (string, error) {
+	switch {
+	case strings.HasPrefix(unit, "^"):
+		segments, err := parseSemverRangeSegments(strings.TrimPrefix(unit, "^"))
+		if err != nil {
+			return "", err
+		}
+		lower := semverRangeLowerBound(unit[1:], segments)
+		major, minor, patch := segments[0], segmentOrZero(segments, 1), segmentOrZero(segments, 2)
+		var upper string
+		switch {
+		case major > 0 || len(segments) == 1:
+			upper = fmt.Sprintf("%d.0.0", major+1)
+		case minor > 0 || len(segments) == 2:
+			upper = fmt.Sprintf("0.%d.0", minor+1)
+		default:
+			upper = fmt.Sprintf("0.0.%d", patch+1)
+		}
+		return fmt.Sprintf(">= %s, < %s", lower, upper), nil
+	case strings.HasPrefix(unit, "~") && !strings.HasPrefix(unit, "~>"):
+		segments, err := parseSemverRangeSegments(strings.TrimPrefix(unit, "~"))
+		if err != nil {
+			return "", err
+		}
+		lower := semverRangeLowerBound(unit[1:], segments)
+		if len(segments) == 1 {
+			return fmt.Sprintf(">= %s, < %d.0.0", lower, segments[0]+1), nil
+		}
+		return fmt.Sprintf(">= %s, < %d.%d.0", lower, segments[0], segments[1]+1), nil
+	case isSemverWildcard(unit):
+		var segments []int
+		for _, s := range strings.Split(unit, ".") {
+			if s == "*" || s == "x" || s == "X" {
+				break
+			}
+			value, err := strconv.Atoi(s)
+			if err != nil {
+				return "", fmt.Errorf("invalid wildcard version range %q: %w", unit, err)
+			}
+			segments = append(segments, value)
+		}
+		switch len(segments) {
+		case 0:
+			return ">= 0.0.0", nil
+		case 1:
+			return fmt.Sprintf(">= %d.0.0, < %d.0.0", segments[0], segments[0]+1), nil
+		default:
+			return fmt.Sprintf(">= %d.%d.0, < %d.%d.0", segments[0], segments[1], segments[0], segments[1]+1), nil
+		}
+	}
+	return unit, nil
+}
+
+func isSemverWildcard(unit string) bool {
+	if unit == "" || strings.ContainsAny(unit, "<>=!~^ ") {
+		return false
+	}
+	for _, s := range strings.Split(unit, ".") {
+		if s == "*" || s == "x" || s == "X" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSemverRangeSegments returns the numeric major, minor, and patch values given (at least one must be present).
+func parseSemverRangeSegments(version string) ([]int, error) {
+	core := strings.SplitN(strings.SplitN(strings.TrimSpace(version), "-", 2)[0], "+", 2)[0]
+	var segments []int
+	for _, s := range strings.Split(core, ".") {
+		if s == "*" || s == "x" || s == "X" {
+			break
+		}
+		value, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version range %q: %w", version, err)
+		}
+		segments = append(segments, value)
+	}
+	if len(segments) == 0 || len(segments) > 3 {
+		return nil, fmt.Errorf("invalid version range %q", version)
+	}
+	return segments, nil
+}
+
+func semverRangeLowerBound(version string, segments []int) string {
+	version = strings.TrimSpace(version)
+	if len(segments) == 3 {
+		return version
+	}
+	lower := make([]string, 3)
+	for i := range lower {
+		lower[i] = strconv.Itoa(segmentOrZero(segments, i))
+	}
+	return strings.Join(lower, ".")
+}
+
+func segmentOrZero(segments []int, idx int) int {
+	if idx < len(segments) {
+		return segments[idx]
+	}
+	return 0
+}
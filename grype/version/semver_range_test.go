@@ -0,0 +1,78 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionSemverRangeConstraint(t *testing.T) {
+	tests := []testCase{
+		// empty values
+		{version: "2.3.1", constraint: "", satisfied: true},
+		// cargo-style range operators
+		{version: "1.4.0", constraint: "^1.2.3", satisfied: true},
+		{version: "2.0.0", constraint: "^1.2.3", satisfied: false},
+		{version: "0.2.9", constraint: "^0.2.3", satisfied: true},
+		{version: "0.3.0", constraint: "^0.2.3", satisfied: false},
+		{version: "1.2.9", constraint: "~1.2.3", satisfied: true},
+		{version: "1.3.0", constraint: "~1.2.3", satisfied: false},
+		{version: "0.3.1", constraint: "< 0.2.8 || ^0.3.0, < 0.3.2", satisfied: true},
+		// pub-style (space separated) ranges
+		{version: "1.5.0", constraint: ">=1.0.0 <2.0.0", satisfied: true},
+		{version: "2.0.0", constraint: ">=1.0.0 <2.0.0", satisfied: false},
+		// typical cases
+		{version: "1.5.0", constraint: "> 0.1.0, < 0.5.0 || > 1.0.0, < 2.0.0", satisfied: true},
+		{version: "1.0.0-beta", constraint: "< 1.0.0", satisfied: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.tName(), func(t *testing.T) {
+			constraint, err := newSemverRangeConstraint(test.constraint)
+			assert.NoError(t, err, "unexpected error from newSemverRangeConstraint: %v", err)
+
+			test.assertVersionConstraint(t, SemanticFormat, constraint)
+		})
+	}
+}
+
+func Test_expandSemverRanges(t *testing.T) {
+	tests := []struct {
+		phrase   string
+		expected string
+		wantErr  bool
+	}{
+		{phrase: "< 1.2.3", expected: "< 1.2.3"},
+		{phrase: ">= 1.0.0, < 1.2.3", expected: ">= 1.0.0, < 1.2.3"},
+		{phrase: "^1.2.3", expected: ">= 1.2.3, < 2.0.0"},
+		{phrase: "^1.2", expected: ">= 1.2.0, < 2.0.0"},
+		{phrase: "^1", expected: ">= 1.0.0, < 2.0.0"},
+		{phrase: "^0.2.3", expected: ">= 0.2.3, < 0.3.0"},
+		{phrase: "^0.0.3", expected: ">= 0.0.3, < 0.0.4"},
+		{phrase: "^0.0", expected: ">= 0.0.0, < 0.1.0"},
+		{phrase: "^0", expected: ">= 0.0.0, < 1.0.0"},
+		{phrase: "^1.2.3-beta.1", expected: ">= 1.2.3-beta.1, < 2.0.0"},
+		{phrase: "~1.2.3", expected: ">= 1.2.3, < 1.3.0"},
+		{phrase: "~1.2", expected: ">= 1.2.0, < 1.3.0"},
+		{phrase: "~1", expected: ">= 1.0.0, < 2.0.0"},
+		{phrase: "~> 1.2", expected: "~> 1.2"},
+		{phrase: "1.2.*", expected: ">= 1.2.0, < 1.3.0"},
+		{phrase: "1.x", expected: ">= 1.0.0, < 2.0.0"},
+		{phrase: "*", expected: ">= 0.0.0"},
+		{phrase: "^0.1.0 || ^0.2.0", expected: ">= 0.1.0, < 0.2.0 || >= 0.2.0, < 0.3.0"},
+		{phrase: ">=1.0.0 <2.0.0", expected: ">=1.0.0, <2.0.0"},
+		{phrase: ">= 1.0.0 < 2.0.0 || ^3.0.0", expected: ">= 1.0.0, < 2.0.0 || >= 3.0.0, < 4.0.0"},
+		{phrase: "^a.b", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.phrase, func(t *testing.T) {
+			actual, err := expandSemverRanges(test.phrase)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
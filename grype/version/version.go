@@ -14,12 +14,23 @@ type Version struct {
 }
 
 type rich struct {
-	cpeVers []syftPkg.CPE
-	semVer  *semanticVersion
-	apkVer  *apkVersion
-	debVer  *debVersion
-	rpmVer  *rpmVersion
-	kbVer   *kbVersion
+	cpeVers     []syftPkg.CPE
+	semVer      *semanticVersion
+	apkVer      *apkVersion
+	debVer      *debVersion
+	rpmVer      *rpmVersion
+	kbVer       *kbVersion
+	nugetVer    *nugetVersion
+	composerVer *composerVersion
+	gemVer      *gemVersion
+	condaVer    *condaVersion
+	rVer        *rVersion
+	haskellVer  *haskellVersion
+	bitnamiVer  *bitnamiVersion
+	pep440Ver   *pep440Version
+	mavenVer    *mavenVersion
+	npmVer      *npmVersion
+	customVer   *customVersion
 }
 
 func NewVersion(raw string, format Format) (*Version, error) {
@@ -37,7 +48,7 @@ func NewVersion(raw string, format Format) (*Version, error) {
 }
 
 func NewVersionFromPkg(p pkg.Package) (*Version, error) {
-	ver, err := NewVersion(p.Version, FormatFromPkgType(p.Type))
+	ver, err := NewVersion(p.Version, FormatFromPkg(p))
 	if err != nil {
 		return nil, err
 	}
@@ -64,8 +75,72 @@ func (v *Version) populate() error {
 		ver, err := newRpmVersion(v.Raw)
 		v.rich.rpmVer = &ver
 		return err
+	case GolangFormat:
+		ver, err := newGolangVersion(v.Raw)
+		if ver != nil {
+			// go versions are semver compatible once normalized, allowing for semver constraints to be used as well
+			v.rich.semVer = &semanticVersion{verObj: ver.verObj}
+		}
+		return err
+	case NugetFormat:
+		ver, err := newNugetVersion(v.Raw)
+		v.rich.nugetVer = ver
+		return err
+	case ComposerFormat:
+		ver, err := newComposerVersion(v.Raw)
+		v.rich.composerVer = ver
+		return err
+	case SemverRangeFormat:
+		// only the constraint syntax differs from semver
+		ver, err := newSemanticVersion(v.Raw)
+		v.rich.semVer = ver
+		return err
+	case HexFormat:
+		// hex packages are versioned with semver (only the requirement syntax differs)
+		ver, err := newSemanticVersion(v.Raw)
+		v.rich.semVer = ver
+		return err
+	case CondaFormat:
+		ver, err := newCondaVersion(v.Raw)
+		v.rich.condaVer = ver
+		return err
+	case RFormat:
+		ver, err := newRVersion(v.Raw)
+		v.rich.rVer = ver
+		return err
+	case HaskellFormat:
+		ver, err := newHaskellVersion(v.Raw)
+		v.rich.haskellVer = ver
+		return err
+	case NpmFormat:
+		ver, err := newNpmVersion(v.Raw)
+		v.rich.npmVer = ver
+		if semVer, err := newSemanticVersion(v.Raw); err == nil {
+			// npm versions are semver, allowing for semver constraints to be used as well
+			v.rich.semVer = semVer
+		}
+		return err
+	case MavenFormat:
+		ver, err := newMavenVersion(v.Raw)
+		v.rich.mavenVer = ver
+		return err
+	case BitnamiFormat:
+		ver, err := newBitnamiVersion(v.Raw)
+		v.rich.bitnamiVer = ver
+		return err
+	case GemFormat:
+		ver, err := newGemVersion(v.Raw)
+		v.rich.gemVer = ver
+		if semVer, err := newSemanticVersion(v.Raw); err == nil {
+			// most gem versions are semver compatible, allowing for semver constraints to be used as well
+			v.rich.semVer = semVer
+		}
+		return err
 	case PythonFormat:
-		// use the fuzzy constraint
+		if ver, err := newPep440Version(v.Raw); err == nil {
+			v.rich.pep440Ver = ver
+		}
+		// versions that do not conform to PEP 440 are still allowed, using the fuzzy constraint instead
 		return nil
 	case KBFormat:
 		ver := newKBVersion(v.Raw)
@@ -76,6 +151,12 @@ func (v *Version) populate() error {
 		return nil
 	}
 
+	if _, ok := customFormat(v.Format); ok {
+		ver, err := newCustomVersion(v.Raw, v.Format)
+		v.rich.customVer = ver
+		return err
+	}
+
 	return fmt.Errorf("no rich version populated (format=%s)", v.Format)
 }
 
@@ -1,17 +1,23 @@
 package vulnerability
 
 import (
+	"time"
+
 	grypeDB "github.com/anchore/grype/grype/db/v3"
 )
 
 type Metadata struct {
-	ID          string
-	DataSource  string
-	Namespace   string
-	Severity    string
-	URLs        []string
-	Description string
-	Cvss        []Cvss
+	ID           string
+	DataSource   string
+	Namespace    string
+	Severity     string
+	URLs         []string
+	Description  string
+	Cvss         []Cvss
+	Published    *time.Time
+	Modified     *time.Time
+	CWEs         []string
+	Exploitation string
 }
 
 type Cvss struct {
@@ -32,13 +38,17 @@ func NewMetadata(m *grypeDB.VulnerabilityMetadata) (*Metadata, error) {
 		return nil, nil
 	}
 	return &Metadata{
-		ID:          m.ID,
-		DataSource:  m.DataSource,
-		Namespace:   m.Namespace,
-		Severity:    m.Severity,
-		URLs:        m.URLs,
-		Description: m.Description,
-		Cvss:        NewCvss(m.Cvss),
+		ID:           m.ID,
+		DataSource:   m.DataSource,
+		Namespace:    m.Namespace,
+		Severity:     m.Severity,
+		URLs:         m.URLs,
+		Description:  m.Description,
+		Cvss:         NewCvss(m.Cvss),
+		Published:    m.Published,
+		Modified:     m.Modified,
+		CWEs:         m.CWEs,
+		Exploitation: m.Exploitation,
 	}, nil
 }
 
@@ -1,6 +1,8 @@
 package vulnerability
 
 import (
+	"time"
+
 	"github.com/anchore/grype/grype/distro"
 	"github.com/anchore/grype/grype/pkg"
 	syftPkg "github.com/anchore/syft/syft/pkg"
@@ -9,6 +11,7 @@ import (
 type Provider interface {
 	ProviderByDistro
 	ProviderByLanguage
+	ProviderByPackageType
 	ProviderByCPE
 }
 
@@ -20,10 +23,29 @@ type ProviderByLanguage interface {
 	GetByLanguage(syftPkg.Language, pkg.Package) ([]Vulnerability, error)
 }
 
+type ProviderByPackageType interface {
+	GetByPackageType(syftPkg.Type, pkg.Package) ([]Vulnerability, error)
+}
+
 type ProviderByCPE interface {
 	GetByCPE(syftPkg.CPE) ([]Vulnerability, error)
 }
 
+// BatchProvider is implemented by providers that are able to retrieve the vulnerabilities of many packages at once,
+// which is much faster than retrieving the vulnerabilities of each package on its own (e.g. for SBOMs with thousands of
+// packages).
+type BatchProvider interface {
+	// WithPackages returns a provider that has retrieved the vulnerabilities of the given packages at once (for the
+	// given distro), which falls back to retrieving the vulnerabilities of any other package on its own
+	WithPackages(*distro.Distro, []pkg.Package) (Provider, error)
+}
+
+// DistroEOLProvider is implemented by providers that are able to describe the end of life of distro releases.
+type DistroEOLProvider interface {
+	// GetDistroEOL returns when the given distro release reached (or will reach) the end of life (or nil when unknown)
+	GetDistroEOL(*distro.Distro) (*time.Time, error)
+}
+
 type MetadataProvider interface {
 	GetMetadata(id, namespace string) (*Metadata, error)
 }
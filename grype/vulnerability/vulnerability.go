@@ -2,12 +2,41 @@ package vulnerability
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/anchore/grype/grype/cpe"
 	grypeDB "github.com/anchore/grype/grype/db/v3"
 	"github.com/anchore/grype/grype/version"
 	"github.com/anchore/syft/syft/pkg"
 )
 
+// namespaceFormats are the version formats implied by the ecosystem of a namespace, used for records that do not
+// specify a version format.
+var namespaceFormats = map[string]version.Format{
+	"github:java": version.MavenFormat,
+	"github:npm":  version.NpmFormat,
+	"github:pub":  version.SemverRangeFormat,
+	"github:rust": version.SemverRangeFormat,
+}
+
+// namespacePrefixFormats are the version formats implied by the distro of a namespace regardless of the release
+// (e.g. "amzn:2" and "amzn:2023"), used for records that do not specify a version format.
+var namespacePrefixFormats = map[string]version.Format{
+	grypeDB.AmazonLinuxNamespacePrefix + ":": version.RpmFormat,
+	"mariner:":                               version.RpmFormat,
+	"azurelinux:":                            version.RpmFormat,
+	"wolfi:":                                 version.ApkFormat,
+	"chainguard:":                            version.ApkFormat,
+}
+
+// fixOnlyNamespacePrefixes are the namespaces of data sources that only describe the version that fixes a package
+// (e.g. amazon linux security advisories and the wolfi secdb), used for records that do not specify a constraint.
+var fixOnlyNamespacePrefixes = []string{
+	grypeDB.AmazonLinuxNamespacePrefix + ":",
+	"wolfi:",
+	"chainguard:",
+}
+
 type Reference struct {
 	ID        string
 	Namespace string
@@ -21,14 +50,30 @@ type Vulnerability struct {
 	Fix                    Fix
 	Advisories             []Advisory
 	RelatedVulnerabilities []Reference
+	RpmModularity          string
+	Arches                 []string
+	PlatformCPEs           []pkg.CPE
 }
 
 func NewVulnerability(vuln grypeDB.Vulnerability) (*Vulnerability, error) {
 	format := version.ParseFormat(vuln.VersionFormat)
+	if format == version.UnknownFormat {
+		format = impliedFormat(vuln.Namespace)
+	}
 
-	constraint, err := version.GetConstraint(vuln.VersionConstraint, format)
+	constraintStr := vuln.VersionConstraint
+	if constraintStr == "" && isFixOnlyNamespace(vuln.Namespace) {
+		constraintStr = fixConstraint(vuln.Fix)
+	}
+
+	constraint, err := version.GetConstraint(constraintStr, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse constraint='%s' format='%s': %w", constraintStr, format, err)
+	}
+
+	platformCPEs, err := cpe.NewSlice(vuln.PlatformCPEs...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse constraint='%s' format='%s': %w", vuln.VersionConstraint, format, err)
+		return nil, fmt.Errorf("failed to parse platform CPEs of vulnerability=%q: %w", vuln.ID, err)
 	}
 
 	advisories := make([]Advisory, len(vuln.Advisories))
@@ -58,9 +103,51 @@ func NewVulnerability(vuln grypeDB.Vulnerability) (*Vulnerability, error) {
 		},
 		Advisories:             advisories,
 		RelatedVulnerabilities: relatedVulnerabilities,
+		RpmModularity:          vuln.RpmModularity,
+		Arches:                 vuln.Arches,
+		PlatformCPEs:           platformCPEs,
 	}, nil
 }
 
+// impliedFormat returns the version format implied by the given namespace (or the unknown format if nothing is
+// implied).
+func impliedFormat(namespace string) version.Format {
+	if format, ok := namespaceFormats[namespace]; ok {
+		return format
+	}
+	for prefix, format := range namespacePrefixFormats {
+		if strings.HasPrefix(namespace, prefix) {
+			return format
+		}
+	}
+	return version.UnknownFormat
+}
+
+func isFixOnlyNamespace(namespace string) bool {
+	for _, prefix := range fixOnlyNamespacePrefixes {
+		if strings.HasPrefix(namespace, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// fixConstraint returns the constraint for a record from a fix-only data source without one, where any version earlier
+// than a fixed version is affected. Note that the wolfi secdb marks packages that were never affected with a fixed
+// version of "0", which results in a constraint that no version satisfies.
+func fixConstraint(fix grypeDB.Fix) string {
+	if fix.State != grypeDB.FixedState {
+		return ""
+	}
+	var units []string
+	for _, v := range fix.Versions {
+		if v != "" {
+			units = append(units, fmt.Sprintf("< %s", v))
+		}
+	}
+	return strings.Join(units, " || ")
+}
+
 func (v Vulnerability) String() string {
 	return fmt.Sprintf("Vuln(id=%s constraint=%q)", v.ID, v.Constraint.String())
 }
@@ -0,0 +1,195 @@
+package vulnerability
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	grypeDB "github.com/anchore/grype/grype/db/v3"
+)
+
+func TestNewVulnerability_ImpliedVersionFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		record     grypeDB.Vulnerability
+		constraint string
+	}{
+		{
+			name: "explicit format",
+			record: grypeDB.Vulnerability{
+				ID:                "CVE-2021-44228",
+				Namespace:         "github:java",
+				VersionConstraint: "< 2.15.0",
+				VersionFormat:     "semver",
+			},
+			constraint: "< 2.15.0 (semver)",
+		},
+		{
+			name: "unknown format within java namespace",
+			record: grypeDB.Vulnerability{
+				ID:                "CVE-2021-44228",
+				Namespace:         "github:java",
+				VersionConstraint: "< 2.15.0",
+				VersionFormat:     "unknown",
+			},
+			constraint: "< 2.15.0 (maven)",
+		},
+		{
+			name: "unknown format within npm namespace",
+			record: grypeDB.Vulnerability{
+				ID:                "CVE-2021-23337",
+				Namespace:         "github:npm",
+				VersionConstraint: "<4.17.21",
+				VersionFormat:     "unknown",
+			},
+			constraint: "<4.17.21 (npm)",
+		},
+		{
+			name: "unknown format within rust namespace",
+			record: grypeDB.Vulnerability{
+				ID:                "GHSA-5h46-h7hh-c6x9",
+				Namespace:         "github:rust",
+				VersionConstraint: "^0.3.0, < 0.3.2",
+				VersionFormat:     "unknown",
+			},
+			constraint: "^0.3.0, < 0.3.2 (semver range)",
+		},
+		{
+			name: "unknown format within amazon linux namespace",
+			record: grypeDB.Vulnerability{
+				ID:                "ALAS2023-2023-001",
+				Namespace:         "amzn:2023",
+				VersionConstraint: "< 3.0.8-1.amzn2023.0.1",
+				VersionFormat:     "unknown",
+			},
+			constraint: "< 3.0.8-1.amzn2023.0.1 (rpm)",
+		},
+		{
+			name: "unknown format within mariner namespace",
+			record: grypeDB.Vulnerability{
+				ID:                "CVE-2022-2068",
+				Namespace:         "mariner:2.0",
+				VersionConstraint: "< 1.1.1k-15.cm2",
+				VersionFormat:     "unknown",
+			},
+			constraint: "< 1.1.1k-15.cm2 (rpm)",
+		},
+		{
+			name: "unknown format within other namespace",
+			record: grypeDB.Vulnerability{
+				ID:                "CVE-2021-44228",
+				Namespace:         "nvd",
+				VersionConstraint: "< 2.15.0",
+				VersionFormat:     "unknown",
+			},
+			constraint: "< 2.15.0 (unknown)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vuln, err := NewVulnerability(test.record)
+			require.NoError(t, err)
+			assert.Equal(t, test.constraint, vuln.Constraint.String())
+		})
+	}
+}
+
+func TestNewVulnerability_FixOnlyConstraint(t *testing.T) {
+	tests := []struct {
+		name       string
+		record     grypeDB.Vulnerability
+		constraint string
+	}{
+		{
+			name: "constraint derived from the fixed version",
+			record: grypeDB.Vulnerability{
+				ID:            "ALAS2-2021-1722",
+				Namespace:     "amzn:2",
+				VersionFormat: "rpm",
+				Fix: grypeDB.Fix{
+					Versions: []string{"1:1.0.2k-24.amzn2.0.1"},
+					State:    grypeDB.FixedState,
+				},
+			},
+			constraint: "< 1:1.0.2k-24.amzn2.0.1 (rpm)",
+		},
+		{
+			name: "explicit constraint",
+			record: grypeDB.Vulnerability{
+				ID:                "ALAS2-2021-1722",
+				Namespace:         "amzn:2",
+				VersionConstraint: "< 1:1.0.2k-22.amzn2",
+				VersionFormat:     "rpm",
+				Fix: grypeDB.Fix{
+					Versions: []string{"1:1.0.2k-24.amzn2.0.1"},
+					State:    grypeDB.FixedState,
+				},
+			},
+			constraint: "< 1:1.0.2k-22.amzn2 (rpm)",
+		},
+		{
+			name: "constraint derived from the wolfi secdb fixed version",
+			record: grypeDB.Vulnerability{
+				ID:        "CVE-2023-0286",
+				Namespace: "wolfi:rolling",
+				Fix: grypeDB.Fix{
+					Versions: []string{"3.0.8-r0"},
+					State:    grypeDB.FixedState,
+				},
+			},
+			constraint: "< 3.0.8-r0 (apk)",
+		},
+		{
+			name: "wolfi secdb package that was never affected",
+			record: grypeDB.Vulnerability{
+				ID:        "CVE-2023-0464",
+				Namespace: "chainguard:rolling",
+				Fix: grypeDB.Fix{
+					Versions: []string{"0"},
+					State:    grypeDB.FixedState,
+				},
+			},
+			constraint: "< 0 (apk)",
+		},
+		{
+			name: "fixed version is only considered for fix-only data sources",
+			record: grypeDB.Vulnerability{
+				ID:            "RHSA-2021:1024",
+				Namespace:     "rhel:8",
+				VersionFormat: "rpm",
+				Fix: grypeDB.Fix{
+					Versions: []string{"1:1.1.1g-15.el8_3"},
+					State:    grypeDB.FixedState,
+				},
+			},
+			constraint: "none (rpm)",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vuln, err := NewVulnerability(test.record)
+			require.NoError(t, err)
+			assert.Equal(t, test.constraint, vuln.Constraint.String())
+		})
+	}
+}
+
+func TestNewVulnerability_PlatformCPEs(t *testing.T) {
+	vuln, err := NewVulnerability(grypeDB.Vulnerability{
+		ID:                "CVE-2021-fake",
+		Namespace:         "nvd",
+		VersionConstraint: "< 5.1.0",
+		PlatformCPEs: []string{
+			"cpe:2.3:o:microsoft:windows:-:*:*:*:*:*:*:*",
+			"not-a-cpe",
+		},
+	})
+	require.NoError(t, err)
+
+	// invalid CPEs are excluded
+	require.Len(t, vuln.PlatformCPEs, 1)
+	assert.Equal(t, "cpe:2.3:o:microsoft:windows:-:*:*:*:*:*:*:*", vuln.PlatformCPEs[0].BindToFmtString())
+}
@@ -0,0 +1,93 @@
+package watch
+
+import (
+	"time"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/metrics"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// Target is a scanned input whose packages are re-evaluated against every new vulnerability database.
+type Target struct {
+	Input    string
+	Packages []pkg.Package
+	Context  pkg.Context
+}
+
+// Event describes a match that newly appeared for a target.
+type Event struct {
+	Target string `json:"target"`
+	// DBBuilt is when the vulnerability database that introduced the match was built
+	DBBuilt time.Time    `json:"dbBuilt"`
+	Match   models.Match `json:"match"`
+}
+
+// seenKey identifies a match of a target across evaluations.
+type seenKey struct {
+	vulnerability string
+	namespace     string
+	pkg           pkg.ID
+}
+
+// Watcher re-evaluates a set of targets, keeping track of the matches that were already reported.
+type Watcher struct {
+	targets     []Target
+	config      matcher.Config
+	ignoreRules []match.IgnoreRule
+	seen        map[string]map[seenKey]struct{}
+}
+
+// NewWatcher returns a watcher of the given targets, which are matched with the given configuration and ignore rules.
+func NewWatcher(targets []Target, config matcher.Config, ignoreRules []match.IgnoreRule) *Watcher {
+	seen := make(map[string]map[seenKey]struct{})
+	for _, t := range targets {
+		seen[t.Input] = make(map[seenKey]struct{})
+	}
+	return &Watcher{
+		targets:     targets,
+		config:      config,
+		ignoreRules: ignoreRules,
+		seen:        seen,
+	}
+}
+
+// Evaluate matches all targets against the given providers, returning the matches that did not appear in any previous
+// evaluation (on the first evaluation these are all matches).
+func (w *Watcher) Evaluate(provider vulnerability.Provider, metadataProvider vulnerability.MetadataProvider, dbBuilt time.Time) ([]Event, error) {
+	var events []Event
+	for _, t := range w.targets {
+		start := time.Now()
+		allMatches := matcher.FindMatchesWithConfig(provider, t.Context.Distro, w.config, t.Packages...)
+		remainingMatches, _ := match.ApplyIgnoreRules(allMatches, w.ignoreRules)
+
+		newMatches := match.NewMatches()
+		seen := w.seen[t.Input]
+		for m := range remainingMatches.Enumerate() {
+			key := seenKey{
+				vulnerability: m.Vulnerability.ID,
+				namespace:     m.Vulnerability.Namespace,
+				pkg:           m.Package.ID,
+			}
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+			newMatches.Add(m)
+		}
+
+		err := models.EnumerateMatches(t.Packages, newMatches, metadataProvider, func(m models.Match) error {
+			events = append(events, Event{Target: t.Input, DBBuilt: dbBuilt, Match: m})
+			metrics.Matches.With(m.Vulnerability.Severity).Inc()
+			return nil
+		})
+		metrics.ObserveScan(time.Since(start), err)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
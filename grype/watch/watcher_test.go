@@ -0,0 +1,102 @@
+package watch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/distro"
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/grype/version"
+	"github.com/anchore/grype/grype/vulnerability"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+type mockCPEProvider struct {
+	vulns []vulnerability.Vulnerability
+}
+
+func (pr *mockCPEProvider) GetByDistro(*distro.Distro, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (pr *mockCPEProvider) GetByLanguage(syftPkg.Language, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (pr *mockCPEProvider) GetByPackageType(syftPkg.Type, pkg.Package) ([]vulnerability.Vulnerability, error) {
+	return nil, nil
+}
+
+func (pr *mockCPEProvider) GetByCPE(syftPkg.CPE) ([]vulnerability.Vulnerability, error) {
+	return pr.vulns, nil
+}
+
+type mockMetadataProvider struct{}
+
+func (mockMetadataProvider) GetMetadata(id, namespace string) (*vulnerability.Metadata, error) {
+	return &vulnerability.Metadata{ID: id, Namespace: namespace, Severity: "High"}, nil
+}
+
+func TestWatcher_Evaluate(t *testing.T) {
+	cpe, err := syftPkg.NewCPE("cpe:2.3:a:libfoo:libfoo:*:*:*:*:*:*:*:*")
+	require.NoError(t, err)
+
+	newVulnerability := func(id string) vulnerability.Vulnerability {
+		return vulnerability.Vulnerability{
+			Constraint: version.MustGetConstraint("< 2.0.0", version.SemanticFormat),
+			ID:         id,
+			Namespace:  "nvd",
+			CPEs:       []syftPkg.CPE{cpe},
+		}
+	}
+	newTarget := func(input string) Target {
+		return Target{
+			Input: input,
+			Packages: []pkg.Package{{
+				ID:       pkg.ID(uuid.NewString()),
+				Name:     "libfoo",
+				Version:  "1.0.0",
+				Type:     syftPkg.RustPkg,
+				Language: syftPkg.Rust,
+				CPEs:     []syftPkg.CPE{cpe},
+			}},
+		}
+	}
+
+	watcher := NewWatcher(
+		[]Target{newTarget("app:1"), newTarget("app:2")},
+		matcher.Config{Workers: 1},
+		[]match.IgnoreRule{{Vulnerability: "CVE-ignored"}},
+	)
+	built := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	describe := func(events []Event) []string {
+		var result []string
+		for _, e := range events {
+			assert.Equal(t, built, e.DBBuilt)
+			assert.Equal(t, "High", e.Match.Vulnerability.Severity)
+			result = append(result, e.Target+" "+e.Match.Vulnerability.ID+" "+e.Match.Artifact.Name)
+		}
+		return result
+	}
+
+	provider := &mockCPEProvider{vulns: []vulnerability.Vulnerability{newVulnerability("CVE-1")}}
+	events, err := watcher.Evaluate(provider, mockMetadataProvider{}, built)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"app:1 CVE-1 libfoo", "app:2 CVE-1 libfoo"}, describe(events), "first evaluation")
+
+	events, err = watcher.Evaluate(provider, mockMetadataProvider{}, built)
+	require.NoError(t, err)
+	assert.Empty(t, events, "no new vulnerabilities")
+
+	provider.vulns = append(provider.vulns, newVulnerability("CVE-2"), newVulnerability("CVE-ignored"))
+	events, err = watcher.Evaluate(provider, mockMetadataProvider{}, built)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"app:1 CVE-2 libfoo", "app:2 CVE-2 libfoo"}, describe(events), "new vulnerability")
+}
@@ -0,0 +1,32 @@
+package watch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// webhookPayload is the document posted to a webhook for every evaluation with new matches.
+type webhookPayload struct {
+	Events []Event `json:"events"`
+}
+
+// PostEvents posts the given events as a JSON document to the given webhook URL.
+func PostEvents(client *http.Client, url string, events []Event) error {
+	body, err := json.Marshal(webhookPayload{Events: events})
+	if err != nil {
+		return fmt.Errorf("unable to encode events: %w", err)
+	}
+
+	response, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to post events to webhook: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unable to post events to webhook: unexpected status %q", response.Status)
+	}
+	return nil
+}
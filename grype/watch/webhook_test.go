@@ -0,0 +1,39 @@
+package watch
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/presenter/models"
+)
+
+func TestPostEvents(t *testing.T) {
+	var received webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	events := []Event{{Target: "app:1", Match: models.Match{Artifact: models.Package{Name: "libfoo"}}}}
+	require.NoError(t, PostEvents(server.Client(), server.URL, events))
+	require.Len(t, received.Events, 1)
+	assert.Equal(t, "app:1", received.Events[0].Target)
+	assert.Equal(t, "libfoo", received.Events[0].Match.Artifact.Name)
+}
+
+func TestPostEvents_Failure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	assert.Error(t, PostEvents(server.Client(), server.URL, nil))
+}
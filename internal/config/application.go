@@ -6,12 +6,17 @@ import (
 	"path"
 	"reflect"
 	"strings"
+	"time"
 
+	"github.com/anchore/grype/grype/distro"
 	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/pkg"
 
 	"github.com/adrg/xdg"
 	"github.com/anchore/grype/grype/vulnerability"
 	"github.com/anchore/grype/internal"
+	"github.com/anchore/syft/syft/linux"
 	"github.com/mitchellh/go-homedir"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
@@ -20,6 +25,13 @@ import (
 
 var ErrApplicationConfigNotFound = fmt.Errorf("application config not found")
 
+const (
+	// AutoProgress shows the progress of the scan with a TTY UI when stderr is a terminal (otherwise with logs)
+	AutoProgress = "auto"
+	// JSONProgress writes the progress of the scan to stderr as newline-delimited JSON events
+	JSONProgress = "json"
+)
+
 type defaultValueLoader interface {
 	loadDefaultValues(*viper.Viper)
 }
@@ -29,23 +41,67 @@ type parser interface {
 }
 
 type Application struct {
-	ConfigPath         string                  `yaml:",omitempty" json:"configPath"`                                                         // the location where the application config was read from (either from -c or discovered while loading)
-	Output             string                  `yaml:"output" json:"output" mapstructure:"output"`                                           // -o, the Presenter hint string to use for report formatting
-	File               string                  `yaml:"file" json:"file" mapstructure:"file"`                                                 // --file, the file to write report output to
-	OutputTemplateFile string                  `yaml:"output-template-file" json:"output-template-file" mapstructure:"output-template-file"` // -t, the template file to use for formatting the final report
-	Quiet              bool                    `yaml:"quiet" json:"quiet" mapstructure:"quiet"`                                              // -q, indicates to not show any status output to stderr (ETUI or logging UI)
-	CheckForAppUpdate  bool                    `yaml:"check-for-app-update" json:"check-for-app-update" mapstructure:"check-for-app-update"` // whether to check for an application update on start up or not
-	OnlyFixed          bool                    `yaml:"only-fixed" json:"only-fixed" mapstructure:"only-fixed"`                               // only fail if detected vulns have a fix
+	ConfigPath         string                  `yaml:",omitempty" json:"configPath"`                                                               // the location where the application config was read from (either from -c or discovered while loading)
+	Profile            string                  `yaml:"profile,omitempty" json:"profile,omitempty"`                                                 // --profile, the profile of the config that was applied
+	Output             string                  `yaml:"output" json:"output" mapstructure:"output"`                                                 // -o, the Presenter hint string to use for report formatting
+	File               string                  `yaml:"file" json:"file" mapstructure:"file"`                                                       // --file, the file to write report output to
+	OutputTemplateFile string                  `yaml:"output-template-file" json:"output-template-file" mapstructure:"output-template-file"`       // -t, the template file to use for formatting the final report
+	Quiet              bool                    `yaml:"quiet" json:"quiet" mapstructure:"quiet"`                                                    // -q, indicates to not show any status output to stderr (ETUI or logging UI)
+	Progress           string                  `yaml:"progress" json:"progress" mapstructure:"progress"`                                           // --progress, how the progress of the scan is shown on stderr ("auto" or "json")
+	CheckForAppUpdate  bool                    `yaml:"check-for-app-update" json:"check-for-app-update" mapstructure:"check-for-app-update"`       // whether to check for an application update on start up or not
+	OnlyFixed          bool                    `yaml:"only-fixed" json:"only-fixed" mapstructure:"only-fixed"`                                     // only fail if detected vulns have a fix
+	UnknownVersions    string                  `yaml:"unknown-version-policy" json:"unknown-version-policy" mapstructure:"unknown-version-policy"` // --unknown-version-policy, how to handle packages with versions that cannot be parsed
+	CPEStrictness      string                  `yaml:"cpe-strictness" json:"cpe-strictness" mapstructure:"cpe-strictness"`                         // --cpe-strictness, how strictly packages are matched against the CPEs of vulnerabilities
+	Distro             string                  `yaml:"distro" json:"distro" mapstructure:"distro"`                                                 // --distro, the distro to use for matching instead of the detected distro
+	Workers            int                     `yaml:"workers" json:"workers" mapstructure:"workers"`                                              // the number of packages to match concurrently
+	Parallelism        int                     `yaml:"parallelism" json:"parallelism" mapstructure:"parallelism"`                                  // --parallelism, the number of targets to scan concurrently
+	Platform           string                  `yaml:"platform" json:"platform" mapstructure:"platform"`                                           // --platform, the platform of a multi-platform image to scan
+	AllPlatforms       bool                    `yaml:"all-platforms" json:"all-platforms" mapstructure:"all-platforms"`                            // --all-platforms, scan every platform of a multi-platform image
+	ExcludeBaseImage   string                  `yaml:"exclude-base-image" json:"exclude-base-image" mapstructure:"exclude-base-image"`             // --exclude-base-image, ignore matches of packages inherited from the base image
+	DedupPackages      bool                    `yaml:"deduplicate-packages" json:"deduplicate-packages" mapstructure:"deduplicate-packages"`       // --deduplicate-packages, merge packages with the same package URL
+	DistroRelease      *linux.Release          `yaml:"-" json:"-"`
+	Matcher            matcher.Config          `yaml:"-" json:"-"`
+	DedupVulns         bool                    `yaml:"deduplicate-vulnerabilities" json:"deduplicate-vulnerabilities" mapstructure:"deduplicate-vulnerabilities"` // --deduplicate-vulnerabilities, merge the matches of aliased vulnerabilities
 	CliOptions         CliOnlyOptions          `yaml:"-" json:"-"`
+	Match              matchConfig             `yaml:"match" json:"match" mapstructure:"match"`
+	MatcherPlugins     []matcherPlugin         `yaml:"matcher-plugins" json:"matcher-plugins" mapstructure:"matcher-plugins"`
 	Search             search                  `yaml:"search" json:"search" mapstructure:"search"`
 	Ignore             []match.IgnoreRule      `yaml:"ignore" json:"ignore" mapstructure:"ignore"`
+	ImportIgnores      []string                `yaml:"import-ignores" json:"import-ignores" mapstructure:"import-ignores"` // --import-ignores, the suppression files of other scanners to import ignore rules from
+	ImportWarnings     []string                `yaml:"-" json:"-"`
 	Exclusions         []string                `yaml:"exclude" json:"exclude" mapstructure:"exclude"`
+	ExcludePackages    []string                `yaml:"exclude-package" json:"exclude-package" mapstructure:"exclude-package"` // --exclude-package, the packages (by name and type) to exclude from matching
+	PackageExclusions  []pkg.PackageExclusion  `yaml:"-" json:"-"`
+	CPEOverrides       []cpeOverride           `yaml:"cpe-overrides" json:"cpe-overrides" mapstructure:"cpe-overrides"`
+	CPEOverrideRules   []pkg.CPEOverride       `yaml:"-" json:"-"`
+	ExcludeDevDeps     bool                    `yaml:"exclude-dev-dependencies" json:"exclude-dev-dependencies" mapstructure:"exclude-dev-dependencies"` // --exclude-dev-dependencies, exclude the packages that are only needed to develop or test a project
 	DB                 database                `yaml:"db" json:"db" mapstructure:"db"`
 	Dev                development             `yaml:"dev" json:"dev" mapstructure:"dev"`
 	FailOn             string                  `yaml:"fail-on-severity" json:"fail-on-severity" mapstructure:"fail-on-severity"`
 	FailOnSeverity     *vulnerability.Severity `yaml:"-" json:"-"`
+	FailOnEOL          bool                    `yaml:"fail-on-eol" json:"fail-on-eol" mapstructure:"fail-on-eol"`             // --fail-on-eol, fail if the distro has reached the end of life
+	FailOnFixable      bool                    `yaml:"fail-on-fixable" json:"fail-on-fixable" mapstructure:"fail-on-fixable"` // --fail-on-fixable, fail if any vulnerability has a fix
+	PublishedAfter     string                  `yaml:"published-after" json:"published-after" mapstructure:"published-after"` // --published-after, only report vulnerabilities published after the date
+	ModifiedAfter      string                  `yaml:"modified-after" json:"modified-after" mapstructure:"modified-after"`    // --modified-after, only report vulnerabilities modified after the date
+	DateFilter         match.DateFilter        `yaml:"-" json:"-"`
 	Registry           registry                `yaml:"registry" json:"registry" mapstructure:"registry"`
+	Proxy              proxyConfig             `yaml:"proxy" json:"proxy" mapstructure:"proxy"`
+	ExternalSources    externalSources         `yaml:"external-sources" json:"external-sources" mapstructure:"external-sources"`
+	History            history                 `yaml:"history" json:"history" mapstructure:"history"`
+	SBOMCache          sbomCache               `yaml:"sbom-cache" json:"sbom-cache" mapstructure:"sbom-cache"`
+	Archives           archives                `yaml:"archives" json:"archives" mapstructure:"archives"`
+	Attestations       attestations            `yaml:"attestations" json:"attestations" mapstructure:"attestations"`
+	ResultAttestation  resultAttestation       `yaml:"result-attestation" json:"result-attestation" mapstructure:"result-attestation"`
+	Policy             policyConfig            `yaml:"policy" json:"policy" mapstructure:"policy"`
+	Webhooks           webhooks                `yaml:"webhooks" json:"webhooks" mapstructure:"webhooks"`
+	Publish            []string                `yaml:"publish" json:"publish" mapstructure:"publish"` // --publish, the sinks the scan result is published to
+	DependencyTrack    dependencyTrack         `yaml:"dependency-track" json:"dependency-track" mapstructure:"dependency-track"`
+	PublishFile        publishFile             `yaml:"publish-file" json:"publish-file" mapstructure:"publish-file"`
+	PublishS3          publishS3               `yaml:"publish-s3" json:"publish-s3" mapstructure:"publish-s3"`
+	PublishHTTP        publishHTTP             `yaml:"publish-http" json:"publish-http" mapstructure:"publish-http"`
 	Log                logging                 `yaml:"log" json:"log" mapstructure:"log"`
+	Tracing            tracingConfig           `yaml:"tracing" json:"tracing" mapstructure:"tracing"`
+	Table              tableConfig             `yaml:"table" json:"table" mapstructure:"table"`
 }
 
 func newApplicationConfig(v *viper.Viper, cliOpts CliOnlyOptions) *Application {
@@ -65,10 +121,17 @@ func LoadApplicationConfig(v *viper.Viper, cliOpts CliOnlyOptions) (*Application
 		return nil, err
 	}
 
+	if cliOpts.Profile != "" {
+		if err := applyProfile(v, cliOpts.Profile); err != nil {
+			return nil, fmt.Errorf("unable to apply profile: %w", err)
+		}
+	}
+
 	if err := v.Unmarshal(config); err != nil {
 		return nil, fmt.Errorf("unable to parse config: %w", err)
 	}
 	config.ConfigPath = v.ConfigFileUsed()
+	config.Profile = cliOpts.Profile
 
 	if err := config.parseConfigValues(); err != nil {
 		return nil, fmt.Errorf("invalid application config: %w", err)
@@ -82,6 +145,19 @@ func (cfg Application) loadDefaultValues(v *viper.Viper) {
 	// set the default values for primitive fields in this struct
 	v.SetDefault("check-for-app-update", true)
 	v.SetDefault("only-fixed", false)
+	v.SetDefault("fail-on-eol", false)
+	v.SetDefault("fail-on-fixable", false)
+	v.SetDefault("unknown-version-policy", string(matcher.DefaultConfig().UnknownVersionPolicy))
+	v.SetDefault("cpe-strictness", string(matcher.DefaultConfig().CPEStrictness))
+	v.SetDefault("workers", 0)
+	v.SetDefault("parallelism", 2)
+	v.SetDefault("platform", "")
+	v.SetDefault("all-platforms", false)
+	v.SetDefault("deduplicate-packages", false)
+	v.SetDefault("deduplicate-vulnerabilities", false)
+	v.SetDefault("exclude-base-image", "")
+	v.SetDefault("exclude-dev-dependencies", false)
+	v.SetDefault("progress", AutoProgress)
 
 	// for each field in the configuration struct, see if the field implements the defaultValueLoader interface and invoke it if it does
 	value := reflect.ValueOf(cfg)
@@ -99,12 +175,32 @@ func (cfg *Application) parseConfigValues() error {
 	for _, optionFn := range []func() error{
 		cfg.parseLogLevelOption,
 		cfg.parseFailOnOption,
+		cfg.parseUnknownVersionPolicyOption,
+		cfg.parseCPEStrictnessOption,
+		cfg.parseDeduplicateVulnerabilitiesOption,
+		cfg.parseWorkersOption,
+		cfg.parseMatchOption,
+		cfg.parseMatcherPluginsOption,
+		cfg.parseParallelismOption,
+		cfg.parsePlatformOption,
+		cfg.parseDistroOption,
+		cfg.parseExcludePackageOption,
+		cfg.parseCPEOverridesOption,
+		cfg.parseImportIgnoresOption,
+		cfg.parseDateFilterOption,
+		cfg.parsePublishOption,
+		cfg.parseProgressOption,
 	} {
 		if err := optionFn(); err != nil {
 			return err
 		}
 	}
 
+	// note: webhooks given by flags have the default settings
+	for _, u := range cfg.CliOptions.Webhooks {
+		cfg.Webhooks = append(cfg.Webhooks, webhook{URL: u})
+	}
+
 	// parse nested config options
 	// for each field in the configuration struct, see if the field implements the parser interface
 	// note: the app config is a pointer, so we need to grab the elements explicitly (to traverse the address)
@@ -118,6 +214,9 @@ func (cfg *Application) parseConfigValues() error {
 			}
 		}
 	}
+
+	// note: credentials given by flags take precedence over the credentials of env vars and the config file
+	cfg.Registry.prependCredentials(cfg.CliOptions.RegistryAuth)
 	return nil
 }
 
@@ -168,6 +267,153 @@ func (cfg *Application) parseFailOnOption() error {
 	return nil
 }
 
+func (cfg *Application) parseDateFilterOption() error {
+	cfg.DateFilter = match.DateFilter{}
+	for _, option := range []struct {
+		flag  string
+		value string
+		bound **time.Time
+	}{
+		{flag: "published-after", value: cfg.PublishedAfter, bound: &cfg.DateFilter.PublishedAfter},
+		{flag: "modified-after", value: cfg.ModifiedAfter, bound: &cfg.DateFilter.ModifiedAfter},
+	} {
+		if option.value == "" {
+			continue
+		}
+		date, err := parseDate(option.value)
+		if err != nil {
+			return fmt.Errorf("bad --%s value: %w", option.flag, err)
+		}
+		*option.bound = &date
+	}
+	return nil
+}
+
+// parseDate parses a date (e.g. "2024-01-01", which is midnight UTC) or an RFC3339 timestamp.
+func parseDate(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected a date (e.g. 2024-01-01) or an RFC3339 timestamp, got %q", value)
+	}
+	return date, nil
+}
+
+func (cfg *Application) parseUnknownVersionPolicyOption() error {
+	cfg.Matcher = matcher.DefaultConfig()
+	if cfg.UnknownVersions != "" {
+		policy, err := matcher.ParseUnknownVersionPolicy(cfg.UnknownVersions)
+		if err != nil {
+			return fmt.Errorf("bad --unknown-version-policy value: %w", err)
+		}
+		cfg.Matcher.UnknownVersionPolicy = policy
+	}
+	return nil
+}
+
+func (cfg *Application) parseCPEStrictnessOption() error {
+	if cfg.CPEStrictness != "" {
+		strictness, err := matcher.ParseCPEStrictness(cfg.CPEStrictness)
+		if err != nil {
+			return fmt.Errorf("bad --cpe-strictness value: %w", err)
+		}
+		cfg.Matcher.CPEStrictness = strictness
+	}
+	return nil
+}
+
+func (cfg *Application) parseDeduplicateVulnerabilitiesOption() error {
+	cfg.Matcher.MergeAliases = cfg.DedupVulns
+	return nil
+}
+
+func (cfg *Application) parseWorkersOption() error {
+	if cfg.Workers < 0 {
+		return fmt.Errorf("bad workers value: %d (must not be negative)", cfg.Workers)
+	}
+	cfg.Matcher.Workers = cfg.Workers
+	return nil
+}
+
+func (cfg *Application) parseMatchOption() error {
+	matchers, err := cfg.Match.ToConfig()
+	if err != nil {
+		return fmt.Errorf("bad match config: %w", err)
+	}
+	cfg.Matcher.Matchers = matchers
+	return nil
+}
+
+func (cfg *Application) parseParallelismOption() error {
+	if cfg.Parallelism < 1 {
+		return fmt.Errorf("bad parallelism value: %d (must be at least 1)", cfg.Parallelism)
+	}
+	return nil
+}
+
+func (cfg *Application) parseProgressOption() error {
+	switch progress := strings.ToLower(cfg.Progress); progress {
+	case "":
+		cfg.Progress = AutoProgress
+	case AutoProgress, JSONProgress:
+		cfg.Progress = progress
+	default:
+		return fmt.Errorf("bad --progress value %q (options: %q and %q)", cfg.Progress, AutoProgress, JSONProgress)
+	}
+	return nil
+}
+
+func (cfg *Application) parsePlatformOption() error {
+	if cfg.Platform != "" && cfg.AllPlatforms {
+		return fmt.Errorf("cannot select a platform (--platform) and scan all platforms (--all-platforms) together")
+	}
+	return nil
+}
+
+func (cfg *Application) parseDistroOption() error {
+	if cfg.Distro != "" {
+		release, err := distro.ParseRelease(cfg.Distro)
+		if err != nil {
+			return fmt.Errorf("bad --distro value: %w", err)
+		}
+		cfg.DistroRelease = release
+	}
+	return nil
+}
+
+func (cfg *Application) parseExcludePackageOption() error {
+	cfg.PackageExclusions = nil
+	for _, expression := range cfg.ExcludePackages {
+		exclusion, err := pkg.ParsePackageExclusion(expression)
+		if err != nil {
+			return fmt.Errorf("bad --exclude-package value: %w", err)
+		}
+		cfg.PackageExclusions = append(cfg.PackageExclusions, exclusion)
+	}
+	return nil
+}
+
+func (cfg *Application) parsePublishOption() error {
+	for _, sink := range cfg.Publish {
+		if err := cfg.validateSink(sink); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Publishes indicates whether the scan result is published to the given sink.
+func (cfg Application) Publishes(sink string) bool {
+	for _, s := range cfg.Publish {
+		if s == sink {
+			return true
+		}
+	}
+	return false
+}
+
 func (cfg Application) String() string {
 	// yaml is pretty human friendly (at least when compared to json)
 	appCfgStr, err := yaml.Marshal(&cfg)
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/dustin/go-humanize"
+	"github.com/spf13/viper"
+)
+
+type archives struct {
+	Enabled    bool   `yaml:"enabled" json:"enabled" mapstructure:"enabled"`       // --unpack-archives, catalog the contents of the archives within directory and file targets
+	MaxDepth   int    `yaml:"max-depth" json:"max-depth" mapstructure:"max-depth"` // how deep archives within unpacked archives are unpacked
+	MaxSize    string `yaml:"max-size" json:"max-size" mapstructure:"max-size"`    // the maximum size of the unpacked contents of a target (e.g. "1GB")
+	MaxSizeOpt int64  `yaml:"-" json:"-"`
+}
+
+func (cfg archives) loadDefaultValues(v *viper.Viper) {
+	v.SetDefault("archives.enabled", false)
+	v.SetDefault("archives.max-depth", 3)
+	v.SetDefault("archives.max-size", "1GB")
+}
+
+func (cfg *archives) parseConfigValues() error {
+	if cfg.MaxDepth < 1 {
+		return fmt.Errorf("archives max-depth must be at least 1 (got %d)", cfg.MaxDepth)
+	}
+	size, err := humanize.ParseBytes(cfg.MaxSize)
+	if err != nil {
+		return fmt.Errorf("bad archives max-size %q: %w", cfg.MaxSize, err)
+	}
+	if size == 0 {
+		return fmt.Errorf("archives max-size must be greater than 0")
+	}
+	cfg.MaxSizeOpt = int64(size)
+	return nil
+}
+
+func (cfg archives) ToConfig() pkg.ArchiveConfig {
+	return pkg.ArchiveConfig{
+		Enabled:  cfg.Enabled,
+		MaxDepth: cfg.MaxDepth,
+		MaxSize:  cfg.MaxSizeOpt,
+	}
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+)
+
+type attestations struct {
+	Enabled    bool             `yaml:"enabled" json:"enabled" mapstructure:"enabled"`                                                 // --sbom-attestation, scan the signed SBOM attestation of an image instead of the image
+	Key        string           `yaml:"key" json:"key" mapstructure:"key"`                                                             // --key, the public key that signs the attestations
+	Identity   string           `yaml:"certificate-identity" json:"certificate-identity" mapstructure:"certificate-identity"`          // --certificate-identity, the identity of the certificate that signs the attestations (keyless signing)
+	Issuer     string           `yaml:"certificate-oidc-issuer" json:"certificate-oidc-issuer" mapstructure:"certificate-oidc-issuer"` // --certificate-oidc-issuer, the OIDC issuer of the certificate identity
+	CARoots    string           `yaml:"ca-roots" json:"ca-roots" mapstructure:"ca-roots"`                                              // the PEM encoded certificate authorities of the certificates (e.g. the Fulcio root)
+	SkipVerify bool             `yaml:"insecure-skip-verify" json:"insecure-skip-verify" mapstructure:"insecure-skip-verify"`          // --insecure-skip-attestation-verify, scan the attestations of "att:" targets without verifying them
+	KeyOpt     crypto.PublicKey `yaml:"-" json:"-"`
+	CARootOpt  *x509.CertPool   `yaml:"-" json:"-"`
+}
+
+func (cfg attestations) loadDefaultValues(v *viper.Viper) {
+	v.SetDefault("attestations.enabled", false)
+	v.SetDefault("attestations.key", "")
+	v.SetDefault("attestations.certificate-identity", "")
+	v.SetDefault("attestations.certificate-oidc-issuer", "")
+	v.SetDefault("attestations.ca-roots", "")
+	v.SetDefault("attestations.insecure-skip-verify", false)
+}
+
+func (cfg *attestations) parseConfigValues() error {
+	if cfg.Key != "" {
+		contents, err := readConfigFile(cfg.Key)
+		if err != nil {
+			return fmt.Errorf("unable to read attestation key: %w", err)
+		}
+		if cfg.KeyOpt, err = pkg.ParsePublicKey(contents); err != nil {
+			return fmt.Errorf("bad attestation key: %w", err)
+		}
+	}
+
+	if cfg.CARoots != "" {
+		contents, err := readConfigFile(cfg.CARoots)
+		if err != nil {
+			return fmt.Errorf("unable to read attestation CA roots: %w", err)
+		}
+		cfg.CARootOpt = x509.NewCertPool()
+		if !cfg.CARootOpt.AppendCertsFromPEM(contents) {
+			return fmt.Errorf("no PEM encoded certificates found in attestation CA roots %q", cfg.CARoots)
+		}
+	}
+
+	if cfg.Enabled && cfg.KeyOpt == nil && (cfg.Identity == "" || cfg.CARootOpt == nil) {
+		return fmt.Errorf("scanning SBOM attestations requires an attestation key, or a certificate identity and CA roots")
+	}
+	return nil
+}
+
+func (cfg attestations) ToConfig() pkg.AttestationConfig {
+	return pkg.AttestationConfig{
+		Enabled:    cfg.Enabled,
+		Key:        cfg.KeyOpt,
+		Identity:   cfg.Identity,
+		Issuer:     cfg.Issuer,
+		Roots:      cfg.CARootOpt,
+		SkipVerify: cfg.SkipVerify,
+	}
+}
+
+func readConfigFile(path string) ([]byte, error) {
+	expanded, err := homedir.Expand(path)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadFile(expanded)
+}
@@ -2,5 +2,11 @@ package config
 
 type CliOnlyOptions struct {
 	ConfigPath string
-	Verbosity  int
+	// Profile is the name of the profile of the config to apply (see the profiles of the config)
+	Profile   string
+	Verbosity int
+	// RegistryAuth are the registry credentials given by flags (which take precedence over the configured credentials)
+	RegistryAuth RegistryCredentials
+	// Webhooks are the URLs of the webhooks given by flags (in addition to the configured webhooks)
+	Webhooks []string
 }
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/bmatcuk/doublestar/v2"
+
+	"github.com/anchore/grype/grype/pkg"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+// cpeOverride changes the CPEs of the packages that match the package criteria (see pkg.CPEOverride).
+type cpeOverride struct {
+	Package cpeOverridePackage `yaml:"package" json:"package" mapstructure:"package"`
+	Replace []string           `yaml:"replace" json:"replace" mapstructure:"replace"` // the CPEs that replace every CPE of the package
+	Remove  []string           `yaml:"remove" json:"remove" mapstructure:"remove"`    // the patterns of the CPEs to remove from the package
+	Add     []string           `yaml:"add" json:"add" mapstructure:"add"`             // the CPEs to add to the package
+}
+
+// cpeOverridePackage are the glob expressions of the packages that an override applies to.
+type cpeOverridePackage struct {
+	Name string `yaml:"name" json:"name" mapstructure:"name"`
+	Type string `yaml:"type" json:"type" mapstructure:"type"`
+	PURL string `yaml:"purl" json:"purl" mapstructure:"purl"`
+}
+
+func (cfg *Application) parseCPEOverridesOption() error {
+	cfg.CPEOverrideRules = nil
+	for i, o := range cfg.CPEOverrides {
+		override, err := o.toOverride()
+		if err != nil {
+			return fmt.Errorf("bad CPE override #%d: %w", i+1, err)
+		}
+		cfg.CPEOverrideRules = append(cfg.CPEOverrideRules, override)
+	}
+	return nil
+}
+
+func (o cpeOverride) toOverride() (pkg.CPEOverride, error) {
+	if o.Package.Name == "" && o.Package.Type == "" && o.Package.PURL == "" {
+		return pkg.CPEOverride{}, fmt.Errorf("a package name, type, or purl is required")
+	}
+	if len(o.Replace) == 0 && len(o.Remove) == 0 && len(o.Add) == 0 {
+		return pkg.CPEOverride{}, fmt.Errorf("CPEs to replace, remove, or add are required")
+	}
+	for _, pattern := range []string{o.Package.Name, o.Package.Type, o.Package.PURL} {
+		// note: a bad pattern is only reported when the matching reaches it, so each pattern is matched against itself
+		if _, err := doublestar.Match(pattern, pattern); err != nil {
+			return pkg.CPEOverride{}, fmt.Errorf("bad package pattern %q: %w", pattern, err)
+		}
+	}
+
+	override := pkg.CPEOverride{
+		Name: o.Package.Name,
+		Type: o.Package.Type,
+		PURL: o.Package.PURL,
+	}
+	var err error
+	if override.Replace, err = parseCPEs(o.Replace); err != nil {
+		return pkg.CPEOverride{}, err
+	}
+	if override.Remove, err = parseCPEs(o.Remove); err != nil {
+		return pkg.CPEOverride{}, err
+	}
+	if override.Add, err = parseCPEs(o.Add); err != nil {
+		return pkg.CPEOverride{}, err
+	}
+	return override, nil
+}
+
+func parseCPEs(values []string) ([]syftPkg.CPE, error) {
+	var cpes []syftPkg.CPE
+	for _, v := range values {
+		c, err := syftPkg.NewCPE(v)
+		if err != nil {
+			return nil, fmt.Errorf("bad CPE %q: %w", v, err)
+		}
+		cpes = append(cpes, c)
+	}
+	return cpes, nil
+}
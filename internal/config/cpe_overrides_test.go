@@ -0,0 +1,72 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/pkg"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestApplication_parseCPEOverridesOption(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides []cpeOverride
+		expected  []pkg.CPEOverride
+		wantErr   bool
+	}{
+		{
+			name: "no overrides",
+		},
+		{
+			name: "overrides",
+			overrides: []cpeOverride{
+				{Package: cpeOverridePackage{Name: "jq", Type: "npm"}, Remove: []string{"cpe:2.3:a:*:jq:*:*:*:*:*:*:*:*"}},
+				{Package: cpeOverridePackage{PURL: "pkg:generic/acme-openssl@*"}, Replace: []string{"cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*"}, Add: []string{"cpe:2.3:a:acme:openssl:*:*:*:*:*:*:*:*"}},
+			},
+			expected: []pkg.CPEOverride{
+				{Name: "jq", Type: "npm", Remove: []syftPkg.CPE{syftPkg.MustCPE("cpe:2.3:a:*:jq:*:*:*:*:*:*:*:*")}},
+				{
+					PURL:    "pkg:generic/acme-openssl@*",
+					Replace: []syftPkg.CPE{syftPkg.MustCPE("cpe:2.3:a:openssl:openssl:*:*:*:*:*:*:*:*")},
+					Add:     []syftPkg.CPE{syftPkg.MustCPE("cpe:2.3:a:acme:openssl:*:*:*:*:*:*:*:*")},
+				},
+			},
+		},
+		{
+			name:      "without package criteria",
+			overrides: []cpeOverride{{Remove: []string{"cpe:2.3:a:*:jq:*:*:*:*:*:*:*:*"}}},
+			wantErr:   true,
+		},
+		{
+			name:      "without CPEs",
+			overrides: []cpeOverride{{Package: cpeOverridePackage{Name: "jq"}}},
+			wantErr:   true,
+		},
+		{
+			name:      "bad CPE",
+			overrides: []cpeOverride{{Package: cpeOverridePackage{Name: "jq"}, Add: []string{"jq"}}},
+			wantErr:   true,
+		},
+		{
+			name:      "bad package pattern",
+			overrides: []cpeOverride{{Package: cpeOverridePackage{Name: "[jq"}, Add: []string{"cpe:2.3:a:jq:jq:*:*:*:*:*:*:*:*"}}},
+			wantErr:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := Application{CPEOverrides: test.overrides}
+			err := cfg.parseCPEOverridesOption()
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, cfg.CPEOverrideRules)
+		})
+	}
+}
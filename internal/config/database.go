@@ -1,28 +1,118 @@
 package config
 
 import (
+	"fmt"
 	"path"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/adrg/xdg"
 	"github.com/anchore/grype/grype/db"
+	"github.com/anchore/grype/grype/distro"
 	"github.com/anchore/grype/internal"
 	"github.com/spf13/viper"
 )
 
 type database struct {
-	Dir                   string `yaml:"cache-dir" json:"cache-dir" mapstructure:"cache-dir"`
-	UpdateURL             string `yaml:"update-url" json:"update-url" mapstructure:"update-url"`
-	CACert                string `yaml:"ca-cert" json:"ca-cert" mapstructure:"ca-cert"`
-	AutoUpdate            bool   `yaml:"auto-update" json:"auto-update" mapstructure:"auto-update"`
-	ValidateByHashOnStart bool   `yaml:"validate-by-hash-on-start" json:"validate-by-hash-on-start" mapstructure:"validate-by-hash-on-start"`
+	Dir                   string             `yaml:"cache-dir" json:"cache-dir" mapstructure:"cache-dir"`
+	UpdateURL             string             `yaml:"update-url" json:"update-url" mapstructure:"update-url"`
+	CACert                string             `yaml:"ca-cert" json:"ca-cert" mapstructure:"ca-cert"`
+	ClientCert            string             `yaml:"client-cert" json:"client-cert" mapstructure:"client-cert"`
+	ClientKey             string             `yaml:"client-key" json:"client-key" mapstructure:"client-key"`
+	Proxy                 string             `yaml:"proxy" json:"proxy" mapstructure:"proxy"`
+	AutoUpdate            bool               `yaml:"auto-update" json:"auto-update" mapstructure:"auto-update"`
+	ValidateByHashOnStart bool               `yaml:"validate-by-hash-on-start" json:"validate-by-hash-on-start" mapstructure:"validate-by-hash-on-start"`
+	DistroAliases         []string           `yaml:"distro-aliases" json:"distro-aliases" mapstructure:"distro-aliases"`
+	DistroAliasRules      []distro.AliasRule `yaml:"-" json:"-"`
+	Overlays              []string           `yaml:"overlays" json:"overlays" mapstructure:"overlays"`
+	SigningKey            string             `yaml:"signing-key" json:"signing-key" mapstructure:"signing-key"`
+	RequireSigned         bool               `yaml:"require-signed" json:"require-signed" mapstructure:"require-signed"`
+	MaxAllowedAge         string             `yaml:"max-allowed-age" json:"max-allowed-age" mapstructure:"max-allowed-age"`
+	MaxAllowedAgeDuration time.Duration      `yaml:"-" json:"-"`
+	StalePolicy           string             `yaml:"stale-policy" json:"stale-policy" mapstructure:"stale-policy"`
+	CacheSize             int                `yaml:"cache-size" json:"cache-size" mapstructure:"cache-size"`
+}
+
+const (
+	// FailStalePolicy fails the scan when the database is older than the max allowed age
+	FailStalePolicy = "fail"
+	// WarnStalePolicy only warns when the database is older than the max allowed age
+	WarnStalePolicy = "warn"
+)
+
+func (cfg *database) parseConfigValues() error {
+	cfg.DistroAliasRules = make([]distro.AliasRule, 0, len(cfg.DistroAliases))
+	for _, value := range cfg.DistroAliases {
+		rule, err := distro.ParseAliasRule(value)
+		if err != nil {
+			return fmt.Errorf("bad db.distro-aliases value: %w", err)
+		}
+		cfg.DistroAliasRules = append(cfg.DistroAliasRules, rule)
+	}
+
+	maxAge, err := parseAge(cfg.MaxAllowedAge)
+	if err != nil {
+		return fmt.Errorf("bad db.max-allowed-age value: %w", err)
+	}
+	cfg.MaxAllowedAgeDuration = maxAge
+
+	switch cfg.StalePolicy {
+	case FailStalePolicy, WarnStalePolicy:
+	default:
+		return fmt.Errorf("bad db.stale-policy value %q (options: %s, %s)", cfg.StalePolicy, FailStalePolicy, WarnStalePolicy)
+	}
+
+	if cfg.CacheSize < 0 {
+		return fmt.Errorf("bad db.cache-size value: %d (must not be negative)", cfg.CacheSize)
+	}
+	return nil
+}
+
+// parseAge parses an age in days (e.g. "5d") or as a duration (e.g. "36h"), where an empty value is no age.
+func parseAge(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "" || value == "0":
+		return 0, nil
+	case strings.HasSuffix(value, "d"):
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err != nil || days < 0 {
+			return 0, fmt.Errorf("invalid number of days %q", value)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if age < 0 {
+		return 0, fmt.Errorf("negative age %q", value)
+	}
+	return age, nil
 }
 
 func (cfg database) loadDefaultValues(v *viper.Viper) {
 	v.SetDefault("db.cache-dir", path.Join(xdg.CacheHome, internal.ApplicationName, "db"))
 	v.SetDefault("db.update-url", internal.DBUpdateURL)
 	v.SetDefault("db.ca-cert", "")
+	v.SetDefault("db.client-cert", "")
+	v.SetDefault("db.client-key", "")
+	v.SetDefault("db.proxy", "")
 	v.SetDefault("db.auto-update", true)
 	v.SetDefault("db.validate-by-hash-on-start", false)
+
+	var aliases []string
+	for _, rule := range distro.DefaultAliasRules {
+		aliases = append(aliases, rule.String())
+	}
+	v.SetDefault("db.distro-aliases", aliases)
+	v.SetDefault("db.overlays", []string{})
+	v.SetDefault("db.signing-key", "")
+	v.SetDefault("db.require-signed", false)
+	v.SetDefault("db.max-allowed-age", "")
+	v.SetDefault("db.stale-policy", FailStalePolicy)
+	v.SetDefault("db.cache-size", db.DefaultCacheSize)
 }
 
 func (cfg database) ToCuratorConfig() db.Config {
@@ -30,6 +120,14 @@ func (cfg database) ToCuratorConfig() db.Config {
 		DBRootDir:           cfg.Dir,
 		ListingURL:          cfg.UpdateURL,
 		CACert:              cfg.CACert,
+		ClientCert:          cfg.ClientCert,
+		ClientKey:           cfg.ClientKey,
+		Proxy:               cfg.Proxy,
 		ValidateByHashOnGet: cfg.ValidateByHashOnStart,
+		DistroAliases:       cfg.DistroAliasRules,
+		Overlays:            cfg.Overlays,
+		SigningKey:          cfg.SigningKey,
+		RequireSignature:    cfg.RequireSigned,
+		CacheSize:           cfg.CacheSize,
 	}
 }
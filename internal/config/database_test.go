@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		err      bool
+	}{
+		{value: "", expected: 0},
+		{value: "0", expected: 0},
+		{value: "5d", expected: 5 * 24 * time.Hour},
+		{value: "36h", expected: 36 * time.Hour},
+		{value: "-5d", err: true},
+		{value: "-1h", err: true},
+		{value: "five days", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			actual, err := parseAge(test.value)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
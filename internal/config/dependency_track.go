@@ -0,0 +1,55 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/dependencytrack"
+	"github.com/spf13/viper"
+)
+
+type dependencyTrack struct {
+	URL string `yaml:"url" json:"url" mapstructure:"url"` // the URL of the API server of the Dependency-Track instance
+	// IMPORTANT: do not show the API key in any YAML/JSON output (sensitive information)
+	APIKey         string `yaml:"-" json:"-" mapstructure:"api-key"`
+	ProjectUUID    string `yaml:"project-uuid" json:"project-uuid" mapstructure:"project-uuid"`          // the UUID of the project to upload to
+	ProjectName    string `yaml:"project-name" json:"project-name" mapstructure:"project-name"`          // the name of the project to upload to (instead of the UUID)
+	ProjectVersion string `yaml:"project-version" json:"project-version" mapstructure:"project-version"` // the version of the project to upload to (instead of the UUID)
+	AutoCreate     bool   `yaml:"auto-create" json:"auto-create" mapstructure:"auto-create"`             // create the project (by name and version) when it does not exist
+}
+
+func (cfg dependencyTrack) loadDefaultValues(v *viper.Viper) {
+	v.SetDefault("dependency-track.url", "")
+	v.SetDefault("dependency-track.api-key", "")
+	v.SetDefault("dependency-track.project-uuid", "")
+	v.SetDefault("dependency-track.project-name", "")
+	v.SetDefault("dependency-track.project-version", "")
+	v.SetDefault("dependency-track.auto-create", true)
+}
+
+// validate checks the config is complete enough to publish to Dependency-Track (which is only checked when publishing).
+func (cfg dependencyTrack) validate() error {
+	if cfg.URL == "" {
+		return fmt.Errorf("publishing to Dependency-Track requires the URL of the instance (dependency-track.url)")
+	}
+	if cfg.APIKey == "" {
+		return fmt.Errorf("publishing to Dependency-Track requires an API key (dependency-track.api-key)")
+	}
+	if (cfg.ProjectName == "") != (cfg.ProjectVersion == "") {
+		return fmt.Errorf("the Dependency-Track project name and version must be given together")
+	}
+	return nil
+}
+
+// HasProject indicates whether a project is configured (otherwise the project is named after the scanned image).
+func (cfg dependencyTrack) HasProject() bool {
+	return cfg.ProjectUUID != "" || cfg.ProjectName != ""
+}
+
+func (cfg dependencyTrack) ToProject() dependencytrack.Project {
+	return dependencytrack.Project{
+		UUID:       cfg.ProjectUUID,
+		Name:       cfg.ProjectName,
+		Version:    cfg.ProjectVersion,
+		AutoCreate: cfg.AutoCreate,
+	}
+}
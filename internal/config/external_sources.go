@@ -0,0 +1,70 @@
+package config
+
+import (
+	"os"
+	"path"
+	"time"
+
+	"github.com/adrg/xdg"
+	"github.com/anchore/grype/grype/db/ghsa"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/internal"
+	"github.com/spf13/viper"
+)
+
+type externalSources struct {
+	Enable bool   `yaml:"enable" json:"enable" mapstructure:"enable"`
+	Maven  maven  `yaml:"maven" json:"maven" mapstructure:"maven"`
+	GitHub github `yaml:"github" json:"github" mapstructure:"github"`
+}
+
+type maven struct {
+	SearchUpstreamBySha1 bool          `yaml:"search-upstream-by-sha1" json:"search-upstream-by-sha1" mapstructure:"search-upstream-by-sha1"`
+	BaseURL              string        `yaml:"base-url" json:"base-url" mapstructure:"base-url"`
+	Timeout              time.Duration `yaml:"timeout" json:"timeout" mapstructure:"timeout"`
+	CacheDir             string        `yaml:"cache-dir" json:"cache-dir" mapstructure:"cache-dir"`
+}
+
+type github struct {
+	SearchAdvisories bool   `yaml:"search-advisories" json:"search-advisories" mapstructure:"search-advisories"`
+	BaseURL          string `yaml:"base-url" json:"base-url" mapstructure:"base-url"`
+	// IMPORTANT: do not show the token in any YAML/JSON output (sensitive information)
+	Token    string        `yaml:"-" json:"-" mapstructure:"token"`
+	Timeout  time.Duration `yaml:"timeout" json:"timeout" mapstructure:"timeout"`
+	CacheDir string        `yaml:"cache-dir" json:"cache-dir" mapstructure:"cache-dir"`
+	CacheTTL time.Duration `yaml:"cache-ttl" json:"cache-ttl" mapstructure:"cache-ttl"`
+}
+
+func (cfg externalSources) loadDefaultValues(v *viper.Viper) {
+	v.SetDefault("external-sources.enable", false)
+	v.SetDefault("external-sources.maven.search-upstream-by-sha1", true)
+	v.SetDefault("external-sources.maven.base-url", pkg.DefaultMavenSearchURL)
+	v.SetDefault("external-sources.maven.timeout", 10*time.Second)
+	v.SetDefault("external-sources.maven.cache-dir", path.Join(xdg.CacheHome, internal.ApplicationName, "maven"))
+	v.SetDefault("external-sources.github.search-advisories", true)
+	v.SetDefault("external-sources.github.base-url", ghsa.DefaultURL)
+	v.SetDefault("external-sources.github.token", os.Getenv("GITHUB_TOKEN"))
+	v.SetDefault("external-sources.github.timeout", 10*time.Second)
+	v.SetDefault("external-sources.github.cache-dir", path.Join(xdg.CacheHome, internal.ApplicationName, "github"))
+	v.SetDefault("external-sources.github.cache-ttl", 24*time.Hour)
+}
+
+func (cfg externalSources) ToMavenSearchConfig() pkg.MavenSearchConfig {
+	return pkg.MavenSearchConfig{
+		Enabled:  cfg.Enable && cfg.Maven.SearchUpstreamBySha1,
+		BaseURL:  cfg.Maven.BaseURL,
+		Timeout:  cfg.Maven.Timeout,
+		CacheDir: cfg.Maven.CacheDir,
+	}
+}
+
+func (cfg externalSources) ToGitHubAdvisoriesConfig() ghsa.Config {
+	return ghsa.Config{
+		Enabled:  cfg.Enable && cfg.GitHub.SearchAdvisories,
+		BaseURL:  cfg.GitHub.BaseURL,
+		Token:    cfg.GitHub.Token,
+		Timeout:  cfg.GitHub.Timeout,
+		CacheDir: cfg.GitHub.CacheDir,
+		CacheTTL: cfg.GitHub.CacheTTL,
+	}
+}
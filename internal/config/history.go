@@ -0,0 +1,19 @@
+package config
+
+import (
+	"path"
+
+	"github.com/adrg/xdg"
+	"github.com/anchore/grype/internal"
+	"github.com/spf13/viper"
+)
+
+type history struct {
+	Enabled bool   `yaml:"enabled" json:"enabled" mapstructure:"enabled"` // record a summary of every scan into the history
+	Path    string `yaml:"path" json:"path" mapstructure:"path"`          // the location of the history DB
+}
+
+func (cfg history) loadDefaultValues(v *viper.Viper) {
+	v.SetDefault("history.enabled", false)
+	v.SetDefault("history.path", path.Join(xdg.DataHome, internal.ApplicationName, "history.db"))
+}
@@ -0,0 +1,387 @@
+package config
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/anchore/grype/grype/match"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+const (
+	TrivyIgnoreFormat           = "trivy"
+	DependencyCheckIgnoreFormat = "dependency-check"
+	OSVScannerIgnoreFormat      = "osv-scanner"
+)
+
+// IgnoreImportFormats are the formats of the suppression files that ignore rules can be imported from.
+var IgnoreImportFormats = []string{TrivyIgnoreFormat, DependencyCheckIgnoreFormat, OSVScannerIgnoreFormat}
+
+// osvEcosystems are the package types of the OSV ecosystems of packages in OSV-Scanner overrides.
+var osvEcosystems = map[string]syftPkg.Type{
+	"Alpine":    syftPkg.ApkPkg,
+	"crates.io": syftPkg.RustPkg,
+	"Debian":    syftPkg.DebPkg,
+	"Go":        syftPkg.GoModulePkg,
+	"Maven":     syftPkg.JavaPkg,
+	"npm":       syftPkg.NpmPkg,
+	"Packagist": syftPkg.PhpComposerPkg,
+	"PyPI":      syftPkg.PythonPkg,
+	"RubyGems":  syftPkg.GemPkg,
+}
+
+// parseImportIgnoresOption converts the suppressions of the suppression files of other scanners into ignore rules (in
+// addition to the configured ignore rules). Suppressions that cannot be expressed as ignore rules, or that have expired,
+// are skipped with a warning.
+func (cfg *Application) parseImportIgnoresOption() error {
+	cfg.ImportWarnings = nil
+	now := time.Now()
+	for _, value := range cfg.ImportIgnores {
+		format, path := ignoreImportFormat(value)
+		if format == "" {
+			return fmt.Errorf("unable to determine the format of suppression file %q, prefix it with one of %v (e.g. 'trivy:%s')", path, IgnoreImportFormats, path)
+		}
+
+		rules, warnings, err := importIgnoreFile(format, path, now)
+		if err != nil {
+			return fmt.Errorf("unable to import %s suppression file %q: %w", format, path, err)
+		}
+		cfg.Ignore = append(cfg.Ignore, rules...)
+		for _, w := range warnings {
+			cfg.ImportWarnings = append(cfg.ImportWarnings, fmt.Sprintf("%s: %s", path, w))
+		}
+	}
+	return nil
+}
+
+// ignoreImportFormat returns the format and path of a suppression file, given as an optional "<format>:" prefix with the
+// path, where the format is otherwise determined by the name of the file (or empty when it cannot be determined).
+func ignoreImportFormat(value string) (string, string) {
+	for _, f := range IgnoreImportFormats {
+		if strings.HasPrefix(value, f+":") {
+			return f, strings.TrimPrefix(value, f+":")
+		}
+	}
+
+	name := strings.ToLower(filepath.Base(value))
+	switch {
+	case strings.HasPrefix(name, ".trivyignore"):
+		return TrivyIgnoreFormat, value
+	case strings.HasSuffix(name, ".xml"):
+		return DependencyCheckIgnoreFormat, value
+	case strings.HasSuffix(name, ".toml"):
+		return OSVScannerIgnoreFormat, value
+	}
+	return "", value
+}
+
+func importIgnoreFile(format, path string, now time.Time) ([]match.IgnoreRule, []string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	reason := fmt.Sprintf("imported from %s", path)
+	switch format {
+	case TrivyIgnoreFormat:
+		return importTrivyIgnore(f, reason, now)
+	case DependencyCheckIgnoreFormat:
+		return importDependencyCheckSuppressions(f, reason, now)
+	case OSVScannerIgnoreFormat:
+		return importOSVScannerConfig(f, reason, now)
+	}
+	return nil, nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// importTrivyIgnore converts the vulnerability IDs of a .trivyignore file (one on each line, with "#" comments and an
+// optional "exp:YYYY-MM-DD" expiry date) into ignore rules.
+func importTrivyIgnore(reader io.Reader, reason string, now time.Time) ([]match.IgnoreRule, []string, error) {
+	var rules []match.IgnoreRule
+	var warnings []string
+	scanner := bufio.NewScanner(reader)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		expired := false
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "exp:") {
+				continue
+			}
+			until, err := time.Parse("2006-01-02", strings.TrimPrefix(field, "exp:"))
+			if err != nil {
+				return nil, nil, fmt.Errorf("bad expiry date on line %d: %w", line, err)
+			}
+			expired = now.After(until)
+		}
+		if expired {
+			warnings = append(warnings, fmt.Sprintf("skipping expired suppression of %s on line %d", fields[0], line))
+			continue
+		}
+
+		rules = append(rules, match.IgnoreRule{Vulnerability: fields[0], Reason: reason})
+	}
+	return rules, warnings, scanner.Err()
+}
+
+type dependencyCheckSuppressions struct {
+	Suppress []dependencyCheckSuppression `xml:"suppress"`
+}
+
+type dependencyCheckSuppression struct {
+	Until             string                   `xml:"until,attr"`
+	Notes             string                   `xml:"notes"`
+	PackageURL        *dependencyCheckMatcher  `xml:"packageUrl"`
+	FilePath          *dependencyCheckMatcher  `xml:"filePath"`
+	SHA1              string                   `xml:"sha1"`
+	GAV               *dependencyCheckMatcher  `xml:"gav"`
+	CPE               []dependencyCheckMatcher `xml:"cpe"`
+	CVE               []string                 `xml:"cve"`
+	VulnerabilityName []dependencyCheckMatcher `xml:"vulnerabilityName"`
+	CWE               []string                 `xml:"cwe"`
+	CVSSBelow         []string                 `xml:"cvssBelow"`
+}
+
+// dependencyCheckMatcher is a value of a suppression, which is either matched exactly or as a regular expression.
+type dependencyCheckMatcher struct {
+	Value string `xml:",chardata"`
+	Regex bool   `xml:"regex,attr"`
+}
+
+// importDependencyCheckSuppressions converts the suppressions of a Dependency-Check suppression file into ignore rules,
+// one for each suppressed vulnerability (or a single rule for every vulnerability of the suppressed package).
+// Suppressions by CPE, CWE, CVSS score, hash, or Maven coordinates cannot be expressed as ignore rules, nor can regular
+// expressions that do not translate to glob expressions, so these suppressions are skipped.
+func importDependencyCheckSuppressions(reader io.Reader, reason string, now time.Time) ([]match.IgnoreRule, []string, error) {
+	var doc dependencyCheckSuppressions
+	if err := xml.NewDecoder(reader).Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse suppressions: %w", err)
+	}
+
+	var rules []match.IgnoreRule
+	var warnings []string
+	for i, s := range doc.Suppress {
+		suppressionRules, err := s.toIgnoreRules(reason, now)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("skipping suppression #%d: %v", i+1, err))
+			continue
+		}
+		rules = append(rules, suppressionRules...)
+	}
+	return rules, warnings, nil
+}
+
+func (s dependencyCheckSuppression) toIgnoreRules(reason string, now time.Time) ([]match.IgnoreRule, error) {
+	if s.Until != "" {
+		until, err := parseXMLDate(s.Until)
+		if err != nil {
+			return nil, fmt.Errorf("bad until date %q: %w", s.Until, err)
+		}
+		if now.After(until) {
+			return nil, fmt.Errorf("expired on %s", s.Until)
+		}
+	}
+
+	switch {
+	case len(s.CPE) > 0:
+		return nil, fmt.Errorf("suppressions by CPE are not supported")
+	case len(s.CWE) > 0:
+		return nil, fmt.Errorf("suppressions by CWE are not supported")
+	case len(s.CVSSBelow) > 0:
+		return nil, fmt.Errorf("suppressions by CVSS score are not supported")
+	case s.SHA1 != "":
+		return nil, fmt.Errorf("suppressions by SHA1 hash are not supported")
+	case s.GAV != nil:
+		return nil, fmt.Errorf("suppressions by Maven coordinates are not supported")
+	}
+
+	if notes := strings.TrimSpace(s.Notes); notes != "" {
+		reason = notes
+	}
+	rule := match.IgnoreRule{Reason: reason}
+	if s.PackageURL != nil {
+		purl, err := s.PackageURL.glob()
+		if err != nil {
+			return nil, err
+		}
+		rule.Package.PURL = purl
+	}
+	if s.FilePath != nil {
+		location, err := s.FilePath.glob()
+		if err != nil {
+			return nil, err
+		}
+		rule.Package.Location = location
+	}
+
+	var vulnerabilities []string
+	for _, cve := range s.CVE {
+		vulnerabilities = append(vulnerabilities, strings.TrimSpace(cve))
+	}
+	for _, name := range s.VulnerabilityName {
+		if name.Regex {
+			return nil, fmt.Errorf("vulnerability names by regular expression are not supported")
+		}
+		vulnerabilities = append(vulnerabilities, strings.TrimSpace(name.Value))
+	}
+
+	if len(vulnerabilities) == 0 {
+		if rule.Package.PURL == "" && rule.Package.Location == "" {
+			return nil, fmt.Errorf("no supported suppression criteria")
+		}
+		// note: the suppression applies to every vulnerability of the package
+		return []match.IgnoreRule{rule}, nil
+	}
+
+	var rules []match.IgnoreRule
+	for _, v := range vulnerabilities {
+		r := rule
+		r.Vulnerability = v
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// glob returns the value of the matcher as a glob expression, translating simple regular expressions (of literal
+// characters and ".*" wildcards, optionally anchored).
+func (m dependencyCheckMatcher) glob() (string, error) {
+	value := strings.TrimSpace(m.Value)
+	if !m.Regex {
+		return value, nil
+	}
+
+	expr := strings.TrimSuffix(strings.TrimPrefix(value, "^"), "$")
+	var glob strings.Builder
+	for i := 0; i < len(expr); i++ {
+		c := expr[i]
+		switch {
+		case c == '\\' && i+1 < len(expr) && strings.IndexByte(".-/@:_+", expr[i+1]) >= 0:
+			glob.WriteByte(expr[i+1])
+			i++
+		case c == '.' && i+1 < len(expr) && expr[i+1] == '*':
+			glob.WriteByte('*')
+			i++
+		case c == '.':
+			glob.WriteByte('?')
+		case strings.IndexByte(`\^$*+?()[]{}|`, c) >= 0:
+			return "", fmt.Errorf("unsupported regular expression %q", value)
+		default:
+			glob.WriteByte(c)
+		}
+	}
+	return glob.String(), nil
+}
+
+// parseXMLDate parses an xs:date value, which has an optional time zone (e.g. "2020-01-01Z" or "2020-01-01+02:00").
+func parseXMLDate(value string) (time.Time, error) {
+	var err error
+	for _, layout := range []string{"2006-01-02Z07:00", "2006-01-02"} {
+		var t time.Time
+		if t, err = time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+type osvScannerConfig struct {
+	IgnoredVulns     []osvIgnoredVuln     `toml:"IgnoredVulns"`
+	PackageOverrides []osvPackageOverride `toml:"PackageOverrides"`
+}
+
+type osvIgnoredVuln struct {
+	ID          string    `toml:"id"`
+	IgnoreUntil time.Time `toml:"ignoreUntil"`
+	Reason      string    `toml:"reason"`
+}
+
+type osvPackageOverride struct {
+	Name           string    `toml:"name"`
+	Version        string    `toml:"version"`
+	Ecosystem      string    `toml:"ecosystem"`
+	Ignore         bool      `toml:"ignore"`
+	EffectiveUntil time.Time `toml:"effectiveUntil"`
+	Reason         string    `toml:"reason"`
+	Vulnerability  struct {
+		Ignore bool `toml:"ignore"`
+	} `toml:"vulnerability"`
+}
+
+// importOSVScannerConfig converts the ignored vulnerabilities and the ignored packages (of the package overrides) of an
+// OSV-Scanner config file into ignore rules.
+func importOSVScannerConfig(reader io.Reader, reason string, now time.Time) ([]match.IgnoreRule, []string, error) {
+	var doc osvScannerConfig
+	if _, err := toml.NewDecoder(reader).Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("unable to parse config: %w", err)
+	}
+
+	withReason := func(r string) string {
+		if r = strings.TrimSpace(r); r != "" {
+			return r
+		}
+		return reason
+	}
+
+	var rules []match.IgnoreRule
+	var warnings []string
+	for _, v := range doc.IgnoredVulns {
+		if v.ID == "" {
+			continue
+		}
+		if !v.IgnoreUntil.IsZero() && now.After(v.IgnoreUntil) {
+			warnings = append(warnings, fmt.Sprintf("skipping expired suppression of %s", v.ID))
+			continue
+		}
+		rules = append(rules, match.IgnoreRule{Vulnerability: v.ID, Reason: withReason(v.Reason)})
+	}
+
+	for i, o := range doc.PackageOverrides {
+		if !o.Ignore && !o.Vulnerability.Ignore {
+			// note: other overrides (e.g. of licenses) do not affect vulnerabilities
+			continue
+		}
+		if !o.EffectiveUntil.IsZero() && now.After(o.EffectiveUntil) {
+			warnings = append(warnings, fmt.Sprintf("skipping expired package override #%d", i+1))
+			continue
+		}
+
+		rule := match.IgnoreRule{
+			Package: match.IgnoreRulePackage{Version: o.Version},
+			Reason:  withReason(o.Reason),
+		}
+		if o.Ecosystem != "" {
+			t, ok := osvEcosystems[o.Ecosystem]
+			if !ok {
+				warnings = append(warnings, fmt.Sprintf("skipping package override #%d: unsupported ecosystem %q", i+1, o.Ecosystem))
+				continue
+			}
+			rule.Package.Type = string(t)
+		}
+		rule.Package.Name = o.Name
+		if rule.Package.Type == string(syftPkg.JavaPkg) {
+			// note: Maven packages are named by "<group>:<artifact>", where java packages are named by the artifact
+			rule.Package.Name = o.Name[strings.LastIndex(o.Name, ":")+1:]
+		}
+		if rule.Package.Name == "" && rule.Package.Type == "" {
+			warnings = append(warnings, fmt.Sprintf("skipping package override #%d: no package name or ecosystem", i+1))
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, warnings, nil
+}
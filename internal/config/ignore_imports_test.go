@@ -0,0 +1,207 @@
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+)
+
+var importNow = time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func TestIgnoreImportFormat(t *testing.T) {
+	tests := []struct {
+		value          string
+		expectedFormat string
+		expectedPath   string
+	}{
+		{value: ".trivyignore", expectedFormat: TrivyIgnoreFormat, expectedPath: ".trivyignore"},
+		{value: "ci/.trivyignore.prod", expectedFormat: TrivyIgnoreFormat, expectedPath: "ci/.trivyignore.prod"},
+		{value: "dependency-check-suppressions.xml", expectedFormat: DependencyCheckIgnoreFormat, expectedPath: "dependency-check-suppressions.xml"},
+		{value: "osv-scanner.toml", expectedFormat: OSVScannerIgnoreFormat, expectedPath: "osv-scanner.toml"},
+		{value: "trivy:ignores.txt", expectedFormat: TrivyIgnoreFormat, expectedPath: "ignores.txt"},
+		{value: "osv-scanner:config/scanner.conf", expectedFormat: OSVScannerIgnoreFormat, expectedPath: "config/scanner.conf"},
+		{value: "ignores.txt", expectedFormat: "", expectedPath: "ignores.txt"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			format, path := ignoreImportFormat(test.value)
+			assert.Equal(t, test.expectedFormat, format)
+			assert.Equal(t, test.expectedPath, path)
+		})
+	}
+}
+
+func TestImportTrivyIgnore(t *testing.T) {
+	content := `# accepted risks
+CVE-2021-3711
+CVE-2022-0001 exp:2022-12-31 # fixed upstream soon
+
+GHSA-p6mc-m468-83gw exp:2022-01-31
+`
+	rules, warnings, err := importTrivyIgnore(strings.NewReader(content), "imported from .trivyignore", importNow)
+	require.NoError(t, err)
+	assert.Equal(t, []match.IgnoreRule{
+		{Vulnerability: "CVE-2021-3711", Reason: "imported from .trivyignore"},
+		{Vulnerability: "CVE-2022-0001", Reason: "imported from .trivyignore"},
+	}, rules)
+	assert.Equal(t, []string{"skipping expired suppression of GHSA-p6mc-m468-83gw on line 5"}, warnings)
+
+	_, _, err = importTrivyIgnore(strings.NewReader("CVE-2021-3711 exp:tomorrow"), "", importNow)
+	assert.Error(t, err)
+}
+
+func TestImportDependencyCheckSuppressions(t *testing.T) {
+	content := `<?xml version="1.0" encoding="UTF-8"?>
+<suppressions xmlns="https://jeremylong.github.io/DependencyCheck/dependency-suppression.1.3.xsd">
+  <suppress>
+    <notes><![CDATA[ not exploitable in our usage ]]></notes>
+    <packageUrl regex="true">^pkg:maven/org\.eclipse\.jetty/jetty-server@.*$</packageUrl>
+    <cve>CVE-2021-28165</cve>
+    <vulnerabilityName>CVE-2021-34428</vulnerabilityName>
+  </suppress>
+  <suppress until="2022-12-31Z">
+    <filePath>/app/lib/commons-text-1.9.jar</filePath>
+  </suppress>
+  <suppress>
+    <cve>CVE-2021-44228</cve>
+  </suppress>
+  <suppress until="2022-01-31Z">
+    <cve>CVE-2020-0001</cve>
+  </suppress>
+  <suppress>
+    <gav regex="true">^org\.acme:.*$</gav>
+    <cve>CVE-2020-0002</cve>
+  </suppress>
+  <suppress>
+    <packageUrl regex="true">^pkg:npm/(lodash|underscore)@.*$</packageUrl>
+  </suppress>
+  <suppress>
+    <cpe>cpe:/a:apache:tomcat</cpe>
+  </suppress>
+</suppressions>
+`
+	rules, warnings, err := importDependencyCheckSuppressions(strings.NewReader(content), "imported from suppressions.xml", importNow)
+	require.NoError(t, err)
+	assert.Equal(t, []match.IgnoreRule{
+		{
+			Vulnerability: "CVE-2021-28165",
+			Reason:        "not exploitable in our usage",
+			Package:       match.IgnoreRulePackage{PURL: "pkg:maven/org.eclipse.jetty/jetty-server@*"},
+		},
+		{
+			Vulnerability: "CVE-2021-34428",
+			Reason:        "not exploitable in our usage",
+			Package:       match.IgnoreRulePackage{PURL: "pkg:maven/org.eclipse.jetty/jetty-server@*"},
+		},
+		{
+			Reason:  "imported from suppressions.xml",
+			Package: match.IgnoreRulePackage{Location: "/app/lib/commons-text-1.9.jar"},
+		},
+		{
+			Vulnerability: "CVE-2021-44228",
+			Reason:        "imported from suppressions.xml",
+		},
+	}, rules)
+	assert.Equal(t, []string{
+		"skipping suppression #4: expired on 2022-01-31Z",
+		"skipping suppression #5: suppressions by Maven coordinates are not supported",
+		`skipping suppression #6: unsupported regular expression "^pkg:npm/(lodash|underscore)@.*$"`,
+		"skipping suppression #7: suppressions by CPE are not supported",
+	}, warnings)
+
+	_, _, err = importDependencyCheckSuppressions(strings.NewReader("<suppressions>"), "", importNow)
+	assert.Error(t, err)
+}
+
+func TestImportOSVScannerConfig(t *testing.T) {
+	content := `
+[[IgnoredVulns]]
+id = "GO-2022-0968"
+ignoreUntil = 2022-11-09
+reason = "No ssh servers are connected to or hosted"
+
+[[IgnoredVulns]]
+id = "GHSA-p6mc-m468-83gw"
+ignoreUntil = 2022-01-31T00:00:00Z
+
+[[IgnoredVulns]]
+id = "CVE-2021-44228"
+
+[[PackageOverrides]]
+name = "org.apache.logging.log4j:log4j-core"
+ecosystem = "Maven"
+version = "2.14.1"
+ignore = true
+reason = "only used in tests"
+
+[[PackageOverrides]]
+name = "lodash"
+ecosystem = "npm"
+[PackageOverrides.vulnerability]
+ignore = true
+
+[[PackageOverrides]]
+name = "left-pad"
+ecosystem = "npm"
+license.override = ["MIT"]
+
+[[PackageOverrides]]
+name = "serde"
+ecosystem = "Hackage"
+ignore = true
+`
+	rules, warnings, err := importOSVScannerConfig(strings.NewReader(content), "imported from osv-scanner.toml", importNow)
+	require.NoError(t, err)
+	assert.Equal(t, []match.IgnoreRule{
+		{Vulnerability: "GO-2022-0968", Reason: "No ssh servers are connected to or hosted"},
+		{Vulnerability: "CVE-2021-44228", Reason: "imported from osv-scanner.toml"},
+		{
+			Reason:  "only used in tests",
+			Package: match.IgnoreRulePackage{Name: "log4j-core", Version: "2.14.1", Type: "java-archive"},
+		},
+		{
+			Reason:  "imported from osv-scanner.toml",
+			Package: match.IgnoreRulePackage{Name: "lodash", Type: "npm"},
+		},
+	}, rules)
+	assert.Equal(t, []string{
+		"skipping expired suppression of GHSA-p6mc-m468-83gw",
+		`skipping package override #4: unsupported ecosystem "Hackage"`,
+	}, warnings)
+
+	_, _, err = importOSVScannerConfig(strings.NewReader("[[IgnoredVulns]"), "", importNow)
+	assert.Error(t, err)
+}
+
+func TestApplication_parseImportIgnoresOption(t *testing.T) {
+	dir := t.TempDir()
+	trivyIgnore := filepath.Join(dir, ".trivyignore")
+	require.NoError(t, ioutil.WriteFile(trivyIgnore, []byte("CVE-2021-3711\n"), 0600))
+	other := filepath.Join(dir, "ignores.txt")
+	require.NoError(t, ioutil.WriteFile(other, []byte("CVE-2021-44228\n"), 0600))
+
+	cfg := Application{
+		Ignore:        []match.IgnoreRule{{Vulnerability: "CVE-2020-8203"}},
+		ImportIgnores: []string{trivyIgnore, "trivy:" + other},
+	}
+	require.NoError(t, cfg.parseImportIgnoresOption())
+	assert.Equal(t, []match.IgnoreRule{
+		{Vulnerability: "CVE-2020-8203"},
+		{Vulnerability: "CVE-2021-3711", Reason: "imported from " + trivyIgnore},
+		{Vulnerability: "CVE-2021-44228", Reason: "imported from " + other},
+	}, cfg.Ignore)
+
+	cfg = Application{ImportIgnores: []string{other}}
+	assert.Error(t, cfg.parseImportIgnoresOption())
+
+	cfg = Application{ImportIgnores: []string{filepath.Join(dir, "missing.toml")}}
+	assert.Error(t, cfg.parseImportIgnoresOption())
+}
@@ -1,13 +1,24 @@
 package config
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
 )
 
+const (
+	// TextLogFormat shows log entries as human readable lines
+	TextLogFormat = "text"
+	// JSONLogFormat shows log entries as JSON objects (one per line) for log aggregation systems
+	JSONLogFormat = "json"
+)
+
 // logging contains all logging-related configuration options available to the user via the application config.
 type logging struct {
-	Structured   bool         `yaml:"structured" json:"structured" mapstructure:"structured"` // show all log entries as JSON formatted strings
+	Structured   bool         `yaml:"structured" json:"structured" mapstructure:"structured"` // show all log entries as JSON formatted strings (same as format "json")
+	Format       string       `yaml:"format" json:"format" mapstructure:"format"`             // the format of log entries: "text" or "json"
 	LevelOpt     logrus.Level `yaml:"-" json:"-"`                                             // the native log level object used by the logger
 	Level        string       `yaml:"level" json:"level" mapstructure:"level"`                // the log level string hint
 	FileLocation string       `yaml:"file" json:"file" mapstructure:"file"`                   // the file path to write logs to
@@ -17,4 +28,21 @@ func (cfg logging) loadDefaultValues(v *viper.Viper) {
 	v.SetDefault("log.level", "")
 	v.SetDefault("log.file", "")
 	v.SetDefault("log.structured", false)
+	v.SetDefault("log.format", "")
+}
+
+func (cfg *logging) parseConfigValues() error {
+	switch format := strings.ToLower(cfg.Format); format {
+	case "":
+		cfg.Format = TextLogFormat
+		if cfg.Structured {
+			cfg.Format = JSONLogFormat
+		}
+	case TextLogFormat, JSONLogFormat:
+		cfg.Format = format
+	default:
+		return fmt.Errorf("bad log format configured (%q): options are %q and %q", cfg.Format, TextLogFormat, JSONLogFormat)
+	}
+	cfg.Structured = cfg.Format == JSONLogFormat
+	return nil
 }
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_logging_parseConfigValues(t *testing.T) {
+	tests := []struct {
+		name           string
+		cfg            logging
+		wantFormat     string
+		wantStructured bool
+		wantErr        bool
+	}{
+		{
+			name:       "default",
+			wantFormat: TextLogFormat,
+		},
+		{
+			name:           "json",
+			cfg:            logging{Format: "JSON"},
+			wantFormat:     JSONLogFormat,
+			wantStructured: true,
+		},
+		{
+			name:           "structured",
+			cfg:            logging{Structured: true},
+			wantFormat:     JSONLogFormat,
+			wantStructured: true,
+		},
+		{
+			name:       "format takes precedence over structured",
+			cfg:        logging{Structured: true, Format: "text"},
+			wantFormat: TextLogFormat,
+		},
+		{
+			name:    "unknown format",
+			cfg:     logging{Format: "xml"},
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.cfg.parseConfigValues()
+			if test.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.wantFormat, test.cfg.Format)
+			assert.Equal(t, test.wantStructured, test.cfg.Structured)
+		})
+	}
+}
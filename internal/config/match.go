@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/spf13/viper"
+)
+
+// matcherOptions configures a single matcher (see the match config).
+type matcherOptions struct {
+	Enabled   bool `yaml:"enabled" json:"enabled" mapstructure:"enabled"`          // whether the matcher matches packages at all
+	UsingCPEs bool `yaml:"using-cpes" json:"using-cpes" mapstructure:"using-cpes"` // whether the matcher searches for vulnerabilities by the CPEs of packages
+}
+
+// matchConfig configures individual matchers by name, where the name of a matcher is its type without the "-matcher"
+// suffix (e.g. "java" for the java-matcher, and "stock" for the matcher of packages without a specific matcher).
+type matchConfig map[string]matcherOptions
+
+// matcherTypes are the matchers that can be configured, by name.
+func matcherTypes() map[string]match.MatcherType {
+	types := make(map[string]match.MatcherType)
+	for _, t := range append([]match.MatcherType{match.StockMatcher}, match.AllMatcherTypes...) {
+		types[strings.TrimSuffix(string(t), "-matcher")] = t
+	}
+	return types
+}
+
+func (cfg matchConfig) loadDefaultValues(v *viper.Viper) {
+	for name := range matcherTypes() {
+		v.SetDefault(fmt.Sprintf("match.%s.enabled", name), true)
+		v.SetDefault(fmt.Sprintf("match.%s.using-cpes", name), true)
+	}
+}
+
+// ToConfig returns the configs of the matchers that do not have the default configuration.
+func (cfg matchConfig) ToConfig() (map[match.MatcherType]matcher.MatcherConfig, error) {
+	types := matcherTypes()
+	configs := make(map[match.MatcherType]matcher.MatcherConfig)
+	for name, options := range cfg {
+		t, ok := types[name]
+		if !ok {
+			var names []string
+			for n := range types {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unknown matcher %q (matchers: %v)", name, names)
+		}
+		if options.Enabled && options.UsingCPEs {
+			continue
+		}
+		configs[t] = matcher.MatcherConfig{
+			Disabled: !options.Enabled,
+			SkipCPEs: !options.UsingCPEs,
+		}
+	}
+	return configs, nil
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/matcher"
+)
+
+func TestMatchConfig_ToConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   map[string]interface{}
+		expected map[match.MatcherType]matcher.MatcherConfig
+		wantErr  bool
+	}{
+		{
+			name:     "defaults",
+			expected: map[match.MatcherType]matcher.MatcherConfig{},
+		},
+		{
+			name: "configured matchers",
+			values: map[string]interface{}{
+				"match.javascript.using-cpes": false,
+				"match.stock.enabled":         false,
+				"match.java.enabled":          true,
+			},
+			expected: map[match.MatcherType]matcher.MatcherConfig{
+				match.JavascriptMatcher: {SkipCPEs: true},
+				match.StockMatcher:      {Disabled: true, SkipCPEs: false},
+			},
+		},
+		{
+			name:    "unknown matcher",
+			values:  map[string]interface{}{"match.cobol.enabled": false},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := viper.New()
+			var cfg matchConfig
+			cfg.loadDefaultValues(v)
+			for key, value := range test.values {
+				v.Set(key, value)
+			}
+			var app Application
+			require.NoError(t, v.Unmarshal(&app))
+
+			actual, err := app.Match.ToConfig()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/matcher"
+	"github.com/anchore/grype/grype/matcher/plugin"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+	"github.com/mitchellh/go-homedir"
+)
+
+// matcherPlugin is an external matcher of packages of the given types (see the plugin package for the protocol).
+type matcherPlugin struct {
+	Name         string   `yaml:"name" json:"name" mapstructure:"name"`                            // the name of the plugin, where the matcher type is "<name>-plugin"
+	Command      string   `yaml:"command" json:"command" mapstructure:"command"`                   // the executable of the plugin
+	Args         []string `yaml:"args" json:"args" mapstructure:"args"`                            // the arguments of the executable
+	PackageTypes []string `yaml:"package-types" json:"package-types" mapstructure:"package-types"` // the types of the packages that the plugin matches
+}
+
+func (cfg *Application) parseMatcherPluginsOption() error {
+	names := make(map[string]bool)
+	cfg.Matcher.Plugins = nil
+	for _, p := range cfg.MatcherPlugins {
+		switch {
+		case p.Name == "":
+			return fmt.Errorf("bad matcher plugin: a name is required")
+		case names[p.Name]:
+			return fmt.Errorf("bad matcher plugin %q: the name is used by another plugin", p.Name)
+		case p.Command == "":
+			return fmt.Errorf("bad matcher plugin %q: a command is required", p.Name)
+		case len(p.PackageTypes) == 0:
+			return fmt.Errorf("bad matcher plugin %q: package types are required", p.Name)
+		}
+		names[p.Name] = true
+
+		command, err := homedir.Expand(p.Command)
+		if err != nil {
+			return fmt.Errorf("unable to expand matcher plugin command=%q: %w", p.Command, err)
+		}
+		var types []syftPkg.Type
+		for _, t := range p.PackageTypes {
+			types = append(types, syftPkg.Type(t))
+		}
+		cfg.Matcher.Plugins = append(cfg.Matcher.Plugins, matcher.Matcher(plugin.New(plugin.Config{
+			Name:         p.Name,
+			Command:      command,
+			Args:         p.Args,
+			PackageTypes: types,
+		})))
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	syftPkg "github.com/anchore/syft/syft/pkg"
+)
+
+func TestApplication_parseMatcherPluginsOption(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugins  []matcherPlugin
+		expected []match.MatcherType
+		wantErr  bool
+	}{
+		{
+			name: "no plugins",
+		},
+		{
+			name: "plugins",
+			plugins: []matcherPlugin{
+				{Name: "acme", Command: "grype-acme", PackageTypes: []string{"acme"}},
+				{Name: "internal", Command: "/opt/grype-internal", Args: []string{"--strict"}, PackageTypes: []string{"npm", "internal"}},
+			},
+			expected: []match.MatcherType{"acme-plugin", "internal-plugin"},
+		},
+		{
+			name:    "without a name",
+			plugins: []matcherPlugin{{Command: "grype-acme", PackageTypes: []string{"acme"}}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate name",
+			plugins: []matcherPlugin{
+				{Name: "acme", Command: "grype-acme", PackageTypes: []string{"acme"}},
+				{Name: "acme", Command: "grype-acme-2", PackageTypes: []string{"acme"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "without a command",
+			plugins: []matcherPlugin{{Name: "acme", PackageTypes: []string{"acme"}}},
+			wantErr: true,
+		},
+		{
+			name:    "without package types",
+			plugins: []matcherPlugin{{Name: "acme", Command: "grype-acme"}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := Application{MatcherPlugins: test.plugins}
+			err := cfg.parseMatcherPluginsOption()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var actual []match.MatcherType
+			for _, m := range cfg.Matcher.Plugins {
+				actual = append(actual, m.Type())
+			}
+			assert.Equal(t, test.expected, actual)
+			if len(test.plugins) > 0 {
+				assert.Equal(t, []syftPkg.Type{"npm", "internal"}, cfg.Matcher.Plugins[1].PackageTypes())
+			}
+		})
+	}
+}
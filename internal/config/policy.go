@@ -0,0 +1,34 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/policy"
+	"github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+)
+
+type policyConfig struct {
+	File      string         `yaml:"file" json:"file" mapstructure:"file"`    // --policy, the Rego policy that decides whether the scan result passes
+	Query     string         `yaml:"query" json:"query" mapstructure:"query"` // the query of the decision of the policy (with the deny and warn rules)
+	PolicyOpt *policy.Policy `yaml:"-" json:"-"`
+}
+
+func (cfg policyConfig) loadDefaultValues(v *viper.Viper) {
+	v.SetDefault("policy.file", "")
+	v.SetDefault("policy.query", policy.DefaultQuery)
+}
+
+func (cfg *policyConfig) parseConfigValues() error {
+	if cfg.File == "" {
+		return nil
+	}
+	file, err := homedir.Expand(cfg.File)
+	if err != nil {
+		return fmt.Errorf("unable to expand policy path=%q: %w", cfg.File, err)
+	}
+	if cfg.PolicyOpt, err = policy.New(file, cfg.Query); err != nil {
+		return fmt.Errorf("bad policy: %w", err)
+	}
+	return nil
+}
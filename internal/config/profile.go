@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// profilesKey is the key of the named profiles of the config, where each profile has values of the config that take
+// precedence over the values of the rest of the config when the profile is selected (with --profile).
+const profilesKey = "profiles"
+
+// applyProfile merges the values of the given profile into the config, where the ignore rules of the profile are added
+// to the ignore rules of the config (instead of replacing them). Flags and environment variables still take precedence
+// over the values of the profile.
+func applyProfile(v *viper.Viper, name string) error {
+	profiles := v.GetStringMap(profilesKey)
+	// note: viper keys are case-insensitive (and stored in lower case)
+	raw, ok := profiles[strings.ToLower(name)]
+	if !ok {
+		var names []string
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("profile %q is not in the config (profiles: %v)", name, names)
+	}
+	if raw == nil {
+		return nil
+	}
+	settings, ok := raw.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("profile %q is not a mapping of config values", name)
+	}
+	if _, ok := settings[profilesKey]; ok {
+		return fmt.Errorf("profile %q cannot have profiles", name)
+	}
+
+	if rules, ok := settings["ignore"].([]interface{}); ok {
+		if base, ok := v.Get("ignore").([]interface{}); ok {
+			settings["ignore"] = append(append([]interface{}{}, base...), rules...)
+		}
+	}
+	return v.MergeConfigMap(settings)
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profilesConfig = `
+output: table
+fail-on-severity: critical
+ignore:
+  - vulnerability: CVE-2020-0001
+db:
+  auto-update: true
+  cache-dir: /cache
+profiles:
+  ci:
+    output: json
+    fail-on-severity: high
+    ignore:
+      - vulnerability: CVE-2021-0002
+    db:
+      auto-update: false
+  audit:
+    only-fixed: false
+  empty:
+`
+
+func TestApplyProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile string
+		assert  func(t *testing.T, v *viper.Viper)
+		wantErr bool
+	}{
+		{
+			name:    "profile values take precedence",
+			profile: "ci",
+			assert: func(t *testing.T, v *viper.Viper) {
+				assert.Equal(t, "json", v.GetString("output"))
+				assert.Equal(t, "high", v.GetString("fail-on-severity"))
+				// nested values that are not in the profile are kept
+				assert.False(t, v.GetBool("db.auto-update"))
+				assert.Equal(t, "/cache", v.GetString("db.cache-dir"))
+			},
+		},
+		{
+			name:    "ignore rules are added",
+			profile: "CI",
+			assert: func(t *testing.T, v *viper.Viper) {
+				var cfg Application
+				require.NoError(t, v.Unmarshal(&cfg))
+				require.Len(t, cfg.Ignore, 2)
+				assert.Equal(t, "CVE-2020-0001", cfg.Ignore[0].Vulnerability)
+				assert.Equal(t, "CVE-2021-0002", cfg.Ignore[1].Vulnerability)
+			},
+		},
+		{
+			name:    "other values are kept",
+			profile: "audit",
+			assert: func(t *testing.T, v *viper.Viper) {
+				assert.Equal(t, "table", v.GetString("output"))
+				assert.Len(t, v.Get("ignore"), 1)
+			},
+		},
+		{
+			name:    "empty profile",
+			profile: "empty",
+			assert: func(t *testing.T, v *viper.Viper) {
+				assert.Equal(t, "table", v.GetString("output"))
+			},
+		},
+		{
+			name:    "unknown profile",
+			profile: "dev",
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			v := viper.New()
+			v.SetConfigType("yaml")
+			require.NoError(t, v.ReadConfig(strings.NewReader(profilesConfig)))
+
+			err := applyProfile(v, test.profile)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			test.assert(t, v)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"github.com/anchore/grype/internal/proxy"
+	"github.com/spf13/viper"
+)
+
+type proxyConfig struct {
+	// IMPORTANT: do not show the proxies in any YAML/JSON output (the URLs may have credentials)
+	HTTP    string `yaml:"-" json:"-" mapstructure:"http"`                   // the proxy of http requests (instead of HTTP_PROXY)
+	HTTPS   string `yaml:"-" json:"-" mapstructure:"https"`                  // the proxy of https requests (instead of HTTPS_PROXY)
+	NoProxy string `yaml:"no-proxy" json:"no-proxy" mapstructure:"no-proxy"` // the hosts, IP addresses, and CIDR ranges that are not proxied (instead of NO_PROXY)
+}
+
+func (cfg proxyConfig) loadDefaultValues(v *viper.Viper) {
+	v.SetDefault("proxy.http", "")
+	v.SetDefault("proxy.https", "")
+	v.SetDefault("proxy.no-proxy", "")
+}
+
+func (cfg proxyConfig) ToConfig() proxy.Config {
+	return proxy.Config{
+		HTTPProxy:  cfg.HTTP,
+		HTTPSProxy: cfg.HTTPS,
+		NoProxy:    cfg.NoProxy,
+	}
+}
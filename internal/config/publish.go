@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/anchore/grype/grype/publish"
+	"github.com/spf13/viper"
+)
+
+const (
+	// DependencyTrackSink publishes the scan result to a Dependency-Track instance (see the dependency-track config)
+	DependencyTrackSink = "dependency-track"
+	// FileSink writes the JSON document of the scan result to a file (see the publish-file config)
+	FileSink = "file"
+	// S3Sink uploads the JSON document of the scan result to an S3 bucket (see the publish-s3 config)
+	S3Sink = "s3"
+	// HTTPSink posts the JSON document of the scan result to a URL (see the publish-http config)
+	HTTPSink = "http"
+)
+
+// PublishSinks are the sinks the scan result can be published to (with --publish)
+var PublishSinks = []string{DependencyTrackSink, FileSink, S3Sink, HTTPSink}
+
+type publishFile struct {
+	Path string `yaml:"path" json:"path" mapstructure:"path"` // the file of the scan result, where {target} and {timestamp} are replaced
+}
+
+func (cfg publishFile) loadDefaultValues(v *viper.Viper) {
+	v.SetDefault("publish-file.path", "grype-{target}-{timestamp}.json")
+}
+
+type publishS3 struct {
+	Bucket    string `yaml:"bucket" json:"bucket" mapstructure:"bucket"`             // the bucket the scan result is uploaded to
+	Key       string `yaml:"key" json:"key" mapstructure:"key"`                      // the object key of the scan result, where {target} and {timestamp} are replaced
+	Region    string `yaml:"region" json:"region" mapstructure:"region"`             // the region of the bucket (by default the region of the AWS environment)
+	Endpoint  string `yaml:"endpoint" json:"endpoint" mapstructure:"endpoint"`       // the endpoint of an S3 compatible service (by default AWS S3)
+	PathStyle bool   `yaml:"path-style" json:"path-style" mapstructure:"path-style"` // address the bucket in the path of requests (for S3 compatible services)
+}
+
+func (cfg publishS3) loadDefaultValues(v *viper.Viper) {
+	v.SetDefault("publish-s3.bucket", "")
+	v.SetDefault("publish-s3.key", "grype/{target}/{timestamp}.json")
+	v.SetDefault("publish-s3.region", "")
+	v.SetDefault("publish-s3.endpoint", "")
+	v.SetDefault("publish-s3.path-style", false)
+}
+
+func (cfg publishS3) ToConfig() publish.S3Config {
+	return publish.S3Config{
+		Bucket:    cfg.Bucket,
+		Key:       cfg.Key,
+		Region:    cfg.Region,
+		Endpoint:  cfg.Endpoint,
+		PathStyle: cfg.PathStyle,
+	}
+}
+
+type publishHTTP struct {
+	// IMPORTANT: do not show the URL or headers in any YAML/JSON output (these may have credentials)
+	URL     string            `yaml:"-" json:"-" mapstructure:"url"`     // the URL the scan result is posted to
+	Headers map[string]string `yaml:"-" json:"-" mapstructure:"headers"` // the headers of every request (e.g. an authorization header)
+}
+
+func (cfg publishHTTP) loadDefaultValues(v *viper.Viper) {
+	v.SetDefault("publish-http.url", "")
+}
+
+// validateSink checks the config of the given sink is complete enough to publish to the sink.
+func (cfg Application) validateSink(sink string) error {
+	switch sink {
+	case DependencyTrackSink:
+		return cfg.DependencyTrack.validate()
+	case FileSink:
+		if cfg.PublishFile.Path == "" {
+			return fmt.Errorf("publishing to a file requires a path (publish-file.path)")
+		}
+	case S3Sink:
+		if cfg.PublishS3.Bucket == "" || cfg.PublishS3.Key == "" {
+			return fmt.Errorf("publishing to S3 requires a bucket and a key (publish-s3.bucket and publish-s3.key)")
+		}
+	case HTTPSink:
+		u, err := url.Parse(cfg.PublishHTTP.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			// note: the URL is not part of the error, since it may have credentials
+			return fmt.Errorf("publishing with HTTP requires an http or https URL (publish-http.url)")
+		}
+	default:
+		return fmt.Errorf("bad --publish value %q (options: %v)", sink, PublishSinks)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplication_parsePublishOption(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Application
+		wantErr bool
+	}{
+		{
+			name: "no sinks",
+		},
+		{
+			name: "configured sinks",
+			cfg: Application{
+				Publish:         []string{DependencyTrackSink, FileSink, S3Sink, HTTPSink},
+				DependencyTrack: dependencyTrack{URL: "https://dtrack.example.com", APIKey: "key"},
+				PublishFile:     publishFile{Path: "{target}.json"},
+				PublishS3:       publishS3{Bucket: "results", Key: "{target}.json"},
+				PublishHTTP:     publishHTTP{URL: "https://results.example.com"},
+			},
+		},
+		{
+			name:    "unknown sink",
+			cfg:     Application{Publish: []string{"ftp"}},
+			wantErr: true,
+		},
+		{
+			name:    "dependency-track without an API key",
+			cfg:     Application{Publish: []string{DependencyTrackSink}, DependencyTrack: dependencyTrack{URL: "https://dtrack.example.com"}},
+			wantErr: true,
+		},
+		{
+			name:    "dependency-track project name without a version",
+			cfg:     Application{Publish: []string{DependencyTrackSink}, DependencyTrack: dependencyTrack{URL: "https://dtrack.example.com", APIKey: "key", ProjectName: "app"}},
+			wantErr: true,
+		},
+		{
+			name:    "s3 without a bucket",
+			cfg:     Application{Publish: []string{S3Sink}, PublishS3: publishS3{Key: "{target}.json"}},
+			wantErr: true,
+		},
+		{
+			name:    "http without a URL",
+			cfg:     Application{Publish: []string{HTTPSink}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.cfg.parsePublishOption()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -1,10 +1,13 @@
 package config
 
 import (
+	"crypto/x509"
+	"fmt"
 	"os"
 
 	"github.com/spf13/viper"
 
+	"github.com/anchore/grype/grype/pkg"
 	"github.com/anchore/stereoscope/pkg/image"
 )
 
@@ -16,56 +19,83 @@ type RegistryCredentials struct {
 	Password string `yaml:"-" json:"-" mapstructure:"password"`
 	// IMPORTANT: do not show the token in any YAML/JSON output (sensitive information)
 	Token string `yaml:"-" json:"-" mapstructure:"token"`
+	// the docker credential helper of the registry (e.g. "ecr-login" for docker-credential-ecr-login), instead of a
+	// username and password or a token
+	CredentialHelper string `yaml:"credential-helper" json:"credential-helper" mapstructure:"credential-helper"`
 }
 
 type registry struct {
-	InsecureSkipTLSVerify bool                  `yaml:"insecure-skip-tls-verify" json:"insecure-skip-tls-verify" mapstructure:"insecure-skip-tls-verify"`
-	InsecureUseHTTP       bool                  `yaml:"insecure-use-http" json:"insecure-use-http" mapstructure:"insecure-use-http"`
+	InsecureSkipTLSVerify bool                  `yaml:"insecure-skip-tls-verify" json:"insecure-skip-tls-verify" mapstructure:"insecure-skip-tls-verify"` // --insecure-skip-tls-verify, skip TLS verification when communicating with the registry
+	InsecureUseHTTP       bool                  `yaml:"insecure-use-http" json:"insecure-use-http" mapstructure:"insecure-use-http"`                      // --insecure-use-http, use http instead of https when connecting to the registry
+	CACert                string                `yaml:"ca-cert" json:"ca-cert" mapstructure:"ca-cert"`                                                    // --registry-ca-cert, the PEM encoded certificate authorities of registries
+	CloudCredentials      bool                  `yaml:"cloud-credentials" json:"cloud-credentials" mapstructure:"cloud-credentials"`                      // --registry-cloud-credentials, use the credentials of the cloud environment for ECR, GCR, and ACR registries
 	Auth                  []RegistryCredentials `yaml:"auth" json:"auth" mapstructure:"auth"`
+	CACertOpt             *x509.CertPool        `yaml:"-" json:"-"`
 }
 
 func (cfg registry) loadDefaultValues(v *viper.Viper) {
 	v.SetDefault("registry.insecure-skip-tls-verify", false)
 	v.SetDefault("registry.insecure-use-http", false)
+	v.SetDefault("registry.ca-cert", "")
+	v.SetDefault("registry.cloud-credentials", false)
 	v.SetDefault("registry.auth", []RegistryCredentials{})
 }
 
-// nolint:unparam
 func (cfg *registry) parseConfigValues() error {
 	// there may be additional credentials provided by env var that should be appended to the set of credentials
-	authority, username, password, token :=
-		os.Getenv("GRYPE_REGISTRY_AUTH_AUTHORITY"),
-		os.Getenv("GRYPE_REGISTRY_AUTH_USERNAME"),
-		os.Getenv("GRYPE_REGISTRY_AUTH_PASSWORD"),
-		os.Getenv("GRYPE_REGISTRY_AUTH_TOKEN")
+	// note: we prepend the credentials such that the environment variables take precedence over on-disk configuration.
+	cfg.prependCredentials(RegistryCredentials{
+		Authority: os.Getenv("GRYPE_REGISTRY_AUTH_AUTHORITY"),
+		Username:  os.Getenv("GRYPE_REGISTRY_AUTH_USERNAME"),
+		Password:  os.Getenv("GRYPE_REGISTRY_AUTH_PASSWORD"),
+		Token:     os.Getenv("GRYPE_REGISTRY_AUTH_TOKEN"),
+	})
 
-	if hasNonEmptyCredentials(username, password, token) {
-		// note: we prepend the credentials such that the environment variables take precedence over on-disk configuration.
-		cfg.Auth = append([]RegistryCredentials{
-			{
-				Authority: authority,
-				Username:  username,
-				Password:  password,
-				Token:     token,
-			},
-		}, cfg.Auth...)
+	for _, credentials := range cfg.Auth {
+		if credentials.CredentialHelper != "" && credentials.Authority == "" {
+			return fmt.Errorf("the registry credential helper %q requires the authority of the registry", credentials.CredentialHelper)
+		}
+	}
+
+	if cfg.CACert != "" {
+		contents, err := readConfigFile(cfg.CACert)
+		if err != nil {
+			return fmt.Errorf("unable to read registry CA certificates: %w", err)
+		}
+		if cfg.CACertOpt, err = x509.SystemCertPool(); err != nil {
+			cfg.CACertOpt = x509.NewCertPool()
+		}
+		if !cfg.CACertOpt.AppendCertsFromPEM(contents) {
+			return fmt.Errorf("no PEM encoded certificates found in registry CA certificates %q", cfg.CACert)
+		}
 	}
 	return nil
 }
 
+// prependCredentials adds the given credentials (if any) with precedence over the other credentials of the registry.
+func (cfg *registry) prependCredentials(credentials RegistryCredentials) {
+	if hasNonEmptyCredentials(credentials.Username, credentials.Password, credentials.Token) {
+		cfg.Auth = append([]RegistryCredentials{credentials}, cfg.Auth...)
+	}
+}
+
 func hasNonEmptyCredentials(username, password, token string) bool {
 	return password != "" && username != "" || token != ""
 }
 
 func (cfg *registry) ToOptions() *image.RegistryOptions {
-	var auth = make([]image.RegistryCredentials, len(cfg.Auth))
-	for i, a := range cfg.Auth {
-		auth[i] = image.RegistryCredentials{
+	var auth = make([]image.RegistryCredentials, 0, len(cfg.Auth))
+	for _, a := range cfg.Auth {
+		if a.CredentialHelper != "" {
+			// note: the credentials of credential helpers are resolved through the default keychain (see ToAccessConfig)
+			continue
+		}
+		auth = append(auth, image.RegistryCredentials{
 			Authority: a.Authority,
 			Username:  a.Username,
 			Password:  a.Password,
 			Token:     a.Token,
-		}
+		})
 	}
 	return &image.RegistryOptions{
 		InsecureSkipTLSVerify: cfg.InsecureSkipTLSVerify,
@@ -73,3 +103,17 @@ func (cfg *registry) ToOptions() *image.RegistryOptions {
 		Credentials:           auth,
 	}
 }
+
+func (cfg *registry) ToAccessConfig() pkg.RegistryAccessConfig {
+	helpers := make(map[string]string)
+	for _, a := range cfg.Auth {
+		if a.CredentialHelper != "" {
+			helpers[a.Authority] = a.CredentialHelper
+		}
+	}
+	return pkg.RegistryAccessConfig{
+		CredentialHelpers: helpers,
+		CloudCredentials:  cfg.CloudCredentials,
+		CACerts:           cfg.CACertOpt,
+	}
+}
@@ -109,3 +109,32 @@ func Test_registry_ToOptions(t *testing.T) {
 		})
 	}
 }
+
+func Test_registry_CredentialHelpers(t *testing.T) {
+	cfg := registry{
+		Auth: []RegistryCredentials{
+			{Authority: "123456789012.dkr.ecr.us-east-1.amazonaws.com", CredentialHelper: "ecr-login"},
+			{Authority: "ghcr.io", Username: "user", Password: "pass"},
+		},
+		CloudCredentials: true,
+	}
+	assert.NoError(t, cfg.parseConfigValues())
+
+	assert.Equal(t, &image.RegistryOptions{
+		Credentials: []image.RegistryCredentials{{Authority: "ghcr.io", Username: "user", Password: "pass"}},
+	}, cfg.ToOptions())
+
+	access := cfg.ToAccessConfig()
+	assert.Equal(t, map[string]string{"123456789012.dkr.ecr.us-east-1.amazonaws.com": "ecr-login"}, access.CredentialHelpers)
+	assert.True(t, access.CloudCredentials)
+
+	cfg = registry{Auth: []RegistryCredentials{{CredentialHelper: "ecr-login"}}}
+	assert.Error(t, cfg.parseConfigValues())
+}
+
+func Test_registry_prependCredentials(t *testing.T) {
+	cfg := registry{Auth: []RegistryCredentials{{Username: "config", Password: "pass"}}}
+	cfg.prependCredentials(RegistryCredentials{})
+	cfg.prependCredentials(RegistryCredentials{Token: "flag"})
+	assert.Equal(t, []RegistryCredentials{{Token: "flag"}, {Username: "config", Password: "pass"}}, cfg.Auth)
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"crypto"
+	"fmt"
+	"os"
+
+	"github.com/anchore/grype/grype/presenter/attestation"
+	"github.com/spf13/viper"
+)
+
+type resultAttestation struct {
+	SigningKey string `yaml:"signing-key" json:"signing-key" mapstructure:"signing-key"` // --attestation-signing-key, the private key that signs the attestation of the scan result
+	// IMPORTANT: do not show the password in any YAML/JSON output (sensitive information)
+	Password      string        `yaml:"-" json:"-" mapstructure:"password"`
+	Push          bool          `yaml:"push" json:"push" mapstructure:"push"` // --push-attestation, push the attestation of the scan result to the registry of the image
+	SigningKeyOpt crypto.Signer `yaml:"-" json:"-"`
+}
+
+func (cfg resultAttestation) loadDefaultValues(v *viper.Viper) {
+	v.SetDefault("result-attestation.signing-key", "")
+	v.SetDefault("result-attestation.password", "")
+	v.SetDefault("result-attestation.push", false)
+}
+
+func (cfg *resultAttestation) parseConfigValues() error {
+	if cfg.SigningKey != "" {
+		contents, err := readConfigFile(cfg.SigningKey)
+		if err != nil {
+			return fmt.Errorf("unable to read attestation signing key: %w", err)
+		}
+		password := cfg.Password
+		if password == "" {
+			// the password of keys made by "cosign generate-key-pair"
+			password = os.Getenv("COSIGN_PASSWORD")
+		}
+		if cfg.SigningKeyOpt, err = attestation.ParseSigningKey(contents, []byte(password)); err != nil {
+			return fmt.Errorf("bad attestation signing key: %w", err)
+		}
+	}
+
+	if cfg.Push && cfg.SigningKeyOpt == nil {
+		return fmt.Errorf("pushing the attestation of the scan result requires an attestation signing key")
+	}
+	return nil
+}
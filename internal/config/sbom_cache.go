@@ -0,0 +1,27 @@
+package config
+
+import (
+	"path"
+
+	"github.com/adrg/xdg"
+	"github.com/anchore/grype/grype/pkg"
+	"github.com/anchore/grype/internal"
+	"github.com/spf13/viper"
+)
+
+type sbomCache struct {
+	Enabled bool   `yaml:"enabled" json:"enabled" mapstructure:"enabled"`       // --cache-sbom, reuse the cataloged packages of images that were scanned before
+	Dir     string `yaml:"cache-dir" json:"cache-dir" mapstructure:"cache-dir"` // where the cataloged packages of each image digest are kept
+}
+
+func (cfg sbomCache) loadDefaultValues(v *viper.Viper) {
+	v.SetDefault("sbom-cache.enabled", false)
+	v.SetDefault("sbom-cache.cache-dir", path.Join(xdg.CacheHome, internal.ApplicationName, "sbom"))
+}
+
+func (cfg sbomCache) ToConfig() pkg.SBOMCacheConfig {
+	return pkg.SBOMCacheConfig{
+		Enabled: cfg.Enabled,
+		Dir:     cfg.Dir,
+	}
+}
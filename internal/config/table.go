@@ -0,0 +1,31 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/anchore/grype/grype/presenter/table"
+	"github.com/spf13/viper"
+)
+
+type tableConfig struct {
+	SortBy   string        `yaml:"sort-by" json:"sort-by" mapstructure:"sort-by"`       // --sort-by, the order of the rows of the table output
+	Columns  []string      `yaml:"columns" json:"columns" mapstructure:"columns"`       // --columns, the columns of the table output (the default columns when empty)
+	ShowRefs bool          `yaml:"show-refs" json:"show-refs" mapstructure:"show-refs"` // --show-refs, add the related vulnerabilities and reference URLs to the table output
+	Options  table.Options `yaml:"-" json:"-"`
+}
+
+func (cfg tableConfig) loadDefaultValues(v *viper.Viper) {
+	v.SetDefault("table.sort-by", string(table.SortByPackage))
+	v.SetDefault("table.columns", []string{})
+	v.SetDefault("table.show-refs", false)
+}
+
+func (cfg *tableConfig) parseConfigValues() error {
+	options, err := table.ParseOptions(cfg.SortBy, cfg.Columns)
+	if err != nil {
+		return fmt.Errorf("bad table config: %w", err)
+	}
+	options.ShowRefs = cfg.ShowRefs
+	cfg.Options = options
+	return nil
+}
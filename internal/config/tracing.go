@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anchore/grype/internal/tracing"
+	"github.com/spf13/viper"
+)
+
+// the environment variables of the OpenTelemetry OTLP exporter, which are used when the endpoint is not configured
+// (see https://opentelemetry.io/docs/reference/specification/protocol/exporter/)
+const (
+	otlpEndpointEnv       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	otlpTracesEndpointEnv = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	otlpHeadersEnv        = "OTEL_EXPORTER_OTLP_HEADERS"
+)
+
+type tracingConfig struct {
+	Endpoint string        `yaml:"endpoint" json:"endpoint" mapstructure:"endpoint"` // --otlp-endpoint, the OTLP/HTTP URL spans are exported to (e.g. "http://localhost:4318/v1/traces")
+	Timeout  time.Duration `yaml:"timeout" json:"timeout" mapstructure:"timeout"`    // how long an export may take
+	// IMPORTANT: do not show the headers in any YAML/JSON output (these may have credentials)
+	Headers map[string]string `yaml:"-" json:"-" mapstructure:"headers"` // the headers of every export (e.g. an authorization header)
+}
+
+func (cfg tracingConfig) loadDefaultValues(v *viper.Viper) {
+	v.SetDefault("tracing.endpoint", "")
+	v.SetDefault("tracing.timeout", 10*time.Second)
+}
+
+func (cfg *tracingConfig) parseConfigValues() error {
+	cfg.applyEnvironment(os.Getenv)
+	if cfg.Endpoint == "" {
+		return nil
+	}
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("bad tracing endpoint configured (%q): must be an http or https URL", cfg.Endpoint)
+	}
+	return nil
+}
+
+// applyEnvironment fills the endpoint and headers that are not configured from the standard OpenTelemetry environment
+// variables, where the traces path is added to the base URL of OTEL_EXPORTER_OTLP_ENDPOINT.
+func (cfg *tracingConfig) applyEnvironment(getenv func(string) string) {
+	if cfg.Endpoint == "" {
+		if endpoint := getenv(otlpTracesEndpointEnv); endpoint != "" {
+			cfg.Endpoint = endpoint
+		} else if endpoint := getenv(otlpEndpointEnv); endpoint != "" {
+			cfg.Endpoint = strings.TrimSuffix(endpoint, "/") + "/v1/traces"
+		}
+	}
+	if len(cfg.Headers) == 0 {
+		cfg.Headers = parseOTLPHeaders(getenv(otlpHeadersEnv))
+	}
+}
+
+// parseOTLPHeaders parses headers in the format of OTEL_EXPORTER_OTLP_HEADERS (e.g. "api-key=secret,team=security"),
+// where the values are URL encoded.
+func parseOTLPHeaders(value string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		fields := strings.SplitN(pair, "=", 2)
+		if len(fields) != 2 || strings.TrimSpace(fields[0]) == "" {
+			continue
+		}
+		v, err := url.QueryUnescape(strings.TrimSpace(fields[1]))
+		if err != nil {
+			v = strings.TrimSpace(fields[1])
+		}
+		headers[strings.TrimSpace(fields[0])] = v
+	}
+	return headers
+}
+
+func (cfg tracingConfig) ToConfig() tracing.Config {
+	return tracing.Config{
+		Endpoint: cfg.Endpoint,
+		Headers:  cfg.Headers,
+		Timeout:  cfg.Timeout,
+	}
+}
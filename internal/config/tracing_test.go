@@ -0,0 +1,66 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_tracingConfig_applyEnvironment(t *testing.T) {
+	tests := []struct {
+		name             string
+		cfg              tracingConfig
+		env              map[string]string
+		expectedEndpoint string
+		expectedHeaders  map[string]string
+	}{
+		{
+			name:            "nothing configured",
+			expectedHeaders: map[string]string{},
+		},
+		{
+			name:             "configured endpoint wins",
+			cfg:              tracingConfig{Endpoint: "http://collector:4318/v1/traces"},
+			env:              map[string]string{otlpEndpointEnv: "http://other:4318"},
+			expectedEndpoint: "http://collector:4318/v1/traces",
+			expectedHeaders:  map[string]string{},
+		},
+		{
+			name:             "base endpoint gets the traces path",
+			env:              map[string]string{otlpEndpointEnv: "http://collector:4318/"},
+			expectedEndpoint: "http://collector:4318/v1/traces",
+			expectedHeaders:  map[string]string{},
+		},
+		{
+			name: "traces endpoint is used as is",
+			env: map[string]string{
+				otlpEndpointEnv:       "http://collector:4318",
+				otlpTracesEndpointEnv: "https://traces.example.com/ingest",
+			},
+			expectedEndpoint: "https://traces.example.com/ingest",
+			expectedHeaders:  map[string]string{},
+		},
+		{
+			name:            "headers",
+			env:             map[string]string{otlpHeadersEnv: "api-key=s%3Dcret, team = security,broken"},
+			expectedHeaders: map[string]string{"api-key": "s=cret", "team": "security"},
+		},
+		{
+			name:            "configured headers win",
+			cfg:             tracingConfig{Headers: map[string]string{"authorization": "Bearer token"}},
+			env:             map[string]string{otlpHeadersEnv: "api-key=secret"},
+			expectedHeaders: map[string]string{"authorization": "Bearer token"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := test.cfg
+			cfg.applyEnvironment(func(key string) string {
+				return test.env[key]
+			})
+			assert.Equal(t, test.expectedEndpoint, cfg.Endpoint)
+			assert.Equal(t, test.expectedHeaders, cfg.Headers)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/anchore/grype/grype/notify"
+)
+
+type webhook struct {
+	// IMPORTANT: do not show the URL, headers, or secret in any YAML/JSON output (these may have credentials, e.g. the
+	// token of a chat webhook or an authorization header)
+	URL     string            `yaml:"-" json:"-" mapstructure:"url"`                 // the URL that is notified
+	Headers map[string]string `yaml:"-" json:"-" mapstructure:"headers"`             // the headers of every request (e.g. an authorization header)
+	Secret  string            `yaml:"-" json:"-" mapstructure:"secret"`              // the secret that signs every request with HMAC-SHA256
+	Payload string            `yaml:"payload" json:"payload" mapstructure:"payload"` // what is posted: "summary" or "report" (the summary with the full report)
+	Events  []string          `yaml:"events" json:"events" mapstructure:"events"`    // the events that are notified: "scan-finished" and "new-vulnerabilities" (all events when empty)
+}
+
+// webhooks are the webhooks notified of scans (--notify-webhook adds a webhook with the default settings)
+type webhooks []webhook
+
+func (cfg *webhooks) parseConfigValues() error {
+	for i := range *cfg {
+		w := &(*cfg)[i]
+		u, err := url.Parse(w.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			// note: the URL is not part of the error, since it may have credentials
+			return fmt.Errorf("bad URL of webhook %d (must be an http or https URL)", i+1)
+		}
+
+		switch notify.Payload(w.Payload) {
+		case "":
+			w.Payload = string(notify.SummaryPayload)
+		case notify.SummaryPayload, notify.ReportPayload:
+		default:
+			return fmt.Errorf("bad payload of webhook %d: %q (options: %s, %s)", i+1, w.Payload, notify.SummaryPayload, notify.ReportPayload)
+		}
+
+		for _, event := range w.Events {
+			if !knownEvent(notify.Event(event)) {
+				return fmt.Errorf("bad event of webhook %d: %q (options: %v)", i+1, event, notify.AllEvents)
+			}
+		}
+	}
+	return nil
+}
+
+func knownEvent(event notify.Event) bool {
+	for _, e := range notify.AllEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func (cfg webhooks) ToWebhooks() []notify.Webhook {
+	var result []notify.Webhook
+	for _, w := range cfg {
+		var events []notify.Event
+		for _, event := range w.Events {
+			events = append(events, notify.Event(event))
+		}
+		result = append(result, notify.Webhook{
+			URL:     w.URL,
+			Headers: w.Headers,
+			Secret:  w.Secret,
+			Payload: notify.Payload(w.Payload),
+			Events:  events,
+		})
+	}
+	return result
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/notify"
+)
+
+func Test_webhooks_parseConfigValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     webhooks
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			cfg: webhooks{
+				{URL: "https://hooks.example.com/grype"},
+				{URL: "http://localhost:8080", Payload: "report", Events: []string{"new-vulnerabilities"}},
+			},
+		},
+		{
+			name:    "missing URL",
+			cfg:     webhooks{{}},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported URL scheme",
+			cfg:     webhooks{{URL: "ftp://hooks.example.com"}},
+			wantErr: true,
+		},
+		{
+			name:    "bad payload",
+			cfg:     webhooks{{URL: "https://hooks.example.com", Payload: "everything"}},
+			wantErr: true,
+		},
+		{
+			name:    "bad event",
+			cfg:     webhooks{{URL: "https://hooks.example.com", Events: []string{"scan-started"}}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.cfg.parseConfigValues()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func Test_webhooks_ToWebhooks(t *testing.T) {
+	cfg := webhooks{
+		{URL: "https://hooks.example.com/grype", Headers: map[string]string{"authorization": "Bearer token"}, Secret: "secret"},
+		{URL: "https://hooks.example.com/watch", Payload: "report", Events: []string{"new-vulnerabilities"}},
+	}
+	require.NoError(t, cfg.parseConfigValues())
+
+	assert.Equal(t, []notify.Webhook{
+		{URL: "https://hooks.example.com/grype", Headers: map[string]string{"authorization": "Bearer token"}, Secret: "secret", Payload: notify.SummaryPayload},
+		{URL: "https://hooks.example.com/watch", Payload: notify.ReportPayload, Events: []notify.Event{notify.NewVulnerabilitiesEvent}},
+	}, cfg.ToWebhooks())
+}
@@ -3,3 +3,7 @@ package internal
 // note: do not change this
 const ApplicationName = "grype"
 const DBUpdateURL = "https://toolbox-data.anchore.io/grype/databases/listing.json"
+
+// JSONSchemaVersion is the version of the shape of the JSON output, which is bumped on every change to the shape: the
+// major version for breaking changes, and the minor version for additions.
+const JSONSchemaVersion = "1.1.0"
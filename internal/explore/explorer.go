@@ -0,0 +1,534 @@
+package explore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/presenter/models"
+	"github.com/anchore/grype/grype/vulnerability"
+)
+
+// the fix states that matches can be filtered by (where the empty state shows all matches)
+var fixStates = []string{"", "fixed", "not-fixed", "wont-fix", "unknown"}
+
+// the ANSI escape codes of the styles of the explorer
+const (
+	reverseStyle = "\x1b[7m"
+	boldStyle    = "\x1b[1m"
+	resetStyle   = "\x1b[0m"
+)
+
+type mode int
+
+const (
+	listMode mode = iota
+	detailMode
+	inputMode
+)
+
+// Explorer is the state of the result explorer: the filtered matches, the selected match, and the matches that are
+// marked to be ignored. The explorer is driven by keys (see Handle) and drawn as lines of text (see Render), which
+// keeps it independent of the terminal.
+type Explorer struct {
+	matches []models.Match
+	// visible are the indexes of the matches that pass the filters
+	visible []int
+	cursor  int
+	offset  int
+
+	minSeverity   vulnerability.Severity
+	fixState      int
+	packageFilter string
+
+	mode         mode
+	detailOffset int
+	input        textInput
+
+	// ignores are the ignore rules of the marked matches (by the index of the match)
+	ignores      map[int]match.IgnoreRule
+	unsaved      bool
+	confirmQuit  bool
+	status       string
+	writeIgnores func([]match.IgnoreRule) error
+}
+
+// textInput is a line of text entered by the user (e.g. the package filter), which is submitted with enter.
+type textInput struct {
+	prompt   string
+	value    string
+	onSubmit func(value string)
+	previous mode
+}
+
+// New returns an explorer of the given matches (of a JSON report), where the marked ignore rules are written with
+// the given function.
+func New(matches []models.Match, writeIgnores func([]match.IgnoreRule) error) *Explorer {
+	e := &Explorer{
+		matches:      matches,
+		ignores:      make(map[int]match.IgnoreRule),
+		writeIgnores: writeIgnores,
+	}
+	e.applyFilters()
+	return e
+}
+
+// Handle updates the state of the explorer for the given key, returning true when the explorer is done.
+func (e *Explorer) Handle(k Key) bool {
+	if k.Type != KeyRune || k.Rune != 'q' {
+		e.confirmQuit = false
+	}
+
+	switch e.mode {
+	case inputMode:
+		e.handleInput(k)
+		return false
+	case detailMode:
+		return e.handleDetail(k)
+	default:
+		return e.handleList(k)
+	}
+}
+
+// nolint:funlen
+func (e *Explorer) handleList(k Key) bool {
+	e.status = ""
+	switch k.Type {
+	case KeyUp:
+		e.move(-1)
+	case KeyDown:
+		e.move(1)
+	case KeyPageUp:
+		e.move(-10)
+	case KeyPageDown:
+		e.move(10)
+	case KeyHome:
+		e.move(-len(e.visible))
+	case KeyEnd:
+		e.move(len(e.visible))
+	case KeyEnter:
+		if len(e.visible) > 0 {
+			e.mode = detailMode
+			e.detailOffset = 0
+		}
+	case KeyCtrlC:
+		return true
+	case KeyEscape:
+		if e.packageFilter != "" {
+			e.packageFilter = ""
+			e.applyFilters()
+		}
+	case KeyRune:
+		switch k.Rune {
+		case 'k':
+			e.move(-1)
+		case 'j':
+			e.move(1)
+		case 'g':
+			e.move(-len(e.visible))
+		case 'G':
+			e.move(len(e.visible))
+		case 's':
+			e.minSeverity = (e.minSeverity + 1) % (vulnerability.CriticalSeverity + 1)
+			e.applyFilters()
+		case 'f':
+			e.fixState = (e.fixState + 1) % len(fixStates)
+			e.applyFilters()
+		case '/':
+			e.startInput("package: ", e.packageFilter, func(value string) {
+				e.packageFilter = strings.TrimSpace(value)
+				e.applyFilters()
+			})
+		case 'i':
+			e.toggleIgnore()
+		case 'w':
+			e.saveIgnores()
+		case 'q':
+			return e.quit()
+		}
+	}
+	return false
+}
+
+func (e *Explorer) handleDetail(k Key) bool {
+	switch k.Type {
+	case KeyUp:
+		e.scrollDetail(-1)
+	case KeyDown:
+		e.scrollDetail(1)
+	case KeyPageUp:
+		e.scrollDetail(-10)
+	case KeyPageDown:
+		e.scrollDetail(10)
+	case KeyEscape, KeyBackspace, KeyEnter:
+		e.mode = listMode
+	case KeyCtrlC:
+		return true
+	case KeyRune:
+		switch k.Rune {
+		case 'k':
+			e.scrollDetail(-1)
+		case 'j':
+			e.scrollDetail(1)
+		case 'i':
+			e.toggleIgnore()
+		case 'q':
+			e.mode = listMode
+		}
+	}
+	return false
+}
+
+func (e *Explorer) handleInput(k Key) {
+	switch k.Type {
+	case KeyEnter:
+		e.mode = e.input.previous
+		e.input.onSubmit(e.input.value)
+	case KeyEscape, KeyCtrlC:
+		e.mode = e.input.previous
+	case KeyBackspace:
+		if runes := []rune(e.input.value); len(runes) > 0 {
+			e.input.value = string(runes[:len(runes)-1])
+		}
+	case KeyRune:
+		e.input.value += string(k.Rune)
+	}
+}
+
+func (e *Explorer) startInput(prompt, value string, onSubmit func(string)) {
+	e.input = textInput{prompt: prompt, value: value, onSubmit: onSubmit, previous: e.mode}
+	e.mode = inputMode
+}
+
+// quit is done unless there are ignores that are not written yet, which must be confirmed by quitting again.
+func (e *Explorer) quit() bool {
+	if !e.unsaved || e.confirmQuit {
+		return true
+	}
+	e.confirmQuit = true
+	e.status = "there are unsaved ignores: press w to write them, or q again to quit without writing them"
+	return false
+}
+
+func (e *Explorer) move(delta int) {
+	e.cursor += delta
+	if e.cursor >= len(e.visible) {
+		e.cursor = len(e.visible) - 1
+	}
+	if e.cursor < 0 {
+		e.cursor = 0
+	}
+}
+
+func (e *Explorer) scrollDetail(delta int) {
+	e.detailOffset += delta
+	if e.detailOffset < 0 {
+		e.detailOffset = 0
+	}
+}
+
+// selected returns the index of the selected match (or -1 when no match is visible).
+func (e *Explorer) selected() int {
+	if len(e.visible) == 0 {
+		return -1
+	}
+	return e.visible[e.cursor]
+}
+
+// toggleIgnore marks the selected match to be ignored (asking for the reason), or unmarks it.
+func (e *Explorer) toggleIgnore() {
+	idx := e.selected()
+	if idx < 0 {
+		return
+	}
+	if _, ok := e.ignores[idx]; ok {
+		delete(e.ignores, idx)
+		e.unsaved = true
+		e.status = fmt.Sprintf("%s is no longer marked to be ignored", e.matches[idx].Vulnerability.ID)
+		return
+	}
+	e.startInput("reason for ignoring (optional): ", "", func(reason string) {
+		e.ignores[idx] = ignoreRule(e.matches[idx], strings.TrimSpace(reason))
+		e.unsaved = true
+		e.status = fmt.Sprintf("%s of %s is marked to be ignored (press w to write the ignores)", e.matches[idx].Vulnerability.ID, e.matches[idx].Artifact.Name)
+	})
+}
+
+func (e *Explorer) saveIgnores() {
+	if !e.unsaved {
+		e.status = "there are no new ignores to write"
+		return
+	}
+	indexes := make([]int, 0, len(e.ignores))
+	for idx := range e.ignores {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+	rules := make([]match.IgnoreRule, 0, len(indexes))
+	for _, idx := range indexes {
+		rules = append(rules, e.ignores[idx])
+	}
+
+	if err := e.writeIgnores(rules); err != nil {
+		e.status = fmt.Sprintf("unable to write the ignores: %+v", err)
+		return
+	}
+	e.unsaved = false
+	e.status = fmt.Sprintf("wrote %d ignore rules", len(rules))
+}
+
+// ignoreRule returns the rule that ignores the vulnerability of the given match for the version of its package.
+func ignoreRule(m models.Match, reason string) match.IgnoreRule {
+	return match.IgnoreRule{
+		Vulnerability: m.Vulnerability.ID,
+		Package: match.IgnoreRulePackage{
+			Name:    m.Artifact.Name,
+			Version: m.Artifact.Version,
+			Type:    string(m.Artifact.Type),
+		},
+		Reason: reason,
+	}
+}
+
+// applyFilters updates the visible matches, keeping the selected match selected when it is still visible.
+func (e *Explorer) applyFilters() {
+	selected := e.selected()
+	e.visible = e.visible[:0]
+	query := strings.ToLower(e.packageFilter)
+	for idx, m := range e.matches {
+		if e.minSeverity != vulnerability.UnknownSeverity && vulnerability.ParseSeverity(m.Vulnerability.Severity) < e.minSeverity {
+			continue
+		}
+		if state := fixStates[e.fixState]; state != "" && !strings.EqualFold(m.Vulnerability.Fix.State, state) {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(m.Artifact.Name), query) {
+			continue
+		}
+		e.visible = append(e.visible, idx)
+	}
+
+	e.cursor = 0
+	for i, idx := range e.visible {
+		if idx == selected {
+			e.cursor = i
+			break
+		}
+	}
+}
+
+// Render draws the explorer as lines of the given width (in runes), filling the given height.
+func (e *Explorer) Render(width, height int) []string {
+	if width < 20 || height < 5 {
+		return []string{truncate("the terminal is too small", width)}
+	}
+	lines := []string{boldStyle + pad(e.header(), width) + resetStyle}
+
+	body := height - 3
+	if e.mode == detailMode || (e.mode == inputMode && e.input.previous == detailMode) {
+		lines = append(lines, e.renderDetail(width, body)...)
+	} else {
+		lines = append(lines, e.renderList(width, body)...)
+	}
+
+	for len(lines) < height-2 {
+		lines = append(lines, "")
+	}
+	lines = append(lines, pad(e.statusLine(), width), reverseStyle+pad(e.helpLine(), width)+resetStyle)
+	return lines
+}
+
+func (e *Explorer) header() string {
+	filters := []string{fmt.Sprintf("%d of %d matches", len(e.visible), len(e.matches))}
+	if e.minSeverity != vulnerability.UnknownSeverity {
+		filters = append(filters, "severity >= "+e.minSeverity.String())
+	}
+	if state := fixStates[e.fixState]; state != "" {
+		filters = append(filters, "fix state "+state)
+	}
+	if e.packageFilter != "" {
+		filters = append(filters, fmt.Sprintf("package ~ %q", e.packageFilter))
+	}
+	if len(e.ignores) > 0 {
+		filters = append(filters, fmt.Sprintf("%d marked to ignore", len(e.ignores)))
+	}
+	return "grype explore: " + strings.Join(filters, " | ")
+}
+
+func (e *Explorer) statusLine() string {
+	if e.mode == inputMode {
+		return e.input.prompt + e.input.value + "█"
+	}
+	return e.status
+}
+
+func (e *Explorer) helpLine() string {
+	switch e.mode {
+	case inputMode:
+		return "enter: apply  esc: cancel"
+	case detailMode:
+		return "↑/↓: scroll  i: ignore  esc/q: back"
+	default:
+		return "↑/↓: move  enter: details  s: severity  f: fix state  /: package  i: ignore  w: write ignores  q: quit"
+	}
+}
+
+func (e *Explorer) renderList(width, height int) []string {
+	if len(e.visible) == 0 {
+		return []string{"", "  no matches (press s, f, or / to change the filters)"}
+	}
+
+	// keep the cursor within the rows that are shown
+	rows := height - 1
+	if e.cursor < e.offset {
+		e.offset = e.cursor
+	}
+	if e.cursor >= e.offset+rows {
+		e.offset = e.cursor - rows + 1
+	}
+
+	lines := []string{pad(fmt.Sprintf("  %-10s %-20s %-30s %-20s %-12s %s", "SEVERITY", "VULNERABILITY", "PACKAGE", "VERSION", "FIX STATE", "FIXED IN"), width)}
+	for i := e.offset; i < len(e.visible) && i < e.offset+rows; i++ {
+		idx := e.visible[i]
+		m := e.matches[idx]
+		marker := " "
+		if _, ok := e.ignores[idx]; ok {
+			marker = "I"
+		}
+		line := pad(fmt.Sprintf("%s %-10s %-20s %-30s %-20s %-12s %s", marker, m.Vulnerability.Severity, m.Vulnerability.ID, m.Artifact.Name, m.Artifact.Version, m.Vulnerability.Fix.State, strings.Join(m.Vulnerability.Fix.Versions, ", ")), width)
+		if i == e.cursor {
+			line = reverseStyle + line + resetStyle
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func (e *Explorer) renderDetail(width, height int) []string {
+	idx := e.selected()
+	if idx < 0 {
+		return nil
+	}
+	var lines []string
+	for _, line := range detailLines(e.matches[idx]) {
+		lines = append(lines, wrap(line, width)...)
+	}
+
+	if e.detailOffset > len(lines)-height {
+		e.detailOffset = len(lines) - height
+	}
+	if e.detailOffset < 0 {
+		e.detailOffset = 0
+	}
+	end := e.detailOffset + height
+	if end > len(lines) {
+		end = len(lines)
+	}
+	return lines[e.detailOffset:end]
+}
+
+// detailLines describes a match: the vulnerability (with its description, scores, and references), the fix, the
+// related vulnerabilities, and the package.
+func detailLines(m models.Match) []string {
+	v := m.Vulnerability
+	lines := []string{
+		fmt.Sprintf("%s (%s)", v.ID, v.Severity),
+		"",
+	}
+	if v.Description != "" {
+		lines = append(lines, v.Description, "")
+	}
+	for _, cvss := range v.Cvss {
+		lines = append(lines, fmt.Sprintf("CVSS %s: %.1f %s", cvss.Version, cvss.Metrics.BaseScore, cvss.Vector))
+	}
+
+	fix := v.Fix.State
+	if len(v.Fix.Versions) > 0 {
+		fix = fmt.Sprintf("%s in %s", fix, strings.Join(v.Fix.Versions, ", "))
+	}
+	lines = append(lines,
+		"Namespace: "+v.Namespace,
+		"Data source: "+v.DataSource,
+		"Fix: "+fix,
+		"",
+		fmt.Sprintf("Package: %s %s (%s)", m.Artifact.Name, m.Artifact.Version, m.Artifact.Type),
+	)
+	if m.Artifact.PURL != "" {
+		lines = append(lines, "PURL: "+m.Artifact.PURL)
+	}
+	for _, l := range m.Artifact.Locations {
+		lines = append(lines, "Location: "+l.RealPath)
+	}
+
+	var references []string
+	references = append(references, v.URLs...)
+	for _, a := range v.Advisories {
+		references = append(references, a.Link)
+	}
+	if len(references) > 0 {
+		lines = append(lines, "", "References:")
+		for _, r := range references {
+			lines = append(lines, "  "+r)
+		}
+	}
+
+	if len(m.RelatedVulnerabilities) > 0 {
+		lines = append(lines, "", "Related vulnerabilities:")
+		for _, related := range m.RelatedVulnerabilities {
+			lines = append(lines, fmt.Sprintf("  %s (%s, %s)", related.ID, related.Severity, related.Namespace))
+			for _, u := range related.URLs {
+				lines = append(lines, "    "+u)
+			}
+		}
+	}
+
+	if len(m.MatchDetails) > 0 {
+		lines = append(lines, "", "Matched by:")
+		for _, d := range m.MatchDetails {
+			lines = append(lines, fmt.Sprintf("  %s (%s)", d.Matcher, d.Type))
+		}
+	}
+	return lines
+}
+
+// pad truncates or pads the line to exactly the given width (in runes).
+func pad(line string, width int) string {
+	line = truncate(line, width)
+	if n := len([]rune(line)); n < width {
+		line += strings.Repeat(" ", width-n)
+	}
+	return line
+}
+
+func truncate(line string, width int) string {
+	runes := []rune(line)
+	if len(runes) <= width {
+		return line
+	}
+	if width <= 1 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+// wrap breaks the line into lines of at most the given width (in runes), at spaces where possible.
+func wrap(line string, width int) []string {
+	runes := []rune(line)
+	if len(runes) <= width {
+		return []string{line}
+	}
+	var lines []string
+	for len(runes) > width {
+		cut := width
+		for i := width; i > width/2; i-- {
+			if runes[i] == ' ' {
+				cut = i
+				break
+			}
+		}
+		lines = append(lines, string(runes[:cut]))
+		runes = []rune(strings.TrimLeft(string(runes[cut:]), " "))
+	}
+	return append(lines, string(runes))
+}
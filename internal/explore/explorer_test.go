@@ -0,0 +1,226 @@
+package explore
+
+import (
+	"bufio"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+	"github.com/anchore/grype/grype/presenter/models"
+)
+
+func newTestMatch(id, severity, fixState, pkgName string) models.Match {
+	m := models.Match{
+		Artifact: models.Package{Name: pkgName, Version: "1.0.0", Type: "apk"},
+	}
+	m.Vulnerability.ID = id
+	m.Vulnerability.Severity = severity
+	m.Vulnerability.Fix.State = fixState
+	m.Vulnerability.Description = "a vulnerability of " + pkgName
+	m.Vulnerability.URLs = []string{"https://example.com/" + id}
+	return m
+}
+
+func testMatches() []models.Match {
+	return []models.Match{
+		newTestMatch("CVE-2021-0001", "Critical", "fixed", "openssl"),
+		newTestMatch("CVE-2021-0002", "Low", "not-fixed", "busybox"),
+		newTestMatch("CVE-2021-0003", "High", "not-fixed", "libssl"),
+		newTestMatch("CVE-2021-0004", "Medium", "wont-fix", "zlib"),
+	}
+}
+
+func runes(value string) []Key {
+	var keys []Key
+	for _, r := range value {
+		keys = append(keys, Key{Type: KeyRune, Rune: r})
+	}
+	return keys
+}
+
+func visibleIDs(e *Explorer) []string {
+	var ids []string
+	for _, idx := range e.visible {
+		ids = append(ids, e.matches[idx].Vulnerability.ID)
+	}
+	return ids
+}
+
+func TestExplorer_filters(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []Key
+		expected []string
+	}{
+		{
+			name:     "no filters",
+			expected: []string{"CVE-2021-0001", "CVE-2021-0002", "CVE-2021-0003", "CVE-2021-0004"},
+		},
+		{
+			name:     "minimum severity high",
+			keys:     runes("ssss"),
+			expected: []string{"CVE-2021-0001", "CVE-2021-0003"},
+		},
+		{
+			name:     "severity filter wraps around",
+			keys:     runes("ssssss"),
+			expected: []string{"CVE-2021-0001", "CVE-2021-0002", "CVE-2021-0003", "CVE-2021-0004"},
+		},
+		{
+			name:     "not fixed",
+			keys:     runes("ff"),
+			expected: []string{"CVE-2021-0002", "CVE-2021-0003"},
+		},
+		{
+			name:     "package",
+			keys:     append(runes("/SSL"), Key{Type: KeyEnter}),
+			expected: []string{"CVE-2021-0001", "CVE-2021-0003"},
+		},
+		{
+			name:     "canceled package filter",
+			keys:     append(runes("/ssl"), Key{Type: KeyEscape}),
+			expected: []string{"CVE-2021-0001", "CVE-2021-0002", "CVE-2021-0003", "CVE-2021-0004"},
+		},
+		{
+			name:     "combined",
+			keys:     append(append(runes("ff/ssl"), Key{Type: KeyEnter}), runes("ssss")...),
+			expected: []string{"CVE-2021-0003"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e := New(testMatches(), nil)
+			for _, k := range test.keys {
+				assert.False(t, e.Handle(k))
+			}
+			assert.Equal(t, test.expected, visibleIDs(e))
+		})
+	}
+}
+
+func TestExplorer_keepsSelection(t *testing.T) {
+	e := New(testMatches(), nil)
+	e.Handle(Key{Type: KeyDown})
+	e.Handle(Key{Type: KeyDown})
+	require.Equal(t, "CVE-2021-0003", e.matches[e.selected()].Vulnerability.ID)
+
+	// the selected match is still visible (with a severity of at least high), so it stays selected
+	for _, k := range runes("ssss") {
+		e.Handle(k)
+	}
+	assert.Equal(t, "CVE-2021-0003", e.matches[e.selected()].Vulnerability.ID)
+
+	// the cursor does not move past the visible matches
+	e.Handle(Key{Type: KeyEnd})
+	e.Handle(Key{Type: KeyDown})
+	assert.Equal(t, 1, e.cursor)
+}
+
+func TestExplorer_ignores(t *testing.T) {
+	var written [][]match.IgnoreRule
+	e := New(testMatches(), func(rules []match.IgnoreRule) error {
+		written = append(written, rules)
+		return nil
+	})
+
+	// mark the second match, with a reason
+	e.Handle(Key{Type: KeyDown})
+	e.Handle(Key{Type: KeyRune, Rune: 'i'})
+	for _, k := range append(runes("not reachable"), Key{Type: KeyEnter}) {
+		e.Handle(k)
+	}
+	// mark and unmark the first match
+	e.Handle(Key{Type: KeyUp})
+	e.Handle(Key{Type: KeyRune, Rune: 'i'})
+	e.Handle(Key{Type: KeyEnter})
+	e.Handle(Key{Type: KeyRune, Rune: 'i'})
+
+	// quitting with unsaved ignores must be confirmed
+	assert.False(t, e.Handle(Key{Type: KeyRune, Rune: 'q'}))
+	assert.Contains(t, e.status, "unsaved")
+
+	e.Handle(Key{Type: KeyRune, Rune: 'w'})
+	require.Len(t, written, 1)
+	assert.Equal(t, []match.IgnoreRule{{
+		Vulnerability: "CVE-2021-0002",
+		Package:       match.IgnoreRulePackage{Name: "busybox", Version: "1.0.0", Type: "apk"},
+		Reason:        "not reachable",
+	}}, written[0])
+
+	assert.True(t, e.Handle(Key{Type: KeyRune, Rune: 'q'}))
+}
+
+func TestExplorer_failedWrite(t *testing.T) {
+	e := New(testMatches(), func([]match.IgnoreRule) error {
+		return errors.New("read-only file system")
+	})
+	e.Handle(Key{Type: KeyRune, Rune: 'i'})
+	e.Handle(Key{Type: KeyEnter})
+	e.Handle(Key{Type: KeyRune, Rune: 'w'})
+
+	assert.Contains(t, e.status, "read-only file system")
+	assert.True(t, e.unsaved)
+}
+
+var ansi = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestExplorer_Render(t *testing.T) {
+	e := New(testMatches(), nil)
+	e.Handle(Key{Type: KeyDown})
+
+	lines := e.Render(120, 12)
+	require.Len(t, lines, 12)
+	for _, line := range lines {
+		assert.LessOrEqual(t, len([]rune(ansi.ReplaceAllString(line, ""))), 120)
+	}
+	assert.Contains(t, lines[0], "4 of 4 matches")
+	assert.Contains(t, lines[3], "CVE-2021-0002")
+	assert.True(t, strings.HasPrefix(lines[3], reverseStyle), "the selected match is highlighted")
+
+	// the details of the selected match
+	e.Handle(Key{Type: KeyEnter})
+	screen := strings.Join(e.Render(120, 30), "\n")
+	assert.Contains(t, screen, "CVE-2021-0002 (Low)")
+	assert.Contains(t, screen, "a vulnerability of busybox")
+	assert.Contains(t, screen, "https://example.com/CVE-2021-0002")
+
+	e.Handle(Key{Type: KeyEscape})
+	assert.Equal(t, listMode, e.mode)
+}
+
+func TestReadKey(t *testing.T) {
+	input := "j\x1b[A\x1b[B\x1bOA\x1b[5~\x1b[6~\r\x7f\x03\x1b[Zé\x1b"
+	expected := []Key{
+		{Type: KeyRune, Rune: 'j'},
+		{Type: KeyUp},
+		{Type: KeyDown},
+		{Type: KeyUp},
+		{Type: KeyPageUp},
+		{Type: KeyPageDown},
+		{Type: KeyEnter},
+		{Type: KeyBackspace},
+		{Type: KeyCtrlC},
+		{Type: KeyUnknown},
+		{Type: KeyRune, Rune: 'é'},
+		{Type: KeyEscape},
+	}
+
+	r := bufio.NewReader(strings.NewReader(input))
+	for _, want := range expected {
+		k, err := readKey(r)
+		require.NoError(t, err)
+		assert.Equal(t, want, k)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, []string{"short"}, wrap("short", 10))
+	assert.Equal(t, []string{"a long line", "of text"}, wrap("a long line of text", 12))
+	assert.Equal(t, []string{"abcdef", "ghij"}, wrap("abcdefghij", 6))
+}
@@ -0,0 +1,149 @@
+package explore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anchore/grype/grype/match"
+)
+
+// AppendIgnoreRules adds the given rules to the ignore rules of the YAML config file at the given path (which is
+// created when it does not exist), keeping the rest of the file (including comments) as it is. Rules that are
+// already in the file are not added again.
+func AppendIgnoreRules(path string, rules []match.IgnoreRule) error {
+	if ext := strings.ToLower(filepath.Ext(path)); ext != ".yaml" && ext != ".yml" {
+		return fmt.Errorf("ignore rules can only be written to a YAML config file (not %q)", path)
+	}
+
+	mode := os.FileMode(0644)
+	contents, err := ioutil.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		contents = nil
+	case err != nil:
+		return fmt.Errorf("unable to read config=%q: %w", path, err)
+	default:
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+	}
+
+	updated, err := appendIgnoreRules(contents, rules)
+	if err != nil {
+		return fmt.Errorf("unable to update config=%q: %w", path, err)
+	}
+	if err := ioutil.WriteFile(path, updated, mode); err != nil {
+		return fmt.Errorf("unable to write config=%q: %w", path, err)
+	}
+	return nil
+}
+
+func appendIgnoreRules(contents []byte, rules []match.IgnoreRule) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(contents, &doc); err != nil {
+		return nil, err
+	}
+	// note: the comments of a file without any values (e.g. only comments) are kept as they are, before the rules
+	var prefix []byte
+	if doc.Kind == 0 || (len(doc.Content) > 0 && doc.Content[0].Tag == "!!null") {
+		if trimmed := bytes.TrimRight(contents, "\n"); len(trimmed) > 0 {
+			prefix = append(trimmed, '\n')
+		}
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("the config is not a mapping")
+	}
+
+	ignore := mappingValue(root, "ignore")
+	if ignore == nil {
+		ignore = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "ignore"}, ignore)
+	}
+	if ignore.Kind != yaml.SequenceNode {
+		if ignore.Tag != "!!null" {
+			return nil, fmt.Errorf("the ignore rules of the config are not a list")
+		}
+		*ignore = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", LineComment: ignore.LineComment}
+	}
+	// note: the rules are always written in the block style (even when the list was empty, i.e. "ignore: []")
+	ignore.Style = 0
+
+	var existing []match.IgnoreRule
+	if err := ignore.Decode(&existing); err != nil {
+		return nil, fmt.Errorf("unable to read the ignore rules of the config: %w", err)
+	}
+	for _, rule := range rules {
+		if containsRule(existing, rule) {
+			continue
+		}
+		ignore.Content = append(ignore.Content, ruleNode(rule))
+		existing = append(existing, rule)
+	}
+
+	buf := bytes.NewBuffer(prefix)
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// mappingValue returns the value of the given key of the mapping (or nil when the key is not in the mapping).
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func containsRule(rules []match.IgnoreRule, rule match.IgnoreRule) bool {
+	for _, r := range rules {
+		// note: the reason is not a criterion of the rule
+		if r.Vulnerability == rule.Vulnerability && r.FixState == rule.FixState && r.Package == rule.Package {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleNode returns the YAML of the rule with only the fields that are set (the way rules are written by hand).
+func ruleNode(rule match.IgnoreRule) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	add := func(mapping *yaml.Node, key, value string) {
+		if value == "" {
+			return
+		}
+		mapping.Content = append(mapping.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value},
+		)
+	}
+
+	add(node, "vulnerability", rule.Vulnerability)
+	add(node, "fix-state", rule.FixState)
+	pkgNode := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	add(pkgNode, "name", rule.Package.Name)
+	add(pkgNode, "version", rule.Package.Version)
+	add(pkgNode, "type", rule.Package.Type)
+	add(pkgNode, "location", rule.Package.Location)
+	if len(pkgNode.Content) > 0 {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "package"}, pkgNode)
+	}
+	add(node, "reason", rule.Reason)
+	return node
+}
@@ -0,0 +1,94 @@
+package explore
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/grype/grype/match"
+)
+
+func TestAppendIgnoreRules(t *testing.T) {
+	rule := match.IgnoreRule{
+		Vulnerability: "CVE-2021-0002",
+		Package:       match.IgnoreRulePackage{Name: "busybox", Version: "1.0.0", Type: "apk"},
+		Reason:        "not reachable",
+	}
+	ruleYAML := `  - vulnerability: CVE-2021-0002
+    package:
+      name: busybox
+      version: 1.0.0
+      type: apk
+    reason: not reachable
+`
+
+	tests := []struct {
+		name     string
+		existing string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "new file",
+			expected: "ignore:\n" + ruleYAML,
+		},
+		{
+			name:     "empty file",
+			existing: "# grype config\n",
+			expected: "# grype config\nignore:\n" + ruleYAML,
+		},
+		{
+			name:     "no ignore rules",
+			existing: "# the output format\noutput: json\n",
+			expected: "# the output format\noutput: json\nignore:\n" + ruleYAML,
+		},
+		{
+			name:     "empty ignore rules",
+			existing: "output: json\nignore: []\n",
+			expected: "output: json\nignore:\n" + ruleYAML,
+		},
+		{
+			name:     "existing ignore rules",
+			existing: "ignore:\n  # not exploitable\n  - vulnerability: CVE-2020-0001\n",
+			expected: "ignore:\n  # not exploitable\n  - vulnerability: CVE-2020-0001\n" + ruleYAML,
+		},
+		{
+			name:     "rule already exists (with another reason)",
+			existing: "ignore:\n  - vulnerability: CVE-2021-0002\n    package:\n      name: busybox\n      version: 1.0.0\n      type: apk\n",
+			expected: "ignore:\n  - vulnerability: CVE-2021-0002\n    package:\n      name: busybox\n      version: 1.0.0\n      type: apk\n",
+		},
+		{
+			name:     "ignore rules are not a list",
+			existing: "ignore: CVE-2020-0001\n",
+			wantErr:  true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".grype.yaml")
+			if test.existing != "" {
+				require.NoError(t, ioutil.WriteFile(path, []byte(test.existing), 0600))
+			}
+
+			err := AppendIgnoreRules(path, []match.IgnoreRule{rule})
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			contents, err := ioutil.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(contents))
+		})
+	}
+}
+
+func TestAppendIgnoreRules_notYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.Error(t, AppendIgnoreRules(path, []match.IgnoreRule{{Vulnerability: "CVE-2021-0002"}}))
+}
@@ -0,0 +1,105 @@
+package explore
+
+import (
+	"bufio"
+	"unicode/utf8"
+)
+
+// KeyType is the kind of a key pressed by the user.
+type KeyType int
+
+const (
+	// KeyRune is a printable character (see Key.Rune)
+	KeyRune KeyType = iota
+	KeyUp
+	KeyDown
+	KeyPageUp
+	KeyPageDown
+	KeyHome
+	KeyEnd
+	KeyEnter
+	KeyEscape
+	KeyBackspace
+	KeyCtrlC
+	// KeyUnknown is a key (or escape sequence) that the explorer does not use
+	KeyUnknown
+)
+
+// Key is a key pressed by the user.
+type Key struct {
+	Type KeyType
+	Rune rune
+}
+
+// the escape sequences of the keys that are used by the explorer (in the forms of common terminals)
+var escapeSequences = map[string]KeyType{
+	"[A":  KeyUp,
+	"OA":  KeyUp,
+	"[B":  KeyDown,
+	"OB":  KeyDown,
+	"[5~": KeyPageUp,
+	"[6~": KeyPageDown,
+	"[H":  KeyHome,
+	"OH":  KeyHome,
+	"[1~": KeyHome,
+	"[F":  KeyEnd,
+	"OF":  KeyEnd,
+	"[4~": KeyEnd,
+}
+
+// readKey reads the next key from the input of a terminal in raw mode, where an escape that is not followed by more
+// (already buffered) input is the escape key itself.
+func readKey(r *bufio.Reader) (Key, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return Key{}, err
+	}
+
+	switch c {
+	case '\r', '\n':
+		return Key{Type: KeyEnter}, nil
+	case 0x7f, 0x08:
+		return Key{Type: KeyBackspace}, nil
+	case 0x03:
+		return Key{Type: KeyCtrlC}, nil
+	case 0x1b:
+		if r.Buffered() == 0 {
+			return Key{Type: KeyEscape}, nil
+		}
+		return readEscapeSequence(r)
+	case utf8.RuneError:
+		return Key{Type: KeyUnknown}, nil
+	}
+
+	if c < 0x20 {
+		return Key{Type: KeyUnknown}, nil
+	}
+	return Key{Type: KeyRune, Rune: c}, nil
+}
+
+// readEscapeSequence reads the rest of a CSI ("\x1b[...") or SS3 ("\x1bO.") sequence.
+func readEscapeSequence(r *bufio.Reader) (Key, error) {
+	first, err := r.ReadByte()
+	if err != nil {
+		return Key{}, err
+	}
+	sequence := []byte{first}
+	if first == '[' || first == 'O' {
+		for r.Buffered() > 0 {
+			b, err := r.ReadByte()
+			if err != nil {
+				return Key{}, err
+			}
+			sequence = append(sequence, b)
+			// note: the final byte of a sequence is a letter or a tilde
+			if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || b == '~' {
+				break
+			}
+		}
+	}
+
+	if t, ok := escapeSequences[string(sequence)]; ok {
+		return Key{Type: t}, nil
+	}
+	return Key{Type: KeyUnknown}, nil
+}
@@ -0,0 +1,71 @@
+package explore
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// the escape sequences that switch to (and back from) the alternate screen of the terminal, so that the screen of the
+// user is restored when the explorer is done
+const (
+	enterAlternateScreen = "\x1b[?1049h\x1b[?25l"
+	exitAlternateScreen  = "\x1b[?25h\x1b[?1049l"
+	clearScreen          = "\x1b[H\x1b[2J"
+)
+
+// IsTerminal indicates whether the explorer can run with the given input and output (which must both be terminals).
+func IsTerminal(in, out *os.File) bool {
+	return term.IsTerminal(int(in.Fd())) && term.IsTerminal(int(out.Fd()))
+}
+
+// Run runs the explorer on the terminal of the given input and output until the user quits.
+func Run(e *Explorer, in, out *os.File) (err error) {
+	if !IsTerminal(in, out) {
+		return errors.New("the result explorer requires an interactive terminal")
+	}
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("unable to prepare the terminal: %w", err)
+	}
+	defer func() {
+		_, _ = io.WriteString(out, exitAlternateScreen)
+		if restoreErr := term.Restore(int(in.Fd()), state); restoreErr != nil && err == nil {
+			err = fmt.Errorf("unable to restore the terminal: %w", restoreErr)
+		}
+	}()
+	if _, err := io.WriteString(out, enterAlternateScreen); err != nil {
+		return err
+	}
+
+	keys := bufio.NewReader(in)
+	for {
+		// note: the size is read before every draw, so that the explorer follows resizes of the terminal (where the last
+		// column is not drawn, since writing to it wraps the line on some terminals)
+		width, height, err := term.GetSize(int(out.Fd()))
+		if err != nil {
+			return fmt.Errorf("unable to get the size of the terminal: %w", err)
+		}
+		// note: lines end with a carriage return, since the terminal is in raw mode
+		screen := clearScreen + strings.Join(e.Render(width-1, height), "\r\n")
+		if _, err := io.WriteString(out, screen); err != nil {
+			return err
+		}
+
+		k, err := readKey(keys)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("unable to read key: %w", err)
+		}
+		if e.Handle(k) {
+			return nil
+		}
+	}
+}
@@ -1,13 +1,22 @@
 package file
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/go-getter/helper/url"
 
 	"github.com/anchore/grype/internal"
+	"github.com/anchore/grype/internal/log"
+	"github.com/anchore/grype/internal/proxy"
+	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-getter"
 	"github.com/wagoodman/go-progress"
 )
@@ -33,15 +42,39 @@ type Getter interface {
 
 type HashiGoGetter struct {
 	httpGetter getter.HttpGetter
+	policy     RetryPolicy
+	wait       func(time.Duration)
 }
 
 // NewGetter creates and returns a new Getter. Providing an http.Client is optional. If one is provided,
 // it will be used for all HTTP(S) getting; otherwise, go-getter's default getters will be used.
+// HTTP(S) requests and downloads are retried according to the DefaultRetryPolicy.
 func NewGetter(httpClient *http.Client) *HashiGoGetter {
+	return newGetter(httpClient, DefaultRetryPolicy(), time.Sleep)
+}
+
+func newGetter(httpClient *http.Client, policy RetryPolicy, wait func(time.Duration)) *HashiGoGetter {
+	if httpClient == nil {
+		httpClient = cleanhttp.DefaultClient()
+		httpClient.Transport.(*http.Transport).Proxy = proxy.ForRequest
+	}
+	retryingClient := *httpClient
+	transport := retryingClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	retryingClient.Transport = &retryTransport{
+		base:   transport,
+		policy: policy,
+		wait:   wait,
+	}
+
 	return &HashiGoGetter{
 		httpGetter: getter.HttpGetter{
-			Client: httpClient,
+			Client: &retryingClient,
 		},
+		policy: policy,
+		wait:   wait,
 	}
 }
 
@@ -50,7 +83,10 @@ func (g HashiGoGetter) GetFile(dst, src string, monitors ...*progress.Manual) er
 		return fmt.Errorf("multiple monitors provided, which is not allowed")
 	}
 
-	return getterClient(dst, src, false, g.httpGetter, monitors).Get()
+	if !internal.HasAnyOfPrefixes(src, "http://", "https://") {
+		return getterClient(dst, src, false, g.httpGetter, monitors).Get()
+	}
+	return g.getResumable(dst, src, monitors)
 }
 
 func (g HashiGoGetter) GetToDir(dst, src string, monitors ...*progress.Manual) error {
@@ -62,7 +98,76 @@ func (g HashiGoGetter) GetToDir(dst, src string, monitors ...*progress.Manual) e
 		return fmt.Errorf("multiple monitors provided, which is not allowed")
 	}
 
-	return getterClient(dst, src, true, g.httpGetter, monitors).Get()
+	if !internal.HasAnyOfPrefixes(src, "http://", "https://") {
+		return getterClient(dst, src, true, g.httpGetter, monitors).Get()
+	}
+
+	// the archive is downloaded (and resumed when interrupted) before it is extracted
+	u, err := url.Parse(src)
+	if err != nil {
+		return fmt.Errorf("bad URL provided %q: %w", src, err)
+	}
+	decompressor := archiveDecompressor(u.Path)
+	if decompressor == nil {
+		return ErrNonArchiveSource
+	}
+	query := u.Query()
+	query.Set("archive", "false")
+	u.RawQuery = query.Encode()
+
+	archiveDir, err := ioutil.TempDir("", "grype-download")
+	if err != nil {
+		return fmt.Errorf("unable to create download temp dir: %w", err)
+	}
+	defer os.RemoveAll(archiveDir)
+
+	archivePath := filepath.Join(archiveDir, "archive")
+	if err := g.getResumable(archivePath, u.String(), monitors); err != nil {
+		return err
+	}
+	return decompressor.Decompress(dst, archivePath, true, 0)
+}
+
+// getResumable downloads the given URL into the given path, retrying downloads that are interrupted by transient
+// errors. Retries resume the partial download (with a range request) when the server supports it.
+func (g HashiGoGetter) getResumable(dst, src string, monitors []*progress.Manual) error {
+	for attempt := 1; ; attempt++ {
+		resumed := false
+		if info, err := os.Stat(dst); err == nil && info.Size() > 0 {
+			resumed = true
+		}
+
+		err := getterClient(dst, src, false, g.httpGetter, monitors).Get()
+		if err == nil || attempt >= g.policy.MaxAttempts {
+			return err
+		}
+
+		var checksumErr *getter.ChecksumError
+		switch {
+		case errors.As(err, &checksumErr) && resumed:
+			// the partial download may have been corrupted (e.g. the resource changed in the meantime), start over
+			if err := os.Remove(dst); err != nil {
+				return fmt.Errorf("unable to remove partial download (%s): %w", dst, err)
+			}
+		case !isTransient(err):
+			return err
+		}
+
+		wait := g.policy.backoff(attempt)
+		log.Warnf("download interrupted, retrying in %s", wait)
+		log.Debugf("download failed (%s): %+v", src, err)
+		g.wait(wait)
+	}
+}
+
+// archiveDecompressor returns the decompressor for the given archive path (or nil when the path is not an archive).
+func archiveDecompressor(archivePath string) getter.Decompressor {
+	for _, ext := range archiveExtensions {
+		if strings.HasSuffix(archivePath, ext) {
+			return getter.Decompressors[strings.TrimPrefix(ext, ".")]
+		}
+	}
+	return nil
 }
 
 func validateHTTPSource(src string) error {
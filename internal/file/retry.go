@@ -0,0 +1,134 @@
+package file
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/anchore/grype/internal/log"
+)
+
+// RetryPolicy describes how failed requests and downloads are retried.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts of each request or download (including the first attempt)
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, which doubles with every further retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts, including waits requested by the server (via Retry-After)
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy for downloads from public endpoints.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+	}
+}
+
+// backoff returns the wait after the given (failed) attempt, with jitter so that many clients failing at the same time
+// (e.g. a fleet of CI jobs) do not retry at the same time.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.InitialBackoff << uint(attempt-1)
+	if wait > p.MaxBackoff || wait <= 0 {
+		wait = p.MaxBackoff
+	}
+	//nolint:gosec // jitter does not need a secure random source
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+// retryTransport retries idempotent requests that fail with a transient error or a retryable status (e.g. 429 Too
+// Many Requests or 503 Service Unavailable), honoring the Retry-After header of the response.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+	wait   func(time.Duration)
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if (req.Method != http.MethodGet && req.Method != http.MethodHead) || (req.Body != nil && req.Body != http.NoBody) {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.policy.MaxAttempts || req.Context().Err() != nil {
+			return resp, err
+		}
+
+		var wait time.Duration
+		switch {
+		case err != nil:
+			if !isTransient(err) {
+				return resp, err
+			}
+			wait = t.policy.backoff(attempt)
+			log.Debugf("request failed (%s %s), retrying: %+v", req.Method, req.URL.Redacted(), err)
+		case isRetryableStatus(resp.StatusCode):
+			wait = t.policy.backoff(attempt)
+			if after, ok := retryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				wait = after
+				if wait > t.policy.MaxBackoff {
+					wait = t.policy.MaxBackoff
+				}
+			}
+			log.Debugf("request failed (%s %s) with status=%d, retrying in %s", req.Method, req.URL.Redacted(), resp.StatusCode, wait)
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		default:
+			return resp, nil
+		}
+
+		t.wait(wait)
+	}
+}
+
+func isRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isTransient indicates if an error is caused by a network blip (e.g. a reset connection or a timeout), as opposed to
+// a permanent error (e.g. an untrusted certificate).
+func isTransient(err error) bool {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		return true
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.EPIPE):
+		return true
+	case errors.As(err, &netErr):
+		return netErr.Timeout()
+	}
+	return false
+}
+
+// retryAfter parses the value of a Retry-After header, which is either a number of seconds or an HTTP date.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if wait := at.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
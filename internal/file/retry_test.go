@@ -0,0 +1,170 @@
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, InitialBackoff: time.Second, MaxBackoff: 10 * time.Second}
+
+	tests := []struct {
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{attempt: 1, min: 500 * time.Millisecond, max: time.Second},
+		{attempt: 2, min: time.Second, max: 2 * time.Second},
+		{attempt: 3, min: 2 * time.Second, max: 4 * time.Second},
+		{attempt: 5, min: 5 * time.Second, max: 10 * time.Second},
+		{attempt: 100, min: 5 * time.Second, max: 10 * time.Second},
+	}
+
+	for _, test := range tests {
+		t.Run(strconv.Itoa(test.attempt), func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				actual := policy.backoff(test.attempt)
+				assert.GreaterOrEqual(t, int64(actual), int64(test.min))
+				assert.LessOrEqual(t, int64(actual), int64(test.max))
+			}
+		})
+	}
+}
+
+func Test_retryAfter(t *testing.T) {
+	now := time.Date(2021, 11, 20, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{value: "", ok: false},
+		{value: "120", expected: 2 * time.Minute, ok: true},
+		{value: "-1", ok: false},
+		{value: "Sat, 20 Nov 2021 08:00:30 GMT", expected: 30 * time.Second, ok: true},
+		{value: "Sat, 20 Nov 2021 07:00:00 GMT", expected: 0, ok: true},
+		{value: "later", ok: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.value, func(t *testing.T) {
+			actual, ok := retryAfter(test.value, now)
+			assert.Equal(t, test.ok, ok)
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func Test_isTransient(t *testing.T) {
+	assert.True(t, isTransient(fmt.Errorf("read: %w", io.ErrUnexpectedEOF)))
+	assert.True(t, isTransient(fmt.Errorf("read: %w", syscall.ECONNRESET)))
+	assert.False(t, isTransient(fmt.Errorf("bad response code: 404")))
+	assert.False(t, isTransient(ErrNonArchiveSource))
+}
+
+// recordedWaits records the waits between attempts instead of waiting.
+type recordedWaits struct {
+	lock  sync.Mutex
+	waits []time.Duration
+}
+
+func (r *recordedWaits) wait(d time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.waits = append(r.waits, d)
+}
+
+func TestGetter_RetriesWithRetryAfter(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		requests++
+		if requests < 3 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write(testFileContent)
+	}))
+	t.Cleanup(server.Close)
+
+	waits := &recordedWaits{}
+	getter := newGetter(nil, RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Minute}, waits.wait)
+
+	dst := filepath.Join(t.TempDir(), "listing.json")
+	require.NoError(t, getter.GetFile(dst, server.URL+"/listing.json"))
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, []time.Duration{7 * time.Second, 7 * time.Second}, waits.waits)
+
+	actual, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, testFileContent, actual)
+}
+
+func TestGetter_GivesUpAfterMaxAttempts(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet {
+			requests++
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	waits := &recordedWaits{}
+	getter := newGetter(nil, RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Minute}, waits.wait)
+
+	err := getter.GetFile(filepath.Join(t.TempDir(), "listing.json"), server.URL+"/listing.json")
+	assert.Error(t, err)
+	assert.Equal(t, 3, requests)
+}
+
+func TestGetter_ResumesInterruptedDownload(t *testing.T) {
+	content := bytes.Repeat(testFileContent, 1000)
+	tarball := createTarball("foo", content)
+
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == http.MethodGet {
+			ranges = append(ranges, req.Header.Get("Range"))
+			if len(ranges) == 1 {
+				// send half of the archive and then drop the connection
+				w.Header().Set("Content-Length", strconv.Itoa(len(tarball)))
+				_, _ = w.Write(tarball[:len(tarball)/2])
+				w.(http.Flusher).Flush()
+				panic(http.ErrAbortHandler)
+			}
+		}
+		http.ServeContent(w, req, "db.tar", time.Time{}, bytes.NewReader(tarball))
+	}))
+	t.Cleanup(server.Close)
+
+	waits := &recordedWaits{}
+	getter := newGetter(nil, RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Minute}, waits.wait)
+
+	dst := t.TempDir()
+	require.NoError(t, getter.GetToDir(dst, server.URL+"/db.tar"))
+	assert.Equal(t, []string{"", fmt.Sprintf("bytes=%d-", len(tarball)/2)}, ranges)
+	assert.Len(t, waits.waits, 1)
+
+	actual, err := os.ReadFile(filepath.Join(dst, "foo"))
+	require.NoError(t, err)
+	assert.Equal(t, content, actual)
+}
@@ -50,7 +50,12 @@ func UnTarGz(dst string, r io.Reader) error {
 	}
 	defer gzr.Close()
 
-	tr := tar.NewReader(gzr)
+	return UnTar(dst, gzr)
+}
+
+// UnTar extracts all files within the given (uncompressed) tar archive into the given directory.
+func UnTar(dst string, r io.Reader) error {
+	tr := tar.NewReader(r)
 
 	for {
 		header, err := tr.Next()
@@ -96,6 +101,42 @@ func UnTarGz(dst string, r io.Reader) error {
 	}
 }
 
+// Tar writes the given files into a tar archive (without their directories).
+func Tar(w io.Writer, paths ...string) error {
+	tw := tar.NewWriter(w)
+	for _, p := range paths {
+		if err := addToTar(tw, p); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+func addToTar(tw *tar.Writer, p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return fmt.Errorf("failed to open file (%s): %w", p, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat file (%s): %w", p, err)
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return fmt.Errorf("failed to create tar header (%s): %w", p, err)
+	}
+	header.Name = filepath.Base(p)
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write tar header (%s): %w", p, err)
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("failed to write file to tar (%s): %w", p, err)
+	}
+	return nil
+}
+
 func copyWithLimits(writer io.Writer, reader io.Reader, byteReadLimit int64, pathInArchive string) error {
 	if numBytes, err := io.Copy(writer, io.LimitReader(reader, byteReadLimit)); err != nil {
 		return fmt.Errorf("failed to copy file (%s): %w", pathInArchive, err)
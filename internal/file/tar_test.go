@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func assertErrorAs(expectedErr interface{}) assert.ErrorAssertionFunc {
@@ -136,3 +139,28 @@ func Test_copyWithLimits(t *testing.T) {
 		})
 	}
 }
+
+func TestTarRoundTrip(t *testing.T) {
+	src := t.TempDir()
+	files := map[string]string{
+		"listing.json": `{"available":{}}`,
+		"db.tar.gz":    "the archive",
+	}
+	var paths []string
+	for name, contents := range files {
+		p := filepath.Join(src, name)
+		require.NoError(t, os.WriteFile(p, []byte(contents), 0600))
+		paths = append(paths, p)
+	}
+
+	archive := &bytes.Buffer{}
+	require.NoError(t, Tar(archive, paths...))
+
+	dst := t.TempDir()
+	require.NoError(t, UnTar(dst, archive))
+	for name, contents := range files {
+		actual, err := os.ReadFile(filepath.Join(dst, name))
+		require.NoError(t, err)
+		assert.Equal(t, contents, string(actual))
+	}
+}
@@ -0,0 +1,177 @@
+package jsonschema
+
+import (
+	"path"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON schema specification that the generated schemas conform to.
+const Draft = "http://json-schema.org/draft-07/schema#"
+
+// Schema is a JSON schema (see https://json-schema.org), limited to the keywords needed to describe the JSON encoding
+// of Go values.
+type Schema struct {
+	Draft                string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Reflect returns the schema of the JSON encoding (as done by encoding/json) of the given struct value. Named structs
+// nested within the value are described once within the definitions of the schema, structs only allow the properties
+// of their fields, and properties of fields without "omitempty" are required. Pointers, slices and maps are nullable,
+// and fields of interface types allow any value.
+func Reflect(v interface{}) *Schema {
+	r := reflector{
+		names:       make(map[reflect.Type]string),
+		definitions: make(map[string]*Schema),
+	}
+	s := r.structSchema(reflect.TypeOf(v))
+	s.Draft = Draft
+	if len(r.definitions) > 0 {
+		s.Definitions = r.definitions
+	}
+	return s
+}
+
+type reflector struct {
+	names       map[reflect.Type]string
+	definitions map[string]*Schema
+}
+
+func (r *reflector) reflectType(t reflect.Type) *Schema {
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(r.reflectType(t.Elem()))
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return r.ref(t)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// byte slices are encoded as base64 strings
+			return nullable(&Schema{Type: "string"})
+		}
+		return nullable(&Schema{Type: "array", Items: r.reflectType(t.Elem())})
+	case reflect.Array:
+		return &Schema{Type: "array", Items: r.reflectType(t.Elem())}
+	case reflect.Map:
+		return nullable(&Schema{Type: "object", AdditionalProperties: r.reflectType(t.Elem())})
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	}
+	return &Schema{}
+}
+
+// ref returns a reference to the definition of the given named struct, adding the definition on first use.
+func (r *reflector) ref(t reflect.Type) *Schema {
+	name, exists := r.names[t]
+	if !exists {
+		name = r.definitionName(t)
+		// the name is reserved before the definition is built, so that recursive types refer to themselves
+		r.names[t] = name
+		r.definitions[name] = r.structSchema(t)
+	}
+	return &Schema{Ref: "#/definitions/" + name}
+}
+
+// definitionName returns the (capitalized) name of the given type, qualified by its package when another type of the
+// same name is already defined.
+func (r *reflector) definitionName(t reflect.Type) string {
+	name := capitalize(t.Name())
+	for _, existing := range r.names {
+		if existing == name {
+			return capitalize(path.Base(t.PkgPath())) + name
+		}
+	}
+	return name
+}
+
+func (r *reflector) structSchema(t reflect.Type) *Schema {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+	r.addFields(s, t)
+	return s
+}
+
+// addFields adds the properties of the fields of the given struct, where the fields of embedded structs are promoted.
+func (r *reflector) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options := parseTag(tag)
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				r.addFields(s, ft)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			// unexported fields are not encoded
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		s.Properties[name] = r.reflectType(f.Type)
+		if !strings.Contains(options, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func parseTag(tag string) (string, string) {
+	if idx := strings.Index(tag, ","); idx != -1 {
+		return tag[:idx], tag[idx+1:]
+	}
+	return tag, ""
+}
+
+func nullable(s *Schema) *Schema {
+	return &Schema{AnyOf: []*Schema{s, {Type: "null"}}}
+}
+
+func capitalize(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
@@ -0,0 +1,79 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type embedded struct {
+	Promoted string `json:"promoted"`
+}
+
+type node struct {
+	Name     string `json:"name"`
+	Children []node `json:"children,omitempty"`
+}
+
+type example struct {
+	embedded
+	Name     string            `json:"name"`
+	Count    int               `json:"count,omitempty"`
+	Score    float64           `json:"score"`
+	Enabled  bool              `json:"enabled"`
+	When     *time.Time        `json:"when,omitempty"`
+	Labels   map[string]string `json:"labels"`
+	Anything interface{}       `json:"anything"`
+	Tree     node              `json:"tree"`
+	Inline   struct {
+		Value string `json:"value"`
+	} `json:"inline"`
+	Untagged string
+	Ignored  string `json:"-"`
+	hidden   string
+}
+
+func TestReflect(t *testing.T) {
+	actual, err := json.Marshal(Reflect(example{}))
+	require.NoError(t, err)
+
+	expected := `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"promoted": {"type": "string"},
+			"name": {"type": "string"},
+			"count": {"type": "integer"},
+			"score": {"type": "number"},
+			"enabled": {"type": "boolean"},
+			"when": {"anyOf": [{"type": "string", "format": "date-time"}, {"type": "null"}]},
+			"labels": {"anyOf": [{"type": "object", "additionalProperties": {"type": "string"}}, {"type": "null"}]},
+			"anything": {},
+			"tree": {"$ref": "#/definitions/Node"},
+			"inline": {
+				"type": "object",
+				"properties": {"value": {"type": "string"}},
+				"required": ["value"],
+				"additionalProperties": false
+			},
+			"Untagged": {"type": "string"}
+		},
+		"required": ["promoted", "name", "score", "enabled", "labels", "anything", "tree", "inline", "Untagged"],
+		"additionalProperties": false,
+		"definitions": {
+			"Node": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string"},
+					"children": {"anyOf": [{"type": "array", "items": {"$ref": "#/definitions/Node"}}, {"type": "null"}]}
+				},
+				"required": ["name"],
+				"additionalProperties": false
+			}
+		}
+	}`
+	assert.JSONEq(t, expected, string(actual))
+}
@@ -35,3 +35,20 @@ func Debugf(format string, args ...interface{}) {
 func Debug(args ...interface{}) {
 	Log.Debug(args...)
 }
+
+// Fields are the structured fields of log entries (e.g. the digest of the scanned image or the version of the
+// database), which are keys of the entries in the JSON log format.
+type Fields map[string]interface{}
+
+type fieldLogger interface {
+	WithFields(fields map[string]interface{}) logger.Logger
+}
+
+// WithFields returns a logger that adds the given fields to its entries (where the fields are dropped when the
+// configured logger does not support fields).
+func WithFields(fields Fields) logger.Logger {
+	if l, ok := Log.(fieldLogger); ok {
+		return l.WithFields(fields)
+	}
+	return Log
+}
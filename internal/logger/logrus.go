@@ -6,11 +6,15 @@ import (
 	"io/fs"
 	"os"
 
+	"github.com/anchore/grype/grype/logger"
 	"github.com/sirupsen/logrus"
 	prefixed "github.com/x-cray/logrus-prefixed-formatter"
 )
 
-const defaultLogFilePermissions fs.FileMode = 0644
+const (
+	defaultLogFilePermissions fs.FileMode = 0644
+	jsonTimestampFormat                   = "2006-01-02T15:04:05.000Z07:00"
+)
 
 type LogrusConfig struct {
 	EnableConsole bool
@@ -57,11 +61,17 @@ func NewLogrusLogger(cfg LogrusConfig) *LogrusLogger {
 	appLogger.SetLevel(cfg.Level)
 
 	if cfg.Structured {
+		// note: the timestamp has the zone and sub-second precision so that log aggregation systems can order entries
+		// of many hosts
 		appLogger.SetFormatter(&logrus.JSONFormatter{
-			TimestampFormat:   "2006-01-02 15:04:05",
+			TimestampFormat:   jsonTimestampFormat,
 			DisableTimestamp:  false,
 			DisableHTMLEscape: false,
 			PrettyPrint:       false,
+			FieldMap: logrus.FieldMap{
+				logrus.FieldKeyTime: "timestamp",
+				logrus.FieldKeyMsg:  "message",
+			},
 		})
 	} else {
 		appLogger.SetFormatter(&prefixed.TextFormatter{
@@ -78,6 +88,11 @@ func NewLogrusLogger(cfg LogrusConfig) *LogrusLogger {
 	}
 }
 
+// WithFields returns a logger that adds the given fields to every entry.
+func (l *LogrusLogger) WithFields(fields map[string]interface{}) logger.Logger {
+	return &LogrusNestedLogger{Logger: l.Logger.WithFields(fields)}
+}
+
 func (l *LogrusLogger) Debugf(format string, args ...interface{}) {
 	l.Logger.Debugf(format, args...)
 }
@@ -110,6 +125,11 @@ func (l *LogrusLogger) Error(args ...interface{}) {
 	l.Logger.Error(args...)
 }
 
+// WithFields returns a logger that adds the given fields (in addition to the fields of this logger) to every entry.
+func (l *LogrusNestedLogger) WithFields(fields map[string]interface{}) logger.Logger {
+	return &LogrusNestedLogger{Logger: l.Logger.WithFields(fields)}
+}
+
 func (l *LogrusNestedLogger) Debugf(format string, args ...interface{}) {
 	l.Logger.Debugf(format, args...)
 }
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogrusLogger_Structured(t *testing.T) {
+	location := filepath.Join(t.TempDir(), "grype.log")
+	l := NewLogrusLogger(LogrusConfig{
+		EnableFile:   true,
+		Structured:   true,
+		Level:        logrus.InfoLevel,
+		FileLocation: location,
+	})
+
+	l.Infof("grype version: %s", "0.1.0")
+	nested := l.WithFields(map[string]interface{}{"db-schema-version": 3}).(*LogrusNestedLogger)
+	nested.WithFields(map[string]interface{}{"matcher": "apk-matcher"}).Warnf("matcher failed")
+	l.Debug("not logged")
+
+	f, err := os.Open(location)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "info", entries[0]["level"])
+	assert.Equal(t, "grype version: 0.1.0", entries[0]["message"])
+	_, err = time.Parse(time.RFC3339, entries[0]["timestamp"].(string))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "warning", entries[1]["level"])
+	assert.Equal(t, float64(3), entries[1]["db-schema-version"])
+	assert.Equal(t, "apk-matcher", entries[1]["matcher"])
+}
@@ -0,0 +1,189 @@
+package proxy
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/anchore/grype/internal/log"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Config is the proxy configuration of outgoing HTTP(S) connections, where the settings that are not given are taken
+// from the standard env vars (HTTP_PROXY, HTTPS_PROXY, ALL_PROXY, and NO_PROXY, or their lowercase forms).
+type Config struct {
+	// HTTPProxy is the proxy of http requests (an http, https, socks5, or socks5h URL)
+	HTTPProxy string
+	// HTTPSProxy is the proxy of https requests (an http, https, socks5, or socks5h URL)
+	HTTPSProxy string
+	// NoProxy are the comma separated hosts that are not proxied: host names (which also match their subdomains), IP
+	// addresses, and CIDR ranges (which also match the host names that resolve to an address in the range)
+	NoProxy string
+}
+
+var (
+	lock    sync.RWMutex
+	current func(*http.Request) (*url.URL, error)
+
+	// lookupIP resolves the host names that are matched against the CIDR ranges of NO_PROXY
+	lookupIP = net.LookupIP
+)
+
+// Configure applies the given config to every outgoing connection that uses ForRequest, including the connections of
+// the default HTTP transport.
+func Configure(cfg Config) error {
+	fn, err := cfg.proxyFunc()
+	if err != nil {
+		return err
+	}
+	lock.Lock()
+	current = fn
+	lock.Unlock()
+
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport.Proxy = ForRequest
+	}
+	return nil
+}
+
+// ForRequest returns the proxy of the given request (or nil when the request is not proxied), as configured, or else
+// as given by the env vars. This is a replacement of http.ProxyFromEnvironment for HTTP transports.
+func ForRequest(request *http.Request) (*url.URL, error) {
+	lock.RLock()
+	fn := current
+	lock.RUnlock()
+
+	if fn == nil {
+		var err error
+		if fn, err = (Config{}).proxyFunc(); err != nil {
+			return nil, err
+		}
+		lock.Lock()
+		current = fn
+		lock.Unlock()
+	}
+	return fn(request)
+}
+
+// withEnvironment returns the config with the settings that are not given taken from the env vars, where ALL_PROXY is
+// the proxy of both http and https requests without a specific proxy.
+func (cfg Config) withEnvironment() Config {
+	all := getEnv("ALL_PROXY")
+	if cfg.HTTPProxy == "" {
+		cfg.HTTPProxy = firstNonEmpty(getEnv("HTTP_PROXY"), all)
+	}
+	if cfg.HTTPSProxy == "" {
+		cfg.HTTPSProxy = firstNonEmpty(getEnv("HTTPS_PROXY"), all)
+	}
+	if cfg.NoProxy == "" {
+		cfg.NoProxy = getEnv("NO_PROXY")
+	}
+	return cfg
+}
+
+func (cfg Config) proxyFunc() (func(*http.Request) (*url.URL, error), error) {
+	cfg = cfg.withEnvironment()
+	for _, proxy := range []string{cfg.HTTPProxy, cfg.HTTPSProxy} {
+		if proxy == "" {
+			continue
+		}
+		if err := validateProxyURL(proxy); err != nil {
+			return nil, err
+		}
+	}
+
+	base := (&httpproxy.Config{
+		HTTPProxy:  cfg.HTTPProxy,
+		HTTPSProxy: cfg.HTTPSProxy,
+		NoProxy:    cfg.NoProxy,
+	}).ProxyFunc()
+	bypass := newResolvedBypass(cfg.NoProxy)
+
+	return func(request *http.Request) (*url.URL, error) {
+		proxy, err := base(request.URL)
+		if err != nil || proxy == nil {
+			return proxy, err
+		}
+		if bypass.matches(request.URL.Hostname()) {
+			return nil, nil
+		}
+		return proxy, nil
+	}, nil
+}
+
+func validateProxyURL(proxy string) error {
+	u, err := url.Parse(proxy)
+	if err != nil || u.Host == "" {
+		// note: a proxy without a scheme is an http proxy (e.g. "proxy.example.com:3128")
+		if u, err = url.Parse("http://" + proxy); err != nil || u.Host == "" {
+			return fmt.Errorf("bad proxy URL %q", proxy)
+		}
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return nil
+	default:
+		return fmt.Errorf("unsupported proxy scheme %q of proxy URL %q (supported schemes: http, https, socks5, socks5h)", u.Scheme, proxy)
+	}
+}
+
+// resolvedBypass matches the host names that resolve to an address within the CIDR ranges of NO_PROXY (which are not
+// otherwise matched, since only IP addresses are matched against CIDR ranges).
+type resolvedBypass struct {
+	networks []*net.IPNet
+	lock     sync.Mutex
+	resolved map[string]bool
+}
+
+func newResolvedBypass(noProxy string) *resolvedBypass {
+	bypass := &resolvedBypass{resolved: make(map[string]bool)}
+	for _, entry := range strings.Split(noProxy, ",") {
+		if _, network, err := net.ParseCIDR(strings.TrimSpace(entry)); err == nil {
+			bypass.networks = append(bypass.networks, network)
+		}
+	}
+	return bypass
+}
+
+func (b *resolvedBypass) matches(host string) bool {
+	if len(b.networks) == 0 || host == "" || net.ParseIP(host) != nil {
+		return false
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if matched, ok := b.resolved[host]; ok {
+		return matched
+	}
+
+	ips, err := lookupIP(host)
+	if err != nil {
+		// note: the proxy may be able to resolve the host
+		log.Debugf("unable to resolve host=%q to match the CIDR ranges of NO_PROXY: %+v", host, err)
+	}
+	matched := false
+	for _, ip := range ips {
+		for _, network := range b.networks {
+			matched = matched || network.Contains(ip)
+		}
+	}
+	b.resolved[host] = matched
+	return matched
+}
+
+func getEnv(name string) string {
+	return firstNonEmpty(os.Getenv(name), os.Getenv(strings.ToLower(name)))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
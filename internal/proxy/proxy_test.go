@@ -0,0 +1,134 @@
+package proxy
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var proxyEnvVars = []string{"HTTP_PROXY", "HTTPS_PROXY", "ALL_PROXY", "NO_PROXY", "REQUEST_METHOD"}
+
+// setEnv sets the given proxy env vars (clearing the others) for the test
+func setEnv(t *testing.T, env map[string]string) {
+	for _, name := range proxyEnvVars {
+		for _, key := range []string{name, strings.ToLower(name)} {
+			previous, ok := os.LookupEnv(key)
+			require.NoError(t, os.Unsetenv(key))
+			key := key
+			t.Cleanup(func() {
+				if ok {
+					os.Setenv(key, previous)
+				}
+			})
+		}
+	}
+	for key, value := range env {
+		require.NoError(t, os.Setenv(key, value))
+		key := key
+		t.Cleanup(func() { os.Unsetenv(key) })
+	}
+}
+
+func TestConfig_proxyFunc(t *testing.T) {
+	lookupIP = func(host string) ([]net.IP, error) {
+		if host == "registry.corp.example.com" {
+			return []net.IP{net.ParseIP("10.1.2.3")}, nil
+		}
+		return []net.IP{net.ParseIP("203.0.113.7")}, nil
+	}
+	defer func() { lookupIP = net.LookupIP }()
+
+	tests := []struct {
+		name     string
+		env      map[string]string
+		config   Config
+		url      string
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "https proxy from env",
+			env:      map[string]string{"HTTPS_PROXY": "http://proxy.example.com:3128"},
+			url:      "https://toolbox-data.anchore.io/grype/databases/listing.json",
+			expected: "http://proxy.example.com:3128",
+		},
+		{
+			name:     "lowercase env",
+			env:      map[string]string{"https_proxy": "http://proxy.example.com:3128"},
+			url:      "https://index.docker.io/v2/",
+			expected: "http://proxy.example.com:3128",
+		},
+		{
+			name:     "config takes precedence over env",
+			env:      map[string]string{"HTTPS_PROXY": "http://env.example.com:3128"},
+			config:   Config{HTTPSProxy: "http://config.example.com:3128"},
+			url:      "https://index.docker.io/v2/",
+			expected: "http://config.example.com:3128",
+		},
+		{
+			name:     "socks proxy from ALL_PROXY",
+			env:      map[string]string{"ALL_PROXY": "socks5://proxy.example.com:1080"},
+			url:      "https://index.docker.io/v2/",
+			expected: "socks5://proxy.example.com:1080",
+		},
+		{
+			name:   "http request without an http proxy",
+			config: Config{HTTPSProxy: "http://proxy.example.com:3128"},
+			url:    "http://localhost:5000/v2/",
+		},
+		{
+			name:   "no proxy of a domain",
+			config: Config{HTTPSProxy: "http://proxy.example.com:3128", NoProxy: "example.com"},
+			url:    "https://registry.example.com/v2/",
+		},
+		{
+			name:   "no proxy of an IP address within a CIDR range",
+			config: Config{HTTPSProxy: "http://proxy.example.com:3128", NoProxy: "10.0.0.0/8"},
+			url:    "https://10.4.5.6/v2/",
+		},
+		{
+			name:   "no proxy of a host name that resolves within a CIDR range",
+			config: Config{HTTPSProxy: "http://proxy.example.com:3128", NoProxy: "192.168.0.0/16, 10.0.0.0/8"},
+			url:    "https://registry.corp.example.com/v2/",
+		},
+		{
+			name:     "host name that resolves outside of the CIDR ranges",
+			config:   Config{HTTPSProxy: "http://proxy.example.com:3128", NoProxy: "10.0.0.0/8"},
+			url:      "https://index.docker.io/v2/",
+			expected: "http://proxy.example.com:3128",
+		},
+		{
+			name:    "unsupported scheme",
+			config:  Config{HTTPSProxy: "ftp://proxy.example.com"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setEnv(t, test.env)
+			fn, err := test.config.proxyFunc()
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodGet, test.url, nil)
+			require.NoError(t, err)
+			proxy, err := fn(request)
+			require.NoError(t, err)
+			if test.expected == "" {
+				assert.Nil(t, proxy)
+				return
+			}
+			require.NotNil(t, proxy)
+			assert.Equal(t, test.expected, proxy.String())
+		})
+	}
+}
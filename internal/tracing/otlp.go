@@ -0,0 +1,306 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// source: https://github.com/open-telemetry/opentelemetry-proto/blob/main/docs/specification.md#json-protobuf-encoding
+
+// the OTLP status codes (which differ from the codes of the API)
+const (
+	otlpStatusUnset = 0
+	otlpStatusOk    = 1
+	otlpStatusError = 2
+)
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+	SchemaURL  string           `json:"schemaUrl,omitempty"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Events            []otlpEvent     `json:"events,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string          `json:"timeUnixNano"`
+	Name         string          `json:"name"`
+	Attributes   []otlpAttribute `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	DoubleValue *float64        `json:"doubleValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpValue `json:"values"`
+}
+
+// exporter posts spans to an OTLP/HTTP collector with the JSON encoding.
+type exporter struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+func newExporter(url string, headers map[string]string, client *http.Client) *exporter {
+	return &exporter{
+		url:     url,
+		headers: headers,
+		client:  client,
+	}
+}
+
+// ExportSpans posts the spans to the collector (see sdktrace.SpanExporter).
+func (e *exporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if len(spans) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(newOTLPRequest(spans))
+	if err != nil {
+		return fmt.Errorf("unable to encode spans: %w", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("unable to export spans: %w", err)
+	}
+	request = request.WithContext(ctx)
+	for name, value := range e.headers {
+		request.Header.Set(name, value)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := e.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("unable to export spans: %w", err)
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(ioutil.Discard, response.Body)
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("unable to export spans: unexpected status %q", response.Status)
+	}
+	return nil
+}
+
+// Shutdown stops the exporter (see sdktrace.SpanExporter), which has nothing to release.
+func (e *exporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// newOTLPRequest groups the spans by their resource and instrumentation library.
+func newOTLPRequest(spans []sdktrace.ReadOnlySpan) otlpRequest {
+	type scopeKey struct {
+		resource *resource.Resource
+		library  instrumentation.Library
+	}
+	var resources []*resource.Resource
+	var scopes []scopeKey
+	spansOf := make(map[scopeKey][]otlpSpan)
+	for _, s := range spans {
+		key := scopeKey{resource: s.Resource(), library: s.InstrumentationLibrary()}
+		if _, ok := spansOf[key]; !ok {
+			scopes = append(scopes, key)
+			if !containsResource(resources, key.resource) {
+				resources = append(resources, key.resource)
+			}
+		}
+		spansOf[key] = append(spansOf[key], newOTLPSpan(s))
+	}
+
+	var request otlpRequest
+	for _, r := range resources {
+		resourceSpans := otlpResourceSpans{Resource: otlpResource{Attributes: []otlpAttribute{}}}
+		if r != nil {
+			resourceSpans.Resource.Attributes = newOTLPAttributes(r.Attributes())
+			resourceSpans.SchemaURL = r.SchemaURL()
+		}
+		for _, key := range scopes {
+			if key.resource != r {
+				continue
+			}
+			resourceSpans.ScopeSpans = append(resourceSpans.ScopeSpans, otlpScopeSpans{
+				Scope: otlpScope{Name: key.library.Name, Version: key.library.Version},
+				Spans: spansOf[key],
+			})
+		}
+		request.ResourceSpans = append(request.ResourceSpans, resourceSpans)
+	}
+	return request
+}
+
+func containsResource(resources []*resource.Resource, r *resource.Resource) bool {
+	for _, existing := range resources {
+		if existing == r {
+			return true
+		}
+	}
+	return false
+}
+
+func newOTLPSpan(s sdktrace.ReadOnlySpan) otlpSpan {
+	span := otlpSpan{
+		TraceID:           hex.EncodeToString(traceID(s)),
+		SpanID:            hex.EncodeToString(spanID(s)),
+		Name:              s.Name(),
+		Kind:              int(s.SpanKind()),
+		StartTimeUnixNano: unixNano(s.StartTime()),
+		EndTimeUnixNano:   unixNano(s.EndTime()),
+		Attributes:        newOTLPAttributes(s.Attributes()),
+		Status:            newOTLPStatus(s.Status()),
+	}
+	if parent := s.Parent(); parent.HasSpanID() {
+		id := parent.SpanID()
+		span.ParentSpanID = hex.EncodeToString(id[:])
+	}
+	for _, e := range s.Events() {
+		span.Events = append(span.Events, otlpEvent{
+			TimeUnixNano: unixNano(e.Time),
+			Name:         e.Name,
+			Attributes:   newOTLPAttributes(e.Attributes),
+		})
+	}
+	return span
+}
+
+func traceID(s sdktrace.ReadOnlySpan) []byte {
+	id := s.SpanContext().TraceID()
+	return id[:]
+}
+
+func spanID(s sdktrace.ReadOnlySpan) []byte {
+	id := s.SpanContext().SpanID()
+	return id[:]
+}
+
+func newOTLPStatus(status sdktrace.Status) otlpStatus {
+	switch status.Code {
+	case codes.Ok:
+		return otlpStatus{Code: otlpStatusOk}
+	case codes.Error:
+		return otlpStatus{Code: otlpStatusError, Message: status.Description}
+	default:
+		return otlpStatus{Code: otlpStatusUnset}
+	}
+}
+
+// unixNano returns the nanoseconds since the epoch of the given time, which are a string in the JSON encoding (since
+// the value is a 64-bit integer).
+func unixNano(t time.Time) string {
+	if t.IsZero() {
+		return "0"
+	}
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+func newOTLPAttributes(attributes []attribute.KeyValue) []otlpAttribute {
+	result := make([]otlpAttribute, 0, len(attributes))
+	for _, a := range attributes {
+		result = append(result, otlpAttribute{Key: string(a.Key), Value: newOTLPValue(a.Value)})
+	}
+	return result
+}
+
+func newOTLPValue(v attribute.Value) otlpValue {
+	switch v.Type() {
+	case attribute.BOOL:
+		b := v.AsBool()
+		return otlpValue{BoolValue: &b}
+	case attribute.INT64:
+		i := strconv.FormatInt(v.AsInt64(), 10)
+		return otlpValue{IntValue: &i}
+	case attribute.FLOAT64:
+		f := v.AsFloat64()
+		return otlpValue{DoubleValue: &f}
+	case attribute.ARRAY:
+		return otlpValue{ArrayValue: newOTLPArrayValue(v.AsArray())}
+	default:
+		s := v.Emit()
+		return otlpValue{StringValue: &s}
+	}
+}
+
+// newOTLPArrayValue returns the array value of the given array of booleans, numbers, or strings.
+func newOTLPArrayValue(array interface{}) *otlpArrayValue {
+	result := &otlpArrayValue{Values: []otlpValue{}}
+	elements := reflect.ValueOf(array)
+	if elements.Kind() != reflect.Array && elements.Kind() != reflect.Slice {
+		return result
+	}
+	for i := 0; i < elements.Len(); i++ {
+		var v attribute.Value
+		switch e := elements.Index(i); e.Kind() {
+		case reflect.Bool:
+			v = attribute.BoolValue(e.Bool())
+		case reflect.Int, reflect.Int32, reflect.Int64:
+			v = attribute.Int64Value(e.Int())
+		case reflect.Uint, reflect.Uint32, reflect.Uint64:
+			v = attribute.Int64Value(int64(e.Uint()))
+		case reflect.Float32, reflect.Float64:
+			v = attribute.Float64Value(e.Float())
+		default:
+			v = attribute.StringValue(fmt.Sprint(e.Interface()))
+		}
+		result.Values = append(result.Values, newOTLPValue(v))
+	}
+	return result
+}
@@ -0,0 +1,169 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestExporter_ExportSpans(t *testing.T) {
+	var requests []otlpRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "secret", r.Header.Get("Api-Key"))
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		var request otlpRequest
+		require.NoError(t, json.Unmarshal(body, &request))
+		requests = append(requests, request)
+	}))
+	defer server.Close()
+
+	exp := newExporter(server.URL, map[string]string{"api-key": "secret"}, server.Client())
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	tracer := provider.Tracer(tracerName)
+
+	ctx, parent := tracer.Start(context.Background(), "scan", trace.WithAttributes(
+		attribute.String("grype.target", "alpine:latest"),
+		attribute.Int("grype.packages", 14),
+		attribute.Bool("grype.cached", true),
+		attribute.Float64("grype.busy_seconds", 1.5),
+	))
+	_, child := tracer.Start(ctx, "load db")
+	child.SetStatus(codes.Error, "no database")
+	child.End()
+	parent.SetStatus(codes.Ok, "")
+	parent.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	// note: the syncer exports every span as soon as it ends
+	require.Len(t, requests, 2)
+	childSpan := requests[0].ResourceSpans[0].ScopeSpans[0].Spans[0]
+	parentSpan := requests[1].ResourceSpans[0].ScopeSpans[0].Spans[0]
+	assert.Equal(t, tracerName, requests[0].ResourceSpans[0].ScopeSpans[0].Scope.Name)
+
+	assert.Equal(t, "load db", childSpan.Name)
+	assert.Equal(t, "scan", parentSpan.Name)
+	assert.Len(t, parentSpan.TraceID, 32)
+	assert.Len(t, parentSpan.SpanID, 16)
+	assert.Equal(t, parentSpan.TraceID, childSpan.TraceID)
+	assert.Equal(t, parentSpan.SpanID, childSpan.ParentSpanID)
+	assert.Empty(t, parentSpan.ParentSpanID)
+
+	assert.Equal(t, otlpStatus{Code: otlpStatusError, Message: "no database"}, childSpan.Status)
+	assert.Equal(t, otlpStatus{Code: otlpStatusOk}, parentSpan.Status)
+
+	values := make(map[string]otlpValue)
+	for _, a := range parentSpan.Attributes {
+		values[a.Key] = a.Value
+	}
+	require.NotNil(t, values["grype.target"].StringValue)
+	assert.Equal(t, "alpine:latest", *values["grype.target"].StringValue)
+	require.NotNil(t, values["grype.packages"].IntValue)
+	assert.Equal(t, "14", *values["grype.packages"].IntValue)
+	require.NotNil(t, values["grype.cached"].BoolValue)
+	assert.True(t, *values["grype.cached"].BoolValue)
+	require.NotNil(t, values["grype.busy_seconds"].DoubleValue)
+	assert.Equal(t, 1.5, *values["grype.busy_seconds"].DoubleValue)
+}
+
+func TestExporter_ExportSpans_failedExport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	var exported error
+	exp := newExporter(server.URL, nil, server.Client())
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporterFunc(func(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+		exported = exp.ExportSpans(ctx, spans)
+		return exported
+	})))
+	_, span := provider.Tracer(tracerName).Start(context.Background(), "scan")
+	span.End()
+
+	require.Error(t, exported)
+	assert.Contains(t, exported.Error(), "503")
+}
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{
+			name: "no endpoint",
+		},
+		{
+			name: "endpoint",
+			cfg:  Config{Endpoint: "http://localhost:4318/v1/traces", Timeout: time.Second},
+		},
+		{
+			name:    "not an http endpoint",
+			cfg:     Config{Endpoint: "localhost:4317"},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			shutdown, err := Setup(test.cfg)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NoError(t, shutdown(context.Background()))
+		})
+	}
+}
+
+func TestEnd(t *testing.T) {
+	recorder := &recordingExporter{}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(recorder))
+	_, span := provider.Tracer(tracerName).Start(context.Background(), "catalog")
+	End(span, errors.New("no such image"))
+
+	require.Len(t, recorder.spans, 1)
+	assert.Equal(t, codes.Error, recorder.spans[0].Status().Code)
+	assert.Equal(t, "no such image", recorder.spans[0].Status().Description)
+	require.Len(t, recorder.spans[0].Events(), 1)
+	assert.Equal(t, "exception", recorder.spans[0].Events()[0].Name)
+}
+
+type exporterFunc func(ctx context.Context, spans []sdktrace.ReadOnlySpan) error
+
+func (f exporterFunc) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	return f(ctx, spans)
+}
+
+func (f exporterFunc) Shutdown(context.Context) error {
+	return nil
+}
+
+type recordingExporter struct {
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (e *recordingExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(context.Context) error {
+	return nil
+}
@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/anchore/grype/internal"
+	"github.com/anchore/grype/internal/version"
+)
+
+// tracerName is the name of the instrumentation library of the spans of grype.
+const tracerName = "github.com/anchore/grype"
+
+// Config controls the export of the spans of grype to an OpenTelemetry collector.
+type Config struct {
+	// Endpoint is the URL that spans are posted to with OTLP/HTTP (e.g. "http://localhost:4318/v1/traces"), where
+	// spans are not exported when empty
+	Endpoint string
+	// Headers are added to every export (e.g. an authorization header)
+	Headers map[string]string
+	// Timeout is how long an export may take
+	Timeout time.Duration
+}
+
+// Setup registers the global tracer provider that exports the spans of grype to the endpoint of the given config,
+// returning the function that exports the remaining spans (which must be called before exiting). Nothing is registered
+// when there is no endpoint.
+func Setup(cfg Config) (func(context.Context) error, error) {
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	u, err := url.Parse(cfg.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("bad OTLP endpoint (must be an http or https URL): %q", cfg.Endpoint)
+	}
+
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	exporter := newExporter(cfg.Endpoint, cfg.Headers, &http.Client{Timeout: timeout})
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(internal.ApplicationName),
+			semconv.ServiceVersionKey.String(version.FromBuild().Version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span of grype with the given attributes, which is a child of the span of the given context (if any).
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End ends the span, where an error marks the span as failed.
+func End(span trace.Span, err error) {
+	Fail(span, err)
+	span.End()
+}
+
+// Fail marks the span as failed with the given error (if any), without ending the span.
+func Fail(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Record records a span of grype that already ended, e.g. the sum of the work of concurrent operations (such as the
+// matching of packages by the same matcher).
+func Record(ctx context.Context, name string, start, end time.Time, attributes ...attribute.KeyValue) {
+	_, span := otel.Tracer(tracerName).Start(ctx, name, trace.WithTimestamp(start), trace.WithAttributes(attributes...))
+	span.End(trace.WithTimestamp(end))
+}
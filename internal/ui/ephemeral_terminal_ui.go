@@ -82,6 +82,11 @@ func (h *ephemeralTerminalUI) Handle(event partybus.Event) error {
 			log.Errorf("unable to show %s event: %+v", event.Type, err)
 		}
 
+	case event.Type == grypeEvent.DistroEOLReached:
+		if err := handleDistroEOLReached(ctx, h.frame, event, h.waitGroup); err != nil {
+			log.Errorf("unable to show %s event: %+v", event.Type, err)
+		}
+
 	case event.Type == grypeEvent.VulnerabilityScanningFinished:
 		// we need to close the screen now since signaling the the presenter is ready means that we
 		// are about to write bytes to stdout, so we should reset the terminal state first
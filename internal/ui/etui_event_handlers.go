@@ -32,3 +32,20 @@ func handleAppUpdateAvailable(_ context.Context, fr *frame.Frame, event partybus
 
 	return nil
 }
+
+func handleDistroEOLReached(_ context.Context, fr *frame.Frame, event partybus.Event, _ *sync.WaitGroup) error {
+	d, eol, err := grypeEventParsers.ParseDistroEOLReached(event)
+	if err != nil {
+		return fmt.Errorf("bad %s event: %w", event.Type, err)
+	}
+
+	line, err := fr.Prepend()
+	if err != nil {
+		return err
+	}
+
+	message := color.Yellow.Sprintf("%s reached the end of life on %s, vulnerability data is no longer published for it", d, eol.Format("2006-01-02"))
+	_, _ = io.WriteString(line, message)
+
+	return nil
+}
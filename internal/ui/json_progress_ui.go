@@ -0,0 +1,354 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sync"
+	"time"
+
+	grypeEvent "github.com/anchore/grype/grype/event"
+	grypeEventParsers "github.com/anchore/grype/grype/event/parsers"
+	"github.com/anchore/grype/internal/log"
+	stereoscopeEvent "github.com/anchore/stereoscope/pkg/event"
+	stereoscopeEventParsers "github.com/anchore/stereoscope/pkg/event/parsers"
+	"github.com/anchore/stereoscope/pkg/image/docker"
+	syftEvent "github.com/anchore/syft/syft/event"
+	syftEventParsers "github.com/anchore/syft/syft/event/parsers"
+	"github.com/wagoodman/go-partybus"
+	"github.com/wagoodman/go-progress"
+)
+
+// the stages of a scan that progress events are emitted for
+const (
+	UpdateDBStage        = "update-db"
+	FetchImageStage      = "fetch-image"
+	PullImageStage       = "pull-image"
+	ReadImageStage       = "read-image"
+	IndexFilesStage      = "index-files"
+	CatalogPackagesStage = "catalog-packages"
+	MatchStage           = "match"
+	ReportStage          = "report"
+)
+
+// the statuses of a stage
+const (
+	startedStatus  = "started"
+	runningStatus  = "running"
+	finishedStatus = "finished"
+	failedStatus   = "failed"
+)
+
+// ProgressEvent is a line of the JSON progress output, which describes the state of a stage of the scan.
+type ProgressEvent struct {
+	Timestamp string `json:"timestamp"`
+	// ID distinguishes concurrent instances of the same stage (e.g. the catalogs of several targets)
+	ID     int    `json:"id"`
+	Stage  string `json:"stage"`
+	Status string `json:"status"`
+	// Name is what the stage is about (e.g. the image or the directory)
+	Name string `json:"name,omitempty"`
+	// Detail is the step within the stage (e.g. "downloading" the database)
+	Detail string `json:"detail,omitempty"`
+	// Percent is only known for stages of a known size
+	Percent *float64         `json:"percent,omitempty"`
+	Counts  map[string]int64 `json:"counts,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+// progressSample is the state of a stage at a point in time.
+type progressSample struct {
+	detail  string
+	current int64
+	size    int64
+	counts  map[string]int64
+	done    bool
+	err     error
+}
+
+// jsonProgressUI writes the progress of the scan as newline-delimited JSON events (instead of a TTY UI), so that
+// other tools can show the progress, and writes the final report to the given writer.
+type jsonProgressUI struct {
+	unsubscribe  func() error
+	output       io.Writer
+	reportOutput io.Writer
+	interval     time.Duration
+	lock         sync.Mutex
+	waitGroup    *sync.WaitGroup
+	ctx          context.Context
+	cancel       context.CancelFunc
+	lastID       int
+}
+
+// NewJSONProgressUI writes all progress events as JSON lines to the given progress writer (usually stderr) and writes
+// the final report to the given report writer.
+func NewJSONProgressUI(progressWriter, reportWriter io.Writer) UI {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &jsonProgressUI{
+		output:       progressWriter,
+		reportOutput: reportWriter,
+		interval:     250 * time.Millisecond,
+		waitGroup:    &sync.WaitGroup{},
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+}
+
+func (u *jsonProgressUI) Setup(unsubscribe func() error) error {
+	u.unsubscribe = unsubscribe
+	return nil
+}
+
+// nolint:funlen
+func (u *jsonProgressUI) Handle(event partybus.Event) error {
+	switch event.Type {
+	case grypeEvent.UpdateVulnerabilityDatabase:
+		prog, err := grypeEventParsers.ParseUpdateVulnerabilityDatabase(event)
+		if err != nil {
+			return u.badEvent(event, err)
+		}
+		u.track(UpdateDBStage, "", func() progressSample {
+			s := sampleOf(prog)
+			s.detail = prog.Stage()
+			return s
+		})
+
+	case stereoscopeEvent.FetchImage:
+		name, prog, err := stereoscopeEventParsers.ParseFetchImage(event)
+		if err != nil {
+			return u.badEvent(event, err)
+		}
+		u.track(FetchImageStage, name, func() progressSample {
+			s := sampleOf(prog)
+			s.detail = prog.Stage()
+			return s
+		})
+
+	case stereoscopeEvent.PullDockerImage:
+		name, status, err := stereoscopeEventParsers.ParsePullDockerImage(event)
+		if err != nil {
+			return u.badEvent(event, err)
+		}
+		u.track(PullImageStage, name, func() progressSample {
+			return samplePullStatus(status)
+		})
+
+	case stereoscopeEvent.ReadImage:
+		metadata, prog, err := stereoscopeEventParsers.ParseReadImage(event)
+		if err != nil {
+			return u.badEvent(event, err)
+		}
+		var name string
+		if metadata != nil {
+			name = metadata.ID
+		}
+		u.track(ReadImageStage, name, func() progressSample {
+			s := sampleOf(prog)
+			s.counts = map[string]int64{"layers-read": s.current, "layers": s.size}
+			return s
+		})
+
+	case syftEvent.FileIndexingStarted:
+		path, prog, err := syftEventParsers.ParseFileIndexingStarted(event)
+		if err != nil {
+			return u.badEvent(event, err)
+		}
+		u.track(IndexFilesStage, path, func() progressSample {
+			s := sampleOf(prog)
+			s.detail = prog.Stage()
+			// note: the number of files is not known until the index is done
+			s.counts = map[string]int64{"files": s.current}
+			s.size = -1
+			return s
+		})
+
+	case syftEvent.PackageCatalogerStarted:
+		monitor, err := syftEventParsers.ParsePackageCatalogerStarted(event)
+		if err != nil {
+			return u.badEvent(event, err)
+		}
+		u.track(CatalogPackagesStage, "", func() progressSample {
+			return progressSample{
+				size: -1,
+				counts: map[string]int64{
+					"files":    monitor.FilesProcessed.Current(),
+					"packages": monitor.PackagesDiscovered.Current(),
+				},
+				done: progress.IsErrCompleted(monitor.FilesProcessed.Error()) && progress.IsErrCompleted(monitor.PackagesDiscovered.Error()),
+			}
+		})
+
+	case grypeEvent.VulnerabilityScanningStarted:
+		monitor, err := grypeEventParsers.ParseVulnerabilityScanningStarted(event)
+		if err != nil {
+			return u.badEvent(event, err)
+		}
+		u.track(MatchStage, "", func() progressSample {
+			return sampleMatching(monitor.PackagesProcessed, monitor.VulnerabilitiesDiscovered)
+		})
+
+	case grypeEvent.VulnerabilityScanningFinished:
+		// the progress of every stage is done before the report is written
+		u.waitGroup.Wait()
+
+		id := u.nextID()
+		u.emit(ProgressEvent{ID: id, Stage: ReportStage, Status: startedStatus})
+		done := ProgressEvent{ID: id, Stage: ReportStage, Status: finishedStatus}
+		if err := handleVulnerabilityScanningFinished(event, u.reportOutput); err != nil {
+			log.Errorf("unable to show %s event: %+v", event.Type, err)
+			done.Status = failedStatus
+			done.Error = err.Error()
+		}
+		u.emit(done)
+
+		// this is the last expected event, stop listening to events
+		return u.unsubscribe()
+	}
+	return nil
+}
+
+func (u *jsonProgressUI) badEvent(event partybus.Event, err error) error {
+	log.Warnf("unable to show %s event: %+v", event.Type, err)
+	return nil
+}
+
+func (u *jsonProgressUI) Teardown(force bool) error {
+	if !force {
+		u.waitGroup.Wait()
+	}
+	u.cancel()
+	return nil
+}
+
+func (u *jsonProgressUI) nextID() int {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.lastID++
+	return u.lastID
+}
+
+// track emits the events of a stage until it is done: a started event, a running event whenever the state changes
+// (checked every interval), and a finished (or failed) event.
+func (u *jsonProgressUI) track(stage, name string, sample func() progressSample) {
+	id := u.nextID()
+	u.waitGroup.Add(1)
+	go func() {
+		defer u.waitGroup.Done()
+
+		last := sample()
+		u.emit(newProgressEvent(id, stage, name, startedStatus, last))
+		for !last.done {
+			select {
+			case <-u.ctx.Done():
+				return
+			case <-time.After(u.interval):
+			}
+			s := sample()
+			if s.done {
+				last = s
+				break
+			}
+			if !reflect.DeepEqual(s, last) {
+				u.emit(newProgressEvent(id, stage, name, runningStatus, s))
+				last = s
+			}
+		}
+
+		status := finishedStatus
+		if last.err != nil && !progress.IsErrCompleted(last.err) {
+			status = failedStatus
+		}
+		u.emit(newProgressEvent(id, stage, name, status, last))
+	}()
+}
+
+func (u *jsonProgressUI) emit(event ProgressEvent) {
+	event.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	line, err := json.Marshal(event)
+	if err != nil {
+		log.Warnf("unable to encode progress event: %+v", err)
+		return
+	}
+
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	if _, err := fmt.Fprintf(u.output, "%s\n", line); err != nil {
+		log.Warnf("unable to write progress event: %+v", err)
+	}
+}
+
+func newProgressEvent(id int, stage, name, status string, s progressSample) ProgressEvent {
+	event := ProgressEvent{
+		ID:     id,
+		Stage:  stage,
+		Status: status,
+		Name:   name,
+		Detail: s.detail,
+		Counts: s.counts,
+	}
+	if s.size > 0 {
+		percent := math.Round(1000*math.Min(float64(s.current)/float64(s.size), 1)) / 10
+		event.Percent = &percent
+	}
+	if status == finishedStatus && s.size >= 0 {
+		percent := 100.0
+		event.Percent = &percent
+	}
+	if s.err != nil && !progress.IsErrCompleted(s.err) {
+		event.Error = s.err.Error()
+	}
+	return event
+}
+
+// sampleOf returns the state of a progressable, which is done once it has an error (either completed or failed) or
+// reached its (non-empty) size.
+func sampleOf(p progress.Progressable) progressSample {
+	s := progressSample{
+		current: p.Current(),
+		size:    p.Size(),
+		err:     p.Error(),
+	}
+	s.done = s.err != nil || (s.size > 0 && s.current >= s.size)
+	return s
+}
+
+// samplePullStatus sums the downloads of all layers of the pulled image.
+func samplePullStatus(status *docker.PullStatus) progressSample {
+	s := progressSample{done: status.Complete()}
+	var pulled int64
+	layers := status.Layers()
+	for _, layer := range layers {
+		state := status.Current(layer)
+		if state.Phase >= docker.PullCompletePhase || state.Phase == docker.AlreadyExistsPhase {
+			pulled++
+		}
+		if state.DownloadProgress != nil {
+			s.current += state.DownloadProgress.Current()
+			s.size += state.DownloadProgress.Size()
+		}
+	}
+	s.counts = map[string]int64{"layers": int64(len(layers)), "layers-pulled": pulled}
+	return s
+}
+
+// sampleMatching returns the state of matching, where the number of packages to match is known when the monitor of
+// processed packages is sizable.
+func sampleMatching(packagesProcessed, vulnerabilitiesDiscovered progress.Monitorable) progressSample {
+	s := progressSample{
+		current: packagesProcessed.Current(),
+		size:    -1,
+		counts: map[string]int64{
+			"packages":        packagesProcessed.Current(),
+			"vulnerabilities": vulnerabilitiesDiscovered.Current(),
+		},
+		done: progress.IsErrCompleted(packagesProcessed.Error()) && progress.IsErrCompleted(vulnerabilitiesDiscovered.Error()),
+	}
+	if sizable, ok := packagesProcessed.(progress.Sizable); ok && sizable.Size() > 0 {
+		s.size = sizable.Size()
+		s.counts["total-packages"] = s.size
+	}
+	return s
+}
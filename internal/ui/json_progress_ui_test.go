@@ -0,0 +1,132 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wagoodman/go-partybus"
+	"github.com/wagoodman/go-progress"
+
+	grypeEvent "github.com/anchore/grype/grype/event"
+	"github.com/anchore/grype/grype/matcher"
+)
+
+type stringPresenter string
+
+func (p stringPresenter) Present(w io.Writer) error {
+	_, err := io.WriteString(w, string(p))
+	return err
+}
+
+func TestJSONProgressUI(t *testing.T) {
+	progressOutput := &bytes.Buffer{}
+	reportOutput := &bytes.Buffer{}
+	u := NewJSONProgressUI(progressOutput, reportOutput).(*jsonProgressUI)
+	u.interval = time.Millisecond
+	unsubscribed := false
+	require.NoError(t, u.Setup(func() error {
+		unsubscribed = true
+		return nil
+	}))
+
+	// note: matching is done before it is tracked, so that the monitors are not updated concurrently
+	packagesProcessed := &progress.Manual{N: 4, Total: 4}
+	vulnerabilitiesDiscovered := &progress.Manual{N: 3}
+	packagesProcessed.SetCompleted()
+	vulnerabilitiesDiscovered.SetCompleted()
+	require.NoError(t, u.Handle(partybus.Event{
+		Type: grypeEvent.VulnerabilityScanningStarted,
+		Value: matcher.Monitor{
+			PackagesProcessed:         packagesProcessed,
+			VulnerabilitiesDiscovered: vulnerabilitiesDiscovered,
+		},
+	}))
+
+	require.NoError(t, u.Handle(partybus.Event{
+		Type:  grypeEvent.VulnerabilityScanningFinished,
+		Value: stringPresenter("the report"),
+	}))
+	require.NoError(t, u.Teardown(false))
+
+	assert.True(t, unsubscribed)
+	assert.Equal(t, "the report", reportOutput.String())
+
+	var events []ProgressEvent
+	for _, line := range strings.Split(strings.TrimSpace(progressOutput.String()), "\n") {
+		var event ProgressEvent
+		require.NoError(t, json.Unmarshal([]byte(line), &event), line)
+		assert.NotEmpty(t, event.Timestamp)
+		events = append(events, event)
+	}
+
+	require.Len(t, events, 4)
+	first, last := events[0], events[len(events)-1]
+	assert.Equal(t, MatchStage, first.Stage)
+	assert.Equal(t, startedStatus, first.Status)
+
+	finished := events[len(events)-3]
+	assert.Equal(t, MatchStage, finished.Stage)
+	assert.Equal(t, finishedStatus, finished.Status)
+	require.NotNil(t, finished.Percent)
+	assert.Equal(t, 100.0, *finished.Percent)
+	assert.Equal(t, map[string]int64{"packages": 4, "total-packages": 4, "vulnerabilities": 3}, finished.Counts)
+
+	assert.Equal(t, ReportStage, events[len(events)-2].Stage)
+	assert.Equal(t, startedStatus, events[len(events)-2].Status)
+	assert.Equal(t, ReportStage, last.Stage)
+	assert.Equal(t, finishedStatus, last.Status)
+	assert.NotEqual(t, first.ID, last.ID)
+}
+
+func TestNewProgressEvent(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          string
+		sample          progressSample
+		expectedPercent *float64
+		expectedError   string
+	}{
+		{
+			name:   "unknown size",
+			status: runningStatus,
+			sample: progressSample{current: 10, size: -1},
+		},
+		{
+			name:            "partial",
+			status:          runningStatus,
+			sample:          progressSample{current: 1, size: 3},
+			expectedPercent: percent(33.3),
+		},
+		{
+			name:            "finished",
+			status:          finishedStatus,
+			sample:          progressSample{current: 2, size: 3, err: progress.ErrCompleted},
+			expectedPercent: percent(100),
+		},
+		{
+			name:            "failed",
+			status:          failedStatus,
+			sample:          progressSample{current: 1, size: 4, err: io.ErrUnexpectedEOF},
+			expectedPercent: percent(25),
+			expectedError:   io.ErrUnexpectedEOF.Error(),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			event := newProgressEvent(1, MatchStage, "", test.status, test.sample)
+			assert.Equal(t, test.expectedPercent, event.Percent)
+			assert.Equal(t, test.expectedError, event.Error)
+		})
+	}
+}
+
+func percent(value float64) *float64 {
+	return &value
+}
@@ -23,6 +23,30 @@ func newMockDbStore() *mockStore {
 						CPEs:              []string{"cpe:2.3:a:lib_vnc_project-(server):libvncserver:*:*:*:*:*:*:*:*"},
 					},
 				},
+				"busybox": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-busybox",
+						VersionConstraint: "< 1.34.0",
+						VersionFormat:     "unknown",
+						CPEs:              []string{"cpe:2.3:a:busybox:busybox:*:*:*:*:*:*:*:*"},
+					},
+				},
+				"git": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-git",
+						VersionConstraint: "< 2.35.2",
+						VersionFormat:     "unknown",
+						CPEs:              []string{"cpe:2.3:a:git:git:*:*:*:*:*:*:*:*"},
+					},
+				},
+				"linux_kernel": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-linux-kernel",
+						VersionConstraint: "< 5.10.84",
+						VersionFormat:     "unknown",
+						CPEs:              []string{"cpe:2.3:o:linux:linux_kernel:*:*:*:*:*:*:*:*"},
+					},
+				},
 				"my-package": []grypeDB.Vulnerability{
 					{
 						ID:                "CVE-bogus-my-package-1",
@@ -64,6 +88,13 @@ func newMockDbStore() *mockStore {
 						VersionFormat:     "python",
 					},
 				},
+				"urllib3": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-conda-urllib3",
+						VersionConstraint: "< 1.26.5",
+						VersionFormat:     "python",
+					},
+				},
 				"my-package": []grypeDB.Vulnerability{
 					{
 						ID:                "CVE-bogus-my-package-2-python",
@@ -90,6 +121,112 @@ func newMockDbStore() *mockStore {
 					},
 				},
 			},
+			"github:go": {
+				"github.com/gorilla/websocket": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-golang-websocket",
+						VersionConstraint: "< 1.4.1",
+						VersionFormat:     "go",
+					},
+				},
+				"stdlib": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-golang-stdlib",
+						VersionConstraint: "< 1.17.2",
+						VersionFormat:     "go",
+					},
+				},
+			},
+			"github:rust": {
+				"hyper": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-rust-hyper",
+						VersionConstraint: "^0.14.0, < 0.14.10",
+						VersionFormat:     "cargo",
+					},
+				},
+			},
+			"github:nuget": {
+				"Newtonsoft.Json": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-dotnet-newtonsoft-json",
+						VersionConstraint: "< 13.0.1",
+						VersionFormat:     "nuget",
+					},
+				},
+			},
+			"github:composer": {
+				"monolog/monolog": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-php-monolog",
+						VersionConstraint: "< 1.25.2",
+						VersionFormat:     "composer",
+					},
+				},
+			},
+			"github:pub": {
+				"http": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-dart-http",
+						VersionConstraint: "^0.13.0, < 0.13.3",
+						VersionFormat:     "pub",
+					},
+				},
+			},
+			"github:swift": {
+				"github.com/apple/swift-nio": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-swift-nio",
+						VersionConstraint: "< 2.29.1",
+						VersionFormat:     "semver",
+					},
+				},
+			},
+			"github:erlang": {
+				"phoenix": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-hex-phoenix",
+						VersionConstraint: "< 1.6.14",
+						VersionFormat:     "hex",
+					},
+				},
+			},
+			"osv:cran": {
+				"jsonlite": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-r-jsonlite",
+						VersionConstraint: "< 1.7.3",
+						VersionFormat:     "R",
+					},
+				},
+			},
+			"osv:hackage": {
+				"hakyll": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-haskell-hakyll",
+						VersionConstraint: "< 4.15.0.0",
+						VersionFormat:     "haskell",
+					},
+				},
+			},
+			"osv:bitnami": {
+				"redis": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-bitnami-redis",
+						VersionConstraint: "< 6.2.7",
+						VersionFormat:     "bitnami",
+					},
+				},
+			},
+			"osv:homebrew": {
+				"jq": []grypeDB.Vulnerability{
+					{
+						ID:                "CVE-homebrew-jq",
+						VersionConstraint: "< 1.7",
+						VersionFormat:     "unknown",
+					},
+				},
+			},
 			"debian:8": {
 				"apt-dev": []grypeDB.Vulnerability{
 					{
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/anchore/grype/grype/db"
+	grypeDB "github.com/anchore/grype/grype/db/v3"
 
 	"github.com/anchore/grype/grype"
 	"github.com/anchore/grype/grype/match"
@@ -298,6 +299,348 @@ func addSlesMatches(t *testing.T, theSource source.Source, catalog *syftPkg.Cata
 	})
 }
 
+func addGolangMatches(t *testing.T, packages []pkg.Package, theStore *mockStore, theResult *match.Matches) {
+	binPackages := packagesByPath(packages, "/golang/hello-world")
+	if len(binPackages) != 2 { // 2, because the go toolchain (stdlib) is reported alongside the modules of a binary
+		t.Logf("Go Packages: %+v", binPackages)
+		t.Fatalf("problem with upstream syft cataloger (go binary)")
+	}
+	for _, thePkg := range binPackages {
+		theVuln := theStore.backend["github:go"][thePkg.Name][0]
+		vulnObj, err := vulnerability.NewVulnerability(theVuln)
+		if err != nil {
+			t.Fatalf("failed to create vuln obj: %+v", err)
+		}
+		theResult.Add(match.Match{
+
+			Vulnerability: *vulnObj,
+			Package:       thePkg,
+			Details: []match.Detail{
+				{
+					Type:       match.ExactDirectMatch,
+					Confidence: 1.0,
+					SearchedBy: map[string]interface{}{
+						"language": "go",
+					},
+					Found: map[string]interface{}{
+						"constraint": theVuln.VersionConstraint + " (go)",
+					},
+					Matcher: match.GoModuleMatcher,
+				},
+			},
+		})
+	}
+}
+
+func addPhpMatches(t *testing.T, theSource source.Source, catalog *syftPkg.Catalog, theStore *mockStore, theResult *match.Matches) {
+	packages := catalog.PackagesByPath("/php/vendor/composer/installed.json")
+	if len(packages) != 1 {
+		t.Logf("PHP Packages: %+v", packages)
+		t.Fatalf("problem with upstream syft cataloger (php)")
+	}
+	thePkg := pkg.New(packages[0])
+	theVuln := theStore.backend["github:composer"][thePkg.Name][0]
+	vulnObj, err := vulnerability.NewVulnerability(theVuln)
+	if err != nil {
+		t.Fatalf("failed to create vuln obj: %+v", err)
+	}
+	theResult.Add(match.Match{
+
+		Vulnerability: *vulnObj,
+		Package:       thePkg,
+		Details: []match.Detail{
+			{
+				Type:       match.ExactDirectMatch,
+				Confidence: 1.0,
+				SearchedBy: map[string]interface{}{
+					"language": "php",
+				},
+				Found: map[string]interface{}{
+					"constraint": "< 1.25.2 (composer)",
+				},
+				Matcher: match.PhpComposerMatcher,
+			},
+		},
+	})
+}
+
+func addCondaMatches(t *testing.T, packages []pkg.Package, theStore *mockStore, theResult *match.Matches) {
+	condaPackages := packagesByPath(packages, "/conda/conda-meta/urllib3-1.26.4-pyhd3eb1b0_0.json")
+	if len(condaPackages) != 1 {
+		t.Logf("Conda Packages: %+v", condaPackages)
+		t.Fatalf("problem with grype cataloger (conda)")
+	}
+	thePkg := condaPackages[0]
+	// note: python packages within conda environments are matched against the python advisories
+	theVuln := theStore.backend["github:python"][thePkg.Name][0]
+	vulnObj, err := vulnerability.NewVulnerability(theVuln)
+	if err != nil {
+		t.Fatalf("failed to create vuln obj: %+v", err)
+	}
+	theResult.Add(match.Match{
+
+		Vulnerability: *vulnObj,
+		Package:       thePkg,
+		Details: []match.Detail{
+			{
+				Type:       match.ExactDirectMatch,
+				Confidence: 1.0,
+				SearchedBy: map[string]interface{}{
+					"language": "python",
+				},
+				Found: map[string]interface{}{
+					"constraint": "< 1.26.5 (python)",
+				},
+				Matcher: match.CondaMatcher,
+			},
+		},
+	})
+}
+
+func addRMatches(t *testing.T, packages []pkg.Package, theStore *mockStore, theResult *match.Matches) {
+	rPackages := packagesByPath(packages, "/r/site-library/jsonlite/DESCRIPTION")
+	if len(rPackages) != 1 {
+		t.Logf("R Packages: %+v", rPackages)
+		t.Fatalf("problem with grype cataloger (R)")
+	}
+	thePkg := rPackages[0]
+	theVuln := theStore.backend["osv:cran"][thePkg.Name][0]
+	vulnObj, err := vulnerability.NewVulnerability(theVuln)
+	if err != nil {
+		t.Fatalf("failed to create vuln obj: %+v", err)
+	}
+	theResult.Add(match.Match{
+
+		Vulnerability: *vulnObj,
+		Package:       thePkg,
+		Details: []match.Detail{
+			{
+				Type:       match.ExactDirectMatch,
+				Confidence: 1.0,
+				SearchedBy: map[string]interface{}{
+					"language": "R",
+				},
+				Found: map[string]interface{}{
+					"constraint": "< 1.7.3 (r)",
+				},
+				Matcher: match.RPackageMatcher,
+			},
+		},
+	})
+}
+
+func addHaskellMatches(t *testing.T, packages []pkg.Package, theStore *mockStore, theResult *match.Matches) {
+	haskellPackages := packagesByPath(packages, "/haskell/stack.yaml.lock")
+	if len(haskellPackages) != 1 {
+		t.Logf("Haskell Packages: %+v", haskellPackages)
+		t.Fatalf("problem with grype cataloger (haskell)")
+	}
+	thePkg := haskellPackages[0]
+	theVuln := theStore.backend["osv:hackage"][thePkg.Name][0]
+	vulnObj, err := vulnerability.NewVulnerability(theVuln)
+	if err != nil {
+		t.Fatalf("failed to create vuln obj: %+v", err)
+	}
+	theResult.Add(match.Match{
+
+		Vulnerability: *vulnObj,
+		Package:       thePkg,
+		Details: []match.Detail{
+			{
+				Type:       match.ExactDirectMatch,
+				Confidence: 1.0,
+				SearchedBy: map[string]interface{}{
+					"language": "haskell",
+				},
+				Found: map[string]interface{}{
+					"constraint": "< 4.15.0.0 (haskell)",
+				},
+				Matcher: match.HaskellMatcher,
+			},
+		},
+	})
+}
+
+func addBitnamiMatches(t *testing.T, packages []pkg.Package, theStore *mockStore, theResult *match.Matches) {
+	bitnamiPackages := packagesByPath(packages, "/opt/bitnami/redis/.spdx-redis.spdx")
+	if len(bitnamiPackages) != 1 {
+		t.Logf("Bitnami Packages: %+v", bitnamiPackages)
+		t.Fatalf("problem with grype cataloger (bitnami)")
+	}
+	thePkg := bitnamiPackages[0]
+	theVuln := theStore.backend["osv:bitnami"][thePkg.Name][0]
+	vulnObj, err := vulnerability.NewVulnerability(theVuln)
+	if err != nil {
+		t.Fatalf("failed to create vuln obj: %+v", err)
+	}
+	theResult.Add(match.Match{
+
+		Vulnerability: *vulnObj,
+		Package:       thePkg,
+		Details: []match.Detail{
+			{
+				Type:       match.ExactDirectMatch,
+				Confidence: 1.0,
+				SearchedBy: map[string]interface{}{
+					"type": string(thePkg.Type),
+				},
+				Found: map[string]interface{}{
+					"constraint": "< 6.2.7 (bitnami)",
+				},
+				Matcher: match.BitnamiMatcher,
+			},
+		},
+	})
+}
+
+func addLinuxKernelMatches(t *testing.T, packages []pkg.Package, theStore *mockStore, theResult *match.Matches) {
+	kernelPackages := packagesByPath(packages, "/boot/vmlinuz-5.10.0-9-amd64")
+	if len(kernelPackages) != 1 {
+		t.Logf("Linux Kernel Packages: %+v", kernelPackages)
+		t.Fatalf("problem with grype cataloger (linux kernel)")
+	}
+	thePkg := kernelPackages[0]
+	// note: the kernel is only matched by CPE
+	theVuln := theStore.backend[grypeDB.NVDNamespace]["linux_kernel"][0]
+	vulnObj, err := vulnerability.NewVulnerability(theVuln)
+	if err != nil {
+		t.Fatalf("failed to create vuln obj: %+v", err)
+	}
+	theResult.Add(match.Match{
+
+		Vulnerability: *vulnObj,
+		Package:       thePkg,
+		Details: []match.Detail{
+			{
+				Type:       match.CPEMatch,
+				Confidence: 0.9,
+				SearchedBy: map[string]interface{}{
+					"cpe": "cpe:2.3:o:linux:linux_kernel:5.10.0:*:*:*:*:*:*:*",
+				},
+				Found: map[string]interface{}{
+					"cpes":       []string{"cpe:2.3:o:linux:linux_kernel:*:*:*:*:*:*:*:*"},
+					"constraint": "< 5.10.84 (unknown)",
+				},
+				Matcher: match.LinuxKernelMatcher,
+			},
+		},
+	})
+}
+
+func addBinaryMatches(t *testing.T, packages []pkg.Package, theStore *mockStore, theResult *match.Matches) {
+	binaryPackages := packagesByPath(packages, "/usr/local/bin/busybox")
+	if len(binaryPackages) != 1 {
+		t.Logf("Binary Packages: %+v", binaryPackages)
+		t.Fatalf("problem with grype cataloger (binary)")
+	}
+	thePkg := binaryPackages[0]
+	// note: classified binaries are only matched by the CPEs that are allowed for the classified component
+	theVuln := theStore.backend[grypeDB.NVDNamespace]["busybox"][0]
+	vulnObj, err := vulnerability.NewVulnerability(theVuln)
+	if err != nil {
+		t.Fatalf("failed to create vuln obj: %+v", err)
+	}
+	theResult.Add(match.Match{
+
+		Vulnerability: *vulnObj,
+		Package:       thePkg,
+		Details: []match.Detail{
+			{
+				Type:       match.CPEMatch,
+				Confidence: 0.9,
+				SearchedBy: map[string]interface{}{
+					"cpe": "cpe:2.3:a:busybox:busybox:1.31.1:*:*:*:*:*:*:*",
+				},
+				Found: map[string]interface{}{
+					"cpes":       []string{"cpe:2.3:a:busybox:busybox:*:*:*:*:*:*:*:*"},
+					"constraint": "< 1.34.0 (unknown)",
+				},
+				Matcher: match.BinaryMatcher,
+			},
+		},
+	})
+}
+
+func addHomebrewMatches(t *testing.T, packages []pkg.Package, theStore *mockStore, theResult *match.Matches) {
+	homebrewPackages := packagesByPath(packages, "/home/linuxbrew/.linuxbrew/Cellar/jq/1.6/INSTALL_RECEIPT.json")
+	if len(homebrewPackages) != 1 {
+		t.Logf("Homebrew Packages: %+v", homebrewPackages)
+		t.Fatalf("problem with grype cataloger (homebrew)")
+	}
+	thePkg := homebrewPackages[0]
+	theVuln := theStore.backend["osv:homebrew"][thePkg.Name][0]
+	vulnObj, err := vulnerability.NewVulnerability(theVuln)
+	if err != nil {
+		t.Fatalf("failed to create vuln obj: %+v", err)
+	}
+	theResult.Add(match.Match{
+
+		Vulnerability: *vulnObj,
+		Package:       thePkg,
+		Details: []match.Detail{
+			{
+				Type:       match.ExactDirectMatch,
+				Confidence: 1.0,
+				SearchedBy: map[string]interface{}{
+					"type": string(thePkg.Type),
+				},
+				Found: map[string]interface{}{
+					"constraint": "< 1.7 (unknown)",
+				},
+				Matcher: match.HomebrewMatcher,
+			},
+		},
+	})
+}
+
+func addChocolateyMatches(t *testing.T, packages []pkg.Package, theStore *mockStore, theResult *match.Matches) {
+	chocolateyPackages := packagesByPath(packages, "/ProgramData/chocolatey/lib/git.install/git.install.nuspec")
+	if len(chocolateyPackages) != 1 {
+		t.Logf("Chocolatey Packages: %+v", chocolateyPackages)
+		t.Fatalf("problem with grype cataloger (chocolatey)")
+	}
+	thePkg := chocolateyPackages[0]
+	// note: chocolatey packages are only matched by the CPEs of the upstream software they install
+	theVuln := theStore.backend[grypeDB.NVDNamespace]["git"][0]
+	vulnObj, err := vulnerability.NewVulnerability(theVuln)
+	if err != nil {
+		t.Fatalf("failed to create vuln obj: %+v", err)
+	}
+	theResult.Add(match.Match{
+
+		Vulnerability: *vulnObj,
+		Package:       thePkg,
+		Details: []match.Detail{
+			{
+				Type:       match.CPEMatch,
+				Confidence: 0.9,
+				SearchedBy: map[string]interface{}{
+					"cpe": "cpe:2.3:a:git:git:2.33.0:*:*:*:*:*:*:*",
+				},
+				Found: map[string]interface{}{
+					"cpes":       []string{"cpe:2.3:a:git:git:*:*:*:*:*:*:*:*"},
+					"constraint": "< 2.35.2 (unknown)",
+				},
+				Matcher: match.ChocolateyMatcher,
+			},
+		},
+	})
+}
+
+// packagesByPath returns the provided packages that were found at the given path.
+func packagesByPath(packages []pkg.Package, path string) []pkg.Package {
+	var result []pkg.Package
+	for _, p := range packages {
+		for _, l := range p.Locations {
+			if l.RealPath == path {
+				result = append(result, p)
+				break
+			}
+		}
+	}
+	return result
+}
+
 func TestMatchByImage(t *testing.T) {
 
 	observedMatchers := internal.NewStringSet()
@@ -308,23 +651,33 @@ func TestMatchByImage(t *testing.T) {
 
 	tests := []struct {
 		fixtureImage string
-		expectedFn   func(source.Source, *syftPkg.Catalog, *mockStore) match.Matches
+		expectedFn   func(source.Source, *syftPkg.Catalog, []pkg.Package, *mockStore) match.Matches
 	}{
 		{
 			fixtureImage: "image-debian-match-coverage",
-			expectedFn: func(theSource source.Source, catalog *syftPkg.Catalog, theStore *mockStore) match.Matches {
+			expectedFn: func(theSource source.Source, catalog *syftPkg.Catalog, packages []pkg.Package, theStore *mockStore) match.Matches {
 				expectedMatches := match.NewMatches()
 				addPythonMatches(t, theSource, catalog, theStore, &expectedMatches)
 				addRubyMatches(t, theSource, catalog, theStore, &expectedMatches)
 				addJavaMatches(t, theSource, catalog, theStore, &expectedMatches)
 				addDpkgMatches(t, theSource, catalog, theStore, &expectedMatches)
 				addJavascriptMatches(t, theSource, catalog, theStore, &expectedMatches)
+				addGolangMatches(t, packages, theStore, &expectedMatches)
+				addPhpMatches(t, theSource, catalog, theStore, &expectedMatches)
+				addCondaMatches(t, packages, theStore, &expectedMatches)
+				addRMatches(t, packages, theStore, &expectedMatches)
+				addHaskellMatches(t, packages, theStore, &expectedMatches)
+				addBitnamiMatches(t, packages, theStore, &expectedMatches)
+				addLinuxKernelMatches(t, packages, theStore, &expectedMatches)
+				addBinaryMatches(t, packages, theStore, &expectedMatches)
+				addHomebrewMatches(t, packages, theStore, &expectedMatches)
+				addChocolateyMatches(t, packages, theStore, &expectedMatches)
 				return expectedMatches
 			},
 		},
 		{
 			fixtureImage: "image-centos-match-coverage",
-			expectedFn: func(theSource source.Source, catalog *syftPkg.Catalog, theStore *mockStore) match.Matches {
+			expectedFn: func(theSource source.Source, catalog *syftPkg.Catalog, packages []pkg.Package, theStore *mockStore) match.Matches {
 				expectedMatches := match.NewMatches()
 				addRhelMatches(t, theSource, catalog, theStore, &expectedMatches)
 				return expectedMatches
@@ -332,7 +685,7 @@ func TestMatchByImage(t *testing.T) {
 		},
 		{
 			fixtureImage: "image-alpine-match-coverage",
-			expectedFn: func(theSource source.Source, catalog *syftPkg.Catalog, theStore *mockStore) match.Matches {
+			expectedFn: func(theSource source.Source, catalog *syftPkg.Catalog, packages []pkg.Package, theStore *mockStore) match.Matches {
 				expectedMatches := match.NewMatches()
 				addAlpineMatches(t, theSource, catalog, theStore, &expectedMatches)
 				return expectedMatches
@@ -340,7 +693,7 @@ func TestMatchByImage(t *testing.T) {
 		},
 		{
 			fixtureImage: "image-sles-match-coverage",
-			expectedFn: func(theSource source.Source, catalog *syftPkg.Catalog, theStore *mockStore) match.Matches {
+			expectedFn: func(theSource source.Source, catalog *syftPkg.Catalog, packages []pkg.Package, theStore *mockStore) match.Matches {
 				expectedMatches := match.NewMatches()
 				addSlesMatches(t, theSource, catalog, theStore, &expectedMatches)
 				return expectedMatches
@@ -368,19 +721,26 @@ func TestMatchByImage(t *testing.T) {
 			config := cataloger.DefaultConfig()
 			config.Search.Scope = source.SquashedScope
 
-			theCatalog, _, theDistro, err := syft.CatalogPackages(theSource, config)
+			theCatalog, _, _, err := syft.CatalogPackages(theSource, config)
 			if err != nil {
 				t.Fatalf("could not get the source obj: %+v", err)
 			}
 
+			// the packages are provided as when scanning the image, which includes the packages that grype derives
+			// from the syft catalog (e.g. the go toolchain of go binaries)
+			thePackages, theContext, err := pkg.Provide(userImage, pkg.ProviderConfig{CatalogingOptions: config})
+			if err != nil {
+				t.Fatalf("could not provide packages: %+v", err)
+			}
+
 			actualResults := grype.FindVulnerabilitiesForPackage(
 				db.NewVulnerabilityProvider(theStore),
-				theDistro,
-				pkg.FromCatalog(theCatalog)...,
+				theContext.Distro,
+				thePackages...,
 			)
 
 			// build expected matches from what's discovered from the catalog
-			expectedMatches := test.expectedFn(*theSource, theCatalog, theStore)
+			expectedMatches := test.expectedFn(*theSource, theCatalog, thePackages, theStore)
 
 			// build expected match set...
 			expectedMatchSet := map[string]string{}
@@ -421,6 +781,13 @@ func TestMatchByImage(t *testing.T) {
 	observedMatchers.Remove(string(match.UnknownMatcherType))
 	definedMatchers.Remove(string(match.UnknownMatcherType))
 	definedMatchers.Remove(string(match.MsrcMatcher))
+	// syft does not catalog packages of these ecosystems within images, so these matchers are covered by SBOMs that
+	// describe such packages instead (see TestMatchBySBOMDocument)
+	definedMatchers.Remove(string(match.RustMatcher))
+	definedMatchers.Remove(string(match.HexMatcher))
+	definedMatchers.Remove(string(match.SwiftMatcher))
+	definedMatchers.Remove(string(match.DartPubMatcher))
+	definedMatchers.Remove(string(match.DotnetMatcher))
 
 	if len(observedMatchers) != len(definedMatchers) {
 		t.Errorf("matcher coverage incomplete (matchers=%d, coverage=%d)", len(definedMatchers), len(observedMatchers))
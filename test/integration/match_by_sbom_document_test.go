@@ -86,6 +86,101 @@ func TestMatchBySBOMDocument(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "rust crate",
+			fixture:     "test-fixtures/sbom/syft-sbom-with-rust-packages.json",
+			expectedIDs: []string{"CVE-rust-hyper"},
+			expectedDetails: []match.Detail{
+				{
+					Type: match.ExactDirectMatch,
+					SearchedBy: map[string]interface{}{
+						"language":  "rust",
+						"namespace": "github:rust",
+					},
+					Found: map[string]interface{}{
+						"versionConstraint": "^0.14.0, < 0.14.10 (semver range)",
+					},
+					Matcher:    match.RustMatcher,
+					Confidence: 1,
+				},
+			},
+		},
+		{
+			name:        "nuget package",
+			fixture:     "test-fixtures/sbom/syft-sbom-with-dotnet-packages.json",
+			expectedIDs: []string{"CVE-dotnet-newtonsoft-json"},
+			expectedDetails: []match.Detail{
+				{
+					Type: match.ExactDirectMatch,
+					SearchedBy: map[string]interface{}{
+						"language":  "dotnet",
+						"namespace": "github:nuget",
+					},
+					Found: map[string]interface{}{
+						"versionConstraint": "< 13.0.1 (nuget)",
+					},
+					Matcher:    match.DotnetMatcher,
+					Confidence: 1,
+				},
+			},
+		},
+		{
+			name:        "pub package",
+			fixture:     "test-fixtures/sbom/syft-sbom-with-dart-packages.json",
+			expectedIDs: []string{"CVE-dart-http"},
+			expectedDetails: []match.Detail{
+				{
+					Type: match.ExactDirectMatch,
+					SearchedBy: map[string]interface{}{
+						"language":  "dart",
+						"namespace": "github:pub",
+					},
+					Found: map[string]interface{}{
+						"versionConstraint": "^0.13.0, < 0.13.3 (semver range)",
+					},
+					Matcher:    match.DartPubMatcher,
+					Confidence: 1,
+				},
+			},
+		},
+		{
+			name:        "swift package",
+			fixture:     "test-fixtures/sbom/syft-sbom-with-swift-packages.json",
+			expectedIDs: []string{"CVE-swift-nio"},
+			expectedDetails: []match.Detail{
+				{
+					Type: match.ExactDirectMatch,
+					SearchedBy: map[string]interface{}{
+						"language":  "swift",
+						"namespace": "github:swift",
+					},
+					Found: map[string]interface{}{
+						"versionConstraint": "< 2.29.1 (semver)",
+					},
+					Matcher:    match.SwiftMatcher,
+					Confidence: 1,
+				},
+			},
+		},
+		{
+			name:        "hex package",
+			fixture:     "test-fixtures/sbom/syft-sbom-with-hex-packages.json",
+			expectedIDs: []string{"CVE-hex-phoenix"},
+			expectedDetails: []match.Detail{
+				{
+					Type: match.ExactDirectMatch,
+					SearchedBy: map[string]interface{}{
+						"language":  "elixir",
+						"namespace": "github:erlang",
+					},
+					Found: map[string]interface{}{
+						"versionConstraint": "< 1.6.14 (hex)",
+					},
+					Matcher:    match.HexMatcher,
+					Confidence: 1,
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {